@@ -320,6 +320,9 @@ type AllocationMetric struct {
 	AllocationTime    time.Duration
 	CoalescedFailures int
 	ScoreMetaData     []*NodeScoreMeta
+	GangDesired       int
+	GangPlaced        int
+	NodeExplanations  []*NodeExplanation
 }
 
 // NodeScoreMeta is used to serialize node scoring metadata
@@ -330,6 +333,18 @@ type NodeScoreMeta struct {
 	NormScore float64
 }
 
+// NodeExplanation describes how a node was filtered, exhausted, or scored
+// for a placement. It's only returned by explained job plans.
+type NodeExplanation struct {
+	NodeID             string
+	NodeName           string
+	FilteredBy         string
+	FilterReason       string
+	ExhaustedDimension string
+	Scores             map[string]float64
+	NormScore          float64
+}
+
 // Stub returns a list stub for the allocation
 func (a *Allocation) Stub() *AllocationListStub {
 	stub := &AllocationListStub{
@@ -426,6 +441,7 @@ type AllocDeploymentStatus struct {
 	Healthy     *bool
 	Timestamp   time.Time
 	Canary      bool
+	Retained    bool
 	ModifyIndex uint64
 }
 
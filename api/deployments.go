@@ -211,6 +211,55 @@ type DeploymentState struct {
 	PlacedAllocs      int
 	HealthyAllocs     int
 	UnhealthyAllocs   int
+	CanaryAnalysis    *CanaryAnalysisResult
+	BlueGreen         bool
+	BlueRetention     time.Duration
+	RetainBlueUntil   time.Time
+	PreDeploy         *DeploymentHookState
+	PostDeploy        *DeploymentHookState
+}
+
+const (
+	DeploymentHookStatusPending  = "pending"
+	DeploymentHookStatusRunning  = "running"
+	DeploymentHookStatusComplete = "complete"
+	DeploymentHookStatusFailed   = "failed"
+)
+
+// DeploymentHookState tracks a hook of the deployment of a task group.
+type DeploymentHookState struct {
+	Status            string
+	StatusDescription string
+	Job               string
+	Group             string
+	DispatchedJobID   string
+}
+
+const (
+	CanaryAnalysisStatusRunning = "running"
+	CanaryAnalysisStatusPassed  = "passed"
+	CanaryAnalysisStatusFailed  = "failed"
+)
+
+// CanaryAnalysisResult is the result of the canary analysis of a task group
+// in a deployment.
+type CanaryAnalysisResult struct {
+	Status            string
+	StatusDescription string
+	StartedAt         time.Time
+	Samples           int
+	Signals           []*CanaryAnalysisSignal
+}
+
+// CanaryAnalysisSignal is the comparison of a signal between the canaries and
+// the stable allocations of a task group.
+type CanaryAnalysisSignal struct {
+	Name      string
+	Canary    float64
+	Stable    float64
+	Threshold float64
+	Samples   int
+	Failed    bool
 }
 
 // DeploymentIndexSort is a wrapper to sort deployments by CreateIndex. We
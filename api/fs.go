@@ -259,6 +259,30 @@ func queryClientNode(c *Client, alloc *Allocation, reqPath string, q *QueryOptio
 // long pauses on this API call.
 func (a *AllocFS) Logs(alloc *Allocation, follow bool, task, logType, origin string,
 	offset int64, cancel <-chan struct{}, q *QueryOptions) (<-chan *StreamFrame, <-chan error) {
+	return a.FilteredLogs(alloc, follow, task, logType, origin, offset, nil, cancel, q)
+}
+
+// LogsFilter filters the lines of the logs streamed by AllocFS.FilteredLogs.
+// The lines are filtered by the client running the allocation.
+type LogsFilter struct {
+	// Since and Until only stream the lines timestamped within the time
+	// range, if set. Lines are only timestamped if the timestamps option of
+	// the task logs is enabled, and lines without timestamps are never
+	// filtered out by time.
+	Since time.Time
+	Until time.Time
+
+	// Grep only streams the lines containing the substring, or matching the
+	// regular expression if Regex is true.
+	Grep  string
+	Regex bool
+}
+
+// FilteredLogs streams the lines of a tasks logs matching the filter. The
+// parameters are the same as the parameters of Logs, and a nil filter streams
+// all the lines.
+func (a *AllocFS) FilteredLogs(alloc *Allocation, follow bool, task, logType, origin string,
+	offset int64, filter *LogsFilter, cancel <-chan struct{}, q *QueryOptions) (<-chan *StreamFrame, <-chan error) {
 
 	errCh := make(chan error, 1)
 
@@ -270,6 +294,19 @@ func (a *AllocFS) Logs(alloc *Allocation, follow bool, task, logType, origin str
 			q.Params["type"] = logType
 			q.Params["origin"] = origin
 			q.Params["offset"] = strconv.FormatInt(offset, 10)
+			if filter == nil {
+				return
+			}
+			if !filter.Since.IsZero() {
+				q.Params["since"] = filter.Since.Format(time.RFC3339Nano)
+			}
+			if !filter.Until.IsZero() {
+				q.Params["until"] = filter.Until.Format(time.RFC3339Nano)
+			}
+			if filter.Grep != "" {
+				q.Params["grep"] = filter.Grep
+				q.Params["regex"] = strconv.FormatBool(filter.Regex)
+			}
 		})
 	if err != nil {
 		errCh <- err
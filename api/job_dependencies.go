@@ -0,0 +1,50 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+const (
+	// JobDependencyConditionComplete is satisfied once the upstream job is
+	// dead, regardless of the outcome of its allocations.
+	JobDependencyConditionComplete = "complete"
+
+	// JobDependencyConditionSuccessful is satisfied once the upstream job is
+	// dead and none of its allocations failed or were lost.
+	JobDependencyConditionSuccessful = "successful"
+
+	// JobDependencyConditionFailed is satisfied once the upstream job is
+	// dead and at least one of its allocations failed or was lost, or the
+	// job was stopped.
+	JobDependencyConditionFailed = "failed"
+)
+
+const (
+	JobDependencyStatusPending       = "pending"
+	JobDependencyStatusSatisfied     = "satisfied"
+	JobDependencyStatusUnsatisfiable = "unsatisfiable"
+	JobDependencyStatusMissing       = "missing"
+)
+
+// JobDependency prevents a batch job from being placed until another job in
+// the same namespace reaches a given state.
+type JobDependency struct {
+	JobID     *string `hcl:"job_id,label"`
+	Condition *string `hcl:"condition,optional"`
+}
+
+// JobDependencyState is the state of a single edge of the job dependency
+// graph.
+type JobDependencyState struct {
+	JobID     string
+	Condition string
+	JobStatus string
+	Status    string
+}
+
+// JobDependencies is the dependency graph around a single job.
+type JobDependencies struct {
+	JobID        string
+	Namespace    string
+	Dependencies []*JobDependencyState
+	Dependents   []*JobDependencyState
+}
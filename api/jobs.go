@@ -488,6 +488,10 @@ func (j *Jobs) PeriodicForce(jobID string, q *WriteOptions) (string, *WriteMeta,
 type PlanOptions struct {
 	Diff           bool
 	PolicyOverride bool
+
+	// Explain records how each node was filtered and scored for the
+	// placements of the job.
+	Explain bool
 }
 
 func (j *Jobs) Plan(job *Job, diff bool, q *WriteOptions) (*JobPlanResponse, *WriteMeta, error) {
@@ -510,6 +514,7 @@ func (j *Jobs) PlanOpts(job *Job, opts *PlanOptions, q *WriteOptions) (*JobPlanR
 	if opts != nil {
 		req.Diff = opts.Diff
 		req.PolicyOverride = opts.PolicyOverride
+		req.Explain = opts.Explain
 	}
 
 	var resp JobPlanResponse
@@ -529,6 +534,17 @@ func (j *Jobs) Summary(jobID string, q *QueryOptions) (*JobSummary, *QueryMeta,
 	return &resp, qm, nil
 }
 
+// Dependencies is used to retrieve the status of the dependencies of a job,
+// along with the jobs that depend on it.
+func (j *Jobs) Dependencies(jobID string, q *QueryOptions) (*JobDependencies, *QueryMeta, error) {
+	var resp JobDependencies
+	qm, err := j.client.query("/v1/job/"+url.PathEscape(jobID)+"/dependencies", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
 func (j *Jobs) Dispatch(jobID string, meta map[string]string,
 	payload []byte, idPrefixTemplate string, q *WriteOptions) (*JobDispatchResponse, *WriteMeta, error) {
 	var resp JobDispatchResponse
@@ -596,15 +612,132 @@ type periodicForceResponse struct {
 
 // UpdateStrategy defines a task groups update strategy.
 type UpdateStrategy struct {
-	Stagger          *time.Duration `mapstructure:"stagger" hcl:"stagger,optional"`
-	MaxParallel      *int           `mapstructure:"max_parallel" hcl:"max_parallel,optional"`
-	HealthCheck      *string        `mapstructure:"health_check" hcl:"health_check,optional"`
-	MinHealthyTime   *time.Duration `mapstructure:"min_healthy_time" hcl:"min_healthy_time,optional"`
-	HealthyDeadline  *time.Duration `mapstructure:"healthy_deadline" hcl:"healthy_deadline,optional"`
-	ProgressDeadline *time.Duration `mapstructure:"progress_deadline" hcl:"progress_deadline,optional"`
-	Canary           *int           `mapstructure:"canary" hcl:"canary,optional"`
-	AutoRevert       *bool          `mapstructure:"auto_revert" hcl:"auto_revert,optional"`
-	AutoPromote      *bool          `mapstructure:"auto_promote" hcl:"auto_promote,optional"`
+	Stagger          *time.Duration  `mapstructure:"stagger" hcl:"stagger,optional"`
+	MaxParallel      *int            `mapstructure:"max_parallel" hcl:"max_parallel,optional"`
+	HealthCheck      *string         `mapstructure:"health_check" hcl:"health_check,optional"`
+	MinHealthyTime   *time.Duration  `mapstructure:"min_healthy_time" hcl:"min_healthy_time,optional"`
+	HealthyDeadline  *time.Duration  `mapstructure:"healthy_deadline" hcl:"healthy_deadline,optional"`
+	ProgressDeadline *time.Duration  `mapstructure:"progress_deadline" hcl:"progress_deadline,optional"`
+	Canary           *int            `mapstructure:"canary" hcl:"canary,optional"`
+	AutoRevert       *bool           `mapstructure:"auto_revert" hcl:"auto_revert,optional"`
+	AutoPromote      *bool           `mapstructure:"auto_promote" hcl:"auto_promote,optional"`
+	Analysis         *CanaryAnalysis `mapstructure:"analysis" hcl:"analysis,block"`
+	BlueGreen        *BlueGreen      `mapstructure:"blue_green" hcl:"blue_green,block"`
+	PreDeploy        *DeploymentHook `mapstructure:"pre_deploy" hcl:"pre_deploy,block"`
+	PostDeploy       *DeploymentHook `mapstructure:"post_deploy" hcl:"post_deploy,block"`
+}
+
+// DeploymentHook defines a parameterized job dispatched, or a task group of
+// the job run as a batch job, during the deployment of a task group.
+type DeploymentHook struct {
+	Job   *string           `mapstructure:"job" hcl:"job,optional"`
+	Group *string           `mapstructure:"group" hcl:"group,optional"`
+	Meta  map[string]string `mapstructure:"meta" hcl:"meta,block"`
+}
+
+func (h *DeploymentHook) Copy() *DeploymentHook {
+	if h == nil {
+		return nil
+	}
+	nh := new(DeploymentHook)
+	if h.Job != nil {
+		nh.Job = pointerOf(*h.Job)
+	}
+	if h.Group != nil {
+		nh.Group = pointerOf(*h.Group)
+	}
+	nh.Meta = maps.Clone(h.Meta)
+	return nh
+}
+
+// BlueGreen defines a blue/green deployment of a task group. The green
+// allocations are placed next to the blue ones and the blue allocations are
+// kept for the retention once the deployment is promoted.
+type BlueGreen struct {
+	Retention *time.Duration `mapstructure:"retention" hcl:"retention,optional"`
+}
+
+func (b *BlueGreen) Copy() *BlueGreen {
+	if b == nil {
+		return nil
+	}
+	nb := new(BlueGreen)
+	if b.Retention != nil {
+		nb.Retention = pointerOf(*b.Retention)
+	}
+	return nb
+}
+
+func (b *BlueGreen) Canonicalize() {
+	if b.Retention == nil {
+		b.Retention = pointerOf(10 * time.Minute)
+	}
+}
+
+// CanaryAnalysis defines how the canaries of a deployment are compared with
+// the stable allocations of the task group before they're promoted.
+type CanaryAnalysis struct {
+	Window              *time.Duration `mapstructure:"window" hcl:"window,optional"`
+	Interval            *time.Duration `mapstructure:"interval" hcl:"interval,optional"`
+	MaxCheckFailureRate *float64       `mapstructure:"max_check_failure_rate" hcl:"max_check_failure_rate,optional"`
+	MaxRestarts         *int           `mapstructure:"max_restarts" hcl:"max_restarts,optional"`
+	MaxOOMKills         *int           `mapstructure:"max_oom_kills" hcl:"max_oom_kills,optional"`
+	MaxCPUIncrease      *int           `mapstructure:"max_cpu_increase" hcl:"max_cpu_increase,optional"`
+	MaxMemoryIncrease   *int           `mapstructure:"max_memory_increase" hcl:"max_memory_increase,optional"`
+}
+
+func (a *CanaryAnalysis) Copy() *CanaryAnalysis {
+	if a == nil {
+		return nil
+	}
+	na := new(CanaryAnalysis)
+	*na = *a
+	if a.Window != nil {
+		na.Window = pointerOf(*a.Window)
+	}
+	if a.Interval != nil {
+		na.Interval = pointerOf(*a.Interval)
+	}
+	if a.MaxCheckFailureRate != nil {
+		na.MaxCheckFailureRate = pointerOf(*a.MaxCheckFailureRate)
+	}
+	if a.MaxRestarts != nil {
+		na.MaxRestarts = pointerOf(*a.MaxRestarts)
+	}
+	if a.MaxOOMKills != nil {
+		na.MaxOOMKills = pointerOf(*a.MaxOOMKills)
+	}
+	if a.MaxCPUIncrease != nil {
+		na.MaxCPUIncrease = pointerOf(*a.MaxCPUIncrease)
+	}
+	if a.MaxMemoryIncrease != nil {
+		na.MaxMemoryIncrease = pointerOf(*a.MaxMemoryIncrease)
+	}
+	return na
+}
+
+func (a *CanaryAnalysis) Canonicalize() {
+	if a.Window == nil {
+		a.Window = pointerOf(5 * time.Minute)
+	}
+	if a.Interval == nil {
+		a.Interval = pointerOf(30 * time.Second)
+	}
+	if a.MaxCheckFailureRate == nil {
+		a.MaxCheckFailureRate = pointerOf(0.0)
+	}
+	if a.MaxRestarts == nil {
+		a.MaxRestarts = pointerOf(0)
+	}
+	if a.MaxOOMKills == nil {
+		a.MaxOOMKills = pointerOf(0)
+	}
+	if a.MaxCPUIncrease == nil {
+		a.MaxCPUIncrease = pointerOf(0)
+	}
+	if a.MaxMemoryIncrease == nil {
+		a.MaxMemoryIncrease = pointerOf(0)
+	}
 }
 
 // DefaultUpdateStrategy provides a baseline that can be used to upgrade
@@ -666,6 +799,11 @@ func (u *UpdateStrategy) Copy() *UpdateStrategy {
 		copy.AutoPromote = pointerOf(*u.AutoPromote)
 	}
 
+	copy.Analysis = u.Analysis.Copy()
+	copy.BlueGreen = u.BlueGreen.Copy()
+	copy.PreDeploy = u.PreDeploy.Copy()
+	copy.PostDeploy = u.PostDeploy.Copy()
+
 	return copy
 }
 
@@ -709,6 +847,22 @@ func (u *UpdateStrategy) Merge(o *UpdateStrategy) {
 	if o.AutoPromote != nil {
 		u.AutoPromote = pointerOf(*o.AutoPromote)
 	}
+
+	if o.Analysis != nil {
+		u.Analysis = o.Analysis.Copy()
+	}
+
+	if o.BlueGreen != nil {
+		u.BlueGreen = o.BlueGreen.Copy()
+	}
+
+	if o.PreDeploy != nil {
+		u.PreDeploy = o.PreDeploy.Copy()
+	}
+
+	if o.PostDeploy != nil {
+		u.PostDeploy = o.PostDeploy.Copy()
+	}
 }
 
 func (u *UpdateStrategy) Canonicalize() {
@@ -749,6 +903,14 @@ func (u *UpdateStrategy) Canonicalize() {
 	if u.AutoPromote == nil {
 		u.AutoPromote = d.AutoPromote
 	}
+
+	if u.Analysis != nil {
+		u.Analysis.Canonicalize()
+	}
+
+	if u.BlueGreen != nil {
+		u.BlueGreen.Canonicalize()
+	}
 }
 
 // Empty returns whether the UpdateStrategy is empty or has user defined values.
@@ -793,6 +955,18 @@ func (u *UpdateStrategy) Empty() bool {
 		return false
 	}
 
+	if u.Analysis != nil {
+		return false
+	}
+
+	if u.BlueGreen != nil {
+		return false
+	}
+
+	if u.PreDeploy != nil || u.PostDeploy != nil {
+		return false
+	}
+
 	return true
 }
 
@@ -875,8 +1049,12 @@ type PeriodicConfig struct {
 	Spec            *string  `hcl:"cron,optional"`
 	Specs           []string `hcl:"crons,optional"`
 	SpecType        *string
-	ProhibitOverlap *bool   `mapstructure:"prohibit_overlap" hcl:"prohibit_overlap,optional"`
-	TimeZone        *string `mapstructure:"time_zone" hcl:"time_zone,optional"`
+	ProhibitOverlap *bool          `mapstructure:"prohibit_overlap" hcl:"prohibit_overlap,optional"`
+	TimeZone        *string        `mapstructure:"time_zone" hcl:"time_zone,optional"`
+	Concurrency     *string        `hcl:"concurrency,optional"`
+	Catchup         *string        `mapstructure:"catchup" hcl:"catchup,optional"`
+	CatchupMaxCount *int           `mapstructure:"catchup_max_count" hcl:"catchup_max_count,optional"`
+	CatchupMaxAge   *time.Duration `mapstructure:"catchup_max_age" hcl:"catchup_max_age,optional"`
 }
 
 func (p *PeriodicConfig) Canonicalize() {
@@ -954,9 +1132,20 @@ func (p *PeriodicConfig) GetLocation() (*time.Location, error) {
 
 // ParameterizedJobConfig is used to configure the parameterized job.
 type ParameterizedJobConfig struct {
-	Payload      string   `hcl:"payload,optional"`
-	MetaRequired []string `mapstructure:"meta_required" hcl:"meta_required,optional"`
-	MetaOptional []string `mapstructure:"meta_optional" hcl:"meta_optional,optional"`
+	Payload       string               `hcl:"payload,optional"`
+	MetaRequired  []string             `mapstructure:"meta_required" hcl:"meta_required,optional"`
+	MetaOptional  []string             `mapstructure:"meta_optional" hcl:"meta_optional,optional"`
+	Concurrency   string               `hcl:"concurrency,optional"`
+	MaxConcurrent int                  `mapstructure:"max_concurrent" hcl:"max_concurrent,optional"`
+	DispatchQueue *DispatchQueueConfig `mapstructure:"queue" hcl:"queue,block"`
+}
+
+// DispatchQueueConfig limits how the dispatched jobs of a parameterized job
+// are evaluated.
+type DispatchQueueConfig struct {
+	MaxRunning int     `mapstructure:"max_running" hcl:"max_running,optional"`
+	Rate       float64 `hcl:"rate,optional"`
+	Burst      int     `hcl:"burst,optional"`
 }
 
 // JobSubmission is used to hold information about the original content of a job
@@ -1086,6 +1275,7 @@ type Job struct {
 	Name             *string                 `hcl:"name,optional"`
 	Type             *string                 `hcl:"type,optional"`
 	Priority         *int                    `hcl:"priority,optional"`
+	PriorityClass    *string                 `mapstructure:"priority_class" hcl:"priority_class,optional"`
 	AllAtOnce        *bool                   `mapstructure:"all_at_once" hcl:"all_at_once,optional"`
 	Datacenters      []string                `hcl:"datacenters,optional"`
 	NodePool         *string                 `mapstructure:"node_pool" hcl:"node_pool,optional"`
@@ -1095,8 +1285,12 @@ type Job struct {
 	Update           *UpdateStrategy         `hcl:"update,block"`
 	Multiregion      *Multiregion            `hcl:"multiregion,block"`
 	Spreads          []*Spread               `hcl:"spread,block"`
+	TopologySpreads  []*TopologySpread       `hcl:"topology_spread,block"`
+	JobAffinities    []*JobAffinity          `hcl:"job_affinity,block"`
 	Periodic         *PeriodicConfig         `hcl:"periodic,block"`
 	ParameterizedJob *ParameterizedJobConfig `hcl:"parameterized,block"`
+	DependsOn        []*JobDependency        `mapstructure:"depends_on" hcl:"depends_on,block"`
+	Deadline         *time.Duration          `hcl:"deadline,optional"`
 	Reschedule       *ReschedulePolicy       `hcl:"reschedule,block"`
 	Migrate          *MigrateStrategy        `hcl:"migrate,block"`
 	Meta             map[string]string       `hcl:"meta,block"`
@@ -1154,6 +1348,9 @@ func (j *Job) Canonicalize() {
 	if j.Priority == nil {
 		j.Priority = pointerOf(JobDefaultPriority)
 	}
+	if j.PriorityClass == nil {
+		j.PriorityClass = pointerOf("")
+	}
 	if j.Stop == nil {
 		j.Stop = pointerOf(false)
 	}
@@ -1218,6 +1415,12 @@ func (j *Job) Canonicalize() {
 	for _, spread := range j.Spreads {
 		spread.Canonicalize()
 	}
+	for _, spread := range j.TopologySpreads {
+		spread.Canonicalize()
+	}
+	for _, a := range j.JobAffinities {
+		a.Canonicalize()
+	}
 	for _, a := range j.Affinities {
 		a.Canonicalize()
 	}
@@ -1243,6 +1446,7 @@ type JobSummary struct {
 	Namespace string
 	Summary   map[string]TaskGroupSummary
 	Children  *JobChildrenSummary
+	Deadline  int64
 
 	// Raft Indexes
 	CreateIndex uint64
@@ -1251,6 +1455,7 @@ type JobSummary struct {
 
 // JobChildrenSummary contains the summary of children job status
 type JobChildrenSummary struct {
+	Queued  int64
 	Pending int64
 	Running int64
 	Dead    int64
@@ -1261,7 +1466,7 @@ func (jc *JobChildrenSummary) Sum() int {
 		return 0
 	}
 
-	return int(jc.Pending + jc.Running + jc.Dead)
+	return int(jc.Queued + jc.Pending + jc.Running + jc.Dead)
 }
 
 // TaskGroup summarizes the state of all the allocations of a particular
@@ -1274,6 +1479,9 @@ type TaskGroupSummary struct {
 	Starting int
 	Lost     int
 	Unknown  int
+
+	CompletedIndexes int
+	FailedIndexes    int
 }
 
 // JobListStub is used to return a subset of information about
@@ -1397,6 +1605,16 @@ func (j *Job) AddSpread(s *Spread) *Job {
 	return j
 }
 
+func (j *Job) AddTopologySpread(s *TopologySpread) *Job {
+	j.TopologySpreads = append(j.TopologySpreads, s)
+	return j
+}
+
+func (j *Job) AddJobAffinity(a *JobAffinity) *Job {
+	j.JobAffinities = append(j.JobAffinities, a)
+	return j
+}
+
 type WriteRequest struct {
 	// The target region for this write
 	Region string
@@ -1495,6 +1713,7 @@ type JobPlanRequest struct {
 	Job            *Job
 	Diff           bool
 	PolicyOverride bool
+	Explain        bool
 	WriteRequest
 }
 
@@ -1506,6 +1725,10 @@ type JobPlanResponse struct {
 	FailedTGAllocs     map[string]*AllocationMetric
 	NextPeriodicLaunch time.Time
 
+	// PlacementMetrics is the metrics of the placements of the plan, keyed
+	// by allocation name. It's only set when the plan is explained.
+	PlacementMetrics map[string]*AllocationMetric
+
 	// Warnings contains any warnings about the given job. These may include
 	// deprecation warnings.
 	Warnings string
@@ -1578,6 +1801,7 @@ type JobDispatchResponse struct {
 	EvalID          string
 	EvalCreateIndex uint64
 	JobCreateIndex  uint64
+	Queued          bool
 	WriteMeta
 }
 
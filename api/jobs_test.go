@@ -285,6 +285,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 				ParentID:          pointerOf(""),
 				Priority:          pointerOf(JobDefaultPriority),
 				NodePool:          pointerOf(""),
+				PriorityClass:     pointerOf(""),
 				AllAtOnce:         pointerOf(false),
 				ConsulNamespace:   pointerOf(""),
 				VaultNamespace:    pointerOf(""),
@@ -323,6 +324,9 @@ func TestJobs_Canonicalize(t *testing.T) {
 							Interval:        pointerOf(30 * time.Minute),
 							Mode:            pointerOf("fail"),
 							RenderTemplates: pointerOf(false),
+							DelayFunction:   pointerOf("constant"),
+							MaxDelay:        pointerOf(time.Duration(0)),
+							Jitter:          pointerOf(0.25),
 						},
 						ReschedulePolicy: &ReschedulePolicy{
 							Attempts:      pointerOf(0),
@@ -377,6 +381,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 				ParentID:          pointerOf(""),
 				Priority:          pointerOf(JobDefaultPriority),
 				NodePool:          pointerOf(""),
+				PriorityClass:     pointerOf(""),
 				AllAtOnce:         pointerOf(false),
 				ConsulNamespace:   pointerOf(""),
 				VaultNamespace:    pointerOf(""),
@@ -404,6 +409,9 @@ func TestJobs_Canonicalize(t *testing.T) {
 							Interval:        pointerOf(24 * time.Hour),
 							Mode:            pointerOf("fail"),
 							RenderTemplates: pointerOf(false),
+							DelayFunction:   pointerOf("constant"),
+							MaxDelay:        pointerOf(time.Duration(0)),
+							Jitter:          pointerOf(0.25),
 						},
 						ReschedulePolicy: &ReschedulePolicy{
 							Attempts:      pointerOf(1),
@@ -452,6 +460,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 				ParentID:          pointerOf("lol"),
 				Priority:          pointerOf(JobDefaultPriority),
 				NodePool:          pointerOf(""),
+				PriorityClass:     pointerOf(""),
 				AllAtOnce:         pointerOf(false),
 				ConsulNamespace:   pointerOf(""),
 				VaultNamespace:    pointerOf(""),
@@ -490,6 +499,9 @@ func TestJobs_Canonicalize(t *testing.T) {
 							Interval:        pointerOf(30 * time.Minute),
 							Mode:            pointerOf("fail"),
 							RenderTemplates: pointerOf(false),
+							DelayFunction:   pointerOf("constant"),
+							MaxDelay:        pointerOf(time.Duration(0)),
+							Jitter:          pointerOf(0.25),
 						},
 						ReschedulePolicy: &ReschedulePolicy{
 							Attempts:      pointerOf(0),
@@ -618,6 +630,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 				ParentID:          pointerOf(""),
 				Priority:          pointerOf(JobDefaultPriority),
 				NodePool:          pointerOf(""),
+				PriorityClass:     pointerOf(""),
 				Region:            pointerOf("global"),
 				Type:              pointerOf("service"),
 				AllAtOnce:         pointerOf(false),
@@ -654,6 +667,9 @@ func TestJobs_Canonicalize(t *testing.T) {
 							Delay:           pointerOf(25 * time.Second),
 							Mode:            pointerOf("delay"),
 							RenderTemplates: pointerOf(false),
+							DelayFunction:   pointerOf("constant"),
+							MaxDelay:        pointerOf(time.Duration(0)),
+							Jitter:          pointerOf(0.25),
 						},
 						ReschedulePolicy: &ReschedulePolicy{
 							Attempts:      pointerOf(0),
@@ -696,6 +712,9 @@ func TestJobs_Canonicalize(t *testing.T) {
 									Delay:           pointerOf(25 * time.Second),
 									Mode:            pointerOf("delay"),
 									RenderTemplates: pointerOf(false),
+									DelayFunction:   pointerOf("constant"),
+									MaxDelay:        pointerOf(time.Duration(0)),
+									Jitter:          pointerOf(0.25),
 								},
 								Resources: &Resources{
 									CPU:      pointerOf(500),
@@ -786,6 +805,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 				Type:              pointerOf("service"),
 				Priority:          pointerOf(JobDefaultPriority),
 				NodePool:          pointerOf(""),
+				PriorityClass:     pointerOf(""),
 				AllAtOnce:         pointerOf(false),
 				ConsulNamespace:   pointerOf(""),
 				VaultNamespace:    pointerOf(""),
@@ -876,6 +896,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 				ParentID:          pointerOf("lol"),
 				Priority:          pointerOf(JobDefaultPriority),
 				NodePool:          pointerOf(""),
+				PriorityClass:     pointerOf(""),
 				AllAtOnce:         pointerOf(false),
 				ConsulNamespace:   pointerOf(""),
 				VaultNamespace:    pointerOf(""),
@@ -914,6 +935,9 @@ func TestJobs_Canonicalize(t *testing.T) {
 							Interval:        pointerOf(30 * time.Minute),
 							Mode:            pointerOf("fail"),
 							RenderTemplates: pointerOf(false),
+							DelayFunction:   pointerOf("constant"),
+							MaxDelay:        pointerOf(time.Duration(0)),
+							Jitter:          pointerOf(0.25),
 						},
 						ReschedulePolicy: &ReschedulePolicy{
 							Attempts:      pointerOf(0),
@@ -963,6 +987,9 @@ func TestJobs_Canonicalize(t *testing.T) {
 							Interval:        pointerOf(30 * time.Minute),
 							Mode:            pointerOf("fail"),
 							RenderTemplates: pointerOf(false),
+							DelayFunction:   pointerOf("constant"),
+							MaxDelay:        pointerOf(time.Duration(0)),
+							Jitter:          pointerOf(0.25),
 						},
 						ReschedulePolicy: &ReschedulePolicy{
 							Attempts:      pointerOf(0),
@@ -1050,6 +1077,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 				Type:              pointerOf("service"),
 				ParentID:          pointerOf("lol"),
 				NodePool:          pointerOf(""),
+				PriorityClass:     pointerOf(""),
 				Priority:          pointerOf(JobDefaultPriority),
 				AllAtOnce:         pointerOf(false),
 				ConsulNamespace:   pointerOf(""),
@@ -1089,6 +1117,9 @@ func TestJobs_Canonicalize(t *testing.T) {
 							Interval:        pointerOf(30 * time.Minute),
 							Mode:            pointerOf("fail"),
 							RenderTemplates: pointerOf(false),
+							DelayFunction:   pointerOf("constant"),
+							MaxDelay:        pointerOf(time.Duration(0)),
+							Jitter:          pointerOf(0.25),
 						},
 						ReschedulePolicy: &ReschedulePolicy{
 							Attempts:      pointerOf(0),
@@ -1122,6 +1153,9 @@ func TestJobs_Canonicalize(t *testing.T) {
 									Interval:        pointerOf(30 * time.Minute),
 									Mode:            pointerOf("fail"),
 									RenderTemplates: pointerOf(true),
+									DelayFunction:   pointerOf("constant"),
+									MaxDelay:        pointerOf(time.Duration(0)),
+									Jitter:          pointerOf(0.25),
 								},
 							},
 						},
@@ -1140,6 +1174,9 @@ func TestJobs_Canonicalize(t *testing.T) {
 							Interval:        pointerOf(30 * time.Minute),
 							Mode:            pointerOf("fail"),
 							RenderTemplates: pointerOf(false),
+							DelayFunction:   pointerOf("constant"),
+							MaxDelay:        pointerOf(time.Duration(0)),
+							Jitter:          pointerOf(0.25),
 						},
 						ReschedulePolicy: &ReschedulePolicy{
 							Attempts:      pointerOf(0),
@@ -1177,6 +1214,9 @@ func TestJobs_Canonicalize(t *testing.T) {
 									Interval:        pointerOf(30 * time.Minute),
 									Mode:            pointerOf("fail"),
 									RenderTemplates: pointerOf(false),
+									DelayFunction:   pointerOf("constant"),
+									MaxDelay:        pointerOf(time.Duration(0)),
+									Jitter:          pointerOf(0.25),
 								},
 							},
 						},
@@ -1222,6 +1262,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 				ParentID:          pointerOf("lol"),
 				Priority:          pointerOf(JobDefaultPriority),
 				NodePool:          pointerOf(""),
+				PriorityClass:     pointerOf(""),
 				AllAtOnce:         pointerOf(false),
 				ConsulNamespace:   pointerOf(""),
 				VaultNamespace:    pointerOf(""),
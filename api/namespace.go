@@ -77,6 +77,8 @@ type Namespace struct {
 	NodePoolConfiguration *NamespaceNodePoolConfiguration `hcl:"node_pool_config,block"`
 	VaultConfiguration    *NamespaceVaultConfiguration    `hcl:"vault,block"`
 	ConsulConfiguration   *NamespaceConsulConfiguration   `hcl:"consul,block"`
+	SchedulerWeight       int                             `mapstructure:"scheduler_weight" hcl:"scheduler_weight,optional"`
+	NetworkPolicy         *NetworkPolicy                  `hcl:"network_policy,block"`
 	Meta                  map[string]string
 	CreateIndex           uint64
 	ModifyIndex           uint64
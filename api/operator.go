@@ -180,6 +180,18 @@ type SchedulerConfiguration struct {
 	// until the configuration is updated and written to the Nomad servers.
 	PauseEvalBroker bool
 
+	// NamespaceFairShare configures weighted fair-share dequeueing of
+	// evaluations across namespaces.
+	NamespaceFairShare NamespaceFairShareConfig
+
+	// Descheduler configures the core job that migrates allocations to
+	// rebalance the cluster.
+	Descheduler DeschedulerConfig
+
+	// PriorityClasses are the named priority classes jobs can reference to
+	// set their priority and how they take part in preemption.
+	PriorityClasses []*PriorityClass
+
 	// CreateIndex/ModifyIndex store the create/modify indexes of this configuration.
 	CreateIndex uint64
 	ModifyIndex uint64
@@ -213,6 +225,44 @@ const (
 	SchedulerAlgorithmSpread  SchedulerAlgorithm = "spread"
 )
 
+// NamespaceFairShareConfig configures how the eval broker shares scheduler
+// workers between namespaces.
+type NamespaceFairShareConfig struct {
+	Enabled       bool
+	DefaultWeight int
+}
+
+// DeschedulerConfig configures the core job that periodically migrates
+// allocations away from the nodes where they unbalance the cluster.
+type DeschedulerConfig struct {
+	Enabled                bool
+	DryRun                 bool
+	MaxMigrations          int
+	UtilizationThreshold   int
+	FragmentationThreshold int
+}
+
+const (
+	// PriorityClassPreemptionPolicyLowerPriority allows the jobs of a
+	// priority class to preempt allocations of lower priority jobs.
+	PriorityClassPreemptionPolicyLowerPriority = "lower_priority"
+
+	// PriorityClassPreemptionPolicyNever prevents the jobs of a priority
+	// class from preempting other allocations.
+	PriorityClassPreemptionPolicyNever = "never"
+)
+
+// PriorityClass is a named job priority defined by operators, which also
+// controls whether its jobs preempt other allocations or can be preempted.
+type PriorityClass struct {
+	Name             string
+	Description      string
+	Priority         int
+	PreemptionPolicy string
+	NonPreemptible   bool
+	Namespaces       []string
+}
+
 // PreemptionConfig specifies whether preemption is enabled based on scheduler type
 type PreemptionConfig struct {
 	SystemSchedulerEnabled   bool
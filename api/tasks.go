@@ -54,11 +54,21 @@ type CpuStats struct {
 	Measured         []string
 }
 
+// NetworkStats holds the traffic of the network namespace of an allocation
+type NetworkStats struct {
+	RxBytes  uint64
+	TxBytes  uint64
+	RxMbits  float64
+	TxMbits  float64
+	Measured []string
+}
+
 // ResourceUsage holds information related to cpu and memory stats
 type ResourceUsage struct {
-	MemoryStats *MemoryStats
-	CpuStats    *CpuStats
-	DeviceStats []*DeviceGroupStats
+	MemoryStats  *MemoryStats
+	CpuStats     *CpuStats
+	DeviceStats  []*DeviceGroupStats
+	NetworkStats *NetworkStats
 }
 
 // TaskResourceUsage holds aggregated resource usage of all processes in a Task
@@ -103,6 +113,10 @@ type RestartPolicy struct {
 	Delay           *time.Duration `hcl:"delay,optional"`
 	Mode            *string        `hcl:"mode,optional"`
 	RenderTemplates *bool          `mapstructure:"render_templates" hcl:"render_templates,optional"`
+	DelayFunction   *string        `mapstructure:"delay_function" hcl:"delay_function,optional"`
+	MaxDelay        *time.Duration `mapstructure:"max_delay" hcl:"max_delay,optional"`
+	Jitter          *float64       `hcl:"jitter,optional"`
+	ExitRules       []*ExitRule    `mapstructure:"on_exit" hcl:"on_exit,block"`
 }
 
 func (r *RestartPolicy) Merge(rp *RestartPolicy) {
@@ -121,6 +135,26 @@ func (r *RestartPolicy) Merge(rp *RestartPolicy) {
 	if rp.RenderTemplates != nil {
 		r.RenderTemplates = rp.RenderTemplates
 	}
+	if rp.DelayFunction != nil {
+		r.DelayFunction = rp.DelayFunction
+	}
+	if rp.MaxDelay != nil {
+		r.MaxDelay = rp.MaxDelay
+	}
+	if rp.Jitter != nil {
+		r.Jitter = rp.Jitter
+	}
+	if rp.ExitRules != nil {
+		r.ExitRules = rp.ExitRules
+	}
+}
+
+// ExitRule decides what happens when a task exits with one of the exit codes
+// or is killed by one of the signals of the rule.
+type ExitRule struct {
+	ExitCodes []int    `mapstructure:"exit_codes" hcl:"exit_codes,optional"`
+	Signals   []string `hcl:"signals,optional"`
+	Action    string   `hcl:"action"`
 }
 
 // Disconnect strategy defines how both clients and server should behave in case of
@@ -154,6 +188,45 @@ func (ds *DisconnectStrategy) Canonicalize() {
 	}
 }
 
+// IndexedPolicy turns the allocations of a batch task group into completion
+// indexes, exposed to tasks as NOMAD_ALLOC_INDEX.
+type IndexedPolicy struct {
+	// Parallelism is the maximum number of allocations running at once.
+	Parallelism *int `mapstructure:"parallelism" hcl:"parallelism,optional"`
+
+	// MaxFailures is the number of failed allocations after which an index is
+	// failed and no longer retried.
+	MaxFailures *int `mapstructure:"max_failures" hcl:"max_failures,optional"`
+}
+
+func (i *IndexedPolicy) Canonicalize() {
+	if i.Parallelism == nil {
+		i.Parallelism = pointerOf(0)
+	}
+	if i.MaxFailures == nil {
+		i.MaxFailures = pointerOf(3)
+	}
+}
+
+// NetworkPolicy controls which allocations on the bridge network of a client
+// may reach the allocations of a task group.
+type NetworkPolicy struct {
+	// Default is the policy applied to traffic that isn't matched by an allow
+	// rule, either "allow" or "deny".
+	Default string `mapstructure:"default" hcl:"default,optional"`
+
+	// Allow are the rules matching the allocations allowed to reach the
+	// allocations of the group when the default policy is "deny".
+	Allow []*NetworkPolicyRule `mapstructure:"allow" hcl:"allow,block"`
+}
+
+// NetworkPolicyRule matches the allocations allowed by a network policy.
+type NetworkPolicyRule struct {
+	Namespace string `mapstructure:"namespace" hcl:"namespace,optional"`
+	Job       string `mapstructure:"job" hcl:"job,optional"`
+	Service   string `mapstructure:"service" hcl:"service,optional"`
+}
+
 // Reschedule configures how Tasks are rescheduled  when they crash or fail.
 type ReschedulePolicy struct {
 	// Attempts limits the number of rescheduling attempts that can occur in an interval.
@@ -175,6 +248,10 @@ type ReschedulePolicy struct {
 
 	// Unlimited allows rescheduling attempts until they succeed
 	Unlimited *bool `mapstructure:"unlimited" hcl:"unlimited,optional"`
+
+	// ExitRules decide whether an allocation that failed because a task
+	// exited with a given exit code or signal is rescheduled.
+	ExitRules []*ExitRule `mapstructure:"on_exit" hcl:"on_exit,block"`
 }
 
 func (r *ReschedulePolicy) Merge(rp *ReschedulePolicy) {
@@ -199,6 +276,9 @@ func (r *ReschedulePolicy) Merge(rp *ReschedulePolicy) {
 	if rp.Unlimited != nil {
 		r.Unlimited = rp.Unlimited
 	}
+	if rp.ExitRules != nil {
+		r.ExitRules = rp.ExitRules
+	}
 }
 
 func (r *ReschedulePolicy) Canonicalize(jobType string) {
@@ -361,6 +441,57 @@ func (s *Spread) Canonicalize() {
 	}
 }
 
+// TopologySpread is used to serialize a hard limit on the skew of task group
+// allocations across the values of a node attribute
+type TopologySpread struct {
+	Attribute string `hcl:"attribute,optional"`
+	MaxSkew   *int   `mapstructure:"max_skew" hcl:"max_skew,optional"`
+}
+
+func NewTopologySpread(attribute string, maxSkew int) *TopologySpread {
+	return &TopologySpread{
+		Attribute: attribute,
+		MaxSkew:   pointerOf(maxSkew),
+	}
+}
+
+func (t *TopologySpread) Canonicalize() {
+	if t.MaxSkew == nil {
+		t.MaxSkew = pointerOf(1)
+	}
+}
+
+// JobAffinity is used to serialize a placement rule based on the allocations
+// of other jobs running on a node
+type JobAffinity struct {
+	JobID    string            `mapstructure:"job_id" hcl:"job_id,optional"`
+	Group    string            `hcl:"group,optional"`
+	Meta     map[string]string `hcl:"meta,optional"`
+	Anti     *bool             `hcl:"anti,optional"`
+	Required *bool             `hcl:"required,optional"`
+	Weight   *int8             `hcl:"weight,optional"`
+}
+
+func NewJobAffinity(jobID string, anti bool, weight int8) *JobAffinity {
+	return &JobAffinity{
+		JobID:  jobID,
+		Anti:   pointerOf(anti),
+		Weight: pointerOf(weight),
+	}
+}
+
+func (a *JobAffinity) Canonicalize() {
+	if a.Anti == nil {
+		a.Anti = pointerOf(false)
+	}
+	if a.Required == nil {
+		a.Required = pointerOf(false)
+	}
+	if a.Weight == nil {
+		a.Weight = pointerOf(int8(50))
+	}
+}
+
 // EphemeralDisk is an ephemeral disk object
 type EphemeralDisk struct {
 	Sticky  *bool `hcl:"sticky,optional"`
@@ -395,14 +526,17 @@ type MigrateStrategy struct {
 	HealthCheck     *string        `mapstructure:"health_check" hcl:"health_check,optional"`
 	MinHealthyTime  *time.Duration `mapstructure:"min_healthy_time" hcl:"min_healthy_time,optional"`
 	HealthyDeadline *time.Duration `mapstructure:"healthy_deadline" hcl:"healthy_deadline,optional"`
+
+	DisableDescheduling *bool `mapstructure:"disable_descheduling" hcl:"disable_descheduling,optional"`
 }
 
 func DefaultMigrateStrategy() *MigrateStrategy {
 	return &MigrateStrategy{
-		MaxParallel:     pointerOf(1),
-		HealthCheck:     pointerOf("checks"),
-		MinHealthyTime:  pointerOf(10 * time.Second),
-		HealthyDeadline: pointerOf(5 * time.Minute),
+		MaxParallel:         pointerOf(1),
+		HealthCheck:         pointerOf("checks"),
+		MinHealthyTime:      pointerOf(10 * time.Second),
+		HealthyDeadline:     pointerOf(5 * time.Minute),
+		DisableDescheduling: pointerOf(false),
 	}
 }
 
@@ -423,6 +557,9 @@ func (m *MigrateStrategy) Canonicalize() {
 	if m.HealthyDeadline == nil {
 		m.HealthyDeadline = defaults.HealthyDeadline
 	}
+	if m.DisableDescheduling == nil {
+		m.DisableDescheduling = defaults.DisableDescheduling
+	}
 }
 
 func (m *MigrateStrategy) Merge(o *MigrateStrategy) {
@@ -438,6 +575,9 @@ func (m *MigrateStrategy) Merge(o *MigrateStrategy) {
 	if o.HealthyDeadline != nil {
 		m.HealthyDeadline = o.HealthyDeadline
 	}
+	if o.DisableDescheduling != nil {
+		m.DisableDescheduling = o.DisableDescheduling
+	}
 }
 
 func (m *MigrateStrategy) Copy() *MigrateStrategy {
@@ -501,6 +641,8 @@ type TaskGroup struct {
 	Affinities       []*Affinity               `hcl:"affinity,block"`
 	Tasks            []*Task                   `hcl:"task,block"`
 	Spreads          []*Spread                 `hcl:"spread,block"`
+	TopologySpreads  []*TopologySpread         `hcl:"topology_spread,block"`
+	JobAffinities    []*JobAffinity            `hcl:"job_affinity,block"`
 	Volumes          map[string]*VolumeRequest `hcl:"volume,block"`
 	RestartPolicy    *RestartPolicy            `hcl:"restart,block"`
 	Disconnect       *DisconnectStrategy       `hcl:"disconnect,block"`
@@ -509,9 +651,11 @@ type TaskGroup struct {
 	Update           *UpdateStrategy           `hcl:"update,block"`
 	Migrate          *MigrateStrategy          `hcl:"migrate,block"`
 	Networks         []*NetworkResource        `hcl:"network,block"`
+	NetworkPolicy    *NetworkPolicy            `hcl:"network_policy,block"`
 	Meta             map[string]string         `hcl:"meta,block"`
 	Services         []*Service                `hcl:"service,block"`
 	ShutdownDelay    *time.Duration            `mapstructure:"shutdown_delay" hcl:"shutdown_delay,optional"`
+	MaxRunDuration   *time.Duration            `mapstructure:"max_run_duration" hcl:"max_run_duration,optional"`
 	// Deprecated: StopAfterClientDisconnect is deprecated in Nomad 1.8 and ignored in Nomad 1.10. Use Disconnect.StopOnClientAfter.
 	StopAfterClientDisconnect *time.Duration `mapstructure:"stop_after_client_disconnect" hcl:"stop_after_client_disconnect,optional"`
 	// Deprecated: MaxClientDisconnect is deprecated in Nomad 1.8.0 and ignored in Nomad 1.10. Use Disconnect.LostAfter.
@@ -519,7 +663,9 @@ type TaskGroup struct {
 	Scaling             *ScalingPolicy `hcl:"scaling,block"`
 	Consul              *Consul        `hcl:"consul,block"`
 	// Deprecated: PreventRescheduleOnLost is deprecated in Nomad 1.8.0 and ignored in Nomad 1.10. Use Disconnect.Replace.
-	PreventRescheduleOnLost *bool `hcl:"prevent_reschedule_on_lost,optional"`
+	PreventRescheduleOnLost *bool          `hcl:"prevent_reschedule_on_lost,optional"`
+	Gang                    *bool          `hcl:"gang,optional"`
+	Indexed                 *IndexedPolicy `hcl:"indexed,block"`
 }
 
 // NewTaskGroup creates a new TaskGroup.
@@ -629,6 +775,12 @@ func (g *TaskGroup) Canonicalize(job *Job) {
 	for _, spread := range g.Spreads {
 		spread.Canonicalize()
 	}
+	for _, spread := range g.TopologySpreads {
+		spread.Canonicalize()
+	}
+	for _, a := range g.JobAffinities {
+		a.Canonicalize()
+	}
 	for _, a := range g.Affinities {
 		a.Canonicalize()
 	}
@@ -642,6 +794,10 @@ func (g *TaskGroup) Canonicalize(job *Job) {
 	if g.Disconnect != nil {
 		g.Disconnect.Canonicalize()
 	}
+
+	if g.Indexed != nil {
+		g.Indexed.Canonicalize()
+	}
 }
 
 // These needs to be in sync with DefaultServiceJobRestartPolicy in
@@ -653,6 +809,9 @@ func defaultServiceJobRestartPolicy() *RestartPolicy {
 		Interval:        pointerOf(30 * time.Minute),
 		Mode:            pointerOf(RestartPolicyModeFail),
 		RenderTemplates: pointerOf(false),
+		DelayFunction:   pointerOf("constant"),
+		MaxDelay:        pointerOf(time.Duration(0)),
+		Jitter:          pointerOf(0.25),
 	}
 }
 
@@ -665,6 +824,9 @@ func defaultBatchJobRestartPolicy() *RestartPolicy {
 		Interval:        pointerOf(24 * time.Hour),
 		Mode:            pointerOf(RestartPolicyModeFail),
 		RenderTemplates: pointerOf(false),
+		DelayFunction:   pointerOf("constant"),
+		MaxDelay:        pointerOf(time.Duration(0)),
+		Jitter:          pointerOf(0.25),
 	}
 }
 
@@ -707,6 +869,18 @@ func (g *TaskGroup) AddSpread(s *Spread) *TaskGroup {
 	return g
 }
 
+// AddTopologySpread is used to add a new topology spread to a task group.
+func (g *TaskGroup) AddTopologySpread(s *TopologySpread) *TaskGroup {
+	g.TopologySpreads = append(g.TopologySpreads, s)
+	return g
+}
+
+// AddJobAffinity is used to add a new job affinity to a task group.
+func (g *TaskGroup) AddJobAffinity(a *JobAffinity) *TaskGroup {
+	g.JobAffinities = append(g.JobAffinities, a)
+	return g
+}
+
 // LogConfig provides configuration for log rotation
 type LogConfig struct {
 	MaxFiles      *int `mapstructure:"max_files" hcl:"max_files,optional"`
@@ -717,6 +891,13 @@ type LogConfig struct {
 	Enabled *bool `mapstructure:"enabled" hcl:"enabled,optional"`
 
 	Disabled *bool `mapstructure:"disabled" hcl:"disabled,optional"`
+
+	Compress       string         `mapstructure:"compress" hcl:"compress,optional"`
+	MaxAge         *time.Duration `mapstructure:"max_age" hcl:"max_age,optional"`
+	MaxTotalSizeMB *int           `mapstructure:"max_total_size" hcl:"max_total_size,optional"`
+	Timestamps     *bool          `mapstructure:"timestamps" hcl:"timestamps,optional"`
+
+	Sinks []*LogSink `mapstructure:"sink" hcl:"sink,block"`
 }
 
 func DefaultLogConfig() *LogConfig {
@@ -737,6 +918,35 @@ func (l *LogConfig) Canonicalize() {
 	if l.Disabled == nil {
 		l.Disabled = pointerOf(false)
 	}
+	for _, sink := range l.Sinks {
+		sink.Canonicalize()
+	}
+}
+
+// LogSink configures an external destination the logs of a task are shipped
+// to, in addition to the log files.
+type LogSink struct {
+	Type          string         `mapstructure:"type" hcl:"type,optional"`
+	Address       string         `mapstructure:"address" hcl:"address,optional"`
+	BufferSize    *int           `mapstructure:"buffer_size" hcl:"buffer_size,optional"`
+	RetryAttempts *int           `mapstructure:"retry_attempts" hcl:"retry_attempts,optional"`
+	RetryInterval *time.Duration `mapstructure:"retry_interval" hcl:"retry_interval,optional"`
+	DropPolicy    string         `mapstructure:"drop_policy" hcl:"drop_policy,optional"`
+}
+
+func (s *LogSink) Canonicalize() {
+	if s.BufferSize == nil {
+		s.BufferSize = pointerOf(1024)
+	}
+	if s.RetryAttempts == nil {
+		s.RetryAttempts = pointerOf(3)
+	}
+	if s.RetryInterval == nil {
+		s.RetryInterval = pointerOf(1 * time.Second)
+	}
+	if s.DropPolicy == "" {
+		s.DropPolicy = "oldest"
+	}
 }
 
 // DispatchPayloadConfig configures how a task gets its input from a job dispatch
@@ -760,12 +970,26 @@ func (l *TaskLifecycle) Empty() bool {
 	return l == nil || (l.Hook == "")
 }
 
+const (
+	TaskDependencyConditionStarted   = "started"
+	TaskDependencyConditionHealthy   = "healthy"
+	TaskDependencyConditionCompleted = "completed"
+)
+
+// TaskDependency prevents a task from starting until another task of the same
+// group reaches a given state.
+type TaskDependency struct {
+	Task      string `hcl:"task,label"`
+	Condition string `hcl:"condition,optional"`
+}
+
 // Task is a single process in a task group.
 type Task struct {
 	Name            string                 `hcl:"name,label"`
 	Driver          string                 `hcl:"driver,optional"`
 	User            string                 `hcl:"user,optional"`
 	Lifecycle       *TaskLifecycle         `hcl:"lifecycle,block"`
+	DependsOn       []*TaskDependency      `mapstructure:"depends_on" hcl:"depends_on,block"`
 	Config          map[string]interface{} `hcl:"config,block"`
 	Constraints     []*Constraint          `hcl:"constraint,block"`
 	Affinities      []*Affinity            `hcl:"affinity,block"`
@@ -785,6 +1009,7 @@ type Task struct {
 	CSIPluginConfig *TaskCSIPluginConfig   `mapstructure:"csi_plugin" json:",omitempty" hcl:"csi_plugin,block"`
 	Leader          bool                   `hcl:"leader,optional"`
 	ShutdownDelay   time.Duration          `mapstructure:"shutdown_delay" hcl:"shutdown_delay,optional"`
+	MaxRunDuration  time.Duration          `mapstructure:"max_run_duration" hcl:"max_run_duration,optional"`
 	KillSignal      string                 `mapstructure:"kill_signal" hcl:"kill_signal,optional"`
 	Kind            string                 `hcl:"kind,optional"`
 	ScalingPolicies []*ScalingPolicy       `hcl:"scaling,block"`
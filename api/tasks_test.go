@@ -623,10 +623,11 @@ func TestTaskGroup_Canonicalize_MigrateStrategy(t *testing.T) {
 			jobMigrate:  nil,
 			taskMigrate: nil,
 			expected: &MigrateStrategy{
-				MaxParallel:     pointerOf(1),
-				HealthCheck:     pointerOf("checks"),
-				MinHealthyTime:  pointerOf(10 * time.Second),
-				HealthyDeadline: pointerOf(5 * time.Minute),
+				MaxParallel:         pointerOf(1),
+				HealthCheck:         pointerOf("checks"),
+				MinHealthyTime:      pointerOf(10 * time.Second),
+				HealthyDeadline:     pointerOf(5 * time.Minute),
+				DisableDescheduling: pointerOf(false),
 			},
 		},
 		{
@@ -640,10 +641,11 @@ func TestTaskGroup_Canonicalize_MigrateStrategy(t *testing.T) {
 			},
 			taskMigrate: nil,
 			expected: &MigrateStrategy{
-				MaxParallel:     pointerOf(0),
-				HealthCheck:     pointerOf(""),
-				MinHealthyTime:  pointerOf(time.Duration(0)),
-				HealthyDeadline: pointerOf(time.Duration(0)),
+				MaxParallel:         pointerOf(0),
+				HealthCheck:         pointerOf(""),
+				MinHealthyTime:      pointerOf(time.Duration(0)),
+				HealthyDeadline:     pointerOf(time.Duration(0)),
+				DisableDescheduling: pointerOf(false),
 			},
 		},
 		{
@@ -657,10 +659,11 @@ func TestTaskGroup_Canonicalize_MigrateStrategy(t *testing.T) {
 			},
 			taskMigrate: nil,
 			expected: &MigrateStrategy{
-				MaxParallel:     pointerOf(3),
-				HealthCheck:     pointerOf("checks"),
-				MinHealthyTime:  pointerOf(time.Duration(2)),
-				HealthyDeadline: pointerOf(time.Duration(2)),
+				MaxParallel:         pointerOf(3),
+				HealthCheck:         pointerOf("checks"),
+				MinHealthyTime:      pointerOf(time.Duration(2)),
+				HealthyDeadline:     pointerOf(time.Duration(2)),
+				DisableDescheduling: pointerOf(false),
 			},
 		},
 		{
@@ -674,10 +677,11 @@ func TestTaskGroup_Canonicalize_MigrateStrategy(t *testing.T) {
 				HealthyDeadline: pointerOf(time.Duration(2)),
 			},
 			expected: &MigrateStrategy{
-				MaxParallel:     pointerOf(3),
-				HealthCheck:     pointerOf("checks"),
-				MinHealthyTime:  pointerOf(time.Duration(2)),
-				HealthyDeadline: pointerOf(time.Duration(2)),
+				MaxParallel:         pointerOf(3),
+				HealthCheck:         pointerOf("checks"),
+				MinHealthyTime:      pointerOf(time.Duration(2)),
+				HealthyDeadline:     pointerOf(time.Duration(2)),
+				DisableDescheduling: pointerOf(false),
 			},
 		},
 		{
@@ -692,10 +696,11 @@ func TestTaskGroup_Canonicalize_MigrateStrategy(t *testing.T) {
 				HealthyDeadline: pointerOf(time.Duration(2)),
 			},
 			expected: &MigrateStrategy{
-				MaxParallel:     pointerOf(11),
-				HealthCheck:     pointerOf("checks"),
-				MinHealthyTime:  pointerOf(time.Duration(2)),
-				HealthyDeadline: pointerOf(time.Duration(2)),
+				MaxParallel:         pointerOf(11),
+				HealthCheck:         pointerOf("checks"),
+				MinHealthyTime:      pointerOf(time.Duration(2)),
+				HealthyDeadline:     pointerOf(time.Duration(2)),
+				DisableDescheduling: pointerOf(false),
 			},
 		},
 		{
@@ -711,10 +716,11 @@ func TestTaskGroup_Canonicalize_MigrateStrategy(t *testing.T) {
 				HealthyDeadline: pointerOf(time.Duration(2)),
 			},
 			expected: &MigrateStrategy{
-				MaxParallel:     pointerOf(5),
-				HealthCheck:     pointerOf("checks"),
-				MinHealthyTime:  pointerOf(time.Duration(2)),
-				HealthyDeadline: pointerOf(time.Duration(2)),
+				MaxParallel:         pointerOf(5),
+				HealthCheck:         pointerOf("checks"),
+				MinHealthyTime:      pointerOf(time.Duration(2)),
+				HealthyDeadline:     pointerOf(time.Duration(2)),
+				DisableDescheduling: pointerOf(false),
 			},
 		},
 		{
@@ -725,10 +731,11 @@ func TestTaskGroup_Canonicalize_MigrateStrategy(t *testing.T) {
 			},
 			taskMigrate: nil,
 			expected: &MigrateStrategy{
-				MaxParallel:     pointerOf(5),
-				HealthCheck:     pointerOf("checks"),
-				MinHealthyTime:  pointerOf(10 * time.Second),
-				HealthyDeadline: pointerOf(5 * time.Minute),
+				MaxParallel:         pointerOf(5),
+				HealthCheck:         pointerOf("checks"),
+				MinHealthyTime:      pointerOf(10 * time.Second),
+				HealthyDeadline:     pointerOf(5 * time.Minute),
+				DisableDescheduling: pointerOf(false),
 			},
 		},
 	}
@@ -220,6 +220,13 @@ type allocRunner struct {
 	// partitions is an interface for managing cpuset partitions
 	partitions cinterfaces.CPUPartitions
 
+	// networkPolicies enforces the network policies of allocations on the
+	// bridge network
+	networkPolicies cinterfaces.NetworkPolicies
+
+	// networkStats samples the traffic of the alloc network namespace
+	networkStats *networkStatsCollector
+
 	// widsigner signs workload identities
 	widsigner widmgr.IdentitySigner
 
@@ -270,6 +277,8 @@ func NewAllocRunner(config *config.AllocRunnerConfig) (interfaces.AllocRunner, e
 		getter:                   config.Getter,
 		wranglers:                config.Wranglers,
 		partitions:               config.Partitions,
+		networkPolicies:          config.NetworkPolicies,
+		networkStats:             newNetworkStatsCollector(),
 		hookResources:            cstructs.NewAllocHookResources(),
 		widsigner:                config.WIDSigner,
 		users:                    config.Users,
@@ -379,6 +388,13 @@ func (ar *allocRunner) Run() {
 	// Start the alloc update handler
 	go ar.handleAllocUpdates()
 
+	// Start the health watcher of the tasks that other tasks depend on
+	alloc := ar.Alloc()
+	tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup)
+	if tasks := tasklifecycle.HealthDependencies(tg.Tasks); len(tasks) > 0 {
+		go ar.watchTaskHealth(tasks)
+	}
+
 	// If task update chan has been closed, that means we've been shutdown.
 	select {
 	case <-ar.taskStateUpdateHandlerCh:
@@ -1294,6 +1310,11 @@ func (ar *allocRunner) LatestAllocStats(taskFilter string) (*cstructs.AllocResou
 		},
 	}
 
+	// The network namespace is shared by all the tasks so its stats are
+	// reported for each task without being summed
+	networkStats := ar.networkStats.Latest()
+	astat.ResourceUsage.NetworkStats = networkStats
+
 	for name, tr := range ar.tasks {
 		if taskFilter != "" && taskFilter != name {
 			// Getting stats for a particular task and its not this one!
@@ -1301,6 +1322,9 @@ func (ar *allocRunner) LatestAllocStats(taskFilter string) (*cstructs.AllocResou
 		}
 
 		if usage := tr.LatestResourceUsage(); usage != nil {
+			if networkStats != nil && usage.ResourceUsage != nil {
+				usage = usage.WithNetworkStats(networkStats)
+			}
 			astat.Tasks[name] = usage
 			astat.ResourceUsage.Add(usage.ResourceUsage)
 			if usage.Timestamp > astat.Timestamp {
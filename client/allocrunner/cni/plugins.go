@@ -51,6 +51,17 @@ type PortmapCapabilities struct {
 	Portmappings bool `json:"portMappings"`
 }
 
+// Bandwidth is the "bandwidth" plugin used to limit the ingress and egress
+// rate of the allocation.
+// https://www.cni.dev/plugins/current/meta/bandwidth/
+type Bandwidth struct {
+	Type         string                `json:"type"`
+	Capabilities BandwidthCapabilities `json:"capabilities"`
+}
+type BandwidthCapabilities struct {
+	Bandwidth bool `json:"bandwidth"`
+}
+
 // ConsulCNI is the "consul-cni" plugin used for transparent proxy.
 // https://github.com/hashicorp/consul-k8s/blob/main/control-plane/cni/main.go
 type ConsulCNI struct {
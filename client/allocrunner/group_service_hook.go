@@ -108,9 +108,7 @@ func newGroupServiceHook(cfg groupServiceHookConfig) *groupServiceHook {
 		h.ports = cfg.alloc.AllocatedResources.Shared.Ports
 	}
 
-	if cfg.alloc.DeploymentStatus != nil {
-		h.canary = cfg.alloc.DeploymentStatus.Canary
-	}
+	h.canary = cfg.alloc.DeploymentStatus.CanaryServices()
 
 	return h
 }
@@ -165,10 +163,7 @@ func (h *groupServiceHook) Update(req *interfaces.RunnerUpdateRequest) error {
 	oldWorkloadServices := h.getWorkloadServicesLocked()
 
 	// Store new updated values out of request
-	canary := false
-	if req.Alloc.DeploymentStatus != nil {
-		canary = req.Alloc.DeploymentStatus.Canary
-	}
+	canary := req.Alloc.DeploymentStatus.CanaryServices()
 
 	var networks structs.Networks
 	if req.Alloc.AllocatedResources != nil {
@@ -10,6 +10,7 @@ import (
 
 	hclog "github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	cinterfaces "github.com/hashicorp/nomad/client/interfaces"
 	"github.com/hashicorp/nomad/client/taskenv"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/plugins/drivers"
@@ -44,6 +45,7 @@ type allocNetworkIsolationSetter struct {
 }
 
 func (a *allocNetworkIsolationSetter) SetNetworkIsolation(n *drivers.NetworkIsolationSpec) {
+	a.ar.networkStats.SetNetworkIsolation(n)
 	for _, tr := range a.ar.tasks {
 		tr.SetNetworkIsolation(n)
 	}
@@ -51,6 +53,10 @@ func (a *allocNetworkIsolationSetter) SetNetworkIsolation(n *drivers.NetworkIsol
 
 type networkStatusSetter interface {
 	SetNetworkStatus(*structs.AllocNetworkStatus)
+
+	// NetworkStatus returns the network status restored from the client
+	// state if the network already existed
+	NetworkStatus() *structs.AllocNetworkStatus
 }
 
 // networkHook is an alloc lifecycle hook that manages the network namespace
@@ -79,6 +85,10 @@ type networkHook struct {
 	// the alloc network has been created
 	networkConfigurator NetworkConfigurator
 
+	// networkPolicies enforces the network policy of the alloc once it has
+	// an address on the bridge network
+	networkPolicies cinterfaces.NetworkPolicies
+
 	logger hclog.Logger
 }
 
@@ -88,6 +98,7 @@ func newNetworkHook(logger hclog.Logger,
 	netManager drivers.DriverNetworkManager,
 	netConfigurator NetworkConfigurator,
 	networkStatusSetter networkStatusSetter,
+	networkPolicies cinterfaces.NetworkPolicies,
 ) *networkHook {
 	return &networkHook{
 		isolationSetter:     ns,
@@ -95,6 +106,7 @@ func newNetworkHook(logger hclog.Logger,
 		alloc:               alloc,
 		manager:             netManager,
 		networkConfigurator: netConfigurator,
+		networkPolicies:     networkPolicies,
 		logger:              logger,
 	}
 }
@@ -168,7 +180,8 @@ CREATE:
 			return fmt.Errorf("failed to configure networking for alloc: %v", err)
 		}
 		if status == nil {
-			return nil // netns already existed and was correctly configured
+			// netns already existed and was correctly configured
+			return h.addNetworkPolicy(tg, h.networkStatusSetter.NetworkStatus())
 		}
 
 		// If the driver set the sandbox hostname label, then we will use that
@@ -196,11 +209,34 @@ CREATE:
 		}
 
 		h.networkStatusSetter.SetNetworkStatus(status)
+		return h.addNetworkPolicy(tg, status)
+	}
+	return nil
+}
+
+// addNetworkPolicy starts enforcing the network policy of the alloc and the
+// policies of the other allocs allowing it, if it's on the bridge network.
+func (h *networkHook) addNetworkPolicy(tg *structs.TaskGroup, status *structs.AllocNetworkStatus) error {
+	if h.networkPolicies == nil || tg.Networks[0].Mode != "bridge" {
+		return nil
+	}
+
+	var address string
+	if status != nil {
+		address = status.Address
+	}
+	if err := h.networkPolicies.Add(h.alloc, address); err != nil {
+		return fmt.Errorf("failed to apply network policy for alloc: %v", err)
 	}
 	return nil
 }
 
 func (h *networkHook) Postrun() error {
+	if h.networkPolicies != nil {
+		if err := h.networkPolicies.Remove(h.alloc.ID); err != nil {
+			h.logger.Error("failed to remove network policy for allocation", "alloc", h.alloc.ID, "error", err)
+		}
+	}
 
 	// we need the spec for network teardown
 	if h.spec != nil {
@@ -41,6 +41,10 @@ func (m *mockNetworkStatusSetter) SetNetworkStatus(status *structs.AllocNetworkS
 	test.Eq(m.t, m.expectedStatus, status)
 }
 
+func (m *mockNetworkStatusSetter) NetworkStatus() *structs.AllocNetworkStatus {
+	return m.expectedStatus
+}
+
 // Test that the prerun and postrun hooks call the setter with the expected
 // NetworkIsolationSpec for group bridge network.
 func TestNetworkHook_Prerun_Postrun_group(t *testing.T) {
@@ -83,7 +87,7 @@ func TestNetworkHook_Prerun_Postrun_group(t *testing.T) {
 	}
 
 	logger := testlog.HCLogger(t)
-	hook := newNetworkHook(logger, setter, alloc, nm, &hostNetworkConfigurator{}, statusSetter)
+	hook := newNetworkHook(logger, setter, alloc, nm, &hostNetworkConfigurator{}, statusSetter, nil)
 	env := taskenv.NewBuilder(mock.Node(), alloc, nil, alloc.Job.Region).Build()
 
 	must.NoError(t, hook.Prerun(env))
@@ -127,7 +131,7 @@ func TestNetworkHook_Prerun_Postrun_host(t *testing.T) {
 	}
 
 	logger := testlog.HCLogger(t)
-	hook := newNetworkHook(logger, setter, alloc, nm, &hostNetworkConfigurator{}, statusSetter)
+	hook := newNetworkHook(logger, setter, alloc, nm, &hostNetworkConfigurator{}, statusSetter, nil)
 	env := taskenv.NewBuilder(mock.Node(), alloc, nil, alloc.Job.Region).Build()
 
 	must.NoError(t, hook.Prerun(env))
@@ -252,7 +256,7 @@ func TestNetworkHook_Prerun_Postrun_ExistingNetNS(t *testing.T) {
 			fakePlugin.checkErrors = tc.checkErrs
 			configurator.nodeAttrs["plugins.cni.version.bridge"] = tc.cniVersion
 			hook := newNetworkHook(testlog.HCLogger(t), isolationSetter,
-				alloc, nm, configurator, statusSetter)
+				alloc, nm, configurator, statusSetter, nil)
 
 			err := hook.Prerun(env)
 			if tc.expectPrerunError == "" {
@@ -0,0 +1,293 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package allocrunner
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	hclog "github.com/hashicorp/go-hclog"
+	cinterfaces "github.com/hashicorp/nomad/client/interfaces"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// networkPolicyAlloc is an allocation on the bridge network tracked by the
+// network policies.
+type networkPolicyAlloc struct {
+	namespace string
+	job       string
+	address   string
+	services  []string
+
+	// policy is the network policy of the task group of the allocation,
+	// without the policy of its namespace
+	policy *structs.NetworkPolicy
+}
+
+// allows returns whether the given network policy of the allocation allows the
+// peer allocation to reach it. Allocations of the same job can always reach
+// each other.
+func (a *networkPolicyAlloc) allows(policy *structs.NetworkPolicy, peer *networkPolicyAlloc) bool {
+	if !policy.Denies() {
+		return true
+	}
+	if peer.namespace == a.namespace && peer.job == a.job {
+		return true
+	}
+	for _, rule := range policy.Allow {
+		if rule.Matches(a.namespace, peer.namespace, peer.job, peer.services) {
+			return true
+		}
+	}
+	return false
+}
+
+// adoptedChain is the chain of an allocation that was found in iptables when
+// the client started, before the allocation is restored.
+type adoptedChain struct {
+	address string
+	rules   [][]string
+}
+
+// networkPolicies enforces the network policies of the allocations on the
+// bridge network of the client with iptables. Each allocation that denies
+// traffic by default gets its own chain listing the addresses of the peers it
+// allows, and the chains are rebuilt as allocations come and go.
+type networkPolicies struct {
+	bridgeName  string
+	newIPTables func(structs.NodeNetworkAF) (IPTables, error)
+	logger      hclog.Logger
+
+	// allocs are the tracked allocations, by ID.
+	allocs map[string]*networkPolicyAlloc
+
+	// namespaces are the network policies of the namespaces, by namespace.
+	// They're merged with the policies of the allocations.
+	namespaces map[string]*structs.NetworkPolicy
+
+	// applied are the rules applied by the last update, or nil if they are
+	// unknown because the client restarted or the last update failed.
+	applied networkPolicyRules
+
+	// adopted are the chains of the allocations that were enforced before the
+	// client restarted, by chain name. They keep being enforced until their
+	// allocation is restored or removed, or another allocation gets their
+	// address, so that the allocations can't be reached while the client
+	// restores them.
+	adopted map[string]*adoptedChain
+
+	// adopt is true until the chains found in iptables have been adopted.
+	adopt bool
+
+	lock sync.Mutex
+}
+
+// NewNetworkPolicies returns the network policies enforcer of the bridge
+// network with the given name.
+func NewNetworkPolicies(logger hclog.Logger, bridgeName string) cinterfaces.NetworkPolicies {
+	if bridgeName == "" {
+		bridgeName = defaultNomadBridgeName
+	}
+	return &networkPolicies{
+		bridgeName:  bridgeName,
+		newIPTables: newIPTables,
+		logger:      logger.Named("network_policies"),
+		allocs:      make(map[string]*networkPolicyAlloc),
+		adopted:     make(map[string]*adoptedChain),
+		adopt:       true,
+	}
+}
+
+func (n *networkPolicies) Add(alloc *structs.Allocation, address string) error {
+	tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup)
+	if tg == nil {
+		return nil
+	}
+
+	services := make([]string, 0, len(tg.Services))
+	for _, service := range tg.Services {
+		services = append(services, service.Name)
+	}
+	for _, task := range tg.Tasks {
+		for _, service := range task.Services {
+			services = append(services, service.Name)
+		}
+	}
+
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if address == "" {
+		if tg.NetworkPolicy.Merge(n.namespaces[alloc.Namespace]).Denies() {
+			return fmt.Errorf("network policy requires the allocation to have an IPv4 address")
+		}
+		return nil
+	}
+
+	if err := n.adoptLocked(); err != nil {
+		return err
+	}
+	for chain, adopted := range n.adopted {
+		if chain == networkPolicyAllocChain(alloc.ID) || adopted.address == address {
+			delete(n.adopted, chain)
+		}
+	}
+
+	n.allocs[alloc.ID] = &networkPolicyAlloc{
+		namespace: alloc.Namespace,
+		job:       alloc.JobID,
+		address:   address,
+		services:  services,
+		policy:    tg.NetworkPolicy,
+	}
+	return n.updateLocked()
+}
+
+func (n *networkPolicies) Remove(allocID string) error {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if err := n.adoptLocked(); err != nil {
+		return err
+	}
+
+	chain := networkPolicyAllocChain(allocID)
+	_, tracked := n.allocs[allocID]
+	_, adopted := n.adopted[chain]
+	if !tracked && !adopted {
+		return nil
+	}
+	delete(n.allocs, allocID)
+	delete(n.adopted, chain)
+	return n.updateLocked()
+}
+
+func (n *networkPolicies) SetNamespacePolicies(policies map[string]*structs.NetworkPolicy) error {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if maps.EqualFunc(n.namespaces, policies, (*structs.NetworkPolicy).Equal) {
+		return nil
+	}
+	n.namespaces = policies
+
+	// The rules only need to be updated once allocations are tracked
+	if len(n.allocs) == 0 {
+		return nil
+	}
+	return n.updateLocked()
+}
+
+// adoptLocked adopts the chains of the allocations found in iptables the first
+// time it's called.
+//
+// Caller must hold n.lock.
+func (n *networkPolicies) adoptLocked() error {
+	if !n.adopt {
+		return nil
+	}
+
+	ipt, err := n.newIPTables(structs.NodeNetworkAF_IPv4)
+	if err != nil {
+		return err
+	}
+	chains, err := ipt.ListChains("filter")
+	if err != nil {
+		return fmt.Errorf("failed to list iptables chains: %v", err)
+	}
+	if !slices.Contains(chains, networkPolicyChainName) {
+		n.adopt = false
+		return nil
+	}
+
+	dispatch, err := listChainRules(ipt, networkPolicyChainName)
+	if err != nil {
+		return err
+	}
+	for _, rule := range dispatch {
+		// Dispatch rules are "-d <address>/32 -j <chain>"
+		if len(rule) != 4 || rule[0] != "-d" ||
+			!strings.HasPrefix(rule[3], networkPolicyAllocChainPrefix) {
+			continue
+		}
+		rules, err := listChainRules(ipt, rule[3])
+		if err != nil {
+			return err
+		}
+		n.adopted[rule[3]] = &adoptedChain{
+			address: strings.TrimSuffix(rule[1], "/32"),
+			rules:   rules,
+		}
+	}
+
+	n.adopt = false
+	if len(n.adopted) > 0 {
+		n.logger.Debug("adopted network policy chains of allocations to restore", "chains", len(n.adopted))
+	}
+	return nil
+}
+
+// updateLocked applies the rules of the tracked allocations.
+//
+// Caller must hold n.lock.
+func (n *networkPolicies) updateLocked() error {
+	ipt, err := n.newIPTables(structs.NodeNetworkAF_IPv4)
+	if err != nil {
+		return err
+	}
+
+	desired := n.rulesLocked()
+	if err := syncNetworkPolicyRules(ipt, n.bridgeName, desired, n.applied); err != nil {
+		n.applied = nil
+		return fmt.Errorf("failed to update network policy rules: %w", err)
+	}
+	n.applied = desired
+
+	n.logger.Trace("updated network policy rules",
+		"allocs", len(n.allocs), "adopted", len(n.adopted), "chains", len(desired)-1)
+	return nil
+}
+
+// rulesLocked returns the rules enforcing the network policies of the tracked
+// allocations.
+//
+// Caller must hold n.lock.
+func (n *networkPolicies) rulesLocked() networkPolicyRules {
+	rules := networkPolicyRules{
+		networkPolicyChainName: {generateNetworkPolicyAllowRule()},
+	}
+
+	ids := slices.Sorted(maps.Keys(n.allocs))
+	for _, id := range ids {
+		alloc := n.allocs[id]
+		policy := alloc.policy.Merge(n.namespaces[alloc.namespace])
+		if !policy.Denies() {
+			continue
+		}
+
+		var peers []string
+		for _, peerID := range ids {
+			if peer := n.allocs[peerID]; peerID != id && alloc.allows(policy, peer) {
+				peers = append(peers, peer.address)
+			}
+		}
+
+		chain := networkPolicyAllocChain(id)
+		rules[chain] = generateNetworkPolicyAllocRules(peers)
+		rules[networkPolicyChainName] = append(rules[networkPolicyChainName],
+			generateNetworkPolicyDispatchRule(alloc.address, chain))
+	}
+
+	for _, chain := range slices.Sorted(maps.Keys(n.adopted)) {
+		adopted := n.adopted[chain]
+		rules[chain] = adopted.rules
+		rules[networkPolicyChainName] = append(rules[networkPolicyChainName],
+			generateNetworkPolicyDispatchRule(adopted.address, chain))
+	}
+
+	return rules
+}
@@ -0,0 +1,323 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package allocrunner
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+// fakeIPTables is an in-memory filter table.
+type fakeIPTables struct {
+	chains map[string][][]string
+
+	// check is called after every change to the rules, if set
+	check func()
+}
+
+func newFakeIPTables() *fakeIPTables {
+	return &fakeIPTables{
+		chains: map[string][][]string{"FORWARD": nil},
+	}
+}
+
+func (ipt *fakeIPTables) changed() {
+	if ipt.check != nil {
+		ipt.check()
+	}
+}
+
+func (ipt *fakeIPTables) List(table, chain string) ([]string, error) {
+	rules := []string{"-N " + chain}
+	for _, rule := range ipt.chains[chain] {
+		rules = append(rules, "-A "+chain+" "+strings.Join(rule, " "))
+	}
+	return rules, nil
+}
+
+func (ipt *fakeIPTables) Delete(table, chain string, rulespec ...string) error {
+	ipt.chains[chain] = slices.DeleteFunc(ipt.chains[chain], func(rule []string) bool {
+		return slices.Equal(rule, rulespec)
+	})
+	ipt.changed()
+	return nil
+}
+
+func (ipt *fakeIPTables) ClearAndDeleteChain(table, chain string) error {
+	delete(ipt.chains, chain)
+	ipt.changed()
+	return nil
+}
+
+func (ipt *fakeIPTables) ListChains(table string) ([]string, error) {
+	return slices.Collect(maps.Keys(ipt.chains)), nil
+}
+
+func (ipt *fakeIPTables) NewChain(table, chain string) error {
+	ipt.chains[chain] = nil
+	return nil
+}
+
+func (ipt *fakeIPTables) Exists(table, chain string, rulespec ...string) (bool, error) {
+	return slices.ContainsFunc(ipt.chains[chain], func(rule []string) bool {
+		return slices.Equal(rule, rulespec)
+	}), nil
+}
+
+func (ipt *fakeIPTables) Append(table, chain string, rulespec ...string) error {
+	ipt.chains[chain] = append(ipt.chains[chain], rulespec)
+	ipt.changed()
+	return nil
+}
+
+func (ipt *fakeIPTables) Insert(table, chain string, pos int, rulespec ...string) error {
+	ipt.chains[chain] = slices.Insert(ipt.chains[chain], pos-1, rulespec)
+	ipt.changed()
+	return nil
+}
+
+// testNetworkPolicyAlloc returns an alloc of the job in bridge networking mode
+// with the given network policy.
+func testNetworkPolicyAlloc(namespace, job string, policy *structs.NetworkPolicy) *structs.Allocation {
+	alloc := mock.Alloc()
+	alloc.Namespace = namespace
+	alloc.JobID = job
+	alloc.Job.Namespace = namespace
+	alloc.Job.ID = job
+	tg := alloc.Job.TaskGroups[0]
+	tg.Networks = structs.Networks{{Mode: "bridge"}}
+	tg.NetworkPolicy = policy
+	tg.Services = []*structs.Service{{Name: job + "-svc"}}
+	tg.Tasks[0].Services = nil
+	return alloc
+}
+
+func TestNetworkPolicies(t *testing.T) {
+	ci.Parallel(t)
+
+	ipt := newFakeIPTables()
+	ipt.chains["NOMAD-NP-deadbeef"] = [][]string{{"-j", "DROP"}}
+
+	policies := NewNetworkPolicies(testlog.HCLogger(t), "").(*networkPolicies)
+	policies.newIPTables = func(structs.NodeNetworkAF) (IPTables, error) {
+		return ipt, nil
+	}
+
+	deny := &structs.NetworkPolicy{
+		Default: structs.NetworkPolicyDefaultDeny,
+		Allow: []*structs.NetworkPolicyRule{
+			{Job: "api"},
+			{Namespace: "*", Service: "prometheus-svc"},
+		},
+	}
+	web := testNetworkPolicyAlloc("default", "web", deny)
+	web2 := testNetworkPolicyAlloc("default", "web", deny)
+	api := testNetworkPolicyAlloc("default", "api", nil)
+	batch := testNetworkPolicyAlloc("default", "batch", nil)
+	prometheus := testNetworkPolicyAlloc("ops", "prometheus", nil)
+
+	must.NoError(t, policies.Add(web, "172.26.64.2"))
+	must.NoError(t, policies.Add(api, "172.26.64.3"))
+	must.NoError(t, policies.Add(batch, "172.26.64.4"))
+	must.NoError(t, policies.Add(prometheus, "172.26.64.5"))
+
+	webChain := networkPolicyAllocChain(web.ID)
+	must.Eq(t, "NOMAD-NP-"+web.ID[:19], webChain)
+	must.Eq(t, [][]string{generateNetworkPolicyJumpRule("nomad")}, ipt.chains["FORWARD"])
+	must.Eq(t, [][]string{
+		generateNetworkPolicyAllowRule(),
+		{"-d", "172.26.64.2/32", "-j", webChain},
+	}, ipt.chains[networkPolicyChainName])
+	must.MapContainsKey(t, ipt.chains, networkPolicyAllowChainName)
+	must.SliceContainsAll(t, generateNetworkPolicyAllocRules([]string{"172.26.64.3", "172.26.64.5"}), ipt.chains[webChain])
+	must.Eq(t, []string{"-j", "DROP"}, ipt.chains[webChain][3])
+	must.MapNotContainsKey(t, ipt.chains, "NOMAD-NP-deadbeef")
+
+	// The rules of the allow-list chain are left to operators
+	ipt.chains[networkPolicyAllowChainName] = [][]string{{"-s", "10.0.0.1/32", "-j", "ACCEPT"}}
+
+	// Allocations of the same job can reach each other
+	must.NoError(t, policies.Add(web2, "172.26.64.6"))
+	web2Chain := networkPolicyAllocChain(web2.ID)
+	must.SliceContains(t, ipt.chains[webChain], []string{"-s", "172.26.64.6/32", "-j", "RETURN"})
+	must.SliceContains(t, ipt.chains[web2Chain], []string{"-s", "172.26.64.2/32", "-j", "RETURN"})
+	must.SliceLen(t, 3, ipt.chains[networkPolicyChainName])
+
+	// Removing allocations updates the rules of the others
+	must.NoError(t, policies.Remove(api.ID))
+	must.SliceNotContains(t, ipt.chains[webChain], []string{"-s", "172.26.64.3/32", "-j", "RETURN"})
+
+	must.NoError(t, policies.Remove(web.ID))
+	must.NoError(t, policies.Remove(web2.ID))
+	must.MapNotContainsKey(t, ipt.chains, webChain)
+	must.MapNotContainsKey(t, ipt.chains, web2Chain)
+	must.Eq(t, [][]string{generateNetworkPolicyAllowRule()}, ipt.chains[networkPolicyChainName])
+	must.SliceLen(t, 1, ipt.chains["FORWARD"])
+	must.SliceLen(t, 1, ipt.chains[networkPolicyAllowChainName])
+
+	// Removing an unknown allocation is a no-op
+	must.NoError(t, policies.Remove(api.ID))
+}
+
+func TestNetworkPolicies_MissingAddress(t *testing.T) {
+	ci.Parallel(t)
+
+	policies := NewNetworkPolicies(testlog.HCLogger(t), "").(*networkPolicies)
+	policies.newIPTables = func(structs.NodeNetworkAF) (IPTables, error) {
+		return newFakeIPTables(), nil
+	}
+
+	alloc := testNetworkPolicyAlloc("default", "web", nil)
+	must.NoError(t, policies.Add(alloc, ""))
+
+	alloc.Job.TaskGroups[0].NetworkPolicy = &structs.NetworkPolicy{
+		Default: structs.NetworkPolicyDefaultDeny,
+	}
+	must.ErrorContains(t, policies.Add(alloc, ""), "requires the allocation to have an IPv4 address")
+}
+
+// TestNetworkPolicies_NeverOpen asserts that the allocations denying traffic
+// are never reachable while the rules are updated, including after a restart
+// of the client when the applied rules are unknown.
+func TestNetworkPolicies_NeverOpen(t *testing.T) {
+	ci.Parallel(t)
+
+	deny := &structs.NetworkPolicy{Default: structs.NetworkPolicyDefaultDeny}
+	web := testNetworkPolicyAlloc("default", "web", deny)
+	web2 := testNetworkPolicyAlloc("default", "web", deny)
+	api := testNetworkPolicyAlloc("default", "api", deny)
+	batch := testNetworkPolicyAlloc("default", "batch", nil)
+	addresses := map[string]string{
+		web.ID:   "172.26.64.2",
+		web2.ID:  "172.26.64.3",
+		api.ID:   "172.26.64.4",
+		batch.ID: "172.26.64.5",
+	}
+
+	// protected are the allocations that deny traffic during the whole
+	// update
+	var protected []string
+
+	ipt := newFakeIPTables()
+	ipt.check = func() {
+		jumps := ipt.chains[networkPolicyChainName]
+		for i, jump := range jumps {
+			if i == 0 {
+				must.Eq(t, generateNetworkPolicyAllowRule(), jump)
+				continue
+			}
+			chain := jump[len(jump)-1]
+			rules := ipt.chains[chain]
+			must.SliceNotEmpty(t, rules, must.Sprintf("chain %s in use is empty", chain))
+			must.Eq(t, []string{"-j", "DROP"}, rules[len(rules)-1],
+				must.Sprintf("chain %s in use doesn't drop traffic", chain))
+		}
+		for _, id := range protected {
+			rule := generateNetworkPolicyDispatchRule(addresses[id], networkPolicyAllocChain(id))
+			must.SliceContains(t, jumps, rule,
+				must.Sprintf("traffic to alloc %s isn't filtered", id))
+		}
+	}
+
+	newPolicies := func() *networkPolicies {
+		policies := NewNetworkPolicies(testlog.HCLogger(t), "nomad").(*networkPolicies)
+		policies.newIPTables = func(structs.NodeNetworkAF) (IPTables, error) {
+			return ipt, nil
+		}
+		return policies
+	}
+	policies := newPolicies()
+
+	add := func(alloc *structs.Allocation) {
+		must.NoError(t, policies.Add(alloc, addresses[alloc.ID]))
+		if alloc.Job.TaskGroups[0].NetworkPolicy.Denies() {
+			protected = append(protected, alloc.ID)
+		}
+	}
+	remove := func(alloc *structs.Allocation) {
+		protected = slices.DeleteFunc(protected, func(id string) bool {
+			return id == alloc.ID
+		})
+		must.NoError(t, policies.Remove(alloc.ID))
+	}
+
+	add(web)
+	add(batch)
+	add(web2)
+	add(api)
+	remove(web2)
+	remove(batch)
+
+	// A restarted client keeps enforcing the chains of the allocations it
+	// hasn't restored yet, and updates the existing chains in place
+	policies = newPolicies()
+	add(web)
+	add(web2)
+	must.SliceLen(t, 4, ipt.chains[networkPolicyChainName])
+	must.SliceContains(t, ipt.chains[networkPolicyAllocChain(web.ID)],
+		[]string{"-s", "172.26.64.3/32", "-j", "RETURN"})
+	must.SliceNotContains(t, ipt.chains[networkPolicyAllocChain(web.ID)],
+		[]string{"-s", "172.26.64.5/32", "-j", "RETURN"})
+
+	// Allocations removed before being restored are no longer enforced
+	remove(api)
+	must.MapNotContainsKey(t, ipt.chains, networkPolicyAllocChain(api.ID))
+
+	remove(web)
+	remove(web2)
+	must.Eq(t, [][]string{generateNetworkPolicyAllowRule()}, ipt.chains[networkPolicyChainName])
+}
+
+func TestNetworkPolicies_NamespacePolicies(t *testing.T) {
+	ci.Parallel(t)
+
+	ipt := newFakeIPTables()
+	policies := NewNetworkPolicies(testlog.HCLogger(t), "nomad").(*networkPolicies)
+	policies.newIPTables = func(structs.NodeNetworkAF) (IPTables, error) {
+		return ipt, nil
+	}
+
+	// Setting policies before allocations are tracked doesn't touch iptables
+	must.NoError(t, policies.SetNamespacePolicies(nil))
+	must.MapNotContainsKey(t, ipt.chains, networkPolicyChainName)
+
+	web := testNetworkPolicyAlloc("isolated", "web", nil)
+	api := testNetworkPolicyAlloc("default", "api", nil)
+	prometheus := testNetworkPolicyAlloc("ops", "prometheus", nil)
+	must.NoError(t, policies.Add(web, "172.26.64.2"))
+	must.NoError(t, policies.Add(api, "172.26.64.3"))
+	must.NoError(t, policies.Add(prometheus, "172.26.64.4"))
+	must.SliceLen(t, 1, ipt.chains[networkPolicyChainName])
+
+	// Denying traffic in the namespace applies to the running allocations
+	must.NoError(t, policies.SetNamespacePolicies(map[string]*structs.NetworkPolicy{
+		"isolated": {
+			Default: structs.NetworkPolicyDefaultDeny,
+			Allow:   []*structs.NetworkPolicyRule{{Namespace: "ops"}},
+		},
+	}))
+	webChain := networkPolicyAllocChain(web.ID)
+	must.Eq(t, [][]string{
+		generateNetworkPolicyAllowRule(),
+		{"-d", "172.26.64.2/32", "-j", webChain},
+	}, ipt.chains[networkPolicyChainName])
+	must.Eq(t, generateNetworkPolicyAllocRules([]string{"172.26.64.4"}), ipt.chains[webChain])
+
+	// Allocations without an address can't be isolated
+	must.ErrorContains(t, policies.Add(testNetworkPolicyAlloc("isolated", "batch", nil), ""),
+		"requires the allocation to have an IPv4 address")
+
+	// Removing the namespace policy allows all traffic again
+	must.NoError(t, policies.SetNamespacePolicies(nil))
+	must.MapNotContainsKey(t, ipt.chains, webChain)
+	must.SliceLen(t, 1, ipt.chains[networkPolicyChainName])
+}
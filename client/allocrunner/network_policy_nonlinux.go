@@ -0,0 +1,35 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+//go:build !linux
+// +build !linux
+
+package allocrunner
+
+import (
+	hclog "github.com/hashicorp/go-hclog"
+	cinterfaces "github.com/hashicorp/nomad/client/interfaces"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// noopNetworkPolicies implements the NetworkPolicies interface for systems
+// without a bridge network.
+type noopNetworkPolicies struct{}
+
+// NewNetworkPolicies returns a no-op network policies enforcer since bridge
+// networking is only supported on Linux.
+func NewNetworkPolicies(hclog.Logger, string) cinterfaces.NetworkPolicies {
+	return noopNetworkPolicies{}
+}
+
+func (noopNetworkPolicies) Add(*structs.Allocation, string) error {
+	return nil
+}
+
+func (noopNetworkPolicies) Remove(string) error {
+	return nil
+}
+
+func (noopNetworkPolicies) SetNamespacePolicies(map[string]*structs.NetworkPolicy) error {
+	return nil
+}
@@ -0,0 +1,125 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package allocrunner
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/plugins/drivers"
+)
+
+var (
+	// networkStatsBytesMeasured are the fields of the network stats measured
+	// on every sample.
+	networkStatsBytesMeasured = []string{"Rx Bytes", "Tx Bytes"}
+
+	// networkStatsMeasured are the fields of the network stats measured once
+	// a previous sample is available to compute the throughput.
+	networkStatsMeasured = []string{"Rx Bytes", "Tx Bytes", "Rx Mbits", "Tx Mbits"}
+)
+
+// networkStatsCollector samples the traffic of the network namespace of an
+// allocation to report its throughput in the allocation stats.
+type networkStatsCollector struct {
+	// readCounters returns the bytes received and transmitted by the
+	// interfaces of the network namespace at the given path
+	readCounters func(string) (uint64, uint64, error)
+
+	spec *drivers.NetworkIsolationSpec
+
+	// last is the previous sample, taken at lastAt
+	last   *cstructs.NetworkStats
+	lastAt time.Time
+
+	lock sync.Mutex
+}
+
+func newNetworkStatsCollector() *networkStatsCollector {
+	return &networkStatsCollector{
+		readCounters: readNetNSCounters,
+	}
+}
+
+// SetNetworkIsolation sets the network namespace of the allocation once it
+// has been created.
+func (c *networkStatsCollector) SetNetworkIsolation(spec *drivers.NetworkIsolationSpec) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.spec = spec
+	c.last = nil
+}
+
+// Latest returns the current network stats of the allocation, or nil if the
+// allocation has no network namespace or its traffic can't be read.
+func (c *networkStatsCollector) Latest() *cstructs.NetworkStats {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.spec == nil || c.spec.Mode != drivers.NetIsolationModeGroup || c.spec.Path == "" {
+		return nil
+	}
+
+	rx, tx, err := c.readCounters(c.spec.Path)
+	if err != nil {
+		return nil
+	}
+
+	now := time.Now()
+	stats := &cstructs.NetworkStats{
+		RxBytes:  rx,
+		TxBytes:  tx,
+		Measured: networkStatsBytesMeasured,
+	}
+	if c.last != nil && rx >= c.last.RxBytes && tx >= c.last.TxBytes {
+		if elapsed := now.Sub(c.lastAt).Seconds(); elapsed > 0 {
+			stats.RxMbits = float64(rx-c.last.RxBytes) * 8 / elapsed / 1_000_000
+			stats.TxMbits = float64(tx-c.last.TxBytes) * 8 / elapsed / 1_000_000
+			stats.Measured = networkStatsMeasured
+		}
+	}
+
+	c.last = stats
+	c.lastAt = now
+	return stats
+}
+
+// parseNetDev sums the bytes received and transmitted by all the interfaces
+// but the loopback listed in the /proc/net/dev format.
+func parseNetDev(data []byte) (rx, tx uint64, err error) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		iface, counters, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			// Header lines
+			continue
+		}
+		if strings.TrimSpace(iface) == "lo" {
+			continue
+		}
+
+		// Receive bytes is the first field and transmit bytes is the ninth
+		fields := strings.Fields(counters)
+		if len(fields) < 9 {
+			return 0, 0, fmt.Errorf("invalid counters for interface %q", strings.TrimSpace(iface))
+		}
+		ifaceRx, err := strconv.ParseUint(fields[0], 10, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid receive bytes for interface %q: %w", strings.TrimSpace(iface), err)
+		}
+		ifaceTx, err := strconv.ParseUint(fields[8], 10, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid transmit bytes for interface %q: %w", strings.TrimSpace(iface), err)
+		}
+		rx += ifaceRx
+		tx += ifaceTx
+	}
+	return rx, tx, scanner.Err()
+}
@@ -0,0 +1,26 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package allocrunner
+
+import (
+	"os"
+
+	"github.com/hashicorp/nomad/client/lib/nsutil"
+)
+
+// readNetNSCounters returns the bytes received and transmitted by the
+// interfaces of the network namespace at the given path. The counters are
+// read from the net/dev file of the current thread while it's switched to the
+// network namespace.
+func readNetNSCounters(path string) (rx, tx uint64, err error) {
+	err = nsutil.WithNetNSPath(path, func(nsutil.NetNS) error {
+		data, err := os.ReadFile("/proc/thread-self/net/dev")
+		if err != nil {
+			return err
+		}
+		rx, tx, err = parseNetDev(data)
+		return err
+	})
+	return rx, tx, err
+}
@@ -0,0 +1,15 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+//go:build !linux
+// +build !linux
+
+package allocrunner
+
+import "errors"
+
+// readNetNSCounters always fails since network namespaces are only supported
+// on Linux.
+func readNetNSCounters(string) (uint64, uint64, error) {
+	return 0, 0, errors.New("network namespaces are not supported on this platform")
+}
@@ -0,0 +1,71 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package allocrunner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/plugins/drivers"
+	"github.com/shoenig/test/must"
+)
+
+func TestNetworkStats_parseNetDev(t *testing.T) {
+	ci.Parallel(t)
+
+	data := []byte(`Inter-|   Receive                                                |  Transmit
+ face |bytes    packets errs drop fifo frame compressed multicast|bytes    packets errs drop fifo colls carrier compressed
+    lo:    1000      10    0    0    0     0          0         0     1000      10    0    0    0     0       0          0
+  eth0: 2000000    1500    0    0    0     0          0         0   500000     800    0    0    0     0       0          0
+  eth1:     100       1    0    0    0     0          0         0       50       1    0    0    0     0       0          0
+`)
+	rx, tx, err := parseNetDev(data)
+	must.NoError(t, err)
+	must.Eq(t, 2000100, rx)
+	must.Eq(t, 500050, tx)
+
+	_, _, err = parseNetDev([]byte("eth0: 1 2 3\n"))
+	must.ErrorContains(t, err, `invalid counters for interface "eth0"`)
+}
+
+func TestNetworkStats_Latest(t *testing.T) {
+	ci.Parallel(t)
+
+	var rx, tx uint64
+	c := newNetworkStatsCollector()
+	c.readCounters = func(path string) (uint64, uint64, error) {
+		must.Eq(t, "/var/run/netns/alloc", path)
+		return rx, tx, nil
+	}
+
+	// No stats until the network namespace is created
+	must.Nil(t, c.Latest())
+
+	c.SetNetworkIsolation(&drivers.NetworkIsolationSpec{
+		Mode: drivers.NetIsolationModeHost,
+	})
+	must.Nil(t, c.Latest())
+
+	c.SetNetworkIsolation(&drivers.NetworkIsolationSpec{
+		Mode: drivers.NetIsolationModeGroup,
+		Path: "/var/run/netns/alloc",
+	})
+
+	rx, tx = 1_000_000, 2_000_000
+	stats := c.Latest()
+	must.NotNil(t, stats)
+	must.Eq(t, 1_000_000, stats.RxBytes)
+	must.Eq(t, 2_000_000, stats.TxBytes)
+	must.Eq(t, networkStatsBytesMeasured, stats.Measured)
+
+	// The throughput is computed from the previous sample
+	c.lastAt = time.Now().Add(-time.Second)
+	rx, tx = 2_000_000, 4_000_000
+	stats = c.Latest()
+	must.NotNil(t, stats)
+	must.Eq(t, networkStatsMeasured, stats.Measured)
+	must.Between(t, 7, stats.RxMbits, 8)
+	must.Between(t, 15, stats.TxMbits, 16)
+}
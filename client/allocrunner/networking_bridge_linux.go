@@ -37,6 +37,10 @@ type bridgeNetworkConfigurator struct {
 	bridgeName      string
 	hairpinMode     bool
 
+	// bandwidth is whether the bandwidth plugin is added to the bridge
+	// config to limit the rate of the allocation network.
+	bandwidth bool
+
 	newIPTables func(structs.NodeNetworkAF) (IPTablesChain, error)
 
 	logger hclog.Logger
@@ -64,6 +68,7 @@ func newBridgeNetworkConfigurator(log hclog.Logger, alloc *structs.Allocation, b
 	var err error
 
 	tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup)
+	b.bandwidth = getBandwidth(tg) != nil
 	for _, svc := range tg.Services {
 		if svc.Connect.HasTransparentProxy() {
 			netCfg, err = buildNomadBridgeNetConfig(*b, true)
@@ -141,6 +146,7 @@ func buildNomadBridgeNetConfig(b bridgeNetworkConfigurator, withConsulCNI bool)
 		IPv6Subnet:     b.allocSubnetIPv6,
 		HairpinMode:    b.hairpinMode,
 		ConsulCNI:      withConsulCNI,
+		Bandwidth:      b.bandwidth,
 	})
 	return conf.Json()
 }
@@ -55,6 +55,14 @@ func Test_buildNomadBridgeNetConfig(t *testing.T) {
 				hairpinMode:     true,
 			},
 		},
+		{
+			name: "bandwidth",
+			b: &bridgeNetworkConfigurator{
+				bridgeName:      defaultNomadBridgeName,
+				allocSubnetIPv4: defaultNomadAllocSubnet,
+				bandwidth:       true,
+			},
+		},
 		{
 			name:          "consul-cni",
 			withConsulCNI: true,
@@ -170,6 +170,7 @@ func (c *cniNetworkConfigurator) Setup(ctx context.Context, alloc *structs.Alloc
 	addNomadWorkloadCNIArgs(c.logger, alloc, cniArgs)
 
 	portMaps := getPortMapping(alloc, c.ignorePortMappingHostIP)
+	bandwidth := getBandwidth(tg)
 
 	tproxyArgs, err := c.setupTransparentProxyArgs(alloc, spec, portMaps)
 	if err != nil {
@@ -195,6 +196,7 @@ func (c *cniNetworkConfigurator) Setup(ctx context.Context, alloc *structs.Alloc
 		if err == nil && supportsCNICheck.Check(cniVersion) {
 			err := c.cni.Check(ctx, alloc.ID, spec.Path,
 				c.nsOpts.withCapabilityPortMap(portMaps.ports),
+				c.nsOpts.withCapabilityBandwidth(bandwidth),
 				c.nsOpts.withArgs(cniArgs),
 			)
 			if err != nil {
@@ -218,6 +220,7 @@ func (c *cniNetworkConfigurator) Setup(ctx context.Context, alloc *structs.Alloc
 		var err error
 		if res, err = c.cni.Setup(ctx, alloc.ID, spec.Path,
 			c.nsOpts.withCapabilityPortMap(portMaps.ports),
+			c.nsOpts.withCapabilityBandwidth(bandwidth),
 			c.nsOpts.withArgs(cniArgs),
 		); err != nil {
 			c.logger.Warn("failed to configure network", "error", err, "attempt", attempt)
@@ -587,8 +590,12 @@ func (c *cniNetworkConfigurator) Teardown(ctx context.Context, alloc *structs.Al
 	}
 
 	portMap := getPortMapping(alloc, c.ignorePortMappingHostIP)
+	bandwidth := getBandwidth(alloc.Job.LookupTaskGroup(alloc.TaskGroup))
 
-	if err := c.cni.Remove(ctx, alloc.ID, spec.Path, cni.WithCapabilityPortMap(portMap.ports)); err != nil {
+	if err := c.cni.Remove(ctx, alloc.ID, spec.Path,
+		cni.WithCapabilityPortMap(portMap.ports),
+		c.nsOpts.withCapabilityBandwidth(bandwidth),
+	); err != nil {
 		c.logger.Warn("error from cni.Remove; attempting manual iptables cleanup", "err", err)
 
 		// best effort cleanup ipv6
@@ -704,8 +711,9 @@ func (c *cniNetworkConfigurator) ensureCNIInitialized() error {
 
 // nsOpts keeps track of NamespaceOpts usage, mainly for test assertions.
 type nsOpts struct {
-	args  map[string]string
-	ports []cni.PortMapping
+	args      map[string]string
+	ports     []cni.PortMapping
+	bandwidth *cni.BandWidth
 }
 
 func (o *nsOpts) withArgs(args map[string]string) cni.NamespaceOpts {
@@ -718,6 +726,16 @@ func (o *nsOpts) withCapabilityPortMap(ports []cni.PortMapping) cni.NamespaceOpt
 	return cni.WithCapabilityPortMap(ports)
 }
 
+// withCapabilityBandwidth passes the bandwidth capability to the plugins if
+// the allocation network has a bandwidth limit, and is a no-op otherwise.
+func (o *nsOpts) withCapabilityBandwidth(bandwidth *cni.BandWidth) cni.NamespaceOpts {
+	o.bandwidth = bandwidth
+	if bandwidth == nil {
+		return func(*cni.Namespace) error { return nil }
+	}
+	return cni.WithCapabilityBandWidth(*bandwidth)
+}
+
 // portMappings is a wrapper around a slice of cni.PortMapping that lets us
 // index via the port's label, which isn't otherwise included in the
 // cni.PortMapping struct
@@ -784,3 +802,30 @@ func getPortMapping(alloc *structs.Allocation, ignoreHostIP bool) *portMappings
 	}
 	return mappings
 }
+
+// getBandwidth builds the bandwidth capability arguments for the bandwidth CNI
+// plugin, limiting both the ingress and egress rate of the allocation to the
+// mbits of its group network. It returns nil if the group network has no
+// bandwidth set.
+func getBandwidth(tg *structs.TaskGroup) *cni.BandWidth {
+	var mbits int
+	if tg != nil {
+		for _, network := range tg.Networks {
+			mbits += network.MBits
+		}
+	}
+	if mbits <= 0 {
+		return nil
+	}
+
+	// The plugin expects rates and bursts in bits, and bursts to be set when
+	// rates are. Allow bursts of a tenth of a second of traffic.
+	rate := uint64(mbits) * 1_000_000
+	burst := rate / 10
+	return &cni.BandWidth{
+		IngressRate:  rate,
+		IngressBurst: burst,
+		EgressRate:   rate,
+		EgressBurst:  burst,
+	}
+}
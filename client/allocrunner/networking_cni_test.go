@@ -129,6 +129,8 @@ func TestSetup(t *testing.T) {
 		expectResult *structs.AllocNetworkStatus
 		expectErr    string
 		expectArgs   map[string]string
+
+		expectBandwidth *cni.BandWidth
 	}{
 		{
 			name: "defaults",
@@ -161,6 +163,31 @@ func TestSetup(t *testing.T) {
 				"NOMAD_REGION":     "global",
 			},
 		},
+		{
+			name: "with bandwidth",
+			modAlloc: func(a *structs.Allocation) {
+				tg := a.Job.LookupTaskGroup(a.TaskGroup)
+				tg.Networks[0].MBits = 100
+			},
+			expectResult: &structs.AllocNetworkStatus{
+				InterfaceName: "eth0",
+				Address:       "99.99.99.99",
+			},
+			expectArgs: map[string]string{
+				"IgnoreUnknown":    "true",
+				"NOMAD_ALLOC_ID":   "7cd08c6c-86c8-0bfa-f7ca-338466447711",
+				"NOMAD_GROUP_NAME": "web",
+				"NOMAD_JOB_ID":     "mock-service",
+				"NOMAD_NAMESPACE":  "default",
+				"NOMAD_REGION":     "global",
+			},
+			expectBandwidth: &cni.BandWidth{
+				IngressRate:  100_000_000,
+				IngressBurst: 10_000_000,
+				EgressRate:   100_000_000,
+				EgressBurst:  10_000_000,
+			},
+		},
 		{
 			name:        "error too many times",
 			setupErrors: []string{"sad day", "sad again", "the last straw"},
@@ -301,6 +328,7 @@ func TestSetup(t *testing.T) {
 				must.NoError(t, err)
 				must.Eq(t, tc.expectResult, result)
 				must.Eq(t, tc.expectArgs, c.nsOpts.args)
+				must.Eq(t, tc.expectBandwidth, c.nsOpts.bandwidth)
 				expectCalls := len(tc.setupErrors) + 1
 				must.Eq(t, fakePlugin.counter.Get()["Setup"], expectCalls,
 					must.Sprint("unexpected call count"))
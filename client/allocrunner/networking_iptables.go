@@ -7,6 +7,9 @@ package allocrunner
 
 import (
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
 
 	"github.com/coreos/go-iptables/iptables"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -16,6 +19,24 @@ const (
 	// cniAdminChainName is the name of the admin iptables chain used to allow
 	// forwarding traffic to allocations
 	cniAdminChainName = "NOMAD-ADMIN"
+
+	// networkPolicyChainName is the name of the iptables chain that sends the
+	// traffic between allocations on the bridge network to the chain of the
+	// network policy of the destination allocation
+	networkPolicyChainName = "NOMAD-NETPOL"
+
+	// networkPolicyAllowChainName is the name of the iptables chain operators
+	// can add rules to in order to accept traffic to allocations regardless of
+	// their network policy, such as the traffic of an ingress load balancer.
+	// Nomad creates it but never modifies its rules.
+	networkPolicyAllowChainName = "NOMAD-NETPOL-ALLOW"
+
+	// networkPolicyAllocChainPrefix is the prefix of the iptables chains
+	// enforcing the network policy of each allocation
+	networkPolicyAllocChainPrefix = "NOMAD-NP-"
+
+	// maxChainNameLen is the maximum length of the names of iptables chains
+	maxChainNameLen = 28
 )
 
 // newIPTables provides an *iptables.IPTables for the requested address family
@@ -37,6 +58,7 @@ func newIPTablesChain(family structs.NodeNetworkAF) (IPTablesChain, error) {
 type IPTables interface {
 	IPTablesCleanup
 	IPTablesChain
+	IPTablesPolicy
 }
 type IPTablesCleanup interface {
 	List(table, chain string) ([]string, error)
@@ -49,6 +71,9 @@ type IPTablesChain interface {
 	Exists(table string, chain string, rulespec ...string) (bool, error)
 	Append(table string, chain string, rulespec ...string) error
 }
+type IPTablesPolicy interface {
+	Insert(table, chain string, pos int, rulespec ...string) error
+}
 
 // ensureChainRule ensures our admin chain exists and contains a rule to accept
 // traffic to the bridge network
@@ -100,3 +125,186 @@ func appendChainRule(ipt IPTablesChain, chain string, rule []string) error {
 func generateAdminChainRule(bridgeName, subnet string) []string {
 	return []string{"-o", bridgeName, "-d", subnet, "-j", "ACCEPT"}
 }
+
+// networkPolicyRules are the rules of the network policy chains, by chain
+type networkPolicyRules map[string][][]string
+
+// networkPolicyAllocChain returns the name of the chain enforcing the network
+// policy of the allocation. Chain names are limited to 28 characters so only
+// as much of the allocation ID as fits is used.
+func networkPolicyAllocChain(allocID string) string {
+	if n := maxChainNameLen - len(networkPolicyAllocChainPrefix); len(allocID) > n {
+		allocID = allocID[:n]
+	}
+	return networkPolicyAllocChainPrefix + allocID
+}
+
+// generateNetworkPolicyJumpRule builds the iptables rule that is inserted into
+// the FORWARD chain to send all the traffic forwarded to the bridge network,
+// whether from other allocations, the host or other networks, to the network
+// policy chain
+func generateNetworkPolicyJumpRule(bridgeName string) []string {
+	return []string{"-o", bridgeName, "-j", networkPolicyChainName}
+}
+
+// generateNetworkPolicyAllowRule builds the first iptables rule of the network
+// policy chain that sends the traffic to the allow-list chain, so that the
+// traffic accepted by operators skips the network policies of the allocations
+func generateNetworkPolicyAllowRule() []string {
+	return []string{"-j", networkPolicyAllowChainName}
+}
+
+// generateNetworkPolicyDispatchRule builds the iptables rule of the network
+// policy chain that sends the traffic to the allocation to its own chain
+func generateNetworkPolicyDispatchRule(address, allocChain string) []string {
+	return []string{"-d", address + "/32", "-j", allocChain}
+}
+
+// generateNetworkPolicyAllocRules builds the iptables rules of the chain of an
+// allocation that denies traffic by default. Replies to connections opened by
+// the allocation and traffic from the allowed peers is returned to the
+// FORWARD chain, and everything else is dropped.
+func generateNetworkPolicyAllocRules(peers []string) [][]string {
+	rules := [][]string{
+		{"-m", "conntrack", "--ctstate", "RELATED,ESTABLISHED", "-j", "RETURN"},
+	}
+	for _, peer := range peers {
+		rules = append(rules, []string{"-s", peer + "/32", "-j", "RETURN"})
+	}
+	return append(rules, []string{"-j", "DROP"})
+}
+
+// syncNetworkPolicyRules updates the network policy chains so they contain the
+// desired rules. Chains whose rules were already applied are left untouched,
+// and the chains of allocations that are no longer tracked are deleted. The
+// rules of the chains are read from iptables if the applied rules are unknown.
+func syncNetworkPolicyRules(ipt IPTables, bridgeName string, desired, applied networkPolicyRules) error {
+	if err := ensureChain(ipt, "filter", networkPolicyChainName); err != nil {
+		return err
+	}
+	if err := ensureChain(ipt, "filter", networkPolicyAllowChainName); err != nil {
+		return err
+	}
+
+	// The jump rule must come before the rules accepting the traffic of the
+	// bridge network
+	jump := generateNetworkPolicyJumpRule(bridgeName)
+	exists, err := ipt.Exists("filter", "FORWARD", jump...)
+	if err != nil {
+		return fmt.Errorf("failed to check iptables rule: %v", err)
+	}
+	if !exists {
+		if err := ipt.Insert("filter", "FORWARD", 1, jump...); err != nil {
+			return fmt.Errorf("failed to insert iptables rule: %v", err)
+		}
+	}
+
+	// Update the chains of the allocations before the network policy chain
+	// so that traffic is never sent to a missing chain
+	chains := slices.Sorted(maps.Keys(desired))
+	chains = append(slices.DeleteFunc(chains, func(chain string) bool {
+		return chain == networkPolicyChainName
+	}), networkPolicyChainName)
+
+	for _, chain := range chains {
+		if err := ensureChain(ipt, "filter", chain); err != nil {
+			return err
+		}
+		current := applied[chain]
+		if applied == nil {
+			current, err = listChainRules(ipt, chain)
+			if err != nil {
+				return err
+			}
+		}
+		if slices.EqualFunc(current, desired[chain], slices.Equal) {
+			continue
+		}
+		if err := updateChainRules(ipt, chain, current, desired[chain]); err != nil {
+			return err
+		}
+	}
+
+	existing, err := ipt.ListChains("filter")
+	if err != nil {
+		return fmt.Errorf("failed to list iptables chains: %v", err)
+	}
+	for _, chain := range existing {
+		if _, ok := desired[chain]; ok || !strings.HasPrefix(chain, networkPolicyAllocChainPrefix) {
+			continue
+		}
+		if err := ipt.ClearAndDeleteChain("filter", chain); err != nil {
+			return fmt.Errorf("failed to delete iptables chain %s: %v", chain, err)
+		}
+	}
+
+	return nil
+}
+
+// listChainRules returns the rules of the chain in the filter table.
+func listChainRules(ipt IPTables, chain string) ([][]string, error) {
+	lines, err := ipt.List("filter", chain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list iptables rules of chain %s: %v", chain, err)
+	}
+
+	// Rules are listed in the iptables-save format, "-A <chain> <rulespec>"
+	prefix := "-A " + chain + " "
+	var rules [][]string
+	for _, line := range lines {
+		if rule, ok := strings.CutPrefix(line, prefix); ok {
+			rules = append(rules, strings.Fields(rule))
+		}
+	}
+	return rules, nil
+}
+
+// updateChainRules updates the rules of the chain from the current rules to
+// the desired rules without ever clearing the chain, so that the chain keeps
+// denying traffic while it's updated. Missing rules are inserted before the
+// next desired rule already in the chain, or appended, and the rules that are
+// no longer desired are deleted last.
+func updateChainRules(ipt IPTables, chain string, current, desired [][]string) error {
+	current = slices.Clone(current)
+	contains := func(rules [][]string, rule []string) int {
+		return slices.IndexFunc(rules, func(r []string) bool {
+			return slices.Equal(r, rule)
+		})
+	}
+
+	for i, rule := range desired {
+		if contains(current, rule) >= 0 {
+			continue
+		}
+
+		pos := -1
+		for _, next := range desired[i+1:] {
+			if pos = contains(current, next); pos >= 0 {
+				break
+			}
+		}
+		if pos < 0 {
+			if err := ipt.Append("filter", chain, rule...); err != nil {
+				return fmt.Errorf("failed to append iptables rule to chain %s: %v", chain, err)
+			}
+			current = append(current, rule)
+			continue
+		}
+
+		// iptables rule positions start at 1
+		if err := ipt.Insert("filter", chain, pos+1, rule...); err != nil {
+			return fmt.Errorf("failed to insert iptables rule to chain %s: %v", chain, err)
+		}
+		current = slices.Insert(current, pos, rule)
+	}
+
+	for _, rule := range current {
+		if contains(desired, rule) >= 0 {
+			continue
+		}
+		if err := ipt.Delete("filter", chain, rule...); err != nil {
+			return fmt.Errorf("failed to delete iptables rule from chain %s: %v", chain, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package allocrunner
+
+import (
+	"time"
+
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// taskHealthInterval is how often the health of the tasks that other tasks
+// depend on to be healthy is checked.
+const taskHealthInterval = time.Second
+
+// watchTaskHealth periodically notifies the task coordinator of the health of
+// the given tasks until the alloc runner exits. A task is healthy when it is
+// running and all the Nomad checks of its services are passing.
+func (ar *allocRunner) watchTaskHealth(tasks []string) {
+	timer, stop := helper.NewSafeTimer(0)
+	defer stop()
+
+	for {
+		select {
+		case <-ar.waitCh:
+			return
+		case <-timer.C:
+		}
+
+		var results map[structs.CheckID]*structs.CheckQueryResult
+		if ar.checkStore != nil {
+			results = ar.checkStore.List(ar.id)
+		}
+
+		for _, name := range tasks {
+			ar.taskCoordinator.TaskHealthUpdated(name, ar.taskHealthy(name, results))
+		}
+
+		timer.Reset(taskHealthInterval)
+	}
+}
+
+// taskHealthy returns whether the task is running and all the Nomad checks of
+// its services are passing given the latest check results.
+func (ar *allocRunner) taskHealthy(name string, results map[structs.CheckID]*structs.CheckQueryResult) bool {
+	tr, ok := ar.tasks[name]
+	if !ok || tr.TaskState().State != structs.TaskStateRunning {
+		return false
+	}
+
+	checks := 0
+	for _, service := range tr.Task().Services {
+		if service.Provider == structs.ServiceProviderNomad {
+			checks += len(service.Checks)
+		}
+	}
+
+	passing := 0
+	for _, result := range results {
+		if result.Task == name && result.Status == structs.CheckSuccess {
+			passing++
+		}
+	}
+	return passing >= checks
+}
@@ -94,6 +94,14 @@ type Coordinator struct {
 
 	// gates store the gates that control each task lifecycle stage.
 	gates map[lifecycleStage]*Gate
+
+	// gatesOpen tracks which lifecycle stage gates are open. It must only be
+	// accessed while holding the lock.
+	gatesOpen map[lifecycleStage]bool
+
+	// deps tracks the tasks that depend on other tasks of the group, which
+	// have their own gate.
+	deps *dependencies
 }
 
 // NewCoordinator returns a new Coordinator with all tasks initially blocked.
@@ -102,6 +110,8 @@ func NewCoordinator(logger hclog.Logger, tasks []*structs.Task, shutdownCh <-cha
 		logger:           logger.Named("task_coordinator"),
 		tasksByLifecycle: indexTasksByLifecycle(tasks),
 		gates:            make(map[lifecycleStage]*Gate),
+		gatesOpen:        make(map[lifecycleStage]bool),
+		deps:             newDependencies(tasks, shutdownCh),
 	}
 
 	for lifecycle := range c.tasksByLifecycle {
@@ -118,6 +128,7 @@ func NewCoordinator(logger hclog.Logger, tasks []*structs.Task, shutdownCh <-cha
 func (c *Coordinator) Restart() {
 	c.currentStateLock.Lock()
 	defer c.currentStateLock.Unlock()
+	c.deps.reset()
 	c.enterStateLocked(coordinatorStateInit)
 }
 
@@ -128,16 +139,31 @@ func (c *Coordinator) Restore(states map[string]*structs.TaskState) {
 	// running, causing the Coordinator to be stuck waiting for them to be
 	// "pending".
 	c.enterStateLocked(coordinatorStatePrestart)
+	c.deps.restore(states)
 	c.TaskStateUpdated(states)
 }
 
 // StartConditionForTask returns a channel that is unblocked when the task is
 // allowed to run.
 func (c *Coordinator) StartConditionForTask(task *structs.Task) <-chan struct{} {
+	if gate := c.deps.gate(task.Name); gate != nil {
+		return gate.WaitCh()
+	}
 	lifecycle := taskLifecycleStage(task)
 	return c.gates[lifecycle].WaitCh()
 }
 
+// TaskHealthUpdated notifies that the health of a task that other tasks
+// depend on to be healthy has changed. This may allow those tasks to start.
+func (c *Coordinator) TaskHealthUpdated(task string, healthy bool) {
+	c.currentStateLock.Lock()
+	defer c.currentStateLock.Unlock()
+
+	if c.deps.setHealth(task, healthy) {
+		c.deps.update(c.gatesOpen)
+	}
+}
+
 // TaskStateUpdated notifies that a task state has changed. This may cause the
 // Coordinator to transition to another state.
 func (c *Coordinator) TaskStateUpdated(states map[string]*structs.TaskState) {
@@ -149,6 +175,9 @@ func (c *Coordinator) TaskStateUpdated(states map[string]*structs.TaskState) {
 	// so loop until we stabilize.
 	// This is also important when restoring an alloc since we need to find the
 	// state where FSM was last positioned.
+	c.deps.setStates(states)
+	defer c.deps.update(c.gatesOpen)
+
 	for {
 		nextState := c.nextStateLocked(states)
 		if nextState == c.currentState {
@@ -272,6 +301,7 @@ func (c *Coordinator) enterStateLocked(state coordinatorState) {
 	}
 
 	c.currentState = state
+	c.deps.update(c.gatesOpen)
 }
 
 // isInitDone returns true when the following conditions are met:
@@ -381,6 +411,7 @@ func (c *Coordinator) block(lifecycle lifecycleStage) {
 	if gate != nil {
 		gate.Close()
 	}
+	c.gatesOpen[lifecycle] = false
 }
 
 // allows is used to allow the execution of tasks in the given lifecycle stage.
@@ -389,6 +420,7 @@ func (c *Coordinator) allow(lifecycle lifecycleStage) {
 	if gate != nil {
 		gate.Open()
 	}
+	c.gatesOpen[lifecycle] = true
 }
 
 // indexTasksByLifecycle generates a map that groups tasks by their lifecycle
@@ -561,3 +561,120 @@ func TestCoordinator_Restore(t *testing.T) {
 		})
 	}
 }
+
+func TestCoordinator_TaskDependencies(t *testing.T) {
+	ci.Parallel(t)
+
+	logger := testlog.HCLogger(t)
+	task := mock.Job().TaskGroups[0].Tasks[0]
+
+	db := task.Copy()
+	db.Name = "db"
+
+	migrate := task.Copy()
+	migrate.Name = "migrate"
+	migrate.DependsOn = []*structs.TaskDependency{
+		{Task: db.Name, Condition: structs.TaskDependencyConditionHealthy},
+	}
+
+	api := task.Copy()
+	api.Name = "api"
+	api.DependsOn = []*structs.TaskDependency{
+		{Task: db.Name, Condition: structs.TaskDependencyConditionStarted},
+		{Task: migrate.Name, Condition: structs.TaskDependencyConditionCompleted},
+	}
+
+	tasks := []*structs.Task{db, migrate, api}
+
+	shutdownCh := make(chan struct{})
+	defer close(shutdownCh)
+	coord := NewCoordinator(logger, tasks, shutdownCh)
+
+	// All tasks start blocked.
+	RequireTaskBlocked(t, coord, db)
+	RequireTaskBlocked(t, coord, migrate)
+	RequireTaskBlocked(t, coord, api)
+
+	// Only the task without dependencies is allowed to run.
+	states := map[string]*structs.TaskState{
+		db.Name:      {State: structs.TaskStatePending},
+		migrate.Name: {State: structs.TaskStatePending},
+		api.Name:     {State: structs.TaskStatePending},
+	}
+	coord.TaskStateUpdated(states)
+	RequireTaskAllowed(t, coord, db)
+	RequireTaskBlocked(t, coord, migrate)
+	RequireTaskBlocked(t, coord, api)
+
+	// db is running but not healthy yet.
+	states = map[string]*structs.TaskState{
+		db.Name:      {State: structs.TaskStateRunning, StartedAt: time.Now()},
+		migrate.Name: {State: structs.TaskStatePending},
+		api.Name:     {State: structs.TaskStatePending},
+	}
+	coord.TaskStateUpdated(states)
+	RequireTaskAllowed(t, coord, db)
+	RequireTaskBlocked(t, coord, migrate)
+	RequireTaskBlocked(t, coord, api)
+
+	// db is healthy, migrate is allowed to run.
+	coord.TaskHealthUpdated(db.Name, true)
+	RequireTaskAllowed(t, coord, migrate)
+	RequireTaskBlocked(t, coord, api)
+
+	// migrate stays allowed to run once db becomes unhealthy.
+	coord.TaskHealthUpdated(db.Name, false)
+	RequireTaskAllowed(t, coord, migrate)
+
+	// migrate completed, api is allowed to run.
+	states = map[string]*structs.TaskState{
+		db.Name:      {State: structs.TaskStateRunning, StartedAt: time.Now()},
+		migrate.Name: {State: structs.TaskStateDead, StartedAt: time.Now()},
+		api.Name:     {State: structs.TaskStatePending},
+	}
+	coord.TaskStateUpdated(states)
+	RequireTaskAllowed(t, coord, db)
+	RequireTaskAllowed(t, coord, migrate)
+	RequireTaskAllowed(t, coord, api)
+
+	// Restarting the allocation blocks the tasks with dependencies again.
+	coord.Restart()
+	states = map[string]*structs.TaskState{
+		db.Name:      {State: structs.TaskStatePending},
+		migrate.Name: {State: structs.TaskStatePending},
+		api.Name:     {State: structs.TaskStatePending},
+	}
+	coord.TaskStateUpdated(states)
+	RequireTaskAllowed(t, coord, db)
+	RequireTaskBlocked(t, coord, migrate)
+	RequireTaskBlocked(t, coord, api)
+}
+
+func TestCoordinator_TaskDependencies_Restore(t *testing.T) {
+	ci.Parallel(t)
+
+	logger := testlog.HCLogger(t)
+	task := mock.Job().TaskGroups[0].Tasks[0]
+
+	db := task.Copy()
+	db.Name = "db"
+
+	api := task.Copy()
+	api.Name = "api"
+	api.DependsOn = []*structs.TaskDependency{
+		{Task: db.Name, Condition: structs.TaskDependencyConditionHealthy},
+	}
+
+	shutdownCh := make(chan struct{})
+	defer close(shutdownCh)
+	coord := NewCoordinator(logger, []*structs.Task{db, api}, shutdownCh)
+
+	// api was already running before the client restarted, so it must not
+	// wait for db to be healthy again.
+	coord.Restore(map[string]*structs.TaskState{
+		db.Name:  {State: structs.TaskStateRunning, StartedAt: time.Now()},
+		api.Name: {State: structs.TaskStateRunning, StartedAt: time.Now()},
+	})
+	RequireTaskAllowed(t, coord, db)
+	RequireTaskAllowed(t, coord, api)
+}
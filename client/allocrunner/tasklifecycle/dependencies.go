@@ -0,0 +1,158 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package tasklifecycle
+
+import (
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// dependencies gates the tasks that depend on other tasks of the group. A
+// task with dependencies is allowed to run once the gate of its lifecycle
+// stage is open and all its dependencies are satisfied. Dependencies stay
+// satisfied once they are, so a task doesn't block again if an upstream task
+// later exits, until the allocation is restarted.
+//
+// The dependencies must only be accessed while holding the Coordinator lock.
+type dependencies struct {
+	// tasks are the tasks with dependencies, by name.
+	tasks map[string]*structs.Task
+
+	// gates store the gates that control each task with dependencies.
+	gates map[string]*Gate
+
+	// open tracks which gates are open.
+	open map[string]bool
+
+	// satisfied tracks the tasks whose dependencies have been satisfied.
+	satisfied map[string]bool
+
+	// states are the latest states of the tasks of the group.
+	states map[string]*structs.TaskState
+
+	// healthy tracks the health of the tasks that other tasks depend on to
+	// be healthy.
+	healthy map[string]bool
+}
+
+func newDependencies(tasks []*structs.Task, shutdownCh <-chan struct{}) *dependencies {
+	d := &dependencies{
+		tasks:     make(map[string]*structs.Task),
+		gates:     make(map[string]*Gate),
+		open:      make(map[string]bool),
+		satisfied: make(map[string]bool),
+		healthy:   make(map[string]bool),
+	}
+	for _, task := range tasks {
+		if len(task.DependsOn) == 0 {
+			continue
+		}
+		d.tasks[task.Name] = task
+		d.gates[task.Name] = NewGate(shutdownCh)
+	}
+	return d
+}
+
+// gate returns the gate of the task, or nil if it has no dependencies.
+func (d *dependencies) gate(task string) *Gate {
+	return d.gates[task]
+}
+
+// reset marks all dependencies as unsatisfied so tasks wait for them again
+// when the allocation is restarted.
+func (d *dependencies) reset() {
+	clear(d.satisfied)
+	clear(d.healthy)
+	d.states = nil
+}
+
+// restore marks the dependencies of the tasks that already started as
+// satisfied, since they were satisfied before the client restarted.
+func (d *dependencies) restore(states map[string]*structs.TaskState) {
+	for name := range d.tasks {
+		if state, ok := states[name]; ok && state.State != structs.TaskStatePending {
+			d.satisfied[name] = true
+		}
+	}
+}
+
+// setStates records the latest states of the tasks.
+func (d *dependencies) setStates(states map[string]*structs.TaskState) {
+	d.states = states
+}
+
+// setHealth records the health of the task and returns whether it changed.
+func (d *dependencies) setHealth(task string, healthy bool) bool {
+	if d.healthy[task] == healthy {
+		return false
+	}
+	d.healthy[task] = healthy
+	return true
+}
+
+// update opens the gates of the tasks allowed to run given the open lifecycle
+// stage gates and the state of their dependencies, and closes the others.
+func (d *dependencies) update(stagesOpen map[lifecycleStage]bool) {
+	for name, task := range d.tasks {
+		if !d.satisfied[name] && d.met(task) {
+			d.satisfied[name] = true
+		}
+
+		open := stagesOpen[taskLifecycleStage(task)] && d.satisfied[name]
+		if open == d.open[name] {
+			continue
+		}
+		if open {
+			d.gates[name].Open()
+		} else {
+			d.gates[name].Close()
+		}
+		d.open[name] = open
+	}
+}
+
+// met returns whether all the dependencies of the task are met by the
+// current state of the upstream tasks.
+func (d *dependencies) met(task *structs.Task) bool {
+	for _, dep := range task.DependsOn {
+		state := d.states[dep.Task]
+		if state == nil {
+			return false
+		}
+
+		switch dep.Condition {
+		case structs.TaskDependencyConditionHealthy:
+			if state.State != structs.TaskStateRunning || !d.healthy[dep.Task] {
+				return false
+			}
+		case structs.TaskDependencyConditionCompleted:
+			if !state.Successful() {
+				return false
+			}
+		default:
+			if state.StartedAt.IsZero() {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// HealthDependencies returns the names of the tasks that other tasks depend on
+// to be healthy.
+func HealthDependencies(tasks []*structs.Task) []string {
+	var names []string
+	seen := make(map[string]struct{})
+	for _, task := range tasks {
+		for _, dep := range task.DependsOn {
+			if dep.Condition != structs.TaskDependencyConditionHealthy {
+				continue
+			}
+			if _, ok := seen[dep.Task]; !ok {
+				seen[dep.Task] = struct{}{}
+				names = append(names, dep.Task)
+			}
+		}
+	}
+	return names
+}
@@ -16,6 +16,7 @@ import (
 	plugin "github.com/hashicorp/go-plugin"
 	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
 	"github.com/hashicorp/nomad/client/logmon"
+	"github.com/hashicorp/nomad/client/logmon/sinks"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/structs"
 	bstructs "github.com/hashicorp/nomad/plugins/base/structs"
@@ -192,14 +193,27 @@ func (h *logmonHook) prestartOneLoop(ctx context.Context, req *interfaces.TaskPr
 		}
 	}
 
+	alloc := h.runner.Alloc()
 	err := h.logmon.Start(&logmon.LogConfig{
-		LogDir:        h.config.logDir,
-		StdoutLogFile: fmt.Sprintf("%s.stdout", req.Task.Name),
-		StderrLogFile: fmt.Sprintf("%s.stderr", req.Task.Name),
-		StdoutFifo:    h.config.stdoutFifo,
-		StderrFifo:    h.config.stderrFifo,
-		MaxFiles:      req.Task.LogConfig.MaxFiles,
-		MaxFileSizeMB: req.Task.LogConfig.MaxFileSizeMB,
+		LogDir:         h.config.logDir,
+		StdoutLogFile:  fmt.Sprintf("%s.stdout", req.Task.Name),
+		StderrLogFile:  fmt.Sprintf("%s.stderr", req.Task.Name),
+		StdoutFifo:     h.config.stdoutFifo,
+		StderrFifo:     h.config.stderrFifo,
+		MaxFiles:       req.Task.LogConfig.MaxFiles,
+		MaxFileSizeMB:  req.Task.LogConfig.MaxFileSizeMB,
+		Compression:    req.Task.LogConfig.Compress,
+		MaxAge:         req.Task.LogConfig.MaxAge,
+		MaxTotalSizeMB: req.Task.LogConfig.MaxTotalSizeMB,
+		Timestamps:     req.Task.LogConfig.Timestamps,
+		Sinks:          logSinksConfig(req.Task.LogConfig.Sinks),
+		Labels: map[string]string{
+			"namespace": alloc.Namespace,
+			"job":       alloc.JobID,
+			"group":     alloc.TaskGroup,
+			"alloc":     alloc.ID,
+			"task":      req.Task.Name,
+		},
 	})
 	if err != nil {
 		h.logger.Error("failed to start logmon", "error", err)
@@ -209,6 +223,22 @@ func (h *logmonHook) prestartOneLoop(ctx context.Context, req *interfaces.TaskPr
 	return nil
 }
 
+// logSinksConfig converts the log sinks of a task to the logmon sink configs.
+func logSinksConfig(in []*structs.LogSink) []*sinks.Config {
+	var out []*sinks.Config
+	for _, sink := range in {
+		out = append(out, &sinks.Config{
+			Type:          sink.Type,
+			Address:       sink.Address,
+			BufferSize:    sink.BufferSize,
+			RetryAttempts: sink.RetryAttempts,
+			RetryInterval: sink.RetryInterval,
+			DropPolicy:    sink.DropPolicy,
+		})
+	}
+	return out
+}
+
 func (h *logmonHook) Stop(_ context.Context, req *interfaces.TaskStopRequest, _ *interfaces.TaskStopResponse) error {
 	if h.isLoggingDisabled() {
 		return nil
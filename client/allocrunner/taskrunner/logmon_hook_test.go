@@ -71,7 +71,7 @@ func TestTaskRunner_LogmonHook_StartStop(t *testing.T) {
 	dir := t.TempDir()
 
 	hookConf := newLogMonHookConfig(task.Name, task.LogConfig, dir)
-	runner := &TaskRunner{logmonHookConfig: hookConf}
+	runner := &TaskRunner{alloc: alloc, logmonHookConfig: hookConf}
 	hook := newLogMonHook(runner, testlog.HCLogger(t))
 
 	req := interfaces.TaskPrestartRequest{
@@ -117,7 +117,7 @@ func TestTaskRunner_LogmonHook_Disabled(t *testing.T) {
 	dir := t.TempDir()
 
 	hookConf := newLogMonHookConfig(task.Name, task.LogConfig, dir)
-	runner := &TaskRunner{logmonHookConfig: hookConf}
+	runner := &TaskRunner{alloc: alloc, logmonHookConfig: hookConf}
 	hook := newLogMonHook(runner, testlog.HCLogger(t))
 
 	req := interfaces.TaskPrestartRequest{Task: task}
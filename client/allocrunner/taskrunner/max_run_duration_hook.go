@@ -0,0 +1,170 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package taskrunner
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	ti "github.com/hashicorp/nomad/client/allocrunner/taskrunner/interfaces"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const maxRunDurationHookName = "max_run_duration"
+
+var (
+	_ interfaces.TaskPoststartHook = (*maxRunDurationHook)(nil)
+	_ interfaces.TaskExitedHook    = (*maxRunDurationHook)(nil)
+	_ interfaces.TaskStopHook      = (*maxRunDurationHook)(nil)
+)
+
+// maxRunDurationHook kills the task once it has run for longer than its
+// maximum run duration, or once the deadline of its job has passed. Exceeding
+// the maximum run duration restarts the task as a failure according to its
+// restart policy, while exceeding the deadline kills the task and fails it.
+type maxRunDurationHook struct {
+	lifecycle ti.TaskLifecycle
+	logger    hclog.Logger
+
+	// startedAt returns the time the current run of the task started.
+	startedAt func() time.Time
+
+	// maxRunDuration is the maximum duration of each run of the task, or
+	// zero if it's unlimited.
+	maxRunDuration time.Duration
+
+	// deadline is the time past which the task is killed, or the zero time
+	// if the job has no deadline.
+	deadline time.Time
+
+	// stop stops the timer of the current run of the task.
+	stop context.CancelFunc
+	lock sync.Mutex
+}
+
+func newMaxRunDurationHook(tr *TaskRunner, logger hclog.Logger) *maxRunDurationHook {
+	alloc := tr.Alloc()
+	task := tr.Task()
+
+	maxRunDuration := task.MaxRunDuration
+	if tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup); maxRunDuration == 0 && tg != nil && tg.MaxRunDuration != nil {
+		maxRunDuration = *tg.MaxRunDuration
+	}
+
+	h := &maxRunDurationHook{
+		lifecycle: tr,
+		startedAt: func() time.Time {
+			if state := tr.TaskState(); state != nil {
+				return state.StartedAt
+			}
+			return time.Time{}
+		},
+		maxRunDuration: maxRunDuration,
+		deadline:       alloc.Job.DeadlineTime(),
+	}
+	h.logger = logger.Named(h.Name())
+	return h
+}
+
+func (*maxRunDurationHook) Name() string {
+	return maxRunDurationHookName
+}
+
+// enabled returns whether the task has a limit on how long it may run.
+func (h *maxRunDurationHook) enabled() bool {
+	return h.maxRunDuration > 0 || !h.deadline.IsZero()
+}
+
+func (h *maxRunDurationHook) Poststart(ctx context.Context, _ *interfaces.TaskPoststartRequest, _ *interfaces.TaskPoststartResponse) error {
+	if !h.enabled() {
+		return nil
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.stopLocked()
+
+	// Account for the time the task already ran if it was restored
+	startedAt := h.startedAt()
+	if startedAt.IsZero() {
+		startedAt = time.Now()
+	}
+
+	var timerCtx context.Context
+	timerCtx, h.stop = context.WithCancel(ctx)
+	go h.watch(timerCtx, ctx, startedAt)
+	return nil
+}
+
+func (h *maxRunDurationHook) Exited(context.Context, *interfaces.TaskExitedRequest, *interfaces.TaskExitedResponse) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.stopLocked()
+	return nil
+}
+
+func (h *maxRunDurationHook) Stop(context.Context, *interfaces.TaskStopRequest, *interfaces.TaskStopResponse) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.stopLocked()
+	return nil
+}
+
+// stopLocked stops the timer of the current run of the task, if any.
+//
+// Caller must hold h.lock.
+func (h *maxRunDurationHook) stopLocked() {
+	if h.stop != nil {
+		h.stop()
+		h.stop = nil
+	}
+}
+
+// watch waits until the task exceeds its maximum run duration or deadline and
+// kills it, unless timerCtx is cancelled first because the task exited. The
+// task is restarted or killed with killCtx so it isn't interrupted when the
+// exit of the task stops the timer.
+func (h *maxRunDurationHook) watch(timerCtx, killCtx context.Context, startedAt time.Time) {
+	limit := h.deadline
+	exceedsDeadline := true
+	if h.maxRunDuration > 0 {
+		if runLimit := startedAt.Add(h.maxRunDuration); limit.IsZero() || runLimit.Before(limit) {
+			limit = runLimit
+			exceedsDeadline = false
+		}
+	}
+
+	timer, stop := helper.NewSafeTimer(time.Until(limit))
+	defer stop()
+
+	select {
+	case <-timerCtx.Done():
+		return
+	case <-timer.C:
+	}
+
+	if exceedsDeadline {
+		h.logger.Info("killing task past its job deadline", "deadline", h.deadline)
+		event := structs.NewTaskEvent(structs.TaskDeadlineExceeded).
+			SetDisplayMessage(fmt.Sprintf("Job deadline of %v exceeded", h.deadline.Format(time.RFC3339))).
+			SetFailsTask()
+		if err := h.lifecycle.Kill(context.Background(), event); err != nil {
+			h.logger.Error("failed to kill task past its job deadline", "error", err)
+		}
+		return
+	}
+
+	h.logger.Info("restarting task that exceeded its maximum run duration", "max_run_duration", h.maxRunDuration)
+	event := structs.NewTaskEvent(structs.TaskMaxRunDurationExceeded).
+		SetDisplayMessage(fmt.Sprintf("Task exceeded its maximum run duration of %v", h.maxRunDuration))
+	if err := h.lifecycle.Restart(killCtx, event, true); err != nil {
+		h.logger.Error("failed to restart task that exceeded its maximum run duration", "error", err)
+	}
+}
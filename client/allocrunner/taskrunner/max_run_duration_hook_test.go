@@ -0,0 +1,106 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package taskrunner
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	trtesting "github.com/hashicorp/nomad/client/allocrunner/taskrunner/testing"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+func testMaxRunDurationHook(t *testing.T, lifecycle *trtesting.MockTaskHooks, startedAt time.Time) *maxRunDurationHook {
+	return &maxRunDurationHook{
+		lifecycle: lifecycle,
+		logger:    testlog.HCLogger(t),
+		startedAt: func() time.Time { return startedAt },
+	}
+}
+
+func TestMaxRunDurationHook_MaxRunDuration(t *testing.T) {
+	ci.Parallel(t)
+
+	lifecycle := trtesting.NewMockTaskHooks()
+	hook := testMaxRunDurationHook(t, lifecycle, time.Now())
+	hook.maxRunDuration = 100 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	must.NoError(t, hook.Poststart(ctx, &interfaces.TaskPoststartRequest{}, &interfaces.TaskPoststartResponse{}))
+
+	select {
+	case <-lifecycle.RestartCh:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for the task to be restarted")
+	}
+	must.Nil(t, lifecycle.KillEvent())
+}
+
+func TestMaxRunDurationHook_Restored(t *testing.T) {
+	ci.Parallel(t)
+
+	// The task already ran for longer than its maximum run duration before
+	// the client restarted.
+	lifecycle := trtesting.NewMockTaskHooks()
+	hook := testMaxRunDurationHook(t, lifecycle, time.Now().Add(-time.Hour))
+	hook.maxRunDuration = time.Minute
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	must.NoError(t, hook.Poststart(ctx, &interfaces.TaskPoststartRequest{}, &interfaces.TaskPoststartResponse{}))
+
+	select {
+	case <-lifecycle.RestartCh:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for the task to be restarted")
+	}
+}
+
+func TestMaxRunDurationHook_Deadline(t *testing.T) {
+	ci.Parallel(t)
+
+	lifecycle := trtesting.NewMockTaskHooks()
+	hook := testMaxRunDurationHook(t, lifecycle, time.Now())
+	hook.maxRunDuration = time.Hour
+	hook.deadline = time.Now().Add(100 * time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	must.NoError(t, hook.Poststart(ctx, &interfaces.TaskPoststartRequest{}, &interfaces.TaskPoststartResponse{}))
+
+	select {
+	case event := <-lifecycle.KillCh:
+		must.Eq(t, structs.TaskDeadlineExceeded, event.Type)
+		must.True(t, event.FailsTask)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for the task to be killed")
+	}
+}
+
+func TestMaxRunDurationHook_Exited(t *testing.T) {
+	ci.Parallel(t)
+
+	lifecycle := trtesting.NewMockTaskHooks()
+	hook := testMaxRunDurationHook(t, lifecycle, time.Now())
+	hook.maxRunDuration = 200 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	must.NoError(t, hook.Poststart(ctx, &interfaces.TaskPoststartRequest{}, &interfaces.TaskPoststartResponse{}))
+
+	// The task exits before reaching its maximum run duration.
+	must.NoError(t, hook.Exited(ctx, &interfaces.TaskExitedRequest{}, &interfaces.TaskExitedResponse{}))
+
+	select {
+	case <-lifecycle.RestartCh:
+		t.Fatal("task restarted after it exited")
+	case <-time.After(500 * time.Millisecond):
+	}
+}
@@ -14,9 +14,6 @@ import (
 )
 
 const (
-	// jitter is the percent of jitter added to restart delays.
-	jitter = 0.25
-
 	ReasonNoRestartsAllowed  = "Policy allows no restarts"
 	ReasonUnrecoverableError = "Error was unrecoverable"
 	ReasonWithinPolicy       = "Restart within policy"
@@ -65,6 +62,7 @@ type RestartTracker struct {
 	startTime        time.Time // When the interval began
 	reason           string    // The reason for the last state
 	policy           *structs.RestartPolicy
+	rescheduleRules  []*structs.ExitRule // Exit rules of the reschedule policy
 	rand             *rand.Rand
 	lock             sync.Mutex
 }
@@ -76,6 +74,15 @@ func (r *RestartTracker) SetPolicy(policy *structs.RestartPolicy) {
 	r.policy = policy
 }
 
+// SetRescheduleExitRules sets the exit rules of the reschedule policy of the
+// task group. A task whose exit matches a rule to reschedule isn't restarted,
+// so that its allocation fails and is rescheduled.
+func (r *RestartTracker) SetRescheduleExitRules(rules []*structs.ExitRule) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.rescheduleRules = rules
+}
+
 // GetPolicy returns a copy of the policy used to determine restarts.
 func (r *RestartTracker) GetPolicy() *structs.RestartPolicy {
 	r.lock.Lock()
@@ -175,13 +182,36 @@ func (r *RestartTracker) GetState() (string, time.Duration) {
 		return structs.TaskRestarting, 0
 	}
 
+	// Exit rules take precedence over the rest of the policy, and the exit
+	// rules of the reschedule policy apply if none of the restart policy
+	// matches
+	onSuccess := r.onSuccess
+	if r.exitRes != nil {
+		if rule := structs.MatchExitRule(r.policy.ExitRules, r.exitRes.ExitCode, r.exitRes.Signal); rule != nil {
+			switch rule.Action {
+			case structs.ExitRuleActionComplete:
+				r.reason = fmt.Sprintf("Task exit %s matches a rule to complete", exitDesc(r.exitRes))
+				return structs.TaskTerminated, 0
+			case structs.ExitRuleActionFail:
+				r.reason = fmt.Sprintf("Task exit %s matches a rule to fail", exitDesc(r.exitRes))
+				return structs.TaskNotRestarting, 0
+			case structs.ExitRuleActionRestart:
+				onSuccess = true
+			}
+		} else if rule := structs.MatchExitRule(r.rescheduleRules, r.exitRes.ExitCode, r.exitRes.Signal); rule != nil &&
+			rule.Action == structs.ExitRuleActionReschedule {
+			r.reason = fmt.Sprintf("Task exit %s matches a rule to reschedule", exitDesc(r.exitRes))
+			return structs.TaskNotRestarting, 0
+		}
+	}
+
 	// Hot path if no attempts are expected
 	if r.policy.Attempts == 0 {
 		r.reason = ReasonNoRestartsAllowed
 
 		// If the task does not restart on a successful exit code and
 		// the exit code was successful: terminate.
-		if !r.onSuccess && r.exitRes != nil && r.exitRes.Successful() {
+		if !onSuccess && r.exitRes != nil && r.exitRes.Successful() {
 			return structs.TaskTerminated, 0
 		}
 
@@ -214,7 +244,7 @@ func (r *RestartTracker) GetState() (string, time.Duration) {
 	} else if r.exitRes != nil {
 		// If the task started successfully and restart on success isn't specified,
 		// don't restart but don't mark as failed.
-		if r.exitRes.Successful() && !r.onSuccess {
+		if r.exitRes.Successful() && !onSuccess {
 			r.reason = "Restart unnecessary as task terminated successfully"
 			return structs.TaskTerminated, 0
 		}
@@ -239,6 +269,14 @@ func (r *RestartTracker) GetState() (string, time.Duration) {
 	return structs.TaskRestarting, r.jitter()
 }
 
+// exitDesc describes the exit code or signal of the exit result.
+func exitDesc(res *drivers.ExitResult) string {
+	if res.Signal != 0 {
+		return fmt.Sprintf("with signal %d", res.Signal)
+	}
+	return fmt.Sprintf("with exit code %d", res.ExitCode)
+}
+
 // getDelay returns the delay time to enter the next interval.
 func (r *RestartTracker) getDelay() time.Duration {
 	end := r.startTime.Add(r.policy.Interval)
@@ -246,14 +284,15 @@ func (r *RestartTracker) getDelay() time.Duration {
 	return end.Sub(now)
 }
 
-// jitter returns the delay time plus a jitter.
+// jitter returns the delay time of the current attempt according to the
+// policy's delay function, plus a jitter.
 func (r *RestartTracker) jitter() time.Duration {
 	// Get the delay and ensure it is valid.
-	d := r.policy.Delay.Nanoseconds()
-	if d == 0 {
+	d := r.policy.NextDelay(r.count).Nanoseconds()
+	if d <= 0 {
 		d = 1
 	}
 
-	j := float64(r.rand.Int63n(d)) * jitter
+	j := float64(r.rand.Int63n(d)) * r.policy.GetJitter()
 	return time.Duration(d + int64(j))
 }
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/pointer"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/plugins/drivers"
 	"github.com/stretchr/testify/require"
@@ -27,7 +28,7 @@ func testPolicy(success bool, mode string) *structs.RestartPolicy {
 // the jitter.
 func withinJitter(expected, actual time.Duration) bool {
 	return float64((actual.Nanoseconds()-expected.Nanoseconds())/
-		expected.Nanoseconds()) <= jitter
+		expected.Nanoseconds()) <= structs.RestartPolicyDefaultJitter
 }
 
 func testExitResult(exit int) *drivers.ExitResult {
@@ -62,6 +63,87 @@ func TestClient_RestartTracker_ModeDelay(t *testing.T) {
 	}
 }
 
+func TestClient_RestartTracker_DelayFunction(t *testing.T) {
+	ci.Parallel(t)
+	p := testPolicy(true, structs.RestartPolicyModeFail)
+	p.Attempts = 5
+	p.DelayFunction = "exponential"
+	p.MaxDelay = 6 * time.Second
+	p.Jitter = pointer.Of(0.0)
+	rt := NewRestartTracker(p, structs.JobTypeService, nil)
+
+	for _, exp := range []time.Duration{1, 2, 4, 6, 6} {
+		state, when := rt.SetExitResult(testExitResult(127)).GetState()
+		require.Equal(t, structs.TaskRestarting, state)
+		require.Equal(t, exp*time.Second, when)
+	}
+
+	// The delay is reset with the interval
+	rt.startTime = time.Now().Add(-p.Interval - time.Second)
+	_, when := rt.SetExitResult(testExitResult(127)).GetState()
+	require.Equal(t, p.Delay, when)
+}
+
+func TestClient_RestartTracker_ExitRules(t *testing.T) {
+	ci.Parallel(t)
+	p := testPolicy(true, structs.RestartPolicyModeFail)
+	p.ExitRules = []*structs.ExitRule{
+		{ExitCodes: []int{0, 3}, Action: structs.ExitRuleActionComplete},
+		{ExitCodes: []int{75}, Action: structs.ExitRuleActionRestart},
+		{ExitCodes: []int{1}, Action: structs.ExitRuleActionFail},
+	}
+
+	// A service task completes on a matching exit code
+	rt := NewRestartTracker(p, structs.JobTypeService, nil)
+	state, _ := rt.SetExitResult(testExitResult(3)).GetState()
+	require.Equal(t, structs.TaskTerminated, state)
+
+	// A task fails without restarting on a matching exit code
+	state, _ = rt.SetExitResult(testExitResult(1)).GetState()
+	require.Equal(t, structs.TaskNotRestarting, state)
+	require.Contains(t, rt.GetReason(), "exit code 1")
+
+	// A batch task restarts on a matching exit code, even if successful
+	p.ExitRules[0].ExitCodes = []int{3}
+	p.ExitRules[1].ExitCodes = []int{0, 75}
+	rt = NewRestartTracker(p, structs.JobTypeBatch, nil)
+	state, _ = rt.SetExitResult(testExitResult(0)).GetState()
+	require.Equal(t, structs.TaskRestarting, state)
+
+	// Other exit codes follow the policy
+	state, _ = rt.SetExitResult(testExitResult(2)).GetState()
+	require.Equal(t, structs.TaskRestarting, state)
+	require.Equal(t, ReasonWithinPolicy, rt.GetReason())
+}
+
+func TestClient_RestartTracker_RescheduleExitRules(t *testing.T) {
+	ci.Parallel(t)
+	p := testPolicy(true, structs.RestartPolicyModeFail)
+	p.ExitRules = []*structs.ExitRule{
+		{ExitCodes: []int{75}, Action: structs.ExitRuleActionRestart},
+	}
+
+	rt := NewRestartTracker(p, structs.JobTypeService, nil)
+	rt.SetRescheduleExitRules([]*structs.ExitRule{
+		{ExitCodes: []int{75, 137}, Action: structs.ExitRuleActionReschedule},
+		{ExitCodes: []int{1}, Action: structs.ExitRuleActionFail},
+	})
+
+	// A task fails without restarting so that it's rescheduled
+	state, _ := rt.SetExitResult(testExitResult(137)).GetState()
+	require.Equal(t, structs.TaskNotRestarting, state)
+	require.Contains(t, rt.GetReason(), "rule to reschedule")
+
+	// The rules of the restart policy take precedence
+	state, _ = rt.SetExitResult(testExitResult(75)).GetState()
+	require.Equal(t, structs.TaskRestarting, state)
+
+	// A rule to fail the allocation doesn't prevent restarts
+	state, _ = rt.SetExitResult(testExitResult(1)).GetState()
+	require.Equal(t, structs.TaskRestarting, state)
+	require.Equal(t, ReasonWithinPolicy, rt.GetReason())
+}
+
 func TestClient_RestartTracker_ModeFail(t *testing.T) {
 	ci.Parallel(t)
 	p := testPolicy(true, structs.RestartPolicyModeFail)
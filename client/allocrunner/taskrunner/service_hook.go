@@ -110,9 +110,7 @@ func newServiceHook(c serviceHookConfig) *serviceHook {
 		h.networks = res.Networks
 	}
 
-	if c.alloc.DeploymentStatus != nil && c.alloc.DeploymentStatus.Canary {
-		h.canary = true
-	}
+	h.canary = c.alloc.DeploymentStatus.CanaryServices()
 
 	h.logger = c.logger.Named(h.Name())
 	return h
@@ -164,10 +162,7 @@ func (h *serviceHook) Update(ctx context.Context, req *interfaces.TaskUpdateRequ
 
 func (h *serviceHook) updateHookFields(req *interfaces.TaskUpdateRequest) error {
 	// Store new updated values out of request
-	canary := false
-	if req.Alloc.DeploymentStatus != nil {
-		canary = req.Alloc.DeploymentStatus.Canary
-	}
+	canary := req.Alloc.DeploymentStatus.CanaryServices()
 
 	var networks structs.Networks
 	if res := req.Alloc.AllocatedResources.Tasks[h.taskName]; res != nil {
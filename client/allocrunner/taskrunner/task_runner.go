@@ -462,9 +462,9 @@ func NewTaskRunner(config *Config) (*TaskRunner, error) {
 	tr.taskResources = tres
 
 	// Build the restart tracker.
+	tg := tr.alloc.Job.LookupTaskGroup(tr.alloc.TaskGroup)
 	rp := config.Task.RestartPolicy
 	if rp == nil {
-		tg := tr.alloc.Job.LookupTaskGroup(tr.alloc.TaskGroup)
 		if tg == nil {
 			tr.logger.Error("alloc missing task group")
 			return nil, fmt.Errorf("alloc missing task group")
@@ -472,6 +472,9 @@ func NewTaskRunner(config *Config) (*TaskRunner, error) {
 		rp = tg.RestartPolicy
 	}
 	tr.restartTracker = restarts.NewRestartTracker(rp, tr.alloc.Job.Type, config.Task.Lifecycle)
+	if tg != nil && tg.ReschedulePolicy != nil {
+		tr.restartTracker.SetRescheduleExitRules(tg.ReschedulePolicy.ExitRules)
+	}
 
 	// Get the driver
 	if err := tr.initDriver(); err != nil {
@@ -328,6 +328,10 @@ type Client struct {
 	// partitions is used for managing cpuset partitioning on linux systems
 	partitions cgroupslib.Partition
 
+	// networkPolicies enforces the network policies of allocations on the
+	// bridge network
+	networkPolicies cinterfaces.NetworkPolicies
+
 	// widsigner signs workload identities
 	widsigner widmgr.IdentitySigner
 
@@ -478,6 +482,9 @@ func NewClient(cfg *config.Config, consulCatalog consul.CatalogAPI, consulProxie
 		c.topology.UsableCores(),
 	)
 
+	// Create the network policies enforcer of the bridge network
+	c.networkPolicies = allocrunner.NewNetworkPolicies(c.logger, cfg.BridgeNetworkName)
+
 	// Create the process wranglers
 	wranglers, err := proclib.New(&proclib.Configs{
 		UsableCores: c.topology.UsableCores(),
@@ -2418,6 +2425,12 @@ OUTER:
 			continue OUTER
 		}
 
+		// The network policies of the namespaces are sent along with the
+		// allocations so that changes apply to the running allocations
+		if err := c.networkPolicies.SetNamespacePolicies(resp.NamespaceNetworkPolicies); err != nil {
+			c.logger.Error("failed to update namespace network policies", "error", err)
+		}
+
 		// Filter all allocations whose AllocModifyIndex was not incremented.
 		// These are the allocations who have either not been updated, or whose
 		// updates are a result of the client sending an update for the alloc.
@@ -2834,6 +2847,7 @@ func (c *Client) newAllocRunnerConfig(
 		WIDSigner:           c.widsigner,
 		Wranglers:           c.wranglers,
 		Partitions:          c.partitions,
+		NetworkPolicies:     c.networkPolicies,
 		Users:               c.users,
 	}
 }
@@ -118,6 +118,10 @@ type AllocRunnerConfig struct {
 	// Partitions is an interface for managing cpuset partitions.
 	Partitions interfaces.CPUPartitions
 
+	// NetworkPolicies is an interface for enforcing the network policies of
+	// allocations on the bridge network.
+	NetworkPolicies interfaces.NetworkPolicies
+
 	// WIDSigner fetches workload identities
 	WIDSigner widmgr.IdentitySigner
 
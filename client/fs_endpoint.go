@@ -13,7 +13,6 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
-	"strconv"
 	"strings"
 	"syscall"
 	"time"
@@ -25,6 +24,7 @@ import (
 	"github.com/hashicorp/nomad/acl"
 	"github.com/hashicorp/nomad/client/allocdir"
 	sframer "github.com/hashicorp/nomad/client/lib/streamframer"
+	"github.com/hashicorp/nomad/client/logmon/logging"
 	cstructs "github.com/hashicorp/nomad/client/structs"
 	"github.com/hashicorp/nomad/helper/pointer"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -411,6 +411,11 @@ func (f *FileSystem) logs(conn io.ReadWriteCloser) {
 		handleStreamResultError(invalidOrigin, pointer.Of(int64(http.StatusBadRequest)), encoder)
 		return
 	}
+	filter, err := newLogFilter(&req)
+	if err != nil {
+		handleStreamResultError(err, pointer.Of(int64(http.StatusBadRequest)), encoder)
+		return
+	}
 
 	fs, err := f.c.GetAllocFS(req.AllocID)
 	if err != nil {
@@ -458,10 +463,17 @@ func (f *FileSystem) logs(conn io.ReadWriteCloser) {
 	frames := make(chan *sframer.StreamFrame, streamFramesBuffer)
 	errCh := make(chan error)
 
+	// Filter the lines of the logs before they are sent
+	logFrames := frames
+	if filter != nil {
+		logFrames = make(chan *sframer.StreamFrame, streamFramesBuffer)
+		go filter.run(ctx, logFrames, frames)
+	}
+
 	// Start streaming
 	go func() {
 		if err := f.logsImpl(ctx, req.Follow, req.PlainText,
-			req.Offset, req.Origin, req.Task, req.LogType, fs, frames); err != nil {
+			req.Offset, req.Origin, req.Task, req.LogType, fs, logFrames); err != nil {
 			select {
 			case errCh <- err:
 			case <-ctx.Done():
@@ -580,6 +592,7 @@ func (f *FileSystem) logsImpl(ctx context.Context, follow, plain bool, offset in
 		if err != nil {
 			return fmt.Errorf("failed to list entries: %v", err)
 		}
+		entries = decompressedLogEntries(fs, logPath, entries, task, logType)
 
 		// If we are not following logs, determine the max index for the logs we are
 		// interested in so we can stop there.
@@ -612,7 +625,14 @@ func (f *FileSystem) logsImpl(ctx context.Context, follow, plain bool, offset in
 		}
 
 		p := filepath.Join(logPath, logEntry.Name)
-		err = f.streamFile(ctx, openOffset, p, 0, fs, framer, eofCancelCh, cancelAfterFirstEof)
+		_, compression, _ := logging.ParseLogFileName(logEntry.Name, task+"."+logType)
+		if compression != "" {
+			// Compressed log files are complete, so there is no need to wait
+			// for the next log file
+			err = f.streamCompressedFile(ctx, openOffset, p, compression, fs, framer)
+		} else {
+			err = f.streamFile(ctx, openOffset, p, 0, fs, framer, eofCancelCh, cancelAfterFirstEof)
+		}
 
 		// Check if the context is cancelled
 		select {
@@ -802,6 +822,89 @@ OUTER:
 	}
 }
 
+// streamCompressedFile streams the content of a rotated log file compressed
+// with the given algorithm, starting at the given offset of the decompressed
+// content. Compressed log files are never written to, so the stream ends at
+// EOF.
+func (f *FileSystem) streamCompressedFile(ctx context.Context, offset int64, path, compression string,
+	fs allocdir.AllocDirFS, framer *sframer.StreamFramer) error {
+
+	file, err := fs.ReadAt(path, 0)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	reader, err := logging.NewDecompressReader(compression, file)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	// Seek to the offset by discarding the content before it
+	if _, err := io.CopyN(io.Discard, reader, offset); err != nil && err != io.EOF {
+		return err
+	}
+
+	data := make([]byte, streamFrameSize)
+	for {
+		n, readErr := reader.Read(data)
+		offset += int64(n)
+
+		if readErr != nil && readErr != io.EOF {
+			return readErr
+		}
+
+		if n != 0 {
+			if err := framer.Send(path, "", data[:n], offset); err != nil {
+				return parseFramerErr(err)
+			}
+		}
+
+		if readErr == io.EOF {
+			return nil
+		}
+
+		select {
+		case <-framer.ExitCh():
+			return nil
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+	}
+}
+
+// decompressedLogEntries returns the log entries with the size of the
+// compressed log files set to the size of their content, so that offsets are
+// consistent across compressed and uncompressed log files. Compressed log
+// files that can't be read are skipped, as they may have been purged.
+func decompressedLogEntries(fs allocdir.AllocDirFS, logPath string,
+	entries []*cstructs.AllocFileInfo, task, logType string) []*cstructs.AllocFileInfo {
+
+	out := make([]*cstructs.AllocFileInfo, 0, len(entries))
+	for _, entry := range entries {
+		_, compression, ok := logging.ParseLogFileName(entry.Name, task+"."+logType)
+		if entry.IsDir || !ok || compression == "" {
+			out = append(out, entry)
+			continue
+		}
+
+		p := filepath.Join(logPath, entry.Name)
+		size, err := logging.DecompressedSize(compression, entry.Size, func(offset int64) (io.ReadCloser, error) {
+			return fs.ReadAt(p, offset)
+		})
+		if err != nil {
+			continue
+		}
+
+		decompressed := *entry
+		decompressed.Size = size
+		out = append(out, &decompressed)
+	}
+	return out
+}
+
 // blockUntilNextLog returns a channel that will have data sent when the next
 // log index or anything greater is created.
 func blockUntilNextLog(ctx context.Context, fs allocdir.AllocDirFS, logPath, task, logType string, nextIndex int64) chan error {
@@ -863,8 +966,9 @@ func blockUntilNextLog(ctx context.Context, fs allocdir.AllocDirFS, logPath, tas
 // indexTuple and indexTupleArray are used to find the correct log entry to
 // start streaming logs from
 type indexTuple struct {
-	idx   int64
-	entry *cstructs.AllocFileInfo
+	idx        int64
+	entry      *cstructs.AllocFileInfo
+	compressed bool
 }
 
 type indexTupleArray []indexTuple
@@ -878,7 +982,9 @@ func (a indexTupleArray) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
 // error is returned.
 func logIndexes(entries []*cstructs.AllocFileInfo, task, logType string) (indexTupleArray, error) {
 	var indexes []indexTuple
-	prefix := fmt.Sprintf("%s.%s.", task, logType)
+	positions := make(map[int64]int)
+	baseFileName := fmt.Sprintf("%s.%s", task, logType)
+	prefix := baseFileName + "."
 	for _, entry := range entries {
 		if entry.IsDir {
 			continue
@@ -890,13 +996,23 @@ func logIndexes(entries []*cstructs.AllocFileInfo, task, logType string) (indexT
 			continue
 		}
 
-		// Convert to an int
-		idx, err := strconv.Atoi(idxStr)
-		if err != nil {
-			return nil, fmt.Errorf("failed to convert %q to a log index: %v", idxStr, err)
+		// Convert to an int, ignoring the extension of compressed log files
+		idx, compression, ok := logging.ParseLogFileName(entry.Name, baseFileName)
+		if !ok {
+			return nil, fmt.Errorf("failed to convert %q to a log index", idxStr)
 		}
 
-		indexes = append(indexes, indexTuple{idx: int64(idx), entry: entry})
+		// A log file may briefly exist both compressed and uncompressed while
+		// it's compressed, in which case the uncompressed file is used
+		tuple := indexTuple{idx: int64(idx), entry: entry, compressed: compression != ""}
+		if i, ok := positions[tuple.idx]; ok {
+			if indexes[i].compressed && !tuple.compressed {
+				indexes[i] = tuple
+			}
+			continue
+		}
+		positions[tuple.idx] = len(indexes)
+		indexes = append(indexes, tuple)
 	}
 
 	return indexTupleArray(indexes), nil
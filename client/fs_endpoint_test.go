@@ -4,6 +4,8 @@
 package client
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"fmt"
 	"io"
@@ -32,6 +34,7 @@ import (
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/testutil"
+	"github.com/klauspost/compress/zstd"
 	"github.com/shoenig/test/must"
 	"github.com/stretchr/testify/require"
 )
@@ -2085,6 +2088,75 @@ func TestFS_logsImpl_NoFollow(t *testing.T) {
 	}
 }
 
+// TestFS_logsImpl_Compressed asserts that logs are read transparently across
+// compressed log files.
+func TestFS_logsImpl_Compressed(t *testing.T) {
+	ci.Parallel(t)
+
+	// Get a temp alloc dir and create the log dir
+	ad := tempAllocDir(t)
+	must.NoError(t, ad.Build())
+	defer ad.Destroy()
+
+	logDir := filepath.Join(ad.SharedDir, allocdir.LogDirName)
+	must.NoError(t, os.MkdirAll(logDir, 0777))
+
+	// Create a gzip, a zstd and an uncompressed log file
+	var gzipped bytes.Buffer
+	gw := gzip.NewWriter(&gzipped)
+	_, err := gw.Write([]byte("0123"))
+	must.NoError(t, err)
+	must.NoError(t, gw.Close())
+
+	enc, err := zstd.NewWriter(nil)
+	must.NoError(t, err)
+	zstded := enc.EncodeAll([]byte("4567"), nil)
+	must.NoError(t, enc.Close())
+
+	must.NoError(t, os.WriteFile(filepath.Join(logDir, "foo.stdout.0.gz"), gzipped.Bytes(), 0777))
+	must.NoError(t, os.WriteFile(filepath.Join(logDir, "foo.stdout.1.zst"), zstded, 0777))
+	must.NoError(t, os.WriteFile(filepath.Join(logDir, "foo.stdout.2"), []byte("89"), 0777))
+
+	// The zstd log file is still being compressed
+	must.NoError(t, os.WriteFile(filepath.Join(logDir, "foo.stdout.1"), []byte("4567"), 0777))
+
+	cases := []struct {
+		name     string
+		origin   string
+		offset   int64
+		expected string
+	}{
+		{name: "start", origin: OriginStart, offset: 0, expected: "0123456789"},
+		{name: "start offset", origin: OriginStart, offset: 2, expected: "23456789"},
+		{name: "end offset", origin: OriginEnd, offset: 5, expected: "56789"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			frames := make(chan *sframer.StreamFrame, 32)
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			must.NoError(t, (&FileSystem{}).logsImpl(
+				ctx, false, false, tc.offset,
+				tc.origin, "foo", "stdout", ad, frames))
+
+			var received []byte
+			timeout := time.After(10 * time.Duration(testutil.TestMultiplier()) * streamBatchWindow)
+			for string(received) != tc.expected {
+				select {
+				case frame, ok := <-frames:
+					if !ok {
+						t.Fatalf("did not receive data: got %q", string(received))
+					}
+					received = append(received, frame.Data...)
+				case <-timeout:
+					t.Fatalf("did not receive data: got %q", string(received))
+				}
+			}
+		})
+	}
+}
+
 func TestFS_logsImpl_Follow(t *testing.T) {
 	ci.Parallel(t)
 
@@ -0,0 +1,149 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	sframer "github.com/hashicorp/nomad/client/lib/streamframer"
+	"github.com/hashicorp/nomad/client/logmon/logging"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+)
+
+// logFilter filters the lines of the logs streamed by time range and content.
+type logFilter struct {
+	// since and until bound the time range of the lines, if set. Lines
+	// without timestamps are never filtered out by time.
+	since time.Time
+	until time.Time
+
+	// grep is the substring the lines must contain, unless re is set
+	grep []byte
+	re   *regexp.Regexp
+}
+
+// newLogFilter returns the filter of the logs request, or nil if the request
+// doesn't filter the logs.
+func newLogFilter(req *cstructs.FsLogsRequest) (*logFilter, error) {
+	if req.Since.IsZero() && req.Until.IsZero() && req.Grep == "" {
+		return nil, nil
+	}
+	if !req.Since.IsZero() && !req.Until.IsZero() && req.Until.Before(req.Since) {
+		return nil, fmt.Errorf("until %s is before since %s",
+			req.Until.Format(time.RFC3339), req.Since.Format(time.RFC3339))
+	}
+
+	l := &logFilter{
+		since: req.Since,
+		until: req.Until,
+		grep:  []byte(req.Grep),
+	}
+	if req.Regex && req.Grep != "" {
+		re, err := regexp.Compile(req.Grep)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile grep regex: %v", err)
+		}
+		l.re = re
+	}
+	return l, nil
+}
+
+// match returns true if the line matches the filter. The timestamp of the
+// line, if any, is not searched.
+func (l *logFilter) match(line []byte) bool {
+	ts, msg, ok := logging.ParseTimestamp(line)
+	if ok {
+		if !l.since.IsZero() && ts.Before(l.since) {
+			return false
+		}
+		if !l.until.IsZero() && ts.After(l.until) {
+			return false
+		}
+	}
+
+	switch {
+	case l.re != nil:
+		return l.re.Match(msg)
+	case len(l.grep) > 0:
+		return bytes.Contains(msg, l.grep)
+	default:
+		return true
+	}
+}
+
+// run copies the frames from in to out, keeping only the lines matching the
+// filter. Lines split across frames are reassembled, and heartbeats and file
+// events are passed through. out is closed once in is closed.
+func (l *logFilter) run(ctx context.Context, in <-chan *sframer.StreamFrame, out chan<- *sframer.StreamFrame) {
+	defer close(out)
+
+	send := func(frame *sframer.StreamFrame) {
+		select {
+		case out <- frame:
+		case <-ctx.Done():
+		}
+	}
+
+	// partial is the start of the last line, until it's terminated
+	var partial []byte
+	var last *sframer.StreamFrame
+
+	for frame := range in {
+		// Keep draining the frames once cancelled so that the framer
+		// never blocks
+		if ctx.Err() != nil {
+			continue
+		}
+		if frame.IsHeartbeat() {
+			send(frame)
+			continue
+		}
+		last = frame
+
+		var data []byte
+		partial = append(partial, frame.Data...)
+		for {
+			i := bytes.IndexByte(partial, '\n')
+			if i < 0 {
+				// Give up on reassembling lines longer than a frame
+				if len(partial) >= streamFrameSize {
+					if l.match(partial) {
+						data = append(data, partial...)
+					}
+					partial = nil
+				}
+				break
+			}
+			if l.match(partial[:i]) {
+				data = append(data, partial[:i+1]...)
+			}
+			partial = partial[i+1:]
+		}
+		if len(partial) == 0 {
+			partial = nil
+		}
+
+		if len(data) > 0 || frame.FileEvent != "" {
+			send(&sframer.StreamFrame{
+				Offset:    frame.Offset,
+				Data:      data,
+				File:      frame.File,
+				FileEvent: frame.FileEvent,
+			})
+		}
+	}
+
+	// The last line of the logs may not be terminated
+	if ctx.Err() == nil && len(partial) > 0 && l.match(partial) {
+		send(&sframer.StreamFrame{
+			Offset: last.Offset,
+			Data:   partial,
+			File:   last.File,
+		})
+	}
+}
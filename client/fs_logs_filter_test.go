@@ -0,0 +1,133 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	sframer "github.com/hashicorp/nomad/client/lib/streamframer"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/shoenig/test/must"
+)
+
+func TestLogFilter_New(t *testing.T) {
+	ci.Parallel(t)
+
+	filter, err := newLogFilter(&cstructs.FsLogsRequest{})
+	must.NoError(t, err)
+	must.Nil(t, filter)
+
+	_, err = newLogFilter(&cstructs.FsLogsRequest{Grep: "(", Regex: true})
+	must.ErrorContains(t, err, "failed to compile grep regex")
+
+	now := time.Now()
+	_, err = newLogFilter(&cstructs.FsLogsRequest{Since: now, Until: now.Add(-time.Minute)})
+	must.ErrorContains(t, err, "is before since")
+}
+
+func TestLogFilter_Match(t *testing.T) {
+	ci.Parallel(t)
+
+	since := time.Date(2026, 10, 16, 15, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name    string
+		req     *cstructs.FsLogsRequest
+		line    string
+		matches bool
+	}{
+		{
+			name:    "substring",
+			req:     &cstructs.FsLogsRequest{Grep: "ERROR"},
+			line:    "ERROR: something failed",
+			matches: true,
+		},
+		{
+			name: "substring no match",
+			req:  &cstructs.FsLogsRequest{Grep: "ERROR"},
+			line: "INFO: all good",
+		},
+		{
+			name:    "regex",
+			req:     &cstructs.FsLogsRequest{Grep: "^(ERROR|WARN):", Regex: true},
+			line:    "WARN: careful",
+			matches: true,
+		},
+		{
+			name: "regex no match",
+			req:  &cstructs.FsLogsRequest{Grep: "^(ERROR|WARN):", Regex: true},
+			line: "INFO: ERROR: quoted",
+		},
+		{
+			name: "timestamp not searched",
+			req:  &cstructs.FsLogsRequest{Grep: "2026"},
+			line: "2026-10-16T15:00:00.000000000Z hello",
+		},
+		{
+			name:    "since",
+			req:     &cstructs.FsLogsRequest{Since: since},
+			line:    "2026-10-16T15:00:00.000000000Z hello",
+			matches: true,
+		},
+		{
+			name: "before since",
+			req:  &cstructs.FsLogsRequest{Since: since},
+			line: "2026-10-16T14:59:59.999999999Z hello",
+		},
+		{
+			name: "after until",
+			req:  &cstructs.FsLogsRequest{Until: since},
+			line: "2026-10-16T15:00:00.000000001Z hello",
+		},
+		{
+			name:    "no timestamp",
+			req:     &cstructs.FsLogsRequest{Since: since},
+			line:    "hello",
+			matches: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			filter, err := newLogFilter(tc.req)
+			must.NoError(t, err)
+			must.Eq(t, tc.matches, filter.match([]byte(tc.line)))
+		})
+	}
+}
+
+func TestLogFilter_Run(t *testing.T) {
+	ci.Parallel(t)
+
+	filter, err := newLogFilter(&cstructs.FsLogsRequest{Grep: "ERROR"})
+	must.NoError(t, err)
+
+	in := make(chan *sframer.StreamFrame, 10)
+	out := make(chan *sframer.StreamFrame, 10)
+	go filter.run(context.Background(), in, out)
+
+	// Lines split across frames and log files are reassembled
+	in <- &sframer.StreamFrame{File: "alloc/logs/web.stdout.0", Data: []byte("INFO: one\nERROR: t")}
+	in <- sframer.HeartbeatStreamFrame
+	in <- &sframer.StreamFrame{File: "alloc/logs/web.stdout.0", Offset: 18, Data: []byte("wo\nINFO: thr")}
+	in <- &sframer.StreamFrame{File: "alloc/logs/web.stdout.1", Data: []byte("ee\nERROR: four")}
+	close(in)
+
+	var frames []*sframer.StreamFrame
+	for frame := range out {
+		frames = append(frames, frame)
+	}
+	must.Len(t, 3, frames)
+	must.True(t, frames[0].IsHeartbeat())
+	must.Eq(t, &sframer.StreamFrame{
+		File:   "alloc/logs/web.stdout.0",
+		Offset: 18,
+		Data:   []byte("ERROR: two\n"),
+	}, frames[1])
+	must.Eq(t, &sframer.StreamFrame{
+		File: "alloc/logs/web.stdout.1",
+		Data: []byte("ERROR: four"),
+	}, frames[2])
+}
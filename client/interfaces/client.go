@@ -56,3 +56,20 @@ type CPUPartitions interface {
 	Reserve(*idset.Set[hw.CoreID]) error
 	Release(*idset.Set[hw.CoreID]) error
 }
+
+// NetworkPolicies is an interface satisfied by the allocrunner package to
+// enforce the network policies of the allocations on the bridge network.
+type NetworkPolicies interface {
+	// Add starts tracking the allocation with the given address on the bridge
+	// network and updates the rules of all allocations.
+	Add(alloc *structs.Allocation, address string) error
+
+	// Remove stops tracking the allocation and updates the rules of all
+	// allocations.
+	Remove(allocID string) error
+
+	// SetNamespacePolicies sets the network policies of the namespaces, which
+	// are merged with the network policies of the task groups, and updates
+	// the rules of all allocations if they changed.
+	SetNamespacePolicies(policies map[string]*structs.NetworkPolicy) error
+}
@@ -29,6 +29,21 @@ func (c *logmonClient) Start(cfg *LogConfig) error {
 		MaxFileSizeMb:  uint32(cfg.MaxFileSizeMB),
 		StdoutFifo:     cfg.StdoutFifo,
 		StderrFifo:     cfg.StderrFifo,
+		Compression:    cfg.Compression,
+		MaxAge:         cfg.MaxAge.Nanoseconds(),
+		MaxTotalSizeMb: uint32(cfg.MaxTotalSizeMB),
+		Timestamps:     cfg.Timestamps,
+		Labels:         cfg.Labels,
+	}
+	for _, sink := range cfg.Sinks {
+		req.Sinks = append(req.Sinks, &proto.LogSink{
+			Type:          sink.Type,
+			Address:       sink.Address,
+			BufferSize:    uint32(sink.BufferSize),
+			RetryAttempts: uint32(sink.RetryAttempts),
+			RetryInterval: sink.RetryInterval.Nanoseconds(),
+			DropPolicy:    sink.DropPolicy,
+		})
 	}
 	ctx, cancel := context.WithTimeout(context.Background(), logmonRPCTimeout)
 	defer cancel()
@@ -0,0 +1,215 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package logging
+
+import (
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	// CompressionGzip compresses rotated log files with gzip.
+	CompressionGzip = "gzip"
+
+	// CompressionZstd compresses rotated log files with zstd.
+	CompressionZstd = "zstd"
+
+	gzipExt = ".gz"
+	zstdExt = ".zst"
+)
+
+// compressionExt returns the file extension of the log files compressed with
+// the given algorithm.
+func compressionExt(compression string) string {
+	switch compression {
+	case CompressionGzip:
+		return gzipExt
+	case CompressionZstd:
+		return zstdExt
+	default:
+		return ""
+	}
+}
+
+// LogFileName returns the name of the log file with the given index, with the
+// extension of the compression algorithm if it's compressed.
+func LogFileName(baseFileName string, idx int, compression string) string {
+	return fmt.Sprintf("%s.%d%s", baseFileName, idx, compressionExt(compression))
+}
+
+// ParseLogFileName returns the index and the compression algorithm of a log
+// file named after the base file name. ok is false if the name doesn't match.
+func ParseLogFileName(name, baseFileName string) (idx int, compression string, ok bool) {
+	suffix, found := strings.CutPrefix(name, baseFileName+".")
+	if !found {
+		return 0, "", false
+	}
+
+	switch {
+	case strings.HasSuffix(suffix, gzipExt):
+		compression = CompressionGzip
+		suffix = strings.TrimSuffix(suffix, gzipExt)
+	case strings.HasSuffix(suffix, zstdExt):
+		compression = CompressionZstd
+		suffix = strings.TrimSuffix(suffix, zstdExt)
+	}
+
+	idx, err := strconv.Atoi(suffix)
+	if err != nil || idx < 0 {
+		return 0, "", false
+	}
+	return idx, compression, true
+}
+
+// NewDecompressReader returns a reader of the content of a log file compressed
+// with the given algorithm.
+func NewDecompressReader(compression string, r io.Reader) (io.ReadCloser, error) {
+	switch compression {
+	case CompressionGzip:
+		return gzip.NewReader(r)
+	case CompressionZstd:
+		d, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+		return d.IOReadCloser(), nil
+	default:
+		return nil, fmt.Errorf("unknown compression %q", compression)
+	}
+}
+
+// DecompressedSize returns the size of the content of a log file compressed
+// with the given algorithm, read from the metadata of the file when possible
+// rather than by decompressing it. size is the size of the compressed file and
+// open returns a reader of the file at the given offset.
+func DecompressedSize(compression string, size int64, open func(offset int64) (io.ReadCloser, error)) (int64, error) {
+	switch compression {
+	case CompressionGzip:
+		// The gzip trailer ends with the size of the content modulo 2^32,
+		// which is enough for log files
+		if size < 4 {
+			return 0, fmt.Errorf("truncated gzip file")
+		}
+		r, err := open(size - 4)
+		if err != nil {
+			return 0, err
+		}
+		defer r.Close()
+
+		var trailer [4]byte
+		if _, err := io.ReadFull(r, trailer[:]); err != nil {
+			return 0, err
+		}
+		return int64(binary.LittleEndian.Uint32(trailer[:])), nil
+
+	case CompressionZstd:
+		// The size of the content is written in the frame header
+		r, err := open(0)
+		if err != nil {
+			return 0, err
+		}
+		defer r.Close()
+
+		header := make([]byte, zstd.HeaderMaxSize)
+		n, err := io.ReadFull(r, header)
+		if err != nil && err != io.ErrUnexpectedEOF {
+			return 0, err
+		}
+
+		var h zstd.Header
+		if err := h.Decode(header[:n]); err != nil {
+			return 0, err
+		}
+		if h.HasFCS {
+			return int64(h.FrameContentSize), nil
+		}
+
+		// Files not compressed by the rotator may not have the content size
+		// in their header, in which case they are decompressed to count it
+		full, err := open(0)
+		if err != nil {
+			return 0, err
+		}
+		defer full.Close()
+		dr, err := NewDecompressReader(compression, full)
+		if err != nil {
+			return 0, err
+		}
+		defer dr.Close()
+		return io.Copy(io.Discard, dr)
+
+	default:
+		return 0, fmt.Errorf("unknown compression %q", compression)
+	}
+}
+
+// compressFile compresses a rotated log file with the given algorithm and
+// removes it once its compressed copy is complete. The copy is written to a
+// hidden temporary file so that readers never see a partial file.
+func compressFile(path, compression string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	fi, err := src.Stat()
+	if err != nil {
+		return err
+	}
+
+	dir, name := filepath.Split(path)
+	dstPath := path + compressionExt(compression)
+	tmpPath := filepath.Join(dir, "."+name+compressionExt(compression)+".tmp")
+	dst, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpPath)
+	defer dst.Close()
+
+	var w io.WriteCloser
+	switch compression {
+	case CompressionGzip:
+		w = gzip.NewWriter(dst)
+	case CompressionZstd:
+		enc, err := zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1))
+		if err != nil {
+			return err
+		}
+		// Write the content size in the frame header for DecompressedSize
+		enc.ResetContentSize(dst, fi.Size())
+		w = enc
+	default:
+		return fmt.Errorf("unknown compression %q", compression)
+	}
+
+	if _, err := io.Copy(w, src); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		return err
+	}
+
+	// Keep the modification time of the log file so that the age of the
+	// compressed file is the age of its last line
+	if err := os.Chtimes(tmpPath, fi.ModTime(), fi.ModTime()); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, dstPath); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
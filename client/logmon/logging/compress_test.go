@@ -0,0 +1,90 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package logging
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/shoenig/test/must"
+)
+
+func TestParseLogFileName(t *testing.T) {
+	cases := []struct {
+		name        string
+		idx         int
+		compression string
+		ok          bool
+	}{
+		{name: "redis.stdout.0", idx: 0, ok: true},
+		{name: "redis.stdout.12", idx: 12, ok: true},
+		{name: "redis.stdout.3.gz", idx: 3, compression: CompressionGzip, ok: true},
+		{name: "redis.stdout.4.zst", idx: 4, compression: CompressionZstd, ok: true},
+		{name: "redis.stderr.0"},
+		{name: "redis.stdout.gz"},
+		{name: "redis.stdout.-1"},
+		{name: ".redis.stdout.3.gz.tmp"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			idx, compression, ok := ParseLogFileName(tc.name, baseFileName)
+			must.Eq(t, tc.ok, ok)
+			must.Eq(t, tc.idx, idx)
+			must.Eq(t, tc.compression, compression)
+			if ok {
+				must.Eq(t, tc.name, LogFileName(baseFileName, idx, compression))
+			}
+		})
+	}
+}
+
+func TestCompressFile(t *testing.T) {
+	for _, compression := range []string{CompressionGzip, CompressionZstd} {
+		for _, content := range []string{"a log line\n", strings.Repeat("a log line\n", 1000)} {
+			t.Run(fmt.Sprintf("%s %d", compression, len(content)), func(t *testing.T) {
+				path := t.TempDir()
+				fname := filepath.Join(path, "redis.stdout.0")
+				must.NoError(t, os.WriteFile(fname, []byte(content), 0644))
+
+				must.NoError(t, compressFile(fname, compression))
+				_, err := os.Stat(fname)
+				must.True(t, os.IsNotExist(err))
+
+				// Only the compressed file is left
+				entries, err := os.ReadDir(path)
+				must.NoError(t, err)
+				must.Len(t, 1, entries)
+
+				compressed := filepath.Join(path, LogFileName(baseFileName, 0, compression))
+				fi, err := os.Stat(compressed)
+				must.NoError(t, err)
+
+				size, err := DecompressedSize(compression, fi.Size(), func(offset int64) (io.ReadCloser, error) {
+					f, err := os.Open(compressed)
+					if err != nil {
+						return nil, err
+					}
+					_, err = f.Seek(offset, io.SeekStart)
+					return f, err
+				})
+				must.NoError(t, err)
+				must.Eq(t, int64(len(content)), size)
+
+				f, err := os.Open(compressed)
+				must.NoError(t, err)
+				defer f.Close()
+				r, err := NewDecompressReader(compression, f)
+				must.NoError(t, err)
+				defer r.Close()
+				raw, err := io.ReadAll(r)
+				must.NoError(t, err)
+				must.Eq(t, content, string(raw))
+			})
+		}
+	}
+}
@@ -6,12 +6,9 @@ package logging
 import (
 	"bufio"
 	"bytes"
-	"fmt"
 	"os"
 	"path/filepath"
-	"sort"
-	"strconv"
-	"strings"
+	"slices"
 	"sync"
 	"time"
 
@@ -33,6 +30,10 @@ const (
 
 	// newLineDelimiter is the delimiter used for new lines.
 	newLineDelimiter = '\n'
+
+	// maxAgeCheckInterval is the maximum interval at which rotated files are
+	// checked for expiration when a maximum age is set.
+	maxAgeCheckInterval = 1 * time.Minute
 )
 
 // FileRotator writes bytes to a rotated set of files
@@ -40,6 +41,10 @@ type FileRotator struct {
 	MaxFiles int   // MaxFiles is the maximum number of rotated files allowed in a path
 	FileSize int64 // FileSize is the size a rotated file is allowed to grow
 
+	compression  string        // compression is the algorithm rotated files are compressed with
+	maxAge       time.Duration // maxAge is how long rotated files are retained
+	maxTotalSize int64         // maxTotalSize is the maximum size of all the files in a path
+
 	path         string // path is the path on the file system where the rotated set of files are opened
 	baseFileName string // baseFileName is the base file name of the rotated files
 	logFileIdx   int    // logFileIdx is the current index of the rotated files
@@ -59,9 +64,37 @@ type FileRotator struct {
 	doneCh      chan struct{}
 }
 
+// RotatorOption configures the retention of rotated files beyond the maximum
+// number of files.
+type RotatorOption func(*FileRotator)
+
+// WithCompression compresses the rotated files with the given algorithm,
+// either CompressionGzip or CompressionZstd.
+func WithCompression(compression string) RotatorOption {
+	return func(f *FileRotator) {
+		f.compression = compression
+	}
+}
+
+// WithMaxAge removes the rotated files last written longer than maxAge ago.
+func WithMaxAge(maxAge time.Duration) RotatorOption {
+	return func(f *FileRotator) {
+		f.maxAge = maxAge
+	}
+}
+
+// WithMaxTotalSize removes the oldest rotated files once the size of all the
+// files on disk exceeds maxTotalSize bytes, counting the current file at its
+// maximum size.
+func WithMaxTotalSize(maxTotalSize int64) RotatorOption {
+	return func(f *FileRotator) {
+		f.maxTotalSize = maxTotalSize
+	}
+}
+
 // NewFileRotator returns a new file rotator
 func NewFileRotator(path string, baseFile string, maxFiles int,
-	fileSize int64, logger hclog.Logger) (*FileRotator, error) {
+	fileSize int64, logger hclog.Logger, opts ...RotatorOption) (*FileRotator, error) {
 	logger = logger.Named("rotator")
 	rotator := &FileRotator{
 		MaxFiles: maxFiles,
@@ -75,10 +108,18 @@ func NewFileRotator(path string, baseFile string, maxFiles int,
 		purgeCh:     make(chan struct{}, 1),
 		doneCh:      make(chan struct{}),
 	}
+	for _, opt := range opts {
+		opt(rotator)
+	}
 
 	if err := rotator.lastFile(); err != nil {
 		return nil, err
 	}
+
+	// Apply the retention options to the files left by a previous rotator
+	if rotator.hasRetentionOptions() {
+		rotator.purgeCh <- struct{}{}
+	}
 	go rotator.purgeOldFiles()
 	go rotator.flushPeriodically()
 	return rotator, nil
@@ -165,22 +206,31 @@ func (f *FileRotator) nextFile() error {
 	nextFileIdx := f.logFileIdx
 	for {
 		nextFileIdx += 1
-		logFileName := filepath.Join(f.path, fmt.Sprintf("%s.%d", f.baseFileName, nextFileIdx))
+		logFileName := filepath.Join(f.path, LogFileName(f.baseFileName, nextFileIdx, ""))
 		if fi, err := os.Stat(logFileName); err == nil {
 			if fi.IsDir() || fi.Size() >= f.FileSize {
 				continue
 			}
 		}
+		if f.compression != "" {
+			compressedName := filepath.Join(f.path, LogFileName(f.baseFileName, nextFileIdx, f.compression))
+			if _, err := os.Stat(compressedName); err == nil {
+				continue
+			}
+		}
+		f.fileLock.Lock()
 		f.logFileIdx = nextFileIdx
+		f.fileLock.Unlock()
 		if err := f.createFile(); err != nil {
 			return err
 		}
 		break
 	}
-	// Purge old files if we have more files than MaxFiles
+	// Purge old files if we have more files than MaxFiles, and compress or
+	// expire the rotated file
 	f.fileLock.Lock()
 	defer f.fileLock.Unlock()
-	if f.logFileIdx-f.oldestLogFileIdx >= f.MaxFiles && !f.closed {
+	if (f.logFileIdx-f.oldestLogFileIdx >= f.MaxFiles || f.hasRetentionOptions()) && !f.closed {
 		select {
 		case f.purgeCh <- struct{}{}:
 		default:
@@ -196,20 +246,21 @@ func (f *FileRotator) lastFile() error {
 		return err
 	}
 
-	prefix := fmt.Sprintf("%s.", f.baseFileName)
 	for _, fi := range finfos {
 		if fi.IsDir() {
 			continue
 		}
-		if strings.HasPrefix(fi.Name(), prefix) {
-			fileIdx := strings.TrimPrefix(fi.Name(), prefix)
-			n, err := strconv.Atoi(fileIdx)
-			if err != nil {
-				continue
-			}
-			if n > f.logFileIdx {
-				f.logFileIdx = n
-			}
+		n, compression, ok := ParseLogFileName(fi.Name(), f.baseFileName)
+		if !ok {
+			continue
+		}
+		if n > f.logFileIdx {
+			f.logFileIdx = n
+		}
+
+		// Never append to a compressed file
+		if compression != "" && n == f.logFileIdx {
+			f.logFileIdx++
 		}
 	}
 	if err := f.createFile(); err != nil {
@@ -220,7 +271,7 @@ func (f *FileRotator) lastFile() error {
 
 // createFile opens a new or existing file for writing
 func (f *FileRotator) createFile() error {
-	logFileName := filepath.Join(f.path, fmt.Sprintf("%s.%d", f.baseFileName, f.logFileIdx))
+	logFileName := filepath.Join(f.path, LogFileName(f.baseFileName, f.logFileIdx, ""))
 	cFile, err := os.OpenFile(logFileName, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0644)
 	if err != nil {
 		return err
@@ -270,56 +321,165 @@ func (f *FileRotator) Close() error {
 	return nil
 }
 
+// hasRetentionOptions returns true if rotated files are compressed or expired
+// in addition to being limited by number.
+func (f *FileRotator) hasRetentionOptions() bool {
+	return f.compression != "" || f.maxAge > 0 || f.maxTotalSize > 0
+}
+
 // purgeOldFiles removes older files and keeps only the last N files rotated for
-// a file
+// a file. It also compresses the rotated files and removes the files that
+// exceed the maximum age and total size, if configured.
 func (f *FileRotator) purgeOldFiles() {
+	// Rotated files expire even if nothing is written
+	var ageCh <-chan time.Time
+	if f.maxAge > 0 {
+		ticker := time.NewTicker(min(f.maxAge, maxAgeCheckInterval))
+		defer ticker.Stop()
+		ageCh = ticker.C
+	}
+
 	for {
 		select {
 		case <-f.purgeCh:
-			var fIndexes []int
-			files, err := os.ReadDir(f.path)
-			if err != nil {
-				f.logger.Error("error getting directory listing", "error", err)
-				return
-			}
-			// Inserting all the rotated files in a slice
-			for _, fi := range files {
-				if strings.HasPrefix(fi.Name(), f.baseFileName) {
-					fileIdx := strings.TrimPrefix(fi.Name(), fmt.Sprintf("%s.", f.baseFileName))
-					n, err := strconv.Atoi(fileIdx)
-					if err != nil {
-						f.logger.Error("error extracting file index", "error", err)
-						continue
-					}
-					fIndexes = append(fIndexes, n)
-				}
-			}
+		case <-ageCh:
+		case <-f.doneCh:
+			return
+		}
+
+		if err := f.purge(); err != nil {
+			f.logger.Error("error getting directory listing", "error", err)
+			return
+		}
+	}
+}
 
-			// Not continuing to delete files if the number of files is not more
-			// than MaxFiles
-			if len(fIndexes) <= f.MaxFiles {
+// rotatedFile is a file written by the rotator.
+type rotatedFile struct {
+	idx         int
+	compression string
+	size        int64
+	modTime     time.Time
+}
+
+func (r *rotatedFile) name(baseFileName string) string {
+	return LogFileName(baseFileName, r.idx, r.compression)
+}
+
+// purge applies the retention options to the rotated files. It only returns
+// an error if the files can't be listed.
+func (f *FileRotator) purge() error {
+	f.fileLock.Lock()
+	currentIdx := f.logFileIdx
+	f.fileLock.Unlock()
+
+	entries, err := os.ReadDir(f.path)
+	if err != nil {
+		return err
+	}
+
+	// Inserting all the rotated files in a slice, preferring the uncompressed
+	// file if it is still being compressed
+	var files []*rotatedFile
+	byIdx := make(map[int]*rotatedFile)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		n, compression, ok := ParseLogFileName(entry.Name(), f.baseFileName)
+		if !ok {
+			continue
+		}
+		fi, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		file := &rotatedFile{idx: n, compression: compression, size: fi.Size(), modTime: fi.ModTime()}
+		if prev, ok := byIdx[n]; ok {
+			if compression != "" {
 				continue
 			}
+			*prev = *file
+			continue
+		}
+		byIdx[n] = file
+		files = append(files, file)
+	}
 
-			// Sorting the file indexes so that we can purge the older files and keep
-			// only the number of files as configured by the user
-			sort.Ints(fIndexes)
-			toDelete := fIndexes[0 : len(fIndexes)-f.MaxFiles]
-			for _, fIndex := range toDelete {
-				fname := filepath.Join(f.path, fmt.Sprintf("%s.%d", f.baseFileName, fIndex))
-				err := os.RemoveAll(fname)
-				if err != nil {
-					f.logger.Error("error removing file", "filename", fname, "error", err)
-				}
+	// Sorting the file indexes so that we can purge the older files and keep
+	// only the number of files as configured by the user
+	slices.SortFunc(files, func(a, b *rotatedFile) int { return a.idx - b.idx })
+
+	remove := func(file *rotatedFile) {
+		fname := filepath.Join(f.path, file.name(f.baseFileName))
+		if err := os.RemoveAll(fname); err != nil {
+			f.logger.Error("error removing file", "filename", fname, "error", err)
+		}
+	}
+
+	// Not deleting files by number if the number of files is not more than
+	// MaxFiles
+	if len(files) > f.MaxFiles {
+		for _, file := range files[:len(files)-f.MaxFiles] {
+			remove(file)
+		}
+		files = files[len(files)-f.MaxFiles:]
+	}
+
+	// Deleting the rotated files older than the maximum age. The current file
+	// is never deleted.
+	if f.maxAge > 0 {
+		cutoff := time.Now().Add(-f.maxAge)
+		for len(files) > 0 && files[0].idx < currentIdx && files[0].modTime.Before(cutoff) {
+			remove(files[0])
+			files = files[1:]
+		}
+	}
+
+	// Compressing the rotated files, including the ones left uncompressed by
+	// a previous rotator
+	if f.compression != "" {
+		for _, file := range files {
+			if file.idx >= currentIdx || file.compression != "" {
+				continue
+			}
+			fname := filepath.Join(f.path, file.name(f.baseFileName))
+			if err := compressFile(fname, f.compression); err != nil {
+				f.logger.Error("error compressing file", "filename", fname, "error", err)
+				continue
+			}
+			file.compression = f.compression
+			if fi, err := os.Stat(filepath.Join(f.path, file.name(f.baseFileName))); err == nil {
+				file.size = fi.Size()
 			}
+		}
+	}
 
-			f.fileLock.Lock()
-			f.oldestLogFileIdx = fIndexes[0]
-			f.fileLock.Unlock()
-		case <-f.doneCh:
-			return
+	// Deleting the oldest rotated files until all the files fit in the
+	// maximum total size. The current file is counted at its maximum size
+	// since it keeps growing until the next rotation.
+	if f.maxTotalSize > 0 {
+		var totalSize int64
+		for _, file := range files {
+			if file.idx >= currentIdx {
+				totalSize += max(file.size, f.FileSize)
+			} else {
+				totalSize += file.size
+			}
+		}
+		for len(files) > 0 && files[0].idx < currentIdx && totalSize > f.maxTotalSize {
+			totalSize -= files[0].size
+			remove(files[0])
+			files = files[1:]
 		}
 	}
+
+	if len(files) > 0 {
+		f.fileLock.Lock()
+		f.oldestLogFileIdx = files[0].idx
+		f.fileLock.Unlock()
+	}
+	return nil
 }
 
 // flushBuffer flushes the buffer
@@ -5,10 +5,13 @@ package logging
 
 import (
 	"fmt"
+	"io"
 	"math/rand"
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/nomad/helper/testlog"
 	"github.com/hashicorp/nomad/testutil"
@@ -275,6 +278,122 @@ func TestFileRotator_PurgeOldFiles(t *testing.T) {
 	})
 }
 
+func TestFileRotator_Compress(t *testing.T) {
+	for _, compression := range []string{CompressionGzip, CompressionZstd} {
+		t.Run(compression, func(t *testing.T) {
+			defer goleak.VerifyNone(t)
+
+			path := t.TempDir()
+
+			fr, err := NewFileRotator(path, baseFileName, 10, 6, testlog.HCLogger(t),
+				WithCompression(compression))
+			must.NoError(t, err)
+			defer fr.Close()
+
+			_, err = fr.Write([]byte("line0\nline1\nline2\n"))
+			must.NoError(t, err)
+
+			// The rotated files are compressed and the current one isn't
+			expected := []string{
+				LogFileName(baseFileName, 0, compression),
+				LogFileName(baseFileName, 1, compression),
+				"redis.stdout.2",
+			}
+			testutil.WaitForResult(func() (bool, error) {
+				entries, err := os.ReadDir(path)
+				if err != nil {
+					return false, err
+				}
+				var names []string
+				for _, entry := range entries {
+					names = append(names, entry.Name())
+				}
+				if !slices.Equal(expected, names) {
+					return false, fmt.Errorf("expected files %v, got %v", expected, names)
+				}
+				return true, nil
+			}, func(err error) {
+				must.NoError(t, err)
+			})
+
+			f, err := os.Open(filepath.Join(path, expected[1]))
+			must.NoError(t, err)
+			defer f.Close()
+			r, err := NewDecompressReader(compression, f)
+			must.NoError(t, err)
+			defer r.Close()
+			raw, err := io.ReadAll(r)
+			must.NoError(t, err)
+			must.Eq(t, "line1\n", string(raw))
+		})
+	}
+}
+
+func TestFileRotator_MaxTotalSize(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	path := t.TempDir()
+
+	fr, err := NewFileRotator(path, baseFileName, 10, 5, testlog.HCLogger(t),
+		WithMaxTotalSize(10))
+	must.NoError(t, err)
+	defer fr.Close()
+
+	_, err = fr.Write([]byte("abcd\nefgh\nijkl\nmnop\n"))
+	must.NoError(t, err)
+
+	// Only the last rotated file fits next to the current file
+	testutil.WaitForResult(func() (bool, error) {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return false, err
+		}
+		if len(entries) != 2 {
+			return false, fmt.Errorf("expected 2 files, got %v", entries)
+		}
+		if entries[0].Name() != "redis.stdout.2" {
+			return false, fmt.Errorf("expected redis.stdout.2 to be retained, got %v", entries)
+		}
+		return true, nil
+	}, func(err error) {
+		must.NoError(t, err)
+	})
+}
+
+func TestFileRotator_MaxAge(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	path := t.TempDir()
+
+	// Rotated files left by a previous rotator
+	old := time.Now().Add(-2 * time.Hour)
+	for _, name := range []string{"redis.stdout.0", "redis.stdout.1"} {
+		fname := filepath.Join(path, name)
+		must.NoError(t, os.WriteFile(fname, []byte("line\n"), 0644))
+		must.NoError(t, os.Chtimes(fname, old, old))
+	}
+	must.NoError(t, os.WriteFile(filepath.Join(path, "redis.stdout.2"), []byte("line\n"), 0644))
+
+	fr, err := NewFileRotator(path, baseFileName, 10, 1024, testlog.HCLogger(t),
+		WithMaxAge(time.Hour))
+	must.NoError(t, err)
+	defer fr.Close()
+
+	testutil.WaitForResult(func() (bool, error) {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return false, err
+		}
+		if len(entries) != 1 {
+			return false, fmt.Errorf("expected 1 file, got %v", entries)
+		}
+		return true, nil
+	}, func(err error) {
+		must.NoError(t, err)
+	})
+	must.Eq(t, filepath.Join(path, "redis.stdout.2"), fr.currentFile.Name())
+}
+
 func BenchmarkRotator(b *testing.B) {
 	kb := 1024
 	for _, inputSize := range []int{kb, 2 * kb, 4 * kb, 8 * kb, 16 * kb, 32 * kb, 64 * kb, 128 * kb, 256 * kb} {
@@ -0,0 +1,84 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package logging
+
+import (
+	"bytes"
+	"io"
+	"time"
+)
+
+// TimestampFormat is the format of the timestamps prefixing the log lines. It's
+// RFC3339 with a fixed number of fractional digits so that timestamps align.
+const TimestampFormat = "2006-01-02T15:04:05.000000000Z07:00"
+
+// TimestampWriter prefixes each line written to the underlying writer with the
+// time the line starts being written at and a space.
+type TimestampWriter struct {
+	io.WriteCloser
+
+	// lineStart is true if the next byte written starts a new line
+	lineStart bool
+
+	buf bytes.Buffer
+	now func() time.Time
+}
+
+// NewTimestampWriter returns a writer timestamping the lines written to w.
+func NewTimestampWriter(w io.WriteCloser) *TimestampWriter {
+	return &TimestampWriter{
+		WriteCloser: w,
+		lineStart:   true,
+		now:         time.Now,
+	}
+}
+
+// Write writes p to the underlying writer with the timestamps of the lines
+// starting in p. The returned count is the number of bytes of p written.
+func (w *TimestampWriter) Write(p []byte) (int, error) {
+	w.buf.Reset()
+
+	var ts []byte
+	rest := p
+	for len(rest) > 0 {
+		if w.lineStart {
+			if ts == nil {
+				ts = w.now().UTC().AppendFormat(nil, TimestampFormat)
+				ts = append(ts, ' ')
+			}
+			w.buf.Write(ts)
+			w.lineStart = false
+		}
+
+		i := bytes.IndexByte(rest, newLineDelimiter)
+		if i < 0 {
+			w.buf.Write(rest)
+			break
+		}
+		w.buf.Write(rest[:i+1])
+		rest = rest[i+1:]
+		w.lineStart = true
+	}
+
+	if _, err := w.WriteCloser.Write(w.buf.Bytes()); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// ParseTimestamp returns the timestamp prefixing a log line written by a
+// TimestampWriter and the rest of the line. ok is false if the line has no
+// timestamp.
+func ParseTimestamp(line []byte) (ts time.Time, rest []byte, ok bool) {
+	i := bytes.IndexByte(line, ' ')
+	if i < 0 {
+		return time.Time{}, line, false
+	}
+
+	ts, err := time.Parse(time.RFC3339Nano, string(line[:i]))
+	if err != nil {
+		return time.Time{}, line, false
+	}
+	return ts, line[i+1:], true
+}
@@ -0,0 +1,53 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package logging
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/shoenig/test/must"
+)
+
+type nopCloser struct {
+	bytes.Buffer
+}
+
+func (nopCloser) Close() error { return nil }
+
+func TestTimestampWriter(t *testing.T) {
+	var out nopCloser
+	w := NewTimestampWriter(&out)
+
+	now := time.Date(2026, 10, 16, 15, 0, 0, 0, time.UTC)
+	w.now = func() time.Time { return now }
+
+	n, err := w.Write([]byte("hello\nwor"))
+	must.NoError(t, err)
+	must.Eq(t, 9, n)
+
+	// The rest of a line keeps the timestamp of its start
+	now = now.Add(time.Second)
+	_, err = w.Write([]byte("ld\n\nbye\n"))
+	must.NoError(t, err)
+
+	must.Eq(t, "2026-10-16T15:00:00.000000000Z hello\n"+
+		"2026-10-16T15:00:00.000000000Z world\n"+
+		"2026-10-16T15:00:01.000000000Z \n"+
+		"2026-10-16T15:00:01.000000000Z bye\n", out.String())
+}
+
+func TestParseTimestamp(t *testing.T) {
+	ts, rest, ok := ParseTimestamp([]byte("2026-10-16T15:00:00.000000001Z hello world"))
+	must.True(t, ok)
+	must.Eq(t, time.Date(2026, 10, 16, 15, 0, 0, 1, time.UTC), ts)
+	must.Eq(t, "hello world", string(rest))
+
+	for _, line := range []string{"hello world", "hello", ""} {
+		_, rest, ok = ParseTimestamp([]byte(line))
+		must.False(t, ok)
+		must.Eq(t, line, string(rest))
+	}
+}
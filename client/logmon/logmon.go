@@ -14,6 +14,7 @@ import (
 	hclog "github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/nomad/client/lib/fifo"
 	"github.com/hashicorp/nomad/client/logmon/logging"
+	"github.com/hashicorp/nomad/client/logmon/sinks"
 )
 
 const (
@@ -44,6 +45,28 @@ type LogConfig struct {
 
 	// MaxFileSizeMB is the max log file size in MB allowed before rotation occures
 	MaxFileSizeMB int
+
+	// Compression is the algorithm rotated log files are compressed with, if
+	// any
+	Compression string
+
+	// MaxAge is how long rotated log files are retained, if set
+	MaxAge time.Duration
+
+	// MaxTotalSizeMB is the max size in MB of all the log files of a stream,
+	// if set
+	MaxTotalSizeMB int
+
+	// Timestamps prefixes each line written to the log files with the time
+	// it was written at
+	Timestamps bool
+
+	// Sinks are the external destinations the logs are shipped to in
+	// addition to the log files
+	Sinks []*sinks.Config
+
+	// Labels identify the task in the records shipped to the sinks
+	Labels map[string]string
 }
 
 type LogMon interface {
@@ -109,6 +132,9 @@ type TaskLogger struct {
 
 	// rotator for stderr
 	lre *logRotatorWrapper
+
+	// sinks ship both stdout and stderr to external destinations
+	sinks []*sinks.Sink
 }
 
 // IsRunning will return true as long as one rotator wrapper is still running
@@ -136,33 +162,61 @@ func (tl *TaskLogger) Close() {
 		}()
 	}
 	wg.Wait()
+
+	// The sinks are closed once the rotators stopped writing to them
+	for _, sink := range tl.sinks {
+		wg.Add(1)
+		go func() {
+			sink.Close()
+			wg.Done()
+		}()
+	}
+	wg.Wait()
 }
 
 func NewTaskLogger(cfg *LogConfig, logger hclog.Logger) (*TaskLogger, error) {
 	tl := &TaskLogger{config: cfg}
 
+	for _, sinkCfg := range cfg.Sinks {
+		sink, err := sinks.New(logger, sinkCfg, cfg.Labels)
+		if err != nil {
+			tl.Close()
+			return nil, fmt.Errorf("failed to create %s log sink: %v", sinkCfg.Type, err)
+		}
+		tl.sinks = append(tl.sinks, sink)
+	}
+
 	logFileSize := int64(cfg.MaxFileSizeMB * 1024 * 1024)
+	rotatorOpts := []logging.RotatorOption{
+		logging.WithCompression(cfg.Compression),
+		logging.WithMaxAge(cfg.MaxAge),
+		logging.WithMaxTotalSize(int64(cfg.MaxTotalSizeMB * 1024 * 1024)),
+	}
 	lro, err := logging.NewFileRotator(cfg.LogDir, cfg.StdoutLogFile,
-		cfg.MaxFiles, logFileSize, logger)
+		cfg.MaxFiles, logFileSize, logger, rotatorOpts...)
 	if err != nil {
+		tl.Close()
 		return nil, fmt.Errorf("failed to create stdout logfile for %q: %v", cfg.StdoutLogFile, err)
 	}
 
-	wrapperOut, err := newLogRotatorWrapper(cfg.StdoutFifo, logger, lro)
+	wrapperOut, err := newLogRotatorWrapper(cfg.StdoutFifo, logger, tl.withSinks("stdout", tl.withTimestamps(lro)))
 	if err != nil {
+		tl.Close()
 		return nil, err
 	}
 
 	tl.lro = wrapperOut
 
 	lre, err := logging.NewFileRotator(cfg.LogDir, cfg.StderrLogFile,
-		cfg.MaxFiles, logFileSize, logger)
+		cfg.MaxFiles, logFileSize, logger, rotatorOpts...)
 	if err != nil {
+		tl.Close()
 		return nil, fmt.Errorf("failed to create stderr logfile for %q: %v", cfg.StderrLogFile, err)
 	}
 
-	wrapperErr, err := newLogRotatorWrapper(cfg.StderrFifo, logger, lre)
+	wrapperErr, err := newLogRotatorWrapper(cfg.StderrFifo, logger, tl.withSinks("stderr", tl.withTimestamps(lre)))
 	if err != nil {
+		tl.Close()
 		return nil, err
 	}
 
@@ -172,6 +226,51 @@ func NewTaskLogger(cfg *LogConfig, logger hclog.Logger) (*TaskLogger, error) {
 
 }
 
+// withTimestamps returns a writer timestamping the lines written to the
+// rotator if the task logs are timestamped, or the rotator otherwise.
+func (tl *TaskLogger) withTimestamps(rotator io.WriteCloser) io.WriteCloser {
+	if !tl.config.Timestamps {
+		return rotator
+	}
+	return logging.NewTimestampWriter(rotator)
+}
+
+// withSinks returns a writer copying the stream to the rotator and the sinks
+// of the task, or the rotator if the task has no sinks.
+func (tl *TaskLogger) withSinks(stream string, rotator io.WriteCloser) io.WriteCloser {
+	if len(tl.sinks) == 0 {
+		return rotator
+	}
+	return &sinksWriter{
+		WriteCloser: rotator,
+		sinks:       sinks.NewWriter(stream, tl.sinks),
+	}
+}
+
+// sinksWriter copies the data written to a rotator to the sinks.
+type sinksWriter struct {
+	io.WriteCloser
+	sinks *sinks.Writer
+
+	// lock guards the sinks writer, as the rotator may be closed while the
+	// output of the task is still being copied
+	lock sync.Mutex
+}
+
+func (w *sinksWriter) Write(p []byte) (int, error) {
+	w.lock.Lock()
+	w.sinks.Write(p)
+	w.lock.Unlock()
+	return w.WriteCloser.Write(p)
+}
+
+func (w *sinksWriter) Close() error {
+	w.lock.Lock()
+	w.sinks.Flush()
+	w.lock.Unlock()
+	return w.WriteCloser.Close()
+}
+
 // logRotatorWrapper wraps our log rotator and exposes a pipe that can feed the
 // log rotator data. The processOutWriter should be attached to the process and
 // data will be copied from the reader to the rotator.
@@ -6,13 +6,17 @@ package logmon
 import (
 	"crypto/rand"
 	"fmt"
+	"net"
 	"os"
 	"path/filepath"
 	"runtime"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/nomad/ci"
 	"github.com/hashicorp/nomad/client/lib/fifo"
+	"github.com/hashicorp/nomad/client/logmon/logging"
+	"github.com/hashicorp/nomad/client/logmon/sinks"
 	"github.com/hashicorp/nomad/helper/testlog"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/testutil"
@@ -304,3 +308,110 @@ func TestLogmon_NewError(t *testing.T) {
 	must.Error(t, err)
 	must.Nil(t, w)
 }
+
+// TestLogmon_Start_sinks asserts that the task logs are shipped to the sinks
+// with the task labels in addition to being written to the log files.
+func TestLogmon_Start_sinks(t *testing.T) {
+	ci.Parallel(t)
+
+	if runtime.GOOS == "windows" {
+		t.Skip("windows does not support unixgram sockets")
+	}
+
+	dir := t.TempDir()
+	sockPath := filepath.Join(dir, "syslog.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: sockPath, Net: "unixgram"})
+	must.NoError(t, err)
+	defer conn.Close()
+
+	stdoutFifoPath := filepath.Join(dir, "stdout.fifo")
+	cfg := &LogConfig{
+		LogDir:        dir,
+		StdoutLogFile: "stdout",
+		StdoutFifo:    stdoutFifoPath,
+		StderrLogFile: "stderr",
+		StderrFifo:    filepath.Join(dir, "stderr.fifo"),
+		MaxFiles:      2,
+		MaxFileSizeMB: 1,
+		Sinks: []*sinks.Config{{
+			Type:       sinks.TypeSyslog,
+			Address:    "unix://" + sockPath,
+			BufferSize: 10,
+			DropPolicy: sinks.DropOldest,
+		}},
+		Labels: map[string]string{"job": "example", "task": "web"},
+	}
+
+	lm := NewLogMon(testlog.HCLogger(t))
+	must.NoError(t, lm.Start(cfg))
+	defer lm.Stop()
+
+	stdout, err := fifo.OpenWriter(stdoutFifoPath)
+	must.NoError(t, err)
+	_, err = stdout.Write([]byte("hello\n"))
+	must.NoError(t, err)
+
+	buf := make([]byte, 1024)
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, err := conn.Read(buf)
+	must.NoError(t, err)
+	must.StrHasSuffix(t, `[nomad@32473 job="example" task="web"] hello`, string(buf[:n]))
+
+	testutil.WaitForResult(func() (bool, error) {
+		raw, err := os.ReadFile(filepath.Join(dir, "stdout.0"))
+		if err != nil {
+			return false, err
+		}
+		return string(raw) == "hello\n", fmt.Errorf("unexpected log file content %q", raw)
+	}, func(err error) {
+		must.NoError(t, err)
+	})
+}
+
+func TestLogmon_Start_timestamps(t *testing.T) {
+	ci.Parallel(t)
+
+	dir := t.TempDir()
+	stdoutFifoPath := filepath.Join(dir, "stdout.fifo")
+	stderrFifoPath := filepath.Join(dir, "stderr.fifo")
+	if runtime.GOOS == "windows" {
+		stdoutFifoPath = "//./pipe/test-timestamps.stdout"
+		stderrFifoPath = "//./pipe/test-timestamps.stderr"
+	}
+	cfg := &LogConfig{
+		LogDir:        dir,
+		StdoutLogFile: "stdout",
+		StdoutFifo:    stdoutFifoPath,
+		StderrLogFile: "stderr",
+		StderrFifo:    stderrFifoPath,
+		MaxFiles:      2,
+		MaxFileSizeMB: 1,
+		Timestamps:    true,
+	}
+
+	lm := NewLogMon(testlog.HCLogger(t))
+	must.NoError(t, lm.Start(cfg))
+	defer lm.Stop()
+
+	stdout, err := fifo.OpenWriter(stdoutFifoPath)
+	must.NoError(t, err)
+	_, err = stdout.Write([]byte("hello\n"))
+	must.NoError(t, err)
+
+	testutil.WaitForResult(func() (bool, error) {
+		raw, err := os.ReadFile(filepath.Join(dir, "stdout.0"))
+		if err != nil {
+			return false, err
+		}
+		ts, rest, ok := logging.ParseTimestamp(raw)
+		if !ok {
+			return false, fmt.Errorf("log file content %q is not timestamped", raw)
+		}
+		if time.Since(ts) > time.Minute {
+			return false, fmt.Errorf("unexpected timestamp %s", ts)
+		}
+		return string(rest) == "hello\n", fmt.Errorf("unexpected log file content %q", raw)
+	}, func(err error) {
+		must.NoError(t, err)
+	})
+}
@@ -25,13 +25,20 @@ var _ = math.Inf
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
 type StartRequest struct {
-	LogDir               string   `protobuf:"bytes,1,opt,name=log_dir,json=logDir,proto3" json:"log_dir,omitempty"`
-	StdoutFileName       string   `protobuf:"bytes,2,opt,name=stdout_file_name,json=stdoutFileName,proto3" json:"stdout_file_name,omitempty"`
-	StderrFileName       string   `protobuf:"bytes,3,opt,name=stderr_file_name,json=stderrFileName,proto3" json:"stderr_file_name,omitempty"`
-	MaxFiles             uint32   `protobuf:"varint,4,opt,name=max_files,json=maxFiles,proto3" json:"max_files,omitempty"`
-	MaxFileSizeMb        uint32   `protobuf:"varint,5,opt,name=max_file_size_mb,json=maxFileSizeMb,proto3" json:"max_file_size_mb,omitempty"`
-	StdoutFifo           string   `protobuf:"bytes,6,opt,name=stdout_fifo,json=stdoutFifo,proto3" json:"stdout_fifo,omitempty"`
-	StderrFifo           string   `protobuf:"bytes,7,opt,name=stderr_fifo,json=stderrFifo,proto3" json:"stderr_fifo,omitempty"`
+	LogDir         string            `protobuf:"bytes,1,opt,name=log_dir,json=logDir,proto3" json:"log_dir,omitempty"`
+	StdoutFileName string            `protobuf:"bytes,2,opt,name=stdout_file_name,json=stdoutFileName,proto3" json:"stdout_file_name,omitempty"`
+	StderrFileName string            `protobuf:"bytes,3,opt,name=stderr_file_name,json=stderrFileName,proto3" json:"stderr_file_name,omitempty"`
+	MaxFiles       uint32            `protobuf:"varint,4,opt,name=max_files,json=maxFiles,proto3" json:"max_files,omitempty"`
+	MaxFileSizeMb  uint32            `protobuf:"varint,5,opt,name=max_file_size_mb,json=maxFileSizeMb,proto3" json:"max_file_size_mb,omitempty"`
+	StdoutFifo     string            `protobuf:"bytes,6,opt,name=stdout_fifo,json=stdoutFifo,proto3" json:"stdout_fifo,omitempty"`
+	StderrFifo     string            `protobuf:"bytes,7,opt,name=stderr_fifo,json=stderrFifo,proto3" json:"stderr_fifo,omitempty"`
+	Labels         map[string]string `protobuf:"bytes,8,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Sinks          []*LogSink        `protobuf:"bytes,9,rep,name=sinks,proto3" json:"sinks,omitempty"`
+	Compression    string            `protobuf:"bytes,10,opt,name=compression,proto3" json:"compression,omitempty"`
+	// max_age is in nanoseconds
+	MaxAge               int64    `protobuf:"varint,11,opt,name=max_age,json=maxAge,proto3" json:"max_age,omitempty"`
+	MaxTotalSizeMb       uint32   `protobuf:"varint,12,opt,name=max_total_size_mb,json=maxTotalSizeMb,proto3" json:"max_total_size_mb,omitempty"`
+	Timestamps           bool     `protobuf:"varint,13,opt,name=timestamps,proto3" json:"timestamps,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -111,6 +118,48 @@ func (m *StartRequest) GetStderrFifo() string {
 	return ""
 }
 
+func (m *StartRequest) GetLabels() map[string]string {
+	if m != nil {
+		return m.Labels
+	}
+	return nil
+}
+
+func (m *StartRequest) GetSinks() []*LogSink {
+	if m != nil {
+		return m.Sinks
+	}
+	return nil
+}
+
+func (m *StartRequest) GetCompression() string {
+	if m != nil {
+		return m.Compression
+	}
+	return ""
+}
+
+func (m *StartRequest) GetMaxAge() int64 {
+	if m != nil {
+		return m.MaxAge
+	}
+	return 0
+}
+
+func (m *StartRequest) GetMaxTotalSizeMb() uint32 {
+	if m != nil {
+		return m.MaxTotalSizeMb
+	}
+	return 0
+}
+
+func (m *StartRequest) GetTimestamps() bool {
+	if m != nil {
+		return m.Timestamps
+	}
+	return false
+}
+
 type StartResponse struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -204,11 +253,93 @@ func (m *StopResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_StopResponse proto.InternalMessageInfo
 
+type LogSink struct {
+	Type          string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Address       string `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+	BufferSize    uint32 `protobuf:"varint,3,opt,name=buffer_size,json=bufferSize,proto3" json:"buffer_size,omitempty"`
+	RetryAttempts uint32 `protobuf:"varint,4,opt,name=retry_attempts,json=retryAttempts,proto3" json:"retry_attempts,omitempty"`
+	// retry_interval is in nanoseconds
+	RetryInterval        int64    `protobuf:"varint,5,opt,name=retry_interval,json=retryInterval,proto3" json:"retry_interval,omitempty"`
+	DropPolicy           string   `protobuf:"bytes,6,opt,name=drop_policy,json=dropPolicy,proto3" json:"drop_policy,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LogSink) Reset()         { *m = LogSink{} }
+func (m *LogSink) String() string { return proto.CompactTextString(m) }
+func (*LogSink) ProtoMessage()    {}
+func (*LogSink) Descriptor() ([]byte, []int) {
+	return fileDescriptor_be72d5e24d2ecba6, []int{4}
+}
+
+func (m *LogSink) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LogSink.Unmarshal(m, b)
+}
+func (m *LogSink) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LogSink.Marshal(b, m, deterministic)
+}
+func (m *LogSink) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LogSink.Merge(m, src)
+}
+func (m *LogSink) XXX_Size() int {
+	return xxx_messageInfo_LogSink.Size(m)
+}
+func (m *LogSink) XXX_DiscardUnknown() {
+	xxx_messageInfo_LogSink.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LogSink proto.InternalMessageInfo
+
+func (m *LogSink) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *LogSink) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *LogSink) GetBufferSize() uint32 {
+	if m != nil {
+		return m.BufferSize
+	}
+	return 0
+}
+
+func (m *LogSink) GetRetryAttempts() uint32 {
+	if m != nil {
+		return m.RetryAttempts
+	}
+	return 0
+}
+
+func (m *LogSink) GetRetryInterval() int64 {
+	if m != nil {
+		return m.RetryInterval
+	}
+	return 0
+}
+
+func (m *LogSink) GetDropPolicy() string {
+	if m != nil {
+		return m.DropPolicy
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*StartRequest)(nil), "hashicorp.nomad.client.logmon.proto.StartRequest")
+	proto.RegisterMapType((map[string]string)(nil), "hashicorp.nomad.client.logmon.proto.StartRequest.LabelsEntry")
 	proto.RegisterType((*StartResponse)(nil), "hashicorp.nomad.client.logmon.proto.StartResponse")
 	proto.RegisterType((*StopRequest)(nil), "hashicorp.nomad.client.logmon.proto.StopRequest")
 	proto.RegisterType((*StopResponse)(nil), "hashicorp.nomad.client.logmon.proto.StopResponse")
+	proto.RegisterType((*LogSink)(nil), "hashicorp.nomad.client.logmon.proto.LogSink")
 }
 
 func init() {
@@ -216,27 +347,43 @@ func init() {
 }
 
 var fileDescriptor_be72d5e24d2ecba6 = []byte{
-	// 320 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x90, 0x31, 0x6f, 0xc2, 0x30,
-	0x10, 0x85, 0x1b, 0x0a, 0xa1, 0x1c, 0x0d, 0x45, 0x5e, 0x1a, 0xd1, 0xa1, 0x28, 0x1d, 0xca, 0x14,
-	0x0a, 0xfd, 0x07, 0x55, 0xd5, 0xa9, 0x74, 0x80, 0xad, 0x4b, 0x64, 0xc0, 0x09, 0x96, 0x62, 0x5f,
-	0x6a, 0x1b, 0x09, 0xb1, 0xf6, 0xd7, 0xf6, 0x5f, 0x54, 0x71, 0x4c, 0x94, 0x11, 0x26, 0xeb, 0xee,
-	0x7d, 0x4f, 0xf7, 0xfc, 0x60, 0xbc, 0xc9, 0x39, 0x93, 0x66, 0x9a, 0x63, 0x26, 0x50, 0x4e, 0x0b,
-	0x85, 0x06, 0xdd, 0x10, 0xdb, 0x81, 0x3c, 0xed, 0xa8, 0xde, 0xf1, 0x0d, 0xaa, 0x22, 0x96, 0x28,
-	0xe8, 0x36, 0xae, 0x1c, 0x71, 0x13, 0x8a, 0x7e, 0x5b, 0x70, 0xbb, 0x32, 0x54, 0x99, 0x25, 0xfb,
-	0xd9, 0x33, 0x6d, 0xc8, 0x3d, 0x74, 0x73, 0xcc, 0x92, 0x2d, 0x57, 0xa1, 0x37, 0xf6, 0x26, 0xbd,
-	0xa5, 0x9f, 0x63, 0xf6, 0xce, 0x15, 0x99, 0xc0, 0x50, 0x9b, 0x2d, 0xee, 0x4d, 0x92, 0xf2, 0x9c,
-	0x25, 0x92, 0x0a, 0x16, 0xb6, 0x2c, 0x31, 0xa8, 0xf6, 0x1f, 0x3c, 0x67, 0x5f, 0x54, 0x30, 0x47,
-	0x32, 0xa5, 0x1a, 0xe4, 0x75, 0x4d, 0x32, 0xa5, 0x6a, 0xf2, 0x01, 0x7a, 0x82, 0x1e, 0x2c, 0xa6,
-	0xc3, 0xf6, 0xd8, 0x9b, 0x04, 0xcb, 0x1b, 0x41, 0x0f, 0xa5, 0xae, 0xc9, 0x33, 0x0c, 0x4f, 0x62,
-	0xa2, 0xf9, 0x91, 0x25, 0x62, 0x1d, 0x76, 0x2c, 0x13, 0x38, 0x66, 0xc5, 0x8f, 0x6c, 0xb1, 0x26,
-	0x8f, 0xd0, 0xaf, 0x93, 0xa5, 0x18, 0xfa, 0xf6, 0x14, 0x9c, 0x42, 0xa5, 0xe8, 0x80, 0x2a, 0x50,
-	0x8a, 0x61, 0xb7, 0x06, 0x6c, 0x96, 0x14, 0xa3, 0x3b, 0x08, 0x5c, 0x09, 0xba, 0x40, 0xa9, 0x59,
-	0x14, 0x40, 0x7f, 0x65, 0xb0, 0x70, 0xa5, 0x44, 0x83, 0xb2, 0xa4, 0x72, 0xac, 0xe4, 0xf9, 0x9f,
-	0x07, 0xfe, 0x27, 0x66, 0x0b, 0x94, 0xa4, 0x80, 0x8e, 0xb5, 0x92, 0x59, 0x7c, 0x46, 0xdf, 0x71,
-	0xb3, 0xeb, 0xd1, 0xfc, 0x12, 0x8b, 0x4b, 0x76, 0x45, 0x04, 0xb4, 0xcb, 0x30, 0xe4, 0xe5, 0x4c,
-	0x77, 0xfd, 0x8d, 0xd1, 0xec, 0x02, 0xc7, 0xe9, 0xdc, 0x5b, 0xf7, 0xbb, 0x63, 0xf7, 0x6b, 0xdf,
-	0x3e, 0xaf, 0xff, 0x01, 0x00, 0x00, 0xff, 0xff, 0xd8, 0xb4, 0x87, 0xfc, 0x7a, 0x02, 0x00, 0x00,
+	// 567 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x52, 0x4d, 0x6f, 0xd4, 0x30,
+	0x10, 0x25, 0xdd, 0xaf, 0x76, 0xb6, 0x59, 0x8a, 0x85, 0x84, 0x55, 0x24, 0x88, 0x16, 0x21, 0x82,
+	0x84, 0x52, 0x5a, 0x2e, 0x80, 0xc4, 0xa1, 0x15, 0x20, 0x21, 0xb5, 0x08, 0x65, 0xe1, 0xc2, 0x25,
+	0xf2, 0x6e, 0x9c, 0xd4, 0xaa, 0x1d, 0x07, 0xdb, 0x5b, 0xed, 0xf6, 0x0f, 0xf2, 0x33, 0x38, 0xf3,
+	0x2f, 0x90, 0x1d, 0x27, 0xe4, 0xd8, 0x9e, 0x92, 0x79, 0xf3, 0xc6, 0x33, 0xf3, 0xe6, 0x41, 0xb4,
+	0xe2, 0x8c, 0x56, 0xe6, 0x88, 0xcb, 0x52, 0xc8, 0xea, 0xa8, 0x56, 0xd2, 0x48, 0x1f, 0x24, 0x2e,
+	0x40, 0xcf, 0x2e, 0x89, 0xbe, 0x64, 0x2b, 0xa9, 0xea, 0xa4, 0x92, 0x82, 0xe4, 0x49, 0x53, 0x91,
+	0xf4, 0x49, 0xf3, 0x3f, 0x43, 0xd8, 0x5f, 0x18, 0xa2, 0x4c, 0x4a, 0x7f, 0xad, 0xa9, 0x36, 0xe8,
+	0x11, 0x4c, 0xb8, 0x2c, 0xb3, 0x9c, 0x29, 0x1c, 0x44, 0x41, 0xbc, 0x97, 0x8e, 0xb9, 0x2c, 0x3f,
+	0x32, 0x85, 0x62, 0x38, 0xd0, 0x26, 0x97, 0x6b, 0x93, 0x15, 0x8c, 0xd3, 0xac, 0x22, 0x82, 0xe2,
+	0x1d, 0xc7, 0x98, 0x35, 0xf8, 0x67, 0xc6, 0xe9, 0x57, 0x22, 0xa8, 0x67, 0x52, 0xa5, 0x7a, 0xcc,
+	0x41, 0xc7, 0xa4, 0x4a, 0x75, 0xcc, 0xc7, 0xb0, 0x27, 0xc8, 0xc6, 0xd1, 0x34, 0x1e, 0x46, 0x41,
+	0x1c, 0xa6, 0xbb, 0x82, 0x6c, 0x6c, 0x5e, 0xa3, 0x17, 0x70, 0xd0, 0x26, 0x33, 0xcd, 0x6e, 0x68,
+	0x26, 0x96, 0x78, 0xe4, 0x38, 0xa1, 0xe7, 0x2c, 0xd8, 0x0d, 0xbd, 0x58, 0xa2, 0xa7, 0x30, 0xed,
+	0x26, 0x2b, 0x24, 0x1e, 0xbb, 0x56, 0xd0, 0x0e, 0x55, 0x48, 0x4f, 0x68, 0x06, 0x2a, 0x24, 0x9e,
+	0x74, 0x04, 0x37, 0x4b, 0x21, 0xd1, 0x0f, 0x18, 0x73, 0xb2, 0xa4, 0x5c, 0xe3, 0xdd, 0x68, 0x10,
+	0x4f, 0x4f, 0x3e, 0x24, 0xb7, 0xd0, 0x2e, 0xe9, 0xeb, 0x96, 0x9c, 0xbb, 0xfa, 0x4f, 0x95, 0x51,
+	0xdb, 0xd4, 0x3f, 0x86, 0xce, 0x60, 0xa4, 0x59, 0x75, 0xa5, 0xf1, 0x9e, 0x7b, 0xf5, 0xd5, 0xad,
+	0x5e, 0x3d, 0x97, 0xe5, 0x82, 0x55, 0x57, 0x69, 0x53, 0x8a, 0x22, 0x98, 0xae, 0xa4, 0xa8, 0x15,
+	0xd5, 0x9a, 0xc9, 0x0a, 0x83, 0x9b, 0xbd, 0x0f, 0xd9, 0x8b, 0x59, 0x9d, 0x48, 0x49, 0xf1, 0x34,
+	0x0a, 0xe2, 0x41, 0x3a, 0x16, 0x64, 0x73, 0x5a, 0x52, 0xf4, 0x12, 0x1e, 0xd8, 0x84, 0x91, 0x86,
+	0xf0, 0x4e, 0xc1, 0x7d, 0xa7, 0xe0, 0x4c, 0x90, 0xcd, 0x77, 0x8b, 0x7b, 0x09, 0x9f, 0x00, 0x18,
+	0x26, 0xa8, 0x36, 0x44, 0xd4, 0x1a, 0x87, 0x51, 0x10, 0xef, 0xa6, 0x3d, 0xe4, 0xf0, 0x1d, 0x4c,
+	0x7b, 0x0b, 0xa2, 0x03, 0x18, 0x5c, 0xd1, 0xad, 0x37, 0x88, 0xfd, 0x45, 0x0f, 0x61, 0x74, 0x4d,
+	0xf8, 0xba, 0xb5, 0x44, 0x13, 0xbc, 0xdf, 0x79, 0x1b, 0xcc, 0xef, 0x43, 0xe8, 0x85, 0xd2, 0xb5,
+	0xac, 0x34, 0x9d, 0x87, 0x30, 0x5d, 0x18, 0x59, 0x7b, 0xe1, 0xe6, 0x33, 0xd8, 0x6f, 0x42, 0x9f,
+	0xfe, 0x1d, 0xc0, 0xc4, 0x6b, 0x80, 0x10, 0x0c, 0xcd, 0xb6, 0xa6, 0xbe, 0x91, 0xfb, 0x47, 0x18,
+	0x26, 0x24, 0xcf, 0xed, 0xf2, 0xbe, 0x57, 0x1b, 0xda, 0x33, 0x2f, 0xd7, 0x45, 0x41, 0x95, 0x5b,
+	0xd6, 0x59, 0x2e, 0x4c, 0xa1, 0x81, 0xec, 0x9e, 0xe8, 0x39, 0xcc, 0x14, 0x35, 0x6a, 0x9b, 0x11,
+	0x63, 0xa8, 0xa8, 0x4d, 0xeb, 0xb9, 0xd0, 0xa1, 0xa7, 0x1e, 0xfc, 0x4f, 0x63, 0x95, 0xa1, 0xea,
+	0x9a, 0x70, 0x67, 0xbb, 0x81, 0xa7, 0x7d, 0xf1, 0xa0, 0x6d, 0x97, 0x2b, 0x59, 0x67, 0xb5, 0xe4,
+	0x6c, 0xb5, 0x6d, 0x6d, 0x67, 0xa1, 0x6f, 0x0e, 0x39, 0xf9, 0x1b, 0xc0, 0xf8, 0x5c, 0x96, 0x17,
+	0xb2, 0x42, 0x35, 0x8c, 0x9c, 0x08, 0xe8, 0xf8, 0xce, 0xce, 0x3a, 0x3c, 0xb9, 0x4b, 0x89, 0x17,
+	0xf1, 0x1e, 0x12, 0x30, 0xb4, 0xb2, 0xa2, 0xd7, 0xb7, 0xac, 0xee, 0x0e, 0x72, 0x78, 0x7c, 0x87,
+	0x8a, 0xb6, 0xdd, 0xd9, 0xe4, 0xe7, 0xc8, 0xe1, 0xcb, 0xb1, 0xfb, 0xbc, 0xf9, 0x37, 0x00, 0x35,
+	0xf2, 0x12, 0x11, 0xa0, 0x04, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -5,9 +5,11 @@ package logmon
 
 import (
 	"context"
+	"time"
 
 	"github.com/hashicorp/go-plugin"
 	"github.com/hashicorp/nomad/client/logmon/proto"
+	"github.com/hashicorp/nomad/client/logmon/sinks"
 )
 
 type logmonServer struct {
@@ -17,13 +19,28 @@ type logmonServer struct {
 
 func (s *logmonServer) Start(ctx context.Context, req *proto.StartRequest) (*proto.StartResponse, error) {
 	cfg := &LogConfig{
-		LogDir:        req.LogDir,
-		StdoutLogFile: req.StdoutFileName,
-		StderrLogFile: req.StderrFileName,
-		MaxFiles:      int(req.MaxFiles),
-		MaxFileSizeMB: int(req.MaxFileSizeMb),
-		StdoutFifo:    req.StdoutFifo,
-		StderrFifo:    req.StderrFifo,
+		LogDir:         req.LogDir,
+		StdoutLogFile:  req.StdoutFileName,
+		StderrLogFile:  req.StderrFileName,
+		MaxFiles:       int(req.MaxFiles),
+		MaxFileSizeMB:  int(req.MaxFileSizeMb),
+		StdoutFifo:     req.StdoutFifo,
+		StderrFifo:     req.StderrFifo,
+		Compression:    req.Compression,
+		MaxAge:         time.Duration(req.MaxAge),
+		MaxTotalSizeMB: int(req.MaxTotalSizeMb),
+		Timestamps:     req.Timestamps,
+		Labels:         req.Labels,
+	}
+	for _, sink := range req.Sinks {
+		cfg.Sinks = append(cfg.Sinks, &sinks.Config{
+			Type:          sink.Type,
+			Address:       sink.Address,
+			BufferSize:    int(sink.BufferSize),
+			RetryAttempts: int(sink.RetryAttempts),
+			RetryInterval: time.Duration(sink.RetryInterval),
+			DropPolicy:    sink.DropPolicy,
+		})
 	}
 
 	err := s.impl.Start(cfg)
@@ -0,0 +1,84 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package sinks
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/hashicorp/go-msgpack/v2/codec"
+)
+
+// fluentTag is the tag of the events sent to fluent, used to route them.
+const fluentTag = "nomad"
+
+// fluentShipper sends batches of records to a fluentd or fluent-bit forward
+// input, as messages of the forward protocol in forward mode.
+type fluentShipper struct {
+	network string
+	address string
+	labels  map[string]string
+
+	conn net.Conn
+}
+
+func newFluentShipper(u *url.URL, labels map[string]string) (*fluentShipper, error) {
+	network, address, err := dialAddress(u, "unix")
+	if err != nil {
+		return nil, err
+	}
+	if network == "udp" {
+		return nil, fmt.Errorf("unsupported scheme %q in address %q", u.Scheme, u)
+	}
+
+	return &fluentShipper{
+		network: network,
+		address: address,
+		labels:  labels,
+	}, nil
+}
+
+func (s *fluentShipper) Ship(ctx context.Context, records []Record) error {
+	if s.conn == nil {
+		var d net.Dialer
+		dialCtx, cancel := context.WithTimeout(ctx, dialTimeout)
+		conn, err := d.DialContext(dialCtx, s.network, s.address)
+		cancel()
+		if err != nil {
+			return err
+		}
+		s.conn = conn
+	}
+
+	entries := make([]any, len(records))
+	for i, r := range records {
+		event := make(map[string]string, len(s.labels)+2)
+		for k, v := range s.labels {
+			event[k] = v
+		}
+		event["stream"] = r.Stream
+		event["message"] = r.Message
+		entries[i] = []any{r.Time.Unix(), event}
+	}
+
+	s.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	enc := codec.NewEncoder(s.conn, &codec.MsgpackHandle{})
+	if err := enc.Encode([]any{fluentTag, entries}); err != nil {
+		// Reconnect on the next attempt
+		s.conn.Close()
+		s.conn = nil
+		return err
+	}
+	return nil
+}
+
+func (s *fluentShipper) Close() error {
+	if s.conn == nil {
+		return nil
+	}
+	return s.conn.Close()
+}
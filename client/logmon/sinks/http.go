@@ -0,0 +1,85 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package sinks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	cleanhttp "github.com/hashicorp/go-cleanhttp"
+)
+
+// httpShipper posts batches of records as a JSON array to an HTTP endpoint.
+type httpShipper struct {
+	url    string
+	labels map[string]string
+	client *http.Client
+}
+
+// httpRecord is the JSON representation of a record.
+type httpRecord struct {
+	Time    time.Time         `json:"time"`
+	Stream  string            `json:"stream"`
+	Message string            `json:"message"`
+	Labels  map[string]string `json:"labels,omitempty"`
+}
+
+func newHTTPShipper(u *url.URL, labels map[string]string) (*httpShipper, error) {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported scheme %q in address %q", u.Scheme, u)
+	}
+
+	client := cleanhttp.DefaultPooledClient()
+	client.Timeout = writeTimeout
+	return &httpShipper{
+		url:    u.String(),
+		labels: labels,
+		client: client,
+	}, nil
+}
+
+func (s *httpShipper) Ship(ctx context.Context, records []Record) error {
+	batch := make([]httpRecord, len(records))
+	for i, r := range records {
+		batch[i] = httpRecord{
+			Time:    r.Time,
+			Stream:  r.Stream,
+			Message: r.Message,
+			Labels:  s.labels,
+		}
+	}
+	body, err := json.Marshal(batch)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected response status %q", resp.Status)
+	}
+	return nil
+}
+
+func (s *httpShipper) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
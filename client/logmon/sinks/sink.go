@@ -0,0 +1,259 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+// Package sinks ships the stdout and stderr of tasks collected by logmon to
+// external log destinations.
+package sinks
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/helper"
+)
+
+const (
+	// TypeSyslog ships records as RFC5424 syslog messages over a unix, udp or
+	// tcp socket.
+	TypeSyslog = "syslog"
+
+	// TypeHTTP ships batches of records as JSON to an HTTP endpoint.
+	TypeHTTP = "http"
+
+	// TypeFluent ships batches of records to a fluentd or fluent-bit
+	// forward input.
+	TypeFluent = "fluent"
+
+	// DropOldest drops the oldest buffered records when the buffer is full.
+	DropOldest = "oldest"
+
+	// DropNewest drops the incoming records when the buffer is full.
+	DropNewest = "newest"
+
+	// maxBatchSize is the maximum number of records shipped at once.
+	maxBatchSize = 256
+
+	// closeTimeout is how long Close waits for the buffered records to be
+	// shipped.
+	closeTimeout = 5 * time.Second
+)
+
+// Config configures a sink.
+type Config struct {
+	// Type is the type of the sink, one of TypeSyslog, TypeHTTP or
+	// TypeFluent.
+	Type string
+
+	// Address is the URL of the destination of the records, like
+	// udp://127.0.0.1:514 for syslog or https://logs.example.com for http.
+	Address string
+
+	// BufferSize is the maximum number of records buffered while they can't
+	// be shipped.
+	BufferSize int
+
+	// RetryAttempts is the number of times shipping a batch of records is
+	// retried before the batch is dropped.
+	RetryAttempts int
+
+	// RetryInterval is the time to wait between attempts.
+	RetryInterval time.Duration
+
+	// DropPolicy is which records are dropped when the buffer is full,
+	// either DropOldest or DropNewest.
+	DropPolicy string
+}
+
+// Record is a line written by a task to its stdout or stderr.
+type Record struct {
+	Time    time.Time
+	Stream  string
+	Message string
+}
+
+// shipper sends records to the destination of a sink.
+type shipper interface {
+	// Ship sends a batch of records. An error means the whole batch should
+	// be retried.
+	Ship(ctx context.Context, records []Record) error
+
+	Close() error
+}
+
+// Sink buffers the records of a task and ships them in the background, so
+// that a slow or unavailable destination never blocks the task output.
+type Sink struct {
+	config  *Config
+	shipper shipper
+	logger  hclog.Logger
+
+	buf     []Record
+	dropped int
+	lock    sync.Mutex
+
+	// ctx is cancelled once the close timeout expires, to give up on the
+	// records being shipped
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	notifyCh chan struct{}
+	closeCh  chan struct{}
+	doneCh   chan struct{}
+	closeMu  sync.Once
+}
+
+// New returns a sink shipping records to the destination of the config, with
+// the labels identifying the task attached to every record.
+func New(logger hclog.Logger, config *Config, labels map[string]string) (*Sink, error) {
+	u, err := url.Parse(config.Address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address %q: %w", config.Address, err)
+	}
+
+	var sh shipper
+	switch config.Type {
+	case TypeSyslog:
+		sh, err = newSyslogShipper(u, labels)
+	case TypeHTTP:
+		sh, err = newHTTPShipper(u, labels)
+	case TypeFluent:
+		sh, err = newFluentShipper(u, labels)
+	default:
+		err = fmt.Errorf("unknown sink type %q", config.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Sink{
+		config:   config,
+		shipper:  sh,
+		logger:   logger.Named("sink").With("type", config.Type, "address", config.Address),
+		notifyCh: make(chan struct{}, 1),
+		closeCh:  make(chan struct{}),
+		doneCh:   make(chan struct{}),
+	}
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	go s.run()
+	return s, nil
+}
+
+// Write buffers a record to be shipped. It never blocks: records are dropped
+// according to the drop policy when the buffer is full.
+func (s *Sink) Write(r Record) {
+	s.lock.Lock()
+	if len(s.buf) >= max(s.config.BufferSize, 1) {
+		s.dropped++
+		if s.config.DropPolicy == DropNewest {
+			s.lock.Unlock()
+			return
+		}
+		s.buf = s.buf[1:]
+	}
+	s.buf = append(s.buf, r)
+	s.lock.Unlock()
+
+	select {
+	case s.notifyCh <- struct{}{}:
+	default:
+	}
+}
+
+// Close ships the buffered records, waiting up to a few seconds, and closes
+// the connection to the destination.
+func (s *Sink) Close() {
+	s.closeMu.Do(func() {
+		close(s.closeCh)
+	})
+
+	// Give up on the buffered records once the close timeout expires, even
+	// if a batch is being shipped
+	timer := time.AfterFunc(closeTimeout, s.cancel)
+	defer timer.Stop()
+	<-s.doneCh
+}
+
+// next removes and returns the next batch of buffered records, and the number
+// of records dropped since the previous batch.
+func (s *Sink) next() ([]Record, int) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	n := min(len(s.buf), maxBatchSize)
+	batch := make([]Record, n)
+	copy(batch, s.buf)
+	s.buf = s.buf[n:]
+
+	dropped := s.dropped
+	s.dropped = 0
+	return batch, dropped
+}
+
+func (s *Sink) run() {
+	defer close(s.doneCh)
+	defer s.shipper.Close()
+	defer s.cancel()
+
+	closing := false
+	for {
+		// Batches aren't retried once the sink is closing
+		if !closing {
+			select {
+			case <-s.closeCh:
+				closing = true
+			default:
+			}
+		}
+
+		batch, dropped := s.next()
+		if dropped > 0 {
+			s.logger.Warn("dropped log records because the buffer is full", "dropped", dropped)
+		}
+
+		if len(batch) == 0 {
+			if closing {
+				return
+			}
+			select {
+			case <-s.notifyCh:
+			case <-s.closeCh:
+				closing = true
+			}
+			continue
+		}
+
+		if err := s.ship(s.ctx, batch, closing); err != nil {
+			s.logger.Warn("dropped log records that could not be shipped", "dropped", len(batch), "error", err)
+			if s.ctx.Err() != nil {
+				return
+			}
+		}
+	}
+}
+
+// ship sends a batch of records, retrying according to the retry policy of
+// the sink. Records aren't retried once the sink is closing.
+func (s *Sink) ship(ctx context.Context, batch []Record, closing bool) error {
+	var err error
+	for attempt := 0; ; attempt++ {
+		if err = s.shipper.Ship(ctx, batch); err == nil {
+			return nil
+		}
+		if closing || attempt >= s.config.RetryAttempts {
+			return err
+		}
+
+		timer, stop := helper.NewSafeTimer(s.config.RetryInterval)
+		select {
+		case <-timer.C:
+			stop()
+		case <-s.closeCh:
+			stop()
+			closing = true
+		}
+	}
+}
@@ -0,0 +1,308 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package sinks
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-msgpack/v2/codec"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/shoenig/test/must"
+	"github.com/shoenig/test/wait"
+)
+
+var testLabels = map[string]string{
+	"namespace": "default",
+	"job":       "example",
+	"group":     "web",
+	"alloc":     "7cd08c6c-86c8-0bfa-f7ca-338466447711",
+	"task":      "server",
+}
+
+func testSink(t *testing.T, config *Config) *Sink {
+	t.Helper()
+	s, err := New(testlog.HCLogger(t), config, testLabels)
+	must.NoError(t, err)
+	t.Cleanup(s.Close)
+	return s
+}
+
+func TestSink_New_Invalid(t *testing.T) {
+	ci.Parallel(t)
+
+	for _, config := range []*Config{
+		{Type: "kafka", Address: "tcp://127.0.0.1:9092"},
+		{Type: TypeSyslog, Address: "http://127.0.0.1:514"},
+		{Type: TypeSyslog, Address: "udp://"},
+		{Type: TypeHTTP, Address: "tcp://127.0.0.1:80"},
+		{Type: TypeFluent, Address: "udp://127.0.0.1:24224"},
+		{Type: TypeFluent, Address: "unix://"},
+	} {
+		_, err := New(testlog.HCLogger(t), config, nil)
+		must.Error(t, err, must.Sprintf("expected error for %s sink at %s", config.Type, config.Address))
+	}
+}
+
+func TestSink_Syslog_UDP(t *testing.T) {
+	ci.Parallel(t)
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	must.NoError(t, err)
+	defer conn.Close()
+
+	s := testSink(t, &Config{
+		Type:       TypeSyslog,
+		Address:    "udp://" + conn.LocalAddr().String(),
+		BufferSize: 10,
+	})
+	w := NewWriter("stderr", []*Sink{s})
+	_, err = w.Write([]byte("something failed\n"))
+	must.NoError(t, err)
+
+	buf := make([]byte, 1024)
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	must.NoError(t, err)
+
+	msg := string(buf[:n])
+	must.StrHasPrefix(t, "<11>1 ", msg)
+	must.StrContains(t, msg, ` server - stderr [nomad@32473 alloc="7cd08c6c-86c8-0bfa-f7ca-338466447711" group="web" job="example" namespace="default" task="server"] something failed`)
+}
+
+func TestSink_Syslog_TCP(t *testing.T) {
+	ci.Parallel(t)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	must.NoError(t, err)
+	defer ln.Close()
+
+	s := testSink(t, &Config{
+		Type:       TypeSyslog,
+		Address:    "tcp://" + ln.Addr().String(),
+		BufferSize: 10,
+	})
+	w := NewWriter("stdout", []*Sink{s})
+	_, err = w.Write([]byte("hello\nworld\n"))
+	must.NoError(t, err)
+
+	conn, err := ln.Accept()
+	must.NoError(t, err)
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	r := bufio.NewReader(conn)
+
+	// Messages are framed with octet counting
+	for _, expect := range []string{"hello", "world"} {
+		length, err := r.ReadString(' ')
+		must.NoError(t, err)
+		n, err := strconv.Atoi(strings.TrimSpace(length))
+		must.NoError(t, err)
+
+		msg := make([]byte, n)
+		_, err = io.ReadFull(r, msg)
+		must.NoError(t, err)
+		must.StrHasPrefix(t, "<14>1 ", string(msg))
+		must.StrHasSuffix(t, "] "+expect, string(msg))
+	}
+}
+
+func TestSink_Syslog_Unix(t *testing.T) {
+	ci.Parallel(t)
+
+	path := filepath.Join(t.TempDir(), "log.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	must.NoError(t, err)
+	defer conn.Close()
+
+	s := testSink(t, &Config{
+		Type:       TypeSyslog,
+		Address:    "unix://" + path,
+		BufferSize: 10,
+	})
+	NewWriter("stdout", []*Sink{s}).Write([]byte("hello\n"))
+
+	buf := make([]byte, 1024)
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, err := conn.Read(buf)
+	must.NoError(t, err)
+	must.StrHasSuffix(t, "] hello", string(buf[:n]))
+}
+
+func TestSink_HTTP(t *testing.T) {
+	ci.Parallel(t)
+
+	var lock sync.Mutex
+	var received []httpRecord
+	failures := 1
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		// The first batch is retried
+		if failures > 0 {
+			failures--
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		must.Eq(t, "application/json", r.Header.Get("Content-Type"))
+		var batch []httpRecord
+		must.NoError(t, json.NewDecoder(r.Body).Decode(&batch))
+		received = append(received, batch...)
+	}))
+	defer srv.Close()
+
+	s := testSink(t, &Config{
+		Type:          TypeHTTP,
+		Address:       srv.URL,
+		BufferSize:    10,
+		RetryAttempts: 3,
+		RetryInterval: 10 * time.Millisecond,
+	})
+	w := NewWriter("stdout", []*Sink{s})
+	w.Write([]byte("one\r\ntwo\nthr"))
+	w.Write([]byte("ee\nfour"))
+	w.Flush()
+
+	must.Wait(t, wait.InitialSuccess(
+		wait.BoolFunc(func() bool {
+			lock.Lock()
+			defer lock.Unlock()
+			return len(received) == 4
+		}),
+		wait.Timeout(5*time.Second),
+		wait.Gap(10*time.Millisecond),
+	))
+
+	lock.Lock()
+	defer lock.Unlock()
+	for i, expect := range []string{"one", "two", "three", "four"} {
+		must.Eq(t, expect, received[i].Message)
+		must.Eq(t, "stdout", received[i].Stream)
+		must.Eq(t, testLabels, received[i].Labels)
+	}
+}
+
+func TestSink_Fluent(t *testing.T) {
+	ci.Parallel(t)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	must.NoError(t, err)
+	defer ln.Close()
+
+	s := testSink(t, &Config{
+		Type:       TypeFluent,
+		Address:    "tcp://" + ln.Addr().String(),
+		BufferSize: 10,
+	})
+	NewWriter("stdout", []*Sink{s}).Write([]byte("hello\n"))
+
+	conn, err := ln.Accept()
+	must.NoError(t, err)
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	handle := &codec.MsgpackHandle{}
+	handle.RawToString = true
+
+	var msg []any
+	must.NoError(t, codec.NewDecoder(conn, handle).Decode(&msg))
+	must.Len(t, 2, msg)
+	must.Eq(t, fluentTag, msg[0].(string))
+
+	entries := msg[1].([]any)
+	must.Len(t, 1, entries)
+	entry := entries[0].([]any)
+	event := entry[1].(map[any]any)
+	must.Eq(t, "hello", event["message"].(string))
+	must.Eq(t, "stdout", event["stream"].(string))
+	must.Eq(t, "example", event["job"].(string))
+	must.Eq(t, "server", event["task"].(string))
+}
+
+func TestSink_Close_Unreachable(t *testing.T) {
+	ci.Parallel(t)
+
+	// The listener never accepts, so requests hang until they are cancelled
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	must.NoError(t, err)
+	defer ln.Close()
+
+	s, err := New(testlog.HCLogger(t), &Config{
+		Type:          TypeHTTP,
+		Address:       "http://" + ln.Addr().String(),
+		BufferSize:    1024,
+		RetryAttempts: 3,
+		RetryInterval: 10 * time.Millisecond,
+	}, testLabels)
+	must.NoError(t, err)
+	for i := 0; i < 1024; i++ {
+		s.Write(Record{Message: strconv.Itoa(i)})
+	}
+
+	// Close gives up on the buffered batches once the close timeout expires
+	start := time.Now()
+	s.Close()
+	must.Less(t, closeTimeout+2*time.Second, time.Since(start))
+}
+
+func TestSink_DropPolicy(t *testing.T) {
+	ci.Parallel(t)
+
+	for _, policy := range []string{DropOldest, DropNewest} {
+		t.Run(policy, func(t *testing.T) {
+			// Buffer records without shipping them
+			s := &Sink{
+				config:   &Config{BufferSize: 2, DropPolicy: policy},
+				notifyCh: make(chan struct{}, 1),
+			}
+			for _, msg := range []string{"one", "two", "three"} {
+				s.Write(Record{Message: msg})
+			}
+
+			batch, dropped := s.next()
+			must.Eq(t, 1, dropped)
+			must.Len(t, 2, batch)
+			if policy == DropOldest {
+				must.Eq(t, "two", batch[0].Message)
+				must.Eq(t, "three", batch[1].Message)
+			} else {
+				must.Eq(t, "one", batch[0].Message)
+				must.Eq(t, "two", batch[1].Message)
+			}
+		})
+	}
+}
+
+func TestWriter_LongLine(t *testing.T) {
+	ci.Parallel(t)
+
+	s := &Sink{
+		config:   &Config{BufferSize: 10},
+		notifyCh: make(chan struct{}, 1),
+	}
+	w := NewWriter("stdout", []*Sink{s})
+	w.Write([]byte(strings.Repeat("a", maxLineSize+10)))
+
+	batch, _ := s.next()
+	must.Len(t, 1, batch)
+	must.Eq(t, maxLineSize, len(batch[0].Message))
+
+	w.Flush()
+	batch, _ = s.next()
+	must.Len(t, 1, batch)
+	must.Eq(t, 10, len(batch[0].Message))
+}
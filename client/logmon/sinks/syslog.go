@@ -0,0 +1,167 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package sinks
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// syslogFacilityUser is the facility of the syslog messages.
+	syslogFacilityUser = 1
+
+	// syslogSeverityInfo and syslogSeverityErr are the severities of the
+	// stdout and stderr records.
+	syslogSeverityInfo = 6
+	syslogSeverityErr  = 3
+
+	// syslogSDID is the ID of the structured data element holding the labels
+	// of the task. 32473 is the private enterprise number reserved for
+	// documentation by RFC5612.
+	syslogSDID = "nomad@32473"
+
+	// dialTimeout is the timeout to connect to the destination of a sink.
+	dialTimeout = 5 * time.Second
+
+	// writeTimeout is the timeout to write a batch of records to a
+	// connection.
+	writeTimeout = 10 * time.Second
+)
+
+// syslogShipper sends records as RFC5424 messages. Messages are framed with
+// octet counting over tcp and sent as individual datagrams over udp and unix
+// sockets.
+type syslogShipper struct {
+	network  string
+	address  string
+	hostname string
+	appName  string
+
+	// structuredData is the structured data element holding the labels of
+	// the task, sent with every message.
+	structuredData string
+
+	conn net.Conn
+}
+
+func newSyslogShipper(u *url.URL, labels map[string]string) (*syslogShipper, error) {
+	network, address, err := dialAddress(u, "unixgram")
+	if err != nil {
+		return nil, err
+	}
+
+	hostname, _ := os.Hostname()
+	if hostname == "" {
+		hostname = "-"
+	}
+	appName := labels["task"]
+	if appName == "" {
+		appName = "-"
+	}
+
+	return &syslogShipper{
+		network:        network,
+		address:        address,
+		hostname:       hostname,
+		appName:        appName,
+		structuredData: syslogStructuredData(labels),
+	}, nil
+}
+
+// syslogStructuredData formats the labels as a structured data element, with
+// the parameters sorted by name.
+func syslogStructuredData(labels map[string]string) string {
+	if len(labels) == 0 {
+		return "-"
+	}
+
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	escaper := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`)
+	var b strings.Builder
+	b.WriteString("[" + syslogSDID)
+	for _, name := range names {
+		fmt.Fprintf(&b, ` %s="%s"`, name, escaper.Replace(labels[name]))
+	}
+	b.WriteString("]")
+	return b.String()
+}
+
+// format returns the RFC5424 message of the record.
+func (s *syslogShipper) format(r Record) string {
+	severity := syslogSeverityInfo
+	if r.Stream == "stderr" {
+		severity = syslogSeverityErr
+	}
+	return fmt.Sprintf("<%d>1 %s %s %s - %s %s %s",
+		syslogFacilityUser*8+severity,
+		r.Time.UTC().Format(time.RFC3339Nano),
+		s.hostname, s.appName, r.Stream, s.structuredData, r.Message)
+}
+
+func (s *syslogShipper) Ship(ctx context.Context, records []Record) error {
+	if s.conn == nil {
+		var d net.Dialer
+		dialCtx, cancel := context.WithTimeout(ctx, dialTimeout)
+		conn, err := d.DialContext(dialCtx, s.network, s.address)
+		cancel()
+		if err != nil {
+			return err
+		}
+		s.conn = conn
+	}
+
+	s.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	for _, r := range records {
+		msg := s.format(r)
+		if s.network == "tcp" {
+			msg = fmt.Sprintf("%d %s", len(msg), msg)
+		}
+		if _, err := s.conn.Write([]byte(msg)); err != nil {
+			// Reconnect on the next attempt
+			s.conn.Close()
+			s.conn = nil
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *syslogShipper) Close() error {
+	if s.conn == nil {
+		return nil
+	}
+	return s.conn.Close()
+}
+
+// dialAddress returns the network and address to dial for a sink address of
+// the form unix:///path, udp://host:port or tcp://host:port. Unix sockets are
+// dialed with the given network.
+func dialAddress(u *url.URL, unixNetwork string) (string, string, error) {
+	switch u.Scheme {
+	case "unix":
+		if u.Path == "" {
+			return "", "", fmt.Errorf("missing socket path in address %q", u)
+		}
+		return unixNetwork, u.Path, nil
+	case "udp", "tcp":
+		if u.Host == "" {
+			return "", "", fmt.Errorf("missing host in address %q", u)
+		}
+		return u.Scheme, u.Host, nil
+	default:
+		return "", "", fmt.Errorf("unsupported scheme %q in address %q", u.Scheme, u)
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package sinks
+
+import (
+	"bytes"
+	"time"
+)
+
+// maxLineSize is the maximum size of a record. Longer lines are split into
+// several records.
+const maxLineSize = 64 * 1024
+
+// Writer splits the output of a task into lines and writes them as records to
+// the sinks.
+type Writer struct {
+	stream string
+	sinks  []*Sink
+
+	// partial is the last line written, until it's terminated
+	partial []byte
+}
+
+// NewWriter returns a writer of the given stream, either "stdout" or
+// "stderr", to the sinks.
+func NewWriter(stream string, sinks []*Sink) *Writer {
+	return &Writer{
+		stream: stream,
+		sinks:  sinks,
+	}
+}
+
+// Write never fails so that it can be used with io.MultiWriter without
+// interrupting the other writers.
+func (w *Writer) Write(p []byte) (int, error) {
+	w.partial = append(w.partial, p...)
+	for {
+		i := bytes.IndexByte(w.partial, '\n')
+		if i < 0 {
+			if len(w.partial) >= maxLineSize {
+				w.emit(w.partial[:maxLineSize])
+				w.partial = w.partial[maxLineSize:]
+				continue
+			}
+			break
+		}
+		w.emit(w.partial[:i])
+		w.partial = w.partial[i+1:]
+	}
+
+	// Release the memory of the lines already emitted
+	if len(w.partial) == 0 {
+		w.partial = nil
+	}
+	return len(p), nil
+}
+
+// Flush writes the last line even if it isn't terminated.
+func (w *Writer) Flush() {
+	if len(w.partial) > 0 {
+		w.emit(w.partial)
+		w.partial = nil
+	}
+}
+
+func (w *Writer) emit(line []byte) {
+	r := Record{
+		Time:    time.Now(),
+		Stream:  w.stream,
+		Message: string(bytes.TrimSuffix(line, []byte("\r"))),
+	}
+	for _, s := range w.sinks {
+		s.Write(r)
+	}
+}
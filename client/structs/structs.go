@@ -161,6 +161,16 @@ type FsLogsRequest struct {
 	// Follow follows logs.
 	Follow bool
 
+	// Since and Until only stream the lines timestamped within the time
+	// range, if set. Lines are only timestamped if the task enables it.
+	Since time.Time
+	Until time.Time
+
+	// Grep only streams the lines containing the substring, or matching the
+	// regular expression if Regex is true.
+	Grep  string
+	Regex bool
+
 	structs.QueryOptions
 }
 
@@ -287,11 +297,29 @@ func (cs *CpuStats) Add(other *CpuStats) {
 	cs.Measured = joinStringSet(cs.Measured, other.Measured)
 }
 
+// NetworkStats holds the traffic of the network namespace of an allocation,
+// which is shared by all of its tasks
+type NetworkStats struct {
+	RxBytes uint64
+	TxBytes uint64
+
+	// RxMbits and TxMbits are the throughput since the previous sample
+	RxMbits float64
+	TxMbits float64
+
+	// A list of fields whose values were actually sampled
+	Measured []string
+}
+
 // ResourceUsage holds information related to cpu and memory stats
 type ResourceUsage struct {
 	MemoryStats *MemoryStats
 	CpuStats    *CpuStats
 	DeviceStats []*device.DeviceGroupStats
+
+	// NetworkStats is the usage of the allocation network. It isn't summed
+	// by Add since it's shared by all the tasks of the allocation.
+	NetworkStats *NetworkStats
 }
 
 func (ru *ResourceUsage) Add(other *ResourceUsage) {
@@ -308,6 +336,17 @@ type TaskResourceUsage struct {
 	Pids          map[string]*ResourceUsage
 }
 
+// WithNetworkStats returns a copy of the task resource usage with the
+// network stats of its allocation.
+func (tru *TaskResourceUsage) WithNetworkStats(stats *NetworkStats) *TaskResourceUsage {
+	ru := *tru.ResourceUsage
+	ru.NetworkStats = stats
+
+	out := *tru
+	out.ResourceUsage = &ru
+	return &out
+}
+
 // AllocResourceUsage holds the aggregated task resource usage of the
 // allocation.
 type AllocResourceUsage struct {
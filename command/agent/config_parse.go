@@ -331,10 +331,18 @@ func extraKeys(c *Config) error {
 		helper.RemoveEqualFold(&c.ExtraKeysHCL, "server")
 	}
 
-	for _, k := range []string{"preemption_config"} {
+	for _, k := range []string{"preemption_config", "namespace_fair_share", "descheduler"} {
 		helper.RemoveEqualFold(&c.Server.ExtraKeysHCL, k)
 	}
 
+	// Remove priority class extra keys
+	if c.Server.DefaultSchedulerConfig != nil {
+		for _, class := range c.Server.DefaultSchedulerConfig.PriorityClasses {
+			helper.RemoveEqualFold(&c.Server.ExtraKeysHCL, class.Name)
+			helper.RemoveEqualFold(&c.Server.ExtraKeysHCL, "priority_class")
+		}
+	}
+
 	for _, k := range []string{"datadog_tags"} {
 		helper.RemoveEqualFold(&c.ExtraKeysHCL, k)
 		helper.RemoveEqualFold(&c.ExtraKeysHCL, "telemetry")
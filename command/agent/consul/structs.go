@@ -39,9 +39,7 @@ func BuildAllocServices(
 		DriverExec: nil,
 	}
 
-	if alloc.DeploymentStatus != nil {
-		ws.Canary = alloc.DeploymentStatus.Canary
-	}
+	ws.Canary = alloc.DeploymentStatus.CanaryServices()
 
 	return ws
 }
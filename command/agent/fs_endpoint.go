@@ -12,6 +12,7 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/docker/docker/pkg/ioutils"
 	"github.com/hashicorp/go-msgpack/v2/codec"
@@ -264,6 +265,10 @@ func (s *HTTPServer) Stream(resp http.ResponseWriter, req *http.Request) (interf
 //   - offset: The offset to start streaming data at, defaults to zero.
 //   - origin: Either "start" or "end" and defines from where the offset is
 //     applied. Defaults to "start".
+//   - since/until: Only stream the lines timestamped within the time range,
+//     given as RFC3339 timestamps or durations before now.
+//   - grep: Only stream the lines containing the substring.
+//   - regex: A boolean of whether grep is a regular expression.
 func (s *HTTPServer) Logs(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	var allocID, task, logType string
 	var plain, follow bool
@@ -315,6 +320,26 @@ func (s *HTTPServer) Logs(resp http.ResponseWriter, req *http.Request) (interfac
 		return nil, invalidOrigin
 	}
 
+	now := time.Now()
+	var since, until time.Time
+	if sinceStr := q.Get("since"); sinceStr != "" {
+		if since, err = parseLogsTime(sinceStr, now); err != nil {
+			return nil, CodedError(400, fmt.Sprintf("error parsing since: %v", err))
+		}
+	}
+	if untilStr := q.Get("until"); untilStr != "" {
+		if until, err = parseLogsTime(untilStr, now); err != nil {
+			return nil, CodedError(400, fmt.Sprintf("error parsing until: %v", err))
+		}
+	}
+
+	var regex bool
+	if regexStr := q.Get("regex"); regexStr != "" {
+		if regex, err = strconv.ParseBool(regexStr); err != nil {
+			return nil, CodedError(400, fmt.Sprintf("failed to parse regex field to boolean: %v", err))
+		}
+	}
+
 	// Create the request arguments
 	fsReq := &cstructs.FsLogsRequest{
 		AllocID:   allocID,
@@ -324,6 +349,10 @@ func (s *HTTPServer) Logs(resp http.ResponseWriter, req *http.Request) (interfac
 		Origin:    origin,
 		PlainText: plain,
 		Follow:    follow,
+		Since:     since,
+		Until:     until,
+		Grep:      q.Get("grep"),
+		Regex:     regex,
 	}
 	s.parse(resp, req, &fsReq.QueryOptions.Region, &fsReq.QueryOptions)
 
@@ -339,6 +368,15 @@ func (s *HTTPServer) Logs(resp http.ResponseWriter, req *http.Request) (interfac
 	return s.fsStreamImpl(resp, req, "FileSystem.Logs", fsReq, fsReq.AllocID)
 }
 
+// parseLogsTime parses the bound of a logs time range, either an RFC3339
+// timestamp or a duration before now.
+func parseLogsTime(s string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+	return time.Parse(time.RFC3339Nano, s)
+}
+
 // fsStreamImpl is used to make a streaming filesystem call that serializes the
 // args and then expects a stream of StreamErrWrapper results where the payload
 // is copied to the response body.
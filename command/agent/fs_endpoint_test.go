@@ -627,3 +627,20 @@ func TestHTTP_FS_Logs_PropagatesErrors(t *testing.T) {
 		require.Truef(t, ok, "expected a coded error but found: %#+v", err)
 	})
 }
+
+func TestHTTP_FS_parseLogsTime(t *testing.T) {
+	ci.Parallel(t)
+
+	now := time.Date(2026, 10, 16, 15, 0, 0, 0, time.UTC)
+
+	ts, err := parseLogsTime("10m", now)
+	require.NoError(t, err)
+	require.Equal(t, now.Add(-10*time.Minute), ts)
+
+	ts, err = parseLogsTime("2026-10-16T14:00:00Z", now)
+	require.NoError(t, err)
+	require.Equal(t, now.Add(-time.Hour), ts.UTC())
+
+	_, err = parseLogsTime("yesterday", now)
+	require.Error(t, err)
+}
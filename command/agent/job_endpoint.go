@@ -87,6 +87,9 @@ func (s *HTTPServer) JobSpecificRequest(resp http.ResponseWriter, req *http.Requ
 	case strings.HasSuffix(path, "/summary"):
 		jobID := strings.TrimSuffix(path, "/summary")
 		return s.jobSummaryRequest(resp, req, jobID)
+	case strings.HasSuffix(path, "/dependencies"):
+		jobID := strings.TrimSuffix(path, "/dependencies")
+		return s.jobDependenciesRequest(resp, req, jobID)
 	case strings.HasSuffix(path, "/dispatch"):
 		jobID := strings.TrimSuffix(path, "/dispatch")
 		return s.jobDispatchRequest(resp, req, jobID)
@@ -195,6 +198,7 @@ func (s *HTTPServer) jobPlan(resp http.ResponseWriter, req *http.Request,
 		Job:            sJob,
 		Diff:           args.Diff,
 		PolicyOverride: args.PolicyOverride,
+		Explain:        args.Explain,
 		WriteRequest:   *writeReq,
 	}
 
@@ -884,6 +888,26 @@ func (s *HTTPServer) jobSummaryRequest(resp http.ResponseWriter, req *http.Reque
 	return out.JobSummary, nil
 }
 
+func (s *HTTPServer) jobDependenciesRequest(resp http.ResponseWriter, req *http.Request, jobID string) (interface{}, error) {
+	if req.Method != http.MethodGet {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+	args := structs.JobDependenciesRequest{
+		JobID: jobID,
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.JobDependenciesResponse
+	if err := s.agent.RPC("Job.Dependencies", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	return out.JobDependencies, nil
+}
+
 func (s *HTTPServer) jobDispatchRequest(resp http.ResponseWriter, req *http.Request, jobID string) (interface{}, error) {
 	if req.Method != http.MethodPut && req.Method != http.MethodPost {
 		return nil, CodedError(405, ErrInvalidMethod)
@@ -1142,6 +1166,7 @@ func ApiJobToStructJob(job *api.Job) *structs.Job {
 		Name:           *job.Name,
 		Type:           *job.Type,
 		Priority:       *job.Priority,
+		PriorityClass:  *job.PriorityClass,
 		AllAtOnce:      *job.AllAtOnce,
 		Datacenters:    job.Datacenters,
 		NodePool:       *job.NodePool,
@@ -1175,6 +1200,20 @@ func ApiJobToStructJob(job *api.Job) *structs.Job {
 		}
 	}
 
+	if len(job.TopologySpreads) > 0 {
+		j.TopologySpreads = []*structs.TopologySpread{}
+		for _, spread := range job.TopologySpreads {
+			j.TopologySpreads = append(j.TopologySpreads, ApiTopologySpreadToStructs(spread))
+		}
+	}
+
+	if len(job.JobAffinities) > 0 {
+		j.JobAffinities = []*structs.JobAffinity{}
+		for _, affinity := range job.JobAffinities {
+			j.JobAffinities = append(j.JobAffinities, ApiJobAffinityToStructs(affinity))
+		}
+	}
+
 	if job.Periodic != nil {
 		j.Periodic = &structs.PeriodicConfig{
 			Enabled:         *job.Periodic.Enabled,
@@ -1190,13 +1229,56 @@ func ApiJobToStructJob(job *api.Job) *structs.Job {
 		if job.Periodic.Specs != nil {
 			j.Periodic.Specs = job.Periodic.Specs
 		}
+
+		if job.Periodic.Concurrency != nil {
+			j.Periodic.Concurrency = *job.Periodic.Concurrency
+		}
+
+		if job.Periodic.Catchup != nil {
+			j.Periodic.Catchup = *job.Periodic.Catchup
+		}
+
+		if job.Periodic.CatchupMaxCount != nil {
+			j.Periodic.CatchupMaxCount = *job.Periodic.CatchupMaxCount
+		}
+
+		if job.Periodic.CatchupMaxAge != nil {
+			j.Periodic.CatchupMaxAge = *job.Periodic.CatchupMaxAge
+		}
 	}
 
 	if job.ParameterizedJob != nil {
 		j.ParameterizedJob = &structs.ParameterizedJobConfig{
-			Payload:      job.ParameterizedJob.Payload,
-			MetaRequired: job.ParameterizedJob.MetaRequired,
-			MetaOptional: job.ParameterizedJob.MetaOptional,
+			Payload:       job.ParameterizedJob.Payload,
+			MetaRequired:  job.ParameterizedJob.MetaRequired,
+			MetaOptional:  job.ParameterizedJob.MetaOptional,
+			Concurrency:   job.ParameterizedJob.Concurrency,
+			MaxConcurrent: job.ParameterizedJob.MaxConcurrent,
+		}
+
+		if queue := job.ParameterizedJob.DispatchQueue; queue != nil {
+			j.ParameterizedJob.DispatchQueue = &structs.DispatchQueueConfig{
+				MaxRunning: queue.MaxRunning,
+				Rate:       queue.Rate,
+				Burst:      queue.Burst,
+			}
+		}
+	}
+
+	if job.Deadline != nil {
+		j.Deadline = *job.Deadline
+	}
+
+	if len(job.DependsOn) > 0 {
+		j.DependsOn = make([]*structs.JobDependency, len(job.DependsOn))
+		for i, dep := range job.DependsOn {
+			j.DependsOn[i] = &structs.JobDependency{}
+			if dep.JobID != nil {
+				j.DependsOn[i].JobID = *dep.JobID
+			}
+			if dep.Condition != nil {
+				j.DependsOn[i].Condition = *dep.Condition
+			}
 		}
 	}
 
@@ -1246,12 +1328,33 @@ func ApiTgToStructsTG(job *structs.Job, taskGroup *api.TaskGroup, tg *structs.Ta
 		Delay:           *taskGroup.RestartPolicy.Delay,
 		Mode:            *taskGroup.RestartPolicy.Mode,
 		RenderTemplates: *taskGroup.RestartPolicy.RenderTemplates,
+		DelayFunction:   *taskGroup.RestartPolicy.DelayFunction,
+		MaxDelay:        *taskGroup.RestartPolicy.MaxDelay,
+		Jitter:          pointer.Copy(taskGroup.RestartPolicy.Jitter),
+		ExitRules:       apiExitRulesToStructs(taskGroup.RestartPolicy.ExitRules),
 	}
 
 	if taskGroup.ShutdownDelay != nil {
 		tg.ShutdownDelay = taskGroup.ShutdownDelay
 	}
 
+	if taskGroup.MaxRunDuration != nil {
+		tg.MaxRunDuration = taskGroup.MaxRunDuration
+	}
+
+	if taskGroup.Gang != nil {
+		tg.Gang = *taskGroup.Gang
+	}
+
+	if taskGroup.Indexed != nil {
+		tg.Indexed = &structs.IndexedPolicy{
+			Parallelism: *taskGroup.Indexed.Parallelism,
+			MaxFailures: *taskGroup.Indexed.MaxFailures,
+		}
+	}
+
+	tg.NetworkPolicy = apiNetworkPolicyToStructs(taskGroup.NetworkPolicy)
+
 	if taskGroup.ReschedulePolicy != nil {
 		tg.ReschedulePolicy = &structs.ReschedulePolicy{
 			Attempts:      *taskGroup.ReschedulePolicy.Attempts,
@@ -1260,6 +1363,7 @@ func ApiTgToStructsTG(job *structs.Job, taskGroup *api.TaskGroup, tg *structs.Ta
 			DelayFunction: *taskGroup.ReschedulePolicy.DelayFunction,
 			MaxDelay:      *taskGroup.ReschedulePolicy.MaxDelay,
 			Unlimited:     *taskGroup.ReschedulePolicy.Unlimited,
+			ExitRules:     apiExitRulesToStructs(taskGroup.ReschedulePolicy.ExitRules),
 		}
 	}
 
@@ -1280,10 +1384,11 @@ func ApiTgToStructsTG(job *structs.Job, taskGroup *api.TaskGroup, tg *structs.Ta
 
 	if taskGroup.Migrate != nil {
 		tg.Migrate = &structs.MigrateStrategy{
-			MaxParallel:     *taskGroup.Migrate.MaxParallel,
-			HealthCheck:     *taskGroup.Migrate.HealthCheck,
-			MinHealthyTime:  *taskGroup.Migrate.MinHealthyTime,
-			HealthyDeadline: *taskGroup.Migrate.HealthyDeadline,
+			MaxParallel:         *taskGroup.Migrate.MaxParallel,
+			HealthCheck:         *taskGroup.Migrate.HealthCheck,
+			MinHealthyTime:      *taskGroup.Migrate.MinHealthyTime,
+			HealthyDeadline:     *taskGroup.Migrate.HealthyDeadline,
+			DisableDescheduling: *taskGroup.Migrate.DisableDescheduling,
 		}
 	}
 
@@ -1305,6 +1410,20 @@ func ApiTgToStructsTG(job *structs.Job, taskGroup *api.TaskGroup, tg *structs.Ta
 		}
 	}
 
+	if len(taskGroup.TopologySpreads) > 0 {
+		tg.TopologySpreads = []*structs.TopologySpread{}
+		for _, spread := range taskGroup.TopologySpreads {
+			tg.TopologySpreads = append(tg.TopologySpreads, ApiTopologySpreadToStructs(spread))
+		}
+	}
+
+	if len(taskGroup.JobAffinities) > 0 {
+		tg.JobAffinities = []*structs.JobAffinity{}
+		for _, affinity := range taskGroup.JobAffinities {
+			tg.JobAffinities = append(tg.JobAffinities, ApiJobAffinityToStructs(affinity))
+		}
+	}
+
 	if len(taskGroup.Volumes) > 0 {
 		tg.Volumes = map[string]*structs.VolumeRequest{}
 		for k, v := range taskGroup.Volumes {
@@ -1355,6 +1474,27 @@ func ApiTgToStructsTG(job *structs.Job, taskGroup *api.TaskGroup, tg *structs.Ta
 		if taskGroup.Update.AutoPromote != nil {
 			tg.Update.AutoPromote = *taskGroup.Update.AutoPromote
 		}
+
+		if analysis := taskGroup.Update.Analysis; analysis != nil {
+			tg.Update.Analysis = &structs.CanaryAnalysis{
+				Window:              *analysis.Window,
+				Interval:            *analysis.Interval,
+				MaxCheckFailureRate: *analysis.MaxCheckFailureRate,
+				MaxRestarts:         *analysis.MaxRestarts,
+				MaxOOMKills:         *analysis.MaxOOMKills,
+				MaxCPUIncrease:      *analysis.MaxCPUIncrease,
+				MaxMemoryIncrease:   *analysis.MaxMemoryIncrease,
+			}
+		}
+
+		if blueGreen := taskGroup.Update.BlueGreen; blueGreen != nil {
+			tg.Update.BlueGreen = &structs.BlueGreenStrategy{
+				Retention: *blueGreen.Retention,
+			}
+		}
+
+		tg.Update.PreDeploy = apiDeploymentHookToStructs(taskGroup.Update.PreDeploy)
+		tg.Update.PostDeploy = apiDeploymentHookToStructs(taskGroup.Update.PostDeploy)
 	}
 
 	if len(taskGroup.Tasks) > 0 {
@@ -1373,6 +1513,24 @@ func ApiTgToStructsTG(job *structs.Job, taskGroup *api.TaskGroup, tg *structs.Ta
 	}
 }
 
+// apiDeploymentHookToStructs converts the API deployment hook of an update
+// strategy to its structs representation.
+func apiDeploymentHookToStructs(in *api.DeploymentHook) *structs.DeploymentHook {
+	if in == nil {
+		return nil
+	}
+	out := &structs.DeploymentHook{
+		Meta: maps.Clone(in.Meta),
+	}
+	if in.Job != nil {
+		out.Job = *in.Job
+	}
+	if in.Group != nil {
+		out.Group = *in.Group
+	}
+	return out
+}
+
 // ApiTaskToStructsTask is a copy and type conversion between the API
 // representation of a task from a struct representation of a task.
 func ApiTaskToStructsTask(job *structs.Job, group *structs.TaskGroup,
@@ -1387,6 +1545,7 @@ func ApiTaskToStructsTask(job *structs.Job, group *structs.TaskGroup,
 	structsTask.Meta = apiTask.Meta
 	structsTask.KillTimeout = *apiTask.KillTimeout
 	structsTask.ShutdownDelay = apiTask.ShutdownDelay
+	structsTask.MaxRunDuration = apiTask.MaxRunDuration
 	structsTask.KillSignal = apiTask.KillSignal
 	structsTask.Kind = structs.TaskKind(apiTask.Kind)
 	structsTask.Constraints = ApiConstraintsToStructs(apiTask.Constraints)
@@ -1417,6 +1576,10 @@ func ApiTaskToStructsTask(job *structs.Job, group *structs.TaskGroup,
 			Delay:           *apiTask.RestartPolicy.Delay,
 			Mode:            *apiTask.RestartPolicy.Mode,
 			RenderTemplates: *apiTask.RestartPolicy.RenderTemplates,
+			DelayFunction:   *apiTask.RestartPolicy.DelayFunction,
+			MaxDelay:        *apiTask.RestartPolicy.MaxDelay,
+			Jitter:          pointer.Copy(apiTask.RestartPolicy.Jitter),
+			ExitRules:       apiExitRulesToStructs(apiTask.RestartPolicy.ExitRules),
 		}
 	}
 
@@ -1508,6 +1671,16 @@ func ApiTaskToStructsTask(job *structs.Job, group *structs.TaskGroup,
 		}
 	}
 
+	if len(apiTask.DependsOn) > 0 {
+		structsTask.DependsOn = make([]*structs.TaskDependency, len(apiTask.DependsOn))
+		for i, dep := range apiTask.DependsOn {
+			structsTask.DependsOn[i] = &structs.TaskDependency{
+				Task:      dep.Task,
+				Condition: dep.Condition,
+			}
+		}
+	}
+
 	for _, action := range apiTask.Actions {
 		act := ApiActionToStructsAction(job, action)
 		structsTask.Actions = append(structsTask.Actions, act)
@@ -2157,6 +2330,41 @@ func apiVolumeMountsToStructs(in []*api.VolumeMount) []*structs.VolumeMount {
 	return out
 }
 
+func apiExitRulesToStructs(in []*api.ExitRule) []*structs.ExitRule {
+	if len(in) == 0 {
+		return nil
+	}
+	out := make([]*structs.ExitRule, len(in))
+	for i, rule := range in {
+		out[i] = &structs.ExitRule{
+			ExitCodes: slices.Clone(rule.ExitCodes),
+			Signals:   slices.Clone(rule.Signals),
+			Action:    rule.Action,
+		}
+	}
+	return out
+}
+
+func apiNetworkPolicyToStructs(in *api.NetworkPolicy) *structs.NetworkPolicy {
+	if in == nil {
+		return nil
+	}
+	out := &structs.NetworkPolicy{
+		Default: in.Default,
+	}
+	if len(in.Allow) > 0 {
+		out.Allow = make([]*structs.NetworkPolicyRule, len(in.Allow))
+		for i, rule := range in.Allow {
+			out.Allow[i] = &structs.NetworkPolicyRule{
+				Namespace: rule.Namespace,
+				Job:       rule.Job,
+				Service:   rule.Service,
+			}
+		}
+	}
+	return out
+}
+
 func apiConsulToStructs(in *api.Consul) *structs.Consul {
 	if in == nil {
 		return nil
@@ -2173,11 +2381,33 @@ func apiLogConfigToStructs(in *api.LogConfig) *structs.LogConfig {
 		return nil
 	}
 
-	return &structs.LogConfig{
-		Disabled:      dereferenceBool(in.Disabled),
-		MaxFiles:      dereferenceInt(in.MaxFiles),
-		MaxFileSizeMB: dereferenceInt(in.MaxFileSizeMB),
+	out := &structs.LogConfig{
+		Disabled:       dereferenceBool(in.Disabled),
+		MaxFiles:       dereferenceInt(in.MaxFiles),
+		MaxFileSizeMB:  dereferenceInt(in.MaxFileSizeMB),
+		Compress:       in.Compress,
+		MaxTotalSizeMB: dereferenceInt(in.MaxTotalSizeMB),
+		Timestamps:     dereferenceBool(in.Timestamps),
 	}
+	if in.MaxAge != nil {
+		out.MaxAge = *in.MaxAge
+	}
+
+	for _, sink := range in.Sinks {
+		outSink := &structs.LogSink{
+			Type:          sink.Type,
+			Address:       sink.Address,
+			BufferSize:    dereferenceInt(sink.BufferSize),
+			RetryAttempts: dereferenceInt(sink.RetryAttempts),
+			DropPolicy:    sink.DropPolicy,
+		}
+		if sink.RetryInterval != nil {
+			outSink.RetryInterval = *sink.RetryInterval
+		}
+		out.Sinks = append(out.Sinks, outSink)
+	}
+
+	return out
 }
 
 func dereferenceBool(in *bool) bool {
@@ -2293,6 +2523,24 @@ func ApiSpreadToStructs(a1 *api.Spread) *structs.Spread {
 	return ret
 }
 
+func ApiTopologySpreadToStructs(a1 *api.TopologySpread) *structs.TopologySpread {
+	return &structs.TopologySpread{
+		Attribute: a1.Attribute,
+		MaxSkew:   *a1.MaxSkew,
+	}
+}
+
+func ApiJobAffinityToStructs(a1 *api.JobAffinity) *structs.JobAffinity {
+	return &structs.JobAffinity{
+		JobID:    a1.JobID,
+		Group:    a1.Group,
+		Meta:     maps.Clone(a1.Meta),
+		Anti:     *a1.Anti,
+		Required: *a1.Required,
+		Weight:   *a1.Weight,
+	}
+}
+
 // validateEvalPriorityOpt ensures the supplied evaluation priority override
 // value is within acceptable bounds.
 func validateEvalPriorityOpt(priority int) HTTPCodedError {
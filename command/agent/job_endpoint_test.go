@@ -3125,6 +3125,8 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 					Delay:           10 * time.Second,
 					Mode:            "delay",
 					RenderTemplates: false,
+					DelayFunction:   "constant",
+					Jitter:          pointer.Of(0.25),
 				},
 				Spreads: []*structs.Spread{
 					{
@@ -3296,6 +3298,8 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 							Delay:           20 * time.Second,
 							Mode:            "delay",
 							RenderTemplates: false,
+							DelayFunction:   "constant",
+							Jitter:          pointer.Of(0.25),
 						},
 						Services: []*structs.Service{
 							{
@@ -3634,6 +3638,8 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 					Delay:           10 * time.Second,
 					Mode:            "delay",
 					RenderTemplates: false,
+					DelayFunction:   "constant",
+					Jitter:          pointer.Of(0.25),
 				},
 				EphemeralDisk: &structs.EphemeralDisk{
 					SizeMB:  100,
@@ -3694,6 +3700,8 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 							Delay:           10 * time.Second,
 							Mode:            "delay",
 							RenderTemplates: false,
+							DelayFunction:   "constant",
+							Jitter:          pointer.Of(0.25),
 						},
 						Meta: map[string]string{
 							"lol": "code",
@@ -3882,6 +3890,37 @@ func TestConversion_apiLogConfigToStructs(t *testing.T) {
 		MaxFiles:      pointer.Of(2),
 		MaxFileSizeMB: pointer.Of(8),
 	}))
+	must.Eq(t, &structs.LogConfig{
+		MaxFiles:       2,
+		MaxFileSizeMB:  8,
+		Compress:       structs.LogCompressGzip,
+		MaxAge:         time.Hour,
+		MaxTotalSizeMB: 12,
+		Timestamps:     true,
+		Sinks: []*structs.LogSink{{
+			Type:          structs.LogSinkTypeFluent,
+			Address:       "tcp://127.0.0.1:24224",
+			BufferSize:    512,
+			RetryAttempts: 5,
+			RetryInterval: 2 * time.Second,
+			DropPolicy:    structs.LogSinkDropNewest,
+		}},
+	}, apiLogConfigToStructs(&api.LogConfig{
+		MaxFiles:       pointer.Of(2),
+		MaxFileSizeMB:  pointer.Of(8),
+		Compress:       "gzip",
+		MaxAge:         pointer.Of(time.Hour),
+		MaxTotalSizeMB: pointer.Of(12),
+		Timestamps:     pointer.Of(true),
+		Sinks: []*api.LogSink{{
+			Type:          "fluent",
+			Address:       "tcp://127.0.0.1:24224",
+			BufferSize:    pointer.Of(512),
+			RetryAttempts: pointer.Of(5),
+			RetryInterval: pointer.Of(2 * time.Second),
+			DropPolicy:    "newest",
+		}},
+	}))
 
 	// COMPAT(1.6.0): verify backwards compatibility fixes
 	// Note: we're intentionally ignoring the Enabled: false case
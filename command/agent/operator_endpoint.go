@@ -322,6 +322,17 @@ func (s *HTTPServer) schedulerUpdateConfig(resp http.ResponseWriter, req *http.R
 		MemoryOversubscriptionEnabled: conf.MemoryOversubscriptionEnabled,
 		RejectJobRegistration:         conf.RejectJobRegistration,
 		PauseEvalBroker:               conf.PauseEvalBroker,
+		NamespaceFairShare: structs.NamespaceFairShareConfig{
+			Enabled:       conf.NamespaceFairShare.Enabled,
+			DefaultWeight: conf.NamespaceFairShare.DefaultWeight,
+		},
+		Descheduler: structs.DeschedulerConfig{
+			Enabled:                conf.Descheduler.Enabled,
+			DryRun:                 conf.Descheduler.DryRun,
+			MaxMigrations:          conf.Descheduler.MaxMigrations,
+			UtilizationThreshold:   conf.Descheduler.UtilizationThreshold,
+			FragmentationThreshold: conf.Descheduler.FragmentationThreshold,
+		},
 		PreemptionConfig: structs.PreemptionConfig{
 			SystemSchedulerEnabled:   conf.PreemptionConfig.SystemSchedulerEnabled,
 			SysBatchSchedulerEnabled: conf.PreemptionConfig.SysBatchSchedulerEnabled,
@@ -330,6 +341,20 @@ func (s *HTTPServer) schedulerUpdateConfig(resp http.ResponseWriter, req *http.R
 		},
 	}
 
+	for _, class := range conf.PriorityClasses {
+		if class == nil {
+			continue
+		}
+		args.Config.PriorityClasses = append(args.Config.PriorityClasses, &structs.PriorityClass{
+			Name:             class.Name,
+			Description:      class.Description,
+			Priority:         class.Priority,
+			PreemptionPolicy: class.PreemptionPolicy,
+			NonPreemptible:   class.NonPreemptible,
+			Namespaces:       class.Namespaces,
+		})
+	}
+
 	if err := args.Config.Validate(); err != nil {
 		return nil, CodedError(http.StatusBadRequest, err.Error())
 	}
@@ -9,6 +9,7 @@ import (
 	"io"
 	"os"
 	"os/signal"
+	"regexp"
 	"strings"
 	"syscall"
 	"time"
@@ -27,6 +28,11 @@ type AllocLogsCommand struct {
 	numLines                                   int64
 	numBytes                                   int64
 	task                                       string
+	since, until, grep                         string
+	regex                                      bool
+
+	// filter is the filter of the log lines set by the flags, if any
+	filter *api.LogsFilter
 }
 
 func (l *AllocLogsCommand) Help() string {
@@ -80,6 +86,23 @@ Logs Specific Options:
   -c
     Sets the tail location in number of bytes relative to the end of the logs.
 
+  -since <time>
+    Only display the log lines written after the given time, either a duration
+    relative to now such as "10m" or an RFC3339 timestamp. Lines are only
+    filtered by time if the task's logs are timestamped with the "timestamps"
+    option of the "logs" block.
+
+  -until <time>
+    Only display the log lines written before the given time, either a
+    duration relative to now or an RFC3339 timestamp.
+
+  -grep <pattern>
+    Only display the log lines containing the given substring. The lines are
+    filtered by the client running the allocation.
+
+  -regex
+    Interpret the -grep pattern as a regular expression.
+
   Note that the -no-color option applies to Nomad's own output. If the task's
   logs include terminal escape sequences for color codes, Nomad will not
   remove them.
@@ -104,6 +127,10 @@ func (l *AllocLogsCommand) AutocompleteFlags() complete.Flags {
 			"-tail":    complete.PredictAnything,
 			"-n":       complete.PredictAnything,
 			"-c":       complete.PredictAnything,
+			"-since":   complete.PredictAnything,
+			"-until":   complete.PredictAnything,
+			"-grep":    complete.PredictAnything,
+			"-regex":   complete.PredictNothing,
 		})
 }
 
@@ -137,6 +164,10 @@ func (l *AllocLogsCommand) Run(args []string) int {
 	flags.Int64Var(&l.numLines, "n", -1, "")
 	flags.Int64Var(&l.numBytes, "c", -1, "")
 	flags.StringVar(&l.task, "task", "", "")
+	flags.StringVar(&l.since, "since", "", "")
+	flags.StringVar(&l.until, "until", "", "")
+	flags.StringVar(&l.grep, "grep", "", "")
+	flags.BoolVar(&l.regex, "regex", false, "")
 
 	if err := flags.Parse(args); err != nil {
 		return 1
@@ -158,6 +189,14 @@ func (l *AllocLogsCommand) Run(args []string) int {
 		return 1
 	}
 
+	filter, err := l.logsFilter(time.Now())
+	if err != nil {
+		l.Ui.Error(err.Error())
+		l.Ui.Error(commandErrorText(l))
+		return 1
+	}
+	l.filter = filter
+
 	client, err := l.Meta.Client()
 	if err != nil {
 		l.Ui.Error(fmt.Sprintf("Error initializing client: %v", err))
@@ -314,7 +353,8 @@ func (l *AllocLogsCommand) followFile(client *api.Client, alloc *api.Allocation,
 	logType, origin string, offset int64) (io.ReadCloser, error) {
 
 	cancel := make(chan struct{})
-	frames, errCh := client.AllocFS().Logs(alloc, l.follow, l.task, logType, origin, offset, cancel, nil)
+	frames, errCh := client.AllocFS().FilteredLogs(
+		alloc, l.follow, l.task, logType, origin, offset, l.filter, cancel, nil)
 
 	// Setting up the logs stream can fail, therefore we need to check the
 	// error channel before continuing further.
@@ -357,8 +397,8 @@ func (l *AllocLogsCommand) tailMultipleFiles(client *api.Client, alloc *api.Allo
 	// exit.
 	defer close(cancel)
 
-	stdoutFrames, stdoutErrCh := client.AllocFS().Logs(
-		alloc, true, l.task, api.FSLogNameStdout, api.OriginEnd, 0, cancel, nil)
+	stdoutFrames, stdoutErrCh := client.AllocFS().FilteredLogs(
+		alloc, true, l.task, api.FSLogNameStdout, api.OriginEnd, 0, l.filter, cancel, nil)
 
 	// Setting up the logs stream can fail, therefore we need to check the
 	// error channel before continuing further.
@@ -368,8 +408,8 @@ func (l *AllocLogsCommand) tailMultipleFiles(client *api.Client, alloc *api.Allo
 	default:
 	}
 
-	stderrFrames, stderrErrCh := client.AllocFS().Logs(
-		alloc, true, l.task, api.FSLogNameStderr, api.OriginEnd, 0, cancel, nil)
+	stderrFrames, stderrErrCh := client.AllocFS().FilteredLogs(
+		alloc, true, l.task, api.FSLogNameStderr, api.OriginEnd, 0, l.filter, cancel, nil)
 
 	// Setting up the logs stream can fail, therefore we need to check the
 	// error channel before continuing further.
@@ -416,6 +456,57 @@ func (l *AllocLogsCommand) tailMultipleFiles(client *api.Client, alloc *api.Allo
 	}
 }
 
+// logsFilter returns the filter of the log lines set by the flags, or nil if
+// the log lines aren't filtered.
+func (l *AllocLogsCommand) logsFilter(now time.Time) (*api.LogsFilter, error) {
+	if l.regex && l.grep == "" {
+		return nil, errors.New("-regex requires -grep")
+	}
+	if l.since == "" && l.until == "" && l.grep == "" {
+		return nil, nil
+	}
+
+	filter := &api.LogsFilter{
+		Grep:  l.grep,
+		Regex: l.regex,
+	}
+
+	var err error
+	if l.since != "" {
+		if filter.Since, err = parseLogsTime(l.since, now); err != nil {
+			return nil, fmt.Errorf("Invalid -since value %q: %v", l.since, err)
+		}
+	}
+	if l.until != "" {
+		if filter.Until, err = parseLogsTime(l.until, now); err != nil {
+			return nil, fmt.Errorf("Invalid -until value %q: %v", l.until, err)
+		}
+	}
+	if !filter.Since.IsZero() && !filter.Until.IsZero() && filter.Until.Before(filter.Since) {
+		return nil, errors.New("-until must not be before -since")
+	}
+
+	if l.regex {
+		if _, err := regexp.Compile(l.grep); err != nil {
+			return nil, fmt.Errorf("Invalid -grep regex %q: %v", l.grep, err)
+		}
+	}
+	return filter, nil
+}
+
+// parseLogsTime parses a time given as a duration before now or as an RFC3339
+// timestamp.
+func parseLogsTime(s string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+	ts, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, errors.New("must be a duration or an RFC3339 timestamp")
+	}
+	return ts, nil
+}
+
 func lookupAllocTask(alloc *api.Allocation) (string, error) {
 	tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup)
 	if tg == nil {
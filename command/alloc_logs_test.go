@@ -5,8 +5,10 @@ package command
 
 import (
 	"testing"
+	"time"
 
 	"github.com/hashicorp/cli"
+	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/ci"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -90,3 +92,37 @@ func TestLogsCommand_AutocompleteArgs(t *testing.T) {
 	must.Len(t, 1, res)
 	must.Eq(t, a.ID, res[0])
 }
+
+func TestLogsCommand_Filter(t *testing.T) {
+	ci.Parallel(t)
+
+	now := time.Date(2026, 10, 16, 15, 0, 0, 0, time.UTC)
+
+	cmd := &AllocLogsCommand{}
+	filter, err := cmd.logsFilter(now)
+	must.NoError(t, err)
+	must.Nil(t, filter)
+
+	cmd = &AllocLogsCommand{since: "10m", until: "2026-10-16T14:55:00Z", grep: "ERROR"}
+	filter, err = cmd.logsFilter(now)
+	must.NoError(t, err)
+	must.Eq(t, &api.LogsFilter{
+		Since: now.Add(-10 * time.Minute),
+		Until: now.Add(-5 * time.Minute),
+		Grep:  "ERROR",
+	}, filter)
+
+	for _, tc := range []struct {
+		cmd *AllocLogsCommand
+		err string
+	}{
+		{cmd: &AllocLogsCommand{since: "yesterday"}, err: "Invalid -since value"},
+		{cmd: &AllocLogsCommand{until: "10"}, err: "Invalid -until value"},
+		{cmd: &AllocLogsCommand{since: "5m", until: "10m"}, err: "-until must not be before -since"},
+		{cmd: &AllocLogsCommand{regex: true}, err: "-regex requires -grep"},
+		{cmd: &AllocLogsCommand{grep: "(", regex: true}, err: "Invalid -grep regex"},
+	} {
+		_, err := tc.cmd.logsFilter(now)
+		must.ErrorContains(t, err, tc.err)
+	}
+}
@@ -681,6 +681,7 @@ func (c *AllocStatusCommand) outputVerboseResourceUsage(task string, resourceUsa
 	memoryStats := resourceUsage.MemoryStats
 	cpuStats := resourceUsage.CpuStats
 	deviceStats := resourceUsage.DeviceStats
+	networkStats := resourceUsage.NetworkStats
 
 	if memoryStats != nil && len(memoryStats.Measured) > 0 {
 		c.Ui.Output("Memory Stats")
@@ -746,6 +747,30 @@ func (c *AllocStatusCommand) outputVerboseResourceUsage(task string, resourceUsa
 		c.Ui.Output(formatList(out))
 	}
 
+	if networkStats != nil && len(networkStats.Measured) > 0 {
+		c.Ui.Output("")
+		c.Ui.Output("Network Stats")
+
+		var measuredStats []string
+		for _, measured := range networkStats.Measured {
+			switch measured {
+			case "Rx Bytes":
+				measuredStats = append(measuredStats, humanize.IBytes(networkStats.RxBytes))
+			case "Tx Bytes":
+				measuredStats = append(measuredStats, humanize.IBytes(networkStats.TxBytes))
+			case "Rx Mbits":
+				measuredStats = append(measuredStats, strconv.FormatFloat(networkStats.RxMbits, 'f', 2, 64))
+			case "Tx Mbits":
+				measuredStats = append(measuredStats, strconv.FormatFloat(networkStats.TxMbits, 'f', 2, 64))
+			}
+		}
+
+		out := make([]string, 2)
+		out[0] = strings.Join(networkStats.Measured, "|")
+		out[1] = strings.Join(measuredStats, "|")
+		c.Ui.Output(formatList(out))
+	}
+
 	if len(deviceStats) > 0 {
 		c.Ui.Output("")
 		c.Ui.Output("Device Stats")
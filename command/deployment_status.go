@@ -13,6 +13,7 @@ import (
 	"strings"
 	"time"
 
+	humanize "github.com/dustin/go-humanize"
 	"github.com/gosuri/uilive"
 	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/api/contexts"
@@ -557,6 +558,16 @@ func formatDeployment(c *api.Client, d *api.Deployment, uuidLength int) string {
 	}
 	base += "\n\n[bold]Deployed[reset]\n"
 	base += formatDeploymentGroups(d, uuidLength)
+
+	if analysis := formatCanaryAnalysis(d); analysis != "" {
+		base += "\n\n[bold]Canary Analysis[reset]\n"
+		base += analysis
+	}
+
+	if hooks := formatDeploymentHooks(d); hooks != "" {
+		base += "\n\n[bold]Deployment Hooks[reset]\n"
+		base += hooks
+	}
 	return base
 }
 
@@ -629,7 +640,7 @@ func formatMultiregionDeployment(regions map[string]*api.Deployment, uuidLength
 
 func formatDeploymentGroups(d *api.Deployment, uuidLength int) string {
 	// Detect if we need to add these columns
-	var canaries, autorevert, progressDeadline bool
+	var canaries, autorevert, progressDeadline, blueGreen bool
 	tgNames := make([]string, 0, len(d.TaskGroups))
 	for name, state := range d.TaskGroups {
 		tgNames = append(tgNames, name)
@@ -642,6 +653,9 @@ func formatDeploymentGroups(d *api.Deployment, uuidLength int) string {
 		if state.ProgressDeadline != 0 {
 			progressDeadline = true
 		}
+		if state.BlueGreen {
+			blueGreen = true
+		}
 	}
 
 	// Sort the task group names to get a reliable ordering
@@ -663,6 +677,9 @@ func formatDeploymentGroups(d *api.Deployment, uuidLength int) string {
 	if progressDeadline {
 		rowString += "|Progress Deadline"
 	}
+	if blueGreen {
+		rowString += "|Retain Blue Until"
+	}
 
 	rows := make([]string, len(d.TaskGroups)+1)
 	rows[0] = rowString
@@ -692,6 +709,13 @@ func formatDeploymentGroups(d *api.Deployment, uuidLength int) string {
 				row += fmt.Sprintf("|%v", formatTime(state.RequireProgressBy))
 			}
 		}
+		if blueGreen {
+			if state.RetainBlueUntil.IsZero() {
+				row += fmt.Sprintf("|%v", "N/A")
+			} else {
+				row += fmt.Sprintf("|%v", formatTime(state.RetainBlueUntil))
+			}
+		}
 		rows[i] = row
 		i++
 	}
@@ -699,6 +723,112 @@ func formatDeploymentGroups(d *api.Deployment, uuidLength int) string {
 	return formatList(rows)
 }
 
+// formatCanaryAnalysis returns the result of the canary analysis of each
+// task group, or an empty string if no canaries are analyzed.
+func formatCanaryAnalysis(d *api.Deployment) string {
+	tgNames := make([]string, 0, len(d.TaskGroups))
+	for name, state := range d.TaskGroups {
+		if state.CanaryAnalysis != nil {
+			tgNames = append(tgNames, name)
+		}
+	}
+	if len(tgNames) == 0 {
+		return ""
+	}
+	sort.Strings(tgNames)
+
+	results := []string{"Task Group|Status|Started|Samples|Description"}
+	signals := []string{"Task Group|Signal|Canary|Stable|Threshold|Result"}
+	for _, tg := range tgNames {
+		analysis := d.TaskGroups[tg].CanaryAnalysis
+		results = append(results, fmt.Sprintf("%s|%s|%s|%d|%s",
+			tg, analysis.Status, formatTime(analysis.StartedAt), analysis.Samples, analysis.StatusDescription))
+
+		for _, signal := range analysis.Signals {
+			result := "ok"
+			if signal.Samples == 0 {
+				result = "no data"
+			} else if signal.Failed {
+				result = "failed"
+			}
+			signals = append(signals, fmt.Sprintf("%s|%s|%s|%s|%s|%s",
+				tg, signal.Name,
+				formatCanaryAnalysisValue(signal.Name, signal.Canary),
+				formatCanaryAnalysisValue(signal.Name, signal.Stable),
+				formatCanaryAnalysisThreshold(signal.Name, signal.Threshold),
+				result))
+		}
+	}
+
+	out := formatList(results)
+	if len(signals) > 1 {
+		out += "\n\n" + formatList(signals)
+	}
+	return out
+}
+
+// formatDeploymentHooks returns the state of the deployment hooks of each
+// task group, or an empty string if no task group has hooks.
+func formatDeploymentHooks(d *api.Deployment) string {
+	tgNames := make([]string, 0, len(d.TaskGroups))
+	for name, state := range d.TaskGroups {
+		if state.PreDeploy != nil || state.PostDeploy != nil {
+			tgNames = append(tgNames, name)
+		}
+	}
+	if len(tgNames) == 0 {
+		return ""
+	}
+	sort.Strings(tgNames)
+
+	rows := []string{"Task Group|Hook|Job|Group|Dispatched Job|Status|Description"}
+	for _, tg := range tgNames {
+		state := d.TaskGroups[tg]
+		for _, hook := range []struct {
+			name  string
+			state *api.DeploymentHookState
+		}{
+			{"pre_deploy", state.PreDeploy},
+			{"post_deploy", state.PostDeploy},
+		} {
+			if hook.state == nil {
+				continue
+			}
+			rows = append(rows, fmt.Sprintf("%s|%s|%s|%s|%s|%s|%s",
+				tg, hook.name, hook.state.Job, hook.state.Group, hook.state.DispatchedJobID,
+				hook.state.Status, hook.state.StatusDescription))
+		}
+	}
+	return formatList(rows)
+}
+
+// formatCanaryAnalysisValue formats the value of a canary analysis signal.
+func formatCanaryAnalysisValue(name string, value float64) string {
+	switch name {
+	case "checks":
+		return fmt.Sprintf("%.1f%% failed", value*100)
+	case "cpu":
+		return fmt.Sprintf("%.0f MHz", value)
+	case "memory":
+		return humanize.IBytes(uint64(value))
+	default:
+		return fmt.Sprintf("%.3g", value)
+	}
+}
+
+// formatCanaryAnalysisThreshold formats the maximum increase of a canary
+// analysis signal.
+func formatCanaryAnalysisThreshold(name string, threshold float64) string {
+	switch name {
+	case "checks":
+		return fmt.Sprintf("+%.1f%%", threshold*100)
+	case "cpu", "memory":
+		return fmt.Sprintf("+%.0f%%", threshold)
+	default:
+		return fmt.Sprintf("+%.3g", threshold)
+	}
+}
+
 func hasAutoRevert(d *api.Deployment) bool {
 	taskGroups := d.TaskGroups
 	for _, state := range taskGroups {
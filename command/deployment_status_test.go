@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/hashicorp/cli"
+	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/ci"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/posener/complete"
@@ -75,3 +76,36 @@ func TestDeploymentStatusCommand_AutocompleteArgs(t *testing.T) {
 	must.SliceLen(t, 1, res)
 	must.Eq(t, d.ID, res[0])
 }
+
+func TestDeploymentStatusCommand_CanaryAnalysis(t *testing.T) {
+	ci.Parallel(t)
+
+	d := &api.Deployment{
+		TaskGroups: map[string]*api.DeploymentState{
+			"web": {
+				DesiredCanaries: 1,
+				CanaryAnalysis: &api.CanaryAnalysisResult{
+					Status:            api.CanaryAnalysisStatusFailed,
+					StatusDescription: "Canaries exceeded the threshold of memory",
+					Samples:           10,
+					Signals: []*api.CanaryAnalysisSignal{
+						{Name: "restarts", Samples: 10},
+						{Name: "memory", Canary: 256 << 20, Stable: 128 << 20, Threshold: 20, Samples: 10, Failed: true},
+						{Name: "checks", Threshold: 0.05},
+					},
+				},
+			},
+			"api": {},
+		},
+	}
+
+	out := formatCanaryAnalysis(d)
+	must.StrContains(t, out, "Canaries exceeded the threshold of memory")
+	must.StrContains(t, out, "256 MiB")
+	must.StrContains(t, out, "+20%")
+	must.StrContains(t, out, "failed")
+	must.StrContains(t, out, "no data")
+	must.StrNotContains(t, out, "api")
+
+	must.Eq(t, "", formatCanaryAnalysis(&api.Deployment{}))
+}
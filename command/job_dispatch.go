@@ -9,6 +9,7 @@ import (
 	"net/url"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/nomad/api"
 	flaghelper "github.com/hashicorp/nomad/helper/flags"
@@ -67,6 +68,12 @@ Dispatch Options:
   -id-prefix-template
     Optional prefix template for dispatched job IDs.
 
+  -queue-status
+    Display the dispatch queue of the parameterized job instead of dispatching
+    it: its limits, the number of queued, pending and running dispatched jobs,
+    and how long the oldest queued job has been waiting. Requires the
+    'read-job' capability.
+
   -verbose
     Display full information.
 
@@ -86,6 +93,7 @@ func (c *JobDispatchCommand) AutocompleteFlags() complete.Flags {
 			"-meta":              complete.PredictAnything,
 			"-detach":            complete.PredictNothing,
 			"-idempotency-token": complete.PredictAnything,
+			"-queue-status":      complete.PredictNothing,
 			"-verbose":           complete.PredictNothing,
 			"-ui":                complete.PredictNothing,
 		})
@@ -118,7 +126,7 @@ func (c *JobDispatchCommand) AutocompleteArgs() complete.Predictor {
 func (c *JobDispatchCommand) Name() string { return "job dispatch" }
 
 func (c *JobDispatchCommand) Run(args []string) int {
-	var detach, verbose, openURL bool
+	var detach, verbose, openURL, queueStatus bool
 	var idempotencyToken string
 	var meta []string
 	var idPrefixTemplate string
@@ -131,6 +139,7 @@ func (c *JobDispatchCommand) Run(args []string) int {
 	flags.Var((*flaghelper.StringFlag)(&meta), "meta", "")
 	flags.StringVar(&idPrefixTemplate, "id-prefix-template", "", "")
 	flags.BoolVar(&openURL, "ui", false, "")
+	flags.BoolVar(&queueStatus, "queue-status", false, "")
 
 	if err := flags.Parse(args); err != nil {
 		return 1
@@ -144,6 +153,11 @@ func (c *JobDispatchCommand) Run(args []string) int {
 
 	// Check that we got one or two arguments
 	args = flags.Args()
+	if queueStatus && len(args) != 1 {
+		c.Ui.Error("This command takes one argument with -queue-status: <parameterized job>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
 	if l := len(args); l < 1 || l > 2 {
 		c.Ui.Error("This command takes one or two argument: <parameterized job> [input source]")
 		c.Ui.Error(commandErrorText(c))
@@ -196,6 +210,10 @@ func (c *JobDispatchCommand) Run(args []string) int {
 		return 1
 	}
 
+	if queueStatus {
+		return c.outputQueueStatus(client, jobID, namespace)
+	}
+
 	// Dispatch the job
 	w := &api.WriteOptions{
 		IdempotencyToken: idempotencyToken,
@@ -207,8 +225,8 @@ func (c *JobDispatchCommand) Run(args []string) int {
 		return 1
 	}
 
-	// See if an evaluation was created. If the job is periodic there will be no
-	// eval.
+	// See if an evaluation was created. If the job is periodic or queued there
+	// will be no eval.
 	evalCreated := resp.EvalID != ""
 
 	basic := []string{
@@ -217,6 +235,9 @@ func (c *JobDispatchCommand) Run(args []string) int {
 	if evalCreated {
 		basic = append(basic, fmt.Sprintf("Evaluation ID|%s", limit(resp.EvalID, length)))
 	}
+	if resp.Queued {
+		basic = append(basic, "Status|queued")
+	}
 	c.Ui.Output(formatKV(basic))
 
 	// Nothing to do
@@ -245,3 +266,75 @@ func (c *JobDispatchCommand) Run(args []string) int {
 	}
 	return mon.monitor(resp.EvalID)
 }
+
+// outputQueueStatus displays the limits of the dispatch queue of the
+// parameterized job and the dispatched jobs it holds.
+func (c *JobDispatchCommand) outputQueueStatus(client *api.Client, jobID, namespace string) int {
+	q := &api.QueryOptions{Namespace: namespace}
+	job, _, err := client.Jobs().Info(jobID, q)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying job: %s", err))
+		return 1
+	}
+	summary, _, err := client.Jobs().Summary(jobID, q)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying job summary: %s", err))
+		return 1
+	}
+
+	// Find the oldest queued dispatched job
+	children, _, err := client.Jobs().List(&api.QueryOptions{Namespace: namespace, Prefix: jobID})
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying dispatched jobs: %s", err))
+		return 1
+	}
+	var oldest int64
+	for _, child := range children {
+		if child.ParentID == jobID && child.Status == "queued" && (oldest == 0 || child.SubmitTime < oldest) {
+			oldest = child.SubmitTime
+		}
+	}
+
+	config := job.ParameterizedJob
+	concurrency, maxRunning, rate, burst := "allow", "unlimited", "unlimited", "-"
+	if config.Concurrency != "" {
+		concurrency = config.Concurrency
+	}
+	if config.MaxConcurrent > 0 {
+		maxRunning = fmt.Sprintf("%d", config.MaxConcurrent)
+	}
+	if queue := config.DispatchQueue; queue != nil {
+		concurrency = "queue"
+		if queue.MaxRunning > 0 {
+			maxRunning = fmt.Sprintf("%d", queue.MaxRunning)
+		}
+		if queue.Rate > 0 {
+			rate = fmt.Sprintf("%g/s", queue.Rate)
+			burst = fmt.Sprintf("%d", max(queue.Burst, 1))
+		}
+	}
+
+	counts := summary.Children
+	if counts == nil {
+		counts = &api.JobChildrenSummary{}
+	}
+
+	wait := "-"
+	if oldest != 0 {
+		wait = formatTimeDifference(time.Unix(0, oldest), time.Now(), time.Second)
+	}
+
+	basic := []string{
+		fmt.Sprintf("ID|%s", jobID),
+		fmt.Sprintf("Concurrency|%s", concurrency),
+		fmt.Sprintf("Max Running|%s", maxRunning),
+		fmt.Sprintf("Rate|%s", rate),
+		fmt.Sprintf("Burst|%s", burst),
+		fmt.Sprintf("Queued|%d", counts.Queued),
+		fmt.Sprintf("Pending|%d", counts.Pending),
+		fmt.Sprintf("Running|%d", counts.Running),
+		fmt.Sprintf("Oldest Queued Wait|%s", wait),
+	}
+	c.Ui.Output(formatKV(basic))
+	return 0
+}
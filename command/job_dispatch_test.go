@@ -52,6 +52,11 @@ func TestJobDispatchCommand_Fails(t *testing.T) {
 		t.Fatalf("expected failed query error, got: %s", out)
 	}
 	ui.ErrorWriter.Reset()
+
+	// Fails with an input source when displaying the queue status
+	must.One(t, cmd.Run([]string{"-queue-status", "foo", "-"}))
+	must.StrContains(t, ui.ErrorWriter.String(), "one argument with -queue-status")
+	ui.ErrorWriter.Reset()
 }
 
 func TestJobDispatchCommand_AutocompleteArgs(t *testing.T) {
@@ -79,6 +79,15 @@ Plan Options:
     Determines whether the diff between the remote job and planned job is shown.
     Defaults to true.
 
+  -explain
+    Shows how the scheduler evaluated each node for the placements of the job,
+    including the feasibility checker that rejected a node, the resource a node
+    didn't have enough of, and the score given by each scorer.
+
+  -explain-json
+    Outputs the plan, including the node explanations, in JSON format instead
+    of the diff and dry-run summary.
+
   -json
     Parses the job file as JSON. If the outer object has a Job field, such as
     from "nomad job inspect" or "nomad run -output", the value of the field is
@@ -116,6 +125,8 @@ func (c *JobPlanCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
 			"-diff":            complete.PredictNothing,
+			"-explain":         complete.PredictNothing,
+			"-explain-json":    complete.PredictNothing,
 			"-policy-override": complete.PredictNothing,
 			"-verbose":         complete.PredictNothing,
 			"-json":            complete.PredictNothing,
@@ -136,12 +147,14 @@ func (c *JobPlanCommand) AutocompleteArgs() complete.Predictor {
 
 func (c *JobPlanCommand) Name() string { return "job plan" }
 func (c *JobPlanCommand) Run(args []string) int {
-	var diff, policyOverride, verbose bool
+	var diff, policyOverride, verbose, explain, explainJSON bool
 	var vaultNamespace string
 
 	flagSet := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flagSet.Usage = func() { c.Ui.Output(c.Help()) }
 	flagSet.BoolVar(&diff, "diff", true, "")
+	flagSet.BoolVar(&explain, "explain", false, "")
+	flagSet.BoolVar(&explainJSON, "explain-json", false, "")
 	flagSet.BoolVar(&policyOverride, "policy-override", false, "")
 	flagSet.BoolVar(&verbose, "verbose", false, "")
 	flagSet.BoolVar(&c.JobGetter.JSON, "json", false, "")
@@ -205,9 +218,12 @@ func (c *JobPlanCommand) Run(args []string) int {
 	if policyOverride {
 		opts.PolicyOverride = true
 	}
+	if explain || explainJSON {
+		opts.Explain = true
+	}
 
 	if job.IsMultiregion() {
-		return c.multiregionPlan(client, job, opts, diff, verbose)
+		return c.multiregionPlan(client, job, opts, diff, verbose, explainJSON)
 	}
 
 	// Submit the job
@@ -217,6 +233,10 @@ func (c *JobPlanCommand) Run(args []string) int {
 		return 255
 	}
 
+	if explainJSON {
+		return c.outputPlanJSON(resp, getExitCode(resp))
+	}
+
 	runArgs := strings.Builder{}
 	for _, varArg := range c.JobGetter.Vars {
 		runArgs.WriteString(fmt.Sprintf("-var=%q ", varArg))
@@ -230,12 +250,12 @@ func (c *JobPlanCommand) Run(args []string) int {
 		runArgs.WriteString(fmt.Sprintf("-namespace=%q ", c.namespace))
 	}
 
-	exitCode := c.outputPlannedJob(job, resp, diff, verbose)
+	exitCode := c.outputPlannedJob(job, resp, diff, verbose, explain)
 	c.Ui.Output(c.Colorize().Color(formatJobModifyIndex(resp.JobModifyIndex, runArgs.String(), path)))
 	return exitCode
 }
 
-func (c *JobPlanCommand) multiregionPlan(client *api.Client, job *api.Job, opts *api.PlanOptions, diff, verbose bool) int {
+func (c *JobPlanCommand) multiregionPlan(client *api.Client, job *api.Job, opts *api.PlanOptions, diff, verbose, explainJSON bool) int {
 
 	var exitCode int
 	plans := map[string]*api.JobPlanResponse{}
@@ -258,9 +278,16 @@ func (c *JobPlanCommand) multiregionPlan(client *api.Client, job *api.Job, opts
 		return exitCode
 	}
 
+	if explainJSON {
+		for _, resp := range plans {
+			exitCode = max(exitCode, getExitCode(resp))
+		}
+		return c.outputPlanJSON(plans, exitCode)
+	}
+
 	for regionName, resp := range plans {
 		c.Ui.Output(c.Colorize().Color(fmt.Sprintf("[bold]Region: %q[reset]", regionName)))
-		regionExitCode := c.outputPlannedJob(job, resp, diff, verbose)
+		regionExitCode := c.outputPlannedJob(job, resp, diff, verbose, opts.Explain)
 		if regionExitCode > exitCode {
 			exitCode = regionExitCode
 		}
@@ -268,7 +295,19 @@ func (c *JobPlanCommand) multiregionPlan(client *api.Client, job *api.Job, opts
 	return exitCode
 }
 
-func (c *JobPlanCommand) outputPlannedJob(job *api.Job, resp *api.JobPlanResponse, diff, verbose bool) int {
+// outputPlanJSON outputs the plan in JSON format and returns the exit code,
+// unless the plan can't be formatted.
+func (c *JobPlanCommand) outputPlanJSON(plan any, exitCode int) int {
+	out, err := Format(true, "", plan)
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 255
+	}
+	c.Ui.Output(out)
+	return exitCode
+}
+
+func (c *JobPlanCommand) outputPlannedJob(job *api.Job, resp *api.JobPlanResponse, diff, verbose, explain bool) int {
 
 	// Print the diff if not disabled
 	if diff {
@@ -292,6 +331,13 @@ func (c *JobPlanCommand) outputPlannedJob(job *api.Job, resp *api.JobPlanRespons
 		c.addPreemptions(resp)
 	}
 
+	// Print how each node was evaluated
+	if explain {
+		c.Ui.Output(c.Colorize().Color("[bold]Placement explanations:[reset]"))
+		c.Ui.Output(formatPlanExplanations(resp, verbose))
+		c.Ui.Output("")
+	}
+
 	return getExitCode(resp)
 }
 
@@ -435,6 +481,96 @@ func formatDryRun(resp *api.JobPlanResponse, job *api.Job) string {
 	return out
 }
 
+// formatPlanExplanations produces a table of the nodes evaluated for each
+// failed task group and each placement of the plan.
+func formatPlanExplanations(resp *api.JobPlanResponse, verbose bool) string {
+	var out []string
+	for _, tg := range sortedTaskGroupFromMetrics(resp.FailedTGAllocs) {
+		out = append(out, fmt.Sprintf("Task Group %q (failed to place)", tg),
+			formatNodeExplanations(resp.FailedTGAllocs[tg].NodeExplanations, verbose))
+	}
+
+	names := make([]string, 0, len(resp.PlacementMetrics))
+	for name := range resp.PlacementMetrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		out = append(out, fmt.Sprintf("Allocation %q", name),
+			formatNodeExplanations(resp.PlacementMetrics[name].NodeExplanations, verbose))
+	}
+
+	if len(out) == 0 {
+		return "No placements to explain"
+	}
+	return strings.Join(out, "\n\n")
+}
+
+// formatNodeExplanations produces a table with the result of the evaluation
+// of each node and the score given by each scorer.
+func formatNodeExplanations(explanations []*api.NodeExplanation, verbose bool) string {
+	if len(explanations) == 0 {
+		return "No nodes were evaluated"
+	}
+
+	length := shortId
+	if verbose {
+		length = fullId
+	}
+
+	// Find all the scorers to use as columns
+	allScores := make(map[string]struct{})
+	for _, explanation := range explanations {
+		for score := range explanation.Scores {
+			allScores[score] = struct{}{}
+		}
+	}
+	scores := make([]string, 0, len(allScores))
+	for score := range allScores {
+		scores = append(scores, score)
+	}
+	sort.Strings(scores)
+
+	header := "Node ID|Node Name|Result|Reason"
+	if len(scores) > 0 {
+		header += "|" + strings.Join(scores, "|") + "|Final Score"
+	}
+	rows := make([]string, 0, len(explanations)+1)
+	rows = append(rows, header)
+
+	for _, explanation := range explanations {
+		result, reason := "feasible", ""
+		switch {
+		case explanation.FilteredBy != "":
+			result = fmt.Sprintf("filtered by %s", explanation.FilteredBy)
+			reason = explanation.FilterReason
+		case explanation.ExhaustedDimension != "":
+			result = "exhausted"
+			reason = explanation.ExhaustedDimension
+		}
+
+		row := fmt.Sprintf("%s|%s|%s|%s",
+			limit(explanation.NodeID, length), explanation.NodeName, result, reason)
+		if len(scores) > 0 {
+			for _, score := range scores {
+				if value, ok := explanation.Scores[score]; ok {
+					row += fmt.Sprintf("|%.3g", value)
+				} else {
+					row += "|"
+				}
+			}
+			if result == "feasible" {
+				row += fmt.Sprintf("|%.3g", explanation.NormScore)
+			} else {
+				row += "|"
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return formatList(rows)
+}
+
 // formatJobDiff produces an annotated diff of the job. If verbose mode is
 // set, added or deleted task groups and tasks are expanded.
 func formatJobDiff(job *api.JobDiff, verbose bool) string {
@@ -291,6 +291,59 @@ func TestPlanCommand_Preemptions(t *testing.T) {
 	must.StrContains(t, out, "service")
 }
 
+func TestPlanCommand_Explanations(t *testing.T) {
+	ci.Parallel(t)
+
+	resp := &api.JobPlanResponse{
+		FailedTGAllocs: map[string]*api.AllocationMetric{
+			"db": {
+				NodeExplanations: []*api.NodeExplanation{
+					{
+						NodeID:             "f6a8cfb2-2d2e-4b8e-a1e3-3a7b1c2d9e01",
+						NodeName:           "node1",
+						ExhaustedDimension: "memory",
+					},
+				},
+			},
+		},
+		PlacementMetrics: map[string]*api.AllocationMetric{
+			"example.web[0]": {
+				NodeExplanations: []*api.NodeExplanation{
+					{
+						NodeID:    "0a1c3e2f-7b4d-4c55-9a2e-6d3f8b1e7c02",
+						NodeName:  "node2",
+						Scores:    map[string]float64{"binpack": 0.25, "job-anti-affinity": -0.5},
+						NormScore: -0.125,
+					},
+					{
+						NodeID:       "f6a8cfb2-2d2e-4b8e-a1e3-3a7b1c2d9e01",
+						NodeName:     "node1",
+						FilteredBy:   "constraints",
+						FilterReason: "${attr.kernel.name} = linux",
+					},
+				},
+			},
+		},
+	}
+
+	out := formatPlanExplanations(resp, false)
+	must.StrContains(t, out, `Task Group "db" (failed to place)`)
+	must.StrContains(t, out, `Allocation "example.web[0]"`)
+	must.StrContains(t, out, "binpack")
+	must.StrContains(t, out, "job-anti-affinity")
+	must.StrContains(t, out, "filtered by constraints")
+	must.StrContains(t, out, "${attr.kernel.name} = linux")
+	must.StrContains(t, out, "exhausted")
+	must.StrContains(t, out, "-0.125")
+	must.StrContains(t, out, "0a1c3e2f ")
+	must.StrNotContains(t, out, "0a1c3e2f-7b4d")
+
+	out = formatPlanExplanations(resp, true)
+	must.StrContains(t, out, "0a1c3e2f-7b4d-4c55-9a2e-6d3f8b1e7c02")
+
+	must.Eq(t, "No placements to explain", formatPlanExplanations(&api.JobPlanResponse{}, false))
+}
+
 func TestPlanCommand_JSON(t *testing.T) {
 	ui := cli.NewMockUi()
 	cmd := &JobPlanCommand{
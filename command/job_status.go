@@ -266,10 +266,25 @@ func (c *JobStatusCommand) Run(args []string) int {
 		fmt.Sprintf("Parameterized|%v", parameterized),
 	}
 
+	if job.PriorityClass != nil && *job.PriorityClass != "" {
+		basic = append(basic, fmt.Sprintf("Priority Class|%s", *job.PriorityClass))
+	}
+
 	if job.DispatchIdempotencyToken != nil && *job.DispatchIdempotencyToken != "" {
 		basic = append(basic, fmt.Sprintf("Idempotency Token|%v", *job.DispatchIdempotencyToken))
 	}
 
+	if job.Deadline != nil && *job.Deadline > 0 && !periodic && !parameterized {
+		now := time.Now()
+		deadline := time.Unix(0, *job.SubmitTime).Add(*job.Deadline)
+		if now.Before(deadline) {
+			basic = append(basic, fmt.Sprintf("Deadline|%s (%s from now)",
+				formatTime(deadline), formatTimeDifference(now, deadline, time.Second)))
+		} else {
+			basic = append(basic, fmt.Sprintf("Deadline|%s (exceeded)", formatTime(deadline)))
+		}
+	}
+
 	if periodic && !parameterized {
 		if *job.Stop {
 			basic = append(basic, "Next Periodic Launch|none (job stopped)")
@@ -467,6 +482,10 @@ func (c *JobStatusCommand) outputJobInfo(client *api.Client, job *api.Job) error
 		return err
 	}
 
+	if err := c.outputJobDependencies(client, job); err != nil {
+		return err
+	}
+
 	// Determine latest evaluation with failures whose follow up hasn't
 	// completed, this is done while formatting
 	var latestFailedPlacement *api.Evaluation
@@ -696,6 +715,8 @@ func (c *JobStatusCommand) outputJobSummary(client *api.Client, job *api.Job) er
 			)
 		}
 		c.Ui.Output(formatList(summaries))
+
+		c.outputIndexSummary(job, summary)
 	}
 
 	// Always display the summary if we are periodic or parameterized, but
@@ -707,8 +728,8 @@ func (c *JobStatusCommand) outputJobSummary(client *api.Client, job *api.Job) er
 			c.Ui.Output(c.Colorize().Color("\n[bold]Children Job Summary[reset]"))
 		}
 		summaries := make([]string, 2)
-		summaries[0] = "Pending|Running|Dead"
-		summaries[1] = fmt.Sprintf("%d|%d|%d",
+		summaries[0] = "Queued|Pending|Running|Dead"
+		summaries[1] = fmt.Sprintf("%d|%d|%d|%d", summary.Children.Queued,
 			summary.Children.Pending, summary.Children.Running, summary.Children.Dead)
 		c.Ui.Output(formatList(summaries))
 	}
@@ -716,6 +737,93 @@ func (c *JobStatusCommand) outputJobSummary(client *api.Client, job *api.Job) er
 	return nil
 }
 
+// outputIndexSummary displays the progress of the indexes of the indexed task
+// groups of the job, if any.
+func (c *JobStatusCommand) outputIndexSummary(job *api.Job, summary *api.JobSummary) {
+	indexes := []string{"Task Group|Completed|Failed|Remaining"}
+	for _, tg := range job.TaskGroups {
+		if tg.Indexed == nil || tg.Name == nil || tg.Count == nil {
+			continue
+		}
+		tgs := summary.Summary[*tg.Name]
+		remaining := max(*tg.Count-tgs.CompletedIndexes-tgs.FailedIndexes, 0)
+		indexes = append(indexes, fmt.Sprintf("%s|%d|%d|%d",
+			*tg.Name, tgs.CompletedIndexes, tgs.FailedIndexes, remaining))
+	}
+	if len(indexes) == 1 {
+		return
+	}
+
+	c.Ui.Output(c.Colorize().Color("\n[bold]Index Progress[reset]"))
+	c.Ui.Output(formatList(indexes))
+}
+
+// outputJobDependencies displays the status of the jobs the given job depends
+// on and of the jobs that depend on it, if any.
+func (c *JobStatusCommand) outputJobDependencies(client *api.Client, job *api.Job) error {
+	q := &api.QueryOptions{Namespace: *job.Namespace}
+	deps, _, err := client.Jobs().Dependencies(*job.ID, q)
+	if err != nil {
+		// Servers that don't support job dependencies can't have jobs that
+		// depend on this one, so only fail if the job has dependencies.
+		if len(job.DependsOn) == 0 {
+			return nil
+		}
+		return fmt.Errorf("Error querying job dependencies: %s", err)
+	}
+
+	format := func(states []*api.JobDependencyState) string {
+		out := make([]string, len(states)+1)
+		out[0] = "Job ID|Condition|Job Status|Status"
+		for i, state := range states {
+			out[i+1] = fmt.Sprintf("%s|%s|%s|%s",
+				state.JobID, state.Condition, state.JobStatus, state.Status)
+		}
+		return formatList(out)
+	}
+
+	if len(deps.Dependencies) > 0 {
+		c.Ui.Output(c.Colorize().Color("\n[bold]Dependencies[reset]"))
+		c.Ui.Output(format(deps.Dependencies))
+		if blocked := formatBlockedJobDependencies(job, deps.Dependencies); blocked != "" {
+			c.Ui.Warn(blocked)
+		}
+	}
+	if len(deps.Dependents) > 0 {
+		c.Ui.Output(c.Colorize().Color("\n[bold]Dependents[reset]"))
+		c.Ui.Output(format(deps.Dependents))
+	}
+
+	return nil
+}
+
+// formatBlockedJobDependencies returns a warning describing the dependencies
+// that prevent a pending job from being placed until its upstream jobs are
+// registered or run again, or an empty string if there are none.
+func formatBlockedJobDependencies(job *api.Job, states []*api.JobDependencyState) string {
+	if job.Status == nil || *job.Status != "pending" {
+		return ""
+	}
+
+	var reasons []string
+	for _, state := range states {
+		switch state.Status {
+		case api.JobDependencyStatusMissing:
+			reasons = append(reasons, fmt.Sprintf(
+				"  * Upstream job %q does not exist", state.JobID))
+		case api.JobDependencyStatusUnsatisfiable:
+			reasons = append(reasons, fmt.Sprintf(
+				"  * Upstream job %q is dead but did not reach condition %q", state.JobID, state.Condition))
+		}
+	}
+	if len(reasons) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("\nJob %q will not be placed until these dependencies change:\n%s",
+		*job.ID, strings.Join(reasons, "\n"))
+}
+
 // outputReschedulingEvals displays eval IDs and time for any
 // delayed evaluations by task group
 func (c *JobStatusCommand) outputReschedulingEvals(client *api.Client, job *api.Job, allocListStubs []*api.AllocationListStub, uuidLength int) error {
@@ -14,6 +14,7 @@ import (
 	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/ci"
 	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/helper/pointer"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/testutil"
@@ -391,6 +392,30 @@ func TestJobStatusCommand_RescheduleEvals(t *testing.T) {
 	must.StrContains(t, out, e.ID[:8])
 }
 
+func TestJobStatusCommand_formatBlockedJobDependencies(t *testing.T) {
+	ci.Parallel(t)
+
+	job := &api.Job{ID: pointer.Of("transform"), Status: pointer.Of("pending")}
+	states := []*api.JobDependencyState{
+		{JobID: "extract", Condition: "successful", JobStatus: "dead", Status: api.JobDependencyStatusSatisfied},
+		{JobID: "purged", Condition: "complete", Status: api.JobDependencyStatusMissing},
+		{JobID: "load", Condition: "successful", JobStatus: "dead", Status: api.JobDependencyStatusUnsatisfiable},
+	}
+
+	out := formatBlockedJobDependencies(job, states)
+	must.StrContains(t, out, `Job "transform" will not be placed`)
+	must.StrContains(t, out, `Upstream job "purged" does not exist`)
+	must.StrContains(t, out, `Upstream job "load" is dead but did not reach condition "successful"`)
+	must.StrNotContains(t, out, "extract")
+
+	// Only pending jobs are held back by their dependencies
+	job.Status = pointer.Of("running")
+	must.Eq(t, "", formatBlockedJobDependencies(job, states))
+
+	job.Status = pointer.Of("pending")
+	must.Eq(t, "", formatBlockedJobDependencies(job, states[:1]))
+}
+
 func TestJobStatusCommand_ACL(t *testing.T) {
 	ci.Parallel(t)
 
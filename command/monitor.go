@@ -359,6 +359,12 @@ func formatAllocMetrics(metrics *api.AllocationMetric, scores bool, prefix strin
 		out += fmt.Sprintf("%s* Quota limit hit %q\n", prefix, dim)
 	}
 
+	// Print gang info
+	if metrics.GangDesired > 0 {
+		out += fmt.Sprintf("%s* Gang placement failed: %d of %d allocations could be placed\n",
+			prefix, metrics.GangPlaced, metrics.GangDesired)
+	}
+
 	// Print scores
 	if scores {
 		if len(metrics.ScoreMetaData) > 0 {
@@ -229,6 +229,7 @@ func parseNamespaceSpecImpl(result *api.Namespace, list *ast.ObjectList) error {
 	delete(m, "node_pool_config")
 	delete(m, "vault")
 	delete(m, "consul")
+	delete(m, "network_policy")
 
 	// Decode the rest
 	if err := mapstructure.WeakDecode(m, result); err != nil {
@@ -299,6 +300,22 @@ func parseNamespaceSpecImpl(result *api.Namespace, list *ast.ObjectList) error {
 		}
 	}
 
+	npolObj := list.Filter("network_policy")
+	if len(npolObj.Items) > 0 {
+		for _, o := range npolObj.Elem().Items {
+			ot, ok := o.Val.(*ast.ObjectType)
+			if !ok {
+				break
+			}
+			policy, err := parseNetworkPolicy(ot.List)
+			if err != nil {
+				return err
+			}
+			result.NetworkPolicy = policy
+			break
+		}
+	}
+
 	if metaO := list.Filter("meta"); len(metaO.Items) > 0 {
 		for _, o := range metaO.Elem().Items {
 			var m map[string]interface{}
@@ -313,3 +330,28 @@ func parseNamespaceSpecImpl(result *api.Namespace, list *ast.ObjectList) error {
 
 	return nil
 }
+
+// parseNetworkPolicy parses the network_policy block of a namespace. Each
+// allow block is decoded on its own, since decoding them together splits the
+// blocks with several keys into a rule per key.
+func parseNetworkPolicy(list *ast.ObjectList) (*api.NetworkPolicy, error) {
+	var m map[string]interface{}
+	if err := hcl.DecodeObject(&m, list); err != nil {
+		return nil, err
+	}
+	delete(m, "allow")
+
+	var policy api.NetworkPolicy
+	if err := mapstructure.WeakDecode(m, &policy); err != nil {
+		return nil, err
+	}
+
+	for _, o := range list.Filter("allow").Elem().Items {
+		var rule api.NetworkPolicyRule
+		if err := hcl.DecodeObject(&rule, o.Val); err != nil {
+			return nil, err
+		}
+		policy.Allow = append(policy.Allow, &rule)
+	}
+	return &policy, nil
+}
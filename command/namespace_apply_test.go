@@ -97,6 +97,19 @@ consul {
   allowed = ["prod", "apps*"]
 }
 
+network_policy {
+  default = "deny"
+
+  allow {
+    namespace = "ops"
+    job       = "prometheus"
+  }
+
+  allow {
+    service = "ingress"
+  }
+}
+
 meta {
   dept = "eng"
 }`,
@@ -120,6 +133,13 @@ meta {
 					Default: "prod",
 					Allowed: []string{"prod", "apps*"},
 				},
+				NetworkPolicy: &api.NetworkPolicy{
+					Default: "deny",
+					Allow: []*api.NetworkPolicyRule{
+						{Namespace: "ops", Job: "prometheus"},
+						{Service: "ingress"},
+					},
+				},
 				Meta: map[string]string{
 					"dept": "eng",
 				},
@@ -194,6 +194,20 @@ func (c *NamespaceStatusCommand) Run(args []string) int {
 		c.Ui.Output(formatKV(cConfigOut))
 	}
 
+	if ns.NetworkPolicy != nil {
+		c.Ui.Output(c.Colorize().Color("\n[bold]Network Policy[reset]"))
+		policy := ns.NetworkPolicy
+		c.Ui.Output(formatKV([]string{fmt.Sprintf("Default|%s", policy.Default)}))
+		if len(policy.Allow) > 0 {
+			rules := []string{"Namespace|Job|Service"}
+			for _, rule := range policy.Allow {
+				rules = append(rules, fmt.Sprintf("%s|%s|%s", rule.Namespace, rule.Job, rule.Service))
+			}
+			c.Ui.Output(c.Colorize().Color("\n[bold]Allowed[reset]"))
+			c.Ui.Output(formatList(rules))
+		}
+	}
+
 	return 0
 }
 
@@ -221,6 +235,7 @@ func formatNamespaceBasics(ns *api.Namespace) string {
 		fmt.Sprintf("Name|%s", ns.Name),
 		fmt.Sprintf("Description|%s", ns.Description),
 		fmt.Sprintf("Quota|%s", ns.Quota),
+		fmt.Sprintf("Scheduler Weight|%d", ns.SchedulerWeight),
 		fmt.Sprintf("Enabled Drivers|%s", enabled_drivers),
 		fmt.Sprintf("Disabled Drivers|%s", disabled_drivers),
 		fmt.Sprintf("Enabled Network Modes|%s", enabled_network_modes),
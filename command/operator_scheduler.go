@@ -31,6 +31,10 @@ Usage: nomad operator scheduler <subcommand> [options]
 
       $ nomad operator scheduler set-config -scheduler-algorithm=spread
 
+  Simulate submitting a job against a snapshot of the cluster:
+
+      $ nomad operator scheduler simulate -job example.nomad.hcl backup.snap
+
   Please see the individual subcommand help for detailed usage information.
 `
 	return strings.TrimSpace(helpText)
@@ -86,8 +86,30 @@ func (o *OperatorSchedulerGetConfig) Run(args []string) int {
 		fmt.Sprintf("Preemption Service Scheduler|%v", schedConfig.PreemptionConfig.ServiceSchedulerEnabled),
 		fmt.Sprintf("Preemption Batch Scheduler|%v", schedConfig.PreemptionConfig.BatchSchedulerEnabled),
 		fmt.Sprintf("Preemption SysBatch Scheduler|%v", schedConfig.PreemptionConfig.SysBatchSchedulerEnabled),
+		fmt.Sprintf("Namespace Fair Share|%v", schedConfig.NamespaceFairShare.Enabled),
+		fmt.Sprintf("Namespace Default Weight|%v", schedConfig.NamespaceFairShare.DefaultWeight),
+		fmt.Sprintf("Descheduler|%v", schedConfig.Descheduler.Enabled),
+		fmt.Sprintf("Descheduler Dry Run|%v", schedConfig.Descheduler.DryRun),
+		fmt.Sprintf("Descheduler Max Migrations|%v", schedConfig.Descheduler.MaxMigrations),
+		fmt.Sprintf("Descheduler Utilization Threshold|%v", schedConfig.Descheduler.UtilizationThreshold),
+		fmt.Sprintf("Descheduler Fragmentation Threshold|%v", schedConfig.Descheduler.FragmentationThreshold),
 		fmt.Sprintf("Modify Index|%v", resp.SchedulerConfig.ModifyIndex),
 	}))
+
+	if len(schedConfig.PriorityClasses) > 0 {
+		classes := make([]string, 0, len(schedConfig.PriorityClasses)+1)
+		classes = append(classes, "Name|Priority|Preemption Policy|Non Preemptible|Namespaces")
+		for _, class := range schedConfig.PriorityClasses {
+			namespaces := "*"
+			if len(class.Namespaces) > 0 {
+				namespaces = strings.Join(class.Namespaces, ",")
+			}
+			classes = append(classes, fmt.Sprintf("%s|%d|%s|%v|%s",
+				class.Name, class.Priority, class.PreemptionPolicy, class.NonPreemptible, namespaces))
+		}
+		o.Ui.Output(o.Colorize().Color("\n[bold]Priority Classes[reset]"))
+		o.Ui.Output(formatList(classes))
+	}
 	return 0
 }
 
@@ -31,6 +31,14 @@ type OperatorSchedulerSetConfig struct {
 	preemptServiceScheduler  flagHelper.BoolValue
 	preemptSysBatchScheduler flagHelper.BoolValue
 	preemptSystemScheduler   flagHelper.BoolValue
+	namespaceFairShare       flagHelper.BoolValue
+	namespaceDefaultWeight   int
+
+	descheduler                       flagHelper.BoolValue
+	deschedulerDryRun                 flagHelper.BoolValue
+	deschedulerMaxMigrations          int
+	deschedulerUtilizationThreshold   int
+	deschedulerFragmentationThreshold int
 }
 
 func (o *OperatorSchedulerSetConfig) AutocompleteFlags() complete.Flags {
@@ -48,6 +56,14 @@ func (o *OperatorSchedulerSetConfig) AutocompleteFlags() complete.Flags {
 			"-preempt-service-scheduler":  complete.PredictSet("true", "false"),
 			"-preempt-sysbatch-scheduler": complete.PredictSet("true", "false"),
 			"-preempt-system-scheduler":   complete.PredictSet("true", "false"),
+			"-namespace-fair-share":       complete.PredictSet("true", "false"),
+			"-namespace-default-weight":   complete.PredictAnything,
+			"-descheduler":                complete.PredictSet("true", "false"),
+			"-descheduler-dry-run":        complete.PredictSet("true", "false"),
+			"-descheduler-max-migrations": complete.PredictAnything,
+
+			"-descheduler-utilization-threshold":   complete.PredictAnything,
+			"-descheduler-fragmentation-threshold": complete.PredictAnything,
 		},
 	)
 }
@@ -72,6 +88,13 @@ func (o *OperatorSchedulerSetConfig) Run(args []string) int {
 	flags.Var(&o.preemptServiceScheduler, "preempt-service-scheduler", "")
 	flags.Var(&o.preemptSysBatchScheduler, "preempt-sysbatch-scheduler", "")
 	flags.Var(&o.preemptSystemScheduler, "preempt-system-scheduler", "")
+	flags.Var(&o.namespaceFairShare, "namespace-fair-share", "")
+	flags.IntVar(&o.namespaceDefaultWeight, "namespace-default-weight", 0, "")
+	flags.Var(&o.descheduler, "descheduler", "")
+	flags.Var(&o.deschedulerDryRun, "descheduler-dry-run", "")
+	flags.IntVar(&o.deschedulerMaxMigrations, "descheduler-max-migrations", -1, "")
+	flags.IntVar(&o.deschedulerUtilizationThreshold, "descheduler-utilization-threshold", -1, "")
+	flags.IntVar(&o.deschedulerFragmentationThreshold, "descheduler-fragmentation-threshold", -1, "")
 
 	if err := flags.Parse(args); err != nil {
 		return 1
@@ -134,6 +157,21 @@ func (o *OperatorSchedulerSetConfig) Run(args []string) int {
 	o.preemptServiceScheduler.Merge(&schedulerConfig.PreemptionConfig.ServiceSchedulerEnabled)
 	o.preemptSysBatchScheduler.Merge(&schedulerConfig.PreemptionConfig.SysBatchSchedulerEnabled)
 	o.preemptSystemScheduler.Merge(&schedulerConfig.PreemptionConfig.SystemSchedulerEnabled)
+	o.namespaceFairShare.Merge(&schedulerConfig.NamespaceFairShare.Enabled)
+	if o.namespaceDefaultWeight > 0 {
+		schedulerConfig.NamespaceFairShare.DefaultWeight = o.namespaceDefaultWeight
+	}
+	o.descheduler.Merge(&schedulerConfig.Descheduler.Enabled)
+	o.deschedulerDryRun.Merge(&schedulerConfig.Descheduler.DryRun)
+	if o.deschedulerMaxMigrations >= 0 {
+		schedulerConfig.Descheduler.MaxMigrations = o.deschedulerMaxMigrations
+	}
+	if o.deschedulerUtilizationThreshold >= 0 {
+		schedulerConfig.Descheduler.UtilizationThreshold = o.deschedulerUtilizationThreshold
+	}
+	if o.deschedulerFragmentationThreshold >= 0 {
+		schedulerConfig.Descheduler.FragmentationThreshold = o.deschedulerFragmentationThreshold
+	}
 
 	// Check-and-set the new configuration.
 	result, _, err := client.Operator().SchedulerCASConfiguration(schedulerConfig, nil)
@@ -208,6 +246,40 @@ Scheduler Set Config Options:
   -preempt-system-scheduler=[true|false]
     Specifies whether preemption for system jobs is enabled. Note that if this
     is set to true, then system jobs can preempt any other jobs.
+
+  -namespace-fair-share=[true|false]
+    When true, the eval broker shares scheduler workers between namespaces
+    according to their weights, instead of dequeueing evaluations only by
+    priority. This prevents a single namespace with many evaluations from
+    starving the others.
+
+  -namespace-default-weight=<weight>
+    Specifies the fair share weight of namespaces that don't set their own
+    scheduler_weight.
+
+  -descheduler=[true|false]
+    When true, the leader periodically migrates healthy service allocations
+    away from nodes above the utilization threshold, from nodes that no
+    longer match their affinities or spread targets, and from nodes below
+    the fragmentation threshold when using the binpack algorithm.
+
+  -descheduler-dry-run=[true|false]
+    When true, the descheduler only logs the allocations it would migrate.
+
+  -descheduler-max-migrations=<count>
+    Specifies the maximum number of allocations migrated by each run of the
+    descheduler. Defaults to 10 when set to 0.
+
+  -descheduler-utilization-threshold=<percent>
+    Specifies the percentage of CPU or memory allocated on a node above which
+    the descheduler migrates allocations away from it. Defaults to 80 when set
+    to 0.
+
+  -descheduler-fragmentation-threshold=<percent>
+    Specifies the percentage of CPU and memory allocated on a node below
+    which the descheduler migrates allocations away from it to consolidate
+    them on fewer nodes. Only used by the binpack algorithm. Set to 0 to
+    disable.
 `
 	return strings.TrimSpace(helpText)
 }
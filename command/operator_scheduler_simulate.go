@@ -0,0 +1,448 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/cli"
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/helper"
+	flaghelper "github.com/hashicorp/nomad/helper/flags"
+	"github.com/hashicorp/nomad/helper/raftutil"
+	"github.com/hashicorp/nomad/nomad"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/scheduler"
+	"github.com/posener/complete"
+)
+
+// Ensure OperatorSchedulerSimulate satisfies the cli.Command interface.
+var _ cli.Command = &OperatorSchedulerSimulate{}
+
+type OperatorSchedulerSimulate struct {
+	Meta
+	JobGetter
+
+	jobs       flaghelper.StringFlag
+	drainNodes flaghelper.StringFlag
+
+	schedulerAlgorithm       string
+	memoryOversubscription   flaghelper.BoolValue
+	preemptBatchScheduler    flaghelper.BoolValue
+	preemptServiceScheduler  flaghelper.BoolValue
+	preemptSysBatchScheduler flaghelper.BoolValue
+	preemptSystemScheduler   flaghelper.BoolValue
+
+	json    bool
+	verbose bool
+}
+
+func (o *OperatorSchedulerSimulate) AutocompleteFlags() complete.Flags {
+	return complete.Flags{
+		"-job":                        complete.PredictFiles("*.nomad.hcl"),
+		"-drain-node":                 complete.PredictAnything,
+		"-var":                        complete.PredictAnything,
+		"-var-file":                   complete.PredictFiles("*.var"),
+		"-scheduler-algorithm":        complete.PredictSet(string(structs.SchedulerAlgorithmBinpack), string(structs.SchedulerAlgorithmSpread)),
+		"-memory-oversubscription":    complete.PredictSet("true", "false"),
+		"-preempt-batch-scheduler":    complete.PredictSet("true", "false"),
+		"-preempt-service-scheduler":  complete.PredictSet("true", "false"),
+		"-preempt-sysbatch-scheduler": complete.PredictSet("true", "false"),
+		"-preempt-system-scheduler":   complete.PredictSet("true", "false"),
+		"-json":                       complete.PredictNothing,
+		"-verbose":                    complete.PredictNothing,
+	}
+}
+
+func (o *OperatorSchedulerSimulate) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFiles("*.snap")
+}
+
+func (o *OperatorSchedulerSimulate) Name() string { return "operator scheduler simulate" }
+
+func (o *OperatorSchedulerSimulate) Run(args []string) int {
+
+	flags := o.Meta.FlagSet(o.Name(), FlagSetNone)
+	flags.Usage = func() { o.Ui.Output(o.Help()) }
+
+	flags.Var(&o.jobs, "job", "")
+	flags.Var(&o.drainNodes, "drain-node", "")
+	flags.Var(&o.JobGetter.Vars, "var", "")
+	flags.Var(&o.JobGetter.VarFiles, "var-file", "")
+	flags.StringVar(&o.schedulerAlgorithm, "scheduler-algorithm", "", "")
+	flags.Var(&o.memoryOversubscription, "memory-oversubscription", "")
+	flags.Var(&o.preemptBatchScheduler, "preempt-batch-scheduler", "")
+	flags.Var(&o.preemptServiceScheduler, "preempt-service-scheduler", "")
+	flags.Var(&o.preemptSysBatchScheduler, "preempt-sysbatch-scheduler", "")
+	flags.Var(&o.preemptSystemScheduler, "preempt-system-scheduler", "")
+	flags.BoolVar(&o.json, "json", false, "")
+	flags.BoolVar(&o.verbose, "verbose", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly one argument
+	args = flags.Args()
+	if len(args) != 1 {
+		o.Ui.Error("This command takes one argument: <file>")
+		o.Ui.Error(commandErrorText(o))
+		return 1
+	}
+
+	if len(o.jobs) == 0 && len(o.drainNodes) == 0 {
+		o.Ui.Error("At least one of -job or -drain-node must be specified")
+		o.Ui.Error(commandErrorText(o))
+		return 1
+	}
+
+	switch algo := structs.SchedulerAlgorithm(o.schedulerAlgorithm); algo {
+	case "", structs.SchedulerAlgorithmBinpack, structs.SchedulerAlgorithmSpread:
+	default:
+		o.Ui.Error(fmt.Sprintf("Invalid scheduler algorithm %q", algo))
+		return 1
+	}
+
+	// Parse the candidate jobs before doing the expensive work of restoring
+	// the snapshot.
+	jobs := make([]*structs.Job, 0, len(o.jobs))
+	for _, path := range o.jobs {
+		job, err := o.parseJob(path)
+		if err != nil {
+			o.Ui.Error(err.Error())
+			return 1
+		}
+		jobs = append(jobs, job)
+	}
+
+	f, err := os.Open(args[0])
+	if err != nil {
+		o.Ui.Error(fmt.Sprintf("Error opening snapshot file: %s", err))
+		return 1
+	}
+	defer f.Close()
+
+	_, store, _, err := raftutil.RestoreFromArchive(f, nil)
+	if err != nil {
+		o.Ui.Error(fmt.Sprintf("Failed to read archive file: %s", err))
+		return 1
+	}
+
+	logger := hclog.New(&hclog.LoggerOptions{
+		Name:   "simulate",
+		Level:  hclog.Error,
+		Output: os.Stderr,
+	})
+	sim, err := scheduler.NewSimulator(logger, store)
+	if err != nil {
+		o.Ui.Error(fmt.Sprintf("Error creating simulation: %s", err))
+		return 1
+	}
+
+	if err := o.updateSchedulerConfig(sim); err != nil {
+		o.Ui.Error(fmt.Sprintf("Error updating scheduler configuration: %s", err))
+		return 1
+	}
+
+	for _, prefix := range o.drainNodes {
+		nodeID, err := lookupSimulatedNode(store, prefix)
+		if err != nil {
+			o.Ui.Error(err.Error())
+			return 1
+		}
+		if err := sim.DrainNode(nodeID); err != nil {
+			o.Ui.Error(fmt.Sprintf("Error draining node: %s", err))
+			return 1
+		}
+	}
+
+	for _, job := range jobs {
+		// Apply the implied constraints and priority class the servers would
+		// apply when registering the job
+		job, warnings, err := nomad.MutateSimulatedJob(store, job)
+		if err != nil {
+			o.Ui.Error(fmt.Sprintf("Error registering job: %s", err))
+			return 1
+		}
+		if len(warnings) > 0 {
+			o.Ui.Warn(o.Colorize().Color(fmt.Sprintf("[bold][yellow]Job %q warnings:\n%s[reset]",
+				job.ID, helper.MergeMultierrorWarnings(warnings...))))
+		}
+
+		if err := sim.RegisterJob(job); err != nil {
+			o.Ui.Error(fmt.Sprintf("Error registering job: %s", err))
+			return 1
+		}
+	}
+
+	results, err := sim.Run()
+	if err != nil {
+		o.Ui.Error(fmt.Sprintf("Error running simulation: %s", err))
+		return 1
+	}
+
+	if o.json {
+		out, err := Format(true, "", results)
+		if err != nil {
+			o.Ui.Error(err.Error())
+			return 1
+		}
+		o.Ui.Output(out)
+		return 0
+	}
+
+	length := shortId
+	if o.verbose {
+		length = fullId
+	}
+
+	if len(results) == 0 {
+		o.Ui.Output("No evaluations were created")
+		return 0
+	}
+	for i, result := range results {
+		if i > 0 {
+			o.Ui.Output("")
+		}
+		o.Ui.Output(o.Colorize().Color(formatSimulatedEval(store, result, length)))
+	}
+	return 0
+}
+
+// parseJob parses and validates the jobspec at the given path.
+func (o *OperatorSchedulerSimulate) parseJob(path string) (*structs.Job, error) {
+	o.JobGetter.Strict = true
+	o.JobGetter.JSON = strings.HasSuffix(path, ".json")
+	_, aj, err := o.JobGetter.Get(path)
+	if err != nil {
+		return nil, fmt.Errorf("Error getting job struct: %s", err)
+	}
+
+	job := agent.ApiJobToStructJob(aj)
+	job.Canonicalize()
+	if err := job.Validate(); err != nil {
+		return nil, fmt.Errorf("Job %q is invalid: %s", job.ID, err)
+	}
+	return job, nil
+}
+
+// updateSchedulerConfig applies the scheduler configuration flags to the
+// simulation.
+func (o *OperatorSchedulerSimulate) updateSchedulerConfig(sim *scheduler.Simulator) error {
+	_, current, err := sim.State().SchedulerConfig()
+	if err != nil {
+		return err
+	}
+
+	config := new(structs.SchedulerConfiguration)
+	if current != nil {
+		*config = *current
+	}
+
+	if o.schedulerAlgorithm != "" {
+		config.SchedulerAlgorithm = structs.SchedulerAlgorithm(o.schedulerAlgorithm)
+	}
+	o.memoryOversubscription.Merge(&config.MemoryOversubscriptionEnabled)
+	o.preemptBatchScheduler.Merge(&config.PreemptionConfig.BatchSchedulerEnabled)
+	o.preemptServiceScheduler.Merge(&config.PreemptionConfig.ServiceSchedulerEnabled)
+	o.preemptSysBatchScheduler.Merge(&config.PreemptionConfig.SysBatchSchedulerEnabled)
+	o.preemptSystemScheduler.Merge(&config.PreemptionConfig.SystemSchedulerEnabled)
+
+	return sim.SetSchedulerConfig(config)
+}
+
+// lookupSimulatedNode returns the ID of the single node in the snapshot
+// matching the given prefix.
+func lookupSimulatedNode(store *state.StateStore, prefix string) (string, error) {
+	// The state store only looks up even-length prefixes so filter the nodes
+	// matching the sanitized prefix
+	iter, err := store.NodesByIDPrefix(nil, sanitizeUUIDPrefix(prefix))
+	if err != nil {
+		return "", fmt.Errorf("Error looking up node %q: %s", prefix, err)
+	}
+
+	var ids []string
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		id := raw.(*structs.Node).ID
+		if strings.HasPrefix(id, prefix) {
+			ids = append(ids, id)
+		}
+	}
+
+	switch len(ids) {
+	case 0:
+		return "", fmt.Errorf("No node(s) with prefix %q found", prefix)
+	case 1:
+		return ids[0], nil
+	default:
+		return "", fmt.Errorf("Prefix %q matched multiple nodes: %s", prefix, strings.Join(ids, ", "))
+	}
+}
+
+// formatSimulatedEval formats the placements, stops, preemptions and
+// placement failures of a simulated evaluation.
+func formatSimulatedEval(store *state.StateStore, result *scheduler.SimulatedEval, length int) string {
+	eval := result.Eval
+
+	nodeName := func(id string) string {
+		node, err := store.NodeByID(nil, id)
+		if err != nil || node == nil {
+			return ""
+		}
+		return node.Name
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "[bold]==> Evaluation %q (%s) for job %q in namespace %q: %s[reset]\n",
+		limit(eval.ID, length), eval.TriggeredBy, eval.JobID, eval.Namespace, eval.Status)
+
+	placements := []string{"Alloc ID|Alloc Name|Node ID|Node Name"}
+	stops := []string{"Alloc ID|Alloc Name|Node ID|Node Name|Description"}
+	preemptions := []string{"Alloc ID|Job ID|Task Group|Node ID|Node Name"}
+	for _, plan := range result.Plans {
+		for _, nodeID := range slices.Sorted(maps.Keys(plan.NodeAllocation)) {
+			for _, alloc := range plan.NodeAllocation[nodeID] {
+				placements = append(placements, fmt.Sprintf("%s|%s|%s|%s",
+					limit(alloc.ID, length), alloc.Name, limit(nodeID, length), nodeName(nodeID)))
+			}
+		}
+		for _, nodeID := range slices.Sorted(maps.Keys(plan.NodeUpdate)) {
+			for _, alloc := range plan.NodeUpdate[nodeID] {
+				stops = append(stops, fmt.Sprintf("%s|%s|%s|%s|%s",
+					limit(alloc.ID, length), alloc.Name, limit(nodeID, length), nodeName(nodeID),
+					alloc.DesiredDescription))
+			}
+		}
+		for _, nodeID := range slices.Sorted(maps.Keys(plan.NodePreemptions)) {
+			for _, alloc := range plan.NodePreemptions[nodeID] {
+				preemptions = append(preemptions, fmt.Sprintf("%s|%s|%s|%s|%s",
+					limit(alloc.ID, length), alloc.JobID, alloc.TaskGroup, limit(nodeID, length),
+					nodeName(nodeID)))
+			}
+		}
+	}
+
+	for _, section := range []struct {
+		title string
+		rows  []string
+	}{
+		{"Placements", placements},
+		{"Stops", stops},
+		{"Preemptions", preemptions},
+	} {
+		if len(section.rows) == 1 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n[bold]%s[reset]\n%s\n", section.title, formatList(section.rows))
+	}
+
+	if len(eval.FailedTGAllocs) > 0 {
+		b.WriteString("\n[bold]Placement Failures[reset]\n")
+		tgs := slices.Sorted(maps.Keys(eval.FailedTGAllocs))
+		for _, tg := range tgs {
+			metrics := apiAllocMetric(eval.FailedTGAllocs[tg])
+
+			noun := "allocation"
+			if metrics.CoalescedFailures > 0 {
+				noun += "s"
+			}
+			fmt.Fprintf(&b, "[yellow]Task Group %q (failed to place %d %s):\n[reset]",
+				tg, metrics.CoalescedFailures+1, noun)
+			fmt.Fprintf(&b, "[yellow]%s[reset]\n", formatAllocMetrics(metrics, false, "  "))
+		}
+	}
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// apiAllocMetric converts the allocation metrics returned by the scheduler
+// to their API representation so they can be formatted like the metrics of
+// a real evaluation.
+func apiAllocMetric(m *structs.AllocMetric) *api.AllocationMetric {
+	var out api.AllocationMetric
+	buf, err := json.Marshal(m)
+	if err == nil {
+		_ = json.Unmarshal(buf, &out)
+	}
+	return &out
+}
+
+func (o *OperatorSchedulerSimulate) Synopsis() string {
+	return "Simulate scheduling decisions against a snapshot"
+}
+
+func (o *OperatorSchedulerSimulate) Help() string {
+	helpText := `
+Usage: nomad operator scheduler simulate [options] <file>
+
+  Runs the scheduler against the state of a cluster restored from a snapshot
+  file and displays the resulting placements, stops, preemptions and
+  placement failures. The simulation runs locally, without contacting the
+  Nomad servers, and nothing is written back to the cluster or the snapshot.
+
+  Jobs are given the implicit constraints and the priority of their priority
+  class from the scheduler configuration of the snapshot, like the servers do
+  when registering them. Server-side mutations and checks that depend on the
+  configuration of the servers, such as implicit workload identities, Vault
+  and Consul cluster checks and the job_default_priority of the servers, are
+  not applied. Node drains migrate every allocation on the node at once, as if the
+  drain deadline had been reached.
+
+  To see what would happen if the job in "example.nomad.hcl" was submitted
+  to the cluster saved in "backup.snap":
+
+    $ nomad operator scheduler simulate -job example.nomad.hcl backup.snap
+
+Scheduler Simulate Options:
+
+  -job=<path>
+    Path to a jobspec to register in the simulation. May be specified multiple
+    times. Files ending in ".json" are parsed as JSON jobs.
+
+  -drain-node=<node id>
+    ID or prefix of a node to drain in the simulation. May be specified
+    multiple times.
+
+  -var 'key=value'
+    Variable for template, can be used multiple times.
+
+  -var-file=path
+    Path to HCL2 file containing user variables.
+
+  -scheduler-algorithm=["binpack"|"spread"]
+    Overrides the scheduler algorithm used by the simulation.
+
+  -memory-oversubscription=[true|false]
+    Overrides whether memory oversubscription is enabled in the simulation.
+
+  -preempt-batch-scheduler=[true|false]
+    Overrides whether preemption for batch jobs is enabled in the simulation.
+
+  -preempt-service-scheduler=[true|false]
+    Overrides whether preemption for service jobs is enabled in the
+    simulation.
+
+  -preempt-sysbatch-scheduler=[true|false]
+    Overrides whether preemption for system batch jobs is enabled in the
+    simulation.
+
+  -preempt-system-scheduler=[true|false]
+    Overrides whether preemption for system jobs is enabled in the simulation.
+
+  -json
+    Output the simulated evaluations and plans in JSON format.
+
+  -verbose
+    Display full information.
+`
+	return strings.TrimSpace(helpText)
+}
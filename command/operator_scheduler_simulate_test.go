@@ -0,0 +1,76 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/cli"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+func TestOperatorSchedulerSimulate_Fails(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{
+			name:     "no snapshot",
+			args:     []string{"-job", "example.nomad.hcl"},
+			expected: "This command takes one argument",
+		},
+		{
+			name:     "no changes",
+			args:     []string{"backup.snap"},
+			expected: "At least one of -job or -drain-node must be specified",
+		},
+		{
+			name:     "invalid algorithm",
+			args:     []string{"-drain-node", "abc", "-scheduler-algorithm", "random", "backup.snap"},
+			expected: `Invalid scheduler algorithm "random"`,
+		},
+		{
+			name:     "missing snapshot",
+			args:     []string{"-drain-node", "abc", "/unicorns/leprechauns.snap"},
+			expected: "Error opening snapshot file",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ui := cli.NewMockUi()
+			cmd := &OperatorSchedulerSimulate{Meta: Meta{Ui: ui}}
+			must.One(t, cmd.Run(tc.args))
+			must.StrContains(t, ui.ErrorWriter.String(), tc.expected)
+		})
+	}
+}
+
+func TestOperatorSchedulerSimulate_LookupNode(t *testing.T) {
+	ci.Parallel(t)
+
+	store := state.TestStateStore(t)
+	node1, node2 := mock.Node(), mock.Node()
+	node1.ID = "aaaa1111-0000-0000-0000-000000000000"
+	node2.ID = "aaaa2222-0000-0000-0000-000000000000"
+	must.NoError(t, store.UpsertNode(structs.MsgTypeTestSetup, 100, node1))
+	must.NoError(t, store.UpsertNode(structs.MsgTypeTestSetup, 101, node2))
+
+	id, err := lookupSimulatedNode(store, "aaaa1")
+	must.NoError(t, err)
+	must.Eq(t, node1.ID, id)
+
+	_, err = lookupSimulatedNode(store, "aaaa")
+	must.ErrorContains(t, err, "matched multiple nodes")
+
+	_, err = lookupSimulatedNode(store, "bbbb")
+	must.ErrorContains(t, err, "No node(s) with prefix")
+}
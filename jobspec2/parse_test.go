@@ -1180,3 +1180,364 @@ func TestIdentity(t *testing.T) {
 	must.Eq(t, "sighup", altID.ChangeSignal)
 	must.Eq(t, 2*time.Hour, altID.TTL)
 }
+
+func TestParse_DependsOn(t *testing.T) {
+	t.Parallel()
+
+	hclBytes, err := os.ReadFile("test-fixtures/depends-on.hcl")
+	require.NoError(t, err)
+	job, err := ParseWithConfig(&ParseConfig{
+		Path:    "test-fixtures/depends-on.hcl",
+		Body:    hclBytes,
+		AllowFS: false,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, []*api.JobDependency{
+		{JobID: pointerOf("extract")},
+		{JobID: pointerOf("cleanup"), Condition: pointerOf("failed")},
+	}, job.DependsOn)
+}
+
+func TestParse_TopologySpread(t *testing.T) {
+	t.Parallel()
+
+	hclBytes, err := os.ReadFile("test-fixtures/topology-spread.hcl")
+	require.NoError(t, err)
+	job, err := ParseWithConfig(&ParseConfig{
+		Path:    "test-fixtures/topology-spread.hcl",
+		Body:    hclBytes,
+		AllowFS: false,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, []*api.TopologySpread{
+		{Attribute: "${node.datacenter}"},
+	}, job.TopologySpreads)
+	require.Equal(t, []*api.TopologySpread{
+		{Attribute: "${meta.rack}", MaxSkew: pointerOf(2)},
+	}, job.TaskGroups[0].TopologySpreads)
+}
+
+func TestParse_JobAffinity(t *testing.T) {
+	t.Parallel()
+
+	hclBytes, err := os.ReadFile("test-fixtures/job-affinity.hcl")
+	require.NoError(t, err)
+	job, err := ParseWithConfig(&ParseConfig{
+		Path:    "test-fixtures/job-affinity.hcl",
+		Body:    hclBytes,
+		AllowFS: false,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, []*api.JobAffinity{
+		{JobID: "batch-reports", Anti: pointerOf(true), Required: pointerOf(true)},
+	}, job.JobAffinities)
+	require.Equal(t, []*api.JobAffinity{
+		{JobID: "cache", Group: "redis", Weight: pointerOf(int8(80))},
+		{Meta: map[string]string{"tier": "storage"}, Anti: pointerOf(true)},
+	}, job.TaskGroups[0].JobAffinities)
+}
+
+func TestParse_Indexed(t *testing.T) {
+	t.Parallel()
+
+	hclBytes, err := os.ReadFile("test-fixtures/indexed.hcl")
+	require.NoError(t, err)
+	job, err := ParseWithConfig(&ParseConfig{
+		Path:    "test-fixtures/indexed.hcl",
+		Body:    hclBytes,
+		AllowFS: false,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, &api.IndexedPolicy{
+		Parallelism: pointerOf(4),
+		MaxFailures: pointerOf(5),
+	}, job.TaskGroups[0].Indexed)
+}
+
+func TestParse_PriorityClass(t *testing.T) {
+	t.Parallel()
+
+	hclBytes, err := os.ReadFile("test-fixtures/priority-class.hcl")
+	require.NoError(t, err)
+	job, err := ParseWithConfig(&ParseConfig{
+		Path:    "test-fixtures/priority-class.hcl",
+		Body:    hclBytes,
+		AllowFS: false,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, "critical", *job.PriorityClass)
+}
+
+func TestParse_CanaryAnalysis(t *testing.T) {
+	t.Parallel()
+
+	hclBytes, err := os.ReadFile("test-fixtures/canary-analysis.hcl")
+	require.NoError(t, err)
+	job, err := ParseWithConfig(&ParseConfig{
+		Path:    "test-fixtures/canary-analysis.hcl",
+		Body:    hclBytes,
+		AllowFS: false,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, &api.CanaryAnalysis{
+		Window:              pointerOf(10 * time.Minute),
+		Interval:            pointerOf(time.Minute),
+		MaxCheckFailureRate: pointerOf(0.05),
+		MaxRestarts:         pointerOf(1),
+		MaxMemoryIncrease:   pointerOf(25),
+	}, job.TaskGroups[0].Update.Analysis)
+}
+
+func TestParse_BlueGreen(t *testing.T) {
+	t.Parallel()
+
+	hclBytes, err := os.ReadFile("test-fixtures/blue-green.hcl")
+	require.NoError(t, err)
+	job, err := ParseWithConfig(&ParseConfig{
+		Path:    "test-fixtures/blue-green.hcl",
+		Body:    hclBytes,
+		AllowFS: false,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, &api.BlueGreen{
+		Retention: pointerOf(30 * time.Minute),
+	}, job.TaskGroups[0].Update.BlueGreen)
+}
+
+func TestParse_DeploymentHooks(t *testing.T) {
+	t.Parallel()
+
+	hclBytes, err := os.ReadFile("test-fixtures/deployment-hooks.hcl")
+	require.NoError(t, err)
+	job, err := ParseWithConfig(&ParseConfig{
+		Path:    "test-fixtures/deployment-hooks.hcl",
+		Body:    hclBytes,
+		AllowFS: false,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, &api.DeploymentHook{
+		Job:  pointerOf("migrate-db"),
+		Meta: map[string]string{"direction": "up"},
+	}, job.TaskGroups[0].Update.PreDeploy)
+	require.Equal(t, &api.DeploymentHook{
+		Job: pointerOf("smoke-test"),
+	}, job.TaskGroups[0].Update.PostDeploy)
+}
+
+func TestParse_PeriodicCatchup(t *testing.T) {
+	t.Parallel()
+
+	hclBytes, err := os.ReadFile("test-fixtures/periodic-catchup.hcl")
+	require.NoError(t, err)
+	job, err := ParseWithConfig(&ParseConfig{
+		Path:    "test-fixtures/periodic-catchup.hcl",
+		Body:    hclBytes,
+		AllowFS: false,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, "all", *job.Periodic.Catchup)
+	require.Equal(t, 5, *job.Periodic.CatchupMaxCount)
+	require.Equal(t, 6*time.Hour, *job.Periodic.CatchupMaxAge)
+}
+
+func TestParse_JobConcurrency(t *testing.T) {
+	t.Parallel()
+
+	hclBytes, err := os.ReadFile("test-fixtures/job-concurrency.hcl")
+	require.NoError(t, err)
+	job, err := ParseWithConfig(&ParseConfig{
+		Path:    "test-fixtures/job-concurrency.hcl",
+		Body:    hclBytes,
+		AllowFS: false,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, "replace", *job.Periodic.Concurrency)
+	require.Equal(t, &api.ParameterizedJobConfig{
+		Concurrency:   "queue",
+		MaxConcurrent: 3,
+	}, job.ParameterizedJob)
+}
+
+func TestParse_DispatchQueue(t *testing.T) {
+	t.Parallel()
+
+	hclBytes, err := os.ReadFile("test-fixtures/dispatch-queue.hcl")
+	require.NoError(t, err)
+	job, err := ParseWithConfig(&ParseConfig{
+		Path:    "test-fixtures/dispatch-queue.hcl",
+		Body:    hclBytes,
+		AllowFS: false,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, &api.DispatchQueueConfig{
+		MaxRunning: 20,
+		Rate:       5.5,
+		Burst:      10,
+	}, job.ParameterizedJob.DispatchQueue)
+}
+
+func TestParse_RestartDelayFunction(t *testing.T) {
+	t.Parallel()
+
+	hclBytes, err := os.ReadFile("test-fixtures/restart-delay-function.hcl")
+	require.NoError(t, err)
+	job, err := ParseWithConfig(&ParseConfig{
+		Path:    "test-fixtures/restart-delay-function.hcl",
+		Body:    hclBytes,
+		AllowFS: false,
+	})
+	require.NoError(t, err)
+
+	tg := job.TaskGroups[0]
+	require.Equal(t, "exponential", *tg.RestartPolicy.DelayFunction)
+	require.Equal(t, 2*time.Minute, *tg.RestartPolicy.MaxDelay)
+	require.Equal(t, 0.5, *tg.RestartPolicy.Jitter)
+	require.Equal(t, "fibonacci", *tg.Tasks[0].RestartPolicy.DelayFunction)
+}
+
+func TestParse_ExitRules(t *testing.T) {
+	t.Parallel()
+
+	hclBytes, err := os.ReadFile("test-fixtures/exit-rules.hcl")
+	require.NoError(t, err)
+	job, err := ParseWithConfig(&ParseConfig{
+		Path:    "test-fixtures/exit-rules.hcl",
+		Body:    hclBytes,
+		AllowFS: false,
+	})
+	require.NoError(t, err)
+
+	tg := job.TaskGroups[0]
+	require.Equal(t, []*api.ExitRule{
+		{ExitCodes: []int{0, 3}, Action: "complete"},
+		{ExitCodes: []int{75}, Signals: []string{"SIGKILL"}, Action: "restart"},
+	}, tg.RestartPolicy.ExitRules)
+	require.Equal(t, []*api.ExitRule{
+		{ExitCodes: []int{1}, Action: "fail"},
+	}, tg.ReschedulePolicy.ExitRules)
+}
+
+func TestParse_TaskDependsOn(t *testing.T) {
+	t.Parallel()
+
+	hclBytes, err := os.ReadFile("test-fixtures/task-depends-on.hcl")
+	require.NoError(t, err)
+	job, err := ParseWithConfig(&ParseConfig{
+		Path:    "test-fixtures/task-depends-on.hcl",
+		Body:    hclBytes,
+		AllowFS: false,
+	})
+	require.NoError(t, err)
+
+	tasks := job.TaskGroups[0].Tasks
+	require.Len(t, tasks, 3)
+	require.Nil(t, tasks[0].DependsOn)
+	require.Equal(t, []*api.TaskDependency{
+		{Task: "db", Condition: "healthy"},
+	}, tasks[1].DependsOn)
+	require.Equal(t, []*api.TaskDependency{
+		{Task: "db"},
+		{Task: "migrate", Condition: "completed"},
+	}, tasks[2].DependsOn)
+}
+
+func TestParse_MaxRunDuration(t *testing.T) {
+	t.Parallel()
+
+	hclBytes, err := os.ReadFile("test-fixtures/max-run-duration.hcl")
+	require.NoError(t, err)
+	job, err := ParseWithConfig(&ParseConfig{
+		Path:    "test-fixtures/max-run-duration.hcl",
+		Body:    hclBytes,
+		AllowFS: false,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, 6*time.Hour, *job.Deadline)
+
+	tg := job.TaskGroups[0]
+	require.Equal(t, 2*time.Hour, *tg.MaxRunDuration)
+	require.Zero(t, tg.Tasks[0].MaxRunDuration)
+	require.Equal(t, 30*time.Minute, tg.Tasks[1].MaxRunDuration)
+}
+
+func TestParse_NetworkPolicy(t *testing.T) {
+	t.Parallel()
+
+	hclBytes, err := os.ReadFile("test-fixtures/network-policy.hcl")
+	require.NoError(t, err)
+	job, err := ParseWithConfig(&ParseConfig{
+		Path:    "test-fixtures/network-policy.hcl",
+		Body:    hclBytes,
+		AllowFS: false,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, &api.NetworkPolicy{
+		Default: "deny",
+		Allow: []*api.NetworkPolicyRule{
+			{Job: "api"},
+			{Namespace: "*", Service: "prometheus"},
+		},
+	}, job.TaskGroups[0].NetworkPolicy)
+}
+
+func TestParse_LogSinks(t *testing.T) {
+	t.Parallel()
+
+	hclBytes, err := os.ReadFile("test-fixtures/log-sinks.hcl")
+	require.NoError(t, err)
+	job, err := ParseWithConfig(&ParseConfig{
+		Path:    "test-fixtures/log-sinks.hcl",
+		Body:    hclBytes,
+		AllowFS: false,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, []*api.LogSink{
+		{
+			Type:    "syslog",
+			Address: "udp://127.0.0.1:514",
+		},
+		{
+			Type:          "http",
+			Address:       "https://logs.example.com/ingest",
+			BufferSize:    pointerOf(4096),
+			RetryAttempts: pointerOf(5),
+			RetryInterval: pointerOf(5 * time.Second),
+			DropPolicy:    "newest",
+		},
+	}, job.TaskGroups[0].Tasks[0].LogConfig.Sinks)
+}
+
+func TestParse_LogRetention(t *testing.T) {
+	t.Parallel()
+
+	hclBytes, err := os.ReadFile("test-fixtures/log-retention.hcl")
+	require.NoError(t, err)
+	job, err := ParseWithConfig(&ParseConfig{
+		Path:    "test-fixtures/log-retention.hcl",
+		Body:    hclBytes,
+		AllowFS: false,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, &api.LogConfig{
+		MaxFiles:       pointerOf(20),
+		MaxFileSizeMB:  pointerOf(10),
+		MaxTotalSizeMB: pointerOf(50),
+		MaxAge:         pointerOf(72 * time.Hour),
+		Compress:       "zstd",
+	}, job.TaskGroups[0].Tasks[0].LogConfig)
+}
@@ -0,0 +1,24 @@
+# Copyright (c) HashiCorp, Inc.
+# SPDX-License-Identifier: MPL-2.0
+
+job "web" {
+  group "frontend" {
+    count = 3
+
+    update {
+      auto_revert = true
+
+      blue_green {
+        retention = "30m"
+      }
+    }
+
+    task "server" {
+      driver = "docker"
+
+      config {
+        image = "busybox:1"
+      }
+    }
+  }
+}
@@ -0,0 +1,28 @@
+# Copyright (c) HashiCorp, Inc.
+# SPDX-License-Identifier: MPL-2.0
+
+job "web" {
+  group "frontend" {
+    update {
+      canary       = 1
+      auto_promote = true
+      auto_revert  = true
+
+      analysis {
+        window                 = "10m"
+        interval               = "1m"
+        max_check_failure_rate = 0.05
+        max_restarts           = 1
+        max_memory_increase    = 25
+      }
+    }
+
+    task "server" {
+      driver = "docker"
+
+      config {
+        image = "busybox:1"
+      }
+    }
+  }
+}
@@ -0,0 +1,22 @@
+# Copyright (c) HashiCorp, Inc.
+# SPDX-License-Identifier: MPL-2.0
+
+job "report" {
+  type = "batch"
+
+  depends_on "extract" {}
+
+  depends_on "cleanup" {
+    condition = "failed"
+  }
+
+  group "report" {
+    task "report" {
+      driver = "docker"
+
+      config {
+        image = "busybox:1"
+      }
+    }
+  }
+}
@@ -0,0 +1,30 @@
+# Copyright (c) HashiCorp, Inc.
+# SPDX-License-Identifier: MPL-2.0
+
+job "web" {
+  group "frontend" {
+    count = 3
+
+    update {
+      pre_deploy {
+        job = "migrate-db"
+
+        meta {
+          direction = "up"
+        }
+      }
+
+      post_deploy {
+        job = "smoke-test"
+      }
+    }
+
+    task "server" {
+      driver = "docker"
+
+      config {
+        image = "busybox:1"
+      }
+    }
+  }
+}
@@ -0,0 +1,26 @@
+# Copyright (c) HashiCorp, Inc.
+# SPDX-License-Identifier: MPL-2.0
+
+job "ingest" {
+  type = "batch"
+
+  parameterized {
+    meta_required = ["event_id"]
+
+    queue {
+      max_running = 20
+      rate        = 5.5
+      burst       = 10
+    }
+  }
+
+  group "ingest" {
+    task "process" {
+      driver = "docker"
+
+      config {
+        image = "busybox:1"
+      }
+    }
+  }
+}
@@ -0,0 +1,32 @@
+# Copyright (c) HashiCorp, Inc.
+# SPDX-License-Identifier: MPL-2.0
+
+job "example" {
+  group "group" {
+    restart {
+      attempts = 3
+
+      on_exit {
+        exit_codes = [0, 3]
+        action     = "complete"
+      }
+
+      on_exit {
+        exit_codes = [75]
+        signals    = ["SIGKILL"]
+        action     = "restart"
+      }
+    }
+
+    reschedule {
+      on_exit {
+        exit_codes = [1]
+        action     = "fail"
+      }
+    }
+
+    task "task" {
+      driver = "docker"
+    }
+  }
+}
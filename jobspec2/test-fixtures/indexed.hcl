@@ -0,0 +1,23 @@
+# Copyright (c) HashiCorp, Inc.
+# SPDX-License-Identifier: MPL-2.0
+
+job "shards" {
+  type = "batch"
+
+  group "shards" {
+    count = 16
+
+    indexed {
+      parallelism  = 4
+      max_failures = 5
+    }
+
+    task "process" {
+      driver = "docker"
+
+      config {
+        image = "busybox:1"
+      }
+    }
+  }
+}
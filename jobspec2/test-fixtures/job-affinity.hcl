@@ -0,0 +1,33 @@
+# Copyright (c) HashiCorp, Inc.
+# SPDX-License-Identifier: MPL-2.0
+
+job "web" {
+  job_affinity {
+    job_id   = "batch-reports"
+    anti     = true
+    required = true
+  }
+
+  group "web" {
+    job_affinity {
+      job_id = "cache"
+      group  = "redis"
+      weight = 80
+    }
+
+    job_affinity {
+      meta = {
+        tier = "storage"
+      }
+      anti = true
+    }
+
+    task "web" {
+      driver = "docker"
+
+      config {
+        image = "busybox:1"
+      }
+    }
+  }
+}
@@ -0,0 +1,26 @@
+# Copyright (c) HashiCorp, Inc.
+# SPDX-License-Identifier: MPL-2.0
+
+job "backup" {
+  type = "batch"
+
+  periodic {
+    crons       = ["@hourly"]
+    concurrency = "replace"
+  }
+
+  parameterized {
+    concurrency    = "queue"
+    max_concurrent = 3
+  }
+
+  group "backup" {
+    task "snapshot" {
+      driver = "docker"
+
+      config {
+        image = "busybox:1"
+      }
+    }
+  }
+}
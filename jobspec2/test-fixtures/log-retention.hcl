@@ -0,0 +1,18 @@
+# Copyright (c) HashiCorp, Inc.
+# SPDX-License-Identifier: MPL-2.0
+
+job "example" {
+  group "web" {
+    task "web" {
+      driver = "docker"
+
+      logs {
+        max_files      = 20
+        max_file_size  = 10
+        max_total_size = 50
+        max_age        = "72h"
+        compress       = "zstd"
+      }
+    }
+  }
+}
@@ -0,0 +1,26 @@
+# Copyright (c) HashiCorp, Inc.
+# SPDX-License-Identifier: MPL-2.0
+
+job "example" {
+  group "web" {
+    task "web" {
+      driver = "docker"
+
+      logs {
+        sink {
+          type    = "syslog"
+          address = "udp://127.0.0.1:514"
+        }
+
+        sink {
+          type           = "http"
+          address        = "https://logs.example.com/ingest"
+          buffer_size    = 4096
+          retry_attempts = 5
+          retry_interval = "5s"
+          drop_policy    = "newest"
+        }
+      }
+    }
+  }
+}
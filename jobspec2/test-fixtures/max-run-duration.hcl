@@ -0,0 +1,20 @@
+# Copyright (c) HashiCorp, Inc.
+# SPDX-License-Identifier: MPL-2.0
+
+job "example" {
+  type     = "batch"
+  deadline = "6h"
+
+  group "group" {
+    max_run_duration = "2h"
+
+    task "extract" {
+      driver = "docker"
+    }
+
+    task "load" {
+      driver           = "docker"
+      max_run_duration = "30m"
+    }
+  }
+}
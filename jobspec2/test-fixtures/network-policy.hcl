@@ -0,0 +1,27 @@
+# Copyright (c) HashiCorp, Inc.
+# SPDX-License-Identifier: MPL-2.0
+
+job "example" {
+  group "web" {
+    network {
+      mode = "bridge"
+    }
+
+    network_policy {
+      default = "deny"
+
+      allow {
+        job = "api"
+      }
+
+      allow {
+        namespace = "*"
+        service   = "prometheus"
+      }
+    }
+
+    task "web" {
+      driver = "docker"
+    }
+  }
+}
@@ -0,0 +1,23 @@
+# Copyright (c) HashiCorp, Inc.
+# SPDX-License-Identifier: MPL-2.0
+
+job "report" {
+  type = "batch"
+
+  periodic {
+    crons             = ["0 * * * *"]
+    catchup           = "all"
+    catchup_max_count = 5
+    catchup_max_age   = "6h"
+  }
+
+  group "report" {
+    task "generate" {
+      driver = "docker"
+
+      config {
+        image = "busybox:1"
+      }
+    }
+  }
+}
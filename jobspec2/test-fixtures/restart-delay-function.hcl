@@ -0,0 +1,23 @@
+# Copyright (c) HashiCorp, Inc.
+# SPDX-License-Identifier: MPL-2.0
+
+job "example" {
+  group "group" {
+    restart {
+      attempts       = 5
+      interval       = "10m"
+      delay          = "5s"
+      delay_function = "exponential"
+      max_delay      = "2m"
+      jitter         = 0.5
+    }
+
+    task "task" {
+      driver = "docker"
+
+      restart {
+        delay_function = "fibonacci"
+      }
+    }
+  }
+}
@@ -0,0 +1,28 @@
+# Copyright (c) HashiCorp, Inc.
+# SPDX-License-Identifier: MPL-2.0
+
+job "example" {
+  group "group" {
+    task "db" {
+      driver = "docker"
+    }
+
+    task "migrate" {
+      driver = "docker"
+
+      depends_on "db" {
+        condition = "healthy"
+      }
+    }
+
+    task "api" {
+      driver = "docker"
+
+      depends_on "db" {}
+
+      depends_on "migrate" {
+        condition = "completed"
+      }
+    }
+  }
+}
@@ -0,0 +1,25 @@
+# Copyright (c) HashiCorp, Inc.
+# SPDX-License-Identifier: MPL-2.0
+
+job "web" {
+  topology_spread {
+    attribute = "${node.datacenter}"
+  }
+
+  group "web" {
+    count = 6
+
+    topology_spread {
+      attribute = "${meta.rack}"
+      max_skew  = 2
+    }
+
+    task "web" {
+      driver = "docker"
+
+      config {
+        image = "busybox:1"
+      }
+    }
+  }
+}
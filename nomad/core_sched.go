@@ -7,11 +7,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 	"time"
 
 	log "github.com/hashicorp/go-hclog"
 	memdb "github.com/hashicorp/go-memdb"
+	metrics "github.com/hashicorp/go-metrics/compat"
 	version "github.com/hashicorp/go-version"
 	"github.com/hashicorp/nomad/helper/pointer"
 	"github.com/hashicorp/nomad/helper/uuid"
@@ -77,6 +80,8 @@ func (c *CoreScheduler) Process(eval *structs.Evaluation) error {
 		return c.rootKeyRotateOrGC(eval)
 	case structs.CoreJobVariablesRekey:
 		return c.variablesRekey(eval)
+	case structs.CoreJobDeschedule:
+		return c.deschedule(eval)
 	case structs.CoreJobForceGC:
 		return c.forceGC(eval)
 	default:
@@ -709,7 +714,7 @@ func allocGCEligible(a *structs.Allocation, job *structs.Job, gcTime, cutoffTime
 	tg := job.LookupTaskGroup(a.TaskGroup)
 
 	if tg != nil {
-		reschedulePolicy = tg.ReschedulePolicy
+		reschedulePolicy = tg.EffectiveReschedulePolicy()
 	}
 	// No reschedule policy or rescheduling is disabled
 	if reschedulePolicy == nil || (!reschedulePolicy.Unlimited && reschedulePolicy.Attempts == 0) {
@@ -1356,6 +1361,84 @@ func (c *CoreScheduler) rotateVariables(iter memdb.ResultIterator, eval *structs
 	return nil
 }
 
+// deschedule is used to migrate allocations away from the nodes where they
+// make the cluster unbalanced. In dry-run mode the allocations are only
+// reported.
+func (c *CoreScheduler) deschedule(eval *structs.Evaluation) error {
+	_, schedConfig, err := c.snap.SchedulerConfig()
+	if err != nil {
+		return err
+	}
+	if schedConfig == nil || !schedConfig.Descheduler.Enabled {
+		return nil
+	}
+
+	candidates, err := scheduler.NewDescheduler(c.logger, c.snap, schedConfig).Candidates()
+	if err != nil {
+		return err
+	}
+
+	// Fast-path the nothing case
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	dryRun := schedConfig.Descheduler.DryRun
+	for _, candidate := range candidates {
+		alloc := candidate.Alloc
+		c.logger.Info("descheduling allocation", "alloc_id", alloc.ID, "job_id", alloc.JobID,
+			"namespace", alloc.Namespace, "node_id", alloc.NodeID, "reason", candidate.Reason, "dry_run", dryRun)
+		metrics.IncrCounterWithLabels([]string{"nomad", "descheduler", "allocs"}, 1, []metrics.Label{
+			{Name: "reason", Value: candidate.Reason},
+			{Name: "dry_run", Value: fmt.Sprintf("%v", dryRun)},
+		})
+	}
+	if dryRun {
+		return nil
+	}
+
+	// Mark the allocations for migration and create an evaluation for each
+	// of their jobs, as the drainer does
+	now := time.Now().UTC().UnixNano()
+	transitions := make(map[string]*structs.DesiredTransition, len(candidates))
+	evals := make(map[structs.NamespacedID]*structs.Evaluation)
+	for _, candidate := range candidates {
+		alloc := candidate.Alloc
+		transitions[alloc.ID] = &structs.DesiredTransition{Migrate: pointer.Of(true)}
+
+		id := structs.NamespacedID{Namespace: alloc.Namespace, ID: alloc.JobID}
+		if _, ok := evals[id]; ok {
+			continue
+		}
+		evals[id] = &structs.Evaluation{
+			ID:          uuid.Generate(),
+			Namespace:   alloc.Namespace,
+			Priority:    candidate.Job.Priority,
+			Type:        candidate.Job.Type,
+			TriggeredBy: structs.EvalTriggerDeschedule,
+			JobID:       alloc.JobID,
+			Status:      structs.EvalStatusPending,
+			CreateTime:  now,
+			ModifyTime:  now,
+		}
+	}
+
+	req := structs.AllocUpdateDesiredTransitionRequest{
+		Allocs: transitions,
+		Evals:  slices.Collect(maps.Values(evals)),
+		WriteRequest: structs.WriteRequest{
+			Region:    c.srv.config.Region,
+			AuthToken: eval.LeaderACL,
+		},
+	}
+	var resp structs.GenericResponse
+	if err := c.srv.RPC("Alloc.UpdateDesiredTransition", &req, &resp); err != nil {
+		c.logger.Error("descheduling allocations failed", "error", err)
+		return err
+	}
+	return nil
+}
+
 // getCutoffTime returns a time.Time of the latest object that should be GCd
 func (c *CoreScheduler) getCutoffTime(configThreshold time.Duration) time.Time {
 	return time.Now().UTC().Add(-1 * configThreshold)
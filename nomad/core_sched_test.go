@@ -3066,3 +3066,58 @@ func TestCoreScheduler_ExpiredACLTokenGC_Force(t *testing.T) {
 	tokens = fromIteratorFunc(iter)
 	must.SliceContainsAll(t, append(nonExpiredGlobalTokens, nonExpiredLocalTokens...), tokens)
 }
+
+func TestCoreScheduler_Deschedule(t *testing.T) {
+	ci.Parallel(t)
+
+	for _, dryRun := range []bool{false, true} {
+		t.Run(fmt.Sprintf("dry run %v", dryRun), func(t *testing.T) {
+			s1, cleanupS1 := TestServer(t, func(c *Config) {
+				c.NumSchedulers = 0 // Prevent automatic dequeue
+			})
+			defer cleanupS1()
+			testutil.WaitForLeader(t, s1.RPC)
+
+			store := s1.fsm.State()
+			must.NoError(t, store.SchedulerSetConfig(1000, &structs.SchedulerConfiguration{
+				Descheduler: structs.DeschedulerConfig{
+					Enabled: true,
+					DryRun:  dryRun,
+				},
+			}))
+
+			node := mock.Node()
+			must.NoError(t, store.UpsertNode(structs.MsgTypeTestSetup, 1001, node))
+
+			// A single allocation using most of the node's memory
+			job := mock.Job()
+			must.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 1002, nil, job))
+			alloc := mock.Alloc()
+			alloc.Job = job
+			alloc.JobID = job.ID
+			alloc.NodeID = node.ID
+			alloc.ClientStatus = structs.AllocClientStatusRunning
+			alloc.AllocatedResources.Tasks["web"].Memory.MemoryMB = 7000
+			must.NoError(t, store.UpsertAllocs(structs.MsgTypeTestSetup, 1003, []*structs.Allocation{alloc}))
+
+			snap, err := store.Snapshot()
+			must.NoError(t, err)
+			core := NewCoreScheduler(s1, snap)
+			must.NoError(t, core.Process(s1.coreJobEval(structs.CoreJobDeschedule, 1004)))
+
+			out, err := store.AllocByID(nil, alloc.ID)
+			must.NoError(t, err)
+			evals, err := store.EvalsByJob(nil, job.Namespace, job.ID)
+			must.NoError(t, err)
+
+			if dryRun {
+				must.False(t, out.DesiredTransition.ShouldMigrate())
+				must.Len(t, 0, evals)
+				return
+			}
+			must.True(t, out.DesiredTransition.ShouldMigrate())
+			must.Len(t, 1, evals)
+			must.Eq(t, structs.EvalTriggerDeschedule, evals[0].TriggeredBy)
+		})
+	}
+}
@@ -4,6 +4,7 @@
 package nomad
 
 import (
+	cstructs "github.com/hashicorp/nomad/client/structs"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
 
@@ -57,3 +58,41 @@ func (d *deploymentWatcherRaftShim) UpdateAllocDesiredTransition(req *structs.Al
 	fsmErrIntf, index, raftErr := d.apply(structs.AllocUpdateDesiredTransitionRequestType, req)
 	return d.convertApplyErrors(fsmErrIntf, index, raftErr)
 }
+
+// deploymentWatcherAllocShim is the shim that provides the methods used by
+// the deployment watcher to query the clients running allocations. Requests
+// are made with the leader's ACL token.
+type deploymentWatcherAllocShim struct {
+	srv *Server
+}
+
+func (d *deploymentWatcherAllocShim) Stats(args *cstructs.AllocStatsRequest, reply *cstructs.AllocStatsResponse) error {
+	args.Region = d.srv.config.Region
+	args.AuthToken = d.srv.getLeaderAcl()
+	return NewClientAllocationsEndpoint(d.srv).Stats(args, reply)
+}
+
+func (d *deploymentWatcherAllocShim) Checks(args *cstructs.AllocChecksRequest, reply *cstructs.AllocChecksResponse) error {
+	args.Region = d.srv.config.Region
+	args.AuthToken = d.srv.getLeaderAcl()
+	return NewClientAllocationsEndpoint(d.srv).Checks(args, reply)
+}
+
+// deploymentWatcherHookShim is the shim that provides the methods used by the
+// deployment watcher to run the jobs of deployment hooks. Requests are made
+// with the leader's ACL token.
+type deploymentWatcherHookShim struct {
+	srv *Server
+}
+
+func (d *deploymentWatcherHookShim) Dispatch(args *structs.JobDispatchRequest, reply *structs.JobDispatchResponse) error {
+	args.Region = d.srv.config.Region
+	args.AuthToken = d.srv.getLeaderAcl()
+	return NewJobEndpoints(d.srv, nil).Dispatch(args, reply)
+}
+
+func (d *deploymentWatcherHookShim) Register(args *structs.JobRegisterRequest, reply *structs.JobRegisterResponse) error {
+	args.Region = d.srv.config.Region
+	args.AuthToken = d.srv.getLeaderAcl()
+	return NewJobEndpoints(d.srv, nil).Register(args, reply)
+}
@@ -0,0 +1,460 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package deploymentwatcher
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// maxStableAnalysisAllocs is the maximum number of stable allocations
+	// the canaries of a task group are compared with, to bound the number
+	// of client RPCs made for every sample.
+	maxStableAnalysisAllocs = 10
+
+	// canarySampleTimeout is how long the clients running the canaries and
+	// stable allocations have to answer when they are sampled. Signals
+	// sampled from the clients are skipped for the sample if they don't
+	// answer in time.
+	canarySampleTimeout = 30 * time.Second
+)
+
+// canaryClientSample is the sample of the signals of a task group that are
+// queried from the clients running its allocations.
+type canaryClientSample struct {
+	// checks is set if the canaries had check results
+	checks       bool
+	canaryChecks float64
+	stableChecks float64
+
+	// usage is set if the usage of both the canaries and the stable
+	// allocations could be queried
+	usage        bool
+	canaryCPU    float64
+	canaryMemory float64
+	stableCPU    float64
+	stableMemory float64
+}
+
+// canaryAnalysis returns the canary analysis of the task group, or nil if
+// its canaries aren't analyzed.
+func (w *deploymentWatcher) canaryAnalysis(group string) *structs.CanaryAnalysis {
+	tg := w.j.LookupTaskGroup(group)
+	if tg == nil || tg.Update == nil {
+		return nil
+	}
+	return tg.Update.Analysis
+}
+
+// canariesHealthy returns whether all the desired canaries of the task group
+// are placed and healthy.
+func canariesHealthy(dstate *structs.DeploymentState, allocs []*structs.AllocListStub) bool {
+	if len(dstate.PlacedCanaries) < dstate.DesiredCanaries {
+		return false
+	}
+
+	healthyCanaries := 0
+	for _, c := range dstate.PlacedCanaries {
+		for _, a := range allocs {
+			if c == a.ID && a.DeploymentStatus.IsHealthy() {
+				healthyCanaries += 1
+			}
+		}
+	}
+	return healthyCanaries == dstate.DesiredCanaries
+}
+
+// startCanaryAnalysis starts the analysis of the canaries of each task group
+// whose canaries are all healthy, and returns the interval at which the
+// canaries being analyzed should be sampled. A zero interval means that no
+// canaries are being analyzed.
+func (w *deploymentWatcher) startCanaryAnalysis(updates *allocUpdates) time.Duration {
+	if updates == nil {
+		return 0
+	}
+
+	// Read the deployment from the state store since the analysis of a task
+	// group may have just been started or completed
+	snap, err := w.state.Snapshot()
+	if err != nil {
+		w.logger.Error("failed to start canary analysis", "error", err)
+		return 0
+	}
+	d, err := snap.DeploymentByID(nil, w.deploymentID)
+	if err != nil || d == nil || !d.Active() || !d.RequiresPromotion() {
+		return 0
+	}
+
+	var interval time.Duration
+	started := make(map[string]*structs.CanaryAnalysisResult)
+	for tg, dstate := range d.TaskGroups {
+		analysis := w.canaryAnalysis(tg)
+		if analysis == nil || dstate.Promoted || dstate.DesiredCanaries < 1 {
+			continue
+		}
+
+		if dstate.CanaryAnalysis == nil {
+			if !canariesHealthy(dstate, updates.allocs) {
+				continue
+			}
+			started[tg] = &structs.CanaryAnalysisResult{
+				Status:            structs.CanaryAnalysisStatusRunning,
+				StatusDescription: "Canaries are being analyzed",
+				StartedAt:         time.Now(),
+			}
+		} else if !dstate.CanaryAnalysis.Running() {
+			continue
+		}
+
+		if interval == 0 || analysis.Interval < interval {
+			interval = analysis.Interval
+		}
+	}
+
+	if len(started) != 0 {
+		_, err := w.upsertDeploymentStatusUpdate(&structs.DeploymentStatusUpdate{
+			DeploymentID:   w.deploymentID,
+			CanaryAnalysis: started,
+			UpdatedAt:      time.Now().UnixNano(),
+		}, nil, nil)
+		if err != nil {
+			w.logger.Error("failed to start canary analysis", "error", err)
+			return 0
+		}
+	}
+
+	return interval
+}
+
+// sampleCanaryClients samples the clients running the canaries being analyzed
+// in the background, so the watch loop isn't blocked by slow clients. The
+// samples are sent by task group on the returned channel, or nil if the
+// clients didn't answer within canarySampleTimeout. Nothing is sent once the
+// watcher is cancelled.
+func (w *deploymentWatcher) sampleCanaryClients() <-chan map[string]*canaryClientSample {
+	ch := make(chan map[string]*canaryClientSample, 1)
+	if w.allocRPC == nil {
+		ch <- nil
+		return ch
+	}
+
+	go func() {
+		// The client RPCs can't be cancelled, so a late answer is dropped
+		done := make(chan map[string]*canaryClientSample, 1)
+		go func() {
+			done <- w.queryCanaryClients()
+		}()
+
+		timer, stop := helper.NewSafeTimer(canarySampleTimeout)
+		defer stop()
+
+		select {
+		case samples := <-done:
+			ch <- samples
+		case <-timer.C:
+			w.logger.Warn("timed out sampling canaries", "timeout", canarySampleTimeout)
+			ch <- nil
+		case <-w.ctx.Done():
+		}
+	}()
+	return ch
+}
+
+// queryCanaryClients queries the clients running the canaries of each task
+// group being analyzed and the stable allocations they're compared with.
+func (w *deploymentWatcher) queryCanaryClients() map[string]*canaryClientSample {
+	snap, err := w.state.Snapshot()
+	if err != nil {
+		w.logger.Error("failed to sample canaries", "error", err)
+		return nil
+	}
+	d, err := snap.DeploymentByID(nil, w.deploymentID)
+	if err != nil || d == nil {
+		return nil
+	}
+	allocs, err := snap.AllocsByJob(nil, d.Namespace, d.JobID, false)
+	if err != nil {
+		w.logger.Error("failed to sample canaries", "error", err)
+		return nil
+	}
+
+	samples := make(map[string]*canaryClientSample)
+	for tg, dstate := range d.TaskGroups {
+		analysis := w.canaryAnalysis(tg)
+		if analysis == nil || dstate.Promoted || !dstate.CanaryAnalysis.Running() {
+			continue
+		}
+
+		canaries, stable := canaryAnalysisAllocs(d, tg, dstate, allocs)
+		sample := &canaryClientSample{}
+		sample.canaryChecks, sample.checks = w.checkFailureRate(canaries)
+		if sample.checks {
+			sample.stableChecks, _ = w.checkFailureRate(stable)
+		}
+
+		if analysis.MaxCPUIncrease > 0 || analysis.MaxMemoryIncrease > 0 {
+			canaryCPU, canaryMemory, canaryOK := w.averageUsage(canaries)
+			stableCPU, stableMemory, stableOK := w.averageUsage(stable)
+			sample.usage = canaryOK && stableOK
+			sample.canaryCPU, sample.canaryMemory = canaryCPU, canaryMemory
+			sample.stableCPU, sample.stableMemory = stableCPU, stableMemory
+		}
+		samples[tg] = sample
+	}
+	return samples
+}
+
+// analyzeCanaries samples the canaries of each task group being analyzed
+// and compares them with the stable allocations, using the samples queried
+// from the clients if any. Once the window of the analysis is over the
+// analysis passes, or fails if any signal exceeded its threshold. It returns
+// the deployment with the updated analysis results, whether the deployment
+// should fail and whether it should be rolled back.
+func (w *deploymentWatcher) analyzeCanaries(samples map[string]*canaryClientSample) (*structs.Deployment, bool, bool, error) {
+	snap, err := w.state.Snapshot()
+	if err != nil {
+		return nil, false, false, err
+	}
+	d, err := snap.DeploymentByID(nil, w.deploymentID)
+	if err != nil {
+		return nil, false, false, err
+	}
+	if d == nil || d.Status != structs.DeploymentStatusRunning {
+		return nil, false, false, nil
+	}
+
+	allocs, err := snap.AllocsByJob(nil, d.Namespace, d.JobID, false)
+	if err != nil {
+		return nil, false, false, err
+	}
+
+	now := time.Now()
+	fail, rollback := false, false
+	results := make(map[string]*structs.CanaryAnalysisResult)
+	for tg, dstate := range d.TaskGroups {
+		analysis := w.canaryAnalysis(tg)
+		if analysis == nil || dstate.Promoted || !dstate.CanaryAnalysis.Running() {
+			continue
+		}
+
+		result := dstate.CanaryAnalysis.Copy()
+		canaries, stable := canaryAnalysisAllocs(d, tg, dstate, allocs)
+		sampleCanaries(analysis, result, canaries, stable, samples[tg])
+
+		if now.Sub(result.StartedAt) >= analysis.Window {
+			if failed := result.FailedSignals(); len(failed) != 0 {
+				result.Status = structs.CanaryAnalysisStatusFailed
+				result.StatusDescription = fmt.Sprintf("Canaries exceeded the threshold of %s", strings.Join(failed, ", "))
+				fail = true
+				rollback = rollback || dstate.AutoRevert
+			} else {
+				result.Status = structs.CanaryAnalysisStatusPassed
+				result.StatusDescription = "Canaries passed the analysis"
+			}
+		}
+		results[tg] = result
+	}
+
+	if len(results) == 0 {
+		return d, false, false, nil
+	}
+
+	_, err = w.upsertDeploymentStatusUpdate(&structs.DeploymentStatusUpdate{
+		DeploymentID:   w.deploymentID,
+		CanaryAnalysis: results,
+		UpdatedAt:      now.UnixNano(),
+	}, nil, nil)
+	if err != nil {
+		return nil, false, false, err
+	}
+
+	d = d.Copy()
+	for tg, result := range results {
+		d.TaskGroups[tg].CanaryAnalysis = result
+	}
+	return d, fail, rollback, nil
+}
+
+// canaryAnalysisAllocs returns the running canaries of the task group, and
+// the running allocations of the task group that aren't part of the
+// deployment.
+func canaryAnalysisAllocs(d *structs.Deployment, tg string, dstate *structs.DeploymentState,
+	allocs []*structs.Allocation) (canaries, stable []*structs.Allocation) {
+
+	for _, alloc := range allocs {
+		if alloc.TaskGroup != tg || alloc.ClientStatus != structs.AllocClientStatusRunning ||
+			alloc.ServerTerminalStatus() {
+			continue
+		}
+		if slices.Contains(dstate.PlacedCanaries, alloc.ID) {
+			canaries = append(canaries, alloc)
+		} else if alloc.DeploymentID != d.ID {
+			stable = append(stable, alloc)
+		}
+	}
+
+	slices.SortFunc(stable, func(a, b *structs.Allocation) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+	if len(stable) > maxStableAnalysisAllocs {
+		stable = stable[:maxStableAnalysisAllocs]
+	}
+	return canaries, stable
+}
+
+// sampleCanaries adds a sample of the canaries and stable allocations to
+// each signal of the analysis result. The signals queried from the clients
+// are only sampled if the client sample is set.
+func sampleCanaries(analysis *structs.CanaryAnalysis, result *structs.CanaryAnalysisResult,
+	canaries, stable []*structs.Allocation, sample *canaryClientSample) {
+
+	result.Samples++
+	since := result.StartedAt.UnixNano()
+
+	// Restarts and OOM kills are counted from the task events since the
+	// analysis started, so they replace the previous values
+	restarts := signal(result, structs.CanaryAnalysisSignalRestarts, float64(analysis.MaxRestarts))
+	restarts.Canary = averageTaskEvents(canaries, since, isRestartEvent)
+	restarts.Stable = averageTaskEvents(stable, since, isRestartEvent)
+	restarts.Samples++
+
+	oomKills := signal(result, structs.CanaryAnalysisSignalOOMKills, float64(analysis.MaxOOMKills))
+	oomKills.Canary = averageTaskEvents(canaries, since, isOOMKillEvent)
+	oomKills.Stable = averageTaskEvents(stable, since, isOOMKillEvent)
+	oomKills.Samples++
+
+	if sample != nil {
+		checks := signal(result, structs.CanaryAnalysisSignalChecks, analysis.MaxCheckFailureRate)
+		if sample.checks {
+			checks.Sample(sample.canaryChecks, sample.stableChecks)
+		}
+
+		if analysis.MaxCPUIncrease > 0 {
+			cpu := signal(result, structs.CanaryAnalysisSignalCPU, float64(analysis.MaxCPUIncrease))
+			if sample.usage {
+				cpu.Sample(sample.canaryCPU, sample.stableCPU)
+			}
+		}
+		if analysis.MaxMemoryIncrease > 0 {
+			memory := signal(result, structs.CanaryAnalysisSignalMemory, float64(analysis.MaxMemoryIncrease))
+			if sample.usage {
+				memory.Sample(sample.canaryMemory, sample.stableMemory)
+			}
+		}
+	}
+
+	for _, s := range result.Signals {
+		s.Failed = s.Exceeded()
+	}
+}
+
+// signal returns the signal of the analysis result with the given name,
+// adding it if needed.
+func signal(result *structs.CanaryAnalysisResult, name string, threshold float64) *structs.CanaryAnalysisSignal {
+	s := result.Signal(name)
+	if s == nil {
+		s = &structs.CanaryAnalysisSignal{Name: name}
+		result.Signals = append(result.Signals, s)
+	}
+	s.Threshold = threshold
+	return s
+}
+
+func isRestartEvent(e *structs.TaskEvent) bool {
+	return e.Type == structs.TaskRestarting
+}
+
+func isOOMKillEvent(e *structs.TaskEvent) bool {
+	return e.Details["oom_killed"] == "true"
+}
+
+// averageTaskEvents returns the average number of task events per
+// allocation matching the filter since the given time.
+func averageTaskEvents(allocs []*structs.Allocation, since int64, filter func(*structs.TaskEvent) bool) float64 {
+	if len(allocs) == 0 {
+		return 0
+	}
+
+	count := 0
+	for _, alloc := range allocs {
+		for _, state := range alloc.TaskStates {
+			for _, e := range state.Events {
+				if e.Time >= since && filter(e) {
+					count++
+				}
+			}
+		}
+	}
+	return float64(count) / float64(len(allocs))
+}
+
+// checkFailureRate returns the rate of failed Nomad service check results
+// of the allocations, and false if the allocations have no check results.
+func (w *deploymentWatcher) checkFailureRate(allocs []*structs.Allocation) (float64, bool) {
+	total, failed := 0, 0
+	for _, alloc := range allocs {
+		var resp cstructs.AllocChecksResponse
+		err := w.allocRPC.Checks(&cstructs.AllocChecksRequest{AllocID: alloc.ID}, &resp)
+		if err != nil {
+			w.logger.Debug("failed to query allocation checks", "alloc_id", alloc.ID, "error", err)
+			continue
+		}
+		for _, result := range resp.Results {
+			switch result.Status {
+			case structs.CheckSuccess:
+				total++
+			case structs.CheckFailure:
+				total++
+				failed++
+			}
+		}
+	}
+	if total == 0 {
+		return 0, false
+	}
+	return float64(failed) / float64(total), true
+}
+
+// averageUsage returns the average CPU usage in MHz and memory usage in
+// bytes of the allocations, and false if no usage could be queried.
+func (w *deploymentWatcher) averageUsage(allocs []*structs.Allocation) (float64, float64, bool) {
+	var cpu, memory float64
+	sampled := 0
+	for _, alloc := range allocs {
+		var resp cstructs.AllocStatsResponse
+		err := w.allocRPC.Stats(&cstructs.AllocStatsRequest{AllocID: alloc.ID}, &resp)
+		if err != nil {
+			w.logger.Debug("failed to query allocation stats", "alloc_id", alloc.ID, "error", err)
+			continue
+		}
+		if resp.Stats == nil || resp.Stats.ResourceUsage == nil {
+			continue
+		}
+
+		usage := resp.Stats.ResourceUsage
+		if usage.CpuStats != nil {
+			cpu += usage.CpuStats.TotalTicks
+		}
+		if usage.MemoryStats != nil {
+			// Usage isn't reported by every driver, so fall back to RSS
+			if usage.MemoryStats.Usage > 0 {
+				memory += float64(usage.MemoryStats.Usage)
+			} else {
+				memory += float64(usage.MemoryStats.RSS)
+			}
+		}
+		sampled++
+	}
+	if sampled == 0 {
+		return 0, 0, false
+	}
+	return cpu / float64(sampled), memory / float64(sampled), true
+}
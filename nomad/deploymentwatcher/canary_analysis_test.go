@@ -0,0 +1,265 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package deploymentwatcher
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/helper/pointer"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+	mocker "github.com/stretchr/testify/mock"
+)
+
+func TestDeploymentWatcher_CanaryAnalysis(t *testing.T) {
+	ci.Parallel(t)
+
+	const mb = 1024 * 1024
+
+	testCases := []struct {
+		name          string
+		canaryRestart bool
+		canaryMemory  uint64
+		window        time.Duration
+		expFail       bool
+		expStatus     string
+		expFailed     []string
+	}{
+		{
+			name:         "running",
+			canaryMemory: 110 * mb,
+			window:       2 * time.Hour,
+			expStatus:    structs.CanaryAnalysisStatusRunning,
+		},
+		{
+			name:         "passed",
+			canaryMemory: 110 * mb,
+			window:       10 * time.Minute,
+			expStatus:    structs.CanaryAnalysisStatusPassed,
+		},
+		{
+			name:          "failed",
+			canaryRestart: true,
+			canaryMemory:  200 * mb,
+			window:        10 * time.Minute,
+			expFail:       true,
+			expStatus:     structs.CanaryAnalysisStatusFailed,
+			expFailed: []string{
+				structs.CanaryAnalysisSignalRestarts,
+				structs.CanaryAnalysisSignalMemory,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w, m := defaultTestDeploymentWatcher(t)
+			m.On("UpdateDeploymentStatus", mocker.Anything).Return(nil)
+			startedAt := time.Now().Add(-time.Hour)
+
+			update := structs.DefaultUpdateStrategy.Copy()
+			update.Canary = 1
+			update.AutoRevert = true
+			update.Analysis = &structs.CanaryAnalysis{
+				Window:            tc.window,
+				Interval:          time.Minute,
+				MaxMemoryIncrease: 20,
+			}
+			j := mock.Job()
+			j.TaskGroups[0].Update = update
+
+			d := mock.Deployment()
+			d.JobID = j.ID
+
+			stable := mock.Alloc()
+			stable.JobID = j.ID
+			stable.Job = j
+			stable.ClientStatus = structs.AllocClientStatusRunning
+
+			canary := mock.Alloc()
+			canary.JobID = j.ID
+			canary.Job = j
+			canary.DeploymentID = d.ID
+			canary.ClientStatus = structs.AllocClientStatusRunning
+			canary.DeploymentStatus = &structs.AllocDeploymentStatus{
+				Healthy: pointer.Of(true),
+				Canary:  true,
+			}
+			if tc.canaryRestart {
+				canary.TaskStates = map[string]*structs.TaskState{
+					"web": {
+						State:    structs.TaskStateRunning,
+						Restarts: 1,
+						Events: []*structs.TaskEvent{
+							structs.NewTaskEvent(structs.TaskRestarting),
+						},
+					},
+				}
+			}
+
+			d.TaskGroups["web"].DesiredCanaries = 1
+			d.TaskGroups["web"].PlacedCanaries = []string{canary.ID}
+			d.TaskGroups["web"].AutoRevert = true
+			d.TaskGroups["web"].CanaryAnalysis = &structs.CanaryAnalysisResult{
+				Status:    structs.CanaryAnalysisStatusRunning,
+				StartedAt: startedAt,
+			}
+
+			m.stats = map[string]*cstructs.AllocResourceUsage{
+				stable.ID: {ResourceUsage: &cstructs.ResourceUsage{
+					MemoryStats: &cstructs.MemoryStats{RSS: 100 * mb},
+					CpuStats:    &cstructs.CpuStats{TotalTicks: 100},
+				}},
+				canary.ID: {ResourceUsage: &cstructs.ResourceUsage{
+					MemoryStats: &cstructs.MemoryStats{RSS: tc.canaryMemory},
+					CpuStats:    &cstructs.CpuStats{TotalTicks: 100},
+				}},
+			}
+
+			must.NoError(t, m.state.UpsertJob(structs.MsgTypeTestSetup, m.nextIndex(), nil, j))
+			must.NoError(t, m.state.UpsertDeployment(m.nextIndex(), d))
+			must.NoError(t, m.state.UpsertAllocs(structs.MsgTypeTestSetup, m.nextIndex(),
+				[]*structs.Allocation{stable, canary}))
+
+			ctx, cancel := context.WithCancel(context.Background())
+			t.Cleanup(cancel)
+			dw := &deploymentWatcher{
+				deploymentTriggers: w,
+				allocRPC:           m,
+				state:              m.state,
+				deploymentID:       d.ID,
+				d:                  d,
+				j:                  j,
+				logger:             testlog.HCLogger(t),
+				ctx:                ctx,
+			}
+
+			var samples map[string]*canaryClientSample
+			select {
+			case samples = <-dw.sampleCanaryClients():
+			case <-time.After(5 * time.Second):
+				t.Fatal("timed out sampling canaries")
+			}
+			must.MapContainsKeys(t, samples, []string{"web"})
+
+			out, fail, rollback, err := dw.analyzeCanaries(samples)
+			must.NoError(t, err)
+			must.Eq(t, tc.expFail, fail)
+			must.Eq(t, tc.expFail, rollback)
+
+			result := out.TaskGroups["web"].CanaryAnalysis
+			must.Eq(t, tc.expStatus, result.Status)
+			must.Eq(t, 1, result.Samples)
+			must.Eq(t, tc.expFailed, result.FailedSignals())
+
+			memory := result.Signal(structs.CanaryAnalysisSignalMemory)
+			must.NotNil(t, memory)
+			must.Eq(t, float64(tc.canaryMemory), memory.Canary)
+			must.Eq(t, float64(100*mb), memory.Stable)
+
+			// CPU usage isn't compared unless it has a threshold
+			must.Nil(t, result.Signal(structs.CanaryAnalysisSignalCPU))
+
+			// The result is stored on the deployment
+			stored, err := m.state.DeploymentByID(nil, d.ID)
+			must.NoError(t, err)
+			must.Eq(t, result, stored.TaskGroups["web"].CanaryAnalysis)
+			must.Eq(t, d.Status, stored.Status)
+		})
+	}
+}
+
+func TestDeploymentWatcher_CanaryAnalysis_Start(t *testing.T) {
+	ci.Parallel(t)
+
+	w, m := defaultTestDeploymentWatcher(t)
+	m.On("UpdateDeploymentStatus", mocker.Anything).Return(nil)
+
+	update := structs.DefaultUpdateStrategy.Copy()
+	update.Canary = 1
+	update.Analysis = &structs.CanaryAnalysis{
+		Window:   10 * time.Minute,
+		Interval: 30 * time.Second,
+	}
+	j := mock.Job()
+	j.TaskGroups[0].Update = update
+
+	d := mock.Deployment()
+	d.JobID = j.ID
+
+	canary := mock.Alloc()
+	canary.JobID = j.ID
+	canary.DeploymentID = d.ID
+	canary.DeploymentStatus = &structs.AllocDeploymentStatus{Canary: true}
+	d.TaskGroups["web"].DesiredCanaries = 1
+	d.TaskGroups["web"].PlacedCanaries = []string{canary.ID}
+
+	must.NoError(t, m.state.UpsertJob(structs.MsgTypeTestSetup, m.nextIndex(), nil, j))
+	must.NoError(t, m.state.UpsertDeployment(m.nextIndex(), d))
+
+	dw := &deploymentWatcher{
+		deploymentTriggers: w,
+		allocRPC:           m,
+		state:              m.state,
+		deploymentID:       d.ID,
+		d:                  d,
+		j:                  j,
+		logger:             testlog.HCLogger(t),
+	}
+
+	// The analysis doesn't start until the canaries are healthy
+	updates := &allocUpdates{allocs: []*structs.AllocListStub{canary.Stub(nil)}}
+	must.Eq(t, 0, dw.startCanaryAnalysis(updates))
+
+	stored, err := m.state.DeploymentByID(nil, d.ID)
+	must.NoError(t, err)
+	must.Nil(t, stored.TaskGroups["web"].CanaryAnalysis)
+
+	canary.DeploymentStatus.Healthy = pointer.Of(true)
+	updates = &allocUpdates{allocs: []*structs.AllocListStub{canary.Stub(nil)}}
+	must.Eq(t, 30*time.Second, dw.startCanaryAnalysis(updates))
+
+	stored, err = m.state.DeploymentByID(nil, d.ID)
+	must.NoError(t, err)
+	result := stored.TaskGroups["web"].CanaryAnalysis
+	must.NotNil(t, result)
+	must.True(t, result.Running())
+
+	// Auto promotion waits for the analysis to pass
+	d = stored.Copy()
+	d.TaskGroups["web"].AutoPromote = true
+	must.NoError(t, dw.autoPromoteDeployment(d, updates.allocs))
+	m.AssertNotCalled(t, "UpdateDeploymentPromotion", mocker.Anything)
+}
+
+func TestDeploymentWatcher_CanaryAnalysis_NoClientSample(t *testing.T) {
+	ci.Parallel(t)
+
+	analysis := &structs.CanaryAnalysis{MaxRestarts: 1, MaxMemoryIncrease: 20}
+	result := &structs.CanaryAnalysisResult{
+		Status:    structs.CanaryAnalysisStatusRunning,
+		StartedAt: time.Now().Add(-time.Minute),
+	}
+
+	// Clients that didn't answer in time don't fail the memory signal, but
+	// the restarts are still sampled from the state
+	sampleCanaries(analysis, result, []*structs.Allocation{mock.Alloc()}, nil, nil)
+	must.Eq(t, 1, result.Samples)
+	must.NotNil(t, result.Signal(structs.CanaryAnalysisSignalRestarts))
+	must.Nil(t, result.Signal(structs.CanaryAnalysisSignalMemory))
+
+	sample := &canaryClientSample{usage: true, canaryMemory: 200, stableMemory: 100}
+	sampleCanaries(analysis, result, nil, nil, sample)
+	must.Eq(t, 2, result.Samples)
+	memory := result.Signal(structs.CanaryAnalysisSignalMemory)
+	must.NotNil(t, memory)
+	must.Eq(t, 200, memory.Canary)
+	must.True(t, memory.Failed)
+}
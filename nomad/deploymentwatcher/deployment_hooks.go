@@ -0,0 +1,421 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package deploymentwatcher
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// hasHooks returns whether any task group of the deployment's job has a
+// deployment hook.
+func (w *deploymentWatcher) hasHooks() bool {
+	for _, tg := range w.j.TaskGroups {
+		if tg.Update != nil && (tg.Update.PreDeploy != nil || tg.Update.PostDeploy != nil) {
+			return true
+		}
+	}
+	return false
+}
+
+// deploymentHooks returns the deployment hooks of the task group.
+func (w *deploymentWatcher) deploymentHooks(group string) (pre, post *structs.DeploymentHook) {
+	tg := w.j.LookupTaskGroup(group)
+	if tg == nil || tg.Update == nil {
+		return nil, nil
+	}
+	return tg.Update.PreDeploy, tg.Update.PostDeploy
+}
+
+type hookUpdates struct {
+	deployment *structs.Deployment
+	index      uint64
+	err        error
+}
+
+// getHooksCh creates a channel and starts a goroutine that parks a blocking
+// query for the deployment and the jobs dispatched for its running hooks, and
+// drops the deployment on the channel.
+func (w *deploymentWatcher) getHooksCh(index uint64) <-chan *hookUpdates {
+	out := make(chan *hookUpdates, 1)
+	go func() {
+		resp, index, err := w.state.BlockingQuery(w.getHooksImpl, index, w.ctx)
+		if err == nil {
+			err = w.ctx.Err()
+		}
+		u := &hookUpdates{index: index, err: err}
+		if err == nil {
+			u.deployment, _ = resp.(*structs.Deployment)
+		}
+		out <- u
+	}()
+
+	return out
+}
+
+// getHooksImpl retrieves the deployment and watches the jobs dispatched for
+// its running hooks and their allocations.
+func (w *deploymentWatcher) getHooksImpl(ws memdb.WatchSet, state *state.StateStore) (interface{}, uint64, error) {
+	if err := w.queryLimiter.Wait(w.ctx); err != nil {
+		return nil, 0, err
+	}
+
+	d, err := state.DeploymentByID(ws, w.deploymentID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if d == nil {
+		index, err := state.Index("deployment")
+		return nil, index, err
+	}
+
+	maxIndex := d.ModifyIndex
+	for _, dstate := range d.TaskGroups {
+		for _, hook := range []*structs.DeploymentHookState{dstate.PreDeploy, dstate.PostDeploy} {
+			if !hook.Running() {
+				continue
+			}
+
+			job, err := state.JobByID(ws, d.Namespace, hook.DispatchedJobID)
+			if err != nil {
+				return nil, 0, err
+			}
+			if job != nil && job.ModifyIndex > maxIndex {
+				maxIndex = job.ModifyIndex
+			}
+
+			allocs, err := state.AllocsByJob(ws, d.Namespace, hook.DispatchedJobID, false)
+			if err != nil {
+				return nil, 0, err
+			}
+			for _, alloc := range allocs {
+				if alloc.ModifyIndex > maxIndex {
+					maxIndex = alloc.ModifyIndex
+				}
+			}
+		}
+	}
+
+	return d, maxIndex, nil
+}
+
+// handleHookUpdate dispatches the jobs of the pending hooks of the deployment
+// and records the outcome of the running ones. Pre-deployment hooks are
+// dispatched right away, and post-deployment hooks once the task group is
+// done. It returns the description of the failure if a hook failed, and
+// whether the deployment should be rolled back.
+func (w *deploymentWatcher) handleHookUpdate(d *structs.Deployment) (string, bool, error) {
+	if d == nil || !d.Active() {
+		return "", false, nil
+	}
+
+	snap, err := w.state.Snapshot()
+	if err != nil {
+		return "", false, err
+	}
+
+	var done map[string]bool
+	var failDesc string
+	rollback, completed := false, false
+	pre := make(map[string]*structs.DeploymentHookState)
+	post := make(map[string]*structs.DeploymentHookState)
+	for tg, dstate := range d.TaskGroups {
+		preHook, postHook := w.deploymentHooks(tg)
+
+		if next := w.advanceHook(snap, d, tg, structs.DeploymentHookPreDeploy, preHook, dstate.PreDeploy); next != nil {
+			pre[tg] = next
+			switch next.Status {
+			case structs.DeploymentHookStatusComplete:
+				completed = true
+			case structs.DeploymentHookStatusFailed:
+				failDesc = structs.DeploymentStatusDescriptionPreDeployHook
+				rollback = rollback || dstate.AutoRevert
+			}
+			continue
+		}
+
+		// The post-deployment hook waits for the group to be done
+		if dstate.AwaitingPreDeploy() || dstate.PostDeploy == nil {
+			continue
+		}
+		if dstate.PostDeploy.Pending() {
+			if done == nil {
+				done = w.doneGroups(d)
+			}
+			if !done[tg] {
+				continue
+			}
+		}
+		if next := w.advanceHook(snap, d, tg, structs.DeploymentHookPostDeploy, postHook, dstate.PostDeploy); next != nil {
+			post[tg] = next
+			switch next.Status {
+			case structs.DeploymentHookStatusComplete:
+				completed = true
+			case structs.DeploymentHookStatusFailed:
+				if failDesc == "" {
+					failDesc = structs.DeploymentStatusDescriptionPostDeployHook
+				}
+				rollback = rollback || dstate.AutoRevert
+			}
+		}
+	}
+
+	if len(pre) == 0 && len(post) == 0 {
+		return "", false, nil
+	}
+
+	u := &structs.DeploymentStatusUpdate{
+		DeploymentID: w.deploymentID,
+		PreDeploy:    pre,
+		PostDeploy:   post,
+		UpdatedAt:    time.Now().UnixNano(),
+	}
+
+	// Describe what the deployment is waiting for once its hooks progressed
+	if failDesc == "" && d.Status == structs.DeploymentStatusRunning {
+		if desc := hookStatusDescription(d, pre, post); desc != d.StatusDescription {
+			u.Status = structs.DeploymentStatusRunning
+			u.StatusDescription = desc
+		}
+	}
+
+	// Create an eval so the scheduler places the allocations once the
+	// pre-deployment hooks completed, or completes the deployment once the
+	// post-deployment hooks completed
+	var eval *structs.Evaluation
+	if completed && failDesc == "" {
+		eval = w.getEval()
+	}
+
+	if _, err := w.upsertDeploymentStatusUpdate(u, eval, nil); err != nil {
+		return "", false, err
+	}
+	return failDesc, rollback, nil
+}
+
+// advanceHook dispatches the job of a pending hook, or checks whether the job
+// dispatched for a running hook has finished. It returns the new state of the
+// hook, or nil if the hook is unchanged.
+func (w *deploymentWatcher) advanceHook(snap *state.StateSnapshot, d *structs.Deployment,
+	group, name string, hook *structs.DeploymentHook, hstate *structs.DeploymentHookState) *structs.DeploymentHookState {
+
+	switch {
+	case hstate.Pending() && hook != nil:
+		// Hooks aren't dispatched while the deployment is paused
+		if d.Status != structs.DeploymentStatusRunning {
+			return nil
+		}
+
+		next := hstate.Copy()
+		if hook.Group != "" {
+			id, err := w.registerHookGroup(snap, d, group, name, hook)
+			if err != nil {
+				w.logger.Error("failed to register deployment hook group", "group", group, "hook", name, "error", err)
+				next.Status = structs.DeploymentHookStatusFailed
+				next.StatusDescription = fmt.Sprintf("Failed to register job for group %q: %v", hook.Group, err)
+				return next
+			}
+			next.Status = structs.DeploymentHookStatusRunning
+			next.StatusDescription = fmt.Sprintf("Registered job %q for group %q", id, hook.Group)
+			next.DispatchedJobID = id
+			return next
+		}
+
+		id, err := w.dispatchHook(d, group, name, hook)
+		if err != nil {
+			w.logger.Error("failed to dispatch deployment hook", "group", group, "hook", name, "error", err)
+			next.Status = structs.DeploymentHookStatusFailed
+			next.StatusDescription = fmt.Sprintf("Failed to dispatch job: %v", err)
+			return next
+		}
+		next.Status = structs.DeploymentHookStatusRunning
+		next.StatusDescription = fmt.Sprintf("Dispatched job %q", id)
+		next.DispatchedJobID = id
+		return next
+
+	case hstate.Running():
+		job, err := snap.JobByID(nil, d.Namespace, hstate.DispatchedJobID)
+		if err != nil {
+			w.logger.Error("failed to lookup deployment hook job", "job", hstate.DispatchedJobID, "error", err)
+			return nil
+		}
+
+		next := hstate.Copy()
+		if job == nil {
+			next.Status = structs.DeploymentHookStatusFailed
+			next.StatusDescription = fmt.Sprintf("Job %q not found", hstate.DispatchedJobID)
+			return next
+		}
+		if job.Status != structs.JobStatusDead {
+			return nil
+		}
+
+		allocs, err := snap.AllocsByJob(nil, d.Namespace, job.ID, false)
+		if err != nil {
+			w.logger.Error("failed to lookup deployment hook allocations", "job", job.ID, "error", err)
+			return nil
+		}
+		if hookJobSucceeded(allocs) {
+			next.Status = structs.DeploymentHookStatusComplete
+			next.StatusDescription = fmt.Sprintf("Job %q completed", job.ID)
+		} else {
+			next.Status = structs.DeploymentHookStatusFailed
+			next.StatusDescription = fmt.Sprintf("Job %q failed", job.ID)
+		}
+		return next
+	}
+
+	return nil
+}
+
+// dispatchHook dispatches the parameterized job of the hook and returns the
+// ID of the dispatched job. The dispatch is idempotent for the hook of the
+// task group in the deployment, so a new leader doesn't dispatch it again.
+func (w *deploymentWatcher) dispatchHook(d *structs.Deployment, group, name string, hook *structs.DeploymentHook) (string, error) {
+	if w.hookRPC == nil {
+		return "", fmt.Errorf("deployment hooks are not supported")
+	}
+
+	req := &structs.JobDispatchRequest{
+		JobID: hook.Job,
+		Meta:  hook.Meta,
+		WriteRequest: structs.WriteRequest{
+			Namespace:        d.Namespace,
+			IdempotencyToken: fmt.Sprintf("%s-%s-%s", d.ID, group, name),
+		},
+	}
+	var resp structs.JobDispatchResponse
+	if err := w.hookRPC.Dispatch(req, &resp); err != nil {
+		return "", err
+	}
+	return resp.DispatchedJobID, nil
+}
+
+// registerHookGroup registers the batch job running the hook task group and
+// returns its ID. The ID of the job is derived from the deployment so that a
+// new leader doesn't register it again.
+func (w *deploymentWatcher) registerHookGroup(snap *state.StateSnapshot, d *structs.Deployment,
+	group, name string, hook *structs.DeploymentHook) (string, error) {
+	if w.hookRPC == nil {
+		return "", fmt.Errorf("deployment hooks are not supported")
+	}
+
+	tg := w.j.LookupTaskGroup(hook.Group)
+	if tg == nil {
+		return "", fmt.Errorf("task group not found")
+	}
+
+	id := fmt.Sprintf("%s/%s-%s-%s", w.j.ID, group, strings.ReplaceAll(name, "_", "-"), d.ID[:8])
+	existing, err := snap.JobByID(nil, d.Namespace, id)
+	if err != nil {
+		return "", err
+	}
+	if existing != nil {
+		return id, nil
+	}
+
+	req := &structs.JobRegisterRequest{
+		Job: hookGroupJob(w.j, id, tg, hook.Meta),
+		WriteRequest: structs.WriteRequest{
+			Namespace: d.Namespace,
+		},
+	}
+	var resp structs.JobRegisterResponse
+	if err := w.hookRPC.Register(req, &resp); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// hookGroupJob returns the batch job running the hook task group of the job.
+// The job keeps the placement settings of the job, but none of its
+// scheduling or deployment settings.
+func hookGroupJob(job *structs.Job, id string, tg *structs.TaskGroup, meta map[string]string) *structs.Job {
+	hookGroup := tg.Copy()
+	hookGroup.Update = nil
+	hookGroup.Migrate = nil
+	hookGroup.ReschedulePolicy = structs.NewReschedulePolicy(structs.JobTypeBatch)
+
+	hookMeta := maps.Clone(job.Meta)
+	if hookMeta == nil {
+		hookMeta = make(map[string]string, len(meta))
+	}
+	maps.Copy(hookMeta, meta)
+
+	return &structs.Job{
+		Region:          job.Region,
+		Namespace:       job.Namespace,
+		ID:              id,
+		ParentID:        job.ID,
+		Name:            id,
+		Type:            structs.JobTypeBatch,
+		Priority:        job.Priority,
+		PriorityClass:   job.PriorityClass,
+		Datacenters:     slices.Clone(job.Datacenters),
+		NodePool:        job.NodePool,
+		Constraints:     structs.CopySliceConstraints(job.Constraints),
+		Affinities:      structs.CopySliceAffinities(job.Affinities),
+		Spreads:         structs.CopySliceSpreads(job.Spreads),
+		TaskGroups:      []*structs.TaskGroup{hookGroup},
+		Meta:            hookMeta,
+		ConsulNamespace: job.ConsulNamespace,
+		VaultNamespace:  job.VaultNamespace,
+	}
+}
+
+// hookJobSucceeded returns whether the latest allocations of a dead dispatched
+// job all completed successfully.
+func hookJobSucceeded(allocs []*structs.Allocation) bool {
+	succeeded := false
+	for _, alloc := range allocs {
+		if alloc.NextAllocation != "" {
+			continue
+		}
+		if alloc.ClientStatus != structs.AllocClientStatusComplete {
+			return false
+		}
+		succeeded = true
+	}
+	return succeeded
+}
+
+// hookStatusDescription returns the status description of a running
+// deployment given the updated states of its hooks.
+func hookStatusDescription(d *structs.Deployment, pre, post map[string]*structs.DeploymentHookState) string {
+	awaitingPre, runningPost := false, false
+	for tg, dstate := range d.TaskGroups {
+		preState, ok := pre[tg]
+		if !ok {
+			preState = dstate.PreDeploy
+		}
+		postState, ok := post[tg]
+		if !ok {
+			postState = dstate.PostDeploy
+		}
+
+		awaitingPre = awaitingPre || preState.Incomplete()
+		runningPost = runningPost || postState.Running()
+	}
+
+	switch {
+	case awaitingPre:
+		return structs.DeploymentStatusDescriptionRunningPreDeploy
+	case runningPost:
+		return structs.DeploymentStatusDescriptionRunningPostDeploy
+	case d.RequiresPromotion() && d.HasAutoPromote():
+		return structs.DeploymentStatusDescriptionRunningAutoPromotion
+	case d.RequiresPromotion():
+		return structs.DeploymentStatusDescriptionRunningNeedsPromotion
+	case d.StatusDescription == structs.DeploymentStatusDescriptionRunningRetainingBlue:
+		return d.StatusDescription
+	}
+	return structs.DeploymentStatusDescriptionRunning
+}
@@ -0,0 +1,273 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package deploymentwatcher
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/pointer"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/shoenig/test/must"
+	mocker "github.com/stretchr/testify/mock"
+)
+
+// hookTestJobs returns a parameterized hook job and a job whose task group
+// runs it as the given deployment hook.
+func hookTestJobs(name string) (*structs.Job, *structs.Job) {
+	hookJob := mock.BatchJob()
+	hookJob.ParameterizedJob = &structs.ParameterizedJobConfig{}
+
+	hook := &structs.DeploymentHook{Job: hookJob.ID, Meta: map[string]string{"stage": name}}
+	j := mock.Job()
+	j.TaskGroups[0].Update = structs.DefaultUpdateStrategy.Copy()
+	if name == structs.DeploymentHookPreDeploy {
+		j.TaskGroups[0].Update.PreDeploy = hook
+	} else {
+		j.TaskGroups[0].Update.PostDeploy = hook
+	}
+	return hookJob, j
+}
+
+// finishHookJob completes or fails the single allocation of a dispatched
+// hook job so the job is dead.
+func finishHookJob(t *testing.T, m *mockBackend, jobID string, clientStatus string) {
+	job, err := m.state.JobByID(nil, structs.DefaultNamespace, jobID)
+	must.NoError(t, err)
+	must.NotNil(t, job)
+
+	alloc := mock.Alloc()
+	alloc.Job = job
+	alloc.TaskGroup = job.TaskGroups[0].Name
+	alloc.JobID = job.ID
+	alloc.ClientStatus = clientStatus
+	must.NoError(t, m.state.UpsertAllocs(structs.MsgTypeTestSetup, m.nextIndex(), []*structs.Allocation{alloc}))
+
+	eval := mock.Eval()
+	eval.JobID = job.ID
+	eval.Status = structs.EvalStatusComplete
+	must.NoError(t, m.state.UpsertEvals(structs.MsgTypeTestSetup, m.nextIndex(), []*structs.Evaluation{eval}))
+}
+
+func TestDeploymentWatcher_Hooks_PreDeploy(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name         string
+		clientStatus string
+		expStatus    string
+		expDesc      string
+	}{
+		{
+			name:         "complete",
+			clientStatus: structs.AllocClientStatusComplete,
+			expStatus:    structs.DeploymentStatusRunning,
+			expDesc:      structs.DeploymentStatusDescriptionRunning,
+		},
+		{
+			name:         "failed",
+			clientStatus: structs.AllocClientStatusFailed,
+			expStatus:    structs.DeploymentStatusFailed,
+			expDesc:      structs.DeploymentStatusDescriptionPreDeployHook,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w, m := defaultTestDeploymentWatcher(t)
+			m.On("UpdateDeploymentStatus", mocker.Anything).Return(nil)
+			m.On("Dispatch", mocker.Anything).Return(nil)
+
+			hookJob, j := hookTestJobs(structs.DeploymentHookPreDeploy)
+			d := mock.Deployment()
+			d.JobID = j.ID
+			d.StatusDescription = structs.DeploymentStatusDescriptionRunningPreDeploy
+			d.TaskGroups["web"].PreDeploy = structs.NewDeploymentHookState(j.TaskGroups[0].Update.PreDeploy)
+
+			must.NoError(t, m.state.UpsertJob(structs.MsgTypeTestSetup, m.nextIndex(), nil, hookJob))
+			must.NoError(t, m.state.UpsertJob(structs.MsgTypeTestSetup, m.nextIndex(), nil, j))
+			must.NoError(t, m.state.UpsertDeployment(m.nextIndex(), d))
+
+			w.SetEnabled(true, m.state)
+
+			// The hook job is dispatched right away
+			var hook *structs.DeploymentHookState
+			testutil.WaitForResult(func() (bool, error) {
+				out, err := m.state.DeploymentByID(nil, d.ID)
+				if err != nil {
+					return false, err
+				}
+				hook = out.TaskGroups["web"].PreDeploy
+				return hook.Running(), fmt.Errorf("expected hook to be running: %#v", hook)
+			}, func(err error) {
+				t.Fatal(err)
+			})
+			must.NotEq(t, "", hook.DispatchedJobID)
+			m.AssertCalled(t, "Dispatch", mocker.MatchedBy(func(args *structs.JobDispatchRequest) bool {
+				return args.JobID == hookJob.ID && args.Meta["stage"] == structs.DeploymentHookPreDeploy &&
+					args.IdempotencyToken == fmt.Sprintf("%s-web-%s", d.ID, structs.DeploymentHookPreDeploy)
+			}))
+
+			// Nothing happens until the dispatched job is dead
+			finishHookJob(t, m, hook.DispatchedJobID, tc.clientStatus)
+
+			testutil.WaitForResult(func() (bool, error) {
+				out, err := m.state.DeploymentByID(nil, d.ID)
+				if err != nil {
+					return false, err
+				}
+				if out.Status != tc.expStatus || out.StatusDescription != tc.expDesc {
+					return false, fmt.Errorf("unexpected deployment status %q: %q", out.Status, out.StatusDescription)
+				}
+				return true, nil
+			}, func(err error) {
+				t.Fatal(err)
+			})
+
+			// An eval is created to place the allocations or roll back
+			evals, err := m.state.EvalsByJob(nil, j.Namespace, j.ID)
+			must.NoError(t, err)
+			must.Len(t, 1, evals)
+		})
+	}
+}
+
+func TestDeploymentWatcher_Hooks_PreDeployGroup(t *testing.T) {
+	ci.Parallel(t)
+
+	w, m := defaultTestDeploymentWatcher(t)
+	m.On("UpdateDeploymentStatus", mocker.Anything).Return(nil)
+	m.On("Register", mocker.Anything).Return(nil)
+
+	j := mock.Job()
+	migrate := j.TaskGroups[0].Copy()
+	migrate.Name = "migrate"
+	j.TaskGroups = append(j.TaskGroups, migrate)
+	j.TaskGroups[0].Update = structs.DefaultUpdateStrategy.Copy()
+	j.TaskGroups[0].Update.PreDeploy = &structs.DeploymentHook{Group: "migrate"}
+
+	d := mock.Deployment()
+	d.JobID = j.ID
+	d.StatusDescription = structs.DeploymentStatusDescriptionRunningPreDeploy
+	d.TaskGroups["web"].PreDeploy = structs.NewDeploymentHookState(j.TaskGroups[0].Update.PreDeploy)
+
+	must.NoError(t, m.state.UpsertJob(structs.MsgTypeTestSetup, m.nextIndex(), nil, j))
+	must.NoError(t, m.state.UpsertDeployment(m.nextIndex(), d))
+
+	w.SetEnabled(true, m.state)
+
+	// The job of the hook group is registered right away
+	var hook *structs.DeploymentHookState
+	testutil.WaitForResult(func() (bool, error) {
+		out, err := m.state.DeploymentByID(nil, d.ID)
+		if err != nil {
+			return false, err
+		}
+		hook = out.TaskGroups["web"].PreDeploy
+		return hook.Running(), fmt.Errorf("expected hook to be running: %#v", hook)
+	}, func(err error) {
+		t.Fatal(err)
+	})
+	must.Eq(t, fmt.Sprintf("%s/web-pre-deploy-%s", j.ID, d.ID[:8]), hook.DispatchedJobID)
+
+	hookJob, err := m.state.JobByID(nil, j.Namespace, hook.DispatchedJobID)
+	must.NoError(t, err)
+	must.NotNil(t, hookJob)
+	must.Eq(t, structs.JobTypeBatch, hookJob.Type)
+	must.Eq(t, j.ID, hookJob.ParentID)
+	must.Len(t, 1, hookJob.TaskGroups)
+	must.Eq(t, "migrate", hookJob.TaskGroups[0].Name)
+	must.Nil(t, hookJob.TaskGroups[0].Update)
+
+	finishHookJob(t, m, hook.DispatchedJobID, structs.AllocClientStatusComplete)
+
+	testutil.WaitForResult(func() (bool, error) {
+		out, err := m.state.DeploymentByID(nil, d.ID)
+		if err != nil {
+			return false, err
+		}
+		if out.StatusDescription != structs.DeploymentStatusDescriptionRunning {
+			return false, fmt.Errorf("unexpected deployment status %q: %q", out.Status, out.StatusDescription)
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatal(err)
+	})
+	m.AssertNumberOfCalls(t, "Register", 1)
+}
+
+func TestDeploymentWatcher_Hooks_PostDeploy(t *testing.T) {
+	ci.Parallel(t)
+
+	w, m := defaultTestDeploymentWatcher(t)
+	m.On("UpdateDeploymentStatus", mocker.Anything).Return(nil)
+	m.On("Dispatch", mocker.Anything).Return(nil)
+
+	hookJob, j := hookTestJobs(structs.DeploymentHookPostDeploy)
+	d := mock.Deployment()
+	d.JobID = j.ID
+	d.TaskGroups["web"].DesiredTotal = 1
+	d.TaskGroups["web"].PostDeploy = structs.NewDeploymentHookState(j.TaskGroups[0].Update.PostDeploy)
+
+	a := mock.Alloc()
+	a.JobID = j.ID
+	a.DeploymentID = d.ID
+
+	must.NoError(t, m.state.UpsertJob(structs.MsgTypeTestSetup, m.nextIndex(), nil, hookJob))
+	must.NoError(t, m.state.UpsertJob(structs.MsgTypeTestSetup, m.nextIndex(), nil, j))
+	must.NoError(t, m.state.UpsertDeployment(m.nextIndex(), d))
+	must.NoError(t, m.state.UpsertAllocs(structs.MsgTypeTestSetup, m.nextIndex(), []*structs.Allocation{a}))
+
+	dw := &deploymentWatcher{
+		deploymentTriggers: w,
+		hookRPC:            m,
+		state:              m.state,
+		deploymentID:       d.ID,
+		d:                  d,
+		j:                  j,
+		logger:             testlog.HCLogger(t),
+	}
+
+	// The hook isn't dispatched until the group is done
+	desc, rollback, err := dw.handleHookUpdate(d)
+	must.NoError(t, err)
+	must.Eq(t, "", desc)
+	must.False(t, rollback)
+	m.AssertNotCalled(t, "Dispatch", mocker.Anything)
+
+	a = a.Copy()
+	a.DeploymentStatus = &structs.AllocDeploymentStatus{Healthy: pointer.Of(true)}
+	must.NoError(t, m.state.UpsertAllocs(structs.MsgTypeTestSetup, m.nextIndex(), []*structs.Allocation{a}))
+
+	desc, _, err = dw.handleHookUpdate(d)
+	must.NoError(t, err)
+	must.Eq(t, "", desc)
+	m.AssertNumberOfCalls(t, "Dispatch", 1)
+
+	out, err := m.state.DeploymentByID(nil, d.ID)
+	must.NoError(t, err)
+	hook := out.TaskGroups["web"].PostDeploy
+	must.True(t, hook.Running())
+	must.Eq(t, structs.DeploymentStatusDescriptionRunningPostDeploy, out.StatusDescription)
+
+	// The deployment is ready to complete once the hook completed
+	finishHookJob(t, m, hook.DispatchedJobID, structs.AllocClientStatusComplete)
+
+	desc, _, err = dw.handleHookUpdate(out)
+	must.NoError(t, err)
+	must.Eq(t, "", desc)
+
+	out, err = m.state.DeploymentByID(nil, d.ID)
+	must.NoError(t, err)
+	must.Eq(t, structs.DeploymentHookStatusComplete, out.TaskGroups["web"].PostDeploy.Status)
+	must.Eq(t, structs.DeploymentStatusDescriptionRunning, out.StatusDescription)
+
+	evals, err := m.state.EvalsByJob(nil, j.Namespace, j.ID)
+	must.NoError(t, err)
+	must.Len(t, 1, evals)
+}
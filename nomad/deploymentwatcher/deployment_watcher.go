@@ -73,6 +73,13 @@ type deploymentWatcher struct {
 	// in enterprise edition
 	JobRPC
 
+	// allocRPC is used to query the clients running allocations to analyze
+	// canaries
+	allocRPC AllocRPC
+
+	// hookRPC is used to dispatch the jobs of the deployment hooks
+	hookRPC HookRPC
+
 	// state is the state that is watched for state changes.
 	state *state.StateStore
 
@@ -112,7 +119,8 @@ type deploymentWatcher struct {
 func newDeploymentWatcher(parent context.Context, queryLimiter *rate.Limiter,
 	logger log.Logger, state *state.StateStore, d *structs.Deployment,
 	j *structs.Job, triggers deploymentTriggers,
-	deploymentRPC DeploymentRPC, jobRPC JobRPC) *deploymentWatcher {
+	deploymentRPC DeploymentRPC, jobRPC JobRPC, allocRPC AllocRPC,
+	hookRPC HookRPC) *deploymentWatcher {
 
 	ctx, exitFn := context.WithCancel(parent)
 	w := &deploymentWatcher{
@@ -125,6 +133,8 @@ func newDeploymentWatcher(parent context.Context, queryLimiter *rate.Limiter,
 		deploymentTriggers: triggers,
 		DeploymentRPC:      deploymentRPC,
 		JobRPC:             jobRPC,
+		allocRPC:           allocRPC,
+		hookRPC:            hookRPC,
 		logger:             logger.With("deployment_id", d.ID, "job", j.NamespacedID()),
 		ctx:                ctx,
 		exitFn:             exitFn,
@@ -265,6 +275,7 @@ func (w *deploymentWatcher) PromoteDeployment(
 		DeploymentPromoteRequest: *req,
 		Eval:                     w.getEval(),
 	}
+	areq.PromotedAt = time.Now().UnixNano()
 
 	index, err := w.upsertDeploymentPromotion(areq)
 	if err != nil {
@@ -280,15 +291,14 @@ func (w *deploymentWatcher) PromoteDeployment(
 }
 
 // autoPromoteDeployment creates a synthetic promotion request, and upserts it for processing
-func (w *deploymentWatcher) autoPromoteDeployment(allocs []*structs.AllocListStub) error {
-	d := w.getDeployment()
+func (w *deploymentWatcher) autoPromoteDeployment(d *structs.Deployment, allocs []*structs.AllocListStub) error {
 	if !d.HasPlacedCanaries() || !d.RequiresPromotion() {
 		return nil
 	}
 
 	// AutoPromote iff every task group with canaries is marked auto_promote and is healthy. The whole
 	// job version has been incremented, so we promote together. See also AutoRevert
-	for _, dstate := range d.TaskGroups {
+	for tg, dstate := range d.TaskGroups {
 
 		// skip auto promote canary validation if the task group has no canaries
 		// to prevent auto promote hanging on mixed canary/non-canary taskgroup deploys
@@ -296,28 +306,25 @@ func (w *deploymentWatcher) autoPromoteDeployment(allocs []*structs.AllocListStu
 			continue
 		}
 
-		if !dstate.AutoPromote || len(dstate.PlacedCanaries) < dstate.DesiredCanaries {
+		if !dstate.AutoPromote || !canariesHealthy(dstate, allocs) {
 			return nil
 		}
 
-		healthyCanaries := 0
-		// Find the health status of each canary
-		for _, c := range dstate.PlacedCanaries {
-			for _, a := range allocs {
-				if c == a.ID && a.DeploymentStatus.IsHealthy() {
-					healthyCanaries += 1
-				}
-			}
-		}
-		if healthyCanaries != dstate.DesiredCanaries {
+		// Canaries that are analyzed must also pass the analysis
+		if w.canaryAnalysis(tg) != nil && (dstate.CanaryAnalysis == nil ||
+			dstate.CanaryAnalysis.Status != structs.CanaryAnalysisStatusPassed) {
 			return nil
 		}
 	}
 
 	// Send the request
 	_, err := w.upsertDeploymentPromotion(&structs.ApplyDeploymentPromoteRequest{
-		DeploymentPromoteRequest: structs.DeploymentPromoteRequest{DeploymentID: d.GetID(), All: true},
-		Eval:                     w.getEval(),
+		DeploymentPromoteRequest: structs.DeploymentPromoteRequest{
+			DeploymentID: d.GetID(),
+			All:          true,
+			PromotedAt:   time.Now().UnixNano(),
+		},
+		Eval: w.getEval(),
 	})
 	return err
 }
@@ -433,7 +440,39 @@ func (w *deploymentWatcher) watch() {
 	allocsCh := w.getAllocsCh(allocIndex)
 	var updates *allocUpdates
 
-	rollback, deadlineHit := false, false
+	// analysisCh fires when the canaries should be sampled next. It's nil
+	// while no canaries are being analyzed. sampleCh receives the samples
+	// queried from the clients and is only set while they're queried.
+	var analysisCh <-chan time.Time
+	var sampleCh <-chan map[string]*canaryClientSample
+
+	// retainCh fires when a blue/green task group stops retaining its blue
+	// allocations, so the scheduler can stop them. Retentions that expired
+	// while no leader watched the deployment fire right away.
+	var retainedUntil, currentRetention time.Time
+	var retainCh <-chan time.Time
+	resetRetention := func() {
+		next := w.getBlueRetentionCutoff(w.getDeployment(), retainedUntil)
+		if next.Equal(currentRetention) {
+			return
+		}
+		currentRetention = next
+		retainCh = nil
+		if !next.IsZero() {
+			retainCh = time.After(time.Until(next))
+		}
+	}
+	resetRetention()
+
+	// hooksCh returns the deployment whenever it or the jobs dispatched for
+	// its hooks change. It's nil if the job has no deployment hooks.
+	var hooksCh <-chan *hookUpdates
+	if w.hasHooks() {
+		hooksCh = w.getHooksCh(1)
+	}
+
+	rollback, deadlineHit, analysisFailed := false, false, false
+	hookFailure := ""
 
 FAIL:
 	for {
@@ -497,11 +536,84 @@ FAIL:
 				}
 			}
 
+			resetRetention()
+
 			err := w.nextRegion(w.getStatus())
 			if err != nil {
 				break FAIL
 			}
 
+		case <-retainCh:
+			// Create an eval so the scheduler stops the blue allocations that
+			// are no longer retained and completes the deployment
+			retainedUntil, currentRetention, retainCh = currentRetention, time.Time{}, nil
+			if _, err := w.createUpdate(nil, w.getEval()); err != nil {
+				w.logger.Error("failed to create evaluation for blue/green retention", "error", err)
+			}
+			resetRetention()
+
+		case hooks := <-hooksCh:
+			if err := hooks.err; err != nil {
+				if err == context.Canceled || w.ctx.Err() == context.Canceled {
+					return
+				}
+
+				w.logger.Error("failed to retrieve deployment hooks", "error", err)
+				return
+			}
+
+			// Dispatch the pending hooks and fail the deployment if a hook
+			// failed
+			desc, rback, err := w.handleHookUpdate(hooks.deployment)
+			if err != nil {
+				w.logger.Error("failed to handle deployment hooks", "error", err)
+			}
+			if desc != "" {
+				hookFailure = desc
+				rollback = rback
+				err := w.nextRegion(structs.DeploymentStatusFailed)
+				if err != nil {
+					w.logger.Error("multiregion deployment error", "error", err)
+				}
+				break FAIL
+			}
+
+			hooksCh = w.getHooksCh(hooks.index)
+
+		case <-analysisCh:
+			// Query the clients running the canaries being analyzed
+			analysisCh = nil
+			sampleCh = w.sampleCanaryClients()
+
+		case samples := <-sampleCh:
+			// Sample the canaries being analyzed and fail the deployment if
+			// they failed the analysis
+			sampleCh = nil
+			d, fail, rback, err := w.analyzeCanaries(samples)
+			if err != nil {
+				w.logger.Error("failed to analyze canaries", "error", err)
+			}
+			if fail {
+				analysisFailed = true
+				rollback = rback
+				err := w.nextRegion(structs.DeploymentStatusFailed)
+				if err != nil {
+					w.logger.Error("multiregion deployment error", "error", err)
+				}
+				break FAIL
+			}
+
+			if d != nil && updates != nil {
+				err = w.autoPromoteDeployment(d, updates.allocs)
+				if err != nil {
+					w.logger.Error("failed to auto promote deployment", "error", err)
+				}
+			}
+
+			if interval := w.startCanaryAnalysis(updates); interval > 0 {
+				analysisCh = time.After(interval)
+			}
+
 		case updates = <-allocsCh:
 			if err := updates.err; err != nil {
 				if err == context.Canceled || w.ctx.Err() == context.Canceled {
@@ -537,11 +649,18 @@ FAIL:
 			}
 
 			// If permitted, automatically promote this canary deployment
-			err = w.autoPromoteDeployment(updates.allocs)
+			err = w.autoPromoteDeployment(w.getDeployment(), updates.allocs)
 			if err != nil {
 				w.logger.Error("failed to auto promote deployment", "error", err)
 			}
 
+			// Start analyzing the canaries once they are healthy
+			if analysisCh == nil && sampleCh == nil {
+				if interval := w.startCanaryAnalysis(updates); interval > 0 {
+					analysisCh = time.After(interval)
+				}
+			}
+
 			// Create an eval to push the deployment along
 			if res.createEval || len(res.allowReplacements) != 0 {
 				w.createBatchedUpdate(res.allowReplacements, allocIndex)
@@ -554,7 +673,11 @@ FAIL:
 
 	// Change the deployments status to failed
 	desc := structs.DeploymentStatusDescriptionFailedAllocations
-	if deadlineHit {
+	if hookFailure != "" {
+		desc = hookFailure
+	} else if analysisFailed {
+		desc = structs.DeploymentStatusDescriptionCanaryAnalysis
+	} else if deadlineHit {
 		desc = structs.DeploymentStatusDescriptionProgressDeadline
 	}
 
@@ -743,6 +866,23 @@ func (w *deploymentWatcher) getDeploymentProgressCutoff(d *structs.Deployment) t
 	return next
 }
 
+// getBlueRetentionCutoff returns the earliest time after the given time at
+// which a promoted blue/green task group of the deployment stops retaining its
+// blue allocations, or the zero time if there is none.
+func (w *deploymentWatcher) getBlueRetentionCutoff(d *structs.Deployment, after time.Time) time.Time {
+	var next time.Time
+	for _, dstate := range d.TaskGroups {
+		if !dstate.BlueGreen || !dstate.Promoted || !dstate.RetainBlueUntil.After(after) {
+			continue
+		}
+
+		if next.IsZero() || dstate.RetainBlueUntil.Before(next) {
+			next = dstate.RetainBlueUntil
+		}
+	}
+	return next
+}
+
 // doneGroups returns a map of task group to whether the deployment appears to
 // be done for the group. A true value doesn't mean no more action will be taken
 // in the life time of the deployment because there could always be node
@@ -14,6 +14,7 @@ import (
 	log "github.com/hashicorp/go-hclog"
 	memdb "github.com/hashicorp/go-memdb"
 
+	cstructs "github.com/hashicorp/nomad/client/structs"
 	"github.com/hashicorp/nomad/nomad/state"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
@@ -57,6 +58,26 @@ type DeploymentRaftEndpoints interface {
 	UpdateAllocDesiredTransition(req *structs.AllocUpdateDesiredTransitionRequest) (uint64, error)
 }
 
+// AllocRPC holds methods for querying the clients running the allocations of
+// a deployment, used to analyze canaries.
+type AllocRPC interface {
+	// Stats returns the resource usage of an allocation.
+	Stats(args *cstructs.AllocStatsRequest, reply *cstructs.AllocStatsResponse) error
+
+	// Checks returns the results of the Nomad service checks of an
+	// allocation.
+	Checks(args *cstructs.AllocChecksRequest, reply *cstructs.AllocChecksResponse) error
+}
+
+// HookRPC holds methods for running the jobs of deployment hooks.
+type HookRPC interface {
+	// Dispatch dispatches a parameterized job.
+	Dispatch(args *structs.JobDispatchRequest, reply *structs.JobDispatchResponse) error
+
+	// Register registers the job running a hook task group.
+	Register(args *structs.JobRegisterRequest, reply *structs.JobRegisterResponse) error
+}
+
 // Watcher is used to watch deployments and their allocations created
 // by the scheduler and trigger the scheduler when allocation health
 // transitions.
@@ -84,6 +105,12 @@ type Watcher struct {
 	// server interface for Job RPCs
 	jobRPC JobRPC
 
+	// allocRPC is used to query the clients running allocations
+	allocRPC AllocRPC
+
+	// hookRPC is used to dispatch the jobs of deployment hooks
+	hookRPC HookRPC
+
 	// watchers is the set of active watchers, one per deployment
 	watchers map[string]*deploymentWatcher
 
@@ -102,7 +129,8 @@ type Watcher struct {
 // deployments and trigger the scheduler as needed.
 func NewDeploymentsWatcher(logger log.Logger,
 	raft DeploymentRaftEndpoints,
-	deploymentRPC DeploymentRPC, jobRPC JobRPC,
+	deploymentRPC DeploymentRPC, jobRPC JobRPC, allocRPC AllocRPC,
+	hookRPC HookRPC,
 	stateQueriesPerSecond float64,
 	updateBatchDuration time.Duration,
 ) *Watcher {
@@ -111,6 +139,8 @@ func NewDeploymentsWatcher(logger log.Logger,
 		raft:                raft,
 		deploymentRPC:       deploymentRPC,
 		jobRPC:              jobRPC,
+		allocRPC:            allocRPC,
+		hookRPC:             hookRPC,
 		queryLimiter:        rate.NewLimiter(rate.Limit(stateQueriesPerSecond), 100),
 		updateBatchDuration: updateBatchDuration,
 		logger:              logger.Named("deployments_watcher"),
@@ -301,7 +331,7 @@ func (w *Watcher) addLocked(d *structs.Deployment) (*deploymentWatcher, error) {
 	}
 
 	watcher := newDeploymentWatcher(w.ctx, w.queryLimiter, w.logger, w.state, d, job,
-		w, w.deploymentRPC, w.jobRPC)
+		w, w.deploymentRPC, w.jobRPC, w.allocRPC, w.hookRPC)
 	w.watchers[d.ID] = watcher
 	return watcher, nil
 }
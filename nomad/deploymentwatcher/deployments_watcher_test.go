@@ -25,7 +25,7 @@ import (
 
 func testDeploymentWatcher(t *testing.T, qps float64, batchDur time.Duration) (*Watcher, *mockBackend) {
 	m := newMockBackend(t)
-	w := NewDeploymentsWatcher(testlog.HCLogger(t), m, nil, nil, qps, batchDur)
+	w := NewDeploymentsWatcher(testlog.HCLogger(t), m, nil, nil, m, m, qps, batchDur)
 	return w, m
 }
 
@@ -2132,3 +2132,43 @@ func TestWatcher_PurgeDeployment(t *testing.T) {
 		wait.Gap(10*time.Millisecond),
 	))
 }
+
+// Test that an evaluation is created once a promoted blue/green deployment
+// stops retaining the blue allocations
+func TestDeploymentWatcher_Watch_BlueGreenRetention(t *testing.T) {
+	ci.Parallel(t)
+	w, m := testDeploymentWatcher(t, 1000.0, 1*time.Millisecond)
+
+	j := mock.Job()
+	j.TaskGroups[0].Update = structs.DefaultUpdateStrategy.Copy()
+	j.TaskGroups[0].Update.BlueGreen = &structs.BlueGreenStrategy{Retention: 200 * time.Millisecond}
+	d := mock.Deployment()
+	d.JobID = j.ID
+	d.TaskGroups["web"].DesiredCanaries = 1
+	d.TaskGroups["web"].Promoted = true
+	d.TaskGroups["web"].BlueGreen = true
+	d.TaskGroups["web"].BlueRetention = 200 * time.Millisecond
+	d.TaskGroups["web"].RetainBlueUntil = time.Now().Add(200 * time.Millisecond)
+
+	must.NoError(t, m.state.UpsertJob(structs.MsgTypeTestSetup, m.nextIndex(), nil, j))
+	must.NoError(t, m.state.UpsertDeployment(m.nextIndex(), d))
+
+	m1 := matchUpdateAllocDesiredTransitions([]string{d.ID})
+	m.On("UpdateAllocDesiredTransition", mocker.MatchedBy(m1)).Return(nil).Once()
+
+	w.SetEnabled(true, m.state)
+	testutil.WaitForResult(func() (bool, error) { return 1 == watchersCount(w), nil },
+		func(err error) { must.Eq(t, 1, watchersCount(w)) })
+
+	testutil.WaitForResult(func() (bool, error) {
+		evals, err := m.state.EvalsByJob(nil, j.Namespace, j.ID)
+		if err != nil {
+			return false, err
+		}
+		return len(evals) == 1, fmt.Errorf("expected 1 eval, got %d", len(evals))
+	}, func(err error) {
+		t.Fatal(err)
+	})
+
+	m.AssertCalled(t, "UpdateAllocDesiredTransition", mocker.MatchedBy(m1))
+}
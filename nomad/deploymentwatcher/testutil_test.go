@@ -4,11 +4,13 @@
 package deploymentwatcher
 
 import (
+	"fmt"
 	"reflect"
 	"strings"
 	"sync"
 	"testing"
 
+	cstructs "github.com/hashicorp/nomad/client/structs"
 	"github.com/hashicorp/nomad/nomad/state"
 	"github.com/hashicorp/nomad/nomad/structs"
 	mocker "github.com/stretchr/testify/mock"
@@ -19,6 +21,10 @@ type mockBackend struct {
 	index uint64
 	state *state.StateStore
 	l     sync.Mutex
+
+	// stats and checks are returned by the client allocation RPCs
+	stats  map[string]*cstructs.AllocResourceUsage
+	checks map[string]map[structs.CheckID]*structs.CheckQueryResult
 }
 
 func newMockBackend(t *testing.T) *mockBackend {
@@ -38,6 +44,51 @@ func (m *mockBackend) nextIndex() uint64 {
 	return i
 }
 
+func (m *mockBackend) Stats(args *cstructs.AllocStatsRequest, reply *cstructs.AllocStatsResponse) error {
+	m.l.Lock()
+	defer m.l.Unlock()
+	reply.Stats = m.stats[args.AllocID]
+	return nil
+}
+
+func (m *mockBackend) Checks(args *cstructs.AllocChecksRequest, reply *cstructs.AllocChecksResponse) error {
+	m.l.Lock()
+	defer m.l.Unlock()
+	reply.Results = m.checks[args.AllocID]
+	return nil
+}
+
+func (m *mockBackend) Dispatch(args *structs.JobDispatchRequest, reply *structs.JobDispatchResponse) error {
+	m.Called(args)
+
+	// Dispatch the job the way the Job endpoint does, idempotently
+	parent, err := m.state.JobByID(nil, args.RequestNamespace(), args.JobID)
+	if err != nil {
+		return err
+	}
+	if parent == nil {
+		return fmt.Errorf("parameterized job not found")
+	}
+	reply.DispatchedJobID = args.JobID + "/dispatch-" + args.IdempotencyToken
+	existing, err := m.state.JobByID(nil, args.RequestNamespace(), reply.DispatchedJobID)
+	if err != nil || existing != nil {
+		return err
+	}
+
+	job := parent.Copy()
+	job.ID = reply.DispatchedJobID
+	job.ParentID = parent.ID
+	job.Dispatched = true
+	job.Meta = args.Meta
+	job.DispatchIdempotencyToken = args.IdempotencyToken
+	return m.state.UpsertJob(structs.MsgTypeTestSetup, m.nextIndex(), nil, job)
+}
+
+func (m *mockBackend) Register(args *structs.JobRegisterRequest, reply *structs.JobRegisterResponse) error {
+	m.Called(args)
+	return m.state.UpsertJob(structs.MsgTypeTestSetup, m.nextIndex(), nil, args.Job)
+}
+
 func (m *mockBackend) UpdateAllocDesiredTransition(u *structs.AllocUpdateDesiredTransitionRequest) (uint64, error) {
 	m.Called(u)
 	i := m.nextIndex()
@@ -168,7 +219,13 @@ type matchDeploymentPromoteRequestConfig struct {
 // matchDeploymentPromoteRequest is used to match a promote request
 func matchDeploymentPromoteRequest(c *matchDeploymentPromoteRequestConfig) func(args *structs.ApplyDeploymentPromoteRequest) bool {
 	return func(args *structs.ApplyDeploymentPromoteRequest) bool {
-		if !reflect.DeepEqual(*c.Promotion, args.DeploymentPromoteRequest) {
+		// The promotion time is set by the watcher
+		promotion := args.DeploymentPromoteRequest
+		if promotion.PromotedAt == 0 {
+			return false
+		}
+		promotion.PromotedAt = 0
+		if !reflect.DeepEqual(*c.Promotion, promotion) {
 			return false
 		}
 
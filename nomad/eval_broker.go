@@ -74,8 +74,20 @@ type EvalBroker struct {
 	// now safe for the Eval.Ack RPC to cancel in batches
 	cancelable []*structs.Evaluation
 
-	// ready tracks the ready jobs by scheduler in a priority queue
-	ready map[string]ReadyEvaluations
+	// ready tracks the ready jobs by scheduler and namespace in a priority
+	// queue
+	ready map[string]map[string]ReadyEvaluations
+
+	// readyOrder is the order in which the ready evaluations were made
+	// ready, by evaluation ID. It breaks ties between the ready queues of
+	// different namespaces so that they're dequeued first in, first out.
+	readyOrder map[string]uint64
+	readySeq   uint64
+
+	// fairShare tracks the share of scheduler workers given to each
+	// namespace, and is used to pick the namespace of the next evaluation
+	// when fair share is enabled
+	fairShare *fairShare
 
 	// unack is a map of evalID to an un-acknowledged evaluation
 	unack map[string]*unackEval
@@ -157,7 +169,9 @@ func NewEvalBroker(ctx context.Context, timeout, initialNackDelay, subsequentNac
 		jobEvals:             make(map[structs.NamespacedID]string),
 		pending:              make(map[structs.NamespacedID]PendingEvaluations),
 		cancelable:           make([]*structs.Evaluation, 0, structs.MaxUUIDsPerWriteRequest),
-		ready:                make(map[string]ReadyEvaluations),
+		ready:                make(map[string]map[string]ReadyEvaluations),
+		readyOrder:           make(map[string]uint64),
+		fairShare:            newFairShare(),
 		unack:                make(map[string]*unackEval),
 		waiting:              make(map[string]chan struct{}),
 		requeue:              make(map[string]*structs.Evaluation),
@@ -170,6 +184,7 @@ func NewEvalBroker(ctx context.Context, timeout, initialNackDelay, subsequentNac
 		delayedEvalsUpdateCh: make(chan struct{}, 1),
 	}
 	b.stats.ByScheduler = make(map[string]*SchedulerStats)
+	b.stats.ByNamespace = make(map[string]*NamespaceStats)
 	b.stats.DelayedEvals = make(map[string]*structs.Evaluation)
 
 	return b, nil
@@ -345,21 +360,29 @@ func (b *EvalBroker) enqueueLocked(eval *structs.Evaluation, sched string, track
 		heap.Push(&pending, eval)
 		b.pending[namespacedID] = pending
 		b.stats.TotalPending += 1
+		b.namespaceStats(eval.Namespace).Pending += 1
 		return
 	}
 
-	// Find the next ready eval by scheduler class
-	readyQueue, ok := b.ready[sched]
+	// Find the next ready eval by scheduler class and namespace
+	byNamespace, ok := b.ready[sched]
 	if !ok {
-		readyQueue = make([]*structs.Evaluation, 0, 16)
+		byNamespace = make(map[string]ReadyEvaluations)
+		b.ready[sched] = byNamespace
 		if _, ok := b.waiting[sched]; !ok {
 			b.waiting[sched] = make(chan struct{}, 1)
 		}
 	}
+	readyQueue, ok := byNamespace[eval.Namespace]
+	if !ok {
+		readyQueue = make([]*structs.Evaluation, 0, 16)
+	}
 
 	// Push onto the heap
 	heap.Push(&readyQueue, eval)
-	b.ready[sched] = readyQueue
+	byNamespace[eval.Namespace] = readyQueue
+	b.readySeq++
+	b.readyOrder[eval.ID] = b.readySeq
 
 	// Update the stats
 	b.stats.TotalReady += 1
@@ -370,6 +393,12 @@ func (b *EvalBroker) enqueueLocked(eval *structs.Evaluation, sched string, track
 	}
 	bySched.Ready += 1
 
+	byNs := b.namespaceStats(eval.Namespace)
+	if byNs.Ready == 0 {
+		b.fairShare.activate(eval.Namespace)
+	}
+	byNs.Ready += 1
+
 	// Unblock any pending dequeues
 	select {
 	case b.waiting[sched] <- struct{}{}:
@@ -441,24 +470,30 @@ func (b *EvalBroker) scanForSchedulers(schedulers []string) (*structs.Evaluation
 		return nil, "", fmt.Errorf("eval broker disabled")
 	}
 
+	// When fair share is enabled, only consider work from the namespace that
+	// received the smallest weighted share of dequeues so far.
+	namespace := ""
+	if b.fairShare.enabled {
+		namespace = b.nextFairShareNamespace(schedulers)
+		if namespace == "" {
+			return nil, "", nil
+		}
+	}
+
 	// Scan for eligible work
+	var eligible []*structs.Evaluation
 	var eligibleSched []string
 	var eligiblePriority int
 	for _, sched := range schedulers {
-		// Get the ready queue for this scheduler
-		readyQueue, ok := b.ready[sched]
-		if !ok {
-			continue
-		}
-
-		// Peek at the next item
-		ready := readyQueue.Peek()
+		// Peek at the next item for this scheduler
+		ready := b.peekReady(sched, namespace)
 		if ready == nil {
 			continue
 		}
 
 		// Add to eligible if equal or greater priority
 		if len(eligibleSched) == 0 || ready.Priority > eligiblePriority {
+			eligible = []*structs.Evaluation{ready}
 			eligibleSched = []string{sched}
 			eligiblePriority = ready.Priority
 
@@ -466,6 +501,7 @@ func (b *EvalBroker) scanForSchedulers(schedulers []string) (*structs.Evaluation
 			continue
 
 		} else if eligiblePriority == ready.Priority {
+			eligible = append(eligible, ready)
 			eligibleSched = append(eligibleSched, sched)
 		}
 	}
@@ -478,23 +514,56 @@ func (b *EvalBroker) scanForSchedulers(schedulers []string) (*structs.Evaluation
 
 	case 1:
 		// Only a single task, dequeue
-		return b.dequeueForSched(eligibleSched[0])
+		return b.dequeueForSched(eligibleSched[0], eligible[0].Namespace)
 
 	default:
 		// Multiple tasks. We pick a random task so that we fairly
 		// distribute work.
 		offset := rand.Intn(n)
-		return b.dequeueForSched(eligibleSched[offset])
+		return b.dequeueForSched(eligibleSched[offset], eligible[offset].Namespace)
+	}
+}
+
+// peekReady returns the next ready evaluation for the scheduler without
+// dequeuing it. If namespace is empty, the next evaluation across all
+// namespaces is returned. This assumes locks are held.
+func (b *EvalBroker) peekReady(sched, namespace string) *structs.Evaluation {
+	byNamespace, ok := b.ready[sched]
+	if !ok {
+		return nil
+	}
+	if namespace != "" {
+		return byNamespace[namespace].Peek()
+	}
+
+	var next *structs.Evaluation
+	for _, readyQueue := range byNamespace {
+		ready := readyQueue.Peek()
+		if ready == nil {
+			continue
+		}
+		if next == nil || readyBefore(ready, next) ||
+			(!readyBefore(next, ready) && b.readyOrder[ready.ID] < b.readyOrder[next.ID]) {
+			next = ready
+		}
 	}
+	return next
 }
 
-// dequeueForSched is used to dequeue the next work item for a given scheduler.
-// This assumes locks are held and that this scheduler has work
-func (b *EvalBroker) dequeueForSched(sched string) (*structs.Evaluation, string, error) {
-	readyQueue := b.ready[sched]
+// dequeueForSched is used to dequeue the next work item for a given scheduler
+// and namespace. This assumes locks are held and that this scheduler has work
+// for the namespace.
+func (b *EvalBroker) dequeueForSched(sched, namespace string) (*structs.Evaluation, string, error) {
+	byNamespace := b.ready[sched]
+	readyQueue := byNamespace[namespace]
 	raw := heap.Pop(&readyQueue)
-	b.ready[sched] = readyQueue
+	if len(readyQueue) > 0 {
+		byNamespace[namespace] = readyQueue
+	} else {
+		delete(byNamespace, namespace)
+	}
 	eval := raw.(*structs.Evaluation)
+	delete(b.readyOrder, eval.ID)
 
 	// Generate a UUID for the token
 	token := uuid.Generate()
@@ -520,6 +589,12 @@ func (b *EvalBroker) dequeueForSched(sched string) (*structs.Evaluation, string,
 	bySched := b.stats.ByScheduler[sched]
 	bySched.Ready -= 1
 	bySched.Unacked += 1
+	byNs := b.namespaceStats(namespace)
+	byNs.Ready -= 1
+	byNs.Unacked += 1
+
+	// Charge the namespace for the dequeue
+	b.fairShare.charge(namespace)
 
 	return eval, token, nil
 }
@@ -629,6 +704,8 @@ func (b *EvalBroker) Ack(evalID, token string) error {
 	}
 	bySched := b.stats.ByScheduler[queue]
 	bySched.Unacked -= 1
+	b.namespaceStats(unack.Eval.Namespace).Unacked -= 1
+	defer b.pruneNamespaceStats(unack.Eval.Namespace)
 
 	// Cleanup
 	delete(b.unack, evalID)
@@ -649,12 +726,14 @@ func (b *EvalBroker) Ack(evalID, token string) error {
 		b.cancelable = append(b.cancelable, cancelable...)
 		b.stats.TotalCancelable = len(b.cancelable)
 		b.stats.TotalPending -= len(cancelable)
+		b.namespaceStats(namespacedID.Namespace).Pending -= len(cancelable)
 
 		// If any remain, enqueue an eval
 		if len(pending) > 0 {
 			raw := heap.Pop(&pending)
 			eval := raw.(*structs.Evaluation)
 			b.stats.TotalPending -= 1
+			b.namespaceStats(namespacedID.Namespace).Pending -= 1
 			b.enqueueLocked(eval, eval.Type, true)
 		}
 
@@ -703,6 +782,8 @@ func (b *EvalBroker) Nack(evalID, token string) error {
 	b.stats.TotalUnacked -= 1
 	bySched := b.stats.ByScheduler[unack.Eval.Type]
 	bySched.Unacked -= 1
+	b.namespaceStats(unack.Eval.Namespace).Unacked -= 1
+	defer b.pruneNamespaceStats(unack.Eval.Namespace)
 
 	// Check if we've hit the delivery limit, and re-enqueue
 	// in the failedQueue
@@ -839,11 +920,15 @@ func (b *EvalBroker) flush() {
 	b.stats.TotalCancelable = 0
 	b.stats.DelayedEvals = make(map[string]*structs.Evaluation)
 	b.stats.ByScheduler = make(map[string]*SchedulerStats)
+	b.stats.ByNamespace = make(map[string]*NamespaceStats)
 	b.evals = make(map[string]int)
 	b.jobEvals = make(map[structs.NamespacedID]string)
 	b.pending = make(map[structs.NamespacedID]PendingEvaluations)
 	b.cancelable = make([]*structs.Evaluation, 0, structs.MaxUUIDsPerWriteRequest)
-	b.ready = make(map[string]ReadyEvaluations)
+	b.ready = make(map[string]map[string]ReadyEvaluations)
+	b.readyOrder = make(map[string]uint64)
+	b.readySeq = 0
+	b.fairShare.reset()
 	b.unack = make(map[string]*unackEval)
 	b.timeWait = make(map[string]*time.Timer)
 	b.delayHeap = delayheap.NewDelayHeap()
@@ -928,6 +1013,7 @@ func (b *EvalBroker) Stats() *BrokerStats {
 	stats := new(BrokerStats)
 	stats.DelayedEvals = make(map[string]*structs.Evaluation)
 	stats.ByScheduler = make(map[string]*SchedulerStats)
+	stats.ByNamespace = make(map[string]*NamespaceStats)
 
 	b.l.RLock()
 	defer b.l.RUnlock()
@@ -946,6 +1032,21 @@ func (b *EvalBroker) Stats() *BrokerStats {
 		subStatCopy := *subStat
 		stats.ByScheduler[sched] = &subStatCopy
 	}
+
+	// The share of each namespace is its weight relative to the weights of
+	// all the namespaces with queued work.
+	totalWeight := 0
+	for ns := range b.stats.ByNamespace {
+		totalWeight += b.fairShare.weight(ns)
+	}
+	for ns, subStat := range b.stats.ByNamespace {
+		subStatCopy := *subStat
+		subStatCopy.Weight = b.fairShare.weight(ns)
+		if totalWeight > 0 {
+			subStatCopy.Share = float64(subStatCopy.Weight) / float64(totalWeight)
+		}
+		stats.ByNamespace[ns] = &subStatCopy
+	}
 	return stats
 }
 
@@ -996,6 +1097,13 @@ func (b *EvalBroker) EmitStats(period time.Duration, stopCh <-chan struct{}) {
 				metrics.SetGauge([]string{"nomad", "broker", sched, "ready"}, float32(schedStats.Ready))
 				metrics.SetGauge([]string{"nomad", "broker", sched, "unacked"}, float32(schedStats.Unacked))
 			}
+			for ns, nsStats := range stats.ByNamespace {
+				labels := []metrics.Label{{Name: "namespace", Value: ns}}
+				metrics.SetGaugeWithLabels([]string{"nomad", "broker", "namespace", "ready"}, float32(nsStats.Ready), labels)
+				metrics.SetGaugeWithLabels([]string{"nomad", "broker", "namespace", "unacked"}, float32(nsStats.Unacked), labels)
+				metrics.SetGaugeWithLabels([]string{"nomad", "broker", "namespace", "pending"}, float32(nsStats.Pending), labels)
+				metrics.SetGaugeWithLabels([]string{"nomad", "broker", "namespace", "share"}, float32(nsStats.Share), labels)
+			}
 
 		case <-stopCh:
 			return
@@ -1012,6 +1120,7 @@ type BrokerStats struct {
 	TotalCancelable int
 	DelayedEvals    map[string]*structs.Evaluation
 	ByScheduler     map[string]*SchedulerStats
+	ByNamespace     map[string]*NamespaceStats
 }
 
 // SchedulerStats returns the stats per scheduler
@@ -1020,6 +1129,21 @@ type SchedulerStats struct {
 	Unacked int
 }
 
+// NamespaceStats returns the stats per namespace. Weight and Share are only
+// set on the copies returned by Stats.
+type NamespaceStats struct {
+	Ready   int
+	Unacked int
+	Pending int
+
+	// Weight is the fair share weight of the namespace.
+	Weight int
+
+	// Share is the fraction of scheduler workers the namespace is entitled
+	// to, relative to the other namespaces with queued work.
+	Share float64
+}
+
 // Len is for the sorting interface
 func (r ReadyEvaluations) Len() int {
 	return len(r)
@@ -1035,6 +1159,12 @@ func (r ReadyEvaluations) Less(i, j int) bool {
 	return r[i].CreateIndex < r[j].CreateIndex
 }
 
+// readyBefore returns true if the ready evaluation a should be dequeued before
+// b, using the same ordering as ReadyEvaluations.
+func readyBefore(a, b *structs.Evaluation) bool {
+	return ReadyEvaluations{a, b}.Less(0, 1)
+}
+
 // Swap is for the sorting interface
 func (r ReadyEvaluations) Swap(i, j int) {
 	r[i], r[j] = r[j], r[i]
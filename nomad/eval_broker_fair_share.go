@@ -0,0 +1,146 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"maps"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// fairShare tracks how many evaluations were dequeued for each namespace
+// relative to its weight, so the broker can share scheduler workers between
+// namespaces using weighted fair queueing. It must be used with the broker
+// lock held.
+type fairShare struct {
+	// enabled is set if the broker picks the namespace of the next
+	// evaluation using fair share instead of only by priority.
+	enabled bool
+
+	// defaultWeight is the weight of namespaces not found in weights.
+	defaultWeight int
+
+	// weights is the weight of each namespace that sets its own.
+	weights map[string]int
+
+	// usage is the virtual time of each namespace: the number of evaluations
+	// dequeued for the namespace divided by its weight.
+	usage map[string]float64
+
+	// vtime is the virtual time of the namespace picked most recently. It's
+	// used as the starting point of namespaces that get new work, so they
+	// can't build up credit while idle and starve the others afterwards.
+	vtime float64
+}
+
+func newFairShare() *fairShare {
+	return &fairShare{
+		defaultWeight: structs.NamespaceFairShareDefaultWeight,
+		weights:       make(map[string]int),
+		usage:         make(map[string]float64),
+	}
+}
+
+// weight returns the weight of the namespace.
+func (f *fairShare) weight(namespace string) int {
+	if w, ok := f.weights[namespace]; ok && w > 0 {
+		return w
+	}
+	if f.defaultWeight > 0 {
+		return f.defaultWeight
+	}
+	return structs.NamespaceFairShareDefaultWeight
+}
+
+// activate is called when a namespace without ready evaluations gets one.
+func (f *fairShare) activate(namespace string) {
+	if f.usage[namespace] < f.vtime {
+		f.usage[namespace] = f.vtime
+	}
+}
+
+// charge records that an evaluation was dequeued for the namespace.
+func (f *fairShare) charge(namespace string) {
+	f.vtime = f.usage[namespace]
+	f.usage[namespace] += 1 / float64(f.weight(namespace))
+}
+
+// reset clears the accounting of the namespaces but keeps the configuration.
+func (f *fairShare) reset() {
+	f.usage = make(map[string]float64)
+	f.vtime = 0
+}
+
+// SetFairShare configures how the broker shares scheduler workers between
+// namespaces. If enabled, the next evaluation is taken from the namespace that
+// received the smallest share of dequeues relative to its weight, and by
+// priority within that namespace. Namespaces not found in weights use the
+// default weight.
+//
+// The usage of namespaces not found in weights is dropped once they don't
+// have evaluations in the broker anymore, so that the usage of deleted
+// namespaces isn't kept forever.
+func (b *EvalBroker) SetFairShare(enabled bool, defaultWeight int, weights map[string]int) {
+	b.l.Lock()
+	defer b.l.Unlock()
+
+	b.fairShare.enabled = enabled
+	b.fairShare.defaultWeight = defaultWeight
+	b.fairShare.weights = maps.Clone(weights)
+	if b.fairShare.weights == nil {
+		b.fairShare.weights = make(map[string]int)
+	}
+
+	for namespace := range b.fairShare.usage {
+		if _, ok := b.fairShare.weights[namespace]; ok {
+			continue
+		}
+		if _, ok := b.stats.ByNamespace[namespace]; ok {
+			continue
+		}
+		delete(b.fairShare.usage, namespace)
+	}
+}
+
+// nextFairShareNamespace returns the namespace with ready work for any of the
+// schedulers that has the smallest virtual time, or an empty string if there
+// is no ready work. Ties are broken by namespace name so the result is
+// deterministic. This assumes locks are held.
+func (b *EvalBroker) nextFairShareNamespace(schedulers []string) string {
+	var next string
+	var nextUsage float64
+	for _, sched := range schedulers {
+		for namespace, readyQueue := range b.ready[sched] {
+			if len(readyQueue) == 0 {
+				continue
+			}
+			usage := b.fairShare.usage[namespace]
+			if next == "" || usage < nextUsage || (usage == nextUsage && namespace < next) {
+				next = namespace
+				nextUsage = usage
+			}
+		}
+	}
+	return next
+}
+
+// namespaceStats returns the stats of the namespace, creating them if needed.
+// This assumes locks are held.
+func (b *EvalBroker) namespaceStats(namespace string) *NamespaceStats {
+	byNs, ok := b.stats.ByNamespace[namespace]
+	if !ok {
+		byNs = &NamespaceStats{}
+		b.stats.ByNamespace[namespace] = byNs
+	}
+	return byNs
+}
+
+// pruneNamespaceStats removes the stats of the namespace once it doesn't have
+// any evaluations left in the broker. This assumes locks are held.
+func (b *EvalBroker) pruneNamespaceStats(namespace string) {
+	byNs, ok := b.stats.ByNamespace[namespace]
+	if ok && byNs.Ready == 0 && byNs.Unacked == 0 && byNs.Pending == 0 {
+		delete(b.stats.ByNamespace, namespace)
+	}
+}
@@ -436,6 +436,7 @@ func TestEvalBroker_Serialize_DuplicateJobID(t *testing.T) {
 		stats := b.Stats()
 		stats.DelayedEvals = nil
 		stats.ByScheduler = nil
+		stats.ByNamespace = nil
 		return *stats
 	}
 
@@ -1393,6 +1394,114 @@ func TestEvalBroker_NamespacedJobs(t *testing.T) {
 
 }
 
+func TestEvalBroker_FairShare(t *testing.T) {
+	ci.Parallel(t)
+
+	newEval := func(ns string, priority int) *structs.Evaluation {
+		eval := mock.Eval()
+		eval.Namespace = ns
+		eval.Priority = priority
+		return eval
+	}
+
+	dequeueNamespaces := func(t *testing.T, b *EvalBroker, n int) []string {
+		t.Helper()
+		var namespaces []string
+		for i := 0; i < n; i++ {
+			out, _, err := b.Dequeue(defaultSched, time.Second)
+			must.NoError(t, err)
+			must.NotNil(t, out)
+			namespaces = append(namespaces, out.Namespace)
+		}
+		return namespaces
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		b := testBroker(t, 0)
+		b.SetEnabled(true)
+
+		for i := 0; i < 3; i++ {
+			b.Enqueue(newEval("noisy", 70))
+		}
+		b.Enqueue(newEval("quiet", 50))
+
+		must.Eq(t, []string{"noisy", "noisy", "noisy", "quiet"}, dequeueNamespaces(t, b, 4))
+	})
+
+	t.Run("equal weights", func(t *testing.T) {
+		b := testBroker(t, 0)
+		b.SetEnabled(true)
+		b.SetFairShare(true, 1, nil)
+
+		for i := 0; i < 3; i++ {
+			b.Enqueue(newEval("noisy", 70))
+		}
+		b.Enqueue(newEval("quiet", 50))
+
+		must.Eq(t, []string{"noisy", "quiet", "noisy", "noisy"}, dequeueNamespaces(t, b, 4))
+	})
+
+	t.Run("weighted", func(t *testing.T) {
+		b := testBroker(t, 0)
+		b.SetEnabled(true)
+		b.SetFairShare(true, 1, map[string]int{"b": 2})
+
+		for i := 0; i < 6; i++ {
+			b.Enqueue(newEval("a", 50))
+			b.Enqueue(newEval("b", 50))
+		}
+
+		must.Eq(t, []string{"a", "b", "b", "a", "b", "b"}, dequeueNamespaces(t, b, 6))
+
+		stats := b.Stats()
+		must.Eq(t, 6, stats.TotalReady)
+		must.Eq(t, 6, stats.TotalUnacked)
+		must.Eq(t, &NamespaceStats{Ready: 4, Unacked: 2, Weight: 1, Share: 1.0 / 3}, stats.ByNamespace["a"])
+		must.Eq(t, &NamespaceStats{Ready: 2, Unacked: 4, Weight: 2, Share: 2.0 / 3}, stats.ByNamespace["b"])
+	})
+
+	t.Run("idle namespaces do not build up credit", func(t *testing.T) {
+		b := testBroker(t, 0)
+		b.SetEnabled(true)
+		b.SetFairShare(true, 1, nil)
+
+		for i := 0; i < 4; i++ {
+			b.Enqueue(newEval("busy", 50))
+		}
+		must.Eq(t, []string{"busy", "busy", "busy"}, dequeueNamespaces(t, b, 3))
+
+		for i := 0; i < 3; i++ {
+			b.Enqueue(newEval("new", 50))
+		}
+		b.Enqueue(newEval("busy", 50))
+
+		// The new namespace starts at the virtual time of the last dequeue
+		// instead of zero, so they alternate instead of the new namespace
+		// taking over the workers.
+		must.Eq(t, []string{"new", "busy", "new", "busy"}, dequeueNamespaces(t, b, 4))
+	})
+
+	t.Run("usage of deleted namespaces is dropped", func(t *testing.T) {
+		b := testBroker(t, 0)
+		b.SetEnabled(true)
+		b.SetFairShare(true, 1, map[string]int{"a": 1, "b": 1})
+
+		b.Enqueue(newEval("a", 50))
+		b.Enqueue(newEval("b", 50))
+
+		out, token, err := b.Dequeue(defaultSched, time.Second)
+		must.NoError(t, err)
+		must.Eq(t, "a", out.Namespace)
+		must.NoError(t, b.Ack(out.ID, token))
+		must.Eq(t, []string{"b"}, dequeueNamespaces(t, b, 1))
+
+		// b still has an unacked evaluation so its usage is kept
+		b.SetFairShare(true, 1, nil)
+		must.MapContainsKeys(t, b.fairShare.usage, []string{"b"})
+		must.MapNotContainsKey(t, b.fairShare.usage, "a")
+	})
+}
+
 func TestEvalBroker_ReadyEvals_Ordering(t *testing.T) {
 
 	ready := ReadyEvaluations{}
@@ -1571,6 +1680,7 @@ func TestEvalBroker_IntegrationTest(t *testing.T) {
 		stats := srv.evalBroker.Stats()
 		stats.DelayedEvals = nil
 		stats.ByScheduler = nil
+		stats.ByNamespace = nil
 		return *stats
 	}
 
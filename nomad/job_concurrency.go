@@ -0,0 +1,438 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	memdb "github.com/hashicorp/go-memdb"
+	metrics "github.com/hashicorp/go-metrics/compat"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"golang.org/x/time/rate"
+)
+
+// queuedChildWatcherBackoff is the time to wait before retrying after the
+// queued child watcher fails to query the state store or apply evals.
+const queuedChildWatcherBackoff = 5 * time.Second
+
+// jobConcurrency returns the concurrency policy of the periodic or
+// parameterized job, and the number of children that may run at the same time
+// before it applies.
+func jobConcurrency(parent *structs.Job) (string, int) {
+	switch {
+	case parent.IsPeriodic():
+		return parent.Periodic.ConcurrencyPolicy(), 1
+	case parent.IsParameterized():
+		return parent.ParameterizedJob.ConcurrencyPolicy(), parent.ParameterizedJob.ConcurrencyLimit()
+	}
+	return structs.JobConcurrencyAllow, 0
+}
+
+// childJobCounts returns the number of pending or running children of the
+// parent job and the number of its queued children, as tracked by the summary
+// of the parent job.
+func childJobCounts(ws memdb.WatchSet, store *state.StateStore, parent *structs.Job) (int, int, error) {
+	summary, err := store.JobSummaryByID(ws, parent.Namespace, parent.ID)
+	if err != nil {
+		return 0, 0, err
+	}
+	if summary == nil || summary.Children == nil {
+		return 0, 0, nil
+	}
+
+	children := summary.Children
+	return int(children.Pending + children.Running), int(children.Queued), nil
+}
+
+// activeChildJobs returns the pending or running children of the parent job
+// that aren't stopped, oldest first.
+func activeChildJobs(store *state.StateStore, parent *structs.Job) ([]*structs.Job, error) {
+	iter, err := store.JobsByIDPrefix(nil, parent.Namespace, parent.ID+"/", state.SortDefault)
+	if err != nil {
+		return nil, err
+	}
+
+	var active []*structs.Job
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		child := raw.(*structs.Job)
+		if child.ParentID != parent.ID || child.Stop {
+			continue
+		}
+
+		switch child.Status {
+		case structs.JobStatusPending, structs.JobStatusRunning:
+			active = append(active, child)
+		}
+	}
+
+	slices.SortFunc(active, byCreateIndex)
+	return active, nil
+}
+
+// byCreateIndex orders jobs oldest first.
+func byCreateIndex(a, b *structs.Job) int { return cmp.Compare(a.CreateIndex, b.CreateIndex) }
+
+// applyJobConcurrency applies the concurrency policy of the parent job to the
+// child about to be launched. It returns false if the launch must be skipped,
+// and marks the child as queued if it must wait for a running sibling. Under
+// the replace policy, the oldest running siblings are stopped to make room for
+// the child. The concurrency lock of the parent must be held until the child
+// is committed, so that concurrent launches see each other.
+func (s *Server) applyJobConcurrency(parent, child *structs.Job) (bool, error) {
+	policy, limit := jobConcurrency(parent)
+	if policy == structs.JobConcurrencyAllow {
+		return true, nil
+	}
+
+	// Only the replace policy needs the running children themselves
+	if policy == structs.JobConcurrencyReplace {
+		active, err := activeChildJobs(s.State(), parent)
+		if err != nil {
+			return false, err
+		}
+		if n := len(active) - limit + 1; n > 0 {
+			if err := s.stopChildJobs(active[:n]); err != nil {
+				return false, err
+			}
+		}
+		return true, nil
+	}
+
+	active, queued, err := childJobCounts(nil, s.State(), parent)
+	if err != nil {
+		return false, err
+	}
+
+	switch policy {
+	case structs.JobConcurrencyForbid:
+		return active < limit, nil
+	case structs.JobConcurrencyQueue:
+		child.Queued = active >= limit || queued > 0 ||
+			s.dispatchLimiters.reserve(parent) > 0
+	}
+	return true, nil
+}
+
+// DispatchChildJob applies the concurrency policy of the parent job to the
+// child job and dispatches it as DispatchJob does. It returns false if the
+// policy skips the launch.
+func (s *Server) DispatchChildJob(parent, child *structs.Job) (*structs.Evaluation, bool, error) {
+	unlock := s.jobConcurrencyLocks.lock(parent.NamespacedID())
+	defer unlock()
+
+	launch, err := s.applyJobConcurrency(parent, child)
+	if err != nil || !launch {
+		return nil, false, err
+	}
+	eval, err := s.DispatchJob(child)
+	return eval, true, err
+}
+
+// jobConcurrencyLocks serializes the launches of the children of each parent
+// job, from applying the parent's concurrency policy to committing the child.
+type jobConcurrencyLocks struct {
+	l     sync.Mutex
+	locks map[structs.NamespacedID]*jobConcurrencyLock
+}
+
+// jobConcurrencyLock is the lock of a parent job, with the number of launches
+// holding or waiting for it.
+type jobConcurrencyLock struct {
+	sync.Mutex
+	refs int
+}
+
+// lock locks the launches of the children of the parent job and returns the
+// function unlocking them.
+func (j *jobConcurrencyLocks) lock(id structs.NamespacedID) func() {
+	j.l.Lock()
+	lock, ok := j.locks[id]
+	if !ok {
+		if j.locks == nil {
+			j.locks = make(map[structs.NamespacedID]*jobConcurrencyLock)
+		}
+		lock = new(jobConcurrencyLock)
+		j.locks[id] = lock
+	}
+	lock.refs++
+	j.l.Unlock()
+
+	lock.Lock()
+	return func() {
+		lock.Unlock()
+
+		j.l.Lock()
+		defer j.l.Unlock()
+		lock.refs--
+		if lock.refs == 0 {
+			delete(j.locks, id)
+		}
+	}
+}
+
+// dispatchRateLimiters holds the token buckets of the parameterized jobs whose
+// dispatch queue has a rate.
+type dispatchRateLimiters struct {
+	l        sync.Mutex
+	limiters map[structs.NamespacedID]*rate.Limiter
+}
+
+// reserve takes a token to evaluate a dispatched job of the parent job. It
+// returns zero if the dispatched job may be evaluated now, or how long to wait
+// for a token otherwise, in which case no token is taken.
+func (d *dispatchRateLimiters) reserve(parent *structs.Job) time.Duration {
+	d.l.Lock()
+	defer d.l.Unlock()
+
+	id := parent.NamespacedID()
+	if !parent.IsParameterized() || parent.ParameterizedJob.DispatchQueue == nil ||
+		parent.ParameterizedJob.DispatchQueue.Rate == 0 {
+		delete(d.limiters, id)
+		return 0
+	}
+
+	queue := parent.ParameterizedJob.DispatchQueue
+	limiter, ok := d.limiters[id]
+	if !ok {
+		if d.limiters == nil {
+			d.limiters = make(map[structs.NamespacedID]*rate.Limiter)
+		}
+		limiter = rate.NewLimiter(rate.Limit(queue.Rate), queue.BurstSize())
+		d.limiters[id] = limiter
+	} else {
+		if limiter.Limit() != rate.Limit(queue.Rate) {
+			limiter.SetLimit(rate.Limit(queue.Rate))
+		}
+		if limiter.Burst() != queue.BurstSize() {
+			limiter.SetBurst(queue.BurstSize())
+		}
+	}
+
+	r := limiter.Reserve()
+	if delay := r.Delay(); delay > 0 {
+		r.Cancel()
+		return delay
+	}
+	return 0
+}
+
+// stopChildJobs deregisters the passed jobs without purging them, and creates
+// an evaluation to stop their allocations.
+func (s *Server) stopChildJobs(jobs []*structs.Job) error {
+	for _, job := range jobs {
+		now := time.Now().UnixNano()
+		req := &structs.JobDeregisterRequest{
+			JobID: job.ID,
+			Eval: &structs.Evaluation{
+				ID:          uuid.Generate(),
+				Namespace:   job.Namespace,
+				Priority:    job.Priority,
+				Type:        job.Type,
+				TriggeredBy: structs.EvalTriggerJobDeregister,
+				JobID:       job.ID,
+				Status:      structs.EvalStatusPending,
+				CreateTime:  now,
+				ModifyTime:  now,
+			},
+			SubmitTime: now,
+			WriteRequest: structs.WriteRequest{
+				Namespace: job.Namespace,
+			},
+		}
+		if _, _, err := s.raftApply(structs.JobDeregisterRequestType, req); err != nil {
+			return fmt.Errorf("failed to stop job %q: %v", job.NamespacedID(), err)
+		}
+		s.logger.Debug("stopped child job replaced by a new launch", "job", job.NamespacedID())
+	}
+	return nil
+}
+
+// watchQueuedChildren is a long lived function that watches the jobs table
+// for queued children of periodic and parameterized jobs, and creates an
+// evaluation for them once their parent's concurrency limit allows.
+func (s *Server) watchQueuedChildren(stopCh chan struct{}) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-stopCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	var index uint64 = 1
+	depths := make(map[structs.NamespacedID]struct{})
+	for {
+		resp, newIndex, err := s.State().BlockingQuery(readyQueuedChildren, index, ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			s.logger.Error("failed to find queued child jobs", "error", err)
+			select {
+			case <-stopCh:
+				return
+			case <-time.After(queuedChildWatcherBackoff):
+				continue
+			}
+		}
+		index = newIndex
+
+		// Release the ready children as the rate of their parent allows, and
+		// retry once the next token is available if it doesn't
+		now := time.Now().UTC()
+		var evals []*structs.Evaluation
+		var wait time.Duration
+		seen := make(map[structs.NamespacedID]struct{}, len(depths))
+		for _, group := range resp.([]*queuedChildGroup) {
+			released := 0
+			labels := []metrics.Label{
+				{Name: "job", Value: group.parent.ID},
+				{Name: "namespace", Value: group.parent.Namespace},
+			}
+			for _, job := range group.ready {
+				if delay := s.dispatchLimiters.reserve(group.parent); delay > 0 {
+					if wait == 0 || delay < wait {
+						wait = delay
+					}
+					break
+				}
+
+				evals = append(evals, &structs.Evaluation{
+					ID:             uuid.Generate(),
+					Namespace:      job.Namespace,
+					Priority:       job.Priority,
+					Type:           job.Type,
+					TriggeredBy:    structs.EvalTriggerQueuedChild,
+					JobID:          job.ID,
+					JobModifyIndex: job.ModifyIndex,
+					Status:         structs.EvalStatusPending,
+					CreateTime:     now.UnixNano(),
+					ModifyTime:     now.UnixNano(),
+				})
+				metrics.MeasureSinceWithLabels([]string{"nomad", "job_queue", "wait_time"},
+					time.Unix(0, job.SubmitTime), labels)
+				released++
+			}
+
+			id := group.parent.NamespacedID()
+			seen[id] = struct{}{}
+			depths[id] = struct{}{}
+			metrics.SetGaugeWithLabels([]string{"nomad", "job_queue", "depth"},
+				float32(group.queued-released), labels)
+		}
+
+		// Reset the depth of the queues that were drained
+		for id := range depths {
+			if _, ok := seen[id]; !ok {
+				metrics.SetGaugeWithLabels([]string{"nomad", "job_queue", "depth"}, 0, []metrics.Label{
+					{Name: "job", Value: id.ID},
+					{Name: "namespace", Value: id.Namespace},
+				})
+				delete(depths, id)
+			}
+		}
+
+		if len(evals) > 0 {
+			req := structs.EvalUpdateRequest{
+				Evals: evals,
+			}
+			if _, _, err := s.raftApply(structs.EvalUpdateRequestType, &req); err != nil {
+				s.logger.Error("failed to create evals for queued child jobs", "error", err)
+				select {
+				case <-stopCh:
+					return
+				case <-time.After(queuedChildWatcherBackoff):
+				}
+				continue
+			}
+		}
+
+		if wait > 0 {
+			select {
+			case <-stopCh:
+				return
+			case <-time.After(wait):
+				// Query the state again without blocking
+				index = 0
+			}
+		}
+	}
+}
+
+// queuedChildGroup is the set of queued children of a parent job.
+type queuedChildGroup struct {
+	parent *structs.Job
+
+	// ready are the queued children that fit within the parent's concurrency
+	// limit, oldest first.
+	ready []*structs.Job
+
+	// queued is the number of queued children.
+	queued int
+}
+
+// readyQueuedChildren is a blocking query function that returns the queued
+// children of periodic and parameterized jobs grouped by parent, with the ones
+// that fit within their parent's concurrency limit. The children of stopped or
+// deleted parents stay queued.
+func readyQueuedChildren(ws memdb.WatchSet, store *state.StateStore) (interface{}, uint64, error) {
+	iter, err := store.JobsQueued(ws)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	children := make(map[structs.NamespacedID][]*structs.Job)
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		job := raw.(*structs.Job)
+		id := structs.NewNamespacedID(job.ParentID, job.Namespace)
+		children[id] = append(children[id], job)
+	}
+
+	var groups []*queuedChildGroup
+	for id, queued := range children {
+		parent, err := store.JobByID(ws, id.Namespace, id.ID)
+		if err != nil {
+			return nil, 0, err
+		}
+		if parent == nil || parent.Stop {
+			continue
+		}
+		slices.SortFunc(queued, byCreateIndex)
+
+		// Launch every queued child if the parent no longer queues them
+		policy, limit := jobConcurrency(parent)
+		free := len(queued)
+		if policy == structs.JobConcurrencyQueue {
+			active, _, err := childJobCounts(ws, store, parent)
+			if err != nil {
+				return nil, 0, err
+			}
+			free = min(limit-active, len(queued))
+		}
+		group := &queuedChildGroup{parent: parent, queued: len(queued)}
+		if free > 0 {
+			group.ready = queued[:free]
+		}
+		groups = append(groups, group)
+	}
+
+	index, err := store.Index("jobs")
+	if err != nil {
+		return nil, 0, err
+	}
+	summaryIndex, err := store.Index("job_summary")
+	if err != nil {
+		return nil, 0, err
+	}
+	return groups, max(index, summaryIndex), nil
+}
@@ -0,0 +1,203 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/shoenig/test/must"
+)
+
+func TestReadyQueuedChildren(t *testing.T) {
+	ci.Parallel(t)
+
+	store := state.TestStateStore(t)
+
+	parent := mock.BatchJob()
+	parent.ParameterizedJob = &structs.ParameterizedJobConfig{
+		Concurrency:   structs.JobConcurrencyQueue,
+		MaxConcurrent: 2,
+	}
+	must.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 1000, nil, parent))
+
+	child := func(index uint64, queued bool) *structs.Job {
+		job := parent.Copy()
+		job.ID = structs.DispatchedID(parent.ID, "", time.Now())
+		job.ParentID = parent.ID
+		job.Dispatched = true
+		job.Queued = queued
+		job.Status = ""
+		must.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, index, nil, job))
+		return job
+	}
+
+	// One running child leaves room for the oldest queued child
+	running := child(1001, false)
+	queued1 := child(1002, true)
+	queued2 := child(1003, true)
+
+	eval := mock.Eval()
+	eval.JobID = running.ID
+	must.NoError(t, store.UpsertEvals(structs.MsgTypeTestSetup, 1004, []*structs.Evaluation{eval}))
+
+	resp, _, err := readyQueuedChildren(nil, store)
+	must.NoError(t, err)
+	groups := resp.([]*queuedChildGroup)
+	must.Len(t, 1, groups)
+	must.Eq(t, parent.ID, groups[0].parent.ID)
+	must.Eq(t, 2, groups[0].queued)
+	must.Len(t, 1, groups[0].ready)
+	must.Eq(t, queued1.ID, groups[0].ready[0].ID)
+
+	// Without the queue policy every queued child is launched
+	parent = parent.Copy()
+	parent.ParameterizedJob.Concurrency = structs.JobConcurrencyAllow
+	parent.ParameterizedJob.MaxConcurrent = 0
+	must.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 1005, nil, parent))
+
+	resp, _, err = readyQueuedChildren(nil, store)
+	must.NoError(t, err)
+	groups = resp.([]*queuedChildGroup)
+	must.Len(t, 1, groups)
+	must.Len(t, 2, groups[0].ready)
+	must.Eq(t, queued1.ID, groups[0].ready[0].ID)
+	must.Eq(t, queued2.ID, groups[0].ready[1].ID)
+}
+
+func TestDispatchRateLimiters_Reserve(t *testing.T) {
+	ci.Parallel(t)
+
+	var limiters dispatchRateLimiters
+
+	// Jobs without a dispatch queue rate are never limited
+	parent := mock.BatchJob()
+	parent.ParameterizedJob = &structs.ParameterizedJobConfig{
+		DispatchQueue: &structs.DispatchQueueConfig{MaxRunning: 1},
+	}
+	for i := 0; i < 5; i++ {
+		must.Eq(t, 0, limiters.reserve(parent))
+	}
+
+	// The burst is available at once, and the next token after the rate
+	parent.ParameterizedJob.DispatchQueue.Rate = 0.1
+	parent.ParameterizedJob.DispatchQueue.Burst = 2
+	must.Eq(t, 0, limiters.reserve(parent))
+	must.Eq(t, 0, limiters.reserve(parent))
+	delay := limiters.reserve(parent)
+	must.Greater(t, 9*time.Second, delay)
+	must.LessEq(t, 10*time.Second, delay)
+
+	// Waiting for a token doesn't take it
+	must.Greater(t, 9*time.Second, limiters.reserve(parent))
+}
+
+func TestServer_DispatchChildJob(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0
+	})
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	derive := func(parent *structs.Job, i int) *structs.Job {
+		child := parent.Copy()
+		child.ID = fmt.Sprintf("%s%s%d", parent.ID, structs.PeriodicLaunchSuffix, i)
+		child.ParentID = parent.ID
+		child.Periodic = nil
+		child.Status = ""
+		return child
+	}
+
+	t.Run("forbid", func(t *testing.T) {
+		parent := mock.PeriodicJob()
+		parent.Periodic.Concurrency = structs.JobConcurrencyForbid
+		must.NoError(t, s1.State().UpsertJob(structs.MsgTypeTestSetup, 1000, nil, parent))
+
+		_, ok, err := s1.DispatchChildJob(parent, derive(parent, 1))
+		must.NoError(t, err)
+		must.True(t, ok)
+
+		_, ok, err = s1.DispatchChildJob(parent, derive(parent, 2))
+		must.NoError(t, err)
+		must.False(t, ok)
+	})
+
+	t.Run("queue", func(t *testing.T) {
+		parent := mock.PeriodicJob()
+		parent.Periodic.Concurrency = structs.JobConcurrencyQueue
+		must.NoError(t, s1.State().UpsertJob(structs.MsgTypeTestSetup, 1001, nil, parent))
+
+		first := derive(parent, 1)
+		_, ok, err := s1.DispatchChildJob(parent, first)
+		must.NoError(t, err)
+		must.True(t, ok)
+		must.False(t, first.Queued)
+
+		second := derive(parent, 2)
+		_, ok, err = s1.DispatchChildJob(parent, second)
+		must.NoError(t, err)
+		must.True(t, ok)
+		must.True(t, second.Queued)
+
+		// A launch queues behind the queued sibling even once the running
+		// sibling is stopped
+		must.NoError(t, s1.stopChildJobs([]*structs.Job{first}))
+		third := derive(parent, 3)
+		_, ok, err = s1.DispatchChildJob(parent, third)
+		must.NoError(t, err)
+		must.True(t, ok)
+		must.True(t, third.Queued)
+	})
+}
+
+func TestJobConcurrencyLocks(t *testing.T) {
+	ci.Parallel(t)
+
+	var locks jobConcurrencyLocks
+	a := structs.NewNamespacedID("a", structs.DefaultNamespace)
+	b := structs.NewNamespacedID("b", structs.DefaultNamespace)
+
+	unlockA := locks.lock(a)
+
+	// The launches of another parent aren't blocked
+	unlockB := locks.lock(b)
+	unlockB()
+
+	// The launches of the same parent wait for the lock
+	locked := make(chan struct{})
+	go func() {
+		unlock := locks.lock(a)
+		close(locked)
+		unlock()
+	}()
+
+	select {
+	case <-locked:
+		t.Fatal("expected the second lock to wait")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	unlockA()
+	select {
+	case <-locked:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the second lock to be taken")
+	}
+
+	testutil.WaitForResult(func() (bool, error) {
+		locks.l.Lock()
+		defer locks.l.Unlock()
+		return len(locks.locks) == 0, fmt.Errorf("expected the locks to be released: %v", locks.locks)
+	}, func(err error) {
+		t.Fatal(err)
+	})
+}
@@ -0,0 +1,161 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"context"
+	"time"
+
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// jobDependencyWatcherBackoff is the time to wait before retrying after the
+// job dependency watcher fails to query the state store or apply evals.
+const jobDependencyWatcherBackoff = 5 * time.Second
+
+// watchJobDependencies is a long lived function that watches the jobs table
+// for batch jobs waiting on other jobs, and creates an evaluation for them
+// once all of their dependencies are satisfied.
+func (s *Server) watchJobDependencies(stopCh chan struct{}) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-stopCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	var index uint64 = 1
+	for {
+		resp, newIndex, err := s.State().BlockingQuery(readyDependentJobs, index, ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			s.logger.Error("failed to find jobs with satisfied dependencies", "error", err)
+			select {
+			case <-stopCh:
+				return
+			case <-time.After(jobDependencyWatcherBackoff):
+				continue
+			}
+		}
+		index = newIndex
+
+		jobs := resp.([]*structs.Job)
+		if len(jobs) == 0 {
+			continue
+		}
+
+		now := time.Now().UTC().UnixNano()
+		evals := make([]*structs.Evaluation, 0, len(jobs))
+		for _, job := range jobs {
+			evals = append(evals, &structs.Evaluation{
+				ID:             uuid.Generate(),
+				Namespace:      job.Namespace,
+				Priority:       job.Priority,
+				Type:           job.Type,
+				TriggeredBy:    structs.EvalTriggerJobDependency,
+				JobID:          job.ID,
+				JobModifyIndex: job.ModifyIndex,
+				Status:         structs.EvalStatusPending,
+				CreateTime:     now,
+				ModifyTime:     now,
+			})
+		}
+
+		req := structs.EvalUpdateRequest{
+			Evals: evals,
+		}
+		if _, _, err := s.raftApply(structs.EvalUpdateRequestType, &req); err != nil {
+			s.logger.Error("failed to create evals for jobs with satisfied dependencies", "error", err)
+			select {
+			case <-stopCh:
+				return
+			case <-time.After(jobDependencyWatcherBackoff):
+			}
+		}
+	}
+}
+
+// readyDependentJobs is a blocking query function that returns the batch jobs
+// that have never been placed, aren't being evaluated, and whose dependencies
+// are all satisfied. Only the jobs waiting on dependencies are read, and the
+// query is woken up by changes to their evals and to their upstream jobs.
+func readyDependentJobs(ws memdb.WatchSet, store *state.StateStore) (interface{}, uint64, error) {
+	iter, err := store.JobsWaitingOnDependencies(ws)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var ready []*structs.Job
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		job := raw.(*structs.Job)
+		ok, err := jobDependenciesReady(ws, store, job)
+		if err != nil {
+			return nil, 0, err
+		}
+		if ok {
+			ready = append(ready, job)
+		}
+	}
+
+	index, err := store.LatestIndex()
+	if err != nil {
+		return nil, 0, err
+	}
+	return ready, index, nil
+}
+
+// jobDependenciesReady returns true if the job should be evaluated because
+// all of its dependencies are satisfied and it wasn't already evaluated since
+// they were.
+func jobDependenciesReady(ws memdb.WatchSet, store *state.StateStore, job *structs.Job) (bool, error) {
+	// lastChange is the last index at which an upstream job or its
+	// allocations changed
+	var lastChange uint64
+	for _, dep := range job.DependsOn {
+		upstream, err := store.JobByID(ws, job.Namespace, dep.JobID)
+		if err != nil {
+			return false, err
+		}
+		summary, err := store.JobSummaryByID(ws, job.Namespace, dep.JobID)
+		if err != nil {
+			return false, err
+		}
+		if dep.Status(upstream, summary) != structs.JobDependencyStatusSatisfied {
+			return false, nil
+		}
+		if upstream != nil {
+			lastChange = max(lastChange, upstream.ModifyIndex)
+		}
+		if summary != nil {
+			lastChange = max(lastChange, summary.ModifyIndex)
+		}
+	}
+
+	evals, err := store.EvalsByJob(ws, job.Namespace, job.ID)
+	if err != nil {
+		return false, err
+	}
+	for _, eval := range evals {
+		if !eval.TerminalStatus() {
+			return false, nil
+		}
+
+		// A job whose eval placed nothing, such as when it's blocked, must
+		// not be evaluated again until its upstream jobs change
+		if eval.TriggeredBy == structs.EvalTriggerJobDependency &&
+			eval.JobModifyIndex >= job.JobModifyIndex && eval.CreateIndex > lastChange {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
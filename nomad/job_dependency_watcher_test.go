@@ -0,0 +1,73 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+func TestJobDependencyWatcher_readyDependentJobs(t *testing.T) {
+	ci.Parallel(t)
+
+	store := state.TestStateStore(t)
+	index := uint64(100)
+
+	ready := func() []*structs.Job {
+		t.Helper()
+		resp, _, err := readyDependentJobs(nil, store)
+		must.NoError(t, err)
+		return resp.([]*structs.Job)
+	}
+
+	upstream := mock.BatchJob()
+	must.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, index, nil, upstream))
+
+	downstream := mock.BatchJob()
+	downstream.DependsOn = []*structs.JobDependency{{
+		JobID:     upstream.ID,
+		Condition: structs.JobDependencyConditionComplete,
+	}}
+	index++
+	must.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, index, nil, downstream))
+
+	// The upstream job is still pending
+	must.SliceEmpty(t, ready())
+
+	stopped := upstream.Copy()
+	stopped.Stop = true
+	index++
+	must.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, index, nil, stopped))
+
+	out := ready()
+	must.Len(t, 1, out)
+	must.Eq(t, downstream.ID, out[0].ID)
+
+	// Once evaluated, the job isn't evaluated again even if its eval didn't
+	// place anything
+	downstream, err := store.JobByID(nil, downstream.Namespace, downstream.ID)
+	must.NoError(t, err)
+	eval := mock.Eval()
+	eval.JobID = downstream.ID
+	eval.Type = structs.JobTypeBatch
+	eval.TriggeredBy = structs.EvalTriggerJobDependency
+	eval.JobModifyIndex = downstream.JobModifyIndex
+	eval.Status = structs.EvalStatusComplete
+	index++
+	must.NoError(t, store.UpsertEvals(structs.MsgTypeTestSetup, index, []*structs.Evaluation{eval}))
+	must.SliceEmpty(t, ready())
+
+	// A change to the upstream job evaluates it again
+	stopped = stopped.Copy()
+	stopped.Meta = map[string]string{"version": uuid.Generate()}
+	index++
+	must.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, index, nil, stopped))
+	must.Len(t, 1, ready())
+}
@@ -75,6 +75,7 @@ func NewJobEndpoints(s *Server, ctx *RPCContext) *Job {
 			jobExposeCheckHook{},
 			jobImpliedConstraints{},
 			jobNodePoolMutatingHook{srv: s},
+			jobPriorityClassHook{srv: s},
 			jobImplicitIdentitiesHook{srv: s},
 			jobNumaHook{},
 		},
@@ -85,6 +86,8 @@ func NewJobEndpoints(s *Server, ctx *RPCContext) *Job {
 			jobConsulHook{srv: s},
 			jobNamespaceConstraintCheckHook{srv: s},
 			jobNodePoolValidatingHook{srv: s},
+			jobNetworkPolicyHook{srv: s},
+			jobDependencyHook{srv: s},
 			&jobValidate{srv: s},
 			&memoryOversubscriptionValidate{srv: s},
 			jobNumaHook{},
@@ -195,6 +198,17 @@ func (j *Job) Register(args *structs.JobRegisterRequest, reply *structs.JobRegis
 			}
 		}
 
+		// Deployment hooks running a parameterized job dispatch it on behalf
+		// of the submitter
+		if tg.Update != nil {
+			for _, hook := range []*structs.DeploymentHook{tg.Update.PreDeploy, tg.Update.PostDeploy} {
+				if hook != nil && hook.Job != "" &&
+					!aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityDispatchJob) {
+					return structs.ErrPermissionDenied
+				}
+			}
+		}
+
 		// Check if override is set and we do not have permissions
 		if args.PolicyOverride {
 			if !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilitySentinelOverride) {
@@ -514,6 +528,104 @@ func (j *Job) Summary(args *structs.JobSummaryRequest, reply *structs.JobSummary
 	return j.srv.blockingRPC(&opts)
 }
 
+// Dependencies returns the status of the dependencies of a job, along with
+// the jobs that depend on it.
+func (j *Job) Dependencies(args *structs.JobDependenciesRequest, reply *structs.JobDependenciesResponse) error {
+	authErr := j.srv.Authenticate(j.ctx, args)
+	if done, err := j.srv.forward("Job.Dependencies", args, args, reply); done {
+		return err
+	}
+	j.srv.MeasureRPCRate("job", structs.RateMetricRead, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+	defer metrics.MeasureSince([]string{"nomad", "job", "dependencies"}, time.Now())
+
+	// Check for read-job permissions
+	if aclObj, err := j.srv.ResolveACL(args); err != nil {
+		return err
+	} else if !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, store *state.StateStore) error {
+			namespace := args.RequestNamespace()
+			deps := &structs.JobDependencies{
+				JobID:     args.JobID,
+				Namespace: namespace,
+			}
+
+			job, err := store.JobByID(ws, namespace, args.JobID)
+			if err != nil {
+				return err
+			}
+			if job != nil {
+				for _, dep := range job.DependsOn {
+					upstream, err := store.JobByID(ws, namespace, dep.JobID)
+					if err != nil {
+						return err
+					}
+					summary, err := store.JobSummaryByID(ws, namespace, dep.JobID)
+					if err != nil {
+						return err
+					}
+
+					depState := &structs.JobDependencyState{
+						JobID:     dep.JobID,
+						Condition: dep.Condition,
+						Status:    dep.Status(upstream, summary),
+					}
+					if upstream != nil {
+						depState.JobStatus = upstream.Status
+					}
+					deps.Dependencies = append(deps.Dependencies, depState)
+				}
+			}
+
+			// Find the jobs that depend on this job
+			summary, err := store.JobSummaryByID(ws, namespace, args.JobID)
+			if err != nil {
+				return err
+			}
+			iter, err := store.JobsByNamespace(ws, namespace, state.SortDefault)
+			if err != nil {
+				return err
+			}
+			for raw := iter.Next(); raw != nil; raw = iter.Next() {
+				downstream := raw.(*structs.Job)
+				for _, dep := range downstream.DependsOn {
+					if dep.JobID != args.JobID {
+						continue
+					}
+					deps.Dependents = append(deps.Dependents, &structs.JobDependencyState{
+						JobID:     downstream.ID,
+						Condition: dep.Condition,
+						JobStatus: downstream.Status,
+						Status:    dep.Status(job, summary),
+					})
+				}
+			}
+
+			reply.JobDependencies = deps
+
+			// Use the last index that affected the jobs table
+			index, err := store.Index("jobs")
+			if err != nil {
+				return err
+			}
+			reply.Index = index
+
+			// Set the query response
+			j.srv.setQueryMeta(&reply.QueryMeta)
+			return nil
+		}}
+	return j.srv.blockingRPC(&opts)
+}
+
 // Validate validates a job.
 //
 // Must forward to the leader, because only the leader will have a live Vault
@@ -1883,6 +1995,7 @@ func (j *Job) Plan(args *structs.JobPlanRequest, reply *structs.JobPlanResponse)
 		JobModifyIndex: updatedIndex,
 		Status:         structs.EvalStatusPending,
 		AnnotatePlan:   true,
+		ExplainPlan:    args.Explain,
 		// Timestamps are added for consistency but this eval is never persisted
 		CreateTime: now,
 		ModifyTime: now,
@@ -1938,6 +2051,18 @@ func (j *Job) Plan(args *structs.JobPlanRequest, reply *structs.JobPlanResponse)
 		}
 	}
 
+	// Grab the explained placements
+	if args.Explain {
+		reply.PlacementMetrics = make(map[string]*structs.AllocMetric)
+		for _, allocs := range planner.Plans[0].NodeAllocation {
+			for _, alloc := range allocs {
+				if alloc.Metrics != nil {
+					reply.PlacementMetrics[alloc.Name] = alloc.Metrics
+				}
+			}
+		}
+	}
+
 	reply.FailedTGAllocs = updatedEval.FailedTGAllocs
 	reply.JobModifyIndex = index
 	reply.Annotations = annotations
@@ -2099,6 +2224,20 @@ func (j *Job) Dispatch(args *structs.JobDispatchRequest, reply *structs.JobDispa
 	// Compress the payload
 	dispatchJob.Payload = snappy.Encode(nil, args.Payload)
 
+	// Apply the concurrency policy of the parameterized job, which may queue
+	// the dispatched job or stop its running siblings
+	unlock := j.srv.jobConcurrencyLocks.lock(parameterizedJob.NamespacedID())
+	defer unlock()
+	launch, err := j.srv.applyJobConcurrency(parameterizedJob, dispatchJob)
+	if err != nil {
+		j.logger.Error("failed to apply concurrency policy", "error", err, "method", "dispatch")
+		return err
+	}
+	if !launch {
+		return fmt.Errorf("Parameterized job %q has reached its limit of %d running dispatched jobs",
+			args.JobID, parameterizedJob.ParameterizedJob.ConcurrencyLimit())
+	}
+
 	regReq := &structs.JobRegisterRequest{
 		Job:          dispatchJob,
 		WriteRequest: args.WriteRequest,
@@ -2113,10 +2252,11 @@ func (j *Job) Dispatch(args *structs.JobDispatchRequest, reply *structs.JobDispa
 
 	reply.JobCreateIndex = jobCreateIndex
 	reply.DispatchedJobID = dispatchJob.ID
+	reply.Queued = dispatchJob.Queued
 	reply.Index = jobCreateIndex
 
-	// If the job is periodic, we don't create an eval.
-	if !dispatchJob.IsPeriodic() {
+	// If the job is periodic or queued, we don't create an eval.
+	if !dispatchJob.IsPeriodic() && !dispatchJob.Queued {
 		// Create a new evaluation
 		now := time.Now().UnixNano()
 		eval := &structs.Evaluation{
@@ -0,0 +1,47 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// jobNetworkPolicyHook is an admission hook that warns about task groups in
+// bridge networking mode whose network policy allows all traffic while the
+// policy of the job namespace denies it. Clients merge the namespace policy
+// with the policy of the groups when enforcing it, so the namespace policy
+// applies to jobs registered before it's set.
+type jobNetworkPolicyHook struct {
+	srv *Server
+}
+
+func (jobNetworkPolicyHook) Name() string {
+	return "network-policy"
+}
+
+func (h jobNetworkPolicyHook) Validate(job *structs.Job) ([]error, error) {
+	ns, err := h.srv.State().NamespaceByName(nil, job.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	if ns == nil || !ns.NetworkPolicy.Denies() {
+		return nil, nil
+	}
+
+	var warnings []error
+	for _, tg := range job.TaskGroups {
+		if len(tg.Networks) == 0 || tg.Networks[0].Mode != "bridge" {
+			continue
+		}
+		if tg.NetworkPolicy != nil && tg.NetworkPolicy.Default == structs.NetworkPolicyDefaultAllow {
+			warnings = append(warnings, fmt.Errorf(
+				"group %q network policy allows all traffic but namespace %q denies it by default",
+				tg.Name, job.Namespace))
+		}
+	}
+
+	return warnings, nil
+}
@@ -0,0 +1,86 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/shoenig/test/must"
+)
+
+func TestJobNetworkPolicyHook_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	srv, cleanup := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0
+	})
+	t.Cleanup(cleanup)
+	testutil.WaitForLeader(t, srv.RPC)
+
+	isolated := mock.Namespace()
+	isolated.Name = "isolated"
+	isolated.NetworkPolicy = &structs.NetworkPolicy{
+		Default: structs.NetworkPolicyDefaultDeny,
+		Allow: []*structs.NetworkPolicyRule{
+			{Namespace: "ops", Job: "prometheus"},
+		},
+	}
+	must.NoError(t, srv.State().UpsertNamespaces(1000, []*structs.Namespace{isolated}))
+
+	hook := jobNetworkPolicyHook{srv}
+
+	allow := &structs.NetworkPolicy{Default: structs.NetworkPolicyDefaultAllow}
+	testCases := []struct {
+		name        string
+		namespace   string
+		mode        string
+		policy      *structs.NetworkPolicy
+		expWarnings int
+	}{
+		{
+			name:      "namespace without policy",
+			namespace: structs.DefaultNamespace,
+			mode:      "bridge",
+			policy:    allow,
+		},
+		{
+			name:      "host networking",
+			namespace: "isolated",
+			mode:      "host",
+			policy:    allow,
+		},
+		{
+			name:      "group without policy",
+			namespace: "isolated",
+			mode:      "bridge",
+		},
+		{
+			name:        "group allows by default",
+			namespace:   "isolated",
+			mode:        "bridge",
+			policy:      allow,
+			expWarnings: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			job := mock.Job()
+			job.Namespace = tc.namespace
+			job.TaskGroups[0].Networks = structs.Networks{{Mode: tc.mode}}
+			job.TaskGroups[0].NetworkPolicy = tc.policy
+
+			warnings, err := hook.Validate(job)
+			must.NoError(t, err)
+			must.Len(t, tc.expWarnings, warnings)
+
+			// The namespace policy isn't copied into the job
+			must.Eq(t, tc.policy, job.TaskGroups[0].NetworkPolicy)
+		})
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// jobPriorityClassHook is an admission hook that sets the priority of jobs
+// that reference a priority class to the priority of the class.
+type jobPriorityClassHook struct {
+	srv *Server
+}
+
+func (jobPriorityClassHook) Name() string {
+	return "priority-class"
+}
+
+func (h jobPriorityClassHook) Mutate(job *structs.Job) (*structs.Job, []error, error) {
+	if job.PriorityClass == "" {
+		return job, nil, nil
+	}
+
+	_, schedConfig, err := h.srv.State().SchedulerConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return mutatePriorityClass(job, schedConfig, h.srv.GetConfig().JobDefaultPriority)
+}
+
+// mutatePriorityClass sets the priority of the job to the priority of its
+// class in the given scheduler configuration. The default job priority of the
+// servers is only used to warn jobs that set their own priority.
+func mutatePriorityClass(job *structs.Job, schedConfig *structs.SchedulerConfiguration, defaultPriority int) (*structs.Job, []error, error) {
+	if job.PriorityClass == "" {
+		return job, nil, nil
+	}
+
+	class := schedConfig.LookupPriorityClass(job.PriorityClass)
+	if class == nil {
+		return nil, nil, fmt.Errorf("job %q uses nonexistent priority class %q", job.ID, job.PriorityClass)
+	}
+	if !class.AllowsNamespace(job.Namespace) {
+		return nil, nil, fmt.Errorf("priority class %q can't be used by jobs in namespace %q", class.Name, job.Namespace)
+	}
+
+	// Jobs that set their own priority are likely to be unaware that the
+	// priority class overrides it.
+	var warnings []error
+	if job.Priority != class.Priority &&
+		job.Priority != structs.JobDefaultPriority &&
+		job.Priority != defaultPriority {
+		warnings = append(warnings, fmt.Errorf(
+			"job priority %d is replaced by the priority %d of priority class %q",
+			job.Priority, class.Priority, class.Name))
+	}
+
+	job.Priority = class.Priority
+	return job, warnings, nil
+}
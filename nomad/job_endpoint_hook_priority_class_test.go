@@ -0,0 +1,114 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/shoenig/test/must"
+)
+
+func TestJobPriorityClassHook_Mutate(t *testing.T) {
+	ci.Parallel(t)
+
+	srv, cleanup := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0
+	})
+	t.Cleanup(cleanup)
+	testutil.WaitForLeader(t, srv.RPC)
+
+	_, schedConfig, err := srv.State().SchedulerConfig()
+	must.NoError(t, err)
+	schedConfig = schedConfig.Copy()
+	schedConfig.PriorityClasses = []*structs.PriorityClass{
+		{
+			Name:             "critical",
+			Priority:         20,
+			PreemptionPolicy: structs.PriorityClassPreemptionPolicyLowerPriority,
+			NonPreemptible:   true,
+		},
+		{
+			Name:             "team",
+			Priority:         70,
+			PreemptionPolicy: structs.PriorityClassPreemptionPolicyNever,
+			Namespaces:       []string{"team"},
+		},
+	}
+	must.NoError(t, srv.State().SchedulerSetConfig(1000, schedConfig))
+
+	hook := jobPriorityClassHook{srv}
+
+	testCases := []struct {
+		name        string
+		class       string
+		namespace   string
+		priority    int
+		expPriority int
+		expWarnings int
+		expErr      string
+	}{
+		{
+			name:        "no priority class",
+			namespace:   structs.DefaultNamespace,
+			priority:    60,
+			expPriority: 60,
+		},
+		{
+			name:        "default priority",
+			class:       "critical",
+			namespace:   structs.DefaultNamespace,
+			priority:    structs.JobDefaultPriority,
+			expPriority: 20,
+		},
+		{
+			name:        "priority replaced",
+			class:       "critical",
+			namespace:   structs.DefaultNamespace,
+			priority:    90,
+			expPriority: 20,
+			expWarnings: 1,
+		},
+		{
+			name:        "allowed namespace",
+			class:       "team",
+			namespace:   "team",
+			priority:    structs.JobDefaultPriority,
+			expPriority: 70,
+		},
+		{
+			name:      "disallowed namespace",
+			class:     "team",
+			namespace: structs.DefaultNamespace,
+			expErr:    `can't be used by jobs in namespace "default"`,
+		},
+		{
+			name:      "nonexistent",
+			class:     "unknown",
+			namespace: structs.DefaultNamespace,
+			expErr:    `nonexistent priority class "unknown"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			job := mock.Job()
+			job.Namespace = tc.namespace
+			job.Priority = tc.priority
+			job.PriorityClass = tc.class
+
+			out, warnings, err := hook.Mutate(job)
+			if tc.expErr != "" {
+				must.ErrorContains(t, err, tc.expErr)
+				return
+			}
+			must.NoError(t, err)
+			must.Len(t, tc.expWarnings, warnings)
+			must.Eq(t, tc.expPriority, out.Priority)
+		})
+	}
+}
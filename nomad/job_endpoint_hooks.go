@@ -12,6 +12,7 @@ import (
 	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/lib/lang"
+	"github.com/hashicorp/nomad/nomad/state"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
 
@@ -26,6 +27,7 @@ const (
 	attrHostLocalCNI      = `${attr.plugins.cni.version.host-local}`
 	attrLoopbackCNI       = `${attr.plugins.cni.version.loopback}`
 	attrPortMapCNI        = `${attr.plugins.cni.version.portmap}`
+	attrBandwidthCNI      = `${attr.plugins.cni.version.bandwidth}`
 	attrConsulCNI         = `${attr.plugins.cni.version.consul-cni}`
 )
 
@@ -136,6 +138,15 @@ var (
 		Operand: structs.ConstraintSemver,
 	}
 
+	// cniBandwidthConstraint is an implicit constraint added to jobs making use
+	// of bridge or CNI networking mode with a bandwidth limit. The bandwidth
+	// plugin enforces the limit.
+	cniBandwidthConstraint = &structs.Constraint{
+		LTarget: attrBandwidthCNI,
+		RTarget: cniMinVersion,
+		Operand: structs.ConstraintSemver,
+	}
+
 	// cniConsulConstraint is an implicit constraint added to jobs making use of
 	// transparent proxy mode.
 	cniConsulConstraint = &structs.Constraint{
@@ -228,6 +239,44 @@ func (j *Job) admissionValidators(origJob *structs.Job) ([]error, error) {
 
 }
 
+// MutateSimulatedJob applies the job admission mutators to a job registered
+// in a scheduler simulation, with the given state store standing in for the
+// state of the servers. Mutators that depend on the configuration of the
+// servers, such as the implicit workload identities, are skipped, and the
+// default job priority is assumed.
+func MutateSimulatedJob(store *state.StateStore, job *structs.Job) (*structs.Job, []error, error) {
+	_, schedConfig, err := store.SchedulerConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	job.Canonicalize()
+	if job.Priority == 0 {
+		job.Priority = structs.JobDefaultPriority
+	}
+
+	var warnings []error
+	for _, mutator := range []jobMutator{
+		jobConnectHook{},
+		jobExposeCheckHook{},
+		jobImpliedConstraints{},
+		jobNodePoolMutatingHook{},
+	} {
+		var w []error
+		job, w, err = mutator.Mutate(job)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error in job mutator %s: %v", mutator.Name(), err)
+		}
+		warnings = append(warnings, w...)
+	}
+
+	job, w, err := mutatePriorityClass(job, schedConfig, structs.JobDefaultPriority)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error in job mutator %s: %v", jobPriorityClassHook{}.Name(), err)
+	}
+	return job, append(warnings, w...), nil
+}
+
 // jobCanonicalizer calls job.Canonicalize (sets defaults and initializes
 // fields) and returns any errors as warnings.
 type jobCanonicalizer struct {
@@ -275,6 +324,8 @@ func (jobImpliedConstraints) Mutate(j *structs.Job) (*structs.Job, []error, erro
 
 	bridgeNetworkingTaskGroups := j.RequiredBridgeNetwork()
 
+	bandwidthTaskGroups := j.RequiredBandwidth()
+
 	transparentProxyTaskGroups := j.RequiredTransparentProxy()
 
 	taskScheduleTaskGroups := j.RequiredScheduleTask()
@@ -285,7 +336,7 @@ func (jobImpliedConstraints) Mutate(j *structs.Job) (*structs.Job, []error, erro
 	if len(signals) == 0 && len(vaultBlocks) == 0 &&
 		nativeServiceDisco.Empty() && len(consulServiceDisco) == 0 &&
 		numaTaskGroups.Empty() && bridgeNetworkingTaskGroups.Empty() &&
-		transparentProxyTaskGroups.Empty() &&
+		bandwidthTaskGroups.Empty() && transparentProxyTaskGroups.Empty() &&
 		taskScheduleTaskGroups.Empty() {
 		return j, nil, nil
 	}
@@ -352,6 +403,10 @@ func (jobImpliedConstraints) Mutate(j *structs.Job) (*structs.Job, []error, erro
 			mutateConstraint(constraintMatcherLeft, tg, cniPortMapConstraint)
 		}
 
+		if bandwidthTaskGroups.Contains(tg.Name) {
+			mutateConstraint(constraintMatcherLeft, tg, cniBandwidthConstraint)
+		}
+
 		if transparentProxyTaskGroups.Contains(tg.Name) {
 			mutateConstraint(constraintMatcherLeft, tg, cniConsulConstraint)
 			mutateConstraint(constraintMatcherLeft, tg, tproxyConstraint)
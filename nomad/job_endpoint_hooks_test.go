@@ -11,6 +11,7 @@ import (
 	"github.com/hashicorp/nomad/ci"
 	"github.com/hashicorp/nomad/helper/pointer"
 	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/state"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/nomad/structs/config"
 	"github.com/shoenig/test/must"
@@ -1191,6 +1192,71 @@ func Test_jobImpliedConstraints_Mutate(t *testing.T) {
 			expectedOutputError:    nil,
 			name:                   "task group with bridge network",
 		},
+		{
+			inputJob: &structs.Job{
+				Name: "example",
+				TaskGroups: []*structs.TaskGroup{
+					{
+						Name: "group-with-bandwidth",
+						Networks: []*structs.NetworkResource{
+							{Mode: "bridge", MBits: 100},
+						},
+					},
+				},
+			},
+			expectedOutputJob: &structs.Job{
+				Name: "example",
+				TaskGroups: []*structs.TaskGroup{
+					{
+						Name: "group-with-bandwidth",
+						Networks: []*structs.NetworkResource{
+							{Mode: "bridge", MBits: 100},
+						},
+						Constraints: []*structs.Constraint{
+							cniBridgeConstraint,
+							cniFirewallConstraint,
+							cniHostLocalConstraint,
+							cniLoopbackConstraint,
+							cniPortMapConstraint,
+							cniBandwidthConstraint,
+						},
+					},
+				},
+			},
+			expectedOutputWarnings: nil,
+			expectedOutputError:    nil,
+			name:                   "task group with bridge network bandwidth",
+		},
+		{
+			inputJob: &structs.Job{
+				Name: "example",
+				TaskGroups: []*structs.TaskGroup{
+					{
+						Name: "group-with-cni-bandwidth",
+						Networks: []*structs.NetworkResource{
+							{Mode: "cni/mynet", MBits: 100},
+						},
+					},
+				},
+			},
+			expectedOutputJob: &structs.Job{
+				Name: "example",
+				TaskGroups: []*structs.TaskGroup{
+					{
+						Name: "group-with-cni-bandwidth",
+						Networks: []*structs.NetworkResource{
+							{Mode: "cni/mynet", MBits: 100},
+						},
+						Constraints: []*structs.Constraint{
+							cniBandwidthConstraint,
+						},
+					},
+				},
+			},
+			expectedOutputWarnings: nil,
+			expectedOutputError:    nil,
+			name:                   "task group with cni network bandwidth",
+		},
 		{
 			inputJob: &structs.Job{
 				Name: "example",
@@ -1341,6 +1407,40 @@ func Test_jobCanonicalizer_Mutate(t *testing.T) {
 	}
 }
 
+func TestMutateSimulatedJob(t *testing.T) {
+	ci.Parallel(t)
+
+	store := state.TestStateStore(t)
+	must.NoError(t, store.SchedulerSetConfig(1000, &structs.SchedulerConfiguration{
+		PriorityClasses: []*structs.PriorityClass{
+			{
+				Name:             "critical",
+				Priority:         90,
+				PreemptionPolicy: structs.PriorityClassPreemptionPolicyLowerPriority,
+			},
+		},
+	}))
+
+	job := mock.BatchJob()
+	job.NodePool = ""
+	job.PriorityClass = "critical"
+	job.TaskGroups[0].Networks = []*structs.NetworkResource{{Mode: "bridge", MBits: 100}}
+
+	out, warnings, err := MutateSimulatedJob(store, job)
+	must.NoError(t, err)
+	must.SliceEmpty(t, warnings)
+	must.Eq(t, 90, out.Priority)
+	must.Eq(t, structs.NodePoolDefault, out.NodePool)
+	must.SliceContains(t, out.TaskGroups[0].Constraints, cniBridgeConstraint)
+	must.SliceContains(t, out.TaskGroups[0].Constraints, cniBandwidthConstraint)
+
+	// Priority classes missing from the scheduler configuration are rejected
+	job = mock.BatchJob()
+	job.PriorityClass = "unknown"
+	_, _, err = MutateSimulatedJob(store, job)
+	must.ErrorContains(t, err, `nonexistent priority class "unknown"`)
+}
+
 func TestJob_submissionController(t *testing.T) {
 	ci.Parallel(t)
 	args := &structs.JobRegisterRequest{
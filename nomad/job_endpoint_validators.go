@@ -5,6 +5,7 @@ package nomad
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/hashicorp/nomad/nomad/structs"
 )
@@ -115,3 +116,94 @@ func taskValidateDriver(task *structs.Task, ns *structs.Namespace) bool {
 	}
 	return allow
 }
+
+// jobDependencyHook is an admission hook that ensures the dependencies of a
+// job don't form a cycle with the jobs already registered.
+type jobDependencyHook struct {
+	srv *Server
+}
+
+func (jobDependencyHook) Name() string {
+	return "job-dependency"
+}
+
+func (h jobDependencyHook) Validate(job *structs.Job) ([]error, error) {
+	if !job.HasDependencies() {
+		return nil, nil
+	}
+
+	snap, err := h.srv.State().Snapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	lookup := func(id string) (*structs.Job, error) {
+		return snap.JobByID(nil, job.Namespace, id)
+	}
+
+	var warnings []error
+	for _, dep := range job.DependsOn {
+		upstream, err := lookup(dep.JobID)
+		if err != nil {
+			return nil, err
+		}
+		if upstream == nil {
+			warnings = append(warnings, fmt.Errorf(
+				"upstream job %q does not exist; job %q will not be placed until it is registered and %s",
+				dep.JobID, job.ID, dep.Condition))
+		}
+	}
+
+	cycle, err := jobDependencyCycle(job, lookup)
+	if err != nil {
+		return nil, err
+	}
+	if len(cycle) > 0 {
+		return warnings, fmt.Errorf("job dependency cycle: %s", strings.Join(cycle, " -> "))
+	}
+
+	return warnings, nil
+}
+
+// jobDependencyCycle returns the IDs of the jobs that form a cycle with the
+// dependencies of the given job, starting and ending with the job itself. The
+// lookup function is used to find the current version of the other jobs in
+// the namespace, and the given job takes precedence over any registered
+// version of itself.
+func jobDependencyCycle(job *structs.Job, lookup func(string) (*structs.Job, error)) ([]string, error) {
+	visited := make(map[string]bool)
+
+	var visit func(id string, path []string) ([]string, error)
+	visit = func(id string, path []string) ([]string, error) {
+		path = append(path, id)
+		if len(path) > 1 && id == job.ID {
+			return path, nil
+		}
+		if visited[id] {
+			return nil, nil
+		}
+		visited[id] = true
+
+		current := job
+		if id != job.ID {
+			var err error
+			current, err = lookup(id)
+			if err != nil {
+				return nil, err
+			}
+			if current == nil {
+				return nil, nil
+			}
+		}
+
+		for _, dep := range current.DependsOn {
+			cycle, err := visit(dep.JobID, path)
+			if err != nil || cycle != nil {
+				return cycle, err
+			}
+		}
+		return nil, nil
+	}
+
+	return visit(job.ID, nil)
+}
@@ -237,3 +237,80 @@ func TestJobNamespaceConstraintCheckHook_validate_network_modes(t *testing.T) {
 	_, err = hook.Validate(job)
 	must.EqError(t, err, "used group network modes [\"host\" \"cni/forbidden\"] are not allowed in namespace \"default\"")
 }
+
+func TestJobDependencyHook_jobDependencyCycle(t *testing.T) {
+	ci.Parallel(t)
+
+	newJob := func(id string, deps ...string) *structs.Job {
+		job := &structs.Job{ID: id}
+		for _, dep := range deps {
+			job.DependsOn = append(job.DependsOn, &structs.JobDependency{JobID: dep})
+		}
+		return job
+	}
+
+	cases := []struct {
+		name   string
+		job    *structs.Job
+		jobs   []*structs.Job
+		expect []string
+	}{
+		{
+			name:   "no dependencies",
+			job:    newJob("a"),
+			expect: nil,
+		},
+		{
+			name:   "missing upstream",
+			job:    newJob("a", "b"),
+			expect: nil,
+		},
+		{
+			name:   "chain",
+			job:    newJob("a", "b"),
+			jobs:   []*structs.Job{newJob("b", "c"), newJob("c")},
+			expect: nil,
+		},
+		{
+			name:   "diamond",
+			job:    newJob("a", "b", "c"),
+			jobs:   []*structs.Job{newJob("b", "d"), newJob("c", "d"), newJob("d")},
+			expect: nil,
+		},
+		{
+			name:   "direct cycle",
+			job:    newJob("a", "b"),
+			jobs:   []*structs.Job{newJob("b", "a")},
+			expect: []string{"a", "b", "a"},
+		},
+		{
+			name:   "indirect cycle",
+			job:    newJob("a", "b"),
+			jobs:   []*structs.Job{newJob("b", "c"), newJob("c", "a")},
+			expect: []string{"a", "b", "c", "a"},
+		},
+		{
+			name:   "registered version replaced",
+			job:    newJob("a", "b"),
+			jobs:   []*structs.Job{newJob("a", "c"), newJob("b"), newJob("c", "a")},
+			expect: nil,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			lookup := func(id string) (*structs.Job, error) {
+				for _, job := range tc.jobs {
+					if job.ID == id {
+						return job, nil
+					}
+				}
+				return nil, nil
+			}
+
+			cycle, err := jobDependencyCycle(tc.job, lookup)
+			must.NoError(t, err)
+			must.Eq(t, tc.expect, cycle)
+		})
+	}
+}
@@ -439,6 +439,16 @@ func (s *Server) establishLeadership(stopCh chan struct{}) error {
 	// Periodically unblock failed allocations
 	go s.periodicUnblockFailedEvals(stopCh)
 
+	// Evaluate batch jobs once their job dependencies are satisfied
+	go s.watchJobDependencies(stopCh)
+
+	// Evaluate queued children of periodic and parameterized jobs once their
+	// parent's concurrency limit allows
+	go s.watchQueuedChildren(stopCh)
+
+	// Keep the eval broker fair share weights in sync with the namespaces
+	go s.watchEvalBrokerFairShare(stopCh)
+
 	// Periodically publish job summary metrics
 	go s.publishJobSummaryMetrics(stopCh)
 
@@ -820,7 +830,7 @@ func (s *Server) restoreEvals() error {
 
 // restorePeriodicDispatcher is used to restore all periodic jobs into the
 // periodic dispatcher. It also determines if a periodic job should have been
-// created during the leadership transition and force runs them. The periodic
+// created during the leadership transition and catches up the missed launches. The periodic
 // dispatcher is maintained only by the leader, so it must be restored anytime a
 // leadership transition takes place.
 func (s *Server) restorePeriodicDispatcher() error {
@@ -887,12 +897,15 @@ func (s *Server) restorePeriodicDispatcher() error {
 			continue
 		}
 
-		if _, err := s.periodicDispatcher.ForceEval(job.Namespace, job.ID); err != nil {
-			logger.Error("force run of periodic job failed", "job", job.NamespacedID(), "error", err)
-			return fmt.Errorf("force run of periodic job %q failed: %v", job.NamespacedID(), err)
+		// Dispatch the launches missed during the leadership transition as
+		// allowed by the job's catch-up policy.
+		evals, err := s.periodicDispatcher.CatchUp(job.Namespace, job.ID, launch.Launch)
+		if err != nil {
+			logger.Error("catch-up of periodic job failed", "job", job.NamespacedID(), "error", err)
+			return fmt.Errorf("catch-up of periodic job %q failed: %v", job.NamespacedID(), err)
 		}
 
-		logger.Debug("periodic job force run during leadership establishment", "job", job.NamespacedID())
+		logger.Debug("periodic job caught up during leadership establishment", "job", job.NamespacedID(), "launches", len(evals))
 	}
 
 	return nil
@@ -902,7 +915,7 @@ func (s *Server) restorePeriodicDispatcher() error {
 // instances of the job running in order to determine if a new evaluation needs to
 // be created upon periodic dispatcher restore
 func (s *Server) cronJobOverlapAllowed(job *structs.Job) (bool, error) {
-	if job.Periodic.ProhibitOverlap {
+	if job.Periodic.ConcurrencyPolicy() == structs.JobConcurrencyForbid {
 		running, err := s.periodicDispatcher.dispatcher.RunningChildren(job)
 		if err != nil {
 			return false, fmt.Errorf("failed to determine if periodic job has running children %q error %q", job.NamespacedID(), err)
@@ -936,6 +949,8 @@ func (s *Server) schedulePeriodic(stopCh chan struct{}) {
 	defer rootKeyGC.Stop()
 	variablesRekey := time.NewTicker(s.config.VariablesRekeyInterval)
 	defer variablesRekey.Stop()
+	deschedule := time.NewTicker(s.config.DeschedulerInterval)
+	defer deschedule.Stop()
 
 	// Set up the expired ACL local token garbage collection timer.
 	localTokenExpiredGC, localTokenExpiredGCStop := helper.NewSafeTimer(s.config.ACLTokenExpirationGCInterval)
@@ -989,6 +1004,10 @@ func (s *Server) schedulePeriodic(stopCh chan struct{}) {
 			if index, ok := s.getLatestIndex(); ok {
 				s.evalBroker.Enqueue(s.coreJobEval(structs.CoreJobVariablesRekey, index))
 			}
+		case <-deschedule.C:
+			if index, ok := s.getLatestIndex(); ok {
+				s.evalBroker.Enqueue(s.coreJobEval(structs.CoreJobDeschedule, index))
+			}
 		case <-stopCh:
 			return
 		}
@@ -1344,6 +1363,7 @@ func (s *Server) publishJobStatusMetrics(stopCh chan struct{}) {
 }
 
 func (s *Server) iterateJobStatusMetrics(jobs *memdb.ResultIterator) {
+	var queued int64  // Sum of all jobs in 'queued' state
 	var pending int64 // Sum of all jobs in 'pending' state
 	var running int64 // Sum of all jobs in 'running' state
 	var dead int64    // Sum of all jobs in 'dead' state
@@ -1357,6 +1377,8 @@ func (s *Server) iterateJobStatusMetrics(jobs *memdb.ResultIterator) {
 		job := raw.(*structs.Job)
 
 		switch job.Status {
+		case structs.JobStatusQueued:
+			queued++
 		case structs.JobStatusPending:
 			pending++
 		case structs.JobStatusRunning:
@@ -1366,6 +1388,7 @@ func (s *Server) iterateJobStatusMetrics(jobs *memdb.ResultIterator) {
 		}
 	}
 
+	metrics.SetGauge([]string{"nomad", "job_status", "queued"}, float32(queued))
 	metrics.SetGauge([]string{"nomad", "job_status", "pending"}, float32(pending))
 	metrics.SetGauge([]string{"nomad", "job_status", "running"}, float32(running))
 	metrics.SetGauge([]string{"nomad", "job_status", "dead"}, float32(dead))
@@ -2759,5 +2782,86 @@ func (s *Server) handleEvalBrokerStateChange(schedConfig *structs.SchedulerConfi
 		restoreEvals = enableBrokers
 	}
 
+	if err := s.updateEvalBrokerFairShare(s.State(), schedConfig); err != nil {
+		s.logger.Error("failed to update eval broker fair share", "error", err)
+	}
+
 	return restoreEvals
 }
+
+// updateEvalBrokerFairShare configures how the eval broker shares scheduler
+// workers between namespaces, using the passed scheduler configuration and
+// the weights of the namespaces in the state store. If the scheduler
+// configuration is nil, the default scheduler config is used.
+func (s *Server) updateEvalBrokerFairShare(store *state.StateStore, schedConfig *structs.SchedulerConfiguration) error {
+	if schedConfig == nil {
+		schedConfig = &s.config.DefaultSchedulerConfig
+	}
+
+	iter, err := store.Namespaces(nil)
+	if err != nil {
+		return err
+	}
+
+	// Every namespace is passed to the broker so it can forget the usage of
+	// the deleted ones
+	weights := make(map[string]int)
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		ns := raw.(*structs.Namespace)
+		weights[ns.Name] = schedConfig.NamespaceWeight(ns)
+	}
+
+	fairShare := schedConfig.NamespaceFairShare
+	s.evalBroker.SetFairShare(fairShare.Enabled, fairShare.DefaultWeight, weights)
+	return nil
+}
+
+// watchEvalBrokerFairShare is a long lived function that updates the fair
+// share weights of the eval broker when namespaces change. Changes to the
+// scheduler configuration are handled by handleEvalBrokerStateChange.
+func (s *Server) watchEvalBrokerFairShare(stopCh chan struct{}) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-stopCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	var index uint64 = 1
+	for {
+		_, newIndex, err := s.State().BlockingQuery(
+			func(ws memdb.WatchSet, store *state.StateStore) (interface{}, uint64, error) {
+				if _, err := store.Namespaces(ws); err != nil {
+					return nil, 0, err
+				}
+				index, err := store.Index(state.TableNamespaces)
+				return nil, index, err
+			}, index, ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			s.logger.Error("failed to watch namespaces for eval broker fair share", "error", err)
+			select {
+			case <-stopCh:
+				return
+			case <-time.After(5 * time.Second):
+				continue
+			}
+		}
+		index = newIndex
+
+		store := s.State()
+		_, schedConfig, err := store.SchedulerConfig()
+		if err != nil {
+			s.logger.Error("failed to get scheduler config", "error", err)
+			continue
+		}
+		if err := s.updateEvalBrokerFairShare(store, schedConfig); err != nil {
+			s.logger.Error("failed to update eval broker fair share", "error", err)
+		}
+	}
+}
@@ -453,6 +453,14 @@ func (mjed *mockJobEvalDispatcher) DispatchJob(_ *structs.Job) (*structs.Evaluat
 	return mjed.evalToReturn, nil
 }
 
+func (mjed *mockJobEvalDispatcher) DispatchChildJob(parent, child *structs.Job) (*structs.Evaluation, bool, error) {
+	if mjed.children && parent.Periodic.ConcurrencyPolicy() == structs.JobConcurrencyForbid {
+		return nil, false, nil
+	}
+	eval, err := mjed.DispatchJob(child)
+	return eval, true, err
+}
+
 func (mjed *mockJobEvalDispatcher) RunningChildren(_ *structs.Job) (bool, error) {
 	return mjed.children, nil
 }
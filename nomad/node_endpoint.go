@@ -1237,11 +1237,49 @@ func (n *Node) GetClientAllocs(args *structs.NodeSpecificRequest,
 					reply.Index = index
 				}
 			}
+
+			// The network policies of the namespaces are enforced by the
+			// clients, so changes to the namespaces must unblock the query
+			if node != nil {
+				policies, index, err := namespaceNetworkPolicies(ws, state)
+				if err != nil {
+					return err
+				}
+				reply.NamespaceNetworkPolicies = policies
+				reply.Index = max(reply.Index, index)
+			}
 			return nil
 		}}
 	return n.srv.blockingRPC(&opts)
 }
 
+// namespaceNetworkPolicies returns the network policies of the namespaces that
+// have one, and the index of the namespaces table.
+func namespaceNetworkPolicies(ws memdb.WatchSet, store *state.StateStore) (map[string]*structs.NetworkPolicy, uint64, error) {
+	iter, err := store.Namespaces(ws)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var policies map[string]*structs.NetworkPolicy
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		ns := raw.(*structs.Namespace)
+		if ns.NetworkPolicy == nil {
+			continue
+		}
+		if policies == nil {
+			policies = make(map[string]*structs.NetworkPolicy)
+		}
+		policies[ns.Name] = ns.NetworkPolicy
+	}
+
+	index, err := store.Index(state.TableNamespaces)
+	if err != nil {
+		return nil, 0, err
+	}
+	return policies, index, nil
+}
+
 // UpdateAlloc is used to update the client status of an allocation. It should
 // only be called by clients.
 //
@@ -1349,11 +1387,19 @@ func (n *Node) UpdateAlloc(args *structs.AllocUpdateRequest, reply *structs.Gene
 			}
 
 			// Set trigger by failed if not an orphan.
-			if alloc.RescheduleEligible(taskGroup.ReschedulePolicy, now) {
+			if alloc.RescheduleEligible(taskGroup.EffectiveReschedulePolicy(), now) {
 				evalTriggerBy = structs.EvalTriggerRetryFailedAlloc
 			}
 		}
 
+		// Indexed task groups hold back the placements above their
+		// parallelism, so place them once a running allocation finishes.
+		if evalTriggerBy == "" && taskGroup != nil && taskGroup.Indexed != nil &&
+			taskGroup.Indexed.Parallelism > 0 &&
+			allocToUpdate.ClientTerminalStatus() && !alloc.ClientTerminalStatus() {
+			evalTriggerBy = structs.EvalTriggerIndexProgress
+		}
+
 		var eval *structs.Evaluation
 		// If unknown, and not an orphan, set the trigger by.
 		if evalTriggerBy != structs.EvalTriggerJobDeregister &&
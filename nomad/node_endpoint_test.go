@@ -2596,6 +2596,52 @@ func TestClientEndpoint_GetClientAllocs_Blocking(t *testing.T) {
 	}
 }
 
+func TestClientEndpoint_GetClientAllocs_NamespaceNetworkPolicies(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	node := mock.Node()
+	reg := &structs.NodeRegisterRequest{
+		Node:         node,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.GenericResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Node.Register", reg, &resp))
+
+	req := &structs.NodeSpecificRequest{
+		NodeID:   node.ID,
+		SecretID: node.SecretID,
+		QueryOptions: structs.QueryOptions{
+			Region: "global",
+		},
+	}
+	var resp2 structs.NodeClientAllocsResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Node.GetClientAllocs", req, &resp2))
+	must.MapEmpty(t, resp2.NamespaceNetworkPolicies)
+
+	// Setting the network policy of a namespace unblocks the query
+	ns := mock.Namespace()
+	ns.NetworkPolicy = &structs.NetworkPolicy{Default: structs.NetworkPolicyDefaultDeny}
+	index := resp2.Index + 100
+	time.AfterFunc(100*time.Millisecond, func() {
+		s1.fsm.State().UpsertNamespaces(index, []*structs.Namespace{ns})
+	})
+
+	req.MinQueryIndex = resp2.Index
+	req.MaxQueryTime = 5 * time.Second
+	start := time.Now()
+	var resp3 structs.NodeClientAllocsResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Node.GetClientAllocs", req, &resp3))
+	must.Greater(t, 100*time.Millisecond, time.Since(start))
+	must.Less(t, 5*time.Second, time.Since(start))
+	must.Eq(t, index, resp3.Index)
+	must.Eq(t, map[string]*structs.NetworkPolicy{ns.Name: ns.NetworkPolicy}, resp3.NamespaceNetworkPolicies)
+}
+
 func TestClientEndpoint_GetClientAllocs_Blocking_GC(t *testing.T) {
 	ci.Parallel(t)
 	assert := assert.New(t)
@@ -43,13 +43,31 @@ type JobEvalDispatcher interface {
 	// for it and returns the eval.
 	DispatchJob(job *structs.Job) (*structs.Evaluation, error)
 
+	// DispatchChildJob applies the concurrency policy of the parent job to
+	// the child job and dispatches it. It returns false if the launch is
+	// skipped.
+	DispatchChildJob(parent, child *structs.Job) (*structs.Evaluation, bool, error)
+
 	// RunningChildren returns whether the passed job has any running children.
 	RunningChildren(job *structs.Job) (bool, error)
 }
 
 // DispatchJob creates an evaluation for the passed job and commits both the
-// evaluation and the job to the raft log. It returns the eval.
+// evaluation and the job to the raft log. It returns the eval. Queued jobs are
+// committed without an evaluation.
 func (s *Server) DispatchJob(job *structs.Job) (*structs.Evaluation, error) {
+	if job.Queued {
+		job.SetSubmitTime()
+		req := structs.JobRegisterRequest{
+			Job: job,
+			WriteRequest: structs.WriteRequest{
+				Namespace: job.Namespace,
+			},
+		}
+		_, _, err := s.raftApply(structs.JobRegisterRequestType, req)
+		return nil, err
+	}
+
 	now := time.Now().UTC().UnixNano()
 	eval := &structs.Evaluation{
 		ID:          uuid.Generate(),
@@ -303,6 +321,61 @@ func (p *PeriodicDispatch) ForceEval(namespace, jobID string) (*structs.Evaluati
 	return p.createEval(job, time.Now().In(job.Periodic.GetLocation()))
 }
 
+// CatchUp dispatches the launches of the periodic job that were missed since
+// its last launch, according to the job's catch-up policy, and returns the
+// subsequent evals. The jobs derived for the missed launches are marked with
+// the PeriodicCatchupMetaKey meta key.
+func (p *PeriodicDispatch) CatchUp(namespace, jobID string, lastLaunch time.Time) ([]*structs.Evaluation, error) {
+	p.l.Lock()
+
+	// Do nothing if not enabled
+	if !p.enabled {
+		p.l.Unlock()
+		return nil, fmt.Errorf("periodic dispatch disabled")
+	}
+
+	tuple := structs.NamespacedID{
+		ID:        jobID,
+		Namespace: namespace,
+	}
+	job, tracked := p.tracked[tuple]
+	if !tracked {
+		p.l.Unlock()
+		return nil, fmt.Errorf("can't catch up non-tracked job %q (%s)", jobID, namespace)
+	}
+
+	p.l.Unlock()
+
+	loc := job.Periodic.GetLocation()
+	launches, err := job.Periodic.MissedLaunches(lastLaunch.In(loc), time.Now().In(loc))
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the latest missed launch may run if the job prohibits overlap or
+	// replaces the running launch
+	switch job.Periodic.ConcurrencyPolicy() {
+	case structs.JobConcurrencyForbid, structs.JobConcurrencyReplace:
+		if len(launches) > 1 {
+			launches = launches[len(launches)-1:]
+		}
+	}
+
+	evals := make([]*structs.Evaluation, 0, len(launches))
+	for _, launch := range launches {
+		meta := map[string]string{structs.PeriodicCatchupMetaKey: launch.Format(time.RFC3339)}
+		eval, err := p.launch(job, launch, meta)
+		if err != nil {
+			return evals, err
+		}
+		if eval != nil {
+			evals = append(evals, eval)
+		}
+	}
+
+	return evals, nil
+}
+
 // shouldRun returns whether the long lived run function should run.
 func (p *PeriodicDispatch) shouldRun() bool {
 	p.l.RLock()
@@ -347,26 +420,42 @@ func (p *PeriodicDispatch) dispatch(job *structs.Job, launchTime time.Time) {
 		p.logger.Error("failed to update next launch of periodic job", "job", job.NamespacedID(), "error", err)
 	}
 
-	// If the job prohibits overlapping and there are running children, we skip
-	// the launch.
-	if job.Periodic.ProhibitOverlap {
-		running, err := p.dispatcher.RunningChildren(job)
-		if err != nil {
-			p.logger.Error("failed to determine if periodic job has running children", "job", job.NamespacedID(), "error", err)
-			p.l.Unlock()
-			return
-		}
+	p.l.Unlock()
+	p.launch(job, launchTime, nil)
+}
 
-		if running {
-			p.logger.Debug("skipping launch of periodic job because job prohibits overlap", "job", job.NamespacedID())
-			p.l.Unlock()
-			return
+// launch derives a job for the launch time with the additional metadata and
+// dispatches it according to the concurrency policy of the periodic job, which
+// may skip the launch, stop the running children or queue the derived job. It
+// returns the eval of the derived job, if any. This should not be called with
+// the lock held.
+func (p *PeriodicDispatch) launch(job *structs.Job, launchTime time.Time, meta map[string]string) (*structs.Evaluation, error) {
+	derived, err := p.deriveJob(job, launchTime)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range meta {
+		if derived.Meta == nil {
+			derived.Meta = make(map[string]string, len(meta))
 		}
+		derived.Meta[k] = v
 	}
 
-	p.logger.Debug(" launching job", "job", job.NamespacedID(), "launch_time", launchTime)
-	p.l.Unlock()
-	p.createEval(job, launchTime)
+	p.logger.Debug("launching job", "job", job.NamespacedID(), "launch_time", launchTime)
+	eval, launched, err := p.dispatcher.DispatchChildJob(job, derived)
+	if err != nil {
+		p.logger.Error("failed to dispatch job", "job", job.NamespacedID(), "error", err)
+		return nil, err
+	}
+	if !launched {
+		p.logger.Debug("skipping launch of periodic job because job prohibits overlap", "job", job.NamespacedID())
+		return nil, nil
+	}
+	if derived.Queued {
+		p.logger.Debug("queued launch of periodic job", "job", job.NamespacedID(), "launch_time", launchTime)
+	}
+
+	return eval, nil
 }
 
 // nextLaunch returns the next job to launch and when it should be launched. If
@@ -44,11 +44,46 @@ func (m *MockJobEvalDispatcher) DispatchJob(job *structs.Job) (*structs.Evaluati
 	return nil, nil
 }
 
+// DispatchChildJob applies the concurrency policy of the parent job as the
+// server does, with the children that aren't stopped or queued running.
+func (m *MockJobEvalDispatcher) DispatchChildJob(parent, child *structs.Job) (*structs.Evaluation, bool, error) {
+	m.lock.Lock()
+	var active, queued []*structs.Job
+	for _, job := range m.Jobs {
+		if job.ParentID != parent.ID || job.Namespace != parent.Namespace || job.Stop {
+			continue
+		}
+		if job.Queued {
+			queued = append(queued, job)
+		} else {
+			active = append(active, job)
+		}
+	}
+
+	switch parent.Periodic.ConcurrencyPolicy() {
+	case structs.JobConcurrencyForbid:
+		if len(active) > 0 {
+			m.lock.Unlock()
+			return nil, false, nil
+		}
+	case structs.JobConcurrencyReplace:
+		for _, job := range active {
+			job.Stop = true
+		}
+	case structs.JobConcurrencyQueue:
+		child.Queued = len(active) > 0 || len(queued) > 0
+	}
+	m.lock.Unlock()
+
+	eval, err := m.DispatchJob(child)
+	return eval, true, err
+}
+
 func (m *MockJobEvalDispatcher) RunningChildren(parent *structs.Job) (bool, error) {
 	m.lock.Lock()
 	defer m.lock.Unlock()
 	for _, job := range m.Jobs {
-		if job.ParentID == parent.ID && job.Namespace == parent.Namespace {
+		if job.ParentID == parent.ID && job.Namespace == parent.Namespace && !job.Stop && !job.Queued {
 			return true, nil
 		}
 	}
@@ -398,6 +433,42 @@ func TestPeriodicDispatch_ForceEval_Tracked(t *testing.T) {
 	}
 }
 
+func TestPeriodicDispatch_CatchUp(t *testing.T) {
+	ci.Parallel(t)
+	p, m := testPeriodicDispatcher(t)
+
+	// Create a job that missed three launches and has one in the future.
+	now := time.Now()
+	missed := []time.Time{now.Add(-3 * time.Second), now.Add(-2 * time.Second), now.Add(-1 * time.Second)}
+	job := testPeriodicJob(append(missed, now.Add(10*time.Second))...)
+	job.Periodic.Catchup = structs.PeriodicCatchupAll
+	job.Periodic.CatchupMaxCount = 2
+	must.NoError(t, p.Add(job))
+
+	_, err := p.CatchUp(job.Namespace, job.ID, now.Add(-10*time.Second))
+	must.NoError(t, err)
+
+	// Only the two latest missed launches are dispatched and marked.
+	launches, err := m.LaunchTimes(p, job.Namespace, job.ID)
+	must.NoError(t, err)
+	must.Len(t, 2, launches)
+	for i, launch := range launches {
+		expected := missed[i+1].Round(time.Second)
+		must.Eq(t, expected.Unix(), launch.Unix())
+
+		derived := m.Jobs[structs.NamespacedID{
+			ID:        p.derivedJobID(job, launch),
+			Namespace: job.Namespace,
+		}]
+		must.NotNil(t, derived)
+		must.Eq(t, launch.Format(time.RFC3339), derived.Meta[structs.PeriodicCatchupMetaKey])
+	}
+
+	// Catching up an untracked job fails.
+	_, err = p.CatchUp(job.Namespace, "foo", now)
+	must.Error(t, err)
+}
+
 func TestPeriodicDispatch_Run_DisallowOverlaps(t *testing.T) {
 	ci.Parallel(t)
 	p, m := testPeriodicDispatcher(t)
@@ -428,6 +499,51 @@ func TestPeriodicDispatch_Run_DisallowOverlaps(t *testing.T) {
 	}
 }
 
+func TestPeriodicDispatch_Launch_Concurrency(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		policy      string
+		expLaunches int
+		expStopped  bool
+		expQueued   bool
+	}{
+		{policy: structs.JobConcurrencyAllow, expLaunches: 2},
+		{policy: structs.JobConcurrencyForbid, expLaunches: 1},
+		{policy: structs.JobConcurrencyReplace, expLaunches: 2, expStopped: true},
+		{policy: structs.JobConcurrencyQueue, expLaunches: 2, expQueued: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.policy, func(t *testing.T) {
+			p, m := testPeriodicDispatcher(t)
+
+			launch1 := time.Now().Round(time.Second).Add(10 * time.Second)
+			launch2 := launch1.Add(time.Second)
+			job := testPeriodicJob(launch1, launch2)
+			job.Periodic.Concurrency = tc.policy
+			must.NoError(t, p.Add(job))
+
+			_, err := p.launch(job, launch1, nil)
+			must.NoError(t, err)
+			_, err = p.launch(job, launch2, nil)
+			must.NoError(t, err)
+
+			launches, err := m.LaunchTimes(p, job.Namespace, job.ID)
+			must.NoError(t, err)
+			must.Len(t, tc.expLaunches, launches)
+
+			first := m.Jobs[structs.NewNamespacedID(p.derivedJobID(job, launch1), job.Namespace)]
+			must.Eq(t, tc.expStopped, first.Stop)
+			if tc.expLaunches == 2 {
+				second := m.Jobs[structs.NewNamespacedID(p.derivedJobID(job, launch2), job.Namespace)]
+				must.Eq(t, tc.expQueued, second.Queued)
+				must.False(t, second.Stop)
+			}
+		})
+	}
+}
+
 func TestPeriodicDispatch_Run_Multiple(t *testing.T) {
 	ci.Parallel(t)
 	p, m := testPeriodicDispatcher(t)
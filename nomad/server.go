@@ -242,6 +242,14 @@ type Server struct {
 	// periodicDispatcher is used to track and create evaluations for periodic jobs.
 	periodicDispatcher *PeriodicDispatch
 
+	// dispatchLimiters limits the rate at which the dispatched jobs of
+	// parameterized jobs with a dispatch queue are evaluated.
+	dispatchLimiters dispatchRateLimiters
+
+	// jobConcurrencyLocks serializes the launches of the children of each
+	// periodic or parameterized job.
+	jobConcurrencyLocks jobConcurrencyLocks
+
 	// planner is used to mange the submitted allocation plans that are waiting
 	// to be accessed by the leader
 	*planner
@@ -1135,6 +1143,8 @@ func (s *Server) setupDeploymentWatcher() error {
 		raftShim,
 		NewDeploymentEndpoint(s, nil),
 		NewJobEndpoints(s, nil),
+		&deploymentWatcherAllocShim{srv: s},
+		&deploymentWatcherHookShim{srv: s},
 		s.config.DeploymentQueryRateLimit,
 		deploymentwatcher.CrossDeploymentUpdateBatchDuration,
 	)
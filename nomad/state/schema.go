@@ -261,6 +261,22 @@ func jobTableSchema() *memdb.TableSchema {
 					Field: "NodePool",
 				},
 			},
+			"dependencies": {
+				Name:         "dependencies",
+				AllowMissing: false,
+				Unique:       false,
+				Indexer: &memdb.ConditionalIndex{
+					Conditional: jobIsWaitingOnDependencies,
+				},
+			},
+			"queued": {
+				Name:         "queued",
+				AllowMissing: false,
+				Unique:       false,
+				Indexer: &memdb.ConditionalIndex{
+					Conditional: jobIsQueued,
+				},
+			},
 			// ModifyIndex allows sorting by last-changed
 			"modify_index": {
 				Name:         "modify_index",
@@ -414,6 +430,30 @@ func jobIsGCable(obj interface{}) (bool, error) {
 	}
 }
 
+// jobIsWaitingOnDependencies satisfies the ConditionalIndexFunc interface and
+// creates an index on whether a batch job hasn't been placed yet because of
+// its dependencies.
+func jobIsWaitingOnDependencies(obj interface{}) (bool, error) {
+	j, ok := obj.(*structs.Job)
+	if !ok {
+		return false, fmt.Errorf("Unexpected type: %v", obj)
+	}
+
+	return j.Type == structs.JobTypeBatch && !j.Stop &&
+		j.Status == structs.JobStatusPending && j.HasDependencies(), nil
+}
+
+// jobIsQueued satisfies the ConditionalIndexFunc interface and creates an
+// index on whether a child job is queued behind its siblings.
+func jobIsQueued(obj interface{}) (bool, error) {
+	j, ok := obj.(*structs.Job)
+	if !ok {
+		return false, fmt.Errorf("Unexpected type: %v", obj)
+	}
+
+	return j.Status == structs.JobStatusQueued, nil
+}
+
 // jobIsPeriodic satisfies the ConditionalIndexFunc interface and creates an index
 // on whether a job is periodic.
 func jobIsPeriodic(obj interface{}) (bool, error) {
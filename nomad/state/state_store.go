@@ -1783,6 +1783,16 @@ func (s *StateStore) upsertJobImpl(index uint64, sub *structs.JobSubmission, job
 
 		existingJob = existing.(*structs.Job)
 
+		// Keep the submit time of the first version, unless the stopped job
+		// is started again
+		job.FirstSubmitTime = existingJob.FirstSubmitTime
+		if job.FirstSubmitTime == 0 {
+			job.FirstSubmitTime = existingJob.SubmitTime
+		}
+		if existingJob.Stop && !job.Stop {
+			job.FirstSubmitTime = job.SubmitTime
+		}
+
 		// Bump the version unless asked to keep it. This should only be done
 		// when changing an internal field such as Stable. A spec change should
 		// always come with a version bump
@@ -1808,6 +1818,7 @@ func (s *StateStore) upsertJobImpl(index uint64, sub *structs.JobSubmission, job
 		job.CreateIndex = index
 		job.ModifyIndex = index
 		job.JobModifyIndex = index
+		job.FirstSubmitTime = job.SubmitTime
 
 		if err := s.setJobStatus(index, txn, job, false, ""); err != nil {
 			return fmt.Errorf("setting job status for %q failed: %v", job.ID, err)
@@ -1899,6 +1910,10 @@ func (s *StateStore) DeleteJobTxn(index uint64, namespace, jobID string, txn Txn
 
 				modified := false
 				switch job.Status {
+				case structs.JobStatusQueued:
+					pSummary.Children.Queued--
+					pSummary.Children.Dead++
+					modified = true
 				case structs.JobStatusPending:
 					pSummary.Children.Pending--
 					pSummary.Children.Dead++
@@ -2481,6 +2496,36 @@ func (s *StateStore) JobsByGC(ws memdb.WatchSet, gc bool) (memdb.ResultIterator,
 	return iter, nil
 }
 
+// JobsWaitingOnDependencies returns an iterator over the batch jobs that
+// haven't been placed yet because of their dependencies.
+func (s *StateStore) JobsWaitingOnDependencies(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get("jobs", "dependencies", true)
+	if err != nil {
+		return nil, err
+	}
+
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
+// JobsQueued returns an iterator over the child jobs queued behind their
+// siblings by the concurrency policy of their parent.
+func (s *StateStore) JobsQueued(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get("jobs", "queued", true)
+	if err != nil {
+		return nil, err
+	}
+
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
 // JobsByPool returns an iterator over all jobs in a given node pool.
 func (s *StateStore) JobsByPool(ws memdb.WatchSet, pool string) (memdb.ResultIterator, error) {
 	txn := s.db.ReadTxn()
@@ -3614,7 +3659,7 @@ func isEvalDeleteSafe(allocs []*structs.Allocation, job *structs.Job) bool {
 		tg := job.LookupTaskGroup(alloc.TaskGroup)
 
 		if tg != nil {
-			reschedulePolicy = tg.ReschedulePolicy
+			reschedulePolicy = tg.EffectiveReschedulePolicy()
 		}
 
 		// No reschedule policy or rescheduling is disabled
@@ -4663,8 +4708,25 @@ func (s *StateStore) updateDeploymentStatusImpl(index uint64, u *structs.Deploym
 
 	// Apply the new status
 	copy := deployment.Copy()
-	copy.Status = u.Status
-	copy.StatusDescription = u.StatusDescription
+	if u.Status != "" {
+		copy.Status = u.Status
+		copy.StatusDescription = u.StatusDescription
+	}
+	for tg, result := range u.CanaryAnalysis {
+		if dstate, ok := copy.TaskGroups[tg]; ok {
+			dstate.CanaryAnalysis = result.Copy()
+		}
+	}
+	for tg, hook := range u.PreDeploy {
+		if dstate, ok := copy.TaskGroups[tg]; ok {
+			dstate.PreDeploy = hook.Copy()
+		}
+	}
+	for tg, hook := range u.PostDeploy {
+		if dstate, ok := copy.TaskGroups[tg]; ok {
+			dstate.PostDeploy = hook.Copy()
+		}
+	}
 	copy.ModifyIndex = index
 	copy.ModifyTime = u.UpdatedAt
 
@@ -4919,11 +4981,22 @@ func (s *StateStore) UpdateDeploymentPromotion(msgType structs.MessageType, inde
 			status.RequireProgressBy = time.Now().Add(status.ProgressDeadline)
 		}
 		status.Promoted = true
+
+		// start retaining the blue allocations of blue/green groups
+		if status.BlueGreen {
+			status.RetainBlueUntil = time.Unix(0, req.PromotedAt).Add(status.BlueRetention)
+		}
 	}
 
 	// If the deployment no longer needs promotion, update its status
 	if !copy.RequiresPromotion() && copy.Status == structs.DeploymentStatusRunning {
 		copy.StatusDescription = structs.DeploymentStatusDescriptionRunning
+		for _, status := range copy.TaskGroups {
+			if status.RetainingBlue(time.Unix(0, req.PromotedAt)) {
+				copy.StatusDescription = structs.DeploymentStatusDescriptionRunningRetainingBlue
+				break
+			}
+		}
 	}
 
 	// Update modify time to the time of deployment promotion
@@ -4954,6 +5027,12 @@ func (s *StateStore) UpdateDeploymentPromotion(msgType structs.MessageType, inde
 		}
 	}
 
+	// Mark the blue allocations of promoted blue/green groups as retained so
+	// their services are registered with the canary tags
+	if err := s.retainBlueAllocs(txn, index, copy); err != nil {
+		return err
+	}
+
 	// Update the alloc index
 	if err := txn.Insert("index", &IndexEntry{"allocs", index}); err != nil {
 		return fmt.Errorf("index update failed: %v", err)
@@ -4962,6 +5041,55 @@ func (s *StateStore) UpdateDeploymentPromotion(msgType structs.MessageType, inde
 	return txn.Commit()
 }
 
+// retainBlueAllocs marks the running allocations of the promoted blue/green
+// groups of a deployment that aren't part of the deployment as retained.
+func (s *StateStore) retainBlueAllocs(txn *txn, index uint64, deployment *structs.Deployment) error {
+	groups := make(map[string]struct{}, len(deployment.TaskGroups))
+	for tg, status := range deployment.TaskGroups {
+		if status.BlueGreen && status.Promoted {
+			groups[tg] = struct{}{}
+		}
+	}
+	if len(groups) == 0 {
+		return nil
+	}
+
+	iter, err := txn.Get("allocs", "job", deployment.Namespace, deployment.JobID)
+	if err != nil {
+		return err
+	}
+
+	var blue []*structs.Allocation
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		alloc := raw.(*structs.Allocation)
+		if _, ok := groups[alloc.TaskGroup]; !ok {
+			continue
+		}
+		if alloc.DeploymentID == deployment.ID || alloc.TerminalStatus() ||
+			alloc.DeploymentStatus.CanaryServices() {
+			continue
+		}
+		blue = append(blue, alloc)
+	}
+
+	for _, alloc := range blue {
+		retained := alloc.Copy()
+		if retained.DeploymentStatus == nil {
+			retained.DeploymentStatus = &structs.AllocDeploymentStatus{}
+		}
+		retained.DeploymentStatus.Retained = true
+		retained.DeploymentStatus.ModifyIndex = index
+		retained.ModifyIndex = index
+		retained.AllocModifyIndex = index
+
+		if err := txn.Insert("allocs", retained); err != nil {
+			return fmt.Errorf("alloc insert failed: %v", err)
+		}
+	}
+
+	return nil
+}
+
 // UpdateDeploymentAllocHealth is used to update the health of allocations as
 // part of the deployment and potentially make a evaluation
 func (s *StateStore) UpdateDeploymentAllocHealth(msgType structs.MessageType, index uint64, req *structs.ApplyDeploymentAllocHealthRequest) error {
@@ -5178,6 +5306,8 @@ func (s *StateStore) ReconcileJobSummaries(index uint64) error {
 			children := parentMap[job.ID]
 			for _, childJob := range children {
 				switch childJob.Status {
+				case structs.JobStatusQueued:
+					summary.Children.Queued++
 				case structs.JobStatusPending:
 					summary.Children.Pending++
 				case structs.JobStatusDead:
@@ -5208,6 +5338,7 @@ func (s *StateStore) ReconcileJobSummaries(index uint64) error {
 			JobID:     job.ID,
 			Namespace: job.Namespace,
 			Summary:   make(map[string]structs.TaskGroupSummary),
+			Deadline:  jobDeadline(job),
 		}
 		for _, tg := range job.TaskGroups {
 			summary.Summary[tg.Name] = structs.TaskGroupSummary{}
@@ -5250,6 +5381,15 @@ func (s *StateStore) ReconcileJobSummaries(index uint64) error {
 			default:
 				s.logger.Error("invalid client status set on allocation", "client_status", alloc.ClientStatus, "alloc_id", alloc.ID)
 			}
+			if allocTG := alloc.Job.LookupTaskGroup(alloc.TaskGroup); allocTG != nil && allocTG.Indexed != nil {
+				completed, failed := allocTG.Indexed.IndexProgress(alloc)
+				if completed {
+					tg.CompletedIndexes += 1
+				}
+				if failed {
+					tg.FailedIndexes += 1
+				}
+			}
 			summary.Summary[alloc.TaskGroup] = tg
 		}
 
@@ -5376,6 +5516,8 @@ func (s *StateStore) setJobSummary(txn *txn, updated *structs.Job, index uint64,
 		// Decrement old status
 		if oldStatus != "" {
 			switch oldStatus {
+			case structs.JobStatusQueued:
+				children.Queued--
 			case structs.JobStatusPending:
 				children.Pending--
 			case structs.JobStatusRunning:
@@ -5389,6 +5531,8 @@ func (s *StateStore) setJobSummary(txn *txn, updated *structs.Job, index uint64,
 
 		// Increment new status
 		switch newStatus {
+		case structs.JobStatusQueued:
+			children.Queued++
 		case structs.JobStatusPending:
 			children.Pending++
 		case structs.JobStatusRunning:
@@ -5432,7 +5576,9 @@ func (s *StateStore) getJobStatus(txn *txn, job *structs.Job, evalDelete bool) (
 
 	// If there is a non-terminal allocation, the job is running.
 	hasAlloc := false
+	placed := false
 	for alloc := allocs.Next(); alloc != nil; alloc = allocs.Next() {
+		placed = true
 		if !alloc.(*structs.Allocation).TerminalStatus() {
 			return structs.JobStatusRunning, nil
 		}
@@ -5451,6 +5597,19 @@ func (s *StateStore) getJobStatus(txn *txn, job *structs.Job, evalDelete bool) (
 		}
 	}
 
+	// Queued children wait for their parent's concurrency limit until they
+	// are evaluated.
+	if job.Queued && !job.Stop && !hasEval && !placed && !evalDelete {
+		return structs.JobStatusQueued, nil
+	}
+
+	// Jobs that depend on other jobs remain pending until they have been
+	// placed, since their evals complete without placing anything while
+	// their dependencies aren't met.
+	if job.HasDependencies() && !job.Stop && !placed {
+		return structs.JobStatusPending, nil
+	}
+
 	// The job is dead if all allocations for this version are terminal,
 	// all evals are terminal. In the event a jobs allocs and evals
 	// are all GC'd, we don't want the job to be marked pending.
@@ -5491,6 +5650,11 @@ func (s *StateStore) updateSummaryWithJob(index uint64, job *structs.Job,
 		hasSummaryChanged = true
 	}
 
+	if deadline := jobDeadline(job); summary.Deadline != deadline {
+		summary.Deadline = deadline
+		hasSummaryChanged = true
+	}
+
 	for _, tg := range job.TaskGroups {
 		if _, ok := summary.Summary[tg.Name]; !ok {
 			newSummary := structs.TaskGroupSummary{
@@ -5520,6 +5684,14 @@ func (s *StateStore) updateSummaryWithJob(index uint64, job *structs.Job,
 	return nil
 }
 
+// jobDeadline returns the deadline of the job to store in its summary.
+func jobDeadline(job *structs.Job) int64 {
+	if deadline := job.DeadlineTime(); !deadline.IsZero() {
+		return deadline.UnixNano()
+	}
+	return 0
+}
+
 // updateJobScalingPolicies upserts any scaling policies contained in the job and removes
 // any previous scaling policies that were removed from the job
 func (s *StateStore) updateJobScalingPolicies(index uint64, job *structs.Job, txn *txn) error {
@@ -5926,6 +6098,17 @@ func (s *StateStore) updateSummaryWithAlloc(index uint64, alloc *structs.Allocat
 			s.logger.Error("invalid old client status for allocation",
 				"alloc_id", existingAlloc.ID, "client_status", existingAlloc.ClientStatus)
 		}
+
+		// Track the progress of the indexes of indexed task groups
+		if tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup); tg != nil && tg.Indexed != nil {
+			completed, failed := tg.Indexed.IndexProgress(alloc)
+			if completed {
+				tgSummary.CompletedIndexes += 1
+			}
+			if failed {
+				tgSummary.FailedIndexes += 1
+			}
+		}
 		summaryChanged = true
 	}
 	jobSummary.Summary[alloc.TaskGroup] = tgSummary
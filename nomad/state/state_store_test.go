@@ -2547,6 +2547,95 @@ func TestStateStore_UpsertJob_ChildJob(t *testing.T) {
 	}
 }
 
+func TestStateStore_UpsertJob_QueuedChildJob(t *testing.T) {
+	ci.Parallel(t)
+
+	state := testStateStore(t)
+
+	parent := mock.PeriodicJob()
+	must.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1000, nil, parent))
+
+	child := mock.Job()
+	child.Status = ""
+	child.ParentID = parent.ID
+	child.Queued = true
+	must.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1001, nil, child))
+
+	out, err := state.JobByID(nil, child.Namespace, child.ID)
+	must.NoError(t, err)
+	must.Eq(t, structs.JobStatusQueued, out.Status)
+
+	summary, err := state.JobSummaryByID(nil, parent.Namespace, parent.ID)
+	must.NoError(t, err)
+	must.Eq(t, &structs.JobChildrenSummary{Queued: 1}, summary.Children)
+
+	// The child is pending once it is evaluated
+	eval := mock.Eval()
+	eval.JobID = child.ID
+	must.NoError(t, state.UpsertEvals(structs.MsgTypeTestSetup, 1002, []*structs.Evaluation{eval}))
+
+	out, err = state.JobByID(nil, child.Namespace, child.ID)
+	must.NoError(t, err)
+	must.Eq(t, structs.JobStatusPending, out.Status)
+
+	summary, err = state.JobSummaryByID(nil, parent.Namespace, parent.ID)
+	must.NoError(t, err)
+	must.Eq(t, &structs.JobChildrenSummary{Pending: 1}, summary.Children)
+}
+
+func TestStateStore_UpsertJob_Deadline(t *testing.T) {
+	ci.Parallel(t)
+
+	state := testStateStore(t)
+
+	job := mock.BatchJob()
+	job.SubmitTime = time.Now().UnixNano()
+	job.Deadline = time.Hour
+	must.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1000, nil, job))
+
+	summary, err := state.JobSummaryByID(nil, job.Namespace, job.ID)
+	must.NoError(t, err)
+	must.Eq(t, job.DeadlineTime().UnixNano(), summary.Deadline)
+	deadline := summary.Deadline
+
+	// Updating the job doesn't push the deadline back
+	job = job.Copy()
+	job.SubmitTime = time.Now().Add(time.Minute).UnixNano()
+	job.Meta = map[string]string{"version": "2"}
+	must.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1001, nil, job))
+
+	summary, err = state.JobSummaryByID(nil, job.Namespace, job.ID)
+	must.NoError(t, err)
+	must.Eq(t, deadline, summary.Deadline)
+
+	out, err := state.JobByID(nil, job.Namespace, job.ID)
+	must.NoError(t, err)
+	must.Eq(t, deadline, out.DeadlineTime().UnixNano())
+
+	// Starting the stopped job again anchors a new deadline
+	job = out.Copy()
+	job.Stop = true
+	must.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1002, nil, job))
+	job = job.Copy()
+	job.Stop = false
+	job.SubmitTime = time.Now().Add(2 * time.Minute).UnixNano()
+	must.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1003, nil, job))
+
+	summary, err = state.JobSummaryByID(nil, job.Namespace, job.ID)
+	must.NoError(t, err)
+	must.Eq(t, time.Unix(0, job.SubmitTime).Add(time.Hour).UnixNano(), summary.Deadline)
+
+	// Removing the deadline clears it from the summary
+	job = job.Copy()
+	job.Deadline = 0
+	must.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1004, nil, job))
+
+	summary, err = state.JobSummaryByID(nil, job.Namespace, job.ID)
+	must.NoError(t, err)
+	must.Zero(t, summary.Deadline)
+	must.Eq(t, 1004, summary.ModifyIndex)
+}
+
 func TestStateStore_UpsertJob_submission(t *testing.T) {
 	ci.Parallel(t)
 
@@ -5856,6 +5945,62 @@ func TestStateStore_UpdateAllocsFromClient(t *testing.T) {
 	must.False(t, watchFired(ws))
 }
 
+func TestStateStore_UpdateAllocsFromClient_IndexedSummary(t *testing.T) {
+	ci.Parallel(t)
+
+	state := testStateStore(t)
+
+	job := mock.BatchJob()
+	job.TaskGroups[0].Count = 3
+	job.TaskGroups[0].Indexed = &structs.IndexedPolicy{MaxFailures: 2}
+	must.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1000, nil, job))
+
+	var allocs []*structs.Allocation
+	for i := 0; i < 3; i++ {
+		alloc := mock.Alloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.TaskGroup = job.TaskGroups[0].Name
+		alloc.Name = structs.AllocName(job.ID, alloc.TaskGroup, uint(i))
+		alloc.ClientStatus = structs.AllocClientStatusRunning
+		allocs = append(allocs, alloc)
+	}
+
+	// The last allocation is the second attempt of its index
+	allocs[2].RescheduleTracker = &structs.RescheduleTracker{Events: []*structs.RescheduleEvent{
+		{RescheduleTime: time.Now().UnixNano(), PrevAllocID: uuid.Generate()},
+	}}
+	must.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1001, allocs))
+
+	var updates []*structs.Allocation
+	for i, status := range []string{
+		structs.AllocClientStatusComplete,
+		structs.AllocClientStatusFailed,
+		structs.AllocClientStatusFailed,
+	} {
+		updates = append(updates, &structs.Allocation{
+			ID:           allocs[i].ID,
+			NodeID:       allocs[i].NodeID,
+			ClientStatus: status,
+			JobID:        job.ID,
+			TaskGroup:    allocs[i].TaskGroup,
+		})
+	}
+	must.NoError(t, state.UpdateAllocsFromClient(structs.MsgTypeTestSetup, 1002, updates))
+
+	summary, err := state.JobSummaryByID(nil, job.Namespace, job.ID)
+	must.NoError(t, err)
+	tgSummary := summary.Summary[job.TaskGroups[0].Name]
+	must.Eq(t, 1, tgSummary.CompletedIndexes)
+	must.Eq(t, 1, tgSummary.FailedIndexes)
+
+	// Reconciling the summaries computes the same progress
+	must.NoError(t, state.ReconcileJobSummaries(1003))
+	summary, err = state.JobSummaryByID(nil, job.Namespace, job.ID)
+	must.NoError(t, err)
+	must.Eq(t, tgSummary, summary.Summary[job.TaskGroups[0].Name])
+}
+
 func TestStateStore_UpdateAllocsFromClient_ChildJob(t *testing.T) {
 	ci.Parallel(t)
 
@@ -7928,6 +8073,43 @@ func TestStateStore_GetJobStatus(t *testing.T) {
 			},
 			exp: structs.JobStatusDead,
 		},
+		{
+			name: "batch job waiting on dependencies with terminal evals",
+			setup: func(t *testing.T, txn *txn) *structs.Job {
+				j := mock.BatchJob()
+				j.DependsOn = []*structs.JobDependency{{
+					JobID:     "upstream",
+					Condition: structs.JobDependencyConditionSuccessful,
+				}}
+
+				e := mock.Eval()
+				e.JobID = j.ID
+				e.Status = structs.EvalStatusComplete
+				err := txn.Insert("evals", e)
+				must.NoError(t, err)
+				return j
+			},
+			exp: structs.JobStatusPending,
+		},
+		{
+			name: "stopped batch job waiting on dependencies is dead",
+			setup: func(t *testing.T, txn *txn) *structs.Job {
+				j := mock.BatchJob()
+				j.Stop = true
+				j.DependsOn = []*structs.JobDependency{{
+					JobID:     "upstream",
+					Condition: structs.JobDependencyConditionSuccessful,
+				}}
+
+				e := mock.Eval()
+				e.JobID = j.ID
+				e.Status = structs.EvalStatusComplete
+				err := txn.Insert("evals", e)
+				must.NoError(t, err)
+				return j
+			},
+			exp: structs.JobStatusDead,
+		},
 		{
 			name: "job has all terminal allocs, but pending eval",
 			setup: func(t *testing.T, txn *txn) *structs.Job {
@@ -8764,6 +8946,70 @@ func TestStateStore_UpsertDeploymentPromotion_Subset(t *testing.T) {
 	require.True(aout3.DeploymentStatus.Canary)
 }
 
+// Test promoting a blue/green deployment retains the blue allocations.
+func TestStateStore_UpsertDeploymentPromotion_BlueGreen(t *testing.T) {
+	ci.Parallel(t)
+
+	state := testStateStore(t)
+
+	j := mock.Job()
+	must.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1, nil, j))
+
+	d := mock.Deployment()
+	d.JobID = j.ID
+	d.TaskGroups = map[string]*structs.DeploymentState{
+		"web": {
+			DesiredTotal:    1,
+			DesiredCanaries: 1,
+			BlueGreen:       true,
+			BlueRetention:   time.Hour,
+		},
+	}
+	must.NoError(t, state.UpsertDeployment(2, d))
+
+	blue := mock.Alloc()
+	blue.JobID = j.ID
+	blue.ClientStatus = structs.AllocClientStatusRunning
+
+	green := mock.Alloc()
+	green.JobID = j.ID
+	green.DeploymentID = d.ID
+	green.DeploymentStatus = &structs.AllocDeploymentStatus{
+		Healthy: pointer.Of(true),
+		Canary:  true,
+	}
+	d.TaskGroups["web"].PlacedCanaries = []string{green.ID}
+	must.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 3, []*structs.Allocation{blue, green}))
+
+	promotedAt := time.Now()
+	req := &structs.ApplyDeploymentPromoteRequest{
+		DeploymentPromoteRequest: structs.DeploymentPromoteRequest{
+			DeploymentID: d.ID,
+			All:          true,
+			PromotedAt:   promotedAt.UnixNano(),
+		},
+	}
+	must.NoError(t, state.UpdateDeploymentPromotion(structs.MsgTypeTestSetup, 4, req))
+
+	dout, err := state.DeploymentByID(nil, d.ID)
+	must.NoError(t, err)
+	must.Eq(t, structs.DeploymentStatusDescriptionRunningRetainingBlue, dout.StatusDescription)
+	must.True(t, dout.TaskGroups["web"].Promoted)
+	must.Eq(t, promotedAt.Add(time.Hour).UnixNano(), dout.TaskGroups["web"].RetainBlueUntil.UnixNano())
+
+	// The blue allocation is retained and the green one is no longer a canary
+	blueOut, err := state.AllocByID(nil, blue.ID)
+	must.NoError(t, err)
+	must.True(t, blueOut.DeploymentStatus.Retained)
+	must.True(t, blueOut.DeploymentStatus.CanaryServices())
+	must.Eq(t, 4, blueOut.AllocModifyIndex)
+
+	greenOut, err := state.AllocByID(nil, green.ID)
+	must.NoError(t, err)
+	must.False(t, greenOut.DeploymentStatus.Retained)
+	must.False(t, greenOut.DeploymentStatus.CanaryServices())
+}
+
 // Test that allocation health can't be set against a nonexistent deployment
 func TestStateStore_UpsertDeploymentAllocHealth_Nonexistent(t *testing.T) {
 	ci.Parallel(t)
@@ -0,0 +1,224 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+const (
+	// CanaryAnalysisStatus is the status of the canary analysis of a task
+	// group in a deployment.
+	CanaryAnalysisStatusRunning = "running"
+	CanaryAnalysisStatusPassed  = "passed"
+	CanaryAnalysisStatusFailed  = "failed"
+
+	// CanaryAnalysisSignal is the name of a signal compared between the
+	// canaries and the stable allocations of a task group.
+	CanaryAnalysisSignalChecks   = "checks"
+	CanaryAnalysisSignalRestarts = "restarts"
+	CanaryAnalysisSignalOOMKills = "oom_kills"
+	CanaryAnalysisSignalCPU      = "cpu"
+	CanaryAnalysisSignalMemory   = "memory"
+)
+
+// CanaryAnalysis configures the comparison of the canaries of a deployment
+// with the stable allocations of the task group before the canaries are
+// promoted. The canaries are analyzed once they are all healthy, and the
+// deployment fails if any signal of the canaries exceeds its threshold.
+type CanaryAnalysis struct {
+	// Window is how long the canaries are analyzed for.
+	Window time.Duration
+
+	// Interval is how often the canaries and stable allocations are sampled
+	// during the window.
+	Interval time.Duration
+
+	// MaxCheckFailureRate is the maximum increase of the rate of failed
+	// Nomad service check results of the canaries, between 0 and 1.
+	MaxCheckFailureRate float64
+
+	// MaxRestarts is the maximum increase of the number of task restarts per
+	// canary.
+	MaxRestarts int
+
+	// MaxOOMKills is the maximum increase of the number of tasks killed for
+	// running out of memory per canary.
+	MaxOOMKills int
+
+	// MaxCPUIncrease and MaxMemoryIncrease are the maximum increase of the
+	// CPU and memory usage of the canaries, in percent. Zero disables the
+	// comparison.
+	MaxCPUIncrease    int
+	MaxMemoryIncrease int
+}
+
+func (a *CanaryAnalysis) Copy() *CanaryAnalysis {
+	if a == nil {
+		return nil
+	}
+	na := new(CanaryAnalysis)
+	*na = *a
+	return na
+}
+
+func (a *CanaryAnalysis) Validate(canary int) error {
+	if a == nil {
+		return nil
+	}
+
+	var mErr multierror.Error
+	if canary == 0 {
+		_ = multierror.Append(&mErr, fmt.Errorf("Canary analysis requires a Canary count greater than zero"))
+	}
+	if a.Window <= 0 {
+		_ = multierror.Append(&mErr, fmt.Errorf("Canary analysis window must be greater than zero: %v", a.Window))
+	}
+	if a.Interval <= 0 {
+		_ = multierror.Append(&mErr, fmt.Errorf("Canary analysis interval must be greater than zero: %v", a.Interval))
+	} else if a.Interval > a.Window {
+		_ = multierror.Append(&mErr, fmt.Errorf("Canary analysis interval must not be greater than window: %v > %v", a.Interval, a.Window))
+	}
+	if a.MaxCheckFailureRate < 0 || a.MaxCheckFailureRate > 1 {
+		_ = multierror.Append(&mErr, fmt.Errorf("Canary analysis max check failure rate must be between 0 and 1: %v", a.MaxCheckFailureRate))
+	}
+	if a.MaxRestarts < 0 {
+		_ = multierror.Append(&mErr, fmt.Errorf("Canary analysis max restarts can not be less than zero: %d < 0", a.MaxRestarts))
+	}
+	if a.MaxOOMKills < 0 {
+		_ = multierror.Append(&mErr, fmt.Errorf("Canary analysis max OOM kills can not be less than zero: %d < 0", a.MaxOOMKills))
+	}
+	if a.MaxCPUIncrease < 0 {
+		_ = multierror.Append(&mErr, fmt.Errorf("Canary analysis max CPU increase can not be less than zero: %d < 0", a.MaxCPUIncrease))
+	}
+	if a.MaxMemoryIncrease < 0 {
+		_ = multierror.Append(&mErr, fmt.Errorf("Canary analysis max memory increase can not be less than zero: %d < 0", a.MaxMemoryIncrease))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// CanaryAnalysisResult is the result of the canary analysis of a task group
+// in a deployment.
+type CanaryAnalysisResult struct {
+	// Status is the status of the analysis: running, passed or failed.
+	Status            string
+	StatusDescription string
+
+	// StartedAt is when the canaries were all healthy and the analysis
+	// started.
+	StartedAt time.Time
+
+	// Samples is the number of times the allocations were sampled.
+	Samples int
+
+	// Signals is the comparison of each signal.
+	Signals []*CanaryAnalysisSignal
+}
+
+func (r *CanaryAnalysisResult) Copy() *CanaryAnalysisResult {
+	if r == nil {
+		return nil
+	}
+	nr := new(CanaryAnalysisResult)
+	*nr = *r
+	nr.Signals = nil
+	for _, signal := range r.Signals {
+		nr.Signals = append(nr.Signals, signal.Copy())
+	}
+	return nr
+}
+
+// Running returns whether the analysis hasn't reached a verdict yet.
+func (r *CanaryAnalysisResult) Running() bool {
+	return r != nil && r.Status == CanaryAnalysisStatusRunning
+}
+
+// Signal returns the signal with the given name, or nil if the signal isn't
+// analyzed.
+func (r *CanaryAnalysisResult) Signal(name string) *CanaryAnalysisSignal {
+	idx := slices.IndexFunc(r.Signals, func(s *CanaryAnalysisSignal) bool {
+		return s.Name == name
+	})
+	if idx < 0 {
+		return nil
+	}
+	return r.Signals[idx]
+}
+
+// FailedSignals returns the names of the signals that exceeded their
+// threshold.
+func (r *CanaryAnalysisResult) FailedSignals() []string {
+	var failed []string
+	for _, signal := range r.Signals {
+		if signal.Failed {
+			failed = append(failed, signal.Name)
+		}
+	}
+	return failed
+}
+
+// CanaryAnalysisSignal is the comparison of a signal between the canaries
+// and the stable allocations of a task group.
+type CanaryAnalysisSignal struct {
+	// Name is the name of the signal.
+	Name string
+
+	// Canary and Stable are the values of the signal, averaged over the
+	// allocations and samples.
+	Canary float64
+	Stable float64
+
+	// Threshold is the maximum increase of the signal, in percent for CPU
+	// and memory.
+	Threshold float64
+
+	// Samples is the number of samples that had a value for the signal.
+	Samples int
+
+	// Failed marks whether the canaries exceeded the threshold.
+	Failed bool
+}
+
+func (s *CanaryAnalysisSignal) Copy() *CanaryAnalysisSignal {
+	if s == nil {
+		return nil
+	}
+	ns := new(CanaryAnalysisSignal)
+	*ns = *s
+	return ns
+}
+
+// Relative returns whether the threshold of the signal is a percentage of
+// the value of the stable allocations.
+func (s *CanaryAnalysisSignal) Relative() bool {
+	return s.Name == CanaryAnalysisSignalCPU || s.Name == CanaryAnalysisSignalMemory
+}
+
+// Exceeded returns whether the value of the canaries exceeds the threshold.
+func (s *CanaryAnalysisSignal) Exceeded() bool {
+	if s.Samples == 0 {
+		return false
+	}
+	if s.Relative() {
+		// Without a baseline there is nothing to compare the canaries with
+		if s.Stable <= 0 {
+			return false
+		}
+		return s.Canary > s.Stable*(1+s.Threshold/100)
+	}
+	return s.Canary-s.Stable > s.Threshold
+}
+
+// Sample adds a sample of the canaries and stable allocations to the
+// averages of the signal.
+func (s *CanaryAnalysisSignal) Sample(canary, stable float64) {
+	n := float64(s.Samples)
+	s.Canary = (s.Canary*n + canary) / (n + 1)
+	s.Stable = (s.Stable*n + stable) / (n + 1)
+	s.Samples++
+}
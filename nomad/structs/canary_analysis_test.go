@@ -0,0 +1,101 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/shoenig/test/must"
+)
+
+func TestCanaryAnalysis_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name     string
+		canary   int
+		analysis *CanaryAnalysis
+		expErr   string
+	}{
+		{
+			name:   "valid",
+			canary: 1,
+			analysis: &CanaryAnalysis{
+				Window:              5 * time.Minute,
+				Interval:            30 * time.Second,
+				MaxCheckFailureRate: 0.1,
+				MaxCPUIncrease:      20,
+			},
+		},
+		{
+			name:     "no canaries",
+			analysis: &CanaryAnalysis{Window: time.Minute, Interval: time.Minute},
+			expErr:   "requires a Canary count greater than zero",
+		},
+		{
+			name:     "interval greater than window",
+			canary:   1,
+			analysis: &CanaryAnalysis{Window: time.Minute, Interval: 2 * time.Minute},
+			expErr:   "interval must not be greater than window",
+		},
+		{
+			name:   "invalid check failure rate",
+			canary: 1,
+			analysis: &CanaryAnalysis{
+				Window:              time.Minute,
+				Interval:            time.Minute,
+				MaxCheckFailureRate: 1.5,
+			},
+			expErr: "must be between 0 and 1",
+		},
+		{
+			name:   "negative memory increase",
+			canary: 1,
+			analysis: &CanaryAnalysis{
+				Window:            time.Minute,
+				Interval:          time.Minute,
+				MaxMemoryIncrease: -1,
+			},
+			expErr: "max memory increase can not be less than zero",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.analysis.Validate(tc.canary)
+			if tc.expErr == "" {
+				must.NoError(t, err)
+			} else {
+				must.ErrorContains(t, err, tc.expErr)
+			}
+		})
+	}
+}
+
+func TestCanaryAnalysisSignal_Exceeded(t *testing.T) {
+	ci.Parallel(t)
+
+	// Absolute signals compare the difference with the threshold
+	restarts := &CanaryAnalysisSignal{Name: CanaryAnalysisSignalRestarts, Threshold: 1}
+	must.False(t, restarts.Exceeded())
+	restarts.Sample(2, 1)
+	must.False(t, restarts.Exceeded())
+	restarts.Sample(4, 1)
+	must.Eq(t, 3, restarts.Canary)
+	must.True(t, restarts.Exceeded())
+
+	// Relative signals compare the increase in percent with the threshold
+	memory := &CanaryAnalysisSignal{Name: CanaryAnalysisSignalMemory, Threshold: 20}
+	memory.Sample(110, 100)
+	must.False(t, memory.Exceeded())
+	memory.Sample(150, 100)
+	must.True(t, memory.Exceeded())
+
+	// Without stable allocations there is nothing to compare with
+	cpu := &CanaryAnalysisSignal{Name: CanaryAnalysisSignalCPU, Threshold: 20}
+	cpu.Sample(500, 0)
+	must.False(t, cpu.Exceeded())
+}
@@ -0,0 +1,121 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"fmt"
+	"maps"
+)
+
+const (
+	// DeploymentHook is the name of a hook of the deployment of a task group.
+	DeploymentHookPreDeploy  = "pre_deploy"
+	DeploymentHookPostDeploy = "post_deploy"
+
+	// DeploymentHookStatus is the status of a hook of a deployment.
+	DeploymentHookStatusPending  = "pending"
+	DeploymentHookStatusRunning  = "running"
+	DeploymentHookStatusComplete = "complete"
+	DeploymentHookStatusFailed   = "failed"
+)
+
+// DeploymentHook is a batch job run during the deployment of a task group,
+// either by dispatching a parameterized job or by running a task group of the
+// job being deployed. The pre-deployment hook must complete successfully
+// before the deployment places any allocation, and the post-deployment hook
+// must complete successfully once the allocations are promoted and healthy
+// before the deployment is successful.
+type DeploymentHook struct {
+	// Job is the ID of the parameterized job to dispatch, in the namespace of
+	// the job being deployed.
+	Job string
+
+	// Group is the name of the task group of the job being deployed to run
+	// as a batch job. Hook task groups aren't placed with the rest of the
+	// job.
+	Group string
+
+	// Meta is the metadata the job is dispatched with, or added to the
+	// metadata of the job running the hook task group.
+	Meta map[string]string
+}
+
+func (h *DeploymentHook) Copy() *DeploymentHook {
+	if h == nil {
+		return nil
+	}
+	nh := new(DeploymentHook)
+	*nh = *h
+	nh.Meta = maps.Clone(h.Meta)
+	return nh
+}
+
+func (h *DeploymentHook) Validate(name string) error {
+	if h == nil {
+		return nil
+	}
+	if h.Job == "" && h.Group == "" {
+		return fmt.Errorf("Deployment hook %q must specify a job or a group", name)
+	}
+	if h.Job != "" && h.Group != "" {
+		return fmt.Errorf("Deployment hook %q can not specify both a job and a group", name)
+	}
+	return nil
+}
+
+// NewDeploymentHookState returns the initial state of the given hook, or nil
+// if the hook isn't set.
+func NewDeploymentHookState(hook *DeploymentHook) *DeploymentHookState {
+	if hook == nil {
+		return nil
+	}
+	return &DeploymentHookState{
+		Status: DeploymentHookStatusPending,
+		Job:    hook.Job,
+		Group:  hook.Group,
+	}
+}
+
+// DeploymentHookState tracks a hook of the deployment of a task group.
+type DeploymentHookState struct {
+	// Status is the status of the hook: pending, running, complete or failed.
+	Status            string
+	StatusDescription string
+
+	// Job is the ID of the parameterized job of the hook.
+	Job string
+
+	// Group is the name of the hook task group, if the hook runs a task
+	// group of the job instead of a parameterized job.
+	Group string
+
+	// DispatchedJobID is the ID of the job dispatched for the hook, or of
+	// the job running the hook task group.
+	DispatchedJobID string
+}
+
+func (s *DeploymentHookState) Copy() *DeploymentHookState {
+	if s == nil {
+		return nil
+	}
+	ns := new(DeploymentHookState)
+	*ns = *s
+	return ns
+}
+
+// Pending returns whether the hook hasn't been dispatched yet.
+func (s *DeploymentHookState) Pending() bool {
+	return s != nil && s.Status == DeploymentHookStatusPending
+}
+
+// Running returns whether the dispatched job of the hook is running.
+func (s *DeploymentHookState) Running() bool {
+	return s != nil && s.Status == DeploymentHookStatusRunning
+}
+
+// Incomplete returns whether the hook is set and hasn't completed
+// successfully.
+func (s *DeploymentHookState) Incomplete() bool {
+	return s != nil && s.Status != DeploymentHookStatusComplete
+}
@@ -75,7 +75,8 @@ func (j *Job) Diff(other *Job, contextual bool) (*JobDiff, error) {
 	diff := &JobDiff{Type: DiffTypeNone}
 	var oldPrimitiveFlat, newPrimitiveFlat map[string]string
 	filter := []string{"ID", "Status", "StatusDescription", "Version", "Stable", "CreateIndex",
-		"ModifyIndex", "JobModifyIndex", "Update", "SubmitTime", "NomadTokenID", "VaultToken"}
+		"ModifyIndex", "JobModifyIndex", "Update", "SubmitTime", "FirstSubmitTime", "NomadTokenID",
+		"VaultToken", "Queued"}
 
 	if j == nil && other == nil {
 		return diff, nil
@@ -129,6 +130,28 @@ func (j *Job) Diff(other *Job, contextual bool) (*JobDiff, error) {
 		diff.Objects = append(diff.Objects, affinitiesDiff...)
 	}
 
+	// Topology spreads diff
+	topologySpreadsDiff := primitiveObjectSetDiff(
+		interfaceSlice(j.TopologySpreads),
+		interfaceSlice(other.TopologySpreads),
+		nil,
+		"TopologySpread",
+		contextual)
+	if topologySpreadsDiff != nil {
+		diff.Objects = append(diff.Objects, topologySpreadsDiff...)
+	}
+
+	// Job affinities diff
+	jobAffinitiesDiff := primitiveObjectSetDiff(
+		interfaceSlice(j.JobAffinities),
+		interfaceSlice(other.JobAffinities),
+		nil,
+		"JobAffinity",
+		contextual)
+	if jobAffinitiesDiff != nil {
+		diff.Objects = append(diff.Objects, jobAffinitiesDiff...)
+	}
+
 	// Task groups diff
 	tgs, err := taskGroupDiffs(j.TaskGroups, other.TaskGroups, contextual)
 	if err != nil {
@@ -151,6 +174,17 @@ func (j *Job) Diff(other *Job, contextual bool) (*JobDiff, error) {
 		diff.Objects = append(diff.Objects, mrDiff)
 	}
 
+	// Dependencies diff
+	depDiff := primitiveObjectSetDiff(
+		interfaceSlice(j.DependsOn),
+		interfaceSlice(other.DependsOn),
+		nil,
+		"DependsOn",
+		contextual)
+	if depDiff != nil {
+		diff.Objects = append(diff.Objects, depDiff...)
+	}
+
 	// UI diff
 	if uiDiff := uiDiff(j.UI, other.UI, contextual); uiDiff != nil {
 		diff.Objects = append(diff.Objects, uiDiff)
@@ -262,6 +296,20 @@ func (tg *TaskGroup) Diff(other *TaskGroup, contextual bool) (*TaskGroupDiff, er
 		}
 	}
 
+	// MaxRunDuration diff
+	if oldPrimitiveFlat != nil && newPrimitiveFlat != nil {
+		if tg.MaxRunDuration == nil {
+			oldPrimitiveFlat["MaxRunDuration"] = ""
+		} else {
+			oldPrimitiveFlat["MaxRunDuration"] = fmt.Sprintf("%d", *tg.MaxRunDuration)
+		}
+		if other.MaxRunDuration == nil {
+			newPrimitiveFlat["MaxRunDuration"] = ""
+		} else {
+			newPrimitiveFlat["MaxRunDuration"] = fmt.Sprintf("%d", *other.MaxRunDuration)
+		}
+	}
+
 	// Diff the primitive fields.
 	diff.Fields = fieldDiffs(oldPrimitiveFlat, newPrimitiveFlat, false)
 
@@ -287,8 +335,31 @@ func (tg *TaskGroup) Diff(other *TaskGroup, contextual bool) (*TaskGroupDiff, er
 		diff.Objects = append(diff.Objects, affinitiesDiff...)
 	}
 
+	// Topology spreads diff
+	topologySpreadsDiff := primitiveObjectSetDiff(
+		interfaceSlice(tg.TopologySpreads),
+		interfaceSlice(other.TopologySpreads),
+		nil,
+		"TopologySpread",
+		contextual)
+	if topologySpreadsDiff != nil {
+		diff.Objects = append(diff.Objects, topologySpreadsDiff...)
+	}
+
+	// Job affinities diff
+	jobAffinitiesDiff := primitiveObjectSetDiff(
+		interfaceSlice(tg.JobAffinities),
+		interfaceSlice(other.JobAffinities),
+		nil,
+		"JobAffinity",
+		contextual)
+	if jobAffinitiesDiff != nil {
+		diff.Objects = append(diff.Objects, jobAffinitiesDiff...)
+	}
+
 	// Restart policy diff
-	rDiff := primitiveObjectDiff(tg.RestartPolicy, other.RestartPolicy, nil, "RestartPolicy", contextual)
+	rDiff := restartPolicyDiff(tg.RestartPolicy, other.RestartPolicy, contextual)
+	rDiff = exitRulesDiff(rDiff, "RestartPolicy", restartExitRules(tg.RestartPolicy), restartExitRules(other.RestartPolicy), contextual)
 	if rDiff != nil {
 		diff.Objects = append(diff.Objects, rDiff)
 	}
@@ -299,8 +370,15 @@ func (tg *TaskGroup) Diff(other *TaskGroup, contextual bool) (*TaskGroupDiff, er
 		diff.Objects = append(diff.Objects, migrateDiff)
 	}
 
+	// Indexed policy diff
+	indexedDiff := primitiveObjectDiff(tg.Indexed, other.Indexed, nil, "Indexed", contextual)
+	if indexedDiff != nil {
+		diff.Objects = append(diff.Objects, indexedDiff)
+	}
+
 	// Reschedule policy diff
 	reschedDiff := primitiveObjectDiff(tg.ReschedulePolicy, other.ReschedulePolicy, nil, "ReschedulePolicy", contextual)
+	reschedDiff = exitRulesDiff(reschedDiff, "ReschedulePolicy", rescheduleExitRules(tg.ReschedulePolicy), rescheduleExitRules(other.ReschedulePolicy), contextual)
 	if reschedDiff != nil {
 		diff.Objects = append(diff.Objects, reschedDiff)
 	}
@@ -316,9 +394,33 @@ func (tg *TaskGroup) Diff(other *TaskGroup, contextual bool) (*TaskGroupDiff, er
 		diff.Objects = append(diff.Objects, consulDiff)
 	}
 
+	// Network policy diff
+	if npDiff := networkPolicyDiff(tg.NetworkPolicy, other.NetworkPolicy, contextual); npDiff != nil {
+		diff.Objects = append(diff.Objects, npDiff)
+	}
+
 	// Update diff
 	// COMPAT: Remove "Stagger" in 0.7.0.
-	if uDiff := primitiveObjectDiff(tg.Update, other.Update, []string{"Stagger"}, "Update", contextual); uDiff != nil {
+	uDiff := primitiveObjectDiff(tg.Update, other.Update, []string{"Stagger"}, "Update", contextual)
+	if aDiff := canaryAnalysisDiff(tg.Update, other.Update, contextual); aDiff != nil {
+		if uDiff == nil {
+			uDiff = &ObjectDiff{Type: DiffTypeEdited, Name: "Update"}
+		}
+		uDiff.Objects = append(uDiff.Objects, aDiff)
+	}
+	if bgDiff := blueGreenDiff(tg.Update, other.Update, contextual); bgDiff != nil {
+		if uDiff == nil {
+			uDiff = &ObjectDiff{Type: DiffTypeEdited, Name: "Update"}
+		}
+		uDiff.Objects = append(uDiff.Objects, bgDiff)
+	}
+	if hDiffs := deploymentHookDiffs(tg.Update, other.Update, contextual); hDiffs != nil {
+		if uDiff == nil {
+			uDiff = &ObjectDiff{Type: DiffTypeEdited, Name: "Update"}
+		}
+		uDiff.Objects = append(uDiff.Objects, hDiffs...)
+	}
+	if uDiff != nil {
 		diff.Objects = append(diff.Objects, uDiff)
 	}
 
@@ -504,7 +606,7 @@ func (t *Task) Diff(other *Task, contextual bool) (*TaskDiff, error) {
 	}
 
 	// LogConfig diff
-	lDiff := primitiveObjectDiff(t.LogConfig, other.LogConfig, nil, "LogConfig", contextual)
+	lDiff := logConfigDiff(t.LogConfig, other.LogConfig, contextual)
 	if lDiff != nil {
 		diff.Objects = append(diff.Objects, lDiff)
 	}
@@ -526,6 +628,17 @@ func (t *Task) Diff(other *Task, contextual bool) (*TaskDiff, error) {
 		diff.Objects = append(diff.Objects, diffs...)
 	}
 
+	// Dependencies diff
+	depDiff := primitiveObjectSetDiff(
+		interfaceSlice(t.DependsOn),
+		interfaceSlice(other.DependsOn),
+		nil,
+		"DependsOn",
+		contextual)
+	if depDiff != nil {
+		diff.Objects = append(diff.Objects, depDiff...)
+	}
+
 	// Services diff
 	if sDiffs := serviceDiffs(t.Services, other.Services, contextual); sDiffs != nil {
 		diff.Objects = append(diff.Objects, sDiffs...)
@@ -1704,7 +1817,7 @@ func sidecarTaskDiff(old, new *SidecarTask, contextual bool) *ObjectDiff {
 	}
 
 	// LogConfig diff
-	lDiff := primitiveObjectDiff(old.LogConfig, new.LogConfig, nil, "LogConfig", contextual)
+	lDiff := logConfigDiff(old.LogConfig, new.LogConfig, contextual)
 	if lDiff != nil {
 		diff.Objects = append(diff.Objects, lDiff)
 	}
@@ -1712,6 +1825,34 @@ func sidecarTaskDiff(old, new *SidecarTask, contextual bool) *ObjectDiff {
 	return diff
 }
 
+// logConfigDiff returns the diff of two LogConfig objects, including their
+// sinks. If contextual diff is enabled, all fields will be returned, even if no
+// diff occurred.
+func logConfigDiff(old, new *LogConfig, contextual bool) *ObjectDiff {
+	diff := primitiveObjectDiff(old, new, nil, "LogConfig", contextual)
+
+	var oldSinks, newSinks []*LogSink
+	if old != nil {
+		oldSinks = old.Sinks
+	}
+	if new != nil {
+		newSinks = new.Sinks
+	}
+	sinkDiffs := primitiveObjectSetDiff(
+		interfaceSlice(oldSinks),
+		interfaceSlice(newSinks),
+		nil, "Sink", contextual)
+	if len(sinkDiffs) == 0 {
+		return diff
+	}
+
+	if diff == nil {
+		diff = &ObjectDiff{Type: DiffTypeEdited, Name: "LogConfig"}
+	}
+	diff.Objects = append(diff.Objects, sinkDiffs...)
+	return diff
+}
+
 // consulProxyDiff returns the diff of two ConsulProxy objects.
 // If contextual diff is enabled, all fields will be returned, even if no diff occurred.
 func consulProxyDiff(old, new *ConsulProxy, contextual bool) *ObjectDiff {
@@ -2203,6 +2344,147 @@ func parameterizedJobDiff(old, new *ParameterizedJobConfig, contextual bool) *Ob
 		diff.Objects = append(diff.Objects, requiredDiff)
 	}
 
+	// Dispatch queue diff
+	if queueDiff := primitiveObjectDiff(old.DispatchQueue, new.DispatchQueue, nil, "DispatchQueue", contextual); queueDiff != nil {
+		diff.Objects = append(diff.Objects, queueDiff)
+	}
+
+	return diff
+}
+
+// restartPolicyDiff returns the diff of the primitive fields of two restart
+// policies, including the jitter.
+func restartPolicyDiff(old, new *RestartPolicy, contextual bool) *ObjectDiff {
+	diff := primitiveObjectDiff(old, new, nil, "RestartPolicy", contextual)
+
+	// Add the pointer primitive fields.
+	var oldJitter, newJitter string
+	if old != nil && old.Jitter != nil {
+		oldJitter = fmt.Sprintf("%v", *old.Jitter)
+	}
+	if new != nil && new.Jitter != nil {
+		newJitter = fmt.Sprintf("%v", *new.Jitter)
+	}
+	jitterDiff := fieldDiff(oldJitter, newJitter, "Jitter", contextual)
+	if jitterDiff == nil || diff == nil && jitterDiff.Type == DiffTypeNone {
+		return diff
+	}
+
+	if diff == nil {
+		diff = &ObjectDiff{Type: DiffTypeEdited, Name: "RestartPolicy"}
+	}
+	diff.Fields = append(diff.Fields, jitterDiff)
+	sort.Sort(FieldDiffs(diff.Fields))
+	return diff
+}
+
+func restartExitRules(p *RestartPolicy) []*ExitRule {
+	if p == nil {
+		return nil
+	}
+	return p.ExitRules
+}
+
+func rescheduleExitRules(p *ReschedulePolicy) []*ExitRule {
+	if p == nil {
+		return nil
+	}
+	return p.ExitRules
+}
+
+// exitRulesDiff adds the diff of the exit rules of a policy to the diff of the
+// policy, which is created if the policy is otherwise unchanged. Rules are
+// compared in order since the first matching rule applies.
+func exitRulesDiff(diff *ObjectDiff, name string, old, new []*ExitRule, contextual bool) *ObjectDiff {
+	for i := 0; i < max(len(old), len(new)); i++ {
+		var oldRule, newRule *ExitRule
+		if i < len(old) {
+			oldRule = old[i]
+		}
+		if i < len(new) {
+			newRule = new[i]
+		}
+
+		ruleDiff := exitRuleDiff(oldRule, newRule, contextual)
+		if ruleDiff == nil {
+			continue
+		}
+		if diff == nil {
+			diff = &ObjectDiff{Type: DiffTypeEdited, Name: name}
+		}
+		diff.Objects = append(diff.Objects, ruleDiff)
+	}
+	return diff
+}
+
+// networkPolicyDiff returns the diff of a task group network policy, including
+// its allow rules.
+func networkPolicyDiff(old, new *NetworkPolicy, contextual bool) *ObjectDiff {
+	if old.Equal(new) {
+		return nil
+	}
+
+	diff := primitiveObjectDiff(old, new, []string{"Allow"}, "NetworkPolicy", contextual)
+	if diff == nil {
+		diff = &ObjectDiff{Name: "NetworkPolicy"}
+		switch {
+		case old == nil:
+			diff.Type = DiffTypeAdded
+		case new == nil:
+			diff.Type = DiffTypeDeleted
+		default:
+			diff.Type = DiffTypeEdited
+		}
+	}
+
+	var oldRules, newRules []*NetworkPolicyRule
+	if old != nil {
+		oldRules = old.Allow
+	}
+	if new != nil {
+		newRules = new.Allow
+	}
+	diff.Objects = primitiveObjectSetDiff(interfaceSlice(oldRules), interfaceSlice(newRules), nil, "Allow", contextual)
+	return diff
+}
+
+func exitRuleDiff(old, new *ExitRule, contextual bool) *ObjectDiff {
+	diff := &ObjectDiff{Type: DiffTypeNone, Name: "ExitRule"}
+	var oldPrimitiveFlat, newPrimitiveFlat map[string]string
+
+	if reflect.DeepEqual(old, new) {
+		return nil
+	} else if old == nil {
+		old = &ExitRule{}
+		diff.Type = DiffTypeAdded
+		newPrimitiveFlat = flatmap.Flatten(new, nil, true)
+	} else if new == nil {
+		new = &ExitRule{}
+		diff.Type = DiffTypeDeleted
+		oldPrimitiveFlat = flatmap.Flatten(old, nil, true)
+	} else {
+		diff.Type = DiffTypeEdited
+		oldPrimitiveFlat = flatmap.Flatten(old, nil, true)
+		newPrimitiveFlat = flatmap.Flatten(new, nil, true)
+	}
+
+	// Diff the primitive fields.
+	diff.Fields = fieldDiffs(oldPrimitiveFlat, newPrimitiveFlat, contextual)
+
+	exitCodes := func(codes []int) []string {
+		out := make([]string, len(codes))
+		for i, code := range codes {
+			out[i] = strconv.Itoa(code)
+		}
+		return out
+	}
+	if codesDiff := stringSetDiff(exitCodes(old.ExitCodes), exitCodes(new.ExitCodes), "ExitCodes", contextual); codesDiff != nil {
+		diff.Objects = append(diff.Objects, codesDiff)
+	}
+	if signalsDiff := stringSetDiff(old.Signals, new.Signals, "Signals", contextual); signalsDiff != nil {
+		diff.Objects = append(diff.Objects, signalsDiff)
+	}
+
 	return diff
 }
 
@@ -2725,6 +3007,117 @@ func disconectStrategyDiffs(old, new *DisconnectStrategy, contextual bool) *Obje
 	return diff
 }
 
+// canaryAnalysisDiff diffs the canary analysis of two update strategies.
+func canaryAnalysisDiff(old, new *UpdateStrategy, contextual bool) *ObjectDiff {
+	var oldAnalysis, newAnalysis *CanaryAnalysis
+	if old != nil {
+		oldAnalysis = old.Analysis
+	}
+	if new != nil {
+		newAnalysis = new.Analysis
+	}
+
+	diff := &ObjectDiff{Type: DiffTypeNone, Name: "Analysis"}
+	var oldAnalysisFlat, newAnalysisFlat map[string]string
+
+	if reflect.DeepEqual(oldAnalysis, newAnalysis) {
+		return nil
+	} else if oldAnalysis == nil {
+		diff.Type = DiffTypeAdded
+		newAnalysisFlat = flatmap.Flatten(newAnalysis, nil, false)
+	} else if newAnalysis == nil {
+		diff.Type = DiffTypeDeleted
+		oldAnalysisFlat = flatmap.Flatten(oldAnalysis, nil, false)
+	} else {
+		diff.Type = DiffTypeEdited
+		oldAnalysisFlat = flatmap.Flatten(oldAnalysis, nil, false)
+		newAnalysisFlat = flatmap.Flatten(newAnalysis, nil, false)
+	}
+
+	// Diff the primitive fields.
+	diff.Fields = fieldDiffs(oldAnalysisFlat, newAnalysisFlat, contextual)
+
+	return diff
+}
+
+// blueGreenDiff diffs the blue/green strategy of two update strategies.
+func blueGreenDiff(old, new *UpdateStrategy, contextual bool) *ObjectDiff {
+	var oldBlueGreen, newBlueGreen *BlueGreenStrategy
+	if old != nil {
+		oldBlueGreen = old.BlueGreen
+	}
+	if new != nil {
+		newBlueGreen = new.BlueGreen
+	}
+
+	diff := &ObjectDiff{Type: DiffTypeNone, Name: "BlueGreen"}
+	var oldBlueGreenFlat, newBlueGreenFlat map[string]string
+
+	if reflect.DeepEqual(oldBlueGreen, newBlueGreen) {
+		return nil
+	} else if oldBlueGreen == nil {
+		diff.Type = DiffTypeAdded
+		newBlueGreenFlat = flatmap.Flatten(newBlueGreen, nil, false)
+	} else if newBlueGreen == nil {
+		diff.Type = DiffTypeDeleted
+		oldBlueGreenFlat = flatmap.Flatten(oldBlueGreen, nil, false)
+	} else {
+		diff.Type = DiffTypeEdited
+		oldBlueGreenFlat = flatmap.Flatten(oldBlueGreen, nil, false)
+		newBlueGreenFlat = flatmap.Flatten(newBlueGreen, nil, false)
+	}
+
+	// Diff the primitive fields.
+	diff.Fields = fieldDiffs(oldBlueGreenFlat, newBlueGreenFlat, contextual)
+
+	return diff
+}
+
+// deploymentHookDiffs diffs the deployment hooks of two update strategies.
+func deploymentHookDiffs(old, new *UpdateStrategy, contextual bool) []*ObjectDiff {
+	var oldPre, newPre, oldPost, newPost *DeploymentHook
+	if old != nil {
+		oldPre, oldPost = old.PreDeploy, old.PostDeploy
+	}
+	if new != nil {
+		newPre, newPost = new.PreDeploy, new.PostDeploy
+	}
+
+	var diffs []*ObjectDiff
+	if diff := deploymentHookDiff(oldPre, newPre, "PreDeploy", contextual); diff != nil {
+		diffs = append(diffs, diff)
+	}
+	if diff := deploymentHookDiff(oldPost, newPost, "PostDeploy", contextual); diff != nil {
+		diffs = append(diffs, diff)
+	}
+	return diffs
+}
+
+// deploymentHookDiff diffs a deployment hook.
+func deploymentHookDiff(old, new *DeploymentHook, name string, contextual bool) *ObjectDiff {
+	diff := &ObjectDiff{Type: DiffTypeNone, Name: name}
+	var oldHookFlat, newHookFlat map[string]string
+
+	if reflect.DeepEqual(old, new) {
+		return nil
+	} else if old == nil {
+		diff.Type = DiffTypeAdded
+		newHookFlat = flatmap.Flatten(new, nil, false)
+	} else if new == nil {
+		diff.Type = DiffTypeDeleted
+		oldHookFlat = flatmap.Flatten(old, nil, false)
+	} else {
+		diff.Type = DiffTypeEdited
+		oldHookFlat = flatmap.Flatten(old, nil, false)
+		newHookFlat = flatmap.Flatten(new, nil, false)
+	}
+
+	// Diff the primitive fields.
+	diff.Fields = fieldDiffs(oldHookFlat, newHookFlat, contextual)
+
+	return diff
+}
+
 // networkResourceDiffs diffs a set of NetworkResources. If contextual diff is enabled,
 // non-changed fields will still be returned.
 func networkResourceDiffs(old, new []*NetworkResource, contextual bool) []*ObjectDiff {
@@ -159,6 +159,12 @@ func TestJobDiff(t *testing.T) {
 						Old:  "true",
 						New:  "",
 					},
+					{
+						Type: DiffTypeDeleted,
+						Name: "Deadline",
+						Old:  "0",
+						New:  "",
+					},
 					{
 						Type: DiffTypeDeleted,
 						Name: "Dispatched",
@@ -228,6 +234,12 @@ func TestJobDiff(t *testing.T) {
 						Old:  "",
 						New:  "true",
 					},
+					{
+						Type: DiffTypeAdded,
+						Name: "Deadline",
+						Old:  "",
+						New:  "0",
+					},
 					{
 						Type: DiffTypeAdded,
 						Name: "Dispatched",
@@ -528,6 +540,18 @@ func TestJobDiff(t *testing.T) {
 						Type: DiffTypeAdded,
 						Name: "Periodic",
 						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeAdded,
+								Name: "CatchupMaxAge",
+								Old:  "",
+								New:  "0",
+							},
+							{
+								Type: DiffTypeAdded,
+								Name: "CatchupMaxCount",
+								Old:  "",
+								New:  "0",
+							},
 							{
 								Type: DiffTypeAdded,
 								Name: "Enabled",
@@ -582,6 +606,18 @@ func TestJobDiff(t *testing.T) {
 						Type: DiffTypeAdded,
 						Name: "Periodic",
 						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeAdded,
+								Name: "CatchupMaxAge",
+								Old:  "",
+								New:  "0",
+							},
+							{
+								Type: DiffTypeAdded,
+								Name: "CatchupMaxCount",
+								Old:  "",
+								New:  "0",
+							},
 							{
 								Type: DiffTypeAdded,
 								Name: "Enabled",
@@ -650,6 +686,18 @@ func TestJobDiff(t *testing.T) {
 						Type: DiffTypeDeleted,
 						Name: "Periodic",
 						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeDeleted,
+								Name: "CatchupMaxAge",
+								Old:  "0",
+								New:  "",
+							},
+							{
+								Type: DiffTypeDeleted,
+								Name: "CatchupMaxCount",
+								Old:  "0",
+								New:  "",
+							},
 							{
 								Type: DiffTypeDeleted,
 								Name: "Enabled",
@@ -1027,6 +1075,30 @@ func TestJobDiff(t *testing.T) {
 						Type: DiffTypeEdited,
 						Name: "Periodic",
 						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeNone,
+								Name: "Catchup",
+								Old:  "",
+								New:  "",
+							},
+							{
+								Type: DiffTypeNone,
+								Name: "CatchupMaxAge",
+								Old:  "0",
+								New:  "0",
+							},
+							{
+								Type: DiffTypeNone,
+								Name: "CatchupMaxCount",
+								Old:  "0",
+								New:  "0",
+							},
+							{
+								Type: DiffTypeNone,
+								Name: "Concurrency",
+								Old:  "",
+								New:  "",
+							},
 							{
 								Type: DiffTypeEdited,
 								Name: "Enabled",
@@ -1263,6 +1335,12 @@ func TestJobDiff(t *testing.T) {
 						Type: DiffTypeAdded,
 						Name: "ParameterizedJob",
 						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeAdded,
+								Name: "MaxConcurrent",
+								Old:  "",
+								New:  "0",
+							},
 							{
 								Type: DiffTypeAdded,
 								Name: "Payload",
@@ -1317,6 +1395,12 @@ func TestJobDiff(t *testing.T) {
 						Type: DiffTypeDeleted,
 						Name: "ParameterizedJob",
 						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeDeleted,
+								Name: "MaxConcurrent",
+								Old:  "0",
+								New:  "",
+							},
 							{
 								Type: DiffTypeDeleted,
 								Name: "Payload",
@@ -1450,6 +1534,18 @@ func TestJobDiff(t *testing.T) {
 						Type: DiffTypeEdited,
 						Name: "ParameterizedJob",
 						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeNone,
+								Name: "Concurrency",
+								Old:  "",
+								New:  "",
+							},
+							{
+								Type: DiffTypeNone,
+								Name: "MaxConcurrent",
+								Old:  "0",
+								New:  "0",
+							},
 							{
 								Type: DiffTypeEdited,
 								Name: "Payload",
@@ -2201,6 +2297,7 @@ func TestTaskGroupDiff(t *testing.T) {
 					Interval: 1 * time.Second,
 					Delay:    1 * time.Second,
 					Mode:     "fail",
+					Jitter:   pointer.Of(0.5),
 				},
 			},
 			Expected: &TaskGroupDiff{
@@ -2228,6 +2325,18 @@ func TestTaskGroupDiff(t *testing.T) {
 								Old:  "",
 								New:  "1000000000",
 							},
+							{
+								Type: DiffTypeAdded,
+								Name: "Jitter",
+								Old:  "",
+								New:  "0.5",
+							},
+							{
+								Type: DiffTypeAdded,
+								Name: "MaxDelay",
+								Old:  "",
+								New:  "0",
+							},
 							{
 								Type: DiffTypeAdded,
 								Name: "Mode",
@@ -2253,6 +2362,7 @@ func TestTaskGroupDiff(t *testing.T) {
 					Interval: 1 * time.Second,
 					Delay:    1 * time.Second,
 					Mode:     "fail",
+					Jitter:   pointer.Of(0.25),
 				},
 			},
 			New: &TaskGroup{},
@@ -2281,6 +2391,18 @@ func TestTaskGroupDiff(t *testing.T) {
 								Old:  "1000000000",
 								New:  "",
 							},
+							{
+								Type: DiffTypeDeleted,
+								Name: "Jitter",
+								Old:  "0.25",
+								New:  "",
+							},
+							{
+								Type: DiffTypeDeleted,
+								Name: "MaxDelay",
+								Old:  "0",
+								New:  "",
+							},
 							{
 								Type: DiffTypeDeleted,
 								Name: "Mode",
@@ -2378,6 +2500,7 @@ func TestTaskGroupDiff(t *testing.T) {
 					Delay:           1 * time.Second,
 					Mode:            "fail",
 					RenderTemplates: true,
+					Jitter:          pointer.Of(0.5),
 				},
 			},
 			Expected: &TaskGroupDiff{
@@ -2399,12 +2522,30 @@ func TestTaskGroupDiff(t *testing.T) {
 								Old:  "1000000000",
 								New:  "1000000000",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "DelayFunction",
+								Old:  "",
+								New:  "",
+							},
 							{
 								Type: DiffTypeEdited,
 								Name: "Interval",
 								Old:  "1000000000",
 								New:  "2000000000",
 							},
+							{
+								Type: DiffTypeAdded,
+								Name: "Jitter",
+								Old:  "",
+								New:  "0.5",
+							},
+							{
+								Type: DiffTypeNone,
+								Name: "MaxDelay",
+								Old:  "0",
+								New:  "0",
+							},
 							{
 								Type: DiffTypeNone,
 								Name: "Mode",
@@ -2422,6 +2563,126 @@ func TestTaskGroupDiff(t *testing.T) {
 				},
 			},
 		},
+		{
+			TestCase: "NetworkPolicy edited",
+			Old: &TaskGroup{
+				NetworkPolicy: &NetworkPolicy{
+					Default: NetworkPolicyDefaultDeny,
+					Allow: []*NetworkPolicyRule{
+						{Job: "web"},
+					},
+				},
+			},
+			New: &TaskGroup{
+				NetworkPolicy: &NetworkPolicy{
+					Default: NetworkPolicyDefaultDeny,
+					Allow: []*NetworkPolicyRule{
+						{Job: "web"},
+						{Namespace: "ops"},
+					},
+				},
+			},
+			Expected: &TaskGroupDiff{
+				Type: DiffTypeEdited,
+				Objects: []*ObjectDiff{
+					{
+						Type: DiffTypeEdited,
+						Name: "NetworkPolicy",
+						Objects: []*ObjectDiff{
+							{
+								Type: DiffTypeAdded,
+								Name: "Allow",
+								Fields: []*FieldDiff{
+									{
+										Type: DiffTypeAdded,
+										Name: "Namespace",
+										Old:  "",
+										New:  "ops",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			TestCase: "RestartPolicy exit rules edited",
+			Old: &TaskGroup{
+				RestartPolicy: &RestartPolicy{
+					Attempts: 1,
+					Mode:     "fail",
+					ExitRules: []*ExitRule{
+						{ExitCodes: []int{0, 3}, Action: "complete"},
+					},
+				},
+			},
+			New: &TaskGroup{
+				RestartPolicy: &RestartPolicy{
+					Attempts: 1,
+					Mode:     "fail",
+					ExitRules: []*ExitRule{
+						{ExitCodes: []int{0}, Action: "complete"},
+						{Signals: []string{"SIGKILL"}, Action: "fail"},
+					},
+				},
+			},
+			Expected: &TaskGroupDiff{
+				Type: DiffTypeEdited,
+				Objects: []*ObjectDiff{
+					{
+						Type: DiffTypeEdited,
+						Name: "RestartPolicy",
+						Objects: []*ObjectDiff{
+							{
+								Type: DiffTypeEdited,
+								Name: "ExitRule",
+								Objects: []*ObjectDiff{
+									{
+										Type: DiffTypeDeleted,
+										Name: "ExitCodes",
+										Fields: []*FieldDiff{
+											{
+												Type: DiffTypeDeleted,
+												Name: "ExitCodes",
+												Old:  "3",
+												New:  "",
+											},
+										},
+									},
+								},
+							},
+							{
+								Type: DiffTypeAdded,
+								Name: "ExitRule",
+								Fields: []*FieldDiff{
+									{
+										Type: DiffTypeAdded,
+										Name: "Action",
+										Old:  "",
+										New:  "fail",
+									},
+								},
+								Objects: []*ObjectDiff{
+									{
+										Type: DiffTypeAdded,
+										Name: "Signals",
+										Fields: []*FieldDiff{
+											{
+												Type: DiffTypeAdded,
+												Name: "Signals",
+												Old:  "",
+												New:  "SIGKILL",
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			TestCase: "ReschedulePolicy added",
 			Old:      &TaskGroup{},
@@ -4566,6 +4827,12 @@ func TestTaskGroupDiff(t *testing.T) {
 								Old:  "",
 								New:  "false",
 							},
+							{
+								Type: DiffTypeAdded,
+								Name: "MaxRunDuration",
+								Old:  "",
+								New:  "0",
+							},
 							{
 								Type: DiffTypeAdded,
 								Name: "ShutdownDelay",
@@ -4612,6 +4879,12 @@ func TestTaskGroupDiff(t *testing.T) {
 								Old:  "false",
 								New:  "",
 							},
+							{
+								Type: DiffTypeDeleted,
+								Name: "MaxRunDuration",
+								Old:  "0",
+								New:  "",
+							},
 							{
 								Type: DiffTypeDeleted,
 								Name: "ShutdownDelay",
@@ -5724,9 +5997,13 @@ func TestTaskDiff(t *testing.T) {
 			Old:  &Task{},
 			New: &Task{
 				LogConfig: &LogConfig{
-					MaxFiles:      1,
-					MaxFileSizeMB: 10,
-					Disabled:      true,
+					MaxFiles:       1,
+					MaxFileSizeMB:  10,
+					Disabled:       true,
+					Compress:       "zstd",
+					MaxAge:         24 * time.Hour,
+					MaxTotalSizeMB: 50,
+					Timestamps:     true,
 				},
 			},
 			Expected: &TaskDiff{
@@ -5736,12 +6013,24 @@ func TestTaskDiff(t *testing.T) {
 						Type: DiffTypeAdded,
 						Name: "LogConfig",
 						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeAdded,
+								Name: "Compress",
+								Old:  "",
+								New:  "zstd",
+							},
 							{
 								Type: DiffTypeAdded,
 								Name: "Disabled",
 								Old:  "",
 								New:  "true",
 							},
+							{
+								Type: DiffTypeAdded,
+								Name: "MaxAge",
+								Old:  "",
+								New:  "86400000000000",
+							},
 							{
 								Type: DiffTypeAdded,
 								Name: "MaxFileSizeMB",
@@ -5754,6 +6043,18 @@ func TestTaskDiff(t *testing.T) {
 								Old:  "",
 								New:  "1",
 							},
+							{
+								Type: DiffTypeAdded,
+								Name: "MaxTotalSizeMB",
+								Old:  "",
+								New:  "50",
+							},
+							{
+								Type: DiffTypeAdded,
+								Name: "Timestamps",
+								Old:  "",
+								New:  "true",
+							},
 						},
 					},
 				},
@@ -5782,6 +6083,12 @@ func TestTaskDiff(t *testing.T) {
 								Old:  "true",
 								New:  "",
 							},
+							{
+								Type: DiffTypeDeleted,
+								Name: "MaxAge",
+								Old:  "0",
+								New:  "",
+							},
 							{
 								Type: DiffTypeDeleted,
 								Name: "MaxFileSizeMB",
@@ -5794,6 +6101,18 @@ func TestTaskDiff(t *testing.T) {
 								Old:  "1",
 								New:  "",
 							},
+							{
+								Type: DiffTypeDeleted,
+								Name: "MaxTotalSizeMB",
+								Old:  "0",
+								New:  "",
+							},
+							{
+								Type: DiffTypeDeleted,
+								Name: "Timestamps",
+								Old:  "false",
+								New:  "",
+							},
 						},
 					},
 				},
@@ -5869,12 +6188,24 @@ func TestTaskDiff(t *testing.T) {
 						Type: DiffTypeEdited,
 						Name: "LogConfig",
 						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeNone,
+								Name: "Compress",
+								Old:  "",
+								New:  "",
+							},
 							{
 								Type: DiffTypeEdited,
 								Name: "Disabled",
 								Old:  "false",
 								New:  "true",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "MaxAge",
+								Old:  "0",
+								New:  "0",
+							},
 							{
 								Type: DiffTypeEdited,
 								Name: "MaxFileSizeMB",
@@ -5887,6 +6218,96 @@ func TestTaskDiff(t *testing.T) {
 								Old:  "1",
 								New:  "1",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "MaxTotalSizeMB",
+								Old:  "0",
+								New:  "0",
+							},
+							{
+								Type: DiffTypeNone,
+								Name: "Timestamps",
+								Old:  "false",
+								New:  "false",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			Name: "LogConfig sink added",
+			Old: &Task{
+				LogConfig: &LogConfig{
+					MaxFiles:      1,
+					MaxFileSizeMB: 10,
+				},
+			},
+			New: &Task{
+				LogConfig: &LogConfig{
+					MaxFiles:      1,
+					MaxFileSizeMB: 10,
+					Sinks: []*LogSink{
+						{
+							Type:          LogSinkTypeSyslog,
+							Address:       "udp://127.0.0.1:514",
+							BufferSize:    1024,
+							RetryAttempts: 3,
+							RetryInterval: time.Second,
+							DropPolicy:    LogSinkDropOldest,
+						},
+					},
+				},
+			},
+			Expected: &TaskDiff{
+				Type: DiffTypeEdited,
+				Objects: []*ObjectDiff{
+					{
+						Type: DiffTypeEdited,
+						Name: "LogConfig",
+						Objects: []*ObjectDiff{
+							{
+								Type: DiffTypeAdded,
+								Name: "Sink",
+								Fields: []*FieldDiff{
+									{
+										Type: DiffTypeAdded,
+										Name: "Address",
+										Old:  "",
+										New:  "udp://127.0.0.1:514",
+									},
+									{
+										Type: DiffTypeAdded,
+										Name: "BufferSize",
+										Old:  "",
+										New:  "1024",
+									},
+									{
+										Type: DiffTypeAdded,
+										Name: "DropPolicy",
+										Old:  "",
+										New:  "oldest",
+									},
+									{
+										Type: DiffTypeAdded,
+										Name: "RetryAttempts",
+										Old:  "",
+										New:  "3",
+									},
+									{
+										Type: DiffTypeAdded,
+										Name: "RetryInterval",
+										Old:  "",
+										New:  "1000000000",
+									},
+									{
+										Type: DiffTypeAdded,
+										Name: "Type",
+										Old:  "",
+										New:  "syslog",
+									},
+								},
+							},
 						},
 					},
 				},
@@ -0,0 +1,159 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"syscall"
+
+	"github.com/hashicorp/consul-template/signals"
+	multierror "github.com/hashicorp/go-multierror"
+)
+
+const (
+	// ExitRuleAction is the action taken when a task exits with an exit code
+	// or signal matching an exit rule. The restart, complete and fail actions
+	// apply to restart policies. The reschedule action of a reschedule policy
+	// fails the task without restarting it, unless a rule of the restart
+	// policy matches first, so that the allocation is rescheduled. The fail
+	// action of a reschedule policy prevents the allocation from being
+	// rescheduled.
+	ExitRuleActionRestart    = "restart"
+	ExitRuleActionComplete   = "complete"
+	ExitRuleActionFail       = "fail"
+	ExitRuleActionReschedule = "reschedule"
+)
+
+var (
+	// RestartExitRuleActions are the valid actions of the exit rules of a
+	// restart policy.
+	RestartExitRuleActions = []string{ExitRuleActionRestart, ExitRuleActionComplete, ExitRuleActionFail}
+
+	// RescheduleExitRuleActions are the valid actions of the exit rules of a
+	// reschedule policy.
+	RescheduleExitRuleActions = []string{ExitRuleActionReschedule, ExitRuleActionFail}
+)
+
+// ExitRule decides what happens when a task exits with one of the exit codes
+// or is killed by one of the signals of the rule. The first rule of a policy
+// matching the exit of a task applies.
+type ExitRule struct {
+	// ExitCodes are the exit codes the rule matches.
+	ExitCodes []int
+
+	// Signals are the names of the signals the rule matches, such as
+	// "SIGKILL".
+	Signals []string
+
+	// Action is the action taken when the rule matches.
+	Action string
+}
+
+func (r *ExitRule) Copy() *ExitRule {
+	if r == nil {
+		return nil
+	}
+	nr := new(ExitRule)
+	*nr = *r
+	nr.ExitCodes = slices.Clone(r.ExitCodes)
+	nr.Signals = slices.Clone(r.Signals)
+	return nr
+}
+
+// Validate validates the exit rule given the actions valid for its policy.
+func (r *ExitRule) Validate(actions []string) error {
+	var mErr multierror.Error
+	if !slices.Contains(actions, r.Action) {
+		_ = multierror.Append(&mErr, fmt.Errorf("Invalid exit rule action %q, must be one of %q", r.Action, actions))
+	}
+	if len(r.ExitCodes) == 0 && len(r.Signals) == 0 {
+		_ = multierror.Append(&mErr, fmt.Errorf("Exit rule must specify exit codes or signals"))
+	}
+	for _, sig := range r.Signals {
+		if _, ok := signals.SignalLookup[sig]; !ok {
+			_ = multierror.Append(&mErr, fmt.Errorf("Exit rule has unknown signal %q", sig))
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+// Matches returns whether the rule matches a task that exited with the exit
+// code, or was killed by the signal if it's not zero. The exit code of a task
+// killed by a signal is ignored.
+func (r *ExitRule) Matches(exitCode, signal int) bool {
+	if signal == 0 {
+		return slices.Contains(r.ExitCodes, exitCode)
+	}
+	for _, name := range r.Signals {
+		if sig, ok := signals.SignalLookup[name].(syscall.Signal); ok && int(sig) == signal {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchExitRule returns the first rule that matches a task that exited with
+// the exit code or signal, or nil if none does.
+func MatchExitRule(rules []*ExitRule, exitCode, signal int) *ExitRule {
+	for _, rule := range rules {
+		if rule.Matches(exitCode, signal) {
+			return rule
+		}
+	}
+	return nil
+}
+
+func copyExitRules(rules []*ExitRule) []*ExitRule {
+	if rules == nil {
+		return nil
+	}
+	nr := make([]*ExitRule, len(rules))
+	for i, rule := range rules {
+		nr[i] = rule.Copy()
+	}
+	return nr
+}
+
+func validateExitRules(rules []*ExitRule, actions []string) error {
+	var mErr multierror.Error
+	for i, rule := range rules {
+		if err := rule.Validate(actions); err != nil {
+			_ = multierror.Append(&mErr, multierror.Prefix(err, fmt.Sprintf("Exit rule %d:", i+1)))
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+// exitRuleAction returns the action of the reschedule exit rule matching the
+// last exit of the failed tasks of the allocation, or an empty string if none
+// does.
+func (a *Allocation) exitRuleAction(rules []*ExitRule) string {
+	if len(rules) == 0 {
+		return ""
+	}
+
+	names := make([]string, 0, len(a.TaskStates))
+	for name, state := range a.TaskStates {
+		if state.Failed {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		events := a.TaskStates[name].Events
+		for i := len(events) - 1; i >= 0; i-- {
+			if events[i].Type != TaskTerminated {
+				continue
+			}
+			if rule := MatchExitRule(rules, events[i].ExitCode, events[i].Signal); rule != nil {
+				return rule.Action
+			}
+			break
+		}
+	}
+	return ""
+}
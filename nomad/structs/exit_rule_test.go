@@ -0,0 +1,123 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/shoenig/test/must"
+)
+
+func TestExitRule_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name    string
+		rule    *ExitRule
+		actions []string
+		expErr  string
+	}{
+		{
+			name:    "valid restart rule",
+			rule:    &ExitRule{ExitCodes: []int{75}, Action: ExitRuleActionRestart},
+			actions: RestartExitRuleActions,
+		},
+		{
+			name:    "valid reschedule rule",
+			rule:    &ExitRule{Signals: []string{"SIGKILL"}, Action: ExitRuleActionFail},
+			actions: RescheduleExitRuleActions,
+		},
+		{
+			name:    "invalid action for policy",
+			rule:    &ExitRule{ExitCodes: []int{1}, Action: ExitRuleActionComplete},
+			actions: RescheduleExitRuleActions,
+			expErr:  "Invalid exit rule action",
+		},
+		{
+			name:    "no exit codes or signals",
+			rule:    &ExitRule{Action: ExitRuleActionFail},
+			actions: RestartExitRuleActions,
+			expErr:  "must specify exit codes or signals",
+		},
+		{
+			name:    "unknown signal",
+			rule:    &ExitRule{Signals: []string{"SIGNOPE"}, Action: ExitRuleActionFail},
+			actions: RestartExitRuleActions,
+			expErr:  "unknown signal",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.rule.Validate(tc.actions)
+			if tc.expErr == "" {
+				must.NoError(t, err)
+			} else {
+				must.ErrorContains(t, err, tc.expErr)
+			}
+		})
+	}
+}
+
+func TestMatchExitRule(t *testing.T) {
+	ci.Parallel(t)
+
+	rules := []*ExitRule{
+		{ExitCodes: []int{0, 3}, Action: ExitRuleActionComplete},
+		{ExitCodes: []int{75}, Signals: []string{"SIGKILL"}, Action: ExitRuleActionRestart},
+		{ExitCodes: []int{1, 75}, Action: ExitRuleActionFail},
+	}
+
+	must.Eq(t, rules[0], MatchExitRule(rules, 3, 0))
+	must.Eq(t, rules[1], MatchExitRule(rules, 75, 0))
+	must.Eq(t, rules[1], MatchExitRule(rules, 0, int(syscall.SIGKILL)))
+	must.Eq(t, rules[2], MatchExitRule(rules, 1, 0))
+	must.Nil(t, MatchExitRule(rules, 2, 0))
+}
+
+func TestAllocation_NextRescheduleTime_ExitRules(t *testing.T) {
+	ci.Parallel(t)
+
+	now := time.Now().UTC()
+	alloc := &Allocation{
+		Job:          testJob(),
+		TaskGroup:    "web",
+		ClientStatus: AllocClientStatusFailed,
+		TaskStates: map[string]*TaskState{
+			"web": {
+				State:      TaskStateDead,
+				Failed:     true,
+				FinishedAt: now,
+				Events: []*TaskEvent{
+					NewTaskEvent(TaskTerminated).SetExitCode(1),
+					NewTaskEvent(TaskNotRestarting),
+				},
+			},
+		},
+	}
+	policy := &ReschedulePolicy{
+		Attempts:      1,
+		Interval:      time.Hour,
+		Delay:         5 * time.Second,
+		DelayFunction: "constant",
+		ExitRules: []*ExitRule{
+			{ExitCodes: []int{2}, Action: ExitRuleActionFail},
+		},
+	}
+	alloc.Job.LookupTaskGroup("web").ReschedulePolicy = policy
+
+	// The exit code doesn't match the rule
+	_, eligible := alloc.NextRescheduleTime()
+	must.True(t, eligible)
+	must.True(t, alloc.ShouldReschedule(policy, now))
+
+	// The exit code matches the rule to fail
+	policy.ExitRules[0].ExitCodes = []int{1}
+	_, eligible = alloc.NextRescheduleTime()
+	must.False(t, eligible)
+	must.False(t, alloc.ShouldReschedule(policy, now))
+}
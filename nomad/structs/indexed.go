@@ -0,0 +1,104 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+const (
+	// IndexedDefaultMaxFailures is the number of failed allocations an index
+	// of an indexed task group may have when MaxFailures isn't set.
+	IndexedDefaultMaxFailures = 3
+)
+
+// IndexedPolicy turns a batch task group into a set of Count completion
+// indexes. Each allocation runs a single index, given by the index of its
+// name and exposed to tasks as NOMAD_ALLOC_INDEX. Failed allocations are
+// replaced by allocations running the same index, and the task group is
+// complete once every index has a successful allocation.
+type IndexedPolicy struct {
+	// Parallelism is the maximum number of allocations of the task group
+	// running at once. Zero places every index at once.
+	Parallelism int
+
+	// MaxFailures is the number of failed allocations after which an index
+	// is failed and no longer retried.
+	MaxFailures int
+}
+
+// Copy returns a copy of the indexed policy.
+func (p *IndexedPolicy) Copy() *IndexedPolicy {
+	if p == nil {
+		return nil
+	}
+	np := new(IndexedPolicy)
+	*np = *p
+	return np
+}
+
+// Canonicalize sets the defaults of the indexed policy.
+func (p *IndexedPolicy) Canonicalize() {
+	if p.MaxFailures == 0 {
+		p.MaxFailures = IndexedDefaultMaxFailures
+	}
+}
+
+// Validate returns an error if the indexed policy can't be used by the task
+// group of the job.
+func (p *IndexedPolicy) Validate(job *Job, tg *TaskGroup) error {
+	var mErr *multierror.Error
+
+	if job.Type != JobTypeBatch {
+		mErr = multierror.Append(mErr, fmt.Errorf("Indexed task groups can only be used with batch jobs"))
+	}
+	if p.Parallelism < 0 {
+		mErr = multierror.Append(mErr, fmt.Errorf("Indexed parallelism %d can't be negative", p.Parallelism))
+	}
+	if p.MaxFailures < 1 {
+		mErr = multierror.Append(mErr, fmt.Errorf("Indexed max_failures %d must be at least 1", p.MaxFailures))
+	}
+	if tg.Gang && p.Parallelism > 0 && p.Parallelism < tg.Count {
+		mErr = multierror.Append(mErr, fmt.Errorf("Indexed parallelism %d must not be lower than the count of gang scheduled task groups", p.Parallelism))
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// ReschedulePolicy returns the reschedule policy used for the allocations
+// of an indexed task group. Since replacements keep the index of the
+// allocation they replace, the reschedule tracker of an allocation holds
+// the failures of its index. Indexes are retried until they reach
+// MaxFailures regardless of when the previous failures happened, so only
+// the delay settings of the group's policy are kept.
+func (p *IndexedPolicy) ReschedulePolicy(policy *ReschedulePolicy) *ReschedulePolicy {
+	np := policy.Copy()
+	if np == nil {
+		np = NewReschedulePolicy(JobTypeBatch)
+	}
+	np.Attempts = p.MaxFailures - 1
+	np.Interval = time.Duration(math.MaxInt64)
+	np.Unlimited = false
+	return np
+}
+
+// IndexProgress returns whether the allocation completed its index, or
+// failed it by reaching the maximum number of failures of the index.
+func (p *IndexedPolicy) IndexProgress(alloc *Allocation) (completed, failed bool) {
+	switch alloc.ClientStatus {
+	case AllocClientStatusComplete:
+		return true, false
+	case AllocClientStatusFailed:
+		failures := 1
+		if alloc.RescheduleTracker != nil {
+			failures += len(alloc.RescheduleTracker.Events)
+		}
+		return false, failures >= p.MaxFailures
+	}
+	return false, false
+}
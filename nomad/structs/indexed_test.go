@@ -0,0 +1,106 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/shoenig/test/must"
+)
+
+func TestIndexedPolicy_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name    string
+		jobType string
+		gang    bool
+		policy  *IndexedPolicy
+		expErr  string
+	}{
+		{
+			name:    "valid",
+			jobType: JobTypeBatch,
+			policy:  &IndexedPolicy{Parallelism: 2, MaxFailures: 3},
+		},
+		{
+			name:    "service job",
+			jobType: JobTypeService,
+			policy:  &IndexedPolicy{MaxFailures: 3},
+			expErr:  "can only be used with batch jobs",
+		},
+		{
+			name:    "negative parallelism",
+			jobType: JobTypeBatch,
+			policy:  &IndexedPolicy{Parallelism: -1, MaxFailures: 3},
+			expErr:  "can't be negative",
+		},
+		{
+			name:    "no failures",
+			jobType: JobTypeBatch,
+			policy:  &IndexedPolicy{},
+			expErr:  "must be at least 1",
+		},
+		{
+			name:    "gang with parallelism",
+			jobType: JobTypeBatch,
+			gang:    true,
+			policy:  &IndexedPolicy{Parallelism: 2, MaxFailures: 3},
+			expErr:  "must not be lower than the count",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			job := &Job{Type: tc.jobType}
+			tg := &TaskGroup{Count: 4, Gang: tc.gang}
+
+			err := tc.policy.Validate(job, tg)
+			if tc.expErr == "" {
+				must.NoError(t, err)
+			} else {
+				must.ErrorContains(t, err, tc.expErr)
+			}
+		})
+	}
+}
+
+func TestIndexedPolicy_ReschedulePolicy(t *testing.T) {
+	ci.Parallel(t)
+
+	policy := &IndexedPolicy{MaxFailures: 3}
+	reschedule := policy.ReschedulePolicy(&ReschedulePolicy{
+		Unlimited:     true,
+		Delay:         10 * time.Second,
+		DelayFunction: "exponential",
+		MaxDelay:      time.Minute,
+	})
+
+	must.Eq(t, 2, reschedule.Attempts)
+	must.False(t, reschedule.Unlimited)
+	must.Eq(t, 10*time.Second, reschedule.Delay)
+	must.Eq(t, "exponential", reschedule.DelayFunction)
+
+	// Failures are counted regardless of how long ago they happened
+	now := time.Now()
+	alloc := &Allocation{
+		ClientStatus: AllocClientStatusFailed,
+		RescheduleTracker: &RescheduleTracker{Events: []*RescheduleEvent{
+			{RescheduleTime: now.Add(-30 * 24 * time.Hour).UnixNano(), Delay: 10 * time.Second},
+		}},
+	}
+	must.True(t, alloc.RescheduleEligible(reschedule, now))
+
+	_, failed := policy.IndexProgress(alloc)
+	must.False(t, failed)
+
+	alloc.RescheduleTracker.Events = append(alloc.RescheduleTracker.Events,
+		&RescheduleEvent{RescheduleTime: now.Add(-time.Hour).UnixNano(), Delay: 20 * time.Second})
+	must.False(t, alloc.RescheduleEligible(reschedule, now))
+
+	_, failed = policy.IndexProgress(alloc)
+	must.True(t, failed)
+}
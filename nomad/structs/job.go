@@ -262,6 +262,21 @@ func (j *Job) RequiredBridgeNetwork() set.Collection[string] {
 	return result
 }
 
+// RequiredBandwidth identifies which task groups, if any, within the job
+// request bridge or CNI networking with a bandwidth limit.
+func (j *Job) RequiredBandwidth() set.Collection[string] {
+	result := set.New[string](len(j.TaskGroups))
+	for _, tg := range j.TaskGroups {
+		for _, network := range tg.Networks {
+			if network.HasEnforcedBandwidth() {
+				result.Insert(tg.Name)
+				break
+			}
+		}
+	}
+	return result
+}
+
 // RequiredTransparentProxy identifies which task groups, if any, within the job
 // contain Connect blocks using transparent proxy
 func (j *Job) RequiredTransparentProxy() set.Collection[string] {
@@ -0,0 +1,155 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// JobAffinity is a placement rule based on the allocations of other jobs in
+// the same namespace that are already running, or proposed, on a node. An
+// allocation matches the rule if its job ID, task group name and job meta
+// match all the fields that are set.
+//
+// Required job affinities are hard constraints: nodes without a matching
+// allocation, or with one if Anti is set, are not feasible. Otherwise nodes
+// are scored by Weight according to whether they have matching allocations.
+type JobAffinity struct {
+	// JobID matches the ID of the job of the allocations.
+	JobID string
+
+	// Group matches the name of the task group of the allocations.
+	Group string
+
+	// Meta matches the meta of the job of the allocations. Every key must be
+	// set to the given value.
+	Meta map[string]string
+
+	// Anti inverts the rule so that nodes running matching allocations are
+	// avoided.
+	Anti bool
+
+	// Required makes the rule a hard constraint instead of a preference.
+	Required bool
+
+	// Weight is the score applied to nodes that satisfy the rule when it
+	// isn't required.
+	Weight int8
+}
+
+// Copy returns a copy of the job affinity.
+func (a *JobAffinity) Copy() *JobAffinity {
+	if a == nil {
+		return nil
+	}
+	na := new(JobAffinity)
+	*na = *a
+	na.Meta = maps.Clone(a.Meta)
+	return na
+}
+
+// Equal returns true if both job affinities are the same.
+func (a *JobAffinity) Equal(o *JobAffinity) bool {
+	if a == nil || o == nil {
+		return a == o
+	}
+	return a.JobID == o.JobID &&
+		a.Group == o.Group &&
+		maps.Equal(a.Meta, o.Meta) &&
+		a.Anti == o.Anti &&
+		a.Required == o.Required &&
+		a.Weight == o.Weight
+}
+
+// Selector returns a description of the allocations matched by the job
+// affinity.
+func (a *JobAffinity) Selector() string {
+	var parts []string
+	if a.JobID != "" {
+		parts = append(parts, "job_id="+a.JobID)
+	}
+	if a.Group != "" {
+		parts = append(parts, "group="+a.Group)
+	}
+	for _, k := range slices.Sorted(maps.Keys(a.Meta)) {
+		parts = append(parts, fmt.Sprintf("meta.%s=%s", k, a.Meta[k]))
+	}
+	return strings.Join(parts, " ")
+}
+
+func (a *JobAffinity) String() string {
+	kind := "affinity"
+	if a.Anti {
+		kind = "anti_affinity"
+	}
+	if a.Required {
+		return fmt.Sprintf("%s %s required", kind, a.Selector())
+	}
+	return fmt.Sprintf("%s %s weight=%d", kind, a.Selector(), a.Weight)
+}
+
+// Validate checks the job affinity for reasonable configuration.
+func (a *JobAffinity) Validate() error {
+	var mErr *multierror.Error
+	if a.JobID == "" && a.Group == "" && len(a.Meta) == 0 {
+		mErr = multierror.Append(mErr, errors.New("Job affinity must set at least one of job_id, group or meta"))
+	}
+	if !a.Required && (a.Weight < 1 || a.Weight > 100) {
+		mErr = multierror.Append(mErr, fmt.Errorf("Job affinity weight must be between 1 and 100; got %d", a.Weight))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// Matches returns whether the allocation of the given job matches the job
+// affinity. The namespace and job of the allocation must be checked by the
+// caller.
+func (a *JobAffinity) Matches(alloc *Allocation, job *Job) bool {
+	if a.JobID != "" && a.JobID != alloc.JobID {
+		return false
+	}
+	if a.Group != "" && a.Group != alloc.TaskGroup {
+		return false
+	}
+	for k, v := range a.Meta {
+		if job == nil {
+			return false
+		}
+		if jv, ok := job.Meta[k]; !ok || jv != v {
+			return false
+		}
+	}
+	return true
+}
+
+// CopySliceJobAffinities returns a copy of the given job affinities.
+func CopySliceJobAffinities(s []*JobAffinity) []*JobAffinity {
+	l := len(s)
+	if l == 0 {
+		return nil
+	}
+
+	c := make([]*JobAffinity, l)
+	for i, v := range s {
+		c[i] = v.Copy()
+	}
+	return c
+}
+
+// validateJobAffinities validates a list of job affinities, returning an
+// error for each invalid one.
+func validateJobAffinities(affinities []*JobAffinity) []error {
+	var errs []error
+	for idx, affinity := range affinities {
+		if err := affinity.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("Job affinity %d validation failed: %s", idx+1, err))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,79 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/shoenig/test/must"
+)
+
+func TestJobAffinity_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name     string
+		affinity *JobAffinity
+		expErr   string
+	}{
+		{
+			name:     "valid soft",
+			affinity: &JobAffinity{JobID: "cache", Weight: 50},
+		},
+		{
+			name:     "valid required",
+			affinity: &JobAffinity{Meta: map[string]string{"tier": "storage"}, Anti: true, Required: true},
+		},
+		{
+			name:     "no selector",
+			affinity: &JobAffinity{Weight: 50},
+			expErr:   "must set at least one of job_id, group or meta",
+		},
+		{
+			name:     "invalid weight",
+			affinity: &JobAffinity{Group: "cache", Weight: -10},
+			expErr:   "weight must be between 1 and 100",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.affinity.Validate()
+			if tc.expErr == "" {
+				must.NoError(t, err)
+			} else {
+				must.ErrorContains(t, err, tc.expErr)
+			}
+		})
+	}
+}
+
+func TestJobAffinity_Matches(t *testing.T) {
+	ci.Parallel(t)
+
+	job := &Job{ID: "cache", Meta: map[string]string{"tier": "storage"}}
+	alloc := &Allocation{JobID: job.ID, TaskGroup: "redis"}
+
+	must.True(t, (&JobAffinity{JobID: "cache"}).Matches(alloc, job))
+	must.True(t, (&JobAffinity{JobID: "cache", Group: "redis"}).Matches(alloc, job))
+	must.False(t, (&JobAffinity{JobID: "cache", Group: "memcached"}).Matches(alloc, job))
+	must.True(t, (&JobAffinity{Meta: map[string]string{"tier": "storage"}}).Matches(alloc, job))
+	must.False(t, (&JobAffinity{Meta: map[string]string{"tier": "web"}}).Matches(alloc, job))
+	must.False(t, (&JobAffinity{Meta: map[string]string{"tier": "storage"}}).Matches(alloc, nil))
+}
+
+func TestJob_Validate_JobAffinity(t *testing.T) {
+	ci.Parallel(t)
+
+	job := testJob()
+	job.TaskGroups[0].JobAffinities = []*JobAffinity{{JobID: "cache"}}
+	must.ErrorContains(t, job.Validate(), "Job affinity 1 validation failed")
+
+	job.TaskGroups[0].JobAffinities[0].Weight = 50
+	must.NoError(t, job.Validate())
+
+	job.Type = JobTypeSystem
+	must.ErrorContains(t, job.Validate(), "System jobs may not have a job_affinity block")
+}
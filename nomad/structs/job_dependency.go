@@ -0,0 +1,184 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+const (
+	// JobDependencyConditionComplete is satisfied once the upstream job is
+	// dead, regardless of the outcome of its allocations.
+	JobDependencyConditionComplete = "complete"
+
+	// JobDependencyConditionSuccessful is satisfied once the upstream job is
+	// dead and none of its allocations failed or were lost.
+	JobDependencyConditionSuccessful = "successful"
+
+	// JobDependencyConditionFailed is satisfied once the upstream job is
+	// dead and at least one of its allocations failed or was lost, or the
+	// job was stopped.
+	JobDependencyConditionFailed = "failed"
+)
+
+const (
+	// JobDependencyStatusPending is used when the upstream job hasn't
+	// reached a terminal state yet.
+	JobDependencyStatusPending = "pending"
+
+	// JobDependencyStatusSatisfied is used when the upstream job reached the
+	// state required by the dependency.
+	JobDependencyStatusSatisfied = "satisfied"
+
+	// JobDependencyStatusUnsatisfiable is used when the upstream job reached
+	// a terminal state other than the one required by the dependency.
+	JobDependencyStatusUnsatisfiable = "unsatisfiable"
+
+	// JobDependencyStatusMissing is used when the upstream job doesn't
+	// exist, because it wasn't registered yet or was purged.
+	JobDependencyStatusMissing = "missing"
+)
+
+// JobDependency is used to prevent a batch job from being placed until
+// another job in the same namespace reaches a given state.
+type JobDependency struct {
+	// JobID is the ID of the upstream job.
+	JobID string
+
+	// Condition is the state the upstream job must reach for the dependency
+	// to be satisfied.
+	Condition string
+}
+
+// Copy returns a copy of the dependency.
+func (d *JobDependency) Copy() *JobDependency {
+	if d == nil {
+		return nil
+	}
+	nd := new(JobDependency)
+	*nd = *d
+	return nd
+}
+
+// DiffID fulfills the DiffableWithID interface.
+func (d *JobDependency) DiffID() string {
+	return d.JobID
+}
+
+// Canonicalize sets the default condition.
+func (d *JobDependency) Canonicalize() {
+	if d.Condition == "" {
+		d.Condition = JobDependencyConditionSuccessful
+	}
+}
+
+// Validate checks the dependency for reasonable configuration.
+func (d *JobDependency) Validate() error {
+	var mErr *multierror.Error
+
+	if d.JobID == "" {
+		mErr = multierror.Append(mErr, errors.New("Missing upstream job ID"))
+	}
+
+	switch d.Condition {
+	case JobDependencyConditionComplete, JobDependencyConditionSuccessful, JobDependencyConditionFailed:
+	default:
+		mErr = multierror.Append(mErr, fmt.Errorf("Invalid condition %q", d.Condition))
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// Status returns the status of the dependency given the current version of
+// the upstream job and its summary. Both may be nil if the upstream job
+// doesn't exist, in which case the dependency is missing.
+func (d *JobDependency) Status(upstream *Job, summary *JobSummary) string {
+	if upstream == nil {
+		return JobDependencyStatusMissing
+	}
+	if upstream.Status != JobStatusDead {
+		return JobDependencyStatusPending
+	}
+
+	failed := upstream.Stop
+	if summary != nil {
+		for _, tg := range summary.Summary {
+			if tg.Failed > 0 || tg.Lost > 0 {
+				failed = true
+			}
+		}
+	}
+
+	switch {
+	case d.Condition == JobDependencyConditionComplete:
+		return JobDependencyStatusSatisfied
+	case d.Condition == JobDependencyConditionSuccessful && !failed:
+		return JobDependencyStatusSatisfied
+	case d.Condition == JobDependencyConditionFailed && failed:
+		return JobDependencyStatusSatisfied
+	default:
+		return JobDependencyStatusUnsatisfiable
+	}
+}
+
+// CopySliceJobDependencies returns a copy of the given dependencies.
+func CopySliceJobDependencies(s []*JobDependency) []*JobDependency {
+	l := len(s)
+	if l == 0 {
+		return nil
+	}
+
+	c := make([]*JobDependency, l)
+	for i, v := range s {
+		c[i] = v.Copy()
+	}
+	return c
+}
+
+// JobDependencyState is the state of a single edge of the job dependency
+// graph.
+type JobDependencyState struct {
+	// JobID is the ID of the job on the other end of the edge: the upstream
+	// job for dependencies, or the downstream job for dependents.
+	JobID string
+
+	// Condition is the condition required by the dependency.
+	Condition string
+
+	// JobStatus is the status of the job on the other end of the edge.
+	JobStatus string
+
+	// Status is the status of the dependency.
+	Status string
+}
+
+// JobDependenciesRequest is used to query the dependency graph of a job.
+type JobDependenciesRequest struct {
+	JobID string
+	QueryOptions
+}
+
+// JobDependenciesResponse is used to return the dependency graph of a job.
+type JobDependenciesResponse struct {
+	JobDependencies *JobDependencies
+	QueryMeta
+}
+
+// JobDependencies is the dependency graph around a single job.
+type JobDependencies struct {
+	// JobID is the ID of the job.
+	JobID string
+
+	// Namespace is the namespace of the job.
+	Namespace string
+
+	// Dependencies are the jobs the job depends on.
+	Dependencies []*JobDependencyState
+
+	// Dependents are the jobs that depend on the job.
+	Dependents []*JobDependencyState
+}
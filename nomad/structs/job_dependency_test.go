@@ -0,0 +1,164 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/shoenig/test/must"
+)
+
+func TestJobDependency_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		name   string
+		dep    *JobDependency
+		expErr []string
+	}{
+		{
+			name: "valid",
+			dep:  &JobDependency{JobID: "upstream", Condition: JobDependencyConditionComplete},
+		},
+		{
+			name:   "missing job ID",
+			dep:    &JobDependency{Condition: JobDependencyConditionSuccessful},
+			expErr: []string{"Missing upstream job ID"},
+		},
+		{
+			name:   "invalid condition",
+			dep:    &JobDependency{JobID: "upstream", Condition: "running"},
+			expErr: []string{`Invalid condition "running"`},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.dep.Validate()
+			if len(tc.expErr) == 0 {
+				must.NoError(t, err)
+				return
+			}
+			for _, exp := range tc.expErr {
+				must.ErrorContains(t, err, exp)
+			}
+		})
+	}
+}
+
+func TestJobDependency_Status(t *testing.T) {
+	ci.Parallel(t)
+
+	running := &Job{ID: "upstream", Status: JobStatusRunning}
+	dead := &Job{ID: "upstream", Status: JobStatusDead}
+	stopped := &Job{ID: "upstream", Status: JobStatusDead, Stop: true}
+
+	succeeded := &JobSummary{Summary: map[string]TaskGroupSummary{
+		"web": {Complete: 3},
+	}}
+	failed := &JobSummary{Summary: map[string]TaskGroupSummary{
+		"web": {Complete: 2, Failed: 1},
+	}}
+	lost := &JobSummary{Summary: map[string]TaskGroupSummary{
+		"web": {Complete: 2, Lost: 1},
+	}}
+
+	cases := []struct {
+		name      string
+		condition string
+		upstream  *Job
+		summary   *JobSummary
+		exp       string
+	}{
+		{
+			name:      "missing upstream",
+			condition: JobDependencyConditionComplete,
+			exp:       JobDependencyStatusMissing,
+		},
+		{
+			name:      "running upstream",
+			condition: JobDependencyConditionComplete,
+			upstream:  running,
+			summary:   succeeded,
+			exp:       JobDependencyStatusPending,
+		},
+		{
+			name:      "complete after failure",
+			condition: JobDependencyConditionComplete,
+			upstream:  dead,
+			summary:   failed,
+			exp:       JobDependencyStatusSatisfied,
+		},
+		{
+			name:      "successful",
+			condition: JobDependencyConditionSuccessful,
+			upstream:  dead,
+			summary:   succeeded,
+			exp:       JobDependencyStatusSatisfied,
+		},
+		{
+			name:      "successful after failure",
+			condition: JobDependencyConditionSuccessful,
+			upstream:  dead,
+			summary:   failed,
+			exp:       JobDependencyStatusUnsatisfiable,
+		},
+		{
+			name:      "successful after stop",
+			condition: JobDependencyConditionSuccessful,
+			upstream:  stopped,
+			summary:   succeeded,
+			exp:       JobDependencyStatusUnsatisfiable,
+		},
+		{
+			name:      "failed after lost",
+			condition: JobDependencyConditionFailed,
+			upstream:  dead,
+			summary:   lost,
+			exp:       JobDependencyStatusSatisfied,
+		},
+		{
+			name:      "failed after success",
+			condition: JobDependencyConditionFailed,
+			upstream:  dead,
+			summary:   succeeded,
+			exp:       JobDependencyStatusUnsatisfiable,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dep := &JobDependency{JobID: "upstream", Condition: tc.condition}
+			must.Eq(t, tc.exp, dep.Status(tc.upstream, tc.summary))
+		})
+	}
+}
+
+func TestJob_Validate_DependsOn(t *testing.T) {
+	ci.Parallel(t)
+
+	newJob := func(jobType string, deps ...*JobDependency) *Job {
+		job := testJob()
+		job.Type = jobType
+		job.DependsOn = deps
+		return job
+	}
+
+	err := newJob(JobTypeBatch, &JobDependency{JobID: "upstream", Condition: JobDependencyConditionComplete}).Validate()
+	must.NoError(t, err)
+
+	err = newJob(JobTypeService, &JobDependency{JobID: "upstream", Condition: JobDependencyConditionComplete}).Validate()
+	must.ErrorContains(t, err, `Job dependencies can only be used with "batch" scheduler`)
+
+	job := newJob(JobTypeBatch)
+	job.DependsOn = []*JobDependency{{JobID: job.ID, Condition: JobDependencyConditionComplete}}
+	must.ErrorContains(t, job.Validate(), "Dependency 1 references the job itself")
+
+	err = newJob(JobTypeBatch,
+		&JobDependency{JobID: "upstream", Condition: JobDependencyConditionComplete},
+		&JobDependency{JobID: "upstream", Condition: JobDependencyConditionFailed},
+	).Validate()
+	must.ErrorContains(t, err, `Dependency 2 redefines "upstream" from dependency 1`)
+}
@@ -63,10 +63,19 @@ type NetworkIndex struct {
 	// }
 	UsedPorts map[string]Bitmap
 
-	// Deprecated bandwidth fields
 	AvailBandwidth map[string]int // Bandwidth by device
 	UsedBandwidth  map[string]int // Bandwidth by device
 
+	// BandwidthDevice is the device whose bandwidth is used by group.network
+	// asks, which is the node's default interface.
+	BandwidthDevice string
+
+	// UsedEnforcedBandwidth is the bandwidth of BandwidthDevice used by the
+	// group.network asks whose bandwidth is enforced on the client. Only this
+	// bandwidth is accounted for when scheduling, as the bandwidth of other
+	// networks and of deprecated task networks isn't limited.
+	UsedEnforcedBandwidth int
+
 	MinDynamicPort int // The smallest dynamic port generated
 	MaxDynamicPort int // The largest dynamic port generated
 }
@@ -184,14 +193,11 @@ func (idx *NetworkIndex) Release() {
 
 // Overcommitted checks if the network is overcommitted
 func (idx *NetworkIndex) Overcommitted() bool {
-	// TODO remove since bandwidth is deprecated
-	/*for device, used := range idx.UsedBandwidth {
-		avail := idx.AvailBandwidth[device]
-		if used > avail {
-			return true
-		}
-	}*/
-	return false
+	if idx.UsedEnforcedBandwidth == 0 {
+		return false
+	}
+	return idx.BandwidthDevice == "" ||
+		idx.UsedEnforcedBandwidth > idx.AvailBandwidth[idx.BandwidthDevice]
 }
 
 // SetNode is used to initialize a node's network index with available IPs,
@@ -281,6 +287,9 @@ func (idx *NetworkIndex) SetNode(node *Node) error {
 		if n.Device != "" {
 			idx.TaskNetworks = append(idx.TaskNetworks, n)
 			idx.AvailBandwidth[n.Device] = n.MBits
+			if idx.BandwidthDevice == "" {
+				idx.BandwidthDevice = n.Device
+			}
 
 			// Reserve ports
 			used := idx.getUsedPortsFor(n.IP)
@@ -362,6 +371,7 @@ func (idx *NetworkIndex) AddAllocs(allocs []*Allocation) (collide bool, reason s
 					collide = true
 					reason = fmt.Sprintf("collision when reserving port for alloc %s: %v", alloc.ID, r)
 				}
+				idx.AddReservedBandwidth(alloc.AllocatedResources.Shared.Networks)
 			} else {
 				// Add network resources that are at the task group level
 				if len(alloc.AllocatedResources.Shared.Networks) > 0 {
@@ -448,6 +458,17 @@ func (idx *NetworkIndex) AddReservedPorts(ports AllocatedPorts) (collide bool, r
 	return
 }
 
+// AddReservedBandwidth adds the bandwidth used by group.network resources to
+// the bandwidth used on the node's default interface. Networks whose bandwidth
+// isn't enforced are ignored.
+func (idx *NetworkIndex) AddReservedBandwidth(networks []*NetworkResource) {
+	for _, n := range networks {
+		if n.HasEnforcedBandwidth() {
+			idx.UsedEnforcedBandwidth += n.MBits
+		}
+	}
+}
+
 // AddReservedPortsForIP checks whether any reserved ports collide with those
 // in use for the IP address.
 func (idx *NetworkIndex) AddReservedPortsForIP(ports []uint64, ip string) (collide bool, reasons []string) {
@@ -509,6 +530,14 @@ func (idx *NetworkIndex) AssignPorts(ask *NetworkResource) (AllocatedPorts, erro
 	var offer AllocatedPorts
 	var portsInOffer []int
 
+	// Check if we would exceed the bandwidth of the default interface
+	if ask.HasEnforcedBandwidth() {
+		avail := idx.AvailBandwidth[idx.BandwidthDevice]
+		if idx.BandwidthDevice == "" || idx.UsedEnforcedBandwidth+ask.MBits > avail {
+			return nil, fmt.Errorf("bandwidth exceeded")
+		}
+	}
+
 	// index of host network name to slice of reserved ports, used during dynamic port assignment
 	reservedIdx := map[string][]Port{}
 
@@ -0,0 +1,197 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+const (
+	// NetworkPolicyDefaultAllow allows all allocations on the bridge network
+	// to reach the allocation.
+	NetworkPolicyDefaultAllow = "allow"
+
+	// NetworkPolicyDefaultDeny only allows the allocations of the same job
+	// and those matching one of the allow rules of the policy to reach the
+	// allocation.
+	NetworkPolicyDefaultDeny = "deny"
+
+	// NetworkPolicyWildcard matches any namespace, job or service in an allow
+	// rule.
+	NetworkPolicyWildcard = "*"
+)
+
+// NetworkPolicy controls which allocations on the bridge network of a client
+// may reach the allocations of a task group. It can be set on a namespace,
+// in which case it applies to all the task groups in bridge networking mode
+// of the jobs in the namespace, and on a task group.
+type NetworkPolicy struct {
+	// Default is the policy applied to traffic that isn't matched by an allow
+	// rule, either "allow" or "deny". If empty, the default of the namespace
+	// is used, or "allow" if the namespace doesn't set one.
+	Default string
+
+	// Allow are the rules matching the allocations allowed to reach the
+	// allocations of the group when the default policy is "deny".
+	Allow []*NetworkPolicyRule
+}
+
+// NetworkPolicyRule matches the allocations that are allowed to reach the
+// allocations of a task group. Empty fields match any value, except for the
+// namespace which defaults to the namespace of the task group.
+type NetworkPolicyRule struct {
+	// Namespace is the namespace of the matching allocations, or "*" to
+	// match any namespace.
+	Namespace string
+
+	// Job is the ID of the job of the matching allocations.
+	Job string
+
+	// Service is the name of a service registered by the matching
+	// allocations.
+	Service string
+}
+
+// Copy returns a copy of the network policy.
+func (p *NetworkPolicy) Copy() *NetworkPolicy {
+	if p == nil {
+		return nil
+	}
+	np := new(NetworkPolicy)
+	*np = *p
+	if p.Allow != nil {
+		np.Allow = make([]*NetworkPolicyRule, len(p.Allow))
+		for i, rule := range p.Allow {
+			np.Allow[i] = rule.Copy()
+		}
+	}
+	return np
+}
+
+// Equal returns whether the two network policies are equivalent.
+func (p *NetworkPolicy) Equal(o *NetworkPolicy) bool {
+	if p == nil || o == nil {
+		return p == o
+	}
+	if p.Default != o.Default {
+		return false
+	}
+	return slices.EqualFunc(p.Allow, o.Allow, func(a, b *NetworkPolicyRule) bool {
+		return a.Equal(b)
+	})
+}
+
+// Denies returns whether the policy denies traffic not matched by an allow
+// rule.
+func (p *NetworkPolicy) Denies() bool {
+	return p != nil && p.Default == NetworkPolicyDefaultDeny
+}
+
+// Merge returns the network policy of a task group once the policy of its
+// namespace is applied. The group is denied by default if either policy
+// denies by default, and is reachable by the allocations matching the allow
+// rules of both policies.
+func (p *NetworkPolicy) Merge(ns *NetworkPolicy) *NetworkPolicy {
+	if ns == nil {
+		return p.Copy()
+	}
+
+	merged := p.Copy()
+	if merged == nil {
+		merged = new(NetworkPolicy)
+	}
+	if merged.Default == "" || ns.Denies() {
+		merged.Default = ns.Default
+	}
+	for _, rule := range ns.Allow {
+		if !slices.ContainsFunc(merged.Allow, rule.Equal) {
+			merged.Allow = append(merged.Allow, rule.Copy())
+		}
+	}
+	return merged
+}
+
+// Validate checks the network policy for reasonable configuration.
+func (p *NetworkPolicy) Validate() error {
+	if p == nil {
+		return nil
+	}
+
+	var mErr *multierror.Error
+
+	switch p.Default {
+	case "", NetworkPolicyDefaultAllow, NetworkPolicyDefaultDeny:
+	default:
+		mErr = multierror.Append(mErr, fmt.Errorf("Invalid default policy %q, must be %q or %q",
+			p.Default, NetworkPolicyDefaultAllow, NetworkPolicyDefaultDeny))
+	}
+
+	for idx, rule := range p.Allow {
+		if err := rule.Validate(); err != nil {
+			mErr = multierror.Append(mErr, fmt.Errorf("Allow rule %d validation failed: %v", idx+1, err))
+		}
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// Copy returns a copy of the rule.
+func (r *NetworkPolicyRule) Copy() *NetworkPolicyRule {
+	if r == nil {
+		return nil
+	}
+	nr := new(NetworkPolicyRule)
+	*nr = *r
+	return nr
+}
+
+// Equal returns whether the two rules match the same allocations.
+func (r *NetworkPolicyRule) Equal(o *NetworkPolicyRule) bool {
+	if r == nil || o == nil {
+		return r == o
+	}
+	return *r == *o
+}
+
+// Validate checks the rule for reasonable configuration.
+func (r *NetworkPolicyRule) Validate() error {
+	if r == nil {
+		return errors.New("Missing allow rule")
+	}
+	if r.Namespace == "" && r.Job == "" && r.Service == "" {
+		return errors.New("Allow rule must set at least one of namespace, job or service")
+	}
+	return nil
+}
+
+// Matches returns whether the rule matches an allocation of the given
+// namespace and job that registers the given services. The namespace of the
+// task group the rule applies to is used when the rule doesn't set one.
+func (r *NetworkPolicyRule) Matches(groupNamespace, namespace, job string, services []string) bool {
+	switch r.Namespace {
+	case NetworkPolicyWildcard:
+	case "":
+		if namespace != groupNamespace {
+			return false
+		}
+	default:
+		if namespace != r.Namespace {
+			return false
+		}
+	}
+
+	if r.Job != "" && r.Job != NetworkPolicyWildcard && r.Job != job {
+		return false
+	}
+
+	if r.Service != "" && r.Service != NetworkPolicyWildcard && !slices.Contains(services, r.Service) {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,148 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/shoenig/test/must"
+)
+
+func TestNetworkPolicy_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name   string
+		policy *NetworkPolicy
+		expErr string
+	}{
+		{
+			name: "valid",
+			policy: &NetworkPolicy{
+				Default: NetworkPolicyDefaultDeny,
+				Allow: []*NetworkPolicyRule{
+					{Namespace: "*", Service: "prometheus"},
+					{Job: "web"},
+				},
+			},
+		},
+		{
+			name:   "no default",
+			policy: &NetworkPolicy{},
+		},
+		{
+			name:   "invalid default",
+			policy: &NetworkPolicy{Default: "reject"},
+			expErr: `Invalid default policy "reject"`,
+		},
+		{
+			name: "empty rule",
+			policy: &NetworkPolicy{
+				Default: NetworkPolicyDefaultDeny,
+				Allow:   []*NetworkPolicyRule{{Job: "web"}, {}},
+			},
+			expErr: "Allow rule 2 validation failed",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.policy.Validate()
+			if tc.expErr == "" {
+				must.NoError(t, err)
+			} else {
+				must.ErrorContains(t, err, tc.expErr)
+			}
+		})
+	}
+}
+
+func TestNetworkPolicy_Merge(t *testing.T) {
+	ci.Parallel(t)
+
+	ns := &NetworkPolicy{
+		Default: NetworkPolicyDefaultDeny,
+		Allow:   []*NetworkPolicyRule{{Namespace: "ops"}},
+	}
+
+	// The namespace policy applies to groups without one.
+	var group *NetworkPolicy
+	must.Eq(t, ns, group.Merge(ns))
+
+	// A namespace that denies by default overrides groups that allow.
+	group = &NetworkPolicy{
+		Default: NetworkPolicyDefaultAllow,
+		Allow:   []*NetworkPolicyRule{{Job: "web"}, {Namespace: "ops"}},
+	}
+	must.Eq(t, &NetworkPolicy{
+		Default: NetworkPolicyDefaultDeny,
+		Allow:   []*NetworkPolicyRule{{Job: "web"}, {Namespace: "ops"}},
+	}, group.Merge(ns))
+
+	// A group may deny by default in a namespace that allows.
+	group = &NetworkPolicy{Default: NetworkPolicyDefaultDeny}
+	must.Eq(t, group, group.Merge(&NetworkPolicy{Default: NetworkPolicyDefaultAllow}))
+	must.Eq(t, group, group.Merge(nil))
+}
+
+func TestNetworkPolicyRule_Matches(t *testing.T) {
+	ci.Parallel(t)
+
+	services := []string{"web", "metrics"}
+
+	testCases := []struct {
+		name    string
+		rule    *NetworkPolicyRule
+		ns      string
+		job     string
+		matches bool
+	}{
+		{
+			name:    "same namespace",
+			rule:    &NetworkPolicyRule{Job: "api"},
+			ns:      "default",
+			job:     "api",
+			matches: true,
+		},
+		{
+			name: "other namespace",
+			rule: &NetworkPolicyRule{Job: "api"},
+			ns:   "ops",
+			job:  "api",
+		},
+		{
+			name:    "explicit namespace",
+			rule:    &NetworkPolicyRule{Namespace: "ops"},
+			ns:      "ops",
+			job:     "prometheus",
+			matches: true,
+		},
+		{
+			name:    "any namespace",
+			rule:    &NetworkPolicyRule{Namespace: "*", Service: "metrics"},
+			ns:      "ops",
+			job:     "prometheus",
+			matches: true,
+		},
+		{
+			name: "other job",
+			rule: &NetworkPolicyRule{Job: "api"},
+			ns:   "default",
+			job:  "web",
+		},
+		{
+			name: "missing service",
+			rule: &NetworkPolicyRule{Service: "db"},
+			ns:   "default",
+			job:  "api",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			must.Eq(t, tc.matches, tc.rule.Matches("default", tc.ns, tc.job, services))
+		})
+	}
+}
@@ -424,6 +424,106 @@ func TestNetworkIndex_AssignPorts(t *testing.T) {
 	must.Between(t, idx.MaxDynamicPort-1, adminPortMapping.Value, idx.MaxDynamicPort)
 }
 
+func TestNetworkIndex_AssignPorts_Bandwidth(t *testing.T) {
+	ci.Parallel(t)
+
+	idx := NewNetworkIndex()
+	n := &Node{
+		NodeResources: &NodeResources{
+			Networks: []*NetworkResource{
+				{
+					Device: "eth0",
+					CIDR:   "192.168.0.100/32",
+					IP:     "192.168.0.100",
+					MBits:  1000,
+				},
+			},
+			NodeNetworks: []*NodeNetworkResource{
+				{
+					Mode:   "host",
+					Device: "eth0",
+					Speed:  1000,
+					Addresses: []NodeNetworkAddress{
+						{
+							Alias:   "default",
+							Address: "192.168.0.100",
+							Family:  NodeNetworkAF_IPv4,
+						},
+					},
+				},
+			},
+		},
+	}
+	must.NoError(t, idx.SetNode(n))
+	must.Eq(t, "eth0", idx.BandwidthDevice)
+
+	// An existing allocation uses 600 of the 1000 mbits of the node. The
+	// bandwidth of host networks and deprecated task networks isn't enforced
+	// so it isn't accounted for.
+	allocs := []*Allocation{
+		{
+			AllocatedResources: &AllocatedResources{
+				Shared: AllocatedSharedResources{
+					Networks: []*NetworkResource{{Mode: "bridge", MBits: 600}},
+					Ports:    AllocatedPorts{{Label: "http", Value: 8080, HostIP: "192.168.0.100"}},
+				},
+			},
+		},
+		{
+			AllocatedResources: &AllocatedResources{
+				Shared: AllocatedSharedResources{
+					Networks: []*NetworkResource{{Mode: "host", MBits: 600}},
+					Ports:    AllocatedPorts{{Label: "http", Value: 8081, HostIP: "192.168.0.100"}},
+				},
+			},
+		},
+		{
+			AllocatedResources: &AllocatedResources{
+				Tasks: map[string]*AllocatedTaskResources{
+					"web": {
+						Networks: []*NetworkResource{{
+							Device: "eth0",
+							IP:     "192.168.0.100",
+							MBits:  600,
+						}},
+					},
+				},
+			},
+		},
+	}
+	collide, reason := idx.AddAllocs(allocs)
+	must.False(t, collide, must.Sprint(reason))
+	must.Eq(t, 600, idx.UsedEnforcedBandwidth)
+	must.False(t, idx.Overcommitted())
+
+	// Asks whose bandwidth isn't enforced are always accepted
+	_, err := idx.AssignPorts(&NetworkResource{Mode: "host", MBits: 5000})
+	must.NoError(t, err)
+
+	ask := &NetworkResource{
+		Mode:         "bridge",
+		MBits:        500,
+		DynamicPorts: []Port{{Label: "http", To: 80, HostNetwork: "default"}},
+	}
+	_, err = idx.AssignPorts(ask)
+	must.EqError(t, err, "bandwidth exceeded")
+
+	ask.MBits = 400
+	offer, err := idx.AssignPorts(ask)
+	must.NoError(t, err)
+	must.Len(t, 1, offer)
+
+	idx.AddReservedBandwidth([]*NetworkResource{ask})
+	must.Eq(t, 1000, idx.UsedEnforcedBandwidth)
+	must.False(t, idx.Overcommitted())
+
+	idx.AddReservedBandwidth([]*NetworkResource{{Mode: "host", MBits: 1}})
+	must.False(t, idx.Overcommitted())
+
+	idx.AddReservedBandwidth([]*NetworkResource{{Mode: "cni/mynet", MBits: 1}})
+	must.True(t, idx.Overcommitted())
+}
+
 // TestNetworkIndex_AssignPorts_SmallRange exercises assigning ports on group
 // networks with small dynamic port ranges configured
 func TestNetworkIndex_AssignPortss_SmallRange(t *testing.T) {
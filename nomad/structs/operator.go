@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"net/netip"
+	"slices"
 	"time"
 
 	"github.com/hashicorp/go-uuid"
@@ -233,6 +234,18 @@ type SchedulerConfiguration struct {
 	// during leadership transitions.
 	PauseEvalBroker bool `hcl:"pause_eval_broker"`
 
+	// NamespaceFairShare configures weighted fair-share dequeueing of
+	// evaluations across namespaces.
+	NamespaceFairShare NamespaceFairShareConfig `hcl:"namespace_fair_share"`
+
+	// Descheduler configures the core job that migrates allocations to
+	// rebalance the cluster.
+	Descheduler DeschedulerConfig `hcl:"descheduler"`
+
+	// PriorityClasses are the named priority classes jobs can reference to
+	// set their priority and how they take part in preemption.
+	PriorityClasses []*PriorityClass `hcl:"priority_class"`
+
 	// CreateIndex/ModifyIndex store the create/modify indexes of this configuration.
 	CreateIndex uint64
 	ModifyIndex uint64
@@ -244,6 +257,12 @@ func (s *SchedulerConfiguration) Copy() *SchedulerConfiguration {
 	}
 
 	ns := *s
+	if s.PriorityClasses != nil {
+		ns.PriorityClasses = make([]*PriorityClass, len(s.PriorityClasses))
+		for i, class := range s.PriorityClasses {
+			ns.PriorityClasses[i] = class.Copy()
+		}
+	}
 	return &ns
 }
 
@@ -276,9 +295,15 @@ func (s *SchedulerConfiguration) WithNodePool(pool *NodePool) *SchedulerConfigur
 }
 
 func (s *SchedulerConfiguration) Canonicalize() {
-	if s != nil && s.SchedulerAlgorithm == "" {
+	if s == nil {
+		return
+	}
+	if s.SchedulerAlgorithm == "" {
 		s.SchedulerAlgorithm = SchedulerAlgorithmBinpack
 	}
+	for _, class := range s.PriorityClasses {
+		class.Canonicalize()
+	}
 }
 
 func (s *SchedulerConfiguration) Validate() error {
@@ -292,9 +317,57 @@ func (s *SchedulerConfiguration) Validate() error {
 		return fmt.Errorf("invalid scheduler algorithm: %v", s.SchedulerAlgorithm)
 	}
 
+	if s.NamespaceFairShare.DefaultWeight < 0 {
+		return fmt.Errorf("invalid namespace fair share default weight: %d", s.NamespaceFairShare.DefaultWeight)
+	}
+
+	if err := s.Descheduler.Validate(); err != nil {
+		return err
+	}
+
+	names := make(map[string]struct{}, len(s.PriorityClasses))
+	for _, class := range s.PriorityClasses {
+		if class == nil {
+			return fmt.Errorf("invalid priority class: empty block")
+		}
+		if err := class.Validate(); err != nil {
+			return err
+		}
+		if _, ok := names[class.Name]; ok {
+			return fmt.Errorf("duplicate priority class: %q", class.Name)
+		}
+		names[class.Name] = struct{}{}
+	}
+
+	return nil
+}
+
+// LookupPriorityClass returns the priority class with the given name, or nil
+// if it doesn't exist.
+func (s *SchedulerConfiguration) LookupPriorityClass(name string) *PriorityClass {
+	if s == nil || name == "" {
+		return nil
+	}
+	for _, class := range s.PriorityClasses {
+		if class.Name == name {
+			return class
+		}
+	}
 	return nil
 }
 
+// NamespaceWeight returns the weight of the namespace when the eval broker
+// shares scheduler workers between namespaces.
+func (s *SchedulerConfiguration) NamespaceWeight(ns *Namespace) int {
+	if ns != nil && ns.SchedulerWeight > 0 {
+		return ns.SchedulerWeight
+	}
+	if s != nil && s.NamespaceFairShare.DefaultWeight > 0 {
+		return s.NamespaceFairShare.DefaultWeight
+	}
+	return NamespaceFairShareDefaultWeight
+}
+
 // SchedulerConfigurationResponse is the response object that wraps SchedulerConfiguration
 type SchedulerConfigurationResponse struct {
 	// SchedulerConfig contains scheduler config options
@@ -313,6 +386,93 @@ type SchedulerSetConfigurationResponse struct {
 	WriteMeta
 }
 
+// NamespaceFairShareDefaultWeight is the weight of namespaces when neither
+// the namespace nor the scheduler configuration set one.
+const NamespaceFairShareDefaultWeight = 1
+
+// NamespaceFairShareConfig configures how the eval broker shares scheduler
+// workers between namespaces.
+type NamespaceFairShareConfig struct {
+	// Enabled specifies whether ready evaluations are dequeued using weighted
+	// fair share across namespaces. When disabled, evaluations are dequeued
+	// by priority regardless of their namespace.
+	Enabled bool `hcl:"enabled"`
+
+	// DefaultWeight is the weight of namespaces that don't set their own
+	// scheduler weight.
+	DefaultWeight int `hcl:"default_weight"`
+}
+
+const (
+	// DeschedulerDefaultMaxMigrations is the maximum number of allocations
+	// migrated by each run of the descheduler when not configured.
+	DeschedulerDefaultMaxMigrations = 10
+
+	// DeschedulerDefaultUtilizationThreshold is the percentage of allocated
+	// resources above which a node is overloaded when not configured.
+	DeschedulerDefaultUtilizationThreshold = 80
+)
+
+// DeschedulerConfig configures the descheduler core job, which periodically
+// migrates a bounded number of healthy service allocations away from the
+// nodes where they make the cluster unbalanced.
+type DeschedulerConfig struct {
+	// Enabled specifies whether the descheduler runs.
+	Enabled bool `hcl:"enabled"`
+
+	// DryRun specifies whether the descheduler only reports the allocations
+	// it would migrate, without migrating them.
+	DryRun bool `hcl:"dry_run"`
+
+	// MaxMigrations is the maximum number of allocations migrated by each
+	// run of the descheduler.
+	MaxMigrations int `hcl:"max_migrations"`
+
+	// UtilizationThreshold is the percentage of CPU or memory allocated on a
+	// node above which allocations are migrated away from it.
+	UtilizationThreshold int `hcl:"utilization_threshold"`
+
+	// FragmentationThreshold is the percentage of CPU and memory allocated
+	// on a node below which allocations are migrated away from it to
+	// consolidate them on fewer nodes. It only applies to the binpack
+	// scheduler algorithm, and zero disables it.
+	FragmentationThreshold int `hcl:"fragmentation_threshold"`
+}
+
+// EffectiveMaxMigrations returns the maximum number of allocations migrated
+// by each run of the descheduler.
+func (d *DeschedulerConfig) EffectiveMaxMigrations() int {
+	if d.MaxMigrations > 0 {
+		return d.MaxMigrations
+	}
+	return DeschedulerDefaultMaxMigrations
+}
+
+// EffectiveUtilizationThreshold returns the percentage of allocated
+// resources above which a node is overloaded.
+func (d *DeschedulerConfig) EffectiveUtilizationThreshold() int {
+	if d.UtilizationThreshold > 0 {
+		return d.UtilizationThreshold
+	}
+	return DeschedulerDefaultUtilizationThreshold
+}
+
+func (d *DeschedulerConfig) Validate() error {
+	if d.MaxMigrations < 0 {
+		return fmt.Errorf("invalid descheduler max migrations: %d", d.MaxMigrations)
+	}
+	if d.UtilizationThreshold < 0 || d.UtilizationThreshold > 100 {
+		return fmt.Errorf("invalid descheduler utilization threshold: %d", d.UtilizationThreshold)
+	}
+	if d.FragmentationThreshold < 0 || d.FragmentationThreshold > 100 {
+		return fmt.Errorf("invalid descheduler fragmentation threshold: %d", d.FragmentationThreshold)
+	}
+	if d.FragmentationThreshold >= d.EffectiveUtilizationThreshold() {
+		return fmt.Errorf("descheduler fragmentation threshold must be lower than the utilization threshold")
+	}
+	return nil
+}
+
 // PreemptionConfig specifies whether preemption is enabled based on scheduler type
 type PreemptionConfig struct {
 	// SystemSchedulerEnabled specifies if preemption is enabled for system jobs
@@ -328,6 +488,91 @@ type PreemptionConfig struct {
 	ServiceSchedulerEnabled bool `hcl:"service_scheduler_enabled"`
 }
 
+const (
+	// PriorityClassPreemptionPolicyLowerPriority allows the jobs of a
+	// priority class to preempt allocations of lower priority jobs.
+	PriorityClassPreemptionPolicyLowerPriority = "lower_priority"
+
+	// PriorityClassPreemptionPolicyNever prevents the jobs of a priority
+	// class from preempting other allocations.
+	PriorityClassPreemptionPolicyNever = "never"
+)
+
+// PriorityClass is a named job priority defined by operators. Jobs that
+// reference a priority class have their priority set to the priority of the
+// class, and preempt or get preempted according to the class.
+type PriorityClass struct {
+	// Name is the name jobs use to reference the priority class.
+	Name string `hcl:",key"`
+
+	// Description is a human readable description of the priority class.
+	Description string `hcl:"description"`
+
+	// Priority is the priority given to the jobs of the priority class.
+	Priority int `hcl:"priority"`
+
+	// PreemptionPolicy specifies whether the jobs of the priority class may
+	// preempt allocations of lower priority jobs.
+	PreemptionPolicy string `hcl:"preemption_policy"`
+
+	// NonPreemptible prevents the allocations of the jobs of the priority
+	// class from being preempted, regardless of their priority.
+	NonPreemptible bool `hcl:"non_preemptible"`
+
+	// Namespaces restricts the priority class to the jobs of the given
+	// namespaces. The priority class can be used in every namespace when
+	// empty.
+	Namespaces []string `hcl:"namespaces"`
+}
+
+func (c *PriorityClass) Copy() *PriorityClass {
+	if c == nil {
+		return nil
+	}
+	nc := *c
+	nc.Namespaces = slices.Clone(c.Namespaces)
+	return &nc
+}
+
+func (c *PriorityClass) Canonicalize() {
+	if c.PreemptionPolicy == "" {
+		c.PreemptionPolicy = PriorityClassPreemptionPolicyLowerPriority
+	}
+}
+
+func (c *PriorityClass) Validate() error {
+	if !validNamespaceName.MatchString(c.Name) {
+		return fmt.Errorf("invalid priority class name %q: must match regex %s", c.Name, validNamespaceName)
+	}
+	if c.Priority < JobMinPriority || c.Priority > JobMaxPriority {
+		return fmt.Errorf("invalid priority class %q priority: %d", c.Name, c.Priority)
+	}
+	switch c.PreemptionPolicy {
+	case "", PriorityClassPreemptionPolicyLowerPriority, PriorityClassPreemptionPolicyNever:
+	default:
+		return fmt.Errorf("invalid priority class %q preemption policy: %q", c.Name, c.PreemptionPolicy)
+	}
+	return nil
+}
+
+// CanPreempt returns whether the jobs of the priority class may preempt
+// allocations of lower priority jobs. Jobs without a priority class may.
+func (c *PriorityClass) CanPreempt() bool {
+	return c == nil || c.PreemptionPolicy != PriorityClassPreemptionPolicyNever
+}
+
+// Preemptible returns whether the allocations of the jobs of the priority
+// class may be preempted. Allocations of jobs without a priority class may.
+func (c *PriorityClass) Preemptible() bool {
+	return c == nil || !c.NonPreemptible
+}
+
+// AllowsNamespace returns whether the priority class can be used by jobs of
+// the given namespace.
+func (c *PriorityClass) AllowsNamespace(namespace string) bool {
+	return len(c.Namespaces) == 0 || slices.Contains(c.Namespaces, namespace)
+}
+
 // SchedulerSetConfigRequest is used by the Operator endpoint to update the
 // current Scheduler configuration of the cluster.
 type SchedulerSetConfigRequest struct {
@@ -106,3 +106,116 @@ func TestSchedulerConfiguration_WithNodePool(t *testing.T) {
 		})
 	}
 }
+
+func TestSchedulerConfiguration_NamespaceWeight(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name        string
+		schedConfig *SchedulerConfiguration
+		ns          *Namespace
+		expected    int
+	}{
+		{
+			name:        "nil config and namespace",
+			schedConfig: nil,
+			ns:          nil,
+			expected:    NamespaceFairShareDefaultWeight,
+		},
+		{
+			name: "config default weight",
+			schedConfig: &SchedulerConfiguration{
+				NamespaceFairShare: NamespaceFairShareConfig{DefaultWeight: 3},
+			},
+			ns:       &Namespace{Name: "default"},
+			expected: 3,
+		},
+		{
+			name: "namespace weight overrides config",
+			schedConfig: &SchedulerConfiguration{
+				NamespaceFairShare: NamespaceFairShareConfig{DefaultWeight: 3},
+			},
+			ns:       &Namespace{Name: "default", SchedulerWeight: 5},
+			expected: 5,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			must.Eq(t, tc.expected, tc.schedConfig.NamespaceWeight(tc.ns))
+		})
+	}
+}
+
+func TestSchedulerConfiguration_PriorityClasses(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name    string
+		classes []*PriorityClass
+		expErr  string
+	}{
+		{
+			name: "valid",
+			classes: []*PriorityClass{
+				{Name: "critical", Priority: 20, NonPreemptible: true},
+				{Name: "batch", Priority: 80, PreemptionPolicy: PriorityClassPreemptionPolicyNever},
+			},
+		},
+		{
+			name:    "invalid name",
+			classes: []*PriorityClass{{Name: "no spaces", Priority: 20}},
+			expErr:  "invalid priority class name",
+		},
+		{
+			name:    "invalid priority",
+			classes: []*PriorityClass{{Name: "critical"}},
+			expErr:  "invalid priority class \"critical\" priority",
+		},
+		{
+			name:    "invalid preemption policy",
+			classes: []*PriorityClass{{Name: "critical", Priority: 20, PreemptionPolicy: "always"}},
+			expErr:  "invalid priority class \"critical\" preemption policy",
+		},
+		{
+			name: "duplicate",
+			classes: []*PriorityClass{
+				{Name: "critical", Priority: 20},
+				{Name: "critical", Priority: 30},
+			},
+			expErr: "duplicate priority class",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			schedConfig := &SchedulerConfiguration{PriorityClasses: tc.classes}
+			err := schedConfig.Validate()
+			if tc.expErr == "" {
+				must.NoError(t, err)
+			} else {
+				must.ErrorContains(t, err, tc.expErr)
+			}
+		})
+	}
+
+	schedConfig := &SchedulerConfiguration{
+		PriorityClasses: []*PriorityClass{
+			{Name: "critical", Priority: 20, NonPreemptible: true, Namespaces: []string{"prod"}},
+		},
+	}
+	schedConfig.Canonicalize()
+
+	class := schedConfig.LookupPriorityClass("critical")
+	must.NotNil(t, class)
+	must.True(t, class.CanPreempt())
+	must.False(t, class.Preemptible())
+	must.True(t, class.AllowsNamespace("prod"))
+	must.False(t, class.AllowsNamespace("default"))
+	must.Nil(t, schedConfig.LookupPriorityClass("unknown"))
+
+	// Copies don't share priority classes
+	copied := schedConfig.Copy()
+	copied.PriorityClasses[0].Namespaces[0] = "dev"
+	must.Eq(t, "prod", class.Namespaces[0])
+}
@@ -20,6 +20,7 @@ import (
 	"maps"
 	"math"
 	"net"
+	"net/url"
 	"os"
 	"reflect"
 	"regexp"
@@ -854,6 +855,9 @@ type JobPlanRequest struct {
 	Diff bool // Toggles an annotated diff
 	// PolicyOverride is set when the user is attempting to override any policies
 	PolicyOverride bool
+	// Explain toggles recording how each node was filtered and scored for
+	// the placements of the job
+	Explain bool
 	WriteRequest
 }
 
@@ -1552,6 +1556,11 @@ type NodeClientAllocsResponse struct {
 	// authenticated access to sticky volumes
 	MigrateTokens map[string]string
 
+	// NamespaceNetworkPolicies are the network policies of the namespaces that
+	// have one, by namespace. Clients merge them with the network policies of
+	// the task groups of their allocations.
+	NamespaceNetworkPolicies map[string]*NetworkPolicy
+
 	QueryMeta
 }
 
@@ -1609,6 +1618,10 @@ type JobDispatchResponse struct {
 	EvalID          string
 	EvalCreateIndex uint64
 	JobCreateIndex  uint64
+
+	// Queued is set if the dispatched job waits for the concurrency limit of
+	// the parameterized job before it is evaluated.
+	Queued bool
 	WriteMeta
 }
 
@@ -1642,6 +1655,10 @@ type JobPlanResponse struct {
 	// FailedTGAllocs is the placement failures per task group.
 	FailedTGAllocs map[string]*AllocMetric
 
+	// PlacementMetrics is the metrics of the placements of the plan, keyed
+	// by allocation name. It's only set when the plan is explained.
+	PlacementMetrics map[string]*AllocMetric
+
 	// JobModifyIndex is the modification index of the job. The value can be
 	// used when running `nomad run` to ensure that the Job wasn’t modified
 	// since the last plan. If the job is being created, the value is zero.
@@ -3006,6 +3023,13 @@ func (n *NetworkResource) PortLabels() map[string]int {
 	return labelValues
 }
 
+// HasEnforcedBandwidth returns true if the network asks for bandwidth that is
+// enforced on the client. Only the bridge and CNI networking modes limit the
+// rate of the allocation, with the bandwidth CNI plugin.
+func (n *NetworkResource) HasEnforcedBandwidth() bool {
+	return n.MBits > 0 && (n.Mode == "bridge" || strings.HasPrefix(n.Mode, "cni/"))
+}
+
 func (n *NetworkResource) IsIPv6() bool {
 	ip := net.ParseIP(n.IP)
 	return ip != nil && ip.To4() == nil
@@ -4323,6 +4347,7 @@ const (
 	JobStatusPending = "pending" // Pending means the job is waiting on scheduling
 	JobStatusRunning = "running" // Running means the job has non-terminal allocations
 	JobStatusDead    = "dead"    // Dead means all evaluation's and allocations are terminal
+	JobStatusQueued  = "queued"  // Queued means the child job waits for its parent's concurrency limit
 )
 
 const (
@@ -4452,6 +4477,12 @@ type Job struct {
 	// can preempt other jobs.
 	Priority int
 
+	// PriorityClass is the name of the operator defined priority class of
+	// the job. When set, the job's priority is set to the priority of the
+	// class on registration, and the class controls how the job takes part
+	// in preemption.
+	PriorityClass string
+
 	// AllAtOnce is used to control if incremental scheduling of task groups
 	// is allowed or if we must do a gang scheduling of the entire job. This
 	// can slow down larger jobs if resources are not available.
@@ -4480,6 +4511,14 @@ type Job struct {
 	// allocations across a desired attribute, such as datacenter
 	Spreads []*Spread
 
+	// TopologySpreads can be specified at the job level to limit the skew
+	// of the allocations of every task group across node attributes
+	TopologySpreads []*TopologySpread
+
+	// JobAffinities can be specified at the job level to place every task
+	// group with or away from the allocations of other jobs
+	JobAffinities []*JobAffinity
+
 	// TaskGroups are the collections of task groups that this job needs
 	// to run. Each task group is an atomic unit of scheduling and placement.
 	TaskGroups []*TaskGroup
@@ -4497,6 +4536,15 @@ type Job struct {
 	// for dispatching.
 	ParameterizedJob *ParameterizedJobConfig
 
+	// DependsOn is the set of jobs that must reach a given state before this
+	// job is placed. It is only supported for batch jobs.
+	DependsOn []*JobDependency
+
+	// Deadline is the duration after the job was first submitted past which
+	// its tasks are killed and its allocations are no longer rescheduled. It
+	// is only supported for batch and sysbatch jobs.
+	Deadline time.Duration
+
 	// Dispatched is used to identify if the Job has been dispatched from a
 	// parameterized job.
 	Dispatched bool
@@ -4505,6 +4553,11 @@ type Job struct {
 	// non-terminal siblings which have the same token value.
 	DispatchIdempotencyToken string
 
+	// Queued is set on the children of periodic and parameterized jobs that
+	// were launched while their parent reached its concurrency limit. They
+	// are evaluated once a running sibling finishes.
+	Queued bool
+
 	// Payload is the payload supplied when the job was dispatched.
 	Payload []byte
 
@@ -4544,6 +4597,11 @@ type Job struct {
 	// UnixNano in UTC
 	SubmitTime int64
 
+	// FirstSubmitTime is the time at which the first version of the job was
+	// submitted as UnixNano in UTC. The deadline of the job is anchored on it
+	// so that updating the job doesn't push the deadline back.
+	FirstSubmitTime int64
+
 	// Raft Indexes
 	CreateIndex uint64
 	// ModifyIndex is the index at which any state of the job last changed
@@ -4705,6 +4763,14 @@ func (j *Job) Canonicalize() {
 		j.Spreads = nil
 	}
 
+	if len(j.TopologySpreads) == 0 {
+		j.TopologySpreads = nil
+	}
+
+	if len(j.JobAffinities) == 0 {
+		j.JobAffinities = nil
+	}
+
 	// Ensure the job is in a namespace.
 	if j.Namespace == "" {
 		j.Namespace = DefaultNamespace
@@ -4729,6 +4795,13 @@ func (j *Job) Canonicalize() {
 	if j.Periodic != nil {
 		j.Periodic.Canonicalize()
 	}
+
+	if len(j.DependsOn) == 0 {
+		j.DependsOn = nil
+	}
+	for _, d := range j.DependsOn {
+		d.Canonicalize()
+	}
 }
 
 // Copy returns a deep copy of the Job. It is expected that callers use recover.
@@ -4742,6 +4815,8 @@ func (j *Job) Copy() *Job {
 	nj.Datacenters = slices.Clone(j.Datacenters)
 	nj.Constraints = CopySliceConstraints(j.Constraints)
 	nj.Affinities = CopySliceAffinities(j.Affinities)
+	nj.TopologySpreads = CopySliceTopologySpreads(j.TopologySpreads)
+	nj.JobAffinities = CopySliceJobAffinities(j.JobAffinities)
 	nj.Multiregion = j.Multiregion.Copy()
 	nj.UI = j.UI.Copy()
 	nj.VersionTag = j.VersionTag.Copy()
@@ -4757,6 +4832,7 @@ func (j *Job) Copy() *Job {
 	nj.Periodic = j.Periodic.Copy()
 	nj.Meta = maps.Clone(j.Meta)
 	nj.ParameterizedJob = j.ParameterizedJob.Copy()
+	nj.DependsOn = CopySliceJobDependencies(j.DependsOn)
 	return nj
 }
 
@@ -4835,6 +4911,24 @@ func (j *Job) Validate() error {
 		}
 	}
 
+	if j.Type == JobTypeSystem || j.Type == JobTypeSysBatch {
+		if j.TopologySpreads != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("System jobs may not have a topology_spread block"))
+		}
+		if j.JobAffinities != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("System jobs may not have a job_affinity block"))
+		}
+	} else {
+		mErr.Errors = append(mErr.Errors, validateTopologySpreads(j.TopologySpreads)...)
+		mErr.Errors = append(mErr.Errors, validateJobAffinities(j.JobAffinities)...)
+	}
+
+	if j.Deadline < 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("Deadline must be a positive value"))
+	} else if j.Deadline > 0 && j.Type != JobTypeBatch && j.Type != JobTypeSysBatch {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Deadline is only supported for %q and %q jobs", JobTypeBatch, JobTypeSysBatch))
+	}
+
 	const MaxDescriptionCharacters = 1000
 	if j.UI != nil {
 		if len(j.UI.Description) > MaxDescriptionCharacters {
@@ -4863,6 +4957,10 @@ func (j *Job) Validate() error {
 			mErr.Errors = append(mErr.Errors, errors.New("ShutdownDelay must be a positive value"))
 		}
 
+		if tg.MaxRunDuration != nil && *tg.MaxRunDuration < 0 {
+			mErr.Errors = append(mErr.Errors, errors.New("MaxRunDuration must be a positive value"))
+		}
+
 		if j.Type == "system" && tg.Count > 1 {
 			mErr.Errors = append(mErr.Errors,
 				fmt.Errorf("Job task group %s has count %d. Count cannot exceed 1 with system scheduler",
@@ -4909,9 +5007,38 @@ func (j *Job) Validate() error {
 		}
 	}
 
+	if len(j.DependsOn) > 0 {
+		if j.Type != JobTypeBatch {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf(
+				"Job dependencies can only be used with %q scheduler", JobTypeBatch,
+			))
+		}
+
+		upstreams := make(map[string]int, len(j.DependsOn))
+		for idx, d := range j.DependsOn {
+			if err := d.Validate(); err != nil {
+				outer := fmt.Errorf("Dependency %d validation failed: %s", idx+1, err)
+				mErr.Errors = append(mErr.Errors, outer)
+			}
+			if d.JobID == j.ID {
+				mErr.Errors = append(mErr.Errors, fmt.Errorf("Dependency %d references the job itself", idx+1))
+			}
+			if existing, ok := upstreams[d.JobID]; ok {
+				mErr.Errors = append(mErr.Errors, fmt.Errorf("Dependency %d redefines %q from dependency %d", idx+1, d.JobID, existing+1))
+			} else {
+				upstreams[d.JobID] = idx
+			}
+		}
+	}
+
 	return mErr.ErrorOrNil()
 }
 
+// HasDependencies returns whether the job depends on other jobs.
+func (j *Job) HasDependencies() bool {
+	return j != nil && len(j.DependsOn) > 0
+}
+
 // Warnings returns a list of warnings that may be from dubious settings or
 // deprecation warnings.
 func (j *Job) Warnings() error {
@@ -4962,6 +5089,25 @@ func (j *Job) LookupTaskGroup(name string) *TaskGroup {
 	return nil
 }
 
+// IsDeploymentHookGroup returns whether the task group runs a deployment hook
+// of another task group of the job.
+func (j *Job) IsDeploymentHookGroup(name string) bool {
+	if j == nil {
+		return false
+	}
+	for _, tg := range j.TaskGroups {
+		if tg.Name == name || tg.Update == nil {
+			continue
+		}
+		for _, hook := range []*DeploymentHook{tg.Update.PreDeploy, tg.Update.PostDeploy} {
+			if hook != nil && hook.Group == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // CombinedTaskMeta takes a TaskGroup and Task name and returns the combined
 // meta data for the task. When joining Job, Group and Task Meta, the precedence
 // is by deepest scope (Task > Group > Job).
@@ -5048,6 +5194,27 @@ func (j *Job) Stub(summary *JobSummary, fields *JobStubFields) *JobListStub {
 	return jobStub
 }
 
+// DeadlineTime returns the time past which the tasks of the job are killed,
+// or the zero time if the job has no deadline. The deadline of periodic and
+// parameterized jobs applies to their children instead.
+func (j *Job) DeadlineTime() time.Time {
+	submitTime := j.FirstSubmitTime
+	if submitTime == 0 {
+		submitTime = j.SubmitTime
+	}
+	if j.Deadline <= 0 || submitTime == 0 || j.IsPeriodic() || j.IsParameterized() {
+		return time.Time{}
+	}
+	return time.Unix(0, submitTime).Add(j.Deadline).UTC()
+}
+
+// DeadlineExceeded returns whether the job has a deadline that passed at the
+// given time.
+func (j *Job) DeadlineExceeded(now time.Time) bool {
+	deadline := j.DeadlineTime()
+	return !deadline.IsZero() && !now.Before(deadline)
+}
+
 // IsPeriodic returns whether a job is periodic.
 func (j *Job) IsPeriodic() bool {
 	return j.Periodic != nil
@@ -5209,6 +5376,7 @@ func (j *Job) SpecChanged(new *Job) bool {
 	c.ModifyIndex = j.ModifyIndex
 	c.JobModifyIndex = j.JobModifyIndex
 	c.SubmitTime = j.SubmitTime
+	c.FirstSubmitTime = j.FirstSubmitTime
 
 	// cgbaker: FINISH: probably need some consideration of scaling policy ID here
 
@@ -5259,6 +5427,10 @@ type JobSummary struct {
 	// Children contains a summary for the children of this job.
 	Children *JobChildrenSummary
 
+	// Deadline is the time past which the tasks of the job are killed as
+	// UnixNano in UTC, or zero if the job has no deadline.
+	Deadline int64
+
 	// Raft Indexes
 	CreateIndex uint64
 	ModifyIndex uint64
@@ -5279,6 +5451,7 @@ func (js *JobSummary) Copy() *JobSummary {
 
 // JobChildrenSummary contains the summary of children job statuses
 type JobChildrenSummary struct {
+	Queued  int64
 	Pending int64
 	Running int64
 	Dead    int64
@@ -5305,6 +5478,11 @@ type TaskGroupSummary struct {
 	Starting int
 	Lost     int
 	Unknown  int
+
+	// CompletedIndexes and FailedIndexes track the progress of indexed task
+	// groups.
+	CompletedIndexes int
+	FailedIndexes    int
 }
 
 const (
@@ -5379,6 +5557,19 @@ type UpdateStrategy struct {
 	// Canary is the number of canaries to deploy when a change to the task
 	// group is detected.
 	Canary int
+
+	// Analysis compares the canaries with the stable allocations before they
+	// are promoted.
+	Analysis *CanaryAnalysis
+
+	// BlueGreen replaces all the allocations of the task group at once
+	// instead of rolling them.
+	BlueGreen *BlueGreenStrategy
+
+	// PreDeploy and PostDeploy are the parameterized jobs dispatched before
+	// the deployment places any allocation and once it's promoted.
+	PreDeploy  *DeploymentHook
+	PostDeploy *DeploymentHook
 }
 
 func (u *UpdateStrategy) Copy() *UpdateStrategy {
@@ -5388,6 +5579,10 @@ func (u *UpdateStrategy) Copy() *UpdateStrategy {
 
 	c := new(UpdateStrategy)
 	*c = *u
+	c.Analysis = u.Analysis.Copy()
+	c.BlueGreen = u.BlueGreen.Copy()
+	c.PreDeploy = u.PreDeploy.Copy()
+	c.PostDeploy = u.PostDeploy.Copy()
 	return c
 }
 
@@ -5430,10 +5625,43 @@ func (u *UpdateStrategy) Validate() error {
 	if u.Stagger <= 0 {
 		_ = multierror.Append(&mErr, fmt.Errorf("Stagger must be greater than zero: %v", u.Stagger))
 	}
+	if err := u.Analysis.Validate(u.Canary); err != nil {
+		_ = multierror.Append(&mErr, err)
+	}
+	if u.BlueGreen != nil {
+		if u.Canary != 0 {
+			_ = multierror.Append(&mErr, fmt.Errorf("Blue/green deployments can not set a Canary count"))
+		}
+		if u.AutoPromote {
+			_ = multierror.Append(&mErr, fmt.Errorf("Blue/green deployments can not be auto promoted"))
+		}
+		if u.BlueGreen.Retention < 0 {
+			_ = multierror.Append(&mErr, fmt.Errorf("Blue/green retention can not be less than zero: %v", u.BlueGreen.Retention))
+		}
+	}
+	if err := u.PreDeploy.Validate(DeploymentHookPreDeploy); err != nil {
+		_ = multierror.Append(&mErr, err)
+	}
+	if err := u.PostDeploy.Validate(DeploymentHookPostDeploy); err != nil {
+		_ = multierror.Append(&mErr, err)
+	}
 
 	return mErr.ErrorOrNil()
 }
 
+// DesiredCanaries returns the number of canaries to place for a task group
+// with the given count. Blue/green deployments place the whole green set as
+// canaries.
+func (u *UpdateStrategy) DesiredCanaries(count int) int {
+	if u == nil {
+		return 0
+	}
+	if u.BlueGreen != nil {
+		return count
+	}
+	return u.Canary
+}
+
 func (u *UpdateStrategy) IsEmpty() bool {
 	if u == nil {
 		return true
@@ -5448,6 +5676,25 @@ func (u *UpdateStrategy) IsEmpty() bool {
 	return u.MaxParallel == 0
 }
 
+// BlueGreenStrategy configures a blue/green deployment of a task group. The
+// green allocations are placed next to the blue ones as canaries, and the
+// blue allocations are kept running for the retention once the deployment is
+// promoted so the job can be switched back to them.
+type BlueGreenStrategy struct {
+	// Retention is how long the blue allocations are kept running after the
+	// deployment is promoted.
+	Retention time.Duration
+}
+
+func (b *BlueGreenStrategy) Copy() *BlueGreenStrategy {
+	if b == nil {
+		return nil
+	}
+	nb := new(BlueGreenStrategy)
+	*nb = *b
+	return nb
+}
+
 // Rolling returns if a rolling strategy should be used.
 // TODO(alexdadgar): Remove once no longer used by the scheduler.
 func (u *UpdateStrategy) Rolling() bool {
@@ -5536,6 +5783,15 @@ type Namespace struct {
 	VaultConfiguration  *NamespaceVaultConfiguration
 	ConsulConfiguration *NamespaceConsulConfiguration
 
+	// SchedulerWeight is the weight of the namespace when the eval broker
+	// shares scheduler workers between namespaces. If zero, the default
+	// weight from the scheduler configuration is used.
+	SchedulerWeight int
+
+	// NetworkPolicy is the network policy applied to the task groups in
+	// bridge networking mode of the jobs in the namespace.
+	NetworkPolicy *NetworkPolicy
+
 	// Meta is the set of metadata key/value pairs that attached to the namespace
 	Meta map[string]string
 
@@ -5591,6 +5847,10 @@ func (n *Namespace) Validate() error {
 		err := fmt.Errorf("description longer than %d", maxNamespaceDescriptionLength)
 		mErr.Errors = append(mErr.Errors, err)
 	}
+	if n.SchedulerWeight < 0 {
+		err := fmt.Errorf("scheduler weight must be greater than or equal to zero: %d", n.SchedulerWeight)
+		mErr.Errors = append(mErr.Errors, err)
+	}
 
 	err := n.NodePoolConfiguration.Validate()
 	switch e := err.(type) {
@@ -5622,6 +5882,16 @@ func (n *Namespace) Validate() error {
 		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid consul configuration: %v", e))
 	}
 
+	err = n.NetworkPolicy.Validate()
+	switch e := err.(type) {
+	case *multierror.Error:
+		for _, pErr := range e.Errors {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid network policy: %v", pErr))
+		}
+	case error:
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid network policy: %v", e))
+	}
+
 	return mErr.ErrorOrNil()
 }
 
@@ -5681,6 +5951,19 @@ func (n *Namespace) SetHash() []byte {
 		}
 	}
 
+	if n.SchedulerWeight != 0 {
+		_, _ = hash.Write([]byte(strconv.Itoa(n.SchedulerWeight)))
+	}
+
+	if n.NetworkPolicy != nil {
+		_, _ = hash.Write([]byte(n.NetworkPolicy.Default))
+		for _, rule := range n.NetworkPolicy.Allow {
+			_, _ = hash.Write([]byte(rule.Namespace))
+			_, _ = hash.Write([]byte(rule.Job))
+			_, _ = hash.Write([]byte(rule.Service))
+		}
+	}
+
 	// sort keys to ensure hash stability when meta is stored later
 	var keys []string
 	for k := range n.Meta {
@@ -5732,6 +6015,7 @@ func (n *Namespace) Copy() *Namespace {
 		nc.Allowed = slices.Clone(n.ConsulConfiguration.Allowed)
 		nc.Denied = slices.Clone(n.ConsulConfiguration.Denied)
 	}
+	nc.NetworkPolicy = n.NetworkPolicy.Copy()
 
 	if n.Meta != nil {
 		nc.Meta = make(map[string]string, len(n.Meta))
@@ -5797,6 +6081,27 @@ const (
 	// PeriodicSpecTest is only used by unit tests. It is a sorted, comma
 	// separated list of unix timestamps at which to launch.
 	PeriodicSpecTest = "_internal_test"
+
+	// JobConcurrency is the policy applied when a periodic or parameterized
+	// job launches a child while it has reached its concurrency limit.
+	JobConcurrencyAllow   = "allow"
+	JobConcurrencyForbid  = "forbid"
+	JobConcurrencyReplace = "replace"
+	JobConcurrencyQueue   = "queue"
+
+	// PeriodicCatchup is the policy used to launch the periodic launches that
+	// were missed while there was no leader to dispatch them.
+	PeriodicCatchupNone   = "none"
+	PeriodicCatchupLatest = "latest"
+	PeriodicCatchupAll    = "all"
+
+	// DefaultPeriodicCatchupMaxCount is the maximum number of missed launches
+	// dispatched by the "all" catch-up policy if no maximum is set.
+	DefaultPeriodicCatchupMaxCount = 10
+
+	// PeriodicCatchupMetaKey is the meta key set on the jobs derived for
+	// missed launches. Its value is the missed launch time in RFC3339.
+	PeriodicCatchupMetaKey = "nomad_periodic_catchup"
 )
 
 // Periodic defines the interval a job should be run at.
@@ -5818,6 +6123,11 @@ type PeriodicConfig struct {
 	// ProhibitOverlap enforces that spawned jobs do not run in parallel.
 	ProhibitOverlap bool
 
+	// Concurrency is the policy applied when the job launches while a
+	// previous launch is still running: "allow", "forbid", "replace" or
+	// "queue". ProhibitOverlap is the same as "forbid".
+	Concurrency string
+
 	// TimeZone is the user specified string that determines the time zone to
 	// launch against. The time zones must be specified from IANA Time Zone
 	// database, such as "America/New_York".
@@ -5825,6 +6135,18 @@ type PeriodicConfig struct {
 	// Reference: https://www.iana.org/time-zones
 	TimeZone string
 
+	// Catchup is the policy used to launch the launches missed while there
+	// was no leader: "none", "latest" or "all". It defaults to "latest".
+	Catchup string
+
+	// CatchupMaxCount is the maximum number of missed launches dispatched by
+	// the "all" catch-up policy.
+	CatchupMaxCount int
+
+	// CatchupMaxAge is the maximum age of the missed launches to dispatch.
+	// Zero means missed launches of any age are dispatched.
+	CatchupMaxAge time.Duration
+
 	// location is the time zone to evaluate the launch time against
 	location *time.Location
 }
@@ -5879,6 +6201,25 @@ func (p *PeriodicConfig) Validate() error {
 		_ = multierror.Append(&mErr, fmt.Errorf("Unknown periodic specification type %q", p.SpecType))
 	}
 
+	if err := validateJobConcurrency(p.Concurrency); err != nil {
+		_ = multierror.Append(&mErr, err)
+	}
+	if p.ProhibitOverlap && p.Concurrency != "" && p.Concurrency != JobConcurrencyForbid {
+		_ = multierror.Append(&mErr, fmt.Errorf("Concurrency policy %q conflicts with prohibit_overlap", p.Concurrency))
+	}
+
+	switch p.Catchup {
+	case "", PeriodicCatchupNone, PeriodicCatchupLatest, PeriodicCatchupAll:
+	default:
+		_ = multierror.Append(&mErr, fmt.Errorf("Unknown catch-up policy %q", p.Catchup))
+	}
+	if p.CatchupMaxCount < 0 {
+		_ = multierror.Append(&mErr, fmt.Errorf("Catch-up max count can not be less than zero"))
+	}
+	if p.CatchupMaxAge < 0 {
+		_ = multierror.Append(&mErr, fmt.Errorf("Catch-up max age can not be less than zero"))
+	}
+
 	return mErr.ErrorOrNil()
 }
 
@@ -5961,6 +6302,98 @@ func (p *PeriodicConfig) Next(fromTime time.Time) (time.Time, error) {
 	return time.Time{}, nil
 }
 
+// ConcurrencyPolicy returns the policy applied when the job launches while a
+// previous launch is still running.
+func (p *PeriodicConfig) ConcurrencyPolicy() string {
+	if p.Concurrency != "" {
+		return p.Concurrency
+	}
+	if p.ProhibitOverlap {
+		return JobConcurrencyForbid
+	}
+	return JobConcurrencyAllow
+}
+
+// MissedLaunches returns the launch times after the last launch and up to now
+// that the catch-up policy dispatches, oldest first.
+func (p *PeriodicConfig) MissedLaunches(last, now time.Time) ([]time.Time, error) {
+	if p.Catchup == PeriodicCatchupNone {
+		return nil, nil
+	}
+
+	from := last
+	if p.CatchupMaxAge > 0 {
+		if oldest := now.Add(-p.CatchupMaxAge); oldest.After(from) {
+			from = oldest
+		}
+	}
+
+	maxCount := 1
+	if p.Catchup == PeriodicCatchupAll {
+		maxCount = p.CatchupMaxCount
+		if maxCount == 0 {
+			maxCount = DefaultPeriodicCatchupMaxCount
+		}
+	}
+
+	// Walk the launches back from now, so that only the launches that are
+	// dispatched are computed however long the job didn't launch for
+	var missed []time.Time
+	until := now
+	for len(missed) < maxCount {
+		launch, err := p.latestLaunch(from, until)
+		if err != nil {
+			return nil, err
+		}
+		if launch.IsZero() {
+			break
+		}
+		missed = append(missed, launch)
+		until = launch.Add(-time.Nanosecond)
+	}
+
+	slices.Reverse(missed)
+	return missed, nil
+}
+
+// latestLaunch returns the latest launch time after from and up to until, or
+// the zero time if there is none. The launch times are whole seconds, so the
+// launch is found with a binary search on Next instead of walking every
+// launch in between.
+func (p *PeriodicConfig) latestLaunch(from, until time.Time) (time.Time, error) {
+	// beforeUntil returns whether the next launch after t is up to until
+	beforeUntil := func(t time.Time) (bool, error) {
+		next, err := p.Next(t)
+		if err != nil {
+			return false, err
+		}
+		return !next.IsZero() && !next.After(until), nil
+	}
+
+	ok, err := beforeUntil(from)
+	if err != nil || !ok {
+		return time.Time{}, err
+	}
+
+	// The next launch after lo is up to until and the next launch after hi
+	// is past until, so the latest launch is the next launch after lo once
+	// there is at most a second between them.
+	lo, hi := from, until
+	for hi.Sub(lo) > time.Second {
+		mid := lo.Add(hi.Sub(lo) / 2)
+		ok, err := beforeUntil(mid)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if ok {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return p.Next(lo)
+}
+
 // GetLocation returns the location to use for determining the time zone to run
 // the periodic job against.
 func (p *PeriodicConfig) GetLocation() *time.Location {
@@ -6009,6 +6442,20 @@ type ParameterizedJobConfig struct {
 
 	// MetaOptional is metadata keys that may be specified by the dispatcher
 	MetaOptional []string
+
+	// Concurrency is the policy applied when the job is dispatched while
+	// MaxConcurrent of its children are running: "allow", "forbid",
+	// "replace" or "queue".
+	Concurrency string
+
+	// MaxConcurrent is the number of children that may run at the same time
+	// before the concurrency policy applies. Zero means one.
+	MaxConcurrent int
+
+	// DispatchQueue limits the number of dispatched jobs that run at the same
+	// time and the rate at which they are evaluated. It implies the "queue"
+	// concurrency policy.
+	DispatchQueue *DispatchQueueConfig
 }
 
 func (d *ParameterizedJobConfig) Validate() error {
@@ -6025,9 +6472,67 @@ func (d *ParameterizedJobConfig) Validate() error {
 		_ = multierror.Append(&mErr, fmt.Errorf("Required and optional meta keys should be disjoint. Following keys exist in both: %v", offending))
 	}
 
+	if err := validateJobConcurrency(d.Concurrency); err != nil {
+		_ = multierror.Append(&mErr, err)
+	}
+	if d.MaxConcurrent < 0 {
+		_ = multierror.Append(&mErr, fmt.Errorf("Max concurrent can not be less than zero"))
+	} else if d.MaxConcurrent > 0 && d.ConcurrencyPolicy() == JobConcurrencyAllow {
+		_ = multierror.Append(&mErr, fmt.Errorf("Max concurrent requires a concurrency policy other than %q", JobConcurrencyAllow))
+	}
+
+	if d.DispatchQueue != nil {
+		if d.Concurrency != "" && d.Concurrency != JobConcurrencyQueue {
+			_ = multierror.Append(&mErr, fmt.Errorf("Dispatch queue conflicts with concurrency policy %q", d.Concurrency))
+		}
+		if d.MaxConcurrent > 0 {
+			_ = multierror.Append(&mErr, fmt.Errorf("Dispatch queue conflicts with max concurrent; use max running instead"))
+		}
+		if err := d.DispatchQueue.Validate(); err != nil {
+			_ = multierror.Append(&mErr, err)
+		}
+	}
+
 	return mErr.ErrorOrNil()
 }
 
+// ConcurrencyPolicy returns the policy applied when the job is dispatched
+// while its concurrency limit is reached.
+func (d *ParameterizedJobConfig) ConcurrencyPolicy() string {
+	if d.DispatchQueue != nil {
+		return JobConcurrencyQueue
+	}
+	if d.Concurrency == "" {
+		return JobConcurrencyAllow
+	}
+	return d.Concurrency
+}
+
+// ConcurrencyLimit returns the number of children that may run at the same
+// time before the concurrency policy applies.
+func (d *ParameterizedJobConfig) ConcurrencyLimit() int {
+	if d.DispatchQueue != nil {
+		if d.DispatchQueue.MaxRunning > 0 {
+			return d.DispatchQueue.MaxRunning
+		}
+		return math.MaxInt
+	}
+	if d.MaxConcurrent > 0 {
+		return d.MaxConcurrent
+	}
+	return 1
+}
+
+// validateJobConcurrency returns an error if the concurrency policy is
+// unknown.
+func validateJobConcurrency(policy string) error {
+	switch policy {
+	case "", JobConcurrencyAllow, JobConcurrencyForbid, JobConcurrencyReplace, JobConcurrencyQueue:
+		return nil
+	}
+	return fmt.Errorf("Unknown concurrency policy %q", policy)
+}
+
 func (d *ParameterizedJobConfig) Canonicalize() {
 	if d.Payload == "" {
 		d.Payload = DispatchPayloadOptional
@@ -6042,9 +6547,62 @@ func (d *ParameterizedJobConfig) Copy() *ParameterizedJobConfig {
 	*nd = *d
 	nd.MetaOptional = slices.Clone(nd.MetaOptional)
 	nd.MetaRequired = slices.Clone(nd.MetaRequired)
+	nd.DispatchQueue = d.DispatchQueue.Copy()
 	return nd
 }
 
+// DispatchQueueConfig limits how the dispatched jobs of a parameterized job
+// are evaluated. Dispatched jobs beyond the limits are queued, and released
+// once running ones finish and the rate allows.
+type DispatchQueueConfig struct {
+	// MaxRunning is the number of dispatched jobs that may be pending or
+	// running at the same time. Zero means no limit.
+	MaxRunning int
+
+	// Rate is the number of dispatched jobs evaluated per second. Zero means
+	// no limit.
+	Rate float64
+
+	// Burst is the number of dispatched jobs that may be evaluated at once
+	// above Rate. Zero means one.
+	Burst int
+}
+
+func (q *DispatchQueueConfig) Copy() *DispatchQueueConfig {
+	if q == nil {
+		return nil
+	}
+	nq := new(DispatchQueueConfig)
+	*nq = *q
+	return nq
+}
+
+func (q *DispatchQueueConfig) Validate() error {
+	var mErr multierror.Error
+	if q.MaxRunning < 0 {
+		_ = multierror.Append(&mErr, fmt.Errorf("Dispatch queue max running can not be less than zero"))
+	}
+	if q.Rate < 0 {
+		_ = multierror.Append(&mErr, fmt.Errorf("Dispatch queue rate can not be less than zero"))
+	}
+	if q.Burst < 0 {
+		_ = multierror.Append(&mErr, fmt.Errorf("Dispatch queue burst can not be less than zero"))
+	}
+	if q.MaxRunning == 0 && q.Rate == 0 {
+		_ = multierror.Append(&mErr, fmt.Errorf("Dispatch queue must set max running or rate"))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// BurstSize returns the number of dispatched jobs that may be evaluated at
+// once above the rate.
+func (q *DispatchQueueConfig) BurstSize() int {
+	if q.Burst > 0 {
+		return q.Burst
+	}
+	return 1
+}
+
 // DispatchedID returns an ID appropriate for a job dispatched against a
 // particular parameterized job
 func DispatchedID(templateID, idPrefixTemplate string, t time.Time) string {
@@ -6132,6 +6690,7 @@ var (
 		Interval:        30 * time.Minute,
 		Mode:            RestartPolicyModeFail,
 		RenderTemplates: false,
+		DelayFunction:   "constant",
 	}
 	DefaultBatchJobRestartPolicy = RestartPolicy{
 		Delay:           15 * time.Second,
@@ -6139,6 +6698,7 @@ var (
 		Interval:        24 * time.Hour,
 		Mode:            RestartPolicyModeFail,
 		RenderTemplates: false,
+		DelayFunction:   "constant",
 	}
 )
 
@@ -6173,6 +6733,10 @@ const (
 	// restart policy.
 	RestartPolicyMinInterval = 5 * time.Second
 
+	// RestartPolicyDefaultJitter is the default fraction of the delay added
+	// at random to the delay between restarts.
+	RestartPolicyDefaultJitter = 0.25
+
 	// ReasonWithinPolicy describes restart events that are within policy
 	ReasonWithinPolicy = "Restart within policy"
 )
@@ -6477,6 +7041,7 @@ type RestartPolicy struct {
 	Interval time.Duration
 
 	// Delay is the time between a failure and a restart.
+	// The delay function determines how much subsequent restarts are delayed by.
 	Delay time.Duration
 
 	// Mode controls what happens when the task restarts more than attempt times
@@ -6485,6 +7050,23 @@ type RestartPolicy struct {
 
 	// RenderTemplates is flag to explicitly render all templates on task restart
 	RenderTemplates bool
+
+	// DelayFunction determines how the delay progressively changes on
+	// subsequent restarts within an interval. Valid values are "exponential",
+	// "constant", and "fibonacci".
+	DelayFunction string
+
+	// MaxDelay is an upper bound on the delay. Zero means no upper bound.
+	MaxDelay time.Duration
+
+	// Jitter is the fraction of the delay that is added at random to it, so
+	// that tasks failing together don't restart together. If unset, the
+	// RestartPolicyDefaultJitter is used.
+	Jitter *float64
+
+	// ExitRules decide whether a task that exited with a given exit code or
+	// signal is restarted, or completes or fails without restarting.
+	ExitRules []*ExitRule
 }
 
 func (r *RestartPolicy) Copy() *RestartPolicy {
@@ -6493,6 +7075,8 @@ func (r *RestartPolicy) Copy() *RestartPolicy {
 	}
 	nrp := new(RestartPolicy)
 	*nrp = *r
+	nrp.Jitter = pointer.Copy(r.Jitter)
+	nrp.ExitRules = copyExitRules(r.ExitRules)
 	return nrp
 }
 
@@ -6512,13 +7096,89 @@ func (r *RestartPolicy) Validate() error {
 	if r.Interval.Nanoseconds() < RestartPolicyMinInterval.Nanoseconds() {
 		_ = multierror.Append(&mErr, fmt.Errorf("Interval can not be less than %v (got %v)", RestartPolicyMinInterval, r.Interval))
 	}
-	if time.Duration(r.Attempts)*r.Delay > r.Interval {
-		_ = multierror.Append(&mErr,
-			fmt.Errorf("Nomad can't restart the TaskGroup %v times in an interval of %v with a delay of %v", r.Attempts, r.Interval, r.Delay))
+
+	delayPreCheck := true
+	if r.DelayFunction != "" && !isValidDelayFunction(r.DelayFunction) {
+		_ = multierror.Append(&mErr, fmt.Errorf("Invalid delay function %q, must be one of %q", r.DelayFunction, RescheduleDelayFunctions))
+		delayPreCheck = false
+	}
+	if r.MaxDelay < 0 {
+		_ = multierror.Append(&mErr, fmt.Errorf("Max Delay cannot be negative (got %v)", r.MaxDelay))
+		delayPreCheck = false
+	} else if r.MaxDelay > 0 && r.MaxDelay < r.Delay {
+		_ = multierror.Append(&mErr, fmt.Errorf("Max Delay cannot be less than Delay %v (got %v)", r.Delay, r.MaxDelay))
+		delayPreCheck = false
+	}
+	if r.Jitter != nil && (*r.Jitter < 0 || *r.Jitter > 1) {
+		_ = multierror.Append(&mErr, fmt.Errorf("Jitter must be between 0 and 1 (got %v)", *r.Jitter))
+	}
+	if err := validateExitRules(r.ExitRules, RestartExitRuleActions); err != nil {
+		_ = multierror.Append(&mErr, err)
+	}
+
+	if delayPreCheck {
+		var total time.Duration
+		for attempt := 1; attempt <= r.Attempts && total <= r.Interval; attempt++ {
+			total += r.NextDelay(attempt)
+		}
+		if total > r.Interval {
+			if r.DelayFunction == "" || r.DelayFunction == "constant" {
+				_ = multierror.Append(&mErr,
+					fmt.Errorf("Nomad can't restart the TaskGroup %v times in an interval of %v with a delay of %v", r.Attempts, r.Interval, r.Delay))
+			} else {
+				_ = multierror.Append(&mErr,
+					fmt.Errorf("Nomad can't restart the TaskGroup %v times in an interval of %v with initial delay %v, "+
+						"delay function %q, and delay ceiling %v", r.Attempts, r.Interval, r.Delay, r.DelayFunction, r.MaxDelay))
+			}
+		}
 	}
 	return mErr.ErrorOrNil()
 }
 
+// GetJitter returns the fraction of the delay that is added at random to it,
+// which defaults to RestartPolicyDefaultJitter for the policies of jobs
+// submitted before the jitter could be set.
+func (r *RestartPolicy) GetJitter() float64 {
+	if r.Jitter == nil {
+		return RestartPolicyDefaultJitter
+	}
+	return *r.Jitter
+}
+
+// NextDelay returns the delay before the given restart attempt within an
+// interval, starting at 1, according to the delay function and without
+// jitter.
+func (r *RestartPolicy) NextDelay(attempt int) time.Duration {
+	delay := r.Delay
+	switch r.DelayFunction {
+	case "exponential":
+		for i := 1; i < attempt && !r.exceedsMaxDelay(delay); i++ {
+			delay *= 2
+		}
+	case "fibonacci":
+		prev := r.Delay
+		for i := 2; i < attempt && !r.exceedsMaxDelay(delay); i++ {
+			prev, delay = delay, prev+delay
+		}
+	}
+	if r.MaxDelay > 0 && delay > r.MaxDelay {
+		delay = r.MaxDelay
+	}
+	return delay
+}
+
+// exceedsMaxDelay returns whether the delay reached the upper bound of the
+// policy, or the interval if there is none, so it stops growing.
+func (r *RestartPolicy) exceedsMaxDelay(delay time.Duration) bool {
+	if delay <= 0 {
+		return true
+	}
+	if r.MaxDelay > 0 {
+		return delay >= r.MaxDelay
+	}
+	return delay > r.Interval
+}
+
 func NewRestartPolicy(jobType string) *RestartPolicy {
 	switch jobType {
 	case JobTypeService, JobTypeSystem:
@@ -6558,6 +7218,10 @@ type ReschedulePolicy struct {
 	// Unlimited allows infinite rescheduling attempts. Only allowed when delay is set
 	// between reschedule attempts.
 	Unlimited bool
+
+	// ExitRules decide whether an allocation that failed because a task
+	// exited with a given exit code or signal is rescheduled.
+	ExitRules []*ExitRule
 }
 
 func (r *ReschedulePolicy) Copy() *ReschedulePolicy {
@@ -6566,6 +7230,7 @@ func (r *ReschedulePolicy) Copy() *ReschedulePolicy {
 	}
 	nrp := new(ReschedulePolicy)
 	*nrp = *r
+	nrp.ExitRules = copyExitRules(r.ExitRules)
 	return nrp
 }
 
@@ -6583,6 +7248,10 @@ func (r *ReschedulePolicy) Validate() error {
 		return nil
 	}
 	var mErr multierror.Error
+	if err := validateExitRules(r.ExitRules, RescheduleExitRuleActions); err != nil {
+		_ = multierror.Append(&mErr, err)
+	}
+
 	// Check for ambiguous/confusing settings
 	if r.Attempts > 0 {
 		if r.Interval <= 0 {
@@ -6752,6 +7421,10 @@ type MigrateStrategy struct {
 	HealthCheck     string
 	MinHealthyTime  time.Duration
 	HealthyDeadline time.Duration
+
+	// DisableDescheduling prevents the descheduler from migrating the
+	// allocations of the task group to rebalance the cluster.
+	DisableDescheduling bool
 }
 
 // DefaultMigrateStrategy is used for backwards compat with pre-0.8 Allocations
@@ -6853,10 +7526,23 @@ type TaskGroup struct {
 	// allocations across a desired attribute, such as datacenter
 	Spreads []*Spread
 
+	// TopologySpreads can be specified at the task group level to limit the
+	// skew of its allocations across node attributes. They are nested within
+	// the topology spreads of the job.
+	TopologySpreads []*TopologySpread
+
+	// JobAffinities can be specified at the task group level to place its
+	// allocations with or away from the allocations of other jobs.
+	JobAffinities []*JobAffinity
+
 	// Networks are the network configuration for the task group. This can be
 	// overridden in the task.
 	Networks Networks
 
+	// NetworkPolicy controls which allocations on the bridge network may
+	// reach the allocations of the group.
+	NetworkPolicy *NetworkPolicy
+
 	// Consul configuration specific to this task group
 	Consul *Consul
 
@@ -6870,6 +7556,10 @@ type TaskGroup struct {
 	// group services in consul and stopping tasks.
 	ShutdownDelay *time.Duration
 
+	// MaxRunDuration is the default maximum duration the tasks of the group
+	// may run for before being killed, for tasks that don't set their own.
+	MaxRunDuration *time.Duration
+
 	// StopAfterClientDisconnect, if set, configures the client to stop the task group
 	// after this duration since the last known good heartbeat
 	// To be deprecated after 1.8.0 infavor of Disconnect.StopOnClientAfter
@@ -6885,6 +7575,15 @@ type TaskGroup struct {
 	// To be deprecated after 1.8.0
 	// To be deprecated after 1.8.0 infavor of Disconnect.Replace
 	PreventRescheduleOnLost bool
+
+	// Gang is used to request all-or-nothing placement for the task group.
+	// When set, the scheduler either places every allocation the group needs
+	// in a single plan or places none of them and blocks the evaluation.
+	Gang bool
+
+	// Indexed turns the allocations of a batch task group into completion
+	// indexes that are each retried until they succeed or fail too often.
+	Indexed *IndexedPolicy
 }
 
 func (tg *TaskGroup) Copy() *TaskGroup {
@@ -6898,11 +7597,15 @@ func (tg *TaskGroup) Copy() *TaskGroup {
 	ntg.RestartPolicy = ntg.RestartPolicy.Copy()
 	ntg.Disconnect = ntg.Disconnect.Copy()
 	ntg.ReschedulePolicy = ntg.ReschedulePolicy.Copy()
+	ntg.Indexed = ntg.Indexed.Copy()
 	ntg.Affinities = CopySliceAffinities(ntg.Affinities)
 	ntg.Spreads = CopySliceSpreads(ntg.Spreads)
+	ntg.TopologySpreads = CopySliceTopologySpreads(ntg.TopologySpreads)
+	ntg.JobAffinities = CopySliceJobAffinities(ntg.JobAffinities)
 	ntg.Volumes = CopyMapVolumeRequest(ntg.Volumes)
 	ntg.Scaling = ntg.Scaling.Copy()
 	ntg.Consul = ntg.Consul.Copy()
+	ntg.NetworkPolicy = ntg.NetworkPolicy.Copy()
 
 	// Copy the network objects
 	if tg.Networks != nil {
@@ -6934,6 +7637,10 @@ func (tg *TaskGroup) Copy() *TaskGroup {
 		}
 	}
 
+	if tg.MaxRunDuration != nil {
+		ntg.MaxRunDuration = pointer.Of(*tg.MaxRunDuration)
+	}
+
 	if tg.ShutdownDelay != nil {
 		ntg.ShutdownDelay = tg.ShutdownDelay
 	}
@@ -6961,6 +7668,14 @@ func (tg *TaskGroup) Canonicalize(job *Job) {
 		tg.Spreads = nil
 	}
 
+	if len(tg.TopologySpreads) == 0 {
+		tg.TopologySpreads = nil
+	}
+
+	if len(tg.JobAffinities) == 0 {
+		tg.JobAffinities = nil
+	}
+
 	// Set the default restart policy.
 	if tg.RestartPolicy == nil {
 		tg.RestartPolicy = NewRestartPolicy(job.Type)
@@ -6974,6 +7689,10 @@ func (tg *TaskGroup) Canonicalize(job *Job) {
 		tg.Disconnect.Canonicalize()
 	}
 
+	if tg.Indexed != nil {
+		tg.Indexed.Canonicalize()
+	}
+
 	// Canonicalize Migrate for service jobs
 	if job.Type == JobTypeService && tg.Migrate == nil {
 		tg.Migrate = DefaultMigrateStrategy()
@@ -7001,6 +7720,16 @@ func (tg *TaskGroup) Canonicalize(job *Job) {
 	}
 }
 
+// EffectiveReschedulePolicy returns the reschedule policy used for the
+// allocations of the task group, which is derived from the indexed block if
+// the task group has one.
+func (tg *TaskGroup) EffectiveReschedulePolicy() *ReschedulePolicy {
+	if tg.Indexed != nil {
+		return tg.Indexed.ReschedulePolicy(tg.ReschedulePolicy)
+	}
+	return tg.ReschedulePolicy
+}
+
 // NomadServices returns a list of all group and task - level services in tg that
 // are making use of the nomad service provider.
 func (tg *TaskGroup) NomadServices() []*Service {
@@ -7057,6 +7786,25 @@ func (tg *TaskGroup) Validate(j *Job) error {
 		}
 	}
 
+	if tg.Gang && j.Type != JobTypeService && j.Type != JobTypeBatch {
+		mErr = multierror.Append(mErr, fmt.Errorf("Gang scheduling can only be used with service or batch job types"))
+	}
+
+	if tg.Indexed != nil {
+		if err := tg.Indexed.Validate(j, tg); err != nil {
+			mErr = multierror.Append(mErr, err)
+		}
+	}
+
+	if tg.NetworkPolicy != nil {
+		if len(tg.Networks) == 0 || tg.Networks[0].Mode != "bridge" {
+			mErr = multierror.Append(mErr, errors.New("Network policy requires bridge networking mode"))
+		}
+		if err := tg.NetworkPolicy.Validate(); err != nil {
+			mErr = multierror.Append(mErr, fmt.Errorf("Network policy validation failed: %v", err))
+		}
+	}
+
 	for idx, constr := range tg.Constraints {
 		if err := constr.Validate(); err != nil {
 			outer := fmt.Errorf("Constraint %d validation failed: %s", idx+1, err)
@@ -7097,6 +7845,18 @@ func (tg *TaskGroup) Validate(j *Job) error {
 		}
 	}
 
+	if j.Type == JobTypeSystem || j.Type == JobTypeSysBatch {
+		if tg.TopologySpreads != nil {
+			mErr = multierror.Append(mErr, fmt.Errorf("System jobs may not have a topology_spread block"))
+		}
+		if tg.JobAffinities != nil {
+			mErr = multierror.Append(mErr, fmt.Errorf("System jobs may not have a job_affinity block"))
+		}
+	} else {
+		mErr = multierror.Append(mErr, validateTopologySpreads(tg.TopologySpreads)...)
+		mErr = multierror.Append(mErr, validateJobAffinities(tg.JobAffinities)...)
+	}
+
 	if j.Type == JobTypeSystem {
 		if tg.ReschedulePolicy != nil {
 			mErr = multierror.Append(mErr, fmt.Errorf("System jobs should not have a reschedule policy"))
@@ -7129,6 +7889,19 @@ func (tg *TaskGroup) Validate(j *Job) error {
 		if err := u.Validate(); err != nil {
 			mErr = multierror.Append(mErr, err)
 		}
+		// The update strategy of hook task groups is ignored since they
+		// aren't deployed
+		for _, hook := range []*DeploymentHook{u.PreDeploy, u.PostDeploy} {
+			switch {
+			case hook == nil || j.IsDeploymentHookGroup(tg.Name):
+			case hook.Job != "" && hook.Job == j.ID:
+				mErr = multierror.Append(mErr, fmt.Errorf("Deployment hook job %q can not be the job itself", hook.Job))
+			case hook.Group == tg.Name:
+				mErr = multierror.Append(mErr, fmt.Errorf("Deployment hook group %q can not be the group itself", hook.Group))
+			case hook.Group != "" && j.LookupTaskGroup(hook.Group) == nil:
+				mErr = multierror.Append(mErr, fmt.Errorf("Deployment hook group %q not found", hook.Group))
+			}
+		}
 	}
 
 	// Validate the migration strategy
@@ -7166,11 +7939,14 @@ func (tg *TaskGroup) Validate(j *Job) error {
 		mErr = multierror.Append(mErr, fmt.Errorf("Only one task may be marked as leader"))
 	}
 
-	// Validate the volume requests
-	var canaries int
-	if tg.Update != nil {
-		canaries = tg.Update.Canary
+	// Validate the dependencies between tasks
+	if err := tg.validateTaskDependencies(); err != nil {
+		outer := fmt.Errorf("Task dependency validation failed: %v", err)
+		mErr = multierror.Append(mErr, outer)
 	}
+
+	// Validate the volume requests
+	canaries := tg.Update.DesiredCanaries(tg.Count)
 	for name, volReq := range tg.Volumes {
 		if err := volReq.Validate(j.Type, tg.Count, canaries); err != nil {
 			mErr = multierror.Append(mErr, fmt.Errorf(
@@ -7550,9 +8326,10 @@ func (tg *TaskGroup) Warnings(j *Job) error {
 		mErr.Errors = append(mErr.Errors, errors.New("PreventRescheduleOnLost is deprecated and ignored in favor of Disconnect.Replace"))
 	}
 
-	// Check for mbits network field
-	if len(tg.Networks) > 0 && tg.Networks[0].MBits > 0 {
-		mErr.Errors = append(mErr.Errors, fmt.Errorf("mbits has been deprecated as of Nomad 0.12.0. Please remove mbits from the network block"))
+	// Check for mbits network field, which is only enforced by the bandwidth
+	// CNI plugin
+	if len(tg.Networks) > 0 && tg.Networks[0].MBits > 0 && !tg.Networks[0].HasEnforcedBandwidth() {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("mbits is only enforced in bridge and cni network modes"))
 	}
 
 	// Validate group-level services.
@@ -7720,6 +8497,24 @@ type LogConfig struct {
 	MaxFiles      int
 	MaxFileSizeMB int
 	Disabled      bool
+
+	// Compress is the algorithm rotated log files are compressed with, if any
+	Compress string
+
+	// MaxAge is how long rotated log files are retained, if set
+	MaxAge time.Duration
+
+	// MaxTotalSizeMB is the maximum size of all the log files of a stream, if
+	// set
+	MaxTotalSizeMB int
+
+	// Timestamps prefixes each line of the log files with the time it was
+	// written at
+	Timestamps bool
+
+	// Sinks are the external destinations the task logs are shipped to in
+	// addition to the log files
+	Sinks []*LogSink
 }
 
 func (l *LogConfig) Equal(o *LogConfig) bool {
@@ -7739,6 +8534,18 @@ func (l *LogConfig) Equal(o *LogConfig) bool {
 		return false
 	}
 
+	if l.Compress != o.Compress || l.MaxAge != o.MaxAge || l.MaxTotalSizeMB != o.MaxTotalSizeMB {
+		return false
+	}
+
+	if l.Timestamps != o.Timestamps {
+		return false
+	}
+
+	if !slices.EqualFunc(l.Sinks, o.Sinks, func(a, b *LogSink) bool { return a.Equal(b) }) {
+		return false
+	}
+
 	return true
 }
 
@@ -7747,9 +8554,14 @@ func (l *LogConfig) Copy() *LogConfig {
 		return nil
 	}
 	return &LogConfig{
-		MaxFiles:      l.MaxFiles,
-		MaxFileSizeMB: l.MaxFileSizeMB,
-		Disabled:      l.Disabled,
+		MaxFiles:       l.MaxFiles,
+		MaxFileSizeMB:  l.MaxFileSizeMB,
+		Disabled:       l.Disabled,
+		Compress:       l.Compress,
+		MaxAge:         l.MaxAge,
+		MaxTotalSizeMB: l.MaxTotalSizeMB,
+		Timestamps:     l.Timestamps,
+		Sinks:          helper.CopySlice(l.Sinks),
 	}
 }
 
@@ -7773,14 +8585,136 @@ func (l *LogConfig) Validate(disk *EphemeralDisk) error {
 	if l.MaxFileSizeMB < 1 {
 		mErr.Errors = append(mErr.Errors, fmt.Errorf("minimum file size is 1MB; got %d", l.MaxFileSizeMB))
 	}
+	switch l.Compress {
+	case "", LogCompressGzip, LogCompressZstd:
+	default:
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("compress must be %q or %q; got %q",
+			LogCompressGzip, LogCompressZstd, l.Compress))
+	}
+	if l.MaxAge < 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("max age must not be negative; got %v", l.MaxAge))
+	}
+	if l.MaxTotalSizeMB < 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("max total size must not be negative; got %d", l.MaxTotalSizeMB))
+	} else if l.MaxTotalSizeMB > 0 && l.MaxTotalSizeMB < l.MaxFileSizeMB {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("max total size (%d MB) must be at least the max file size (%d MB)",
+			l.MaxTotalSizeMB, l.MaxFileSizeMB))
+	}
 	if disk != nil {
 		logUsage := (l.MaxFiles * l.MaxFileSizeMB)
+		if l.MaxTotalSizeMB > 0 {
+			logUsage = min(logUsage, l.MaxTotalSizeMB)
+		}
 		if disk.SizeMB <= logUsage {
 			mErr.Errors = append(mErr.Errors,
 				fmt.Errorf("log storage (%d MB) must be less than requested disk capacity (%d MB)",
 					logUsage, disk.SizeMB))
 		}
 	}
+	for i, sink := range l.Sinks {
+		if err := sink.Validate(); err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("sink %d: %v", i+1, err))
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+const (
+	LogCompressGzip = "gzip"
+	LogCompressZstd = "zstd"
+)
+
+const (
+	LogSinkTypeSyslog = "syslog"
+	LogSinkTypeHTTP   = "http"
+	LogSinkTypeFluent = "fluent"
+
+	LogSinkDropOldest = "oldest"
+	LogSinkDropNewest = "newest"
+)
+
+// LogSink is an external destination the logs of a task are shipped to by
+// logmon.
+type LogSink struct {
+	// Type is the protocol used to ship the logs: syslog, http or fluent
+	Type string
+
+	// Address is the URL of the destination
+	Address string
+
+	// BufferSize is the number of log lines buffered while they can't be
+	// shipped
+	BufferSize int
+
+	// RetryAttempts is the number of times shipping a batch of log lines is
+	// retried before it's dropped
+	RetryAttempts int
+
+	// RetryInterval is the time to wait between attempts
+	RetryInterval time.Duration
+
+	// DropPolicy is which log lines are dropped when the buffer is full,
+	// either the oldest or the newest
+	DropPolicy string
+}
+
+func (s *LogSink) Equal(o *LogSink) bool {
+	if s == nil || o == nil {
+		return s == o
+	}
+	return *s == *o
+}
+
+func (s *LogSink) Copy() *LogSink {
+	if s == nil {
+		return nil
+	}
+	ns := *s
+	return &ns
+}
+
+// Validate returns an error if the sink type is unknown or its address
+// doesn't match the type.
+func (s *LogSink) Validate() error {
+	var mErr multierror.Error
+
+	u, err := url.Parse(s.Address)
+	if err != nil || s.Address == "" {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid address %q", s.Address))
+		u = &url.URL{}
+	}
+
+	var schemes []string
+	switch s.Type {
+	case LogSinkTypeSyslog:
+		schemes = []string{"unix", "udp", "tcp"}
+	case LogSinkTypeHTTP:
+		schemes = []string{"http", "https"}
+	case LogSinkTypeFluent:
+		schemes = []string{"unix", "tcp"}
+	default:
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("unknown sink type %q", s.Type))
+	}
+	if schemes != nil && u.Scheme != "" && !slices.Contains(schemes, u.Scheme) {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("%s sink address must use one of the schemes %v; got %q",
+			s.Type, schemes, u.Scheme))
+	}
+
+	if s.BufferSize < 1 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("minimum buffer size is 1; got %d", s.BufferSize))
+	}
+	if s.RetryAttempts < 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("retry attempts must not be negative; got %d", s.RetryAttempts))
+	}
+	if s.RetryInterval < 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("retry interval must not be negative; got %v", s.RetryInterval))
+	}
+	switch s.DropPolicy {
+	case LogSinkDropOldest, LogSinkDropNewest:
+	default:
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("drop policy must be %q or %q; got %q",
+			LogSinkDropOldest, LogSinkDropNewest, s.DropPolicy))
+	}
 	return mErr.ErrorOrNil()
 }
 
@@ -7835,6 +8769,10 @@ type Task struct {
 
 	Lifecycle *TaskLifecycleConfig
 
+	// DependsOn is the set of tasks of the group that must reach a given
+	// state before this task is started.
+	DependsOn []*TaskDependency
+
 	// Meta is used to associate arbitrary metadata with this
 	// task. This is opaque to Nomad.
 	Meta map[string]string
@@ -7843,6 +8781,10 @@ type Task struct {
 	// killed and killing it.
 	KillTimeout time.Duration
 
+	// MaxRunDuration is the maximum duration the task may run for before
+	// being killed. Hitting it is treated as a failure by the restart policy.
+	MaxRunDuration time.Duration
+
 	// LogConfig provides configuration for log rotation
 	LogConfig *LogConfig
 
@@ -7981,6 +8923,7 @@ func (t *Task) Copy() *Task {
 	nt.Meta = maps.Clone(nt.Meta)
 	nt.DispatchPayload = nt.DispatchPayload.Copy()
 	nt.Lifecycle = nt.Lifecycle.Copy()
+	nt.DependsOn = CopySliceTaskDependencies(nt.DependsOn)
 	nt.Identity = nt.Identity.Copy()
 	nt.Identities = helper.CopySlice(nt.Identities)
 	nt.Actions = helper.CopySlice(nt.Actions)
@@ -8035,6 +8978,13 @@ func (t *Task) Canonicalize(job *Job, tg *TaskGroup) {
 		t.VolumeMounts = nil
 	}
 
+	if len(t.DependsOn) == 0 {
+		t.DependsOn = nil
+	}
+	for _, d := range t.DependsOn {
+		d.Canonicalize()
+	}
+
 	for _, service := range t.Services {
 		service.Canonicalize(job.Name, tg.Name, t.Name, job.Namespace)
 	}
@@ -8133,6 +9083,9 @@ func (t *Task) Validate(jobType string, tg *TaskGroup) error {
 	if t.ShutdownDelay < 0 {
 		mErr.Errors = append(mErr.Errors, errors.New("ShutdownDelay must be a positive value"))
 	}
+	if t.MaxRunDuration < 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("MaxRunDuration must be a positive value"))
+	}
 
 	// Validate the resources.
 	if t.Resources == nil {
@@ -9209,6 +10162,14 @@ const (
 	// TaskClientReconnected indicates that the client running the task reconnected.
 	TaskClientReconnected = "Reconnected"
 
+	// TaskMaxRunDurationExceeded indicates that the task was killed because
+	// it ran for longer than its maximum run duration.
+	TaskMaxRunDurationExceeded = "Max Run Duration Exceeded"
+
+	// TaskDeadlineExceeded indicates that the task was killed because the
+	// deadline of its job passed.
+	TaskDeadlineExceeded = "Deadline Exceeded"
+
 	// TaskWaitingShuttingDownDelay indicates that the task is waiting for
 	// shutdown delay before being TaskKilled
 	TaskWaitingShuttingDownDelay = "Waiting for shutdown delay"
@@ -9433,6 +10394,10 @@ func (e *TaskEvent) PopulateEventDisplayMessage() {
 		desc = "Main tasks in the group died"
 	case TaskClientReconnected:
 		desc = "Client reconnected"
+	case TaskMaxRunDurationExceeded:
+		desc = "Task exceeded its maximum run duration"
+	case TaskDeadlineExceeded:
+		desc = "Job deadline exceeded"
 	default:
 		desc = e.Message
 	}
@@ -9565,9 +10530,12 @@ func (e *TaskEvent) SetKillReason(r string) *TaskEvent {
 	return e
 }
 
+// SetRestartDelay sets the delay before the task restarts, and the time at
+// which it restarts relative to the time of the event.
 func (e *TaskEvent) SetRestartDelay(delay time.Duration) *TaskEvent {
 	e.StartDelay = int64(delay)
 	e.Details["start_delay"] = fmt.Sprintf("%d", delay)
+	e.Details["restart_time"] = time.Unix(0, e.Time).Add(delay).UTC().Format(time.RFC3339)
 	return e
 }
 
@@ -10400,12 +11368,18 @@ const (
 	DeploymentStatusDescriptionRunning               = "Deployment is running"
 	DeploymentStatusDescriptionRunningNeedsPromotion = "Deployment is running but requires manual promotion"
 	DeploymentStatusDescriptionRunningAutoPromotion  = "Deployment is running pending automatic promotion"
+	DeploymentStatusDescriptionRunningRetainingBlue  = "Deployment is running and retaining the blue allocations"
+	DeploymentStatusDescriptionRunningPreDeploy      = "Deployment is running pre-deployment hooks"
+	DeploymentStatusDescriptionRunningPostDeploy     = "Deployment is running post-deployment hooks"
 	DeploymentStatusDescriptionPaused                = "Deployment is paused"
 	DeploymentStatusDescriptionSuccessful            = "Deployment completed successfully"
 	DeploymentStatusDescriptionStoppedJob            = "Cancelled because job is stopped"
 	DeploymentStatusDescriptionNewerJob              = "Cancelled due to newer version of job"
 	DeploymentStatusDescriptionFailedAllocations     = "Failed due to unhealthy allocations"
 	DeploymentStatusDescriptionProgressDeadline      = "Failed due to progress deadline"
+	DeploymentStatusDescriptionCanaryAnalysis        = "Failed due to canary analysis"
+	DeploymentStatusDescriptionPreDeployHook         = "Failed due to pre-deployment hook"
+	DeploymentStatusDescriptionPostDeployHook        = "Failed due to post-deployment hook"
 	DeploymentStatusDescriptionFailedByUser          = "Deployment marked as failed"
 
 	// used only in multiregion deployments
@@ -10587,6 +11561,20 @@ func (d *Deployment) RequiresPromotion() bool {
 	return false
 }
 
+// AwaitingPreDeploy determines if any task group of the deployment waits for
+// its pre-deployment hook before placing allocations.
+func (d *Deployment) AwaitingPreDeploy() bool {
+	if d == nil {
+		return false
+	}
+	for _, group := range d.TaskGroups {
+		if group.AwaitingPreDeploy() {
+			return true
+		}
+	}
+	return false
+}
+
 // HasAutoPromote determines if all taskgroups are marked auto_promote
 func (d *Deployment) HasAutoPromote() bool {
 	if d == nil || len(d.TaskGroups) == 0 || d.Status != DeploymentStatusRunning {
@@ -10657,6 +11645,40 @@ type DeploymentState struct {
 
 	// UnhealthyAllocs are allocations that have been marked as unhealthy.
 	UnhealthyAllocs int
+
+	// CanaryAnalysis is the result of the analysis of the canaries, if the
+	// task group's update strategy has an analysis.
+	CanaryAnalysis *CanaryAnalysisResult
+
+	// BlueGreen marks whether the task group is deployed blue/green, in which
+	// case its canaries are the green allocations.
+	BlueGreen bool
+
+	// BlueRetention is how long the blue allocations are kept running after
+	// the promotion. This value is set by the jobspec
+	// `update.blue_green.retention` field.
+	BlueRetention time.Duration
+
+	// RetainBlueUntil is the time until which the blue allocations are kept
+	// running. It is set when the deployment is promoted.
+	RetainBlueUntil time.Time
+
+	// PreDeploy and PostDeploy track the deployment hooks of the task group,
+	// if its update strategy has any.
+	PreDeploy  *DeploymentHookState
+	PostDeploy *DeploymentHookState
+}
+
+// AwaitingPreDeploy returns whether the deployment of the task group waits
+// for its pre-deployment hook before placing allocations.
+func (d *DeploymentState) AwaitingPreDeploy() bool {
+	return d != nil && d.PreDeploy.Incomplete()
+}
+
+// RetainingBlue returns whether the blue allocations of a promoted
+// blue/green deployment are still kept running at the given time.
+func (d *DeploymentState) RetainingBlue(now time.Time) bool {
+	return d != nil && d.BlueGreen && d.Promoted && now.Before(d.RetainBlueUntil)
 }
 
 func (d *DeploymentState) GoString() string {
@@ -10669,6 +11691,7 @@ func (d *DeploymentState) GoString() string {
 	base += fmt.Sprintf("\n\tUnhealthy: %d", d.UnhealthyAllocs)
 	base += fmt.Sprintf("\n\tAutoRevert: %v", d.AutoRevert)
 	base += fmt.Sprintf("\n\tAutoPromote: %v", d.AutoPromote)
+	base += fmt.Sprintf("\n\tBlueGreen: %v", d.BlueGreen)
 	return base
 }
 
@@ -10676,6 +11699,9 @@ func (d *DeploymentState) Copy() *DeploymentState {
 	c := &DeploymentState{}
 	*c = *d
 	c.PlacedCanaries = slices.Clone(d.PlacedCanaries)
+	c.CanaryAnalysis = d.CanaryAnalysis.Copy()
+	c.PreDeploy = d.PreDeploy.Copy()
+	c.PostDeploy = d.PostDeploy.Copy()
 	return c
 }
 
@@ -10684,12 +11710,22 @@ type DeploymentStatusUpdate struct {
 	// DeploymentID is the ID of the deployment to update
 	DeploymentID string
 
-	// Status is the new status of the deployment.
+	// Status is the new status of the deployment. The status and its
+	// description are left unchanged if empty.
 	Status string
 
 	// StatusDescription is the new status description of the deployment.
 	StatusDescription string
 
+	// CanaryAnalysis is the result of the canary analysis of each task group
+	// to update.
+	CanaryAnalysis map[string]*CanaryAnalysisResult
+
+	// PreDeploy and PostDeploy are the deployment hooks of each task group to
+	// update.
+	PreDeploy  map[string]*DeploymentHookState
+	PostDeploy map[string]*DeploymentHookState
+
 	// UpdatedAt is the time of the update, stored as UnixNano
 	UpdatedAt int64
 }
@@ -11241,6 +12277,12 @@ func (a *Allocation) ShouldReschedule(reschedulePolicy *ReschedulePolicy, failTi
 	}
 	switch a.ClientStatus {
 	case AllocClientStatusFailed:
+		if reschedulePolicy != nil && a.exitRuleAction(reschedulePolicy.ExitRules) == ExitRuleActionFail {
+			return false
+		}
+		if a.Job != nil && a.Job.DeadlineExceeded(failTime) {
+			return false
+		}
 		return a.RescheduleEligible(reschedulePolicy, failTime)
 	default:
 		return false
@@ -11282,7 +12324,7 @@ func (a *Allocation) ReschedulePolicy() *ReschedulePolicy {
 	if tg == nil {
 		return nil
 	}
-	return tg.ReschedulePolicy
+	return tg.EffectiveReschedulePolicy()
 }
 
 // MigrateStrategy returns the migrate strategy based on the task group
@@ -11311,6 +12353,17 @@ func (a *Allocation) NextRescheduleTime() (time.Time, bool) {
 		return time.Time{}, false
 	}
 
+	// The exit of a failed task may rule out rescheduling
+	if a.ClientStatus == AllocClientStatusFailed &&
+		a.exitRuleAction(reschedulePolicy.ExitRules) == ExitRuleActionFail {
+		return time.Time{}, false
+	}
+
+	// Allocations are no longer rescheduled past the deadline of their job
+	if a.Job != nil && a.Job.DeadlineExceeded(failTime) {
+		return time.Time{}, false
+	}
+
 	return a.nextRescheduleTime(failTime, reschedulePolicy)
 }
 
@@ -11887,6 +12940,26 @@ type AllocMetric struct {
 	// This is to prevent creating many failed allocations for a
 	// single task group.
 	CoalescedFailures int
+
+	// GangDesired is the number of allocations a gang scheduled task group
+	// needed to place together, which is its count minus its healthy running
+	// allocations. It is only set when the gang could not be placed in full.
+	GangDesired int
+
+	// GangPlaced is the number of allocations of a gang scheduled task group
+	// that could be placed before the rest of the gang failed. These
+	// placements are not part of the plan.
+	GangPlaced int
+
+	// NodeExplanations describes how each evaluated node was filtered,
+	// exhausted, or scored. It's only set when explanations are enabled,
+	// which is only done when explaining a job plan.
+	NodeExplanations []*NodeExplanation
+
+	// explanations tracks the explanation of each node by ID until they are
+	// populated into NodeExplanations. It's nil unless explanations are
+	// enabled.
+	explanations map[string]*NodeExplanation
 }
 
 func (a *AllocMetric) Copy() *AllocMetric {
@@ -11903,15 +12976,60 @@ func (a *AllocMetric) Copy() *AllocMetric {
 	na.QuotaExhausted = slices.Clone(na.QuotaExhausted)
 	na.Scores = maps.Clone(na.Scores)
 	na.ScoreMetaData = CopySliceNodeScoreMeta(na.ScoreMetaData)
+	na.NodeExplanations = helper.CopySlice(na.NodeExplanations)
+	na.explanations = nil
+	if a.explanations != nil {
+		na.explanations = make(map[string]*NodeExplanation, len(a.explanations))
+		for id, explanation := range a.explanations {
+			na.explanations[id] = explanation.Copy()
+		}
+	}
 	return na
 }
 
+// EnableExplanations makes the metrics record how each node is filtered,
+// exhausted, and scored.
+func (a *AllocMetric) EnableExplanations() {
+	if a.explanations == nil {
+		a.explanations = make(map[string]*NodeExplanation)
+	}
+}
+
+// ExplanationsEnabled returns whether the metrics record how each node is
+// filtered, exhausted, and scored.
+func (a *AllocMetric) ExplanationsEnabled() bool {
+	return a.explanations != nil
+}
+
+// explain returns the explanation of the node, or nil if explanations are
+// disabled.
+func (a *AllocMetric) explain(node *Node) *NodeExplanation {
+	if a.explanations == nil || node == nil {
+		return nil
+	}
+	explanation, ok := a.explanations[node.ID]
+	if !ok {
+		explanation = &NodeExplanation{
+			NodeID:   node.ID,
+			NodeName: node.Name,
+		}
+		a.explanations[node.ID] = explanation
+	}
+	return explanation
+}
+
 func (a *AllocMetric) EvaluateNode() {
 	a.NodesEvaluated += 1
 }
 
-func (a *AllocMetric) FilterNode(node *Node, constraint string) {
+// FilterNode records that the node was rejected by the named feasibility
+// checker because of the given constraint.
+func (a *AllocMetric) FilterNode(node *Node, checker, constraint string) {
 	a.NodesFiltered += 1
+	if explanation := a.explain(node); explanation != nil {
+		explanation.FilteredBy = checker
+		explanation.FilterReason = constraint
+	}
 	if node != nil && node.NodeClass != "" {
 		if a.ClassFiltered == nil {
 			a.ClassFiltered = make(map[string]int)
@@ -11928,6 +13046,9 @@ func (a *AllocMetric) FilterNode(node *Node, constraint string) {
 
 func (a *AllocMetric) ExhaustedNode(node *Node, dimension string) {
 	a.NodesExhausted += 1
+	if explanation := a.explain(node); explanation != nil {
+		explanation.ExhaustedDimension = dimension
+	}
 	if node != nil && node.NodeClass != "" {
 		if a.ClassExhausted == nil {
 			a.ClassExhausted = make(map[string]int)
@@ -11950,6 +13071,13 @@ func (a *AllocMetric) ExhaustQuota(dimensions []string) {
 	a.QuotaExhausted = append(a.QuotaExhausted, dimensions...)
 }
 
+// ExhaustGang records that a gang scheduled task group could only place
+// placed of its desired allocations, so none of them were placed.
+func (a *AllocMetric) ExhaustGang(placed, desired int) {
+	a.GangPlaced = placed
+	a.GangDesired = desired
+}
+
 // ExhaustResources updates the amount of resources exhausted for the
 // allocation because of the given task group.
 func (a *AllocMetric) ExhaustResources(tg *TaskGroup) {
@@ -11981,6 +13109,17 @@ func (a *AllocMetric) ExhaustResources(tg *TaskGroup) {
 
 // ScoreNode is used to gather top K scoring nodes in a heap
 func (a *AllocMetric) ScoreNode(node *Node, name string, score float64) {
+	if explanation := a.explain(node); explanation != nil {
+		if name == NormScorerName {
+			explanation.NormScore = score
+		} else {
+			if explanation.Scores == nil {
+				explanation.Scores = make(map[string]float64)
+			}
+			explanation.Scores[name] = score
+		}
+	}
+
 	// Create nodeScoreMeta lazily if its the first time or if its a new node
 	if a.nodeScoreMeta == nil || a.nodeScoreMeta.NodeID != node.ID {
 		a.nodeScoreMeta = &NodeScoreMeta{
@@ -12010,6 +13149,8 @@ func (a *AllocMetric) ScoreNode(node *Node, name string, score float64) {
 // The map is populated by popping elements from a heap of top K scores
 // maintained per scorer
 func (a *AllocMetric) PopulateScoreMetaData() {
+	a.populateExplanations()
+
 	if a.topScores == nil {
 		return
 	}
@@ -12023,6 +13164,33 @@ func (a *AllocMetric) PopulateScoreMetaData() {
 	}
 }
 
+// populateExplanations populates NodeExplanations from the explanations
+// recorded so far. Scored nodes are sorted first by normalized score,
+// followed by the other nodes sorted by name.
+func (a *AllocMetric) populateExplanations() {
+	if len(a.explanations) == 0 {
+		return
+	}
+
+	a.NodeExplanations = make([]*NodeExplanation, 0, len(a.explanations))
+	for _, explanation := range a.explanations {
+		a.NodeExplanations = append(a.NodeExplanations, explanation)
+	}
+	sort.Slice(a.NodeExplanations, func(i, j int) bool {
+		ei, ej := a.NodeExplanations[i], a.NodeExplanations[j]
+		if ei.Scored() != ej.Scored() {
+			return ei.Scored()
+		}
+		if ei.Scored() && ei.NormScore != ej.NormScore {
+			return ei.NormScore > ej.NormScore
+		}
+		if ei.NodeName != ej.NodeName {
+			return ei.NodeName < ej.NodeName
+		}
+		return ei.NodeID < ej.NodeID
+	})
+}
+
 // MaxNormScore returns the ScoreMetaData entry with the highest normalized
 // score.
 func (a *AllocMetric) MaxNormScore() *NodeScoreMeta {
@@ -12032,6 +13200,42 @@ func (a *AllocMetric) MaxNormScore() *NodeScoreMeta {
 	return a.ScoreMetaData[0]
 }
 
+// NodeExplanation describes how a node was evaluated by the scheduler for
+// a placement.
+type NodeExplanation struct {
+	NodeID   string
+	NodeName string
+
+	// FilteredBy is the name of the feasibility checker that rejected the
+	// node, and FilterReason the constraint the node didn't satisfy.
+	FilteredBy   string
+	FilterReason string
+
+	// ExhaustedDimension is the resource the node didn't have enough of.
+	ExhaustedDimension string
+
+	// Scores is the score given to the node by each scorer, and NormScore
+	// the final score used to select a node.
+	Scores    map[string]float64
+	NormScore float64
+}
+
+func (e *NodeExplanation) Copy() *NodeExplanation {
+	if e == nil {
+		return nil
+	}
+	ne := new(NodeExplanation)
+	*ne = *e
+	ne.Scores = maps.Clone(e.Scores)
+	return ne
+}
+
+// Scored returns whether the node was feasible and had enough resources to
+// be scored.
+func (e *NodeExplanation) Scored() bool {
+	return e.FilteredBy == "" && e.ExhaustedDimension == "" && len(e.Scores) > 0
+}
+
 // NodeScoreMeta captures scoring meta data derived from
 // different scoring factors.
 type NodeScoreMeta struct {
@@ -12142,6 +13346,11 @@ type AllocDeploymentStatus struct {
 	// been promoted will have this field set to false.
 	Canary bool
 
+	// Retained marks whether the allocation is part of the blue set of a
+	// promoted blue/green deployment, kept running until the retention of the
+	// deployment expires.
+	Retained bool
+
 	// ModifyIndex is the raft index in which the deployment status was last
 	// changed.
 	ModifyIndex uint64
@@ -12181,6 +13390,17 @@ func (a *AllocDeploymentStatus) IsCanary() bool {
 	return a.Canary
 }
 
+// CanaryServices returns whether the services of the allocation should be
+// registered with their canary tags and meta. This is the case for canaries
+// and for the retained blue allocations of a blue/green deployment.
+func (a *AllocDeploymentStatus) CanaryServices() bool {
+	if a == nil {
+		return false
+	}
+
+	return a.Canary || a.Retained
+}
+
 func (a *AllocDeploymentStatus) Copy() *AllocDeploymentStatus {
 	if a == nil {
 		return nil
@@ -12208,6 +13428,8 @@ func (a *AllocDeploymentStatus) Equal(o *AllocDeploymentStatus) bool {
 		return false
 	case a.Canary != o.Canary:
 		return false
+	case a.Retained != o.Retained:
+		return false
 	case a.ModifyIndex != o.ModifyIndex:
 		return false
 	}
@@ -12240,6 +13462,10 @@ const (
 	EvalTriggerScaling              = "job-scaling"
 	EvalTriggerMaxDisconnectTimeout = "max-disconnect-timeout"
 	EvalTriggerReconnect            = "reconnect"
+	EvalTriggerJobDependency        = "job-dependency"
+	EvalTriggerDeschedule           = "deschedule"
+	EvalTriggerIndexProgress        = "index-progress"
+	EvalTriggerQueuedChild          = "queued-child"
 )
 
 const (
@@ -12298,6 +13524,10 @@ const (
 	// active key
 	CoreJobVariablesRekey = "variables-rekey"
 
+	// CoreJobDeschedule is used to periodically migrate allocations away
+	// from the nodes where they make the cluster unbalanced.
+	CoreJobDeschedule = "deschedule"
+
 	// CoreJobForceGC is used to force garbage collection of all GCable objects.
 	CoreJobForceGC = "force-gc"
 )
@@ -12404,6 +13634,11 @@ type Evaluation struct {
 	// during the evaluation. This should not be set during normal operations.
 	AnnotatePlan bool
 
+	// ExplainPlan triggers the scheduler to record how each node was
+	// filtered and scored in the metrics of its placements. This should not
+	// be set during normal operations.
+	ExplainPlan bool
+
 	// QueuedAllocations is the number of unplaced allocations at the time the
 	// evaluation was processed. The map is keyed by Task Group names.
 	QueuedAllocations map[string]int
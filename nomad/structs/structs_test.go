@@ -5,6 +5,7 @@ package structs
 
 import (
 	"fmt"
+	"math"
 	"net"
 	"os"
 	"reflect"
@@ -409,6 +410,26 @@ func TestJob_Validate(t *testing.T) {
 				"Tagged version description must be under 1000 characters",
 			},
 		},
+		{
+			name: "deadline on service job",
+			job: &Job{
+				Type:     JobTypeService,
+				Deadline: time.Hour,
+			},
+			expErr: []string{
+				`Deadline is only supported for "batch" and "sysbatch" jobs`,
+			},
+		},
+		{
+			name: "negative deadline",
+			job: &Job{
+				Type:     JobTypeBatch,
+				Deadline: -time.Hour,
+			},
+			expErr: []string{
+				"Deadline must be a positive value",
+			},
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
@@ -908,6 +929,32 @@ func TestJob_IsPeriodicActive(t *testing.T) {
 	}
 }
 
+func TestJob_DeadlineTime(t *testing.T) {
+	ci.Parallel(t)
+
+	submitTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	job := &Job{
+		Type:       JobTypeBatch,
+		SubmitTime: submitTime.UnixNano(),
+	}
+	must.True(t, job.DeadlineTime().IsZero())
+	must.False(t, job.DeadlineExceeded(submitTime.Add(24*time.Hour)))
+
+	job.Deadline = 2 * time.Hour
+	must.Eq(t, submitTime.Add(2*time.Hour), job.DeadlineTime())
+	must.False(t, job.DeadlineExceeded(submitTime.Add(time.Hour)))
+	must.True(t, job.DeadlineExceeded(submitTime.Add(2*time.Hour)))
+
+	// The deadline is anchored on the first version of the job
+	job.FirstSubmitTime = job.SubmitTime
+	job.SubmitTime = submitTime.Add(time.Hour).UnixNano()
+	must.Eq(t, submitTime.Add(2*time.Hour), job.DeadlineTime())
+
+	// The deadline of a parameterized job applies to its dispatched children
+	job.ParameterizedJob = &ParameterizedJobConfig{}
+	must.True(t, job.DeadlineTime().IsZero())
+}
+
 func TestJob_SystemJob_Validate(t *testing.T) {
 	j := testJob()
 	j.Type = JobTypeSystem
@@ -1754,6 +1801,19 @@ func TestTaskGroup_Validate(t *testing.T) {
 			},
 			jobType: JobTypeService,
 		},
+		{
+			name: "gang scheduling for system job",
+			tg: &TaskGroup{
+				Name:          "group-a",
+				Gang:          true,
+				RestartPolicy: NewRestartPolicy(JobTypeSystem),
+				Tasks:         []*Task{{Name: "task-a"}},
+			},
+			expErr: []string{
+				"Gang scheduling can only be used with service or batch job types",
+			},
+			jobType: JobTypeSystem,
+		},
 	}
 
 	for _, tc := range tests {
@@ -3160,6 +3220,110 @@ func TestTask_Validate_LogConfig(t *testing.T) {
 	require.Error(t, err, "log storage")
 }
 
+func TestLogConfig_Validate_Retention(t *testing.T) {
+	ci.Parallel(t)
+
+	l := DefaultLogConfig()
+	l.Compress = LogCompressZstd
+	l.MaxAge = 24 * time.Hour
+	l.MaxTotalSizeMB = 50
+	must.NoError(t, l.Validate(&EphemeralDisk{SizeMB: 300}))
+
+	// The total size caps the log storage
+	must.NoError(t, l.Validate(&EphemeralDisk{SizeMB: 60}))
+
+	l.Compress = "bzip2"
+	l.MaxAge = -time.Second
+	l.MaxTotalSizeMB = 5
+	err := l.Validate(nil)
+	must.ErrorContains(t, err, `compress must be "gzip" or "zstd"; got "bzip2"`)
+	must.ErrorContains(t, err, "max age must not be negative")
+	must.ErrorContains(t, err, "max total size (5 MB) must be at least the max file size (10 MB)")
+}
+
+func TestLogSink_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	valid := func() *LogSink {
+		return &LogSink{
+			Type:          LogSinkTypeSyslog,
+			Address:       "udp://127.0.0.1:514",
+			BufferSize:    1024,
+			RetryAttempts: 3,
+			RetryInterval: time.Second,
+			DropPolicy:    LogSinkDropOldest,
+		}
+	}
+
+	cases := []struct {
+		name   string
+		modify func(*LogSink)
+		expErr string
+	}{
+		{
+			name:   "valid",
+			modify: func(*LogSink) {},
+		},
+		{
+			name: "valid http",
+			modify: func(s *LogSink) {
+				s.Type = LogSinkTypeHTTP
+				s.Address = "https://logs.example.com/ingest"
+			},
+		},
+		{
+			name: "valid fluent",
+			modify: func(s *LogSink) {
+				s.Type = LogSinkTypeFluent
+				s.Address = "unix:///var/run/fluent.sock"
+				s.DropPolicy = LogSinkDropNewest
+			},
+		},
+		{
+			name:   "unknown type",
+			modify: func(s *LogSink) { s.Type = "kafka" },
+			expErr: `unknown sink type "kafka"`,
+		},
+		{
+			name:   "scheme mismatch",
+			modify: func(s *LogSink) { s.Type = LogSinkTypeFluent },
+			expErr: "fluent sink address must use one of the schemes",
+		},
+		{
+			name:   "missing address",
+			modify: func(s *LogSink) { s.Address = "" },
+			expErr: "invalid address",
+		},
+		{
+			name:   "buffer size",
+			modify: func(s *LogSink) { s.BufferSize = 0 },
+			expErr: "minimum buffer size is 1",
+		},
+		{
+			name:   "retry attempts",
+			modify: func(s *LogSink) { s.RetryAttempts = -1 },
+			expErr: "retry attempts must not be negative",
+		},
+		{
+			name:   "drop policy",
+			modify: func(s *LogSink) { s.DropPolicy = "random" },
+			expErr: "drop policy must be",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			sink := valid()
+			tc.modify(sink)
+			err := sink.Validate()
+			if tc.expErr == "" {
+				must.NoError(t, err)
+			} else {
+				must.ErrorContains(t, err, tc.expErr)
+			}
+		})
+	}
+}
+
 func TestLogConfig_Equals(t *testing.T) {
 	ci.Parallel(t)
 
@@ -3187,6 +3351,13 @@ func TestLogConfig_Equals(t *testing.T) {
 		require.False(t, a.Equal(b))
 	})
 
+	t.Run("sinks", func(t *testing.T) {
+		a := &LogConfig{MaxFiles: 1, MaxFileSizeMB: 200}
+		b := &LogConfig{MaxFiles: 1, MaxFileSizeMB: 200, Sinks: []*LogSink{{Type: LogSinkTypeHTTP}}}
+		require.False(t, a.Equal(b))
+		require.True(t, b.Equal(b.Copy()))
+	})
+
 	t.Run("same", func(t *testing.T) {
 		a := &LogConfig{MaxFiles: 1, MaxFileSizeMB: 200}
 		b := &LogConfig{MaxFiles: 1, MaxFileSizeMB: 200}
@@ -3800,6 +3971,84 @@ func TestUpdateStrategy_Validate(t *testing.T) {
 	)
 }
 
+func TestUpdateStrategy_BlueGreen(t *testing.T) {
+	ci.Parallel(t)
+
+	u := DefaultUpdateStrategy.Copy()
+	u.BlueGreen = &BlueGreenStrategy{Retention: time.Hour}
+	must.NoError(t, u.Validate())
+	must.Eq(t, 5, u.DesiredCanaries(5))
+
+	u.Canary = 1
+	u.AutoPromote = true
+	u.BlueGreen.Retention = -1
+	requireErrors(t, u.Validate(),
+		"Blue/green deployments can not set a Canary count",
+		"Blue/green deployments can not be auto promoted",
+		"Blue/green retention can not be less than zero",
+	)
+
+	u.BlueGreen = nil
+	must.Eq(t, 1, u.DesiredCanaries(5))
+}
+
+func TestUpdateStrategy_DeploymentHooks(t *testing.T) {
+	ci.Parallel(t)
+
+	u := DefaultUpdateStrategy.Copy()
+	u.PreDeploy = &DeploymentHook{Job: "migrate", Meta: map[string]string{"step": "pre"}}
+	u.PostDeploy = &DeploymentHook{}
+	requireErrors(t, u.Validate(),
+		`Deployment hook "post_deploy" must specify a job or a group`,
+	)
+	u.PostDeploy = &DeploymentHook{Job: "smoke", Group: "smoke"}
+	requireErrors(t, u.Validate(),
+		`Deployment hook "post_deploy" can not specify both a job and a group`,
+	)
+	u.PostDeploy = &DeploymentHook{}
+
+	c := u.Copy()
+	c.PreDeploy.Meta["step"] = "post"
+	must.Eq(t, "pre", u.PreDeploy.Meta["step"])
+
+	state := NewDeploymentHookState(u.PreDeploy)
+	must.True(t, state.Pending())
+	must.True(t, (&DeploymentState{PreDeploy: state}).AwaitingPreDeploy())
+
+	state.Status = DeploymentHookStatusComplete
+	must.False(t, state.Incomplete())
+	must.False(t, (&DeploymentState{PreDeploy: state}).AwaitingPreDeploy())
+}
+
+func TestJob_DeploymentHookGroups(t *testing.T) {
+	ci.Parallel(t)
+
+	job := testJob()
+	hookGroup := job.TaskGroups[0].Copy()
+	hookGroup.Name = "migrate"
+	job.TaskGroups = append(job.TaskGroups, hookGroup)
+
+	// The hook task group inherits the hook of the job's update strategy,
+	// which is ignored
+	for _, tg := range job.TaskGroups {
+		tg.Update = DefaultUpdateStrategy.Copy()
+		tg.Update.PreDeploy = &DeploymentHook{Group: "migrate"}
+	}
+	must.True(t, job.IsDeploymentHookGroup("migrate"))
+	must.False(t, job.IsDeploymentHookGroup(job.TaskGroups[0].Name))
+	must.NoError(t, job.TaskGroups[0].Validate(job))
+	must.NoError(t, hookGroup.Validate(job))
+
+	job.TaskGroups[0].Update.PreDeploy = &DeploymentHook{Group: "missing"}
+	must.False(t, job.IsDeploymentHookGroup("migrate"))
+	requireErrors(t, job.TaskGroups[0].Validate(job),
+		`Deployment hook group "missing" not found`,
+	)
+	requireErrors(t, hookGroup.Validate(job),
+		`Deployment hook group "migrate" can not be the group itself`,
+	)
+}
+
 func TestResource_NetIndex(t *testing.T) {
 	ci.Parallel(t)
 
@@ -4595,6 +4844,103 @@ func TestPeriodicConfig_DST(t *testing.T) {
 	require.Equal(e2, n2.UTC())
 }
 
+func TestPeriodicConfig_MissedLaunches(t *testing.T) {
+	ci.Parallel(t)
+
+	last := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	now := last.Add(5*time.Hour + 30*time.Minute)
+	hour := func(h int) time.Time { return last.Add(time.Duration(h) * time.Hour) }
+
+	testCases := []struct {
+		name     string
+		catchup  string
+		maxCount int
+		maxAge   time.Duration
+		exp      []time.Time
+	}{
+		{
+			name: "default",
+			exp:  []time.Time{hour(5)},
+		},
+		{
+			name:    "none",
+			catchup: PeriodicCatchupNone,
+		},
+		{
+			name:    "latest",
+			catchup: PeriodicCatchupLatest,
+			exp:     []time.Time{hour(5)},
+		},
+		{
+			name:    "all",
+			catchup: PeriodicCatchupAll,
+			exp:     []time.Time{hour(1), hour(2), hour(3), hour(4), hour(5)},
+		},
+		{
+			name:     "all max count",
+			catchup:  PeriodicCatchupAll,
+			maxCount: 2,
+			exp:      []time.Time{hour(4), hour(5)},
+		},
+		{
+			name:    "all max age",
+			catchup: PeriodicCatchupAll,
+			maxAge:  3 * time.Hour,
+			exp:     []time.Time{hour(3), hour(4), hour(5)},
+		},
+		{
+			name:    "latest max age",
+			catchup: PeriodicCatchupLatest,
+			maxAge:  10 * time.Minute,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &PeriodicConfig{
+				Enabled:         true,
+				SpecType:        PeriodicSpecCron,
+				Spec:            "0 * * * *",
+				Catchup:         tc.catchup,
+				CatchupMaxCount: tc.maxCount,
+				CatchupMaxAge:   tc.maxAge,
+			}
+			must.NoError(t, p.Validate())
+
+			missed, err := p.MissedLaunches(last, now)
+			must.NoError(t, err)
+			must.Eq(t, tc.exp, missed)
+		})
+	}
+
+	p := &PeriodicConfig{
+		Enabled:         true,
+		SpecType:        PeriodicSpecCron,
+		Spec:            "0 * * * *",
+		Catchup:         "some",
+		CatchupMaxCount: -1,
+	}
+	err := p.Validate()
+	must.ErrorContains(t, err, "Unknown catch-up policy")
+	must.ErrorContains(t, err, "max count can not be less than zero")
+
+	// Only the dispatched launches are computed after a long time without
+	// launches, not every launch in between
+	p = &PeriodicConfig{
+		Enabled:  true,
+		SpecType: PeriodicSpecCron,
+		Spec:     "* * * * * * *",
+		Catchup:  PeriodicCatchupAll,
+	}
+	must.NoError(t, p.Validate())
+
+	missed, err := p.MissedLaunches(last.AddDate(-10, 0, 0), now.Add(500*time.Millisecond))
+	must.NoError(t, err)
+	must.Len(t, DefaultPeriodicCatchupMaxCount, missed)
+	must.Eq(t, now.Add(-9*time.Second), missed[0])
+	must.Eq(t, now, missed[len(missed)-1])
+}
+
 func TestTaskLifecycleConfig_Validate(t *testing.T) {
 	ci.Parallel(t)
 
@@ -4697,6 +5043,104 @@ func TestRestartPolicy_Validate(t *testing.T) {
 	if err := p.Validate(); err == nil || !strings.Contains(err.Error(), "Interval can not be less than") {
 		t.Fatalf("expect interval too small error, got: %v", err)
 	}
+
+	// Fails when the growing delays do not fit inside interval
+	p = &RestartPolicy{
+		Mode:          RestartPolicyModeDelay,
+		Attempts:      4,
+		Delay:         5 * time.Second,
+		DelayFunction: "exponential",
+		Interval:      time.Minute,
+	}
+	if err := p.Validate(); err == nil || !strings.Contains(err.Error(), "can't restart") {
+		t.Fatalf("expect restart interval error, got: %v", err)
+	}
+
+	// Passes when the delays are capped by the max delay
+	p.MaxDelay = 10 * time.Second
+	if err := p.Validate(); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// Bad delay function, max delay and jitter fail
+	p = &RestartPolicy{
+		Mode:          RestartPolicyModeDelay,
+		Attempts:      1,
+		Delay:         5 * time.Second,
+		DelayFunction: "linear",
+		MaxDelay:      time.Second,
+		Jitter:        pointer.Of(1.5),
+		Interval:      time.Minute,
+	}
+	err := p.Validate()
+	must.ErrorContains(t, err, "Invalid delay function")
+	must.ErrorContains(t, err, "Max Delay cannot be less than Delay")
+	must.ErrorContains(t, err, "Jitter must be between 0 and 1")
+}
+
+func TestRestartPolicy_NextDelay(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		delayFunction string
+		maxDelay      time.Duration
+		expected      []time.Duration
+	}{
+		{
+			delayFunction: "",
+			expected:      []time.Duration{5, 5, 5, 5},
+		},
+		{
+			delayFunction: "constant",
+			maxDelay:      time.Minute,
+			expected:      []time.Duration{5, 5, 5, 5},
+		},
+		{
+			delayFunction: "exponential",
+			expected:      []time.Duration{5, 10, 20, 40, 80},
+		},
+		{
+			delayFunction: "exponential",
+			maxDelay:      30 * time.Second,
+			expected:      []time.Duration{5, 10, 20, 30, 30},
+		},
+		{
+			delayFunction: "fibonacci",
+			expected:      []time.Duration{5, 5, 10, 15, 25, 40},
+		},
+		{
+			delayFunction: "fibonacci",
+			maxDelay:      20 * time.Second,
+			expected:      []time.Duration{5, 5, 10, 15, 20, 20},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%s %v", tc.delayFunction, tc.maxDelay), func(t *testing.T) {
+			p := &RestartPolicy{
+				Delay:         5 * time.Second,
+				DelayFunction: tc.delayFunction,
+				MaxDelay:      tc.maxDelay,
+				Interval:      time.Hour,
+			}
+			for i, exp := range tc.expected {
+				must.Eq(t, exp*time.Second, p.NextDelay(i+1), must.Sprintf("attempt %d", i+1))
+			}
+		})
+	}
+}
+
+func TestRestartPolicy_GetJitter(t *testing.T) {
+	ci.Parallel(t)
+
+	// Policies of jobs submitted without a jitter use the default
+	p := NewRestartPolicy(JobTypeService)
+	must.Nil(t, p.Jitter)
+	must.Eq(t, RestartPolicyDefaultJitter, p.GetJitter())
+
+	p.Jitter = pointer.Of(0.0)
+	must.Eq(t, 0.0, p.GetJitter())
+	must.Nil(t, NewRestartPolicy(JobTypeService).Jitter)
 }
 
 func TestReschedulePolicy_Validate(t *testing.T) {
@@ -6454,6 +6898,58 @@ func TestParameterizedJobConfig_Validate(t *testing.T) {
 	}
 }
 
+func TestParameterizedJobConfig_Validate_Concurrency(t *testing.T) {
+	ci.Parallel(t)
+
+	d := &ParameterizedJobConfig{
+		Payload:       DispatchPayloadOptional,
+		Concurrency:   JobConcurrencyQueue,
+		MaxConcurrent: 2,
+	}
+	must.NoError(t, d.Validate())
+	must.Eq(t, 2, d.ConcurrencyLimit())
+
+	d.Concurrency = "some"
+	must.ErrorContains(t, d.Validate(), "Unknown concurrency policy")
+
+	d.Concurrency = ""
+	must.ErrorContains(t, d.Validate(), "requires a concurrency policy")
+
+	p := &PeriodicConfig{
+		Enabled:         true,
+		SpecType:        PeriodicSpecCron,
+		Spec:            "0 * * * *",
+		ProhibitOverlap: true,
+	}
+	must.Eq(t, JobConcurrencyForbid, p.ConcurrencyPolicy())
+	p.Concurrency = JobConcurrencyQueue
+	must.ErrorContains(t, p.Validate(), "conflicts with prohibit_overlap")
+}
+
+func TestParameterizedJobConfig_Validate_DispatchQueue(t *testing.T) {
+	ci.Parallel(t)
+
+	d := &ParameterizedJobConfig{
+		Payload:       DispatchPayloadOptional,
+		DispatchQueue: &DispatchQueueConfig{Rate: 10},
+	}
+	must.NoError(t, d.Validate())
+	must.Eq(t, JobConcurrencyQueue, d.ConcurrencyPolicy())
+	must.Eq(t, math.MaxInt, d.ConcurrencyLimit())
+
+	d.DispatchQueue.MaxRunning = 5
+	must.Eq(t, 5, d.ConcurrencyLimit())
+
+	d.Concurrency = JobConcurrencyReplace
+	d.MaxConcurrent = 2
+	d.DispatchQueue = &DispatchQueueConfig{Burst: -1}
+	err := d.Validate()
+	must.ErrorContains(t, err, "conflicts with concurrency policy")
+	must.ErrorContains(t, err, "conflicts with max concurrent")
+	must.ErrorContains(t, err, "burst can not be less than zero")
+	must.ErrorContains(t, err, "must set max running or rate")
+}
+
 func TestParameterizedJobConfig_Validate_NonBatch(t *testing.T) {
 	ci.Parallel(t)
 
@@ -7440,6 +7936,65 @@ func TestNodeResources_Merge(t *testing.T) {
 	}, res)
 }
 
+func TestAllocMetric_NodeExplanations(t *testing.T) {
+	ci.Parallel(t)
+
+	nodes := []*Node{
+		{ID: "1", Name: "filtered"},
+		{ID: "2", Name: "exhausted"},
+		{ID: "3", Name: "low"},
+		{ID: "4", Name: "high"},
+	}
+
+	// Explanations are only recorded once enabled
+	metrics := new(AllocMetric)
+	metrics.FilterNode(nodes[0], "constraints", "${attr.kernel.name} = linux")
+	metrics.PopulateScoreMetaData()
+	must.Nil(t, metrics.NodeExplanations)
+
+	metrics = new(AllocMetric)
+	metrics.EnableExplanations()
+	must.True(t, metrics.ExplanationsEnabled())
+
+	metrics.FilterNode(nodes[0], "constraints", "${attr.kernel.name} = linux")
+	metrics.ExhaustedNode(nodes[1], "memory")
+	metrics.ScoreNode(nodes[2], "binpack", 0.2)
+	metrics.ScoreNode(nodes[2], NormScorerName, 0.2)
+	metrics.ScoreNode(nodes[3], "binpack", 0.5)
+	metrics.ScoreNode(nodes[3], "job-anti-affinity", -0.5)
+	metrics.ScoreNode(nodes[3], NormScorerName, 0.6)
+	metrics.PopulateScoreMetaData()
+
+	must.Eq(t, []*NodeExplanation{
+		{
+			NodeID:    "4",
+			NodeName:  "high",
+			Scores:    map[string]float64{"binpack": 0.5, "job-anti-affinity": -0.5},
+			NormScore: 0.6,
+		},
+		{
+			NodeID:    "3",
+			NodeName:  "low",
+			Scores:    map[string]float64{"binpack": 0.2},
+			NormScore: 0.2,
+		},
+		{
+			NodeID:             "2",
+			NodeName:           "exhausted",
+			ExhaustedDimension: "memory",
+		},
+		{
+			NodeID:       "1",
+			NodeName:     "filtered",
+			FilteredBy:   "constraints",
+			FilterReason: "${attr.kernel.name} = linux",
+		},
+	}, metrics.NodeExplanations)
+
+	// Copies keep recording explanations
+	must.True(t, metrics.Copy().ExplanationsEnabled())
+}
+
 func TestAllocatedPortMapping_Equal(t *testing.T) {
 	ci.Parallel(t)
 
@@ -8308,3 +8863,45 @@ func TestTaskIdentity_Canonicalize(t *testing.T) {
 	must.True(t, task.Identities[1].Env)
 	must.False(t, task.Identities[1].File)
 }
+
+func TestAllocation_NextRescheduleTime_Deadline(t *testing.T) {
+	ci.Parallel(t)
+
+	now := time.Now().UTC()
+	job := testJob()
+	job.Type = JobTypeBatch
+	job.Periodic = nil
+	job.SubmitTime = now.Add(-time.Hour).UnixNano()
+	policy := &ReschedulePolicy{
+		Attempts:      1,
+		Interval:      time.Hour,
+		Delay:         5 * time.Second,
+		DelayFunction: "constant",
+	}
+	job.LookupTaskGroup("web").ReschedulePolicy = policy
+
+	alloc := &Allocation{
+		Job:          job,
+		TaskGroup:    "web",
+		ClientStatus: AllocClientStatusFailed,
+		TaskStates: map[string]*TaskState{
+			"web": {
+				State:      TaskStateDead,
+				Failed:     true,
+				FinishedAt: now,
+			},
+		},
+	}
+
+	// The deadline didn't pass when the allocation failed
+	job.Deadline = 2 * time.Hour
+	_, eligible := alloc.NextRescheduleTime()
+	must.True(t, eligible)
+	must.True(t, alloc.ShouldReschedule(policy, now))
+
+	// The deadline passed when the allocation failed
+	job.Deadline = 30 * time.Minute
+	_, eligible = alloc.NextRescheduleTime()
+	must.False(t, eligible)
+	must.False(t, alloc.ShouldReschedule(policy, now))
+}
@@ -0,0 +1,200 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+const (
+	// TaskDependencyConditionStarted is satisfied once the upstream task has
+	// started.
+	TaskDependencyConditionStarted = "started"
+
+	// TaskDependencyConditionHealthy is satisfied once the upstream task is
+	// running and all the Nomad checks of its services are passing.
+	TaskDependencyConditionHealthy = "healthy"
+
+	// TaskDependencyConditionCompleted is satisfied once the upstream task
+	// has exited successfully.
+	TaskDependencyConditionCompleted = "completed"
+)
+
+// TaskDependency is used to prevent a task from starting until another task
+// of the same group reaches a given state.
+type TaskDependency struct {
+	// Task is the name of the upstream task.
+	Task string
+
+	// Condition is the state the upstream task must reach for the dependency
+	// to be satisfied.
+	Condition string
+}
+
+// Copy returns a copy of the dependency.
+func (d *TaskDependency) Copy() *TaskDependency {
+	if d == nil {
+		return nil
+	}
+	nd := new(TaskDependency)
+	*nd = *d
+	return nd
+}
+
+// DiffID fulfills the DiffableWithID interface.
+func (d *TaskDependency) DiffID() string {
+	return d.Task
+}
+
+// Canonicalize sets the default condition.
+func (d *TaskDependency) Canonicalize() {
+	if d.Condition == "" {
+		d.Condition = TaskDependencyConditionStarted
+	}
+}
+
+// Validate checks the dependency for reasonable configuration.
+func (d *TaskDependency) Validate() error {
+	var mErr *multierror.Error
+
+	if d.Task == "" {
+		mErr = multierror.Append(mErr, errors.New("Missing upstream task name"))
+	}
+
+	switch d.Condition {
+	case TaskDependencyConditionStarted, TaskDependencyConditionHealthy, TaskDependencyConditionCompleted:
+	default:
+		mErr = multierror.Append(mErr, fmt.Errorf("Invalid condition %q", d.Condition))
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// CopySliceTaskDependencies returns a copy of the given dependencies.
+func CopySliceTaskDependencies(s []*TaskDependency) []*TaskDependency {
+	l := len(s)
+	if l == 0 {
+		return nil
+	}
+
+	c := make([]*TaskDependency, l)
+	for i, v := range s {
+		c[i] = v.Copy()
+	}
+	return c
+}
+
+// taskStageOrder returns the order in which the lifecycle stage of the task
+// starts within an allocation.
+func taskStageOrder(t *Task) int {
+	switch {
+	case t.IsPrestart():
+		return 0
+	case t.IsPoststart():
+		return 2
+	case t.IsPoststop():
+		return 3
+	}
+	return 1
+}
+
+// validateTaskDependencies validates the dependencies between the tasks of
+// the group. A task may only depend on a task of the same group that starts
+// in the same lifecycle stage or an earlier one, and dependencies can't form
+// a cycle.
+func (tg *TaskGroup) validateTaskDependencies() error {
+	var mErr *multierror.Error
+
+	tasks := make(map[string]*Task, len(tg.Tasks))
+	for _, task := range tg.Tasks {
+		tasks[task.Name] = task
+	}
+
+	hasDeps := false
+	for _, task := range tg.Tasks {
+		upstreams := make(map[string]int, len(task.DependsOn))
+		for idx, d := range task.DependsOn {
+			hasDeps = true
+			if err := d.Validate(); err != nil {
+				mErr = multierror.Append(mErr, fmt.Errorf("Task %q dependency %d validation failed: %s", task.Name, idx+1, err))
+				continue
+			}
+			if existing, ok := upstreams[d.Task]; ok {
+				mErr = multierror.Append(mErr, fmt.Errorf("Task %q dependency %d redefines %q from dependency %d", task.Name, idx+1, d.Task, existing+1))
+				continue
+			}
+			upstreams[d.Task] = idx
+
+			upstream, ok := tasks[d.Task]
+			switch {
+			case !ok:
+				mErr = multierror.Append(mErr, fmt.Errorf("Task %q depends on unknown task %q", task.Name, d.Task))
+			case upstream == task:
+				mErr = multierror.Append(mErr, fmt.Errorf("Task %q depends on itself", task.Name))
+			case taskStageOrder(upstream) > taskStageOrder(task):
+				mErr = multierror.Append(mErr, fmt.Errorf("Task %q can't depend on task %q which starts in a later lifecycle stage", task.Name, d.Task))
+			}
+		}
+	}
+
+	if hasDeps && mErr == nil {
+		if cycle := tg.taskDependencyCycle(); cycle != nil {
+			mErr = multierror.Append(mErr, fmt.Errorf("Task dependencies form a cycle: %v", cycle))
+		}
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// taskDependencyCycle returns the names of the tasks of a dependency cycle of
+// the group, or nil if there is none.
+func (tg *TaskGroup) taskDependencyCycle() []string {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	deps := make(map[string][]string, len(tg.Tasks))
+	for _, task := range tg.Tasks {
+		for _, d := range task.DependsOn {
+			deps[task.Name] = append(deps[task.Name], d.Task)
+		}
+	}
+
+	marks := make(map[string]int, len(tg.Tasks))
+	var path []string
+	var visit func(name string) []string
+	visit = func(name string) []string {
+		switch marks[name] {
+		case visiting:
+			start := slices.Index(path, name)
+			return append(slices.Clone(path[start:]), name)
+		case visited:
+			return nil
+		}
+
+		marks[name] = visiting
+		path = append(path, name)
+		for _, upstream := range deps[name] {
+			if cycle := visit(upstream); cycle != nil {
+				return cycle
+			}
+		}
+		path = path[:len(path)-1]
+		marks[name] = visited
+		return nil
+	}
+
+	for _, task := range tg.Tasks {
+		if cycle := visit(task.Name); cycle != nil {
+			return cycle
+		}
+	}
+	return nil
+}
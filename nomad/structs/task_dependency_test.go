@@ -0,0 +1,100 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/shoenig/test/must"
+)
+
+func TestTaskGroup_Validate_TaskDependencies(t *testing.T) {
+	ci.Parallel(t)
+
+	newTask := func(name, hook string, deps ...*TaskDependency) *Task {
+		task := &Task{Name: name, DependsOn: deps}
+		if hook != "" {
+			task.Lifecycle = &TaskLifecycleConfig{Hook: hook}
+		}
+		return task
+	}
+	dep := func(task, condition string) *TaskDependency {
+		return &TaskDependency{Task: task, Condition: condition}
+	}
+
+	testCases := []struct {
+		name   string
+		tasks  []*Task
+		expErr string
+	}{
+		{
+			name: "valid",
+			tasks: []*Task{
+				newTask("init", TaskLifecycleHookPrestart),
+				newTask("db", ""),
+				newTask("api", "", dep("db", TaskDependencyConditionHealthy), dep("init", TaskDependencyConditionCompleted)),
+			},
+		},
+		{
+			name: "invalid condition",
+			tasks: []*Task{
+				newTask("db", ""),
+				newTask("api", "", dep("db", "ready")),
+			},
+			expErr: `Invalid condition "ready"`,
+		},
+		{
+			name: "unknown task",
+			tasks: []*Task{
+				newTask("api", "", dep("db", TaskDependencyConditionStarted)),
+			},
+			expErr: `Task "api" depends on unknown task "db"`,
+		},
+		{
+			name: "self dependency",
+			tasks: []*Task{
+				newTask("api", "", dep("api", TaskDependencyConditionStarted)),
+			},
+			expErr: `Task "api" depends on itself`,
+		},
+		{
+			name: "duplicate dependency",
+			tasks: []*Task{
+				newTask("db", ""),
+				newTask("api", "", dep("db", TaskDependencyConditionStarted), dep("db", TaskDependencyConditionHealthy)),
+			},
+			expErr: `redefines "db" from dependency 1`,
+		},
+		{
+			name: "later lifecycle stage",
+			tasks: []*Task{
+				newTask("api", ""),
+				newTask("init", TaskLifecycleHookPrestart, dep("api", TaskDependencyConditionStarted)),
+			},
+			expErr: `which starts in a later lifecycle stage`,
+		},
+		{
+			name: "cycle",
+			tasks: []*Task{
+				newTask("a", "", dep("c", TaskDependencyConditionStarted)),
+				newTask("b", "", dep("a", TaskDependencyConditionStarted)),
+				newTask("c", "", dep("b", TaskDependencyConditionStarted)),
+			},
+			expErr: "Task dependencies form a cycle: [a c b a]",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tg := &TaskGroup{Name: "web", Tasks: tc.tasks}
+			err := tg.validateTaskDependencies()
+			if tc.expErr == "" {
+				must.NoError(t, err)
+			} else {
+				must.ErrorContains(t, err, tc.expErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// TopologySpread is a hard constraint on how the allocations of a task group
+// are distributed across the values of a node attribute, called topology
+// domains. A node is not feasible if placing an allocation on it would make
+// the difference between the number of allocations in its domain and in the
+// domain with the fewest allocations greater than MaxSkew.
+//
+// Several topology spreads are nested in the order they are declared, job
+// level spreads first: the skew of each one is only computed between the
+// domains that share the values of the attributes of the previous ones.
+type TopologySpread struct {
+	// Attribute is the node attribute that defines the topology domains.
+	Attribute string
+
+	// MaxSkew is the maximum difference allowed between the number of
+	// allocations of the task group in any two domains.
+	MaxSkew int
+}
+
+// Copy returns a copy of the topology spread.
+func (t *TopologySpread) Copy() *TopologySpread {
+	if t == nil {
+		return nil
+	}
+	nt := new(TopologySpread)
+	*nt = *t
+	return nt
+}
+
+// Equal returns true if both topology spreads are the same.
+func (t *TopologySpread) Equal(o *TopologySpread) bool {
+	if t == nil || o == nil {
+		return t == o
+	}
+	return t.Attribute == o.Attribute && t.MaxSkew == o.MaxSkew
+}
+
+func (t *TopologySpread) String() string {
+	return fmt.Sprintf("%s max_skew=%d", t.Attribute, t.MaxSkew)
+}
+
+// Validate checks the topology spread for reasonable configuration.
+func (t *TopologySpread) Validate() error {
+	var mErr *multierror.Error
+	if t.Attribute == "" {
+		mErr = multierror.Append(mErr, errors.New("Missing topology spread attribute"))
+	}
+	if t.MaxSkew < 1 {
+		mErr = multierror.Append(mErr, fmt.Errorf("Topology spread max_skew must be at least 1; got %d", t.MaxSkew))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// CopySliceTopologySpreads returns a copy of the given topology spreads.
+func CopySliceTopologySpreads(s []*TopologySpread) []*TopologySpread {
+	l := len(s)
+	if l == 0 {
+		return nil
+	}
+
+	c := make([]*TopologySpread, l)
+	for i, v := range s {
+		c[i] = v.Copy()
+	}
+	return c
+}
+
+// validateTopologySpreads validates a list of topology spreads, returning an
+// error for each invalid or repeated one.
+func validateTopologySpreads(spreads []*TopologySpread) []error {
+	var errs []error
+	seen := make(map[string]struct{}, len(spreads))
+	for idx, spread := range spreads {
+		if err := spread.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("Topology spread %d validation failed: %s", idx+1, err))
+		}
+		if _, ok := seen[spread.Attribute]; ok {
+			errs = append(errs, fmt.Errorf("Topology spread attribute %q already defined", spread.Attribute))
+		}
+		seen[spread.Attribute] = struct{}{}
+	}
+	return errs
+}
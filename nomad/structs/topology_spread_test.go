@@ -0,0 +1,67 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/shoenig/test/must"
+)
+
+func TestTopologySpread_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name   string
+		spread *TopologySpread
+		expErr string
+	}{
+		{
+			name:   "valid",
+			spread: &TopologySpread{Attribute: "${meta.rack}", MaxSkew: 1},
+		},
+		{
+			name:   "missing attribute",
+			spread: &TopologySpread{MaxSkew: 1},
+			expErr: "Missing topology spread attribute",
+		},
+		{
+			name:   "zero max skew",
+			spread: &TopologySpread{Attribute: "${meta.rack}"},
+			expErr: "max_skew must be at least 1",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.spread.Validate()
+			if tc.expErr == "" {
+				must.NoError(t, err)
+			} else {
+				must.ErrorContains(t, err, tc.expErr)
+			}
+		})
+	}
+}
+
+func TestJob_Validate_TopologySpread(t *testing.T) {
+	ci.Parallel(t)
+
+	job := testJob()
+	job.TopologySpreads = []*TopologySpread{
+		{Attribute: "${node.datacenter}", MaxSkew: 1},
+	}
+	job.TaskGroups[0].TopologySpreads = []*TopologySpread{
+		{Attribute: "${meta.rack}", MaxSkew: 1},
+		{Attribute: "${meta.rack}", MaxSkew: 2},
+	}
+	must.ErrorContains(t, job.Validate(), `Topology spread attribute "${meta.rack}" already defined`)
+
+	job.TaskGroups[0].TopologySpreads = job.TaskGroups[0].TopologySpreads[:1]
+	must.NoError(t, job.Validate())
+
+	job.Type = JobTypeSystem
+	must.ErrorContains(t, job.Validate(), "System jobs may not have a topology_spread block")
+}
@@ -142,6 +142,10 @@ type EvalContext struct {
 	logger      log.Logger
 	metrics     *structs.AllocMetric
 	eligibility *EvalEligibility
+
+	// explain is set when the metrics of each placement record how each
+	// node was filtered and scored
+	explain bool
 }
 
 // NewEvalContext constructs a new EvalContext
@@ -178,6 +182,17 @@ func (e *EvalContext) SetState(s State) {
 
 func (e *EvalContext) Reset() {
 	e.metrics = new(structs.AllocMetric)
+	if e.explain {
+		e.metrics.EnableExplanations()
+	}
+}
+
+// ExplainPlacements makes the metrics of each placement record how each node
+// was filtered and scored. It's only used to explain job plans as it makes
+// placements more expensive.
+func (e *EvalContext) ExplainPlacements() {
+	e.explain = true
+	e.metrics.EnableExplanations()
 }
 
 func (e *EvalContext) ProposedAllocs(nodeID string) ([]*structs.Allocation, error) {
@@ -0,0 +1,424 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package scheduler
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+
+	log "github.com/hashicorp/go-hclog"
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// DescheduleReasonUtilization is used when an allocation is migrated
+	// away from a node above the utilization threshold.
+	DescheduleReasonUtilization = "node-utilization"
+
+	// DescheduleReasonAffinity is used when an allocation runs on a node that
+	// doesn't match its affinities while other nodes do.
+	DescheduleReasonAffinity = "affinity-drift"
+
+	// DescheduleReasonSpread is used when an allocation runs on a spread
+	// target that has more allocations than its desired share.
+	DescheduleReasonSpread = "spread-drift"
+
+	// DescheduleReasonFragmentation is used when an allocation is migrated
+	// away from a node below the fragmentation threshold so that allocations
+	// are consolidated on fewer nodes.
+	DescheduleReasonFragmentation = "binpack-fragmentation"
+)
+
+// DeschedulingCandidate is an allocation that the descheduler migrates to
+// rebalance the cluster.
+type DeschedulingCandidate struct {
+	Alloc  *structs.Allocation
+	Job    *structs.Job
+	Reason string
+}
+
+// Descheduler finds healthy service allocations that should be migrated to
+// rebalance the cluster. It only selects allocations of task groups that
+// haven't disabled descheduling in their migrate block, and never more of
+// them than the max_parallel of the migrate block allows, including the
+// allocations already migrating.
+type Descheduler struct {
+	logger log.Logger
+	state  State
+	config *structs.SchedulerConfiguration
+	ctx    *EvalContext
+
+	// nodes are the ready nodes, sorted by ID.
+	nodes []*structs.Node
+
+	// allocs are the non-terminal allocations of each ready node.
+	allocs map[string][]*structs.Allocation
+
+	// usage is the allocated resources of each ready node.
+	usage map[string]*structs.ComparableResources
+
+	// jobs caches the latest version of the jobs of the allocations.
+	jobs map[structs.NamespacedID]*structs.Job
+
+	// budgets is the number of allocations of each task group that may
+	// still be migrated.
+	budgets map[string]int
+
+	candidates []*DeschedulingCandidate
+	selected   map[string]struct{}
+}
+
+// NewDescheduler returns a Descheduler for the given state and scheduler
+// configuration.
+func NewDescheduler(logger log.Logger, state State, config *structs.SchedulerConfiguration) *Descheduler {
+	return &Descheduler{
+		logger:   logger.Named("descheduler"),
+		state:    state,
+		config:   config,
+		ctx:      NewEvalContext(nil, state, &structs.Plan{}, logger),
+		allocs:   make(map[string][]*structs.Allocation),
+		usage:    make(map[string]*structs.ComparableResources),
+		jobs:     make(map[structs.NamespacedID]*structs.Job),
+		budgets:  make(map[string]int),
+		selected: make(map[string]struct{}),
+	}
+}
+
+// Candidates returns the allocations to migrate, in the order they were
+// selected. Overloaded nodes are handled first, then affinity and spread
+// drift, then binpack fragmentation.
+func (d *Descheduler) Candidates() ([]*DeschedulingCandidate, error) {
+	if err := d.loadNodes(); err != nil {
+		return nil, err
+	}
+
+	d.findOverloaded()
+	d.findDrift()
+	if d.config.EffectiveSchedulerAlgorithm() == structs.SchedulerAlgorithmBinpack {
+		d.findFragmented()
+	}
+
+	return d.candidates, nil
+}
+
+// loadNodes loads the ready nodes with their allocations and usage.
+func (d *Descheduler) loadNodes() error {
+	ws := memdb.NewWatchSet()
+	iter, err := d.state.Nodes(ws)
+	if err != nil {
+		return err
+	}
+
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		node := raw.(*structs.Node)
+		if !node.Ready() {
+			continue
+		}
+
+		allocs, err := d.state.AllocsByNodeTerminal(ws, node.ID, false)
+		if err != nil {
+			return fmt.Errorf("failed to get allocations of node %s: %v", node.ID, err)
+		}
+
+		usage := new(structs.ComparableResources)
+		for _, alloc := range allocs {
+			usage.Add(alloc.AllocatedResources.Comparable())
+		}
+
+		d.nodes = append(d.nodes, node)
+		d.allocs[node.ID] = allocs
+		d.usage[node.ID] = usage
+	}
+
+	slices.SortFunc(d.nodes, func(a, b *structs.Node) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return nil
+}
+
+// findOverloaded selects the largest allocations of the nodes above the
+// utilization threshold until they would be back under it.
+func (d *Descheduler) findOverloaded() {
+	threshold := float64(d.config.Descheduler.EffectiveUtilizationThreshold()) / 100
+
+	for _, node := range d.nodes {
+		usage := d.usage[node.ID].Copy()
+		if nodeUtilization(node, usage) <= threshold {
+			continue
+		}
+
+		allocs := slices.Clone(d.allocs[node.ID])
+		slices.SortStableFunc(allocs, func(a, b *structs.Allocation) int {
+			return cmp.Compare(allocSize(node, b), allocSize(node, a))
+		})
+		for _, alloc := range allocs {
+			if nodeUtilization(node, usage) <= threshold {
+				break
+			}
+			if d.add(alloc, DescheduleReasonUtilization) {
+				usage.Subtract(alloc.AllocatedResources.Comparable())
+			}
+		}
+	}
+}
+
+// findDrift selects the allocations placed on nodes that no longer match
+// their affinities or that exceed their spread targets.
+func (d *Descheduler) findDrift() {
+	// Count the allocations of each task group per spread attribute value
+	type spreadKey struct {
+		tg        string
+		attribute string
+		value     string
+	}
+	spreadCounts := make(map[spreadKey][]*structs.Allocation)
+	var spreadKeys []spreadKey
+
+	for _, node := range d.nodes {
+		for _, alloc := range d.allocs[node.ID] {
+			job, tg := d.lookupTaskGroup(alloc)
+			if tg == nil {
+				continue
+			}
+
+			if d.affinityDrifted(job, tg, node) {
+				d.add(alloc, DescheduleReasonAffinity)
+			}
+
+			for _, spread := range append(slices.Clone(job.Spreads), tg.Spreads...) {
+				if len(spread.SpreadTarget) == 0 {
+					continue
+				}
+				value, ok := getProperty(node, spread.Attribute)
+				if !ok {
+					continue
+				}
+				key := spreadKey{taskGroupKey(alloc), spread.Attribute, value}
+				if _, ok := spreadCounts[key]; !ok {
+					spreadKeys = append(spreadKeys, key)
+				}
+				spreadCounts[key] = append(spreadCounts[key], alloc)
+			}
+		}
+	}
+
+	for _, key := range spreadKeys {
+		allocs := spreadCounts[key]
+		job, tg := d.lookupTaskGroup(allocs[0])
+		for _, spread := range append(slices.Clone(job.Spreads), tg.Spreads...) {
+			if spread.Attribute != key.attribute {
+				continue
+			}
+			desired, ok := spreadDesiredCount(spread, key.value, tg.Count)
+			if !ok {
+				continue
+			}
+			for _, alloc := range allocs[min(desired, len(allocs)):] {
+				d.add(alloc, DescheduleReasonSpread)
+			}
+		}
+	}
+}
+
+// findFragmented selects the allocations of the nodes below the
+// fragmentation threshold, starting with the least utilized ones.
+func (d *Descheduler) findFragmented() {
+	if d.config.Descheduler.FragmentationThreshold == 0 || len(d.nodes) < 2 {
+		return
+	}
+	threshold := float64(d.config.Descheduler.FragmentationThreshold) / 100
+
+	var nodes []*structs.Node
+	for _, node := range d.nodes {
+		if len(d.allocs[node.ID]) != 0 && nodeUtilization(node, d.usage[node.ID]) < threshold {
+			nodes = append(nodes, node)
+		}
+	}
+	slices.SortStableFunc(nodes, func(a, b *structs.Node) int {
+		return cmp.Compare(nodeUtilization(a, d.usage[a.ID]), nodeUtilization(b, d.usage[b.ID]))
+	})
+
+	for _, node := range nodes {
+		for _, alloc := range d.allocs[node.ID] {
+			d.add(alloc, DescheduleReasonFragmentation)
+		}
+	}
+}
+
+// add selects the allocation for the given reason if it can be migrated.
+func (d *Descheduler) add(alloc *structs.Allocation, reason string) bool {
+	if len(d.candidates) >= d.config.Descheduler.EffectiveMaxMigrations() {
+		return false
+	}
+	if _, ok := d.selected[alloc.ID]; ok {
+		return false
+	}
+
+	job, tg := d.lookupTaskGroup(alloc)
+	if tg == nil || tg.Migrate == nil || tg.Migrate.DisableDescheduling {
+		return false
+	}
+
+	// Only migrate healthy allocations of the current job version, so that
+	// the descheduler never interferes with deployments
+	if alloc.ClientStatus != structs.AllocClientStatusRunning ||
+		alloc.DesiredTransition.ShouldMigrate() ||
+		alloc.Job == nil || alloc.Job.Version != job.Version ||
+		(alloc.DeploymentStatus != nil && !alloc.DeploymentStatus.IsHealthy()) {
+		return false
+	}
+
+	budget, ok := d.budget(alloc, tg)
+	if !ok || budget <= 0 {
+		return false
+	}
+
+	d.budgets[taskGroupKey(alloc)] = budget - 1
+	d.selected[alloc.ID] = struct{}{}
+	d.candidates = append(d.candidates, &DeschedulingCandidate{
+		Alloc:  alloc,
+		Job:    job,
+		Reason: reason,
+	})
+	return true
+}
+
+// budget returns the number of allocations of the task group that may still
+// be migrated, which is the max_parallel of its migrate block minus the
+// allocations already migrating.
+func (d *Descheduler) budget(alloc *structs.Allocation, tg *structs.TaskGroup) (int, bool) {
+	key := taskGroupKey(alloc)
+	if budget, ok := d.budgets[key]; ok {
+		return budget, true
+	}
+
+	allocs, err := d.state.AllocsByJob(nil, alloc.Namespace, alloc.JobID, false)
+	if err != nil {
+		d.logger.Error("failed to get job's allocations", "job_id", alloc.JobID, "namespace", alloc.Namespace, "error", err)
+		return 0, false
+	}
+
+	budget := tg.Migrate.MaxParallel
+	for _, a := range allocs {
+		if a.TaskGroup == tg.Name && !a.TerminalStatus() && a.DesiredTransition.ShouldMigrate() {
+			budget--
+		}
+	}
+	d.budgets[key] = budget
+	return budget, true
+}
+
+// lookupTaskGroup returns the latest version of the job of the allocation
+// and its task group, or nil if the allocation can't be descheduled.
+func (d *Descheduler) lookupTaskGroup(alloc *structs.Allocation) (*structs.Job, *structs.TaskGroup) {
+	id := structs.NamespacedID{Namespace: alloc.Namespace, ID: alloc.JobID}
+	job, ok := d.jobs[id]
+	if !ok {
+		var err error
+		job, err = d.state.JobByID(nil, alloc.Namespace, alloc.JobID)
+		if err != nil {
+			d.logger.Error("failed to get job", "job_id", alloc.JobID, "namespace", alloc.Namespace, "error", err)
+		}
+		if job != nil && (job.Type != structs.JobTypeService || job.Stopped()) {
+			job = nil
+		}
+		d.jobs[id] = job
+	}
+	if job == nil {
+		return nil, nil
+	}
+
+	tg := job.LookupTaskGroup(alloc.TaskGroup)
+	if tg == nil {
+		return nil, nil
+	}
+	return job, tg
+}
+
+// affinityDrifted returns whether the node doesn't match the affinities of
+// the task group while another ready node does.
+func (d *Descheduler) affinityDrifted(job *structs.Job, tg *structs.TaskGroup, node *structs.Node) bool {
+	affinities := slices.Clone(job.Affinities)
+	affinities = append(affinities, tg.Affinities...)
+	for _, task := range tg.Tasks {
+		affinities = append(affinities, task.Affinities...)
+	}
+	if len(affinities) == 0 {
+		return false
+	}
+
+	score := func(node *structs.Node) float64 {
+		var total float64
+		for _, affinity := range affinities {
+			if matchesAffinity(d.ctx, affinity, node) {
+				total += float64(affinity.Weight)
+			}
+		}
+		return total
+	}
+
+	if score(node) > 0 {
+		return false
+	}
+	for _, other := range d.nodes {
+		if other.ID != node.ID && score(other) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// spreadDesiredCount returns the number of allocations desired for the
+// attribute value by the spread targets, if any.
+func spreadDesiredCount(spread *structs.Spread, value string, count int) (int, bool) {
+	sum := 0
+	for _, target := range spread.SpreadTarget {
+		if target.Value == value {
+			return int(math.Ceil(float64(target.Percent) * float64(count) / 100)), true
+		}
+		sum += int(target.Percent)
+	}
+
+	// Values without a target only get allocations if the targets don't
+	// already sum up to 100%
+	if sum >= 100 {
+		return 0, true
+	}
+	return 0, false
+}
+
+// nodeUtilization returns the highest of the CPU and memory utilization of
+// the node, between 0 and 1.
+func nodeUtilization(node *structs.Node, usage *structs.ComparableResources) float64 {
+	res := node.NodeResources.Comparable()
+	nodeCpu := float64(res.Flattened.Cpu.CpuShares)
+	nodeMem := float64(res.Flattened.Memory.MemoryMB)
+	if reserved := node.ReservedResources.Comparable(); reserved != nil {
+		nodeCpu -= float64(reserved.Flattened.Cpu.CpuShares)
+		nodeMem -= float64(reserved.Flattened.Memory.MemoryMB)
+	}
+	if nodeCpu <= 0 || nodeMem <= 0 {
+		return 0
+	}
+
+	return max(float64(usage.Flattened.Cpu.CpuShares)/nodeCpu,
+		float64(usage.Flattened.Memory.MemoryMB)/nodeMem)
+}
+
+// allocSize returns the share of the node used by the allocation.
+func allocSize(node *structs.Node, alloc *structs.Allocation) float64 {
+	usage := alloc.AllocatedResources.Comparable()
+	if usage == nil {
+		return 0
+	}
+	return nodeUtilization(node, usage)
+}
+
+// taskGroupKey returns a key unique to the task group of the allocation.
+func taskGroupKey(alloc *structs.Allocation) string {
+	return alloc.Namespace + "\x00" + alloc.JobID + "\x00" + alloc.TaskGroup
+}
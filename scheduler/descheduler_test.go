@@ -0,0 +1,175 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/pointer"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+// deschedulerAlloc returns a running allocation of the job on the node with
+// the given amount of memory.
+func deschedulerAlloc(job *structs.Job, node *structs.Node, memoryMB int64) *structs.Allocation {
+	alloc := mock.Alloc()
+	alloc.Job = job
+	alloc.JobID = job.ID
+	alloc.NodeID = node.ID
+	alloc.ClientStatus = structs.AllocClientStatusRunning
+	alloc.AllocatedResources.Tasks["web"].Memory.MemoryMB = memoryMB
+	return alloc
+}
+
+func TestDescheduler_Utilization(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name       string
+		setup      func(*structs.Job, []*structs.Allocation)
+		candidates int
+	}{
+		{
+			name:       "overloaded node",
+			candidates: 1,
+		},
+		{
+			name: "descheduling disabled",
+			setup: func(job *structs.Job, _ []*structs.Allocation) {
+				job.TaskGroups[0].Migrate.DisableDescheduling = true
+			},
+		},
+		{
+			name: "max parallel reached",
+			setup: func(job *structs.Job, allocs []*structs.Allocation) {
+				job.TaskGroups[0].Migrate.MaxParallel = 1
+				allocs[4].DesiredTransition.Migrate = pointer.Of(true)
+			},
+		},
+		{
+			name: "unhealthy allocations",
+			setup: func(_ *structs.Job, allocs []*structs.Allocation) {
+				for _, alloc := range allocs {
+					alloc.DeploymentStatus = &structs.AllocDeploymentStatus{Healthy: pointer.Of(false)}
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			store := state.TestStateStore(t)
+			nodes := []*structs.Node{mock.Node(), mock.Node()}
+			for i, node := range nodes {
+				must.NoError(t, store.UpsertNode(structs.MsgTypeTestSetup, uint64(100+i), node))
+			}
+
+			job := mock.Job()
+			job.TaskGroups[0].Migrate.MaxParallel = 10
+
+			// The first node is around 88% utilized, and migrating one of the
+			// large allocations is enough to bring it under 80%
+			var allocs []*structs.Allocation
+			for _, memoryMB := range []int64{500, 2000, 2000, 2000, 500} {
+				allocs = append(allocs, deschedulerAlloc(job, nodes[0], memoryMB))
+			}
+			allocs = append(allocs, deschedulerAlloc(job, nodes[1], 500))
+
+			if tc.setup != nil {
+				tc.setup(job, allocs)
+			}
+			must.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 200, nil, job))
+			must.NoError(t, store.UpsertAllocs(structs.MsgTypeTestSetup, 201, allocs))
+
+			config := &structs.SchedulerConfiguration{
+				Descheduler: structs.DeschedulerConfig{Enabled: true},
+			}
+			candidates, err := NewDescheduler(testlog.HCLogger(t), store, config).Candidates()
+			must.NoError(t, err)
+			must.Len(t, tc.candidates, candidates)
+
+			for _, candidate := range candidates {
+				must.Eq(t, DescheduleReasonUtilization, candidate.Reason)
+				must.Eq(t, nodes[0].ID, candidate.Alloc.NodeID)
+				must.Eq(t, 2000, candidate.Alloc.AllocatedResources.Tasks["web"].Memory.MemoryMB)
+			}
+		})
+	}
+}
+
+func TestDescheduler_AffinityDrift(t *testing.T) {
+	ci.Parallel(t)
+
+	store := state.TestStateStore(t)
+	nodes := []*structs.Node{mock.Node(), mock.Node()}
+	nodes[0].Meta["rack"] = "r1"
+	nodes[1].Meta["rack"] = "r2"
+	for i, node := range nodes {
+		must.NoError(t, store.UpsertNode(structs.MsgTypeTestSetup, uint64(100+i), node))
+	}
+
+	job := mock.Job()
+	job.Affinities = []*structs.Affinity{
+		{LTarget: "${meta.rack}", RTarget: "r2", Operand: "=", Weight: 50},
+	}
+	must.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 200, nil, job))
+
+	allocs := []*structs.Allocation{
+		deschedulerAlloc(job, nodes[0], 256),
+		deschedulerAlloc(job, nodes[1], 256),
+	}
+	must.NoError(t, store.UpsertAllocs(structs.MsgTypeTestSetup, 201, allocs))
+
+	config := &structs.SchedulerConfiguration{
+		Descheduler: structs.DeschedulerConfig{Enabled: true},
+	}
+	candidates, err := NewDescheduler(testlog.HCLogger(t), store, config).Candidates()
+	must.NoError(t, err)
+	must.Len(t, 1, candidates)
+	must.Eq(t, allocs[0].ID, candidates[0].Alloc.ID)
+	must.Eq(t, DescheduleReasonAffinity, candidates[0].Reason)
+}
+
+func TestDescheduler_Fragmentation(t *testing.T) {
+	ci.Parallel(t)
+
+	store := state.TestStateStore(t)
+	nodes := []*structs.Node{mock.Node(), mock.Node()}
+	for i, node := range nodes {
+		must.NoError(t, store.UpsertNode(structs.MsgTypeTestSetup, uint64(100+i), node))
+	}
+
+	job := mock.Job()
+	must.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 200, nil, job))
+
+	allocs := []*structs.Allocation{
+		deschedulerAlloc(job, nodes[0], 256),
+		deschedulerAlloc(job, nodes[1], 4000),
+	}
+	must.NoError(t, store.UpsertAllocs(structs.MsgTypeTestSetup, 201, allocs))
+
+	config := &structs.SchedulerConfiguration{
+		SchedulerAlgorithm: structs.SchedulerAlgorithmBinpack,
+		Descheduler: structs.DeschedulerConfig{
+			Enabled:                true,
+			FragmentationThreshold: 20,
+		},
+	}
+	candidates, err := NewDescheduler(testlog.HCLogger(t), store, config).Candidates()
+	must.NoError(t, err)
+	must.Len(t, 1, candidates)
+	must.Eq(t, allocs[0].ID, candidates[0].Alloc.ID)
+	must.Eq(t, DescheduleReasonFragmentation, candidates[0].Reason)
+
+	// Fragmentation is only considered with the binpack algorithm
+	config.SchedulerAlgorithm = structs.SchedulerAlgorithmSpread
+	candidates, err = NewDescheduler(testlog.HCLogger(t), store, config).Candidates()
+	must.NoError(t, err)
+	must.Len(t, 0, candidates)
+}
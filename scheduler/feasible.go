@@ -197,7 +197,7 @@ func (h *HostVolumeChecker) Feasible(candidate *structs.Node) bool {
 		return true
 	}
 
-	h.ctx.Metrics().FilterNode(candidate, FilterConstraintHostVolumes)
+	h.ctx.Metrics().FilterNode(candidate, "host-volumes", FilterConstraintHostVolumes)
 	return false
 }
 
@@ -399,7 +399,7 @@ func (c *CSIVolumeChecker) Feasible(n *structs.Node) bool {
 		return true
 	}
 
-	c.ctx.Metrics().FilterNode(n, failReason)
+	c.ctx.Metrics().FilterNode(n, "csi-volumes", failReason)
 	return false
 }
 
@@ -541,7 +541,7 @@ func (c *NetworkChecker) Feasible(option *structs.Node) bool {
 			}
 		}
 
-		c.ctx.Metrics().FilterNode(option, "missing network")
+		c.ctx.Metrics().FilterNode(option, "network", "missing network")
 		return false
 	}
 
@@ -559,7 +559,7 @@ func (c *NetworkChecker) hasHostNetworks(option *structs.Node) bool {
 		if port.HostNetwork != "" {
 			hostNetworkValue, hostNetworkOk := resolveTarget(port.HostNetwork, option)
 			if !hostNetworkOk {
-				c.ctx.Metrics().FilterNode(option, fmt.Sprintf("invalid host network %q template for port %q", port.HostNetwork, port.Label))
+				c.ctx.Metrics().FilterNode(option, "network", fmt.Sprintf("invalid host network %q template for port %q", port.HostNetwork, port.Label))
 				return false
 			}
 			found := false
@@ -570,7 +570,7 @@ func (c *NetworkChecker) hasHostNetworks(option *structs.Node) bool {
 				}
 			}
 			if !found {
-				c.ctx.Metrics().FilterNode(option, fmt.Sprintf("missing host network %q for port %q", hostNetworkValue, port.Label))
+				c.ctx.Metrics().FilterNode(option, "network", fmt.Sprintf("missing host network %q for port %q", hostNetworkValue, port.Label))
 				return false
 			}
 		}
@@ -620,7 +620,7 @@ func (c *DriverChecker) Feasible(option *structs.Node) bool {
 	if c.hasDrivers(option) {
 		return true
 	}
-	c.ctx.Metrics().FilterNode(option, FilterConstraintDrivers)
+	c.ctx.Metrics().FilterNode(option, "drivers", FilterConstraintDrivers)
 	return false
 }
 
@@ -731,7 +731,7 @@ func (iter *DistinctHostsIterator) Next() *structs.Node {
 
 		// Check if the host constraints are satisfied
 		if !iter.satisfiesDistinctHosts(option) {
-			iter.ctx.Metrics().FilterNode(option, structs.ConstraintDistinctHosts)
+			iter.ctx.Metrics().FilterNode(option, "distinct-hosts", structs.ConstraintDistinctHosts)
 			continue
 		}
 
@@ -857,7 +857,7 @@ func (iter *DistinctPropertyIterator) Next() *structs.Node {
 func (iter *DistinctPropertyIterator) satisfiesProperties(option *structs.Node, set []*propertySet) bool {
 	for _, ps := range set {
 		if satisfies, reason := ps.SatisfiesDistinctProperties(option, iter.tg.Name); !satisfies {
-			iter.ctx.Metrics().FilterNode(option, reason)
+			iter.ctx.Metrics().FilterNode(option, "distinct-property", reason)
 			return false
 		}
 	}
@@ -903,7 +903,7 @@ func (c *ConstraintChecker) Feasible(option *structs.Node) bool {
 	// Use this node if possible
 	for _, constraint := range c.constraints {
 		if !c.meetsConstraint(constraint, option) {
-			c.ctx.Metrics().FilterNode(option, constraint.String())
+			c.ctx.Metrics().FilterNode(option, "constraints", constraint.String())
 			return false
 		}
 	}
@@ -1292,7 +1292,9 @@ OUTER:
 		switch evalElig.JobStatus(option.ComputedClass) {
 		case EvalComputedClassIneligible:
 			// Fast path the ineligible case
-			metrics.FilterNode(option, "computed class ineligible")
+			if !metrics.ExplanationsEnabled() || !w.explainIneligible(option, w.jobCheckers) {
+				metrics.FilterNode(option, "computed-class", "computed class ineligible")
+			}
 			continue
 		case EvalComputedClassEscaped:
 			jobEscaped = true
@@ -1324,7 +1326,9 @@ OUTER:
 		switch evalElig.TaskGroupStatus(w.tg, option.ComputedClass) {
 		case EvalComputedClassIneligible:
 			// Fast path the ineligible case
-			metrics.FilterNode(option, "computed class ineligible")
+			if !metrics.ExplanationsEnabled() || !w.explainIneligible(option, w.tgCheckers) {
+				metrics.FilterNode(option, "computed-class", "computed class ineligible")
+			}
 			continue
 		case EvalComputedClassEligible:
 			// Fast path the eligible case
@@ -1368,6 +1372,19 @@ OUTER:
 	}
 }
 
+// explainIneligible runs the checkers against a node whose computed class was
+// already found ineligible, so the checker that rejects the node is recorded
+// when explaining placements. It returns false if no checker rejects the
+// node.
+func (w *FeasibilityWrapper) explainIneligible(option *structs.Node, checkers []FeasibilityChecker) bool {
+	for _, check := range checkers {
+		if !check.Feasible(option) {
+			return true
+		}
+	}
+	return false
+}
+
 // available checks transient feasibility checkers which depend on changing conditions,
 // e.g. the health status of a plugin or driver, or that are not considered in node
 // computed class, e.g. host volumes.
@@ -1417,7 +1434,7 @@ func (c *DeviceChecker) Feasible(option *structs.Node) bool {
 		return true
 	}
 
-	c.ctx.Metrics().FilterNode(option, FilterConstraintDevices)
+	c.ctx.Metrics().FilterNode(option, "devices", FilterConstraintDevices)
 	return false
 }
 
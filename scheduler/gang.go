@@ -0,0 +1,191 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package scheduler
+
+import (
+	"slices"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// gangPlacement is a single placement made for a gang scheduled task group.
+type gangPlacement struct {
+	alloc *structs.Allocation
+
+	// prevAlloc is the allocation being replaced, if any.
+	prevAlloc *structs.Allocation
+
+	// stoppedPrev is set if the previous allocation was stopped in the plan
+	// to make room for the placement.
+	stoppedPrev bool
+
+	// rescheduling is set if the placement is a reschedule of prevAlloc.
+	rescheduling bool
+}
+
+// gangTracker tracks the placements made for task groups that use gang
+// scheduling, so they can be removed from the plan if any allocation of the
+// gang can't be placed.
+type gangTracker struct {
+	// desired is the number of placements required for each gang scheduled
+	// task group.
+	desired map[string]int
+
+	// placed is the set of placements made so far for each gang scheduled
+	// task group.
+	placed map[string][]*gangPlacement
+}
+
+// newGangTracker returns a gangTracker for the gang scheduled task groups
+// found in the given placements. A gang must place every allocation it is
+// missing, which is its count minus the healthy allocations that keep running
+// after the plan and, for batch jobs, the allocations of the current job that
+// completed successfully, not only the allocations placed by this evaluation.
+func newGangTracker(job *structs.Job, allocs []*structs.Allocation, plan *structs.Plan,
+	results ...[]placementResult) *gangTracker {
+
+	g := &gangTracker{
+		desired: make(map[string]int),
+		placed:  make(map[string][]*gangPlacement),
+	}
+
+	// Allocations replaced by a placement don't keep running
+	replaced := make(map[string]struct{})
+	for _, result := range results {
+		for _, p := range result {
+			tg := p.TaskGroup()
+			if !tg.Gang {
+				continue
+			}
+			if _, ok := g.desired[tg.Name]; !ok {
+				g.desired[tg.Name] = tg.Count
+				if jobTG := job.LookupTaskGroup(tg.Name); jobTG != nil {
+					g.desired[tg.Name] = jobTG.Count
+				}
+			}
+			if prev := p.PreviousAllocation(); prev != nil {
+				replaced[prev.ID] = struct{}{}
+			}
+		}
+	}
+	if len(g.desired) == 0 {
+		return g
+	}
+
+	stopped := make(map[string]struct{})
+	for _, updates := range plan.NodeUpdate {
+		for _, alloc := range updates {
+			stopped[alloc.ID] = struct{}{}
+		}
+	}
+
+	for _, alloc := range allocs {
+		if _, ok := g.desired[alloc.TaskGroup]; !ok {
+			continue
+		}
+
+		// Batch allocations that completed are never replaced, unless they
+		// belong to an older version of the job
+		if job.Type == structs.JobTypeBatch &&
+			alloc.ClientStatus == structs.AllocClientStatusComplete {
+			if alloc.Job == nil || (alloc.Job.Version >= job.Version &&
+				alloc.Job.CreateIndex >= job.CreateIndex) {
+				g.desired[alloc.TaskGroup]--
+			}
+			continue
+		}
+
+		if alloc.TerminalStatus() {
+			continue
+		}
+		switch alloc.ClientStatus {
+		case structs.AllocClientStatusPending, structs.AllocClientStatusRunning:
+		default:
+			continue
+		}
+		if _, ok := replaced[alloc.ID]; ok {
+			continue
+		}
+		if _, ok := stopped[alloc.ID]; ok {
+			continue
+		}
+		g.desired[alloc.TaskGroup]--
+	}
+	for tg, desired := range g.desired {
+		g.desired[tg] = max(desired, 0)
+	}
+	return g
+}
+
+// any returns true if any of the placements belongs to a gang.
+func (g *gangTracker) any() bool {
+	return len(g.desired) > 0
+}
+
+// isGang returns true if the task group is gang scheduled.
+func (g *gangTracker) isGang(tg string) bool {
+	_, ok := g.desired[tg]
+	return ok
+}
+
+// track records a successful placement for a gang scheduled task group.
+func (g *gangTracker) track(tg string, p *gangPlacement) {
+	if !g.isGang(tg) {
+		return
+	}
+	g.placed[tg] = append(g.placed[tg], p)
+}
+
+// incomplete returns the gang scheduled task groups that placed fewer
+// allocations than they are missing, and were not already rolled back.
+func (g *gangTracker) incomplete() []string {
+	var tgs []string
+	for tg, desired := range g.desired {
+		if placed, ok := g.placed[tg]; ok && len(placed) < desired {
+			tgs = append(tgs, tg)
+		}
+	}
+	slices.Sort(tgs)
+	return tgs
+}
+
+// rollback removes every placement made for the task group from the plan,
+// along with the preemptions and stops they required. It returns the
+// placements that were removed.
+func (g *gangTracker) rollback(plan *structs.Plan, tg string) []*gangPlacement {
+	placed := g.placed[tg]
+	delete(g.placed, tg)
+
+	for _, p := range placed {
+		alloc := p.alloc
+		plan.NodeAllocation[alloc.NodeID] = slices.DeleteFunc(plan.NodeAllocation[alloc.NodeID],
+			func(a *structs.Allocation) bool { return a.ID == alloc.ID })
+		if len(plan.NodeAllocation[alloc.NodeID]) == 0 {
+			delete(plan.NodeAllocation, alloc.NodeID)
+		}
+
+		if len(alloc.PreemptedAllocations) > 0 {
+			preemptions := slices.DeleteFunc(plan.NodePreemptions[alloc.NodeID],
+				func(a *structs.Allocation) bool { return a.PreemptedByAllocation == alloc.ID })
+			if len(preemptions) > 0 {
+				plan.NodePreemptions[alloc.NodeID] = preemptions
+			} else {
+				delete(plan.NodePreemptions, alloc.NodeID)
+			}
+		}
+
+		if p.stoppedPrev {
+			prev := p.prevAlloc
+			updates := slices.DeleteFunc(plan.NodeUpdate[prev.NodeID],
+				func(a *structs.Allocation) bool { return a.ID == prev.ID })
+			if len(updates) > 0 {
+				plan.NodeUpdate[prev.NodeID] = updates
+			} else {
+				delete(plan.NodeUpdate, prev.NodeID)
+			}
+		}
+	}
+
+	return placed
+}
@@ -6,6 +6,7 @@ package scheduler
 import (
 	"fmt"
 	"runtime/debug"
+	"slices"
 	"sort"
 	"time"
 
@@ -170,7 +171,9 @@ func (s *GenericScheduler) Process(eval *structs.Evaluation) (err error) {
 		structs.EvalTriggerPeriodicJob, structs.EvalTriggerMaxPlans,
 		structs.EvalTriggerDeploymentWatcher, structs.EvalTriggerRetryFailedAlloc,
 		structs.EvalTriggerFailedFollowUp, structs.EvalTriggerPreemption,
-		structs.EvalTriggerScaling, structs.EvalTriggerMaxDisconnectTimeout, structs.EvalTriggerReconnect:
+		structs.EvalTriggerScaling, structs.EvalTriggerMaxDisconnectTimeout, structs.EvalTriggerReconnect,
+		structs.EvalTriggerJobDependency, structs.EvalTriggerDeschedule,
+		structs.EvalTriggerIndexProgress:
 	default:
 		desc := fmt.Sprintf("scheduler cannot handle '%s' evaluation reason",
 			eval.TriggeredBy)
@@ -278,6 +281,9 @@ func (s *GenericScheduler) process() (bool, error) {
 
 	// Create an evaluation context
 	s.ctx = NewEvalContext(s.eventsCh, s.state, s.plan, s.logger)
+	if s.eval.ExplainPlan {
+		s.ctx.ExplainPlacements()
+	}
 
 	// Construct the placement stack
 	s.stack = NewGenericStack(s.batch, s.ctx)
@@ -285,6 +291,22 @@ func (s *GenericScheduler) process() (bool, error) {
 		s.setJob(s.job)
 	}
 
+	// Batch jobs with dependencies aren't placed until all of their
+	// dependencies are satisfied. The leader creates a new evaluation once
+	// that happens.
+	if s.batch && !stopped && s.job.HasDependencies() {
+		waiting, err := s.waitingOnDependencies(ws)
+		if err != nil {
+			return false, err
+		}
+		if waiting {
+			for _, tg := range s.job.TaskGroups {
+				s.queuedAllocs[tg.Name] = tg.Count
+			}
+			return true, nil
+		}
+	}
+
 	// Compute the target job allocations
 	if err := s.computeJobAllocs(); err != nil {
 		s.logger.Error("failed to compute job allocations", "error", err)
@@ -466,7 +488,8 @@ func (s *GenericScheduler) computeJobAllocs() error {
 		s.queuedAllocs[p.placeTaskGroup.Name] += 1
 		destructive = append(destructive, p)
 	}
-	return s.computePlacements(destructive, place, results.taskGroupAllocNameIndexes)
+	gangs := newGangTracker(s.job, allocs, s.plan, destructive, place)
+	return s.computePlacements(destructive, place, results.taskGroupAllocNameIndexes, gangs)
 }
 
 // downgradedJobForPlacement returns the previous stable version of the job for
@@ -509,7 +532,8 @@ func (s *GenericScheduler) downgradedJobForPlacement(p placementResult) (string,
 
 // computePlacements computes placements for allocations. It is given the set of
 // destructive updates to place and the set of new placements to place.
-func (s *GenericScheduler) computePlacements(destructive, place []placementResult, nameIndex map[string]*allocNameIndex) error {
+func (s *GenericScheduler) computePlacements(destructive, place []placementResult,
+	nameIndex map[string]*allocNameIndex, gangs *gangTracker) error {
 
 	// Get the base nodes
 	nodes, byDC, err := s.setNodes(s.job)
@@ -525,6 +549,13 @@ func (s *GenericScheduler) computePlacements(destructive, place []placementResul
 	// Capture current time to use as the start time for any rescheduled allocations
 	now := time.Now()
 
+	// Gang scheduled task groups must either place all of their allocations
+	// or none of them, so track their placements and require the plan to be
+	// committed all at once.
+	if gangs.any() {
+		s.plan.AllAtOnce = true
+	}
+
 	// Have to handle destructive changes first as we need to discount their
 	// resources. To understand this imagine the resources were reduced and the
 	// count was scaled up.
@@ -598,7 +629,7 @@ func (s *GenericScheduler) computePlacements(destructive, place []placementResul
 			}
 
 			// Compute penalty nodes for rescheduled allocs
-			selectOptions := getSelectOptions(prevAllocation, preferredNode)
+			selectOptions := getSelectOptions(prevAllocation, preferredNode, s.eval.TriggeredBy)
 			selectOptions.AllocName = missing.Name()
 			option := s.selectNextOption(tg, selectOptions)
 
@@ -650,7 +681,12 @@ func (s *GenericScheduler) computePlacements(destructive, place []placementResul
 				// generate a new index and use this. The log message is useful
 				// for debugging and development, but could be removed in a
 				// future version of Nomad.
-				if taskGroupNameIndex.IsDuplicate(allocIndex) {
+				//
+				// The index of an indexed task group is the work the
+				// allocation runs, so replacements always keep the index of
+				// the allocation they replace.
+				keepIndex := tg.Indexed != nil && prevAllocation != nil
+				if !keepIndex && taskGroupNameIndex.IsDuplicate(allocIndex) {
 					oldAllocName := newAllocName
 					newAllocName = taskGroupNameIndex.Next(1)[0]
 					taskGroupNameIndex.UnsetIndex(allocIndex)
@@ -703,6 +739,12 @@ func (s *GenericScheduler) computePlacements(destructive, place []placementResul
 
 				// Track the placement
 				s.plan.AppendAlloc(alloc, downgradedJob)
+				gangs.track(tg.Name, &gangPlacement{
+					alloc:        alloc,
+					prevAlloc:    prevAllocation,
+					stoppedPrev:  stopPrevAlloc,
+					rescheduling: missing.IsRescheduling(),
+				})
 
 			} else {
 				// Lazy initialize the failed map
@@ -731,14 +773,62 @@ func (s *GenericScheduler) computePlacements(destructive, place []placementResul
 					}
 				}
 
+				// If the task group is gang scheduled, none of its
+				// allocations can be placed. Remove the ones already in the
+				// plan and count them as failures.
+				if gangs.isGang(tg.Name) {
+					s.rollbackGang(gangs, tg.Name)
+				}
 			}
 
 		}
 	}
 
+	// Gangs missing more allocations than this evaluation placed, such as
+	// when some replacements are delayed, must not be placed piecemeal.
+	for _, tgName := range gangs.incomplete() {
+		if s.failedTGAllocs == nil {
+			s.failedTGAllocs = make(map[string]*structs.AllocMetric)
+		}
+		metric := &structs.AllocMetric{}
+		s.failedTGAllocs[tgName] = metric
+		s.rollbackGang(gangs, tgName)
+
+		// Unlike a failed placement, every removed placement is coalesced
+		metric.CoalescedFailures--
+	}
+
 	return nil
 }
 
+// rollbackGang removes the placements made for a gang scheduled task group
+// from the plan once one of its allocations failed to be placed, and records
+// the gang failure in the task group's failed allocation metrics.
+func (s *GenericScheduler) rollbackGang(gangs *gangTracker, tgName string) {
+	removed := gangs.rollback(s.plan, tgName)
+
+	for _, p := range removed {
+		if p.prevAlloc != nil && p.rescheduling {
+			annotateRescheduleTracker(p.prevAlloc, structs.LastRescheduleFailedToPlace)
+		}
+
+		// Drop any preemptions that were annotated for the placement.
+		if s.eval.AnnotatePlan && s.plan.Annotations != nil && len(p.alloc.PreemptedAllocations) > 0 {
+			s.plan.Annotations.PreemptedAllocs = slices.DeleteFunc(s.plan.Annotations.PreemptedAllocs,
+				func(stub *structs.AllocListStub) bool {
+					return slices.Contains(p.alloc.PreemptedAllocations, stub.ID)
+				})
+			if desired := s.plan.Annotations.DesiredTGUpdates[tgName]; desired != nil {
+				desired.Preemptions -= uint64(len(p.alloc.PreemptedAllocations))
+			}
+		}
+	}
+
+	metric := s.failedTGAllocs[tgName]
+	metric.CoalescedFailures += len(removed)
+	metric.ExhaustGang(len(removed), gangs.desired[tgName])
+}
+
 // setJob updates the stack with the given job and job's node pool scheduler
 // configuration.
 func (s *GenericScheduler) setJob(job *structs.Job) error {
@@ -779,7 +869,7 @@ func needsToSetNodes(a, b *structs.Job) bool {
 }
 
 // getSelectOptions sets up preferred nodes and penalty nodes
-func getSelectOptions(prevAllocation *structs.Allocation, preferredNode *structs.Node) *SelectOptions {
+func getSelectOptions(prevAllocation *structs.Allocation, preferredNode *structs.Node, triggeredBy string) *SelectOptions {
 	selectOptions := &SelectOptions{}
 	if prevAllocation != nil {
 		penaltyNodes := make(map[string]struct{})
@@ -789,6 +879,14 @@ func getSelectOptions(prevAllocation *structs.Allocation, preferredNode *structs
 		if prevAllocation.ClientStatus == structs.AllocClientStatusFailed {
 			penaltyNodes[prevAllocation.NodeID] = struct{}{}
 		}
+
+		// If alloc is migrated by the descheduler, penalize the node it is
+		// leaving so that it moves to another node. Nodes being drained are
+		// already ineligible, and other migrations may return to the node.
+		if triggeredBy == structs.EvalTriggerDeschedule &&
+			prevAllocation.DesiredTransition.ShouldMigrate() {
+			penaltyNodes[prevAllocation.NodeID] = struct{}{}
+		}
 		if prevAllocation.RescheduleTracker != nil {
 			for _, reschedEvent := range prevAllocation.RescheduleTracker.Events {
 				penaltyNodes[reschedEvent.PrevNodeID] = struct{}{}
@@ -889,6 +987,10 @@ func (s *GenericScheduler) selectNextOption(tg *structs.TaskGroup, selectOptions
 		} else {
 			enablePreemption = schedConfig.PreemptionConfig.ServiceSchedulerEnabled
 		}
+
+		// Jobs whose priority class never preempts don't need another pass
+		enablePreemption = enablePreemption &&
+			schedConfig.LookupPriorityClass(s.job.PriorityClass).CanPreempt()
 	}
 	// Run stack again with preemption enabled
 	if option == nil && enablePreemption {
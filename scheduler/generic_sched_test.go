@@ -1251,6 +1251,76 @@ func TestServiceSched_JobRegister_Annotate(t *testing.T) {
 	}
 }
 
+func TestServiceSched_JobRegister_Explain(t *testing.T) {
+	ci.Parallel(t)
+
+	h := NewHarness(t)
+
+	// Create some nodes, two of which don't satisfy the job's constraint. A
+	// single feasible node makes the scheduler visit every node, rather than
+	// stopping at a random subset of them.
+	for i := 0; i < 3; i++ {
+		node := mock.Node()
+		if i < 2 {
+			node.Attributes["kernel.name"] = "windows"
+			must.NoError(t, node.ComputeClass())
+		}
+		must.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), node))
+	}
+
+	// Create a job
+	job := mock.Job()
+	job.TaskGroups[0].Count = 2
+	must.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), nil, job))
+
+	// Create a mock evaluation to register the job
+	eval := &structs.Evaluation{
+		Namespace:   structs.DefaultNamespace,
+		ID:          uuid.Generate(),
+		Priority:    job.Priority,
+		TriggeredBy: structs.EvalTriggerJobRegister,
+		JobID:       job.ID,
+		ExplainPlan: true,
+		Status:      structs.EvalStatusPending,
+	}
+	must.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+
+	// Process the evaluation
+	must.NoError(t, h.Process(NewServiceScheduler, eval))
+	must.Len(t, 1, h.Plans)
+
+	var planned []*structs.Allocation
+	for _, allocList := range h.Plans[0].NodeAllocation {
+		planned = append(planned, allocList...)
+	}
+	must.Len(t, 2, planned)
+
+	// The second placement filters the nodes from the cached computed class,
+	// but must still report the checker that rejected them
+	for _, alloc := range planned {
+		var filtered, scored int
+		for _, explanation := range alloc.Metrics.NodeExplanations {
+			if explanation.FilteredBy != "" {
+				filtered++
+				must.Eq(t, "constraints", explanation.FilteredBy)
+				must.StrContains(t, explanation.FilterReason, "kernel.name")
+				continue
+			}
+			if explanation.Scored() {
+				scored++
+				must.MapContainsKey(t, explanation.Scores, "binpack")
+			}
+		}
+		must.Eq(t, 2, filtered)
+		must.Positive(t, scored)
+
+		// Scored nodes are listed first, from the highest score
+		first := alloc.Metrics.NodeExplanations[0]
+		must.True(t, first.Scored())
+		must.Eq(t, alloc.Metrics.MaxNormScore().NormScore, first.NormScore)
+	}
+}
+
 func TestServiceSched_JobRegister_CountZero(t *testing.T) {
 	ci.Parallel(t)
 
@@ -1576,6 +1646,326 @@ func TestServiceSched_JobRegister_FeasibleAndInfeasibleTG(t *testing.T) {
 	h.AssertEvalStatus(t, structs.EvalStatusComplete)
 }
 
+func TestServiceSched_JobRegister_Gang(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name     string
+		nodes    int
+		expPlace bool
+	}{
+		{
+			// Each node fits three allocations, so only six of the ten
+			// allocations in the gang can be placed.
+			name:     "partial capacity",
+			nodes:    2,
+			expPlace: false,
+		},
+		{
+			name:     "full capacity",
+			nodes:    4,
+			expPlace: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := NewHarness(t)
+
+			for i := 0; i < tc.nodes; i++ {
+				node := mock.Node()
+				must.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), node))
+			}
+
+			job := mock.Job()
+			job.TaskGroups[0].Gang = true
+			job.TaskGroups[0].Tasks[0].Resources.MemoryMB = 2048
+			must.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), nil, job))
+
+			eval := &structs.Evaluation{
+				Namespace:   structs.DefaultNamespace,
+				ID:          uuid.Generate(),
+				Priority:    job.Priority,
+				TriggeredBy: structs.EvalTriggerJobRegister,
+				JobID:       job.ID,
+				Status:      structs.EvalStatusPending,
+			}
+			must.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+
+			must.NoError(t, h.Process(NewServiceScheduler, eval))
+			h.AssertEvalStatus(t, structs.EvalStatusComplete)
+
+			out, err := h.State.AllocsByJob(nil, job.Namespace, job.ID, false)
+			must.NoError(t, err)
+
+			must.Len(t, 1, h.Evals)
+			outEval := h.Evals[0]
+
+			if tc.expPlace {
+				must.Len(t, 1, h.Plans)
+				must.True(t, h.Plans[0].AllAtOnce)
+				must.Len(t, 10, out)
+				must.Len(t, 0, h.CreateEvals)
+				must.MapEmpty(t, outEval.FailedTGAllocs)
+				return
+			}
+
+			// Nothing from the gang must be placed and the eval must be
+			// blocked until the whole gang fits.
+			must.Len(t, 0, h.Plans)
+			must.Len(t, 0, out)
+			must.Len(t, 1, h.CreateEvals)
+			must.Eq(t, structs.EvalStatusBlocked, h.CreateEvals[0].Status)
+			must.Eq(t, h.CreateEvals[0].ID, outEval.BlockedEval)
+
+			metrics, ok := outEval.FailedTGAllocs[job.TaskGroups[0].Name]
+			must.True(t, ok)
+			must.Eq(t, 9, metrics.CoalescedFailures)
+			must.Eq(t, 10, metrics.GangDesired)
+			must.Eq(t, 6, metrics.GangPlaced)
+			must.Eq(t, 10, outEval.QueuedAllocations[job.TaskGroups[0].Name])
+		})
+	}
+}
+
+func TestServiceSched_Gang_DelayedReplacement(t *testing.T) {
+	ci.Parallel(t)
+
+	h := NewHarness(t)
+	var nodes []*structs.Node
+	for i := 0; i < 4; i++ {
+		node := mock.Node()
+		nodes = append(nodes, node)
+		must.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), node))
+	}
+
+	job := mock.Job()
+	job.TaskGroups[0].Count = 3
+	job.TaskGroups[0].Gang = true
+	job.TaskGroups[0].ReschedulePolicy = &structs.ReschedulePolicy{
+		Attempts:      1,
+		Interval:      15 * time.Minute,
+		Delay:         15 * time.Second,
+		MaxDelay:      time.Minute,
+		DelayFunction: "constant",
+	}
+	must.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), nil, job))
+
+	now := time.Now()
+	var allocs []*structs.Allocation
+	for i := 0; i < 3; i++ {
+		alloc := mock.Alloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.NodeID = nodes[i].ID
+		alloc.Name = structs.AllocName(job.ID, "web", uint(i))
+		allocs = append(allocs, alloc)
+	}
+
+	// One member failed long enough ago to be replaced now, and the other
+	// one is only replaced once its reschedule delay is over.
+	allocs[1].ClientStatus = structs.AllocClientStatusFailed
+	allocs[1].TaskStates = map[string]*structs.TaskState{"web": {State: "dead",
+		StartedAt:  now.Add(-time.Hour),
+		FinishedAt: now}}
+	allocs[2].ClientStatus = structs.AllocClientStatusFailed
+	allocs[2].TaskStates = map[string]*structs.TaskState{"web": {State: "dead",
+		StartedAt:  now.Add(-time.Hour),
+		FinishedAt: now.Add(-10 * time.Minute)}}
+	must.NoError(t, h.State.UpsertAllocs(structs.MsgTypeTestSetup, h.NextIndex(), allocs))
+
+	eval := &structs.Evaluation{
+		Namespace:   structs.DefaultNamespace,
+		ID:          uuid.Generate(),
+		Priority:    job.Priority,
+		TriggeredBy: structs.EvalTriggerNodeUpdate,
+		JobID:       job.ID,
+		Status:      structs.EvalStatusPending,
+	}
+	must.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+	must.NoError(t, h.Process(NewServiceScheduler, eval))
+
+	// The gang is missing two members, so the replacement that could be
+	// placed now must wait for the other one.
+	out, err := h.State.AllocsByJob(nil, job.Namespace, job.ID, false)
+	must.NoError(t, err)
+	must.Len(t, 3, out)
+
+	metrics := h.Evals[0].FailedTGAllocs[job.TaskGroups[0].Name]
+	must.NotNil(t, metrics)
+	must.Eq(t, 2, metrics.GangDesired)
+	must.Eq(t, 1, metrics.GangPlaced)
+	must.Eq(t, 0, metrics.CoalescedFailures)
+}
+
+// TestBatchSched_Gang_Reschedule asserts that the completed allocations of a
+// batch gang aren't counted as missing when a failed member is replaced.
+func TestBatchSched_Gang_Reschedule(t *testing.T) {
+	ci.Parallel(t)
+
+	h := NewHarness(t)
+	var nodes []*structs.Node
+	for i := 0; i < 3; i++ {
+		node := mock.Node()
+		nodes = append(nodes, node)
+		must.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), node))
+	}
+
+	job := mock.Job()
+	job.Type = structs.JobTypeBatch
+	job.TaskGroups[0].Count = 3
+	job.TaskGroups[0].Gang = true
+	job.TaskGroups[0].ReschedulePolicy = &structs.ReschedulePolicy{
+		Attempts:      1,
+		Interval:      15 * time.Minute,
+		Delay:         5 * time.Second,
+		DelayFunction: "constant",
+	}
+	must.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), nil, job))
+
+	now := time.Now()
+	var allocs []*structs.Allocation
+	for i := 0; i < 3; i++ {
+		alloc := mock.Alloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.NodeID = nodes[i].ID
+		alloc.Name = structs.AllocName(job.ID, "web", uint(i))
+		alloc.ClientStatus = structs.AllocClientStatusComplete
+		alloc.TaskStates = map[string]*structs.TaskState{"web": {State: "dead",
+			StartedAt:  now.Add(-time.Hour),
+			FinishedAt: now.Add(-10 * time.Minute)}}
+		allocs = append(allocs, alloc)
+	}
+
+	// Two members completed and the last one failed and can be rescheduled
+	failed := allocs[2]
+	failed.ClientStatus = structs.AllocClientStatusFailed
+	must.NoError(t, h.State.UpsertAllocs(structs.MsgTypeTestSetup, h.NextIndex(), allocs))
+
+	eval := &structs.Evaluation{
+		Namespace:   structs.DefaultNamespace,
+		ID:          uuid.Generate(),
+		Priority:    job.Priority,
+		TriggeredBy: structs.EvalTriggerNodeUpdate,
+		JobID:       job.ID,
+		Status:      structs.EvalStatusPending,
+	}
+	must.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+	must.NoError(t, h.Process(NewBatchScheduler, eval))
+
+	must.Len(t, 1, h.Plans)
+	must.Len(t, 0, h.CreateEvals)
+	must.MapEmpty(t, h.Evals[0].FailedTGAllocs)
+
+	out, err := h.State.AllocsByJob(nil, job.Namespace, job.ID, false)
+	must.NoError(t, err)
+	must.Len(t, 4, out)
+	var replacement *structs.Allocation
+	for _, alloc := range out {
+		if alloc.PreviousAllocation == failed.ID {
+			replacement = alloc
+		}
+	}
+	must.NotNil(t, replacement)
+}
+
+func TestServiceSched_JobRegister_Gang_Preemption(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name     string
+		count    int
+		expPlace bool
+	}{
+		{
+			// Preempting the three low priority allocations only frees room
+			// for three of the four allocations in the gang.
+			name:     "gang does not fit",
+			count:    4,
+			expPlace: false,
+		},
+		{
+			name:     "gang fits",
+			count:    3,
+			expPlace: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := NewHarness(t)
+
+			// The node fits three allocations of either job.
+			node := mock.Node()
+			must.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), node))
+
+			lowJob := mock.Job()
+			lowJob.Priority = 20
+			lowJob.TaskGroups[0].Count = 3
+			lowJob.TaskGroups[0].Networks = nil
+			lowJob.TaskGroups[0].Tasks[0].Resources.MemoryMB = 2048
+			must.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), nil, lowJob))
+
+			lowEval := &structs.Evaluation{
+				Namespace:   structs.DefaultNamespace,
+				ID:          uuid.Generate(),
+				Priority:    lowJob.Priority,
+				TriggeredBy: structs.EvalTriggerJobRegister,
+				JobID:       lowJob.ID,
+				Status:      structs.EvalStatusPending,
+			}
+			must.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{lowEval}))
+			must.NoError(t, h.Process(NewServiceScheduler, lowEval))
+			must.Len(t, 1, h.Plans)
+
+			job := mock.Job()
+			job.Priority = 80
+			job.TaskGroups[0].Gang = true
+			job.TaskGroups[0].Count = tc.count
+			job.TaskGroups[0].Networks = nil
+			job.TaskGroups[0].Tasks[0].Resources.MemoryMB = 2048
+			must.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), nil, job))
+
+			eval := &structs.Evaluation{
+				Namespace:   structs.DefaultNamespace,
+				ID:          uuid.Generate(),
+				Priority:    job.Priority,
+				TriggeredBy: structs.EvalTriggerJobRegister,
+				JobID:       job.ID,
+				Status:      structs.EvalStatusPending,
+			}
+			must.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+			must.NoError(t, h.Process(NewServiceScheduler, eval))
+
+			out, err := h.State.AllocsByJob(nil, job.Namespace, job.ID, false)
+			must.NoError(t, err)
+
+			if !tc.expPlace {
+				// Neither the gang nor the preemptions it required are
+				// part of a plan.
+				must.Len(t, 1, h.Plans)
+				must.Len(t, 0, out)
+
+				metrics := h.Evals[1].FailedTGAllocs[job.TaskGroups[0].Name]
+				must.NotNil(t, metrics)
+				must.Eq(t, tc.count, metrics.GangDesired)
+				must.Eq(t, 3, metrics.GangPlaced)
+				return
+			}
+
+			must.Len(t, 2, h.Plans)
+			must.Len(t, tc.count, out)
+
+			var preempted []*structs.Allocation
+			for _, allocs := range h.Plans[1].NodePreemptions {
+				preempted = append(preempted, allocs...)
+			}
+			must.Len(t, 3, preempted)
+		})
+	}
+}
+
 func TestServiceSched_JobRegister_SchedulerAlgorithm(t *testing.T) {
 	ci.Parallel(t)
 
@@ -4059,6 +4449,71 @@ func TestServiceSched_NodeDrain(t *testing.T) {
 	h.AssertEvalStatus(t, structs.EvalStatusComplete)
 }
 
+// TestServiceSched_MigratePenalty asserts that only the allocations migrated by
+// the descheduler penalize the node they are leaving.
+func TestServiceSched_MigratePenalty(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		triggeredBy string
+		penalty     float64
+	}{
+		{triggeredBy: structs.EvalTriggerDeschedule, penalty: -1},
+		{triggeredBy: structs.EvalTriggerNodeUpdate, penalty: 0},
+	}
+	for _, tc := range cases {
+		t.Run(tc.triggeredBy, func(t *testing.T) {
+			h := NewHarness(t)
+
+			var nodes []*structs.Node
+			for i := 0; i < 2; i++ {
+				node := mock.Node()
+				nodes = append(nodes, node)
+				must.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), node))
+			}
+
+			job := mock.Job()
+			job.TaskGroups[0].Count = 1
+			must.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), nil, job))
+
+			alloc := mock.Alloc()
+			alloc.Job = job
+			alloc.JobID = job.ID
+			alloc.NodeID = nodes[0].ID
+			alloc.Name = "my-job.web[0]"
+			alloc.DesiredTransition.Migrate = pointer.Of(true)
+			must.NoError(t, h.State.UpsertAllocs(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Allocation{alloc}))
+
+			eval := &structs.Evaluation{
+				Namespace:   structs.DefaultNamespace,
+				ID:          uuid.Generate(),
+				Priority:    50,
+				TriggeredBy: tc.triggeredBy,
+				JobID:       job.ID,
+				Status:      structs.EvalStatusPending,
+			}
+			must.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+			must.NoError(t, h.Process(NewServiceScheduler, eval))
+
+			must.Len(t, 1, h.Plans)
+			var planned []*structs.Allocation
+			for _, allocList := range h.Plans[0].NodeAllocation {
+				planned = append(planned, allocList...)
+			}
+			must.Len(t, 1, planned)
+
+			var scored bool
+			for _, scoreMeta := range planned[0].Metrics.ScoreMetaData {
+				if scoreMeta.NodeID == nodes[0].ID {
+					scored = true
+					must.Eq(t, tc.penalty, scoreMeta.Scores["node-reschedule-penalty"])
+				}
+			}
+			must.True(t, scored)
+		})
+	}
+}
+
 func TestServiceSched_NodeDrain_Down(t *testing.T) {
 	ci.Parallel(t)
 
@@ -5173,6 +5628,73 @@ func TestBatchSched_Run_FailedAlloc(t *testing.T) {
 	h.AssertEvalStatus(t, structs.EvalStatusComplete)
 }
 
+func TestBatchSched_Indexed_ReplacementKeepsIndex(t *testing.T) {
+	ci.Parallel(t)
+
+	h := NewHarness(t)
+
+	var nodes []*structs.Node
+	for i := 0; i < 4; i++ {
+		node := mock.Node()
+		nodes = append(nodes, node)
+		must.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), node))
+	}
+
+	job := mock.Job()
+	job.Type = structs.JobTypeBatch
+	job.TaskGroups[0].Count = 3
+	job.TaskGroups[0].Indexed = &structs.IndexedPolicy{MaxFailures: 3}
+	job.TaskGroups[0].ReschedulePolicy = &structs.ReschedulePolicy{
+		Delay:         5 * time.Second,
+		DelayFunction: "constant",
+	}
+	must.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), nil, job))
+
+	// Index 0 has two failed allocations, so its index is a duplicate in the
+	// name index
+	now := time.Now()
+	var allocs []*structs.Allocation
+	for i, index := range []uint{0, 0, 1} {
+		alloc := mock.Alloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.NodeID = nodes[i].ID
+		alloc.Name = structs.AllocName(job.ID, "web", index)
+		alloc.ClientStatus = structs.AllocClientStatusRunning
+		allocs = append(allocs, alloc)
+	}
+	for _, alloc := range allocs[:2] {
+		alloc.ClientStatus = structs.AllocClientStatusFailed
+		alloc.TaskStates = map[string]*structs.TaskState{"web": {State: "dead",
+			StartedAt:  now.Add(-1 * time.Hour),
+			FinishedAt: now.Add(-10 * time.Minute)}}
+	}
+	must.NoError(t, h.State.UpsertAllocs(structs.MsgTypeTestSetup, h.NextIndex(), allocs))
+
+	eval := &structs.Evaluation{
+		Namespace:   structs.DefaultNamespace,
+		ID:          uuid.Generate(),
+		Priority:    job.Priority,
+		TriggeredBy: structs.EvalTriggerJobRegister,
+		JobID:       job.ID,
+		Status:      structs.EvalStatusPending,
+	}
+	must.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+	must.NoError(t, h.Process(NewBatchScheduler, eval))
+	must.Len(t, 1, h.Plans)
+
+	// Both replacements keep the index of the allocation they replace
+	var placed []*structs.Allocation
+	for _, nodeAllocs := range h.Plans[0].NodeAllocation {
+		placed = append(placed, nodeAllocs...)
+	}
+	must.Len(t, 2, placed)
+	for _, alloc := range placed {
+		must.NotEq(t, "", alloc.PreviousAllocation)
+		must.Eq(t, allocs[0].Name, alloc.Name)
+	}
+}
+
 func TestBatchSched_Run_LostAlloc(t *testing.T) {
 	ci.Parallel(t)
 
@@ -7430,3 +7952,76 @@ func initNodeAndAllocs(t *testing.T, h *Harness, job *structs.Job,
 	return node, job, allocs
 
 }
+
+func TestBatchSched_JobDependencies(t *testing.T) {
+	ci.Parallel(t)
+
+	h := NewHarness(t)
+
+	for i := 0; i < 2; i++ {
+		node := mock.Node()
+		must.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), node))
+	}
+
+	upstream := mock.BatchJob()
+	must.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), nil, upstream))
+
+	job := mock.BatchJob()
+	job.TaskGroups[0].Count = 2
+	job.DependsOn = []*structs.JobDependency{{
+		JobID:     upstream.ID,
+		Condition: structs.JobDependencyConditionSuccessful,
+	}}
+	must.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), nil, job))
+
+	eval := &structs.Evaluation{
+		Namespace:   structs.DefaultNamespace,
+		ID:          uuid.Generate(),
+		Priority:    job.Priority,
+		TriggeredBy: structs.EvalTriggerJobRegister,
+		JobID:       job.ID,
+		Status:      structs.EvalStatusPending,
+	}
+	must.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+
+	// The upstream job hasn't finished, so nothing is placed
+	must.NoError(t, h.Process(NewBatchScheduler, eval))
+	must.Len(t, 0, h.Plans)
+	must.Len(t, 0, h.CreateEvals)
+	must.Len(t, 1, h.Evals)
+	must.Eq(t, structs.EvalStatusComplete, h.Evals[0].Status)
+	must.Eq(t, 2, h.Evals[0].QueuedAllocations["web"])
+
+	// Complete the upstream job
+	upstreamEval := &structs.Evaluation{
+		Namespace:   structs.DefaultNamespace,
+		ID:          uuid.Generate(),
+		Priority:    upstream.Priority,
+		TriggeredBy: structs.EvalTriggerJobRegister,
+		JobID:       upstream.ID,
+		Status:      structs.EvalStatusComplete,
+	}
+	must.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{upstreamEval}))
+
+	out, err := h.State.JobByID(nil, upstream.Namespace, upstream.ID)
+	must.NoError(t, err)
+	must.Eq(t, structs.JobStatusDead, out.Status)
+
+	eval = &structs.Evaluation{
+		Namespace:   structs.DefaultNamespace,
+		ID:          uuid.Generate(),
+		Priority:    job.Priority,
+		TriggeredBy: structs.EvalTriggerJobDependency,
+		JobID:       job.ID,
+		Status:      structs.EvalStatusPending,
+	}
+	must.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+
+	// The dependency is satisfied, so the job is placed
+	must.NoError(t, h.Process(NewBatchScheduler, eval))
+	must.Len(t, 1, h.Plans)
+
+	allocs, err := h.State.AllocsByJob(nil, job.Namespace, job.ID, false)
+	must.NoError(t, err)
+	must.Len(t, 2, allocs)
+}
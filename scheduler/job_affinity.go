@@ -0,0 +1,229 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package scheduler
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// JobAffinityConstraintIterator is a FeasibleIterator which returns nodes
+// that pass the required job_affinity blocks of the job and task group. A
+// node is filtered if it doesn't have a proposed allocation matching a
+// required affinity, or if it has one matching a required anti-affinity.
+type JobAffinityConstraintIterator struct {
+	ctx    Context
+	source FeasibleIterator
+	job    *structs.Job
+	tg     *structs.TaskGroup
+
+	// jobAffinities are the job affinities set at the job level.
+	jobAffinities []*structs.JobAffinity
+
+	// required are the required job affinities of the current task group,
+	// starting with the ones set at the job level.
+	required []*structs.JobAffinity
+}
+
+// NewJobAffinityConstraintIterator creates a JobAffinityConstraintIterator
+// from a source.
+func NewJobAffinityConstraintIterator(ctx Context, source FeasibleIterator) *JobAffinityConstraintIterator {
+	return &JobAffinityConstraintIterator{
+		ctx:    ctx,
+		source: source,
+	}
+}
+
+func (iter *JobAffinityConstraintIterator) SetJob(job *structs.Job) {
+	iter.job = job
+	iter.jobAffinities = job.JobAffinities
+}
+
+func (iter *JobAffinityConstraintIterator) SetTaskGroup(tg *structs.TaskGroup) {
+	iter.tg = tg
+	iter.required = nil
+	for _, affinity := range iter.jobAffinities {
+		if affinity.Required {
+			iter.required = append(iter.required, affinity)
+		}
+	}
+	for _, affinity := range tg.JobAffinities {
+		if affinity.Required {
+			iter.required = append(iter.required, affinity)
+		}
+	}
+}
+
+func (iter *JobAffinityConstraintIterator) Next() *structs.Node {
+	for {
+		// Get the next option from the source
+		option := iter.source.Next()
+
+		// Hot path if there is nothing to check
+		if option == nil || len(iter.required) == 0 {
+			return option
+		}
+
+		matched, err := matchJobAffinities(iter.ctx, iter.job, iter.tg, iter.required, option.ID)
+		if err != nil {
+			iter.ctx.Logger().Named("job_affinity").Error("failed to get proposed allocations", "node_id", option.ID, "error", err)
+			iter.ctx.Metrics().FilterNode(option, "job-affinity", "job_affinity: failed to get proposed allocations")
+			continue
+		}
+
+		if ok, reason := satisfiesJobAffinities(iter.required, matched); !ok {
+			iter.ctx.Metrics().FilterNode(option, "job-affinity", reason)
+			continue
+		}
+
+		return option
+	}
+}
+
+func (iter *JobAffinityConstraintIterator) Reset() {
+	iter.source.Reset()
+}
+
+// satisfiesJobAffinities returns whether the required job affinities are
+// satisfied given whether each one matched an allocation on the node. If not,
+// the reason is returned.
+func satisfiesJobAffinities(required []*structs.JobAffinity, matched []bool) (bool, string) {
+	for i, affinity := range required {
+		switch {
+		case affinity.Anti && matched[i]:
+			return false, fmt.Sprintf("job_anti_affinity: allocation with %s", affinity.Selector())
+		case !affinity.Anti && !matched[i]:
+			return false, fmt.Sprintf("job_affinity: no allocation with %s", affinity.Selector())
+		}
+	}
+	return true, ""
+}
+
+// JobAffinityIterator is used to resolve the job_affinity blocks of the job
+// and task group that aren't required, and apply a weighted score to nodes
+// according to the allocations of other jobs they are running.
+type JobAffinityIterator struct {
+	ctx           Context
+	source        RankIterator
+	job           *structs.Job
+	tg            *structs.TaskGroup
+	jobAffinities []*structs.JobAffinity
+	affinities    []*structs.JobAffinity
+}
+
+// NewJobAffinityIterator is used to create a JobAffinityIterator that
+// applies a weighted score according to whether nodes run allocations
+// matching the job affinities of the job or task group.
+func NewJobAffinityIterator(ctx Context, source RankIterator) *JobAffinityIterator {
+	return &JobAffinityIterator{
+		ctx:    ctx,
+		source: source,
+	}
+}
+
+func (iter *JobAffinityIterator) SetJob(job *structs.Job) {
+	iter.job = job
+	iter.jobAffinities = job.JobAffinities
+}
+
+func (iter *JobAffinityIterator) SetTaskGroup(tg *structs.TaskGroup) {
+	iter.tg = tg
+	iter.affinities = nil
+	for _, affinity := range iter.jobAffinities {
+		if !affinity.Required {
+			iter.affinities = append(iter.affinities, affinity)
+		}
+	}
+	for _, affinity := range tg.JobAffinities {
+		if !affinity.Required {
+			iter.affinities = append(iter.affinities, affinity)
+		}
+	}
+}
+
+func (iter *JobAffinityIterator) Reset() {
+	iter.source.Reset()
+}
+
+func (iter *JobAffinityIterator) hasAffinities() bool {
+	return len(iter.affinities) > 0
+}
+
+func (iter *JobAffinityIterator) Next() *RankedNode {
+	option := iter.source.Next()
+	if option == nil {
+		return nil
+	}
+	if !iter.hasAffinities() {
+		return option
+	}
+
+	matched, err := matchJobAffinities(iter.ctx, iter.job, iter.tg, iter.affinities, option.Node.ID)
+	if err != nil {
+		iter.ctx.Logger().Named("job_affinity").Error("failed to get proposed allocations", "node_id", option.Node.ID, "error", err)
+		return option
+	}
+
+	sumWeight := 0.0
+	totalAffinityScore := 0.0
+	for i, affinity := range iter.affinities {
+		weight := float64(affinity.Weight)
+		sumWeight += math.Abs(weight)
+		if !matched[i] {
+			continue
+		}
+		if affinity.Anti {
+			totalAffinityScore -= weight
+		} else {
+			totalAffinityScore += weight
+		}
+	}
+
+	if totalAffinityScore != 0.0 {
+		normScore := totalAffinityScore / sumWeight
+		option.Scores = append(option.Scores, normScore)
+		iter.ctx.Metrics().ScoreNode(option.Node, "job-affinity", normScore)
+	}
+	return option
+}
+
+// matchJobAffinities returns whether each job affinity matches at least one
+// of the proposed allocations on the node. Allocations of the task group
+// being placed are never matched, but the ones of other task groups of the
+// same job are.
+func matchJobAffinities(ctx Context, job *structs.Job, tg *structs.TaskGroup,
+	affinities []*structs.JobAffinity, nodeID string) ([]bool, error) {
+
+	proposed, err := ctx.ProposedAllocs(nodeID)
+	if err != nil {
+		return nil, err
+	}
+
+	matched := make([]bool, len(affinities))
+	for _, alloc := range proposed {
+		if alloc.Namespace != job.Namespace || alloc.TerminalStatus() {
+			continue
+		}
+		if alloc.JobID == job.ID && alloc.TaskGroup == tg.Name {
+			continue
+		}
+
+		allocJob := alloc.Job
+		if allocJob == nil {
+			allocJob, err = ctx.State().JobByID(nil, alloc.Namespace, alloc.JobID)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		for i, affinity := range affinities {
+			if !matched[i] && affinity.Matches(alloc, allocJob) {
+				matched[i] = true
+			}
+		}
+	}
+	return matched, nil
+}
@@ -0,0 +1,211 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+// jobAffinityTestAllocs registers a cache job with allocations on the first
+// two nodes and returns it.
+func jobAffinityTestAllocs(t *testing.T, store *state.StateStore, nodes []*structs.Node) *structs.Job {
+	cache := mock.Job()
+	cache.ID = "cache"
+	cache.Meta = map[string]string{"tier": "storage"}
+	must.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 200, nil, cache))
+
+	var allocs []*structs.Allocation
+	for _, node := range nodes[:2] {
+		alloc := mock.Alloc()
+		alloc.Job = cache
+		alloc.JobID = cache.ID
+		alloc.TaskGroup = cache.TaskGroups[0].Name
+		alloc.NodeID = node.ID
+		allocs = append(allocs, alloc)
+	}
+	must.NoError(t, store.UpsertAllocs(structs.MsgTypeTestSetup, 201, allocs))
+	return cache
+}
+
+func TestJobAffinityConstraintIterator(t *testing.T) {
+	ci.Parallel(t)
+
+	state, ctx := testContext(t)
+	nodes := []*structs.Node{mock.Node(), mock.Node(), mock.Node()}
+	for i, node := range nodes {
+		must.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, uint64(100+i), node))
+	}
+	jobAffinityTestAllocs(t, state, nodes)
+
+	testCases := []struct {
+		name     string
+		affinity *structs.JobAffinity
+		expected []*structs.Node
+		reason   string
+	}{
+		{
+			name:     "affinity on job id",
+			affinity: &structs.JobAffinity{JobID: "cache", Required: true},
+			expected: nodes[:2],
+			reason:   "job_affinity: no allocation with job_id=cache",
+		},
+		{
+			name:     "anti-affinity on job meta",
+			affinity: &structs.JobAffinity{Meta: map[string]string{"tier": "storage"}, Anti: true, Required: true},
+			expected: nodes[2:],
+			reason:   "job_anti_affinity: allocation with meta.tier=storage",
+		},
+		{
+			name:     "affinity on missing group",
+			affinity: &structs.JobAffinity{JobID: "cache", Group: "other", Required: true},
+			expected: nil,
+			reason:   "job_affinity: no allocation with job_id=cache group=other",
+		},
+		{
+			name:     "soft affinity is ignored",
+			affinity: &structs.JobAffinity{JobID: "unknown", Weight: 50},
+			expected: nodes,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx.metrics = new(structs.AllocMetric)
+
+			job := mock.Job()
+			job.TaskGroups[0].JobAffinities = []*structs.JobAffinity{tc.affinity}
+
+			static := NewStaticIterator(ctx, nodes)
+			iter := NewJobAffinityConstraintIterator(ctx, static)
+			iter.SetJob(job)
+			iter.SetTaskGroup(job.TaskGroups[0])
+
+			must.Eq(t, tc.expected, collectFeasible(iter))
+			if tc.reason != "" {
+				must.MapContainsKey(t, ctx.Metrics().ConstraintFiltered, tc.reason)
+			}
+		})
+	}
+}
+
+func TestJobAffinityConstraintIterator_Plan(t *testing.T) {
+	ci.Parallel(t)
+
+	state, ctx := testContext(t)
+	nodes := []*structs.Node{mock.Node(), mock.Node(), mock.Node()}
+	for i, node := range nodes {
+		must.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, uint64(100+i), node))
+	}
+	cache := jobAffinityTestAllocs(t, state, nodes)
+
+	job := mock.Job()
+	job.JobAffinities = []*structs.JobAffinity{{JobID: cache.ID, Required: true}}
+
+	static := NewStaticIterator(ctx, nodes)
+	iter := NewJobAffinityConstraintIterator(ctx, static)
+	iter.SetJob(job)
+	iter.SetTaskGroup(job.TaskGroups[0])
+
+	// Stopping an allocation and placing another one in the plan moves the
+	// feasible node
+	stopped, err := state.AllocsByNode(nil, nodes[0].ID)
+	must.NoError(t, err)
+	ctx.Plan().NodeUpdate[nodes[0].ID] = stopped
+
+	placed := mock.Alloc()
+	placed.Job = cache
+	placed.JobID = cache.ID
+	placed.NodeID = nodes[2].ID
+	ctx.Plan().NodeAllocation[nodes[2].ID] = []*structs.Allocation{placed}
+
+	iter.Reset()
+	must.Eq(t, []*structs.Node{nodes[1], nodes[2]}, collectFeasible(iter))
+}
+
+func TestJobAffinityIterator(t *testing.T) {
+	ci.Parallel(t)
+
+	state, ctx := testContext(t)
+	nodes := []*structs.Node{mock.Node(), mock.Node(), mock.Node()}
+	for i, node := range nodes {
+		must.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, uint64(100+i), node))
+	}
+	jobAffinityTestAllocs(t, state, nodes)
+
+	job := mock.Job()
+	job.TaskGroups[0].JobAffinities = []*structs.JobAffinity{
+		{JobID: "cache", Weight: 50},
+		{Meta: map[string]string{"tier": "storage"}, Anti: true, Weight: 25},
+	}
+
+	static := NewStaticRankIterator(ctx, []*RankedNode{
+		{Node: nodes[0]}, {Node: nodes[1]}, {Node: nodes[2]},
+	})
+	iter := NewJobAffinityIterator(ctx, static)
+	iter.SetJob(job)
+	iter.SetTaskGroup(job.TaskGroups[0])
+
+	out := collectRanked(iter)
+	must.Len(t, 3, out)
+	must.Eq(t, []float64{(50.0 - 25.0) / 75.0}, out[0].Scores)
+	must.Eq(t, []float64{(50.0 - 25.0) / 75.0}, out[1].Scores)
+	must.Len(t, 0, out[2].Scores)
+}
+
+func TestServiceSched_JobAntiAffinity(t *testing.T) {
+	ci.Parallel(t)
+
+	h := NewHarness(t)
+
+	var nodes []*structs.Node
+	for i := 0; i < 4; i++ {
+		node := mock.Node()
+		must.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), node))
+		nodes = append(nodes, node)
+	}
+
+	noisy := mock.Job()
+	noisy.ID = "noisy"
+	must.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), nil, noisy))
+	var noisyAllocs []*structs.Allocation
+	for _, node := range nodes[:3] {
+		alloc := mock.Alloc()
+		alloc.Job = noisy
+		alloc.JobID = noisy.ID
+		alloc.NodeID = node.ID
+		alloc.Name = structs.AllocName(noisy.ID, noisy.TaskGroups[0].Name, uint(len(noisyAllocs)))
+		noisyAllocs = append(noisyAllocs, alloc)
+	}
+	must.NoError(t, h.State.UpsertAllocs(structs.MsgTypeTestSetup, h.NextIndex(), noisyAllocs))
+
+	job := mock.Job()
+	job.TaskGroups[0].Count = 2
+	job.TaskGroups[0].JobAffinities = []*structs.JobAffinity{
+		{JobID: noisy.ID, Anti: true, Required: true},
+	}
+	must.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), nil, job))
+
+	eval := &structs.Evaluation{
+		Namespace:   structs.DefaultNamespace,
+		ID:          uuid.Generate(),
+		Priority:    job.Priority,
+		TriggeredBy: structs.EvalTriggerJobRegister,
+		JobID:       job.ID,
+		Status:      structs.EvalStatusPending,
+	}
+	must.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+	must.NoError(t, h.Process(NewServiceScheduler, eval))
+
+	// Every placement lands on the only node not running the noisy job
+	must.Len(t, 1, h.Plans)
+	must.MapLen(t, 1, h.Plans[0].NodeAllocation)
+	must.Len(t, 2, h.Plans[0].NodeAllocation[nodes[3].ID])
+}
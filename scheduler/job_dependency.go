@@ -0,0 +1,44 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package scheduler
+
+import (
+	"fmt"
+
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// waitingOnDependencies returns true if the job must not be placed yet
+// because one of its dependencies isn't satisfied. Dependencies are only
+// checked before the first placement of the job, so a job that already has
+// allocations is never held back.
+func (s *GenericScheduler) waitingOnDependencies(ws memdb.WatchSet) (bool, error) {
+	allocs, err := s.state.AllocsByJob(ws, s.job.Namespace, s.job.ID, false)
+	if err != nil {
+		return false, fmt.Errorf("failed to get allocs for job %q: %v", s.job.ID, err)
+	}
+	if len(allocs) > 0 {
+		return false, nil
+	}
+
+	for _, dep := range s.job.DependsOn {
+		upstream, err := s.state.JobByID(ws, s.job.Namespace, dep.JobID)
+		if err != nil {
+			return false, fmt.Errorf("failed to get upstream job %q: %v", dep.JobID, err)
+		}
+		summary, err := s.state.JobSummaryByID(ws, s.job.Namespace, dep.JobID)
+		if err != nil {
+			return false, fmt.Errorf("failed to get summary for upstream job %q: %v", dep.JobID, err)
+		}
+
+		if status := dep.Status(upstream, summary); status != structs.JobDependencyStatusSatisfied {
+			s.logger.Debug("job dependency not satisfied",
+				"upstream_job_id", dep.JobID, "condition", dep.Condition, "status", status)
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
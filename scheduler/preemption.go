@@ -122,6 +122,10 @@ type Preemptor struct {
 	// jobID is the ID of the job being preempted
 	jobID *structs.NamespacedID
 
+	// priorityClasses are the priority classes by name, used to skip
+	// allocations of jobs whose priority class is not preemptible
+	priorityClasses map[string]*structs.PriorityClass
+
 	// nodeRemainingResources tracks available resources on the node after
 	// accounting for running allocations
 	nodeRemainingResources *structs.ComparableResources
@@ -154,6 +158,7 @@ func (p *Preemptor) Copy() *Preemptor {
 		allocDetails:           helper.DeepCopyMap(p.allocDetails),
 		jobPriority:            p.jobPriority,
 		jobID:                  p.jobID,
+		priorityClasses:        p.priorityClasses,
 		nodeRemainingResources: p.nodeRemainingResources.Copy(),
 		currentAllocs:          helper.CopySlice(p.currentAllocs),
 		ctx:                    p.ctx,
@@ -171,6 +176,22 @@ func (p *Preemptor) SetNode(node *structs.Node) {
 	p.nodeRemainingResources = nodeRemainingResources
 }
 
+// SetPriorityClasses sets the priority classes used to find whether the
+// allocations of a job can be preempted
+func (p *Preemptor) SetPriorityClasses(classes map[string]*structs.PriorityClass) {
+	p.priorityClasses = classes
+}
+
+// canPreempt returns whether the allocation can be preempted by the job
+// being placed. Only allocs with a job priority < 10 of jobPriority, and
+// whose job's priority class is preemptible, can be preempted
+func (p *Preemptor) canPreempt(alloc *structs.Allocation) bool {
+	if p.jobPriority-alloc.Job.Priority < 10 {
+		return false
+	}
+	return p.priorityClasses[alloc.Job.PriorityClass].Preemptible()
+}
+
 // SetCandidates initializes the candidate set from which preemptions are chosen
 func (p *Preemptor) SetCandidates(allocs []*structs.Allocation) {
 	// Reset candidate set
@@ -234,7 +255,7 @@ func (p *Preemptor) PreemptForTaskGroup(resourceAsk *structs.AllocatedResources)
 	}
 
 	// Group candidates by priority, filter out ineligible allocs
-	allocsByPriority := filterAndGroupPreemptibleAllocs(p.jobPriority, p.currentAllocs)
+	allocsByPriority := p.filterAndGroupPreemptibleAllocs(p.currentAllocs)
 
 	var bestAllocs []*structs.Allocation
 	allRequirementsMet := false
@@ -336,7 +357,7 @@ func (p *Preemptor) PreemptForNetwork(networkResourceAsk *structs.NetworkResourc
 		net := networks[0]
 
 		// Filter out alloc that's ineligible due to priority
-		if p.jobPriority-alloc.Job.Priority < 10 {
+		if !p.canPreempt(alloc) {
 			// Populate any reserved ports used by
 			// this allocation that cannot be preempted
 			for _, port := range net.ReservedPorts {
@@ -429,7 +450,7 @@ OUTER:
 		}
 
 		// Split by priority
-		allocsByPriority := filterAndGroupPreemptibleAllocs(p.jobPriority, currentAllocs)
+		allocsByPriority := p.filterAndGroupPreemptibleAllocs(currentAllocs)
 
 		for _, allocsGrp := range allocsByPriority {
 			allocs := allocsGrp.allocs
@@ -546,7 +567,7 @@ func (p *Preemptor) PreemptForDevice(ask *structs.RequestedDevice, devAlloc *dev
 OUTER:
 	for deviceIDTuple, allocsGrp := range deviceToAllocs {
 		// First group and sort allocations using this device by priority
-		allocsByPriority := filterAndGroupPreemptibleAllocs(p.jobPriority, allocsGrp.allocs)
+		allocsByPriority := p.filterAndGroupPreemptibleAllocs(allocsGrp.allocs)
 
 		// Reset preempted count for this device
 		preemptedCount := 0
@@ -688,8 +709,8 @@ func scoreForNetwork(resourceUsed *structs.NetworkResource, resourceNeeded *stru
 }
 
 // filterAndGroupPreemptibleAllocs groups allocations by priority after filtering allocs
-// that are not preemptible based on the jobPriority arg
-func filterAndGroupPreemptibleAllocs(jobPriority int, current []*structs.Allocation) []*groupedAllocs {
+// that are not preemptible by the job being placed
+func (p *Preemptor) filterAndGroupPreemptibleAllocs(current []*structs.Allocation) []*groupedAllocs {
 	allocsByPriority := make(map[int][]*structs.Allocation)
 	for _, alloc := range current {
 		if alloc.Job == nil {
@@ -699,7 +720,7 @@ func filterAndGroupPreemptibleAllocs(jobPriority int, current []*structs.Allocat
 		// Skip allocs whose priority is within a delta of 10
 		// This also skips any allocs of the current job
 		// for which we are attempting preemption
-		if jobPriority-alloc.Job.Priority < 10 {
+		if !p.canPreempt(alloc) {
 			continue
 		}
 		grpAllocs, ok := allocsByPriority[alloc.Job.Priority]
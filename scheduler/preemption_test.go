@@ -1569,6 +1569,104 @@ func TestPreemptionMultiple(t *testing.T) {
 	require.Equal(t, allocIDs, preempted)
 }
 
+// TestPreemption_PriorityClasses tests that the priority classes of jobs
+// prevent them from preempting or from being preempted
+func TestPreemption_PriorityClasses(t *testing.T) {
+	ci.Parallel(t)
+
+	schedConfig := testSchedulerConfig.Copy()
+	schedConfig.PriorityClasses = []*structs.PriorityClass{
+		{
+			Name:             "critical",
+			Priority:         20,
+			PreemptionPolicy: structs.PriorityClassPreemptionPolicyLowerPriority,
+			NonPreemptible:   true,
+		},
+		{
+			Name:             "polite",
+			Priority:         90,
+			PreemptionPolicy: structs.PriorityClassPreemptionPolicyNever,
+		},
+		{
+			Name:             "important",
+			Priority:         90,
+			PreemptionPolicy: structs.PriorityClassPreemptionPolicyLowerPriority,
+		},
+	}
+
+	testCases := []struct {
+		name        string
+		allocClass  string
+		jobClass    string
+		expPreempts bool
+	}{
+		{
+			name:        "no priority classes",
+			expPreempts: true,
+		},
+		{
+			name:        "preemptible alloc",
+			jobClass:    "important",
+			expPreempts: true,
+		},
+		{
+			name:       "non-preemptible alloc",
+			allocClass: "critical",
+			jobClass:   "important",
+		},
+		{
+			name:     "job never preempts",
+			jobClass: "polite",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			node := mock.Node()
+			state, ctx := testContext(t)
+			require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 1000, node))
+
+			// Fill the node with a single low priority allocation
+			lowPrioJob := mock.Job()
+			lowPrioJob.Priority = 20
+			lowPrioJob.PriorityClass = tc.allocClass
+			alloc := createAlloc(uuid.Generate(), lowPrioJob, &structs.Resources{
+				CPU:      3500,
+				MemoryMB: 7000,
+			})
+			alloc.NodeID = node.ID
+			require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1001, []*structs.Allocation{alloc}))
+
+			job := mock.Job()
+			job.Priority = 90
+			job.PriorityClass = tc.jobClass
+
+			static := NewStaticRankIterator(ctx, []*RankedNode{{Node: node}})
+			binPackIter := NewBinPackIterator(ctx, static, true, job.Priority)
+			binPackIter.SetJob(job)
+			binPackIter.SetSchedulerConfiguration(schedConfig)
+			binPackIter.SetTaskGroup(&structs.TaskGroup{
+				EphemeralDisk: &structs.EphemeralDisk{},
+				Tasks: []*structs.Task{
+					{
+						Name:      "web",
+						Resources: &structs.Resources{CPU: 1000, MemoryMB: 1024},
+					},
+				},
+			})
+
+			option := binPackIter.Next()
+			if !tc.expPreempts {
+				require.Nil(t, option)
+				return
+			}
+			require.NotNil(t, option)
+			require.Len(t, option.PreemptedAllocs, 1)
+			require.Equal(t, alloc.ID, option.PreemptedAllocs[0].ID)
+		})
+	}
+}
+
 // helper method to create allocations with given jobs and resources
 func createAlloc(id string, job *structs.Job, resource *structs.Resources) *structs.Allocation {
 	return createAllocInner(id, job, resource, nil, nil)
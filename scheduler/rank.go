@@ -162,6 +162,8 @@ type BinPackIterator struct {
 	source                 RankIterator
 	evict                  bool
 	priority               int
+	priorityClass          string
+	priorityClasses        map[string]*structs.PriorityClass
 	jobId                  structs.NamespacedID
 	taskGroup              *structs.TaskGroup
 	memoryOversubscription bool
@@ -186,6 +188,7 @@ func NewBinPackIterator(ctx Context, source RankIterator, evict bool, priority i
 
 func (iter *BinPackIterator) SetJob(job *structs.Job) {
 	iter.priority = job.Priority
+	iter.priorityClass = job.PriorityClass
 	iter.jobId = job.NamespacedID()
 }
 
@@ -204,9 +207,21 @@ func (iter *BinPackIterator) SetSchedulerConfiguration(schedConfig *structs.Sche
 
 	// Set memory oversubscription.
 	iter.memoryOversubscription = schedConfig != nil && schedConfig.MemoryOversubscriptionEnabled
+
+	// Set priority classes.
+	iter.priorityClasses = nil
+	if schedConfig != nil && len(schedConfig.PriorityClasses) > 0 {
+		iter.priorityClasses = make(map[string]*structs.PriorityClass, len(schedConfig.PriorityClasses))
+		for _, class := range schedConfig.PriorityClasses {
+			iter.priorityClasses[class.Name] = class
+		}
+	}
 }
 
 func (iter *BinPackIterator) Next() *RankedNode {
+	// Jobs whose priority class never preempts are placed as if eviction
+	// was disabled
+	evict := iter.evict && iter.priorityClasses[iter.priorityClass].CanPreempt()
 
 NEXTNODE:
 	for {
@@ -276,6 +291,7 @@ NEXTNODE:
 
 		// Initialize preemptor with node
 		preemptor := NewPreemptor(iter.priority, iter.ctx, &iter.jobId)
+		preemptor.SetPriorityClasses(iter.priorityClasses)
 		preemptor.SetNode(option.Node)
 
 		// Count the number of existing preemptions
@@ -314,7 +330,7 @@ NEXTNODE:
 			offer, err := netIdx.AssignPorts(ask)
 			if err != nil {
 				// If eviction is not enabled, mark this node as exhausted and continue
-				if !iter.evict {
+				if !evict {
 					iter.ctx.Metrics().ExhaustedNode(option.Node,
 						fmt.Sprintf("network: %s", err))
 					netIdx.Release()
@@ -355,6 +371,7 @@ NEXTNODE:
 
 			// Reserve this to prevent another task from colliding
 			netIdx.AddReservedPorts(offer)
+			netIdx.AddReservedBandwidth([]*structs.NetworkResource{ask})
 
 			// Update the network ask to the offer
 			nwRes := structs.AllocatedPortsToNetworkResouce(ask, offer, option.Node.NodeResources)
@@ -390,7 +407,7 @@ NEXTNODE:
 				offer, err := netIdx.AssignTaskNetwork(ask)
 				if offer == nil {
 					// If eviction is not enabled, mark this node as exhausted and continue
-					if !iter.evict {
+					if !evict {
 						iter.ctx.Metrics().ExhaustedNode(option.Node,
 							fmt.Sprintf("network: %s", err))
 						netIdx.Release()
@@ -555,7 +572,7 @@ NEXTNODE:
 				// made attempts without preemption.
 
 				// If preemption is not enabled, then this node is exhausted.
-				if !iter.evict {
+				if !evict {
 					// surface err from createOffer()
 					iter.ctx.Metrics().ExhaustedNode(option.Node, fmt.Sprintf("devices: %s", err))
 					continue NEXTNODE
@@ -739,7 +756,7 @@ NEXTNODE:
 		netIdx.Release()
 		if !fit {
 			// Skip the node if evictions are not enabled
-			if !iter.evict {
+			if !evict {
 				iter.ctx.Metrics().ExhaustedNode(option.Node, dim)
 				continue
 			}
@@ -303,7 +303,9 @@ func (a *allocReconciler) computeDeploymentUpdates(deploymentComplete bool) {
 
 	// Set the description of a created deployment
 	if d := a.result.deployment; d != nil {
-		if d.RequiresPromotion() {
+		if d.AwaitingPreDeploy() {
+			d.StatusDescription = structs.DeploymentStatusDescriptionRunningPreDeploy
+		} else if d.RequiresPromotion() {
 			if d.HasAutoPromote() {
 				d.StatusDescription = structs.DeploymentStatusDescriptionRunningAutoPromotion
 			} else {
@@ -443,8 +445,10 @@ func (a *allocReconciler) computeGroup(groupName string, all allocSet) bool {
 	tg := a.job.LookupTaskGroup(groupName)
 
 	// If the task group is nil, then the task group has been removed so all we
-	// need to do is stop everything
-	if tg == nil {
+	// need to do is stop everything. The same goes for task groups that run
+	// the deployment hooks of other task groups, since the deployment watcher
+	// runs them as separate jobs.
+	if tg == nil || a.job.IsDeploymentHookGroup(groupName) {
 		desiredChanges.Stop = a.filterAndStopAll(all)
 		return true
 	}
@@ -458,6 +462,12 @@ func (a *allocReconciler) computeGroup(groupName string, all allocSet) bool {
 
 	canaries, all := a.cancelUnneededCanaries(all, desiredChanges)
 
+	// Keep the blue allocations of a promoted blue/green deployment running
+	// until its retention expires, so the job can be switched back to them.
+	retained := a.filterRetainedBlue(dstate, all)
+	desiredChanges.Ignore += uint64(len(retained))
+	all = all.difference(retained)
+
 	// Determine what set of allocations are on tainted nodes
 	untainted, migrate, lost, disconnecting, reconnecting, ignore, expiring := all.filterByTainted(a.taintedNodes, a.supportsDisconnectedClients, a.now)
 	desiredChanges.Ignore += uint64(len(ignore))
@@ -538,6 +548,16 @@ func (a *allocReconciler) computeGroup(groupName string, all allocSet) bool {
 		// reschedulable later and mark the allocations for in place updating
 		a.createRescheduleLaterEvals(rescheduleLater, all, tg.Name)
 	}
+	// Indexed task groups only retry as many failed indexes as their
+	// parallelism allows. The other failed allocations are kept untainted so
+	// their index isn't placed under a new name, and are retried by the
+	// evaluation created once a running allocation finishes.
+	if tg.Indexed != nil {
+		var held allocSet
+		rescheduleNow, held = rescheduleNow.filterByIndexedSlots(tg, untainted, migrate)
+		untainted = untainted.union(held)
+	}
+
 	// Create a structure for choosing names. Seed with the taken names
 	// which is the union of untainted, rescheduled, allocs on migrating
 	// nodes, and allocs on down nodes (includes canaries)
@@ -591,9 +611,14 @@ func (a *allocReconciler) computeGroup(groupName string, all allocSet) bool {
 		}
 	}
 
+	// Nothing is placed or destructively updated for the deployment until its
+	// pre-deployment hook completes.
+	awaitingPreDeploy := dstate.AwaitingPreDeploy() &&
+		a.requiresDeployment(tg.Update, existingDeployment, dstate, all, destructive)
+
 	// deploymentPlaceReady tracks whether the deployment is in a state where
 	// placements can be made without any other consideration.
-	deploymentPlaceReady := !a.deploymentPaused && !a.deploymentFailed && !isCanarying
+	deploymentPlaceReady := !a.deploymentPaused && !a.deploymentFailed && !isCanarying && !awaitingPreDeploy
 
 	underProvisionedBy = a.computeReplacements(deploymentPlaceReady, desiredChanges, place, rescheduleNow, lost, underProvisionedBy)
 
@@ -632,6 +657,12 @@ func (a *allocReconciler) initializeDeploymentState(group string, tg *structs.Ta
 			dstate.AutoRevert = tg.Update.AutoRevert
 			dstate.AutoPromote = tg.Update.AutoPromote
 			dstate.ProgressDeadline = tg.Update.ProgressDeadline
+			if tg.Update.BlueGreen != nil {
+				dstate.BlueGreen = true
+				dstate.BlueRetention = tg.Update.BlueGreen.Retention
+			}
+			dstate.PreDeploy = structs.NewDeploymentHookState(tg.Update.PreDeploy)
+			dstate.PostDeploy = structs.NewDeploymentHookState(tg.Update.PostDeploy)
 		}
 	}
 
@@ -643,16 +674,16 @@ func (a *allocReconciler) requiresCanaries(tg *structs.TaskGroup, dstate *struct
 	canariesPromoted := dstate != nil && dstate.Promoted
 	return tg.Update != nil &&
 		len(destructive) != 0 &&
-		len(canaries) < tg.Update.Canary &&
+		len(canaries) < tg.Update.DesiredCanaries(tg.Count) &&
 		!canariesPromoted
 }
 
 func (a *allocReconciler) computeCanaries(tg *structs.TaskGroup, dstate *structs.DeploymentState,
 	destructive, canaries allocSet, desiredChanges *structs.DesiredUpdates, nameIndex *allocNameIndex) {
-	dstate.DesiredCanaries = tg.Update.Canary
+	dstate.DesiredCanaries = tg.Update.DesiredCanaries(tg.Count)
 
-	if !a.deploymentPaused && !a.deploymentFailed {
-		desiredChanges.Canary += uint64(tg.Update.Canary - len(canaries))
+	if !a.deploymentPaused && !a.deploymentFailed && !dstate.AwaitingPreDeploy() {
+		desiredChanges.Canary += uint64(dstate.DesiredCanaries - len(canaries))
 		for _, name := range nameIndex.NextCanaries(uint(desiredChanges.Canary), canaries, destructive) {
 			a.result.place = append(a.result.place, allocPlaceResult{
 				name:      name,
@@ -685,6 +716,23 @@ func (a *allocReconciler) filterOldTerminalAllocs(all allocSet) (filtered, ignor
 	return filtered, ignored
 }
 
+// filterRetainedBlue returns the allocations of the group that are retained by
+// the promoted blue/green deployment of the group. These are the running
+// allocations that aren't part of the deployment.
+func (a *allocReconciler) filterRetainedBlue(dstate *structs.DeploymentState, all allocSet) allocSet {
+	if a.deployment == nil || !a.deployment.Active() || !dstate.RetainingBlue(a.now) {
+		return nil
+	}
+
+	retained := make(allocSet)
+	for id, alloc := range all {
+		if alloc.DeploymentID != a.deployment.ID && !alloc.TerminalStatus() {
+			retained[id] = alloc
+		}
+	}
+	return retained
+}
+
 // cancelUnneededCanaries handles the canaries for the group by stopping the
 // unneeded ones and returning the current set of canaries and the updated total
 // set of allocs for the group
@@ -813,6 +861,11 @@ func (a *allocReconciler) computePlacements(group *structs.TaskGroup,
 	// Add replacements for disconnected and lost allocs up to group.Count
 	existing := len(untainted) + len(migrate) + len(reschedule)
 
+	// Indexed task groups don't place more allocations than their
+	// parallelism allows
+	slots, limited := indexedSlots(group, untainted, migrate)
+	slots -= len(reschedule)
+
 	// Add replacements for lost
 	for _, alloc := range lost {
 		if existing >= group.Count {
@@ -820,8 +873,12 @@ func (a *allocReconciler) computePlacements(group *structs.TaskGroup,
 			// allocs
 			break
 		}
+		if limited && slots <= 0 {
+			break
+		}
 
 		existing++
+		slots--
 		place = append(place, allocPlaceResult{
 			name:               alloc.Name,
 			taskGroup:          group,
@@ -835,8 +892,12 @@ func (a *allocReconciler) computePlacements(group *structs.TaskGroup,
 	}
 
 	// Add remaining placement results
-	if existing < group.Count {
-		for _, name := range nameIndex.Next(uint(group.Count - existing)) {
+	remaining := group.Count - existing
+	if limited {
+		remaining = min(remaining, slots)
+	}
+	if remaining > 0 {
+		for _, name := range nameIndex.Next(uint(remaining)) {
 			place = append(place, allocPlaceResult{
 				name:               name,
 				taskGroup:          group,
@@ -848,6 +909,23 @@ func (a *allocReconciler) computePlacements(group *structs.TaskGroup,
 	return place
 }
 
+// indexedSlots returns how many more allocations of an indexed task group
+// can run at once given its untainted and migrating allocations, and whether
+// the task group limits its parallelism at all.
+func indexedSlots(group *structs.TaskGroup, untainted, migrate allocSet) (int, bool) {
+	if group.Indexed == nil || group.Indexed.Parallelism == 0 {
+		return 0, false
+	}
+
+	slots := group.Indexed.Parallelism
+	for _, alloc := range untainted.union(migrate) {
+		if !alloc.TerminalStatus() {
+			slots--
+		}
+	}
+	return max(slots, 0), true
+}
+
 // computeReplacements either applies the placements calculated by computePlacements,
 // or computes more placements based on whether the deployment is ready for placement
 // and if the placement is already rescheduling or part of a failed deployment.
@@ -964,13 +1042,32 @@ func (a *allocReconciler) computeMigrations(desiredChanges *structs.DesiredUpdat
 
 func (a *allocReconciler) createDeployment(groupName string, strategy *structs.UpdateStrategy,
 	existingDeployment bool, dstate *structs.DeploymentState, all, destructive allocSet) {
-	// Guard the simple cases that require no computation first.
-	if existingDeployment ||
-		strategy.IsEmpty() ||
-		dstate.DesiredTotal == 0 {
+	if existingDeployment || !a.requiresDeployment(strategy, existingDeployment, dstate, all, destructive) {
 		return
 	}
 
+	// A previous group may have made the deployment already. If not create one.
+	if a.deployment == nil {
+		a.deployment = structs.NewDeployment(a.job, a.evalPriority, a.now.UnixNano())
+		a.result.deployment = a.deployment
+	}
+
+	// Attach the groups deployment state to the deployment
+	a.deployment.TaskGroups[groupName] = dstate
+}
+
+// requiresDeployment returns whether the group is part of a deployment, either
+// an existing one or one that createDeployment creates.
+func (a *allocReconciler) requiresDeployment(strategy *structs.UpdateStrategy,
+	existingDeployment bool, dstate *structs.DeploymentState, all, destructive allocSet) bool {
+	// Guard the simple cases that require no computation first.
+	if existingDeployment {
+		return true
+	}
+	if strategy.IsEmpty() || dstate.DesiredTotal == 0 {
+		return false
+	}
+
 	updatingSpec := len(destructive) != 0 || len(a.result.inplaceUpdate) != 0
 
 	hadRunning := false
@@ -983,18 +1080,7 @@ func (a *allocReconciler) createDeployment(groupName string, strategy *structs.U
 
 	// Don't create a deployment if it's not the first time running the job
 	// and there are no updates to the spec.
-	if hadRunning && !updatingSpec {
-		return
-	}
-
-	// A previous group may have made the deployment already. If not create one.
-	if a.deployment == nil {
-		a.deployment = structs.NewDeployment(a.job, a.evalPriority, a.now.UnixNano())
-		a.result.deployment = a.deployment
-	}
-
-	// Attach the groups deployment state to the deployment
-	a.deployment.TaskGroups[groupName] = dstate
+	return !hadRunning || updatingSpec
 }
 
 func (a *allocReconciler) isDeploymentComplete(groupName string, destructive, inplace, migrate, rescheduleNow allocSet,
@@ -1010,7 +1096,9 @@ func (a *allocReconciler) isDeploymentComplete(groupName string, destructive, in
 	// Final check to see if the deployment is complete is to ensure everything is healthy
 	if dstate, ok := a.deployment.TaskGroups[groupName]; ok {
 		if dstate.HealthyAllocs < max(dstate.DesiredTotal, dstate.DesiredCanaries) || // Make sure we have enough healthy allocs
-			(dstate.DesiredCanaries > 0 && !dstate.Promoted) { // Make sure we are promoted if we have canaries
+			(dstate.DesiredCanaries > 0 && !dstate.Promoted) || // Make sure we are promoted if we have canaries
+			dstate.RetainingBlue(a.now) || // Make sure the blue allocations are no longer retained
+			dstate.PreDeploy.Incomplete() || dstate.PostDeploy.Incomplete() { // Make sure the deployment hooks completed
 			complete = false
 		}
 	}
@@ -1076,6 +1164,28 @@ func (a *allocReconciler) computeStop(group *structs.TaskGroup, nameIndex *alloc
 		}
 	}
 
+	// Prefer stopping the allocations that would need to be replaced if a
+	// blue/green task group is switched back to its blue allocations, so the
+	// blue allocations that are still running are kept
+	if group.Update != nil && group.Update.BlueGreen != nil {
+		for id, alloc := range untainted {
+			if !tasksUpdated(a.job, alloc.Job, group.Name).modified {
+				continue
+			}
+			stop[id] = alloc
+			a.result.stop = append(a.result.stop, allocStopResult{
+				alloc:             alloc,
+				statusDescription: allocNotNeeded,
+			})
+			delete(untainted, id)
+
+			remove--
+			if remove == 0 {
+				return stop
+			}
+		}
+	}
+
 	// Prefer selecting from the migrating set before stopping existing allocs
 	if len(migrate) != 0 {
 		migratingNames := newAllocNameIndex(a.jobID, group.Name, group.Count, migrate)
@@ -3934,6 +3934,404 @@ func TestReconciler_PromoteCanaries_CanariesEqualCount(t *testing.T) {
 	assertNamesHaveIndexes(t, intRange(0, 1), stopResultsToNames(r.stop))
 }
 
+// Tests the reconciler places the whole green set as canaries for a
+// blue/green task group
+func TestReconciler_BlueGreen_PlaceGreen(t *testing.T) {
+	ci.Parallel(t)
+
+	job := mock.Job()
+	job.TaskGroups[0].Count = 4
+	job.TaskGroups[0].Update = noCanaryUpdate.Copy()
+	job.TaskGroups[0].Update.BlueGreen = &structs.BlueGreenStrategy{Retention: time.Hour}
+
+	// Create 4 allocations from the old job
+	var allocs []*structs.Allocation
+	for i := 0; i < 4; i++ {
+		alloc := mock.Alloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.NodeID = uuid.Generate()
+		alloc.Name = structs.AllocName(job.ID, job.TaskGroups[0].Name, uint(i))
+		alloc.TaskGroup = job.TaskGroups[0].Name
+		allocs = append(allocs, alloc)
+	}
+
+	reconciler := NewAllocReconciler(testlog.HCLogger(t), allocUpdateFnDestructive, false, job.ID, job,
+		nil, allocs, nil, "", 50, true)
+	r := reconciler.Compute()
+
+	newD := structs.NewDeployment(job, 50, r.deployment.CreateTime)
+	newD.StatusDescription = structs.DeploymentStatusDescriptionRunningNeedsPromotion
+	newD.TaskGroups[job.TaskGroups[0].Name] = &structs.DeploymentState{
+		DesiredCanaries: 4,
+		DesiredTotal:    4,
+		BlueGreen:       true,
+		BlueRetention:   time.Hour,
+	}
+
+	assertResults(t, r, &resultExpectation{
+		createDeployment:  newD,
+		deploymentUpdates: nil,
+		place:             4,
+		inplace:           0,
+		stop:              0,
+		desiredTGUpdates: map[string]*structs.DesiredUpdates{
+			job.TaskGroups[0].Name: {
+				Canary: 4,
+				Ignore: 4,
+			},
+		},
+	})
+
+	for _, p := range r.place {
+		must.True(t, p.canary)
+	}
+	assertNamesHaveIndexes(t, intRange(0, 3), placeResultsToNames(r.place))
+}
+
+// Tests the reconciler keeps the blue allocations of a promoted blue/green
+// deployment until its retention expires
+func TestReconciler_BlueGreen_RetainBlue(t *testing.T) {
+	ci.Parallel(t)
+
+	job := mock.Job()
+	job.TaskGroups[0].Count = 2
+	job.TaskGroups[0].Update = noCanaryUpdate.Copy()
+	job.TaskGroups[0].Update.BlueGreen = &structs.BlueGreenStrategy{Retention: time.Hour}
+
+	now := time.Now()
+	testCases := []struct {
+		name            string
+		retainBlueUntil time.Time
+		expStop         int
+		expIgnore       uint64
+	}{
+		{
+			name:            "retaining",
+			retainBlueUntil: now.Add(time.Hour),
+			expIgnore:       4,
+		},
+		{
+			name:            "expired",
+			retainBlueUntil: now.Add(-time.Minute),
+			expStop:         2,
+			expIgnore:       2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := structs.NewDeployment(job, 50, now.UnixNano())
+			s := &structs.DeploymentState{
+				Promoted:        true,
+				DesiredTotal:    2,
+				DesiredCanaries: 2,
+				PlacedAllocs:    2,
+				HealthyAllocs:   2,
+				BlueGreen:       true,
+				BlueRetention:   time.Hour,
+				RetainBlueUntil: tc.retainBlueUntil,
+			}
+			d.TaskGroups[job.TaskGroups[0].Name] = s
+
+			// Create the blue allocations from the old job
+			var allocs []*structs.Allocation
+			for i := 0; i < 2; i++ {
+				alloc := mock.Alloc()
+				alloc.Job = job
+				alloc.JobID = job.ID
+				alloc.NodeID = uuid.Generate()
+				alloc.Name = structs.AllocName(job.ID, job.TaskGroups[0].Name, uint(i))
+				alloc.TaskGroup = job.TaskGroups[0].Name
+				alloc.DeploymentStatus = &structs.AllocDeploymentStatus{Retained: true}
+				allocs = append(allocs, alloc)
+			}
+
+			// Create the promoted green allocations
+			handled := make(map[string]allocUpdateType)
+			for i := 0; i < 2; i++ {
+				green := mock.Alloc()
+				green.Job = job
+				green.JobID = job.ID
+				green.NodeID = uuid.Generate()
+				green.Name = structs.AllocName(job.ID, job.TaskGroups[0].Name, uint(i))
+				green.TaskGroup = job.TaskGroups[0].Name
+				green.DeploymentID = d.ID
+				green.DeploymentStatus = &structs.AllocDeploymentStatus{
+					Healthy: pointer.Of(true),
+				}
+				s.PlacedCanaries = append(s.PlacedCanaries, green.ID)
+				allocs = append(allocs, green)
+				handled[green.ID] = allocUpdateFnIgnore
+			}
+
+			var expUpdates []*structs.DeploymentStatusUpdate
+			if tc.expStop != 0 {
+				expUpdates = []*structs.DeploymentStatusUpdate{{
+					DeploymentID:      d.ID,
+					Status:            structs.DeploymentStatusSuccessful,
+					StatusDescription: structs.DeploymentStatusDescriptionSuccessful,
+				}}
+			}
+
+			mockUpdateFn := allocUpdateFnMock(handled, allocUpdateFnDestructive)
+			reconciler := NewAllocReconciler(testlog.HCLogger(t), mockUpdateFn, false, job.ID, job,
+				d, allocs, nil, "", 50, true, AllocRenconcilerWithNow(now))
+			r := reconciler.Compute()
+
+			assertResults(t, r, &resultExpectation{
+				createDeployment:  nil,
+				deploymentUpdates: expUpdates,
+				place:             0,
+				destructive:       0,
+				stop:              tc.expStop,
+				desiredTGUpdates: map[string]*structs.DesiredUpdates{
+					job.TaskGroups[0].Name: {
+						Stop:   uint64(tc.expStop),
+						Ignore: tc.expIgnore,
+					},
+				},
+			})
+
+			assertNoCanariesStopped(t, d, r.stop)
+		})
+	}
+}
+
+// Tests the reconciler keeps the blue allocations and stops the green ones
+// when a blue/green task group is switched back to the blue job
+func TestReconciler_BlueGreen_SwitchBack(t *testing.T) {
+	ci.Parallel(t)
+
+	// The blue job is the job reverted to
+	job := mock.Job()
+	job.Version = 2
+	job.TaskGroups[0].Count = 2
+	job.TaskGroups[0].Update = noCanaryUpdate.Copy()
+	job.TaskGroups[0].Update.BlueGreen = &structs.BlueGreenStrategy{Retention: time.Hour}
+
+	greenJob := job.Copy()
+	greenJob.Version = 1
+	greenJob.TaskGroups[0].Tasks[0].Config["command"] = "/bin/green"
+
+	// The deployment of the green job is cancelled by the revert
+	d := structs.NewDeployment(greenJob, 50, time.Now().UnixNano())
+	d.Status = structs.DeploymentStatusCancelled
+	s := &structs.DeploymentState{
+		Promoted:        true,
+		DesiredTotal:    2,
+		DesiredCanaries: 2,
+		BlueGreen:       true,
+		RetainBlueUntil: time.Now().Add(time.Hour),
+	}
+	d.TaskGroups[job.TaskGroups[0].Name] = s
+
+	var allocs []*structs.Allocation
+	handled := make(map[string]allocUpdateType)
+	for i := 0; i < 2; i++ {
+		blue := mock.Alloc()
+		blue.Job = job
+		blue.JobID = job.ID
+		blue.NodeID = uuid.Generate()
+		blue.Name = structs.AllocName(job.ID, job.TaskGroups[0].Name, uint(i))
+		blue.TaskGroup = job.TaskGroups[0].Name
+		blue.DeploymentStatus = &structs.AllocDeploymentStatus{Retained: true}
+		allocs = append(allocs, blue)
+		handled[blue.ID] = allocUpdateFnIgnore
+
+		green := mock.Alloc()
+		green.Job = greenJob
+		green.JobID = job.ID
+		green.NodeID = uuid.Generate()
+		green.Name = structs.AllocName(job.ID, job.TaskGroups[0].Name, uint(i))
+		green.TaskGroup = job.TaskGroups[0].Name
+		green.DeploymentID = d.ID
+		green.DeploymentStatus = &structs.AllocDeploymentStatus{Healthy: pointer.Of(true)}
+		s.PlacedCanaries = append(s.PlacedCanaries, green.ID)
+		allocs = append(allocs, green)
+	}
+
+	mockUpdateFn := allocUpdateFnMock(handled, allocUpdateFnDestructive)
+	reconciler := NewAllocReconciler(testlog.HCLogger(t), mockUpdateFn, false, job.ID, job,
+		d, allocs, nil, "", 50, true)
+	r := reconciler.Compute()
+
+	assertResults(t, r, &resultExpectation{
+		createDeployment:  nil,
+		deploymentUpdates: nil,
+		place:             0,
+		destructive:       0,
+		stop:              2,
+		desiredTGUpdates: map[string]*structs.DesiredUpdates{
+			job.TaskGroups[0].Name: {
+				Stop:   2,
+				Ignore: 2,
+			},
+		},
+	})
+
+	for _, stop := range r.stop {
+		must.Eq(t, greenJob, stop.alloc.Job)
+	}
+}
+
+// Tests the reconciler doesn't update any allocation until the pre-deployment
+// hook of the deployment completed
+func TestReconciler_PreDeployHook(t *testing.T) {
+	ci.Parallel(t)
+
+	job := mock.Job()
+	job.TaskGroups[0].Count = 4
+	job.TaskGroups[0].Update = noCanaryUpdate.Copy()
+	job.TaskGroups[0].Update.PreDeploy = &structs.DeploymentHook{Job: "migrate"}
+
+	var allocs []*structs.Allocation
+	for i := 0; i < 4; i++ {
+		alloc := mock.Alloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.NodeID = uuid.Generate()
+		alloc.Name = structs.AllocName(job.ID, job.TaskGroups[0].Name, uint(i))
+		alloc.TaskGroup = job.TaskGroups[0].Name
+		allocs = append(allocs, alloc)
+	}
+
+	// The deployment is created awaiting the hook
+	reconciler := NewAllocReconciler(testlog.HCLogger(t), allocUpdateFnDestructive, false, job.ID, job,
+		nil, allocs, nil, "", 50, true)
+	r := reconciler.Compute()
+
+	newD := structs.NewDeployment(job, 50, r.deployment.CreateTime)
+	newD.StatusDescription = structs.DeploymentStatusDescriptionRunningPreDeploy
+	newD.TaskGroups[job.TaskGroups[0].Name] = &structs.DeploymentState{
+		DesiredTotal: 4,
+		PreDeploy: &structs.DeploymentHookState{
+			Status: structs.DeploymentHookStatusPending,
+			Job:    "migrate",
+		},
+	}
+
+	assertResults(t, r, &resultExpectation{
+		createDeployment:  newD,
+		deploymentUpdates: nil,
+		destructive:       0,
+		desiredTGUpdates: map[string]*structs.DesiredUpdates{
+			job.TaskGroups[0].Name: {
+				Ignore: 4,
+			},
+		},
+	})
+
+	// The allocations are updated once the hook completed
+	d := newD.Copy()
+	d.ID = uuid.Generate()
+	d.TaskGroups[job.TaskGroups[0].Name].PreDeploy.Status = structs.DeploymentHookStatusComplete
+	reconciler = NewAllocReconciler(testlog.HCLogger(t), allocUpdateFnDestructive, false, job.ID, job,
+		d, allocs, nil, "", 50, true)
+	r = reconciler.Compute()
+
+	assertResults(t, r, &resultExpectation{
+		createDeployment:  nil,
+		deploymentUpdates: nil,
+		destructive:       4,
+		desiredTGUpdates: map[string]*structs.DesiredUpdates{
+			job.TaskGroups[0].Name: {
+				DestructiveUpdate: 4,
+			},
+		},
+	})
+}
+
+// Tests the reconciler places allocations without waiting for the
+// pre-deployment hook when the change doesn't create a deployment
+func TestReconciler_PreDeployHook_NoDeployment(t *testing.T) {
+	ci.Parallel(t)
+
+	job := mock.Job()
+	job.TaskGroups[0].Count = 4
+	job.TaskGroups[0].Update = noCanaryUpdate.Copy()
+	job.TaskGroups[0].Update.PreDeploy = &structs.DeploymentHook{Job: "migrate"}
+
+	// Scale up a job whose allocations already run the current version
+	var allocs []*structs.Allocation
+	for i := 0; i < 2; i++ {
+		alloc := mock.Alloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.NodeID = uuid.Generate()
+		alloc.Name = structs.AllocName(job.ID, job.TaskGroups[0].Name, uint(i))
+		alloc.TaskGroup = job.TaskGroups[0].Name
+		allocs = append(allocs, alloc)
+	}
+
+	reconciler := NewAllocReconciler(testlog.HCLogger(t), allocUpdateFnIgnore, false, job.ID, job,
+		nil, allocs, nil, "", 50, true)
+	r := reconciler.Compute()
+
+	assertResults(t, r, &resultExpectation{
+		createDeployment:  nil,
+		deploymentUpdates: nil,
+		place:             2,
+		desiredTGUpdates: map[string]*structs.DesiredUpdates{
+			job.TaskGroups[0].Name: {
+				Place:  2,
+				Ignore: 2,
+			},
+		},
+	})
+}
+
+// Tests the reconciler doesn't place the task group running the
+// pre-deployment hook of another task group, and stops its allocations
+func TestReconciler_PreDeployHook_Group(t *testing.T) {
+	ci.Parallel(t)
+
+	job := mock.Job()
+	job.TaskGroups[0].Count = 2
+	job.TaskGroups[0].Update = noCanaryUpdate.Copy()
+	job.TaskGroups[0].Update.PreDeploy = &structs.DeploymentHook{Group: "migrate"}
+
+	hookGroup := job.TaskGroups[0].Copy()
+	hookGroup.Name = "migrate"
+	hookGroup.Count = 1
+	hookGroup.Update = nil
+	job.TaskGroups = append(job.TaskGroups, hookGroup)
+
+	// An allocation of the hook task group placed before it was a hook
+	alloc := mock.Alloc()
+	alloc.Job = job
+	alloc.JobID = job.ID
+	alloc.NodeID = uuid.Generate()
+	alloc.Name = structs.AllocName(job.ID, hookGroup.Name, 0)
+	alloc.TaskGroup = hookGroup.Name
+
+	reconciler := NewAllocReconciler(testlog.HCLogger(t), allocUpdateFnIgnore, false, job.ID, job,
+		nil, []*structs.Allocation{alloc}, nil, "", 50, true)
+	r := reconciler.Compute()
+
+	newD := structs.NewDeployment(job, 50, r.deployment.CreateTime)
+	newD.StatusDescription = structs.DeploymentStatusDescriptionRunningPreDeploy
+	newD.TaskGroups[job.TaskGroups[0].Name] = &structs.DeploymentState{
+		DesiredTotal: 2,
+		PreDeploy: &structs.DeploymentHookState{
+			Status: structs.DeploymentHookStatusPending,
+			Group:  "migrate",
+		},
+	}
+
+	assertResults(t, r, &resultExpectation{
+		createDeployment:  newD,
+		deploymentUpdates: nil,
+		stop:              1,
+		desiredTGUpdates: map[string]*structs.DesiredUpdates{
+			job.TaskGroups[0].Name: {},
+			hookGroup.Name: {
+				Stop: 1,
+			},
+		},
+	})
+}
+
 // Tests the reconciler checks the health of placed allocs to determine the
 // limit
 func TestReconciler_DeploymentLimit_HealthAccounting(t *testing.T) {
@@ -6203,3 +6601,141 @@ func TestReconciler_ComputeDeploymentPaused(t *testing.T) {
 		})
 	}
 }
+
+// indexedTestAllocs returns an allocation of the batch job for each client
+// status, named after its position.
+func indexedTestAllocs(job *structs.Job, now time.Time, statuses ...string) []*structs.Allocation {
+	var allocs []*structs.Allocation
+	for i, status := range statuses {
+		alloc := mock.Alloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.NodeID = uuid.Generate()
+		alloc.Name = structs.AllocName(job.ID, job.TaskGroups[0].Name, uint(i))
+		alloc.ClientStatus = status
+		alloc.TaskStates = map[string]*structs.TaskState{"web": {
+			State:      structs.TaskStateDead,
+			StartedAt:  now.Add(-1 * time.Hour),
+			FinishedAt: now.Add(-10 * time.Second),
+		}}
+		allocs = append(allocs, alloc)
+	}
+	return allocs
+}
+
+// Tests that indexed task groups don't run more allocations than their
+// parallelism, and retry failed indexes before placing new ones
+func TestReconciler_Indexed_Parallelism(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name     string
+		statuses []string
+		expected *resultExpectation
+		indexes  []int
+	}{
+		{
+			name: "failed index is retried",
+			statuses: []string{
+				structs.AllocClientStatusComplete,
+				structs.AllocClientStatusRunning,
+				structs.AllocClientStatusFailed,
+			},
+			expected: &resultExpectation{
+				place: 1,
+				stop:  1,
+				desiredTGUpdates: map[string]*structs.DesiredUpdates{
+					"web": {Place: 1, Stop: 1, Ignore: 2},
+				},
+			},
+			indexes: []int{2},
+		},
+		{
+			name: "failed index is held back",
+			statuses: []string{
+				structs.AllocClientStatusComplete,
+				structs.AllocClientStatusRunning,
+				structs.AllocClientStatusFailed,
+				structs.AllocClientStatusRunning,
+			},
+			expected: &resultExpectation{
+				desiredTGUpdates: map[string]*structs.DesiredUpdates{
+					"web": {Ignore: 4},
+				},
+			},
+		},
+		{
+			name: "new indexes up to parallelism",
+			statuses: []string{
+				structs.AllocClientStatusComplete,
+				structs.AllocClientStatusComplete,
+			},
+			expected: &resultExpectation{
+				place: 2,
+				desiredTGUpdates: map[string]*structs.DesiredUpdates{
+					"web": {Place: 2, Ignore: 2},
+				},
+			},
+			indexes: []int{2, 3},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			now := time.Now()
+			job := mock.BatchJob()
+			job.TaskGroups[0].Count = 5
+			job.TaskGroups[0].Indexed = &structs.IndexedPolicy{Parallelism: 2, MaxFailures: 3}
+			job.TaskGroups[0].ReschedulePolicy = &structs.ReschedulePolicy{Delay: 5 * time.Second, DelayFunction: "constant"}
+
+			allocs := indexedTestAllocs(job, now, tc.statuses...)
+
+			reconciler := NewAllocReconciler(testlog.HCLogger(t), allocUpdateFnIgnore, true, job.ID, job,
+				nil, allocs, nil, "", 50, true)
+			reconciler.now = now
+			r := reconciler.Compute()
+
+			assertResults(t, r, tc.expected)
+			assertNamesHaveIndexes(t, tc.indexes, placeResultsToNames(r.place))
+		})
+	}
+}
+
+// Tests that indexes of indexed task groups are retried until they reach
+// their maximum number of failures, regardless of the reschedule policy
+func TestReconciler_Indexed_MaxFailures(t *testing.T) {
+	ci.Parallel(t)
+
+	now := time.Now()
+	job := mock.BatchJob()
+	job.TaskGroups[0].Count = 3
+	job.TaskGroups[0].Indexed = &structs.IndexedPolicy{MaxFailures: 2}
+	job.TaskGroups[0].ReschedulePolicy = &structs.ReschedulePolicy{Delay: 5 * time.Second, DelayFunction: "constant"}
+
+	allocs := indexedTestAllocs(job, now,
+		structs.AllocClientStatusFailed,
+		structs.AllocClientStatusFailed,
+		structs.AllocClientStatusComplete,
+	)
+
+	// The first index already failed twice
+	allocs[0].RescheduleTracker = &structs.RescheduleTracker{Events: []*structs.RescheduleEvent{
+		{RescheduleTime: now.Add(-48 * time.Hour).UnixNano(), PrevAllocID: uuid.Generate()},
+	}}
+
+	reconciler := NewAllocReconciler(testlog.HCLogger(t), allocUpdateFnIgnore, true, job.ID, job,
+		nil, allocs, nil, "", 50, true)
+	reconciler.now = now
+	r := reconciler.Compute()
+
+	must.Nil(t, r.desiredFollowupEvals[job.TaskGroups[0].Name])
+	assertResults(t, r, &resultExpectation{
+		place: 1,
+		stop:  1,
+		desiredTGUpdates: map[string]*structs.DesiredUpdates{
+			job.TaskGroups[0].Name: {Place: 1, Stop: 1, Ignore: 2},
+		},
+	})
+	assertNamesHaveIndexes(t, intRange(1, 1), placeResultsToNames(r.place))
+	assertPlacementsAreRescheduled(t, 1, r.place)
+}
@@ -439,6 +439,39 @@ func (a allocSet) filterByRescheduleable(isBatch, isDisconnecting bool, now time
 	return untainted, rescheduleNow, rescheduleLater
 }
 
+// filterByIndexedSlots splits the allocations to reschedule of an indexed task
+// group into the ones that fit within its parallelism and the ones that must
+// wait for running allocations to finish. Failed allocations are retried by
+// order of index, while the replacements of disconnected allocations are
+// never held back.
+func (a allocSet) filterByIndexedSlots(group *structs.TaskGroup, untainted, migrate allocSet) (reschedule, held allocSet) {
+	slots, limited := indexedSlots(group, untainted, migrate)
+	if !limited {
+		return a, nil
+	}
+
+	reschedule = make(map[string]*structs.Allocation)
+	held = make(map[string]*structs.Allocation)
+	for _, alloc := range a.nameOrder() {
+		if alloc.ClientStatus != structs.AllocClientStatusFailed {
+			reschedule[alloc.ID] = alloc
+			slots--
+		}
+	}
+	for _, alloc := range a.nameOrder() {
+		if alloc.ClientStatus != structs.AllocClientStatusFailed {
+			continue
+		}
+		if slots > 0 {
+			reschedule[alloc.ID] = alloc
+			slots--
+		} else {
+			held[alloc.ID] = alloc
+		}
+	}
+	return reschedule, held
+}
+
 // shouldFilter returns whether the alloc should be ignored or considered untainted.
 //
 // Ignored allocs are filtered out.
@@ -99,6 +99,9 @@ type State interface {
 	// GetJobByID is used to lookup a job by ID
 	JobByID(ws memdb.WatchSet, namespace, id string) (*structs.Job, error)
 
+	// JobSummaryByID returns the summary of the job
+	JobSummaryByID(ws memdb.WatchSet, namespace, jobID string) (*structs.JobSummary, error)
+
 	// DeploymentsByJobID returns the deployments associated with the job
 	DeploymentsByJobID(ws memdb.WatchSet, namespace, jobID string, all bool) ([]*structs.Deployment, error)
 
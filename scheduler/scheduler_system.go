@@ -152,6 +152,9 @@ func (s *SystemScheduler) process() (bool, error) {
 
 	// Create an evaluation context
 	s.ctx = NewEvalContext(s.eventsCh, s.state, s.plan, s.logger)
+	if s.eval.ExplainPlan {
+		s.ctx.ExplainPlacements()
+	}
 
 	// Construct the placement stack
 	s.stack = NewSystemStack(s.sysbatch, s.ctx)
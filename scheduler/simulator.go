@@ -0,0 +1,307 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package scheduler
+
+import (
+	"fmt"
+	"time"
+
+	log "github.com/hashicorp/go-hclog"
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/nomad/helper/pointer"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// SimulatedEval is the outcome of processing a single evaluation with the
+// Simulator.
+type SimulatedEval struct {
+	// Eval is the evaluation as last updated by the scheduler.
+	Eval *structs.Evaluation
+
+	// Plans are the plans submitted by the scheduler while processing the
+	// evaluation.
+	Plans []*structs.Plan
+
+	// CreatedEvals are the evaluations created by the scheduler, such as
+	// blocked evaluations for allocations that could not be placed.
+	CreatedEvals []*structs.Evaluation
+}
+
+// Simulator runs the schedulers against a state store that isn't backed by
+// raft, such as one restored from a snapshot. Changes to the cluster are made
+// directly to the state store and the plans are applied to it without
+// verification, so later evaluations see the placements of earlier ones.
+// Nothing is ever submitted to the servers.
+type Simulator struct {
+	logger  log.Logger
+	planner *simulatedPlanner
+
+	// pending are the evaluations created by the changes made to the
+	// cluster, in the order they will be processed.
+	pending []*structs.Evaluation
+}
+
+// NewSimulator returns a Simulator that modifies the given state store.
+func NewSimulator(logger log.Logger, store *state.StateStore) (*Simulator, error) {
+	index, err := store.LatestIndex()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read latest index: %w", err)
+	}
+
+	return &Simulator{
+		logger: logger.Named("simulator"),
+		planner: &simulatedPlanner{
+			store: store,
+			index: index,
+		},
+	}, nil
+}
+
+// State returns the state store used by the simulation.
+func (s *Simulator) State() *state.StateStore {
+	return s.planner.store
+}
+
+// SetSchedulerConfig replaces the scheduler configuration used by the
+// simulation.
+func (s *Simulator) SetSchedulerConfig(config *structs.SchedulerConfiguration) error {
+	return s.planner.store.SchedulerSetConfig(s.planner.nextIndex(), config)
+}
+
+// RegisterJob registers or updates the job and creates the evaluation the
+// servers would create for it.
+func (s *Simulator) RegisterJob(job *structs.Job) error {
+	store := s.planner.store
+	ws := memdb.NewWatchSet()
+
+	existing, err := store.JobByID(ws, job.Namespace, job.ID)
+	if err != nil {
+		return fmt.Errorf("failed to lookup job %q: %w", job.ID, err)
+	}
+
+	// Only insert the job if it has changed, so that the scheduler can
+	// reuse the existing deployment like it would for an unchanged job
+	if existing == nil || existing.SpecChanged(job) {
+		if err := store.UpsertJob(structs.IgnoreUnknownTypeFlag, s.planner.nextIndex(), nil, job); err != nil {
+			return fmt.Errorf("failed to register job %q: %w", job.ID, err)
+		}
+	}
+
+	job, err = store.JobByID(ws, job.Namespace, job.ID)
+	if err != nil {
+		return fmt.Errorf("failed to lookup job %q: %w", job.ID, err)
+	}
+
+	now := time.Now().UTC().UnixNano()
+	return s.enqueue(&structs.Evaluation{
+		ID:             uuid.Generate(),
+		Namespace:      job.Namespace,
+		Priority:       job.Priority,
+		Type:           job.Type,
+		TriggeredBy:    structs.EvalTriggerJobRegister,
+		JobID:          job.ID,
+		JobModifyIndex: job.JobModifyIndex,
+		Status:         structs.EvalStatusPending,
+		CreateTime:     now,
+		ModifyTime:     now,
+	})
+}
+
+// DrainNode marks the node as draining and migrates all of its allocations at
+// once, as if the drain deadline had been reached.
+func (s *Simulator) DrainNode(nodeID string) error {
+	store := s.planner.store
+	ws := memdb.NewWatchSet()
+
+	now := time.Now().UTC()
+	drain := &structs.DrainStrategy{
+		DrainSpec: structs.DrainSpec{
+			Deadline: -1,
+		},
+		StartedAt: now,
+	}
+	err := store.UpdateNodeDrain(structs.IgnoreUnknownTypeFlag, s.planner.nextIndex(),
+		nodeID, drain, false, now.Unix(), nil, nil, "")
+	if err != nil {
+		return fmt.Errorf("failed to drain node %q: %w", nodeID, err)
+	}
+
+	allocs, err := store.AllocsByNode(ws, nodeID)
+	if err != nil {
+		return fmt.Errorf("failed to lookup allocations for node %q: %w", nodeID, err)
+	}
+
+	transitions := make(map[string]*structs.DesiredTransition)
+	jobs := make(map[structs.NamespacedID]struct{})
+	var evals []*structs.Evaluation
+	for _, alloc := range allocs {
+		if alloc.TerminalStatus() || alloc.Job == nil {
+			continue
+		}
+		transitions[alloc.ID] = &structs.DesiredTransition{
+			Migrate: pointer.Of(true),
+		}
+
+		jobID := structs.NamespacedID{ID: alloc.JobID, Namespace: alloc.Namespace}
+		if _, ok := jobs[jobID]; ok {
+			continue
+		}
+		jobs[jobID] = struct{}{}
+
+		evals = append(evals, &structs.Evaluation{
+			ID:          uuid.Generate(),
+			Namespace:   alloc.Namespace,
+			Priority:    alloc.Job.Priority,
+			Type:        alloc.Job.Type,
+			TriggeredBy: structs.EvalTriggerNodeDrain,
+			JobID:       alloc.JobID,
+			Status:      structs.EvalStatusPending,
+			CreateTime:  now.UnixNano(),
+			ModifyTime:  now.UnixNano(),
+		})
+	}
+
+	if len(transitions) == 0 {
+		return nil
+	}
+
+	err = store.UpdateAllocsDesiredTransitions(structs.IgnoreUnknownTypeFlag,
+		s.planner.nextIndex(), transitions, evals)
+	if err != nil {
+		return fmt.Errorf("failed to migrate allocations for node %q: %w", nodeID, err)
+	}
+	s.pending = append(s.pending, evals...)
+	return nil
+}
+
+// enqueue inserts the evaluation into the state store and queues it for
+// processing.
+func (s *Simulator) enqueue(eval *structs.Evaluation) error {
+	err := s.planner.store.UpsertEvals(structs.IgnoreUnknownTypeFlag, s.planner.nextIndex(),
+		[]*structs.Evaluation{eval})
+	if err != nil {
+		return fmt.Errorf("failed to create evaluation: %w", err)
+	}
+	s.pending = append(s.pending, eval)
+	return nil
+}
+
+// Run processes the pending evaluations in order and returns their outcome.
+// Evaluations created by the schedulers, such as blocked or follow-up
+// evaluations, are returned but not processed.
+func (s *Simulator) Run() ([]*SimulatedEval, error) {
+	var results []*SimulatedEval
+
+	for len(s.pending) > 0 {
+		eval := s.pending[0]
+		s.pending = s.pending[1:]
+
+		snap, err := s.planner.store.Snapshot()
+		if err != nil {
+			return nil, fmt.Errorf("failed to snapshot state: %w", err)
+		}
+
+		result := &SimulatedEval{Eval: eval}
+		s.planner.result = result
+
+		sched, err := NewScheduler(eval.Type, s.logger, nil, snap, s.planner)
+		if err != nil {
+			return nil, err
+		}
+		if err := sched.Process(eval); err != nil {
+			return nil, fmt.Errorf("failed to process evaluation %q: %w", eval.ID, err)
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// simulatedPlanner is the Planner used by the Simulator. Plans are applied to
+// the state store as submitted, and evaluation updates are recorded in the
+// result of the evaluation being processed instead of being written.
+type simulatedPlanner struct {
+	store *state.StateStore
+
+	// index is the last raft index used to modify the state store
+	index uint64
+
+	// result is the outcome of the evaluation being processed
+	result *SimulatedEval
+}
+
+// nextIndex returns the index of the next change to the state store.
+func (p *simulatedPlanner) nextIndex() uint64 {
+	p.index++
+	return p.index
+}
+
+func (p *simulatedPlanner) SubmitPlan(plan *structs.Plan) (*structs.PlanResult, State, error) {
+	p.result.Plans = append(p.result.Plans, plan)
+
+	index := p.nextIndex()
+	result := &structs.PlanResult{
+		NodeUpdate:      plan.NodeUpdate,
+		NodeAllocation:  plan.NodeAllocation,
+		NodePreemptions: plan.NodePreemptions,
+		AllocIndex:      index,
+	}
+
+	req := structs.ApplyPlanResultsRequest{
+		AllocUpdateRequest: structs.AllocUpdateRequest{
+			Job: plan.Job,
+		},
+		Deployment:        plan.Deployment,
+		DeploymentUpdates: plan.DeploymentUpdates,
+		EvalID:            plan.EvalID,
+	}
+
+	now := time.Now().UTC().UnixNano()
+	for _, allocs := range plan.NodeAllocation {
+		for _, alloc := range allocs {
+			if alloc.CreateTime == 0 {
+				alloc.CreateTime = now
+			}
+			req.AllocsUpdated = append(req.AllocsUpdated, alloc)
+		}
+	}
+	for _, allocs := range plan.NodeUpdate {
+		for _, alloc := range allocs {
+			req.AllocsStopped = append(req.AllocsStopped, alloc.AllocationDiff())
+		}
+	}
+	for _, allocs := range plan.NodePreemptions {
+		for _, alloc := range allocs {
+			diff := alloc.AllocationDiff()
+			diff.ModifyTime = now
+			req.AllocsPreempted = append(req.AllocsPreempted, diff)
+		}
+	}
+
+	if err := p.store.UpsertPlanResults(structs.IgnoreUnknownTypeFlag, index, &req); err != nil {
+		return nil, nil, fmt.Errorf("failed to apply plan: %w", err)
+	}
+	return result, nil, nil
+}
+
+func (p *simulatedPlanner) UpdateEval(eval *structs.Evaluation) error {
+	p.result.Eval = eval
+	return nil
+}
+
+func (p *simulatedPlanner) CreateEval(eval *structs.Evaluation) error {
+	p.result.CreatedEvals = append(p.result.CreatedEvals, eval)
+	return nil
+}
+
+func (p *simulatedPlanner) ReblockEval(*structs.Evaluation) error {
+	return nil
+}
+
+func (p *simulatedPlanner) ServersMeetMinimumVersion(*version.Version, bool) bool {
+	return true
+}
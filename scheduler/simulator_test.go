@@ -0,0 +1,121 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+func TestSimulator_RegisterJob(t *testing.T) {
+	ci.Parallel(t)
+
+	store := state.TestStateStore(t)
+	for i := 0; i < 3; i++ {
+		must.NoError(t, store.UpsertNode(structs.MsgTypeTestSetup, uint64(100+i), mock.Node()))
+	}
+
+	sim, err := NewSimulator(testlog.HCLogger(t), store)
+	must.NoError(t, err)
+
+	job := mock.Job()
+	must.NoError(t, sim.RegisterJob(job))
+
+	results, err := sim.Run()
+	must.NoError(t, err)
+	must.Len(t, 1, results)
+
+	result := results[0]
+	must.Eq(t, structs.EvalStatusComplete, result.Eval.Status)
+	must.Len(t, 1, result.Plans)
+	must.MapLen(t, 0, result.Eval.FailedTGAllocs)
+
+	var placed int
+	for _, allocs := range result.Plans[0].NodeAllocation {
+		placed += len(allocs)
+	}
+	must.Eq(t, job.TaskGroups[0].Count, placed)
+
+	// The placements are applied to the simulated state only
+	allocs, err := store.AllocsByJob(nil, job.Namespace, job.ID, false)
+	must.NoError(t, err)
+	must.Len(t, placed, allocs)
+}
+
+func TestSimulator_DrainNode(t *testing.T) {
+	ci.Parallel(t)
+
+	store := state.TestStateStore(t)
+	nodes := []*structs.Node{mock.Node(), mock.Node()}
+	for i, node := range nodes {
+		must.NoError(t, store.UpsertNode(structs.MsgTypeTestSetup, uint64(100+i), node))
+	}
+
+	job := mock.Job()
+	job.TaskGroups[0].Count = 2
+	must.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 200, nil, job))
+
+	var allocs []*structs.Allocation
+	for i := 0; i < 2; i++ {
+		alloc := mock.Alloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.NodeID = nodes[0].ID
+		alloc.Name = structs.AllocName(job.ID, job.TaskGroups[0].Name, uint(i))
+		alloc.ClientStatus = structs.AllocClientStatusRunning
+		allocs = append(allocs, alloc)
+	}
+	must.NoError(t, store.UpsertAllocs(structs.MsgTypeTestSetup, 300, allocs))
+
+	sim, err := NewSimulator(testlog.HCLogger(t), store)
+	must.NoError(t, err)
+	must.NoError(t, sim.DrainNode(nodes[0].ID))
+
+	results, err := sim.Run()
+	must.NoError(t, err)
+	must.Len(t, 1, results)
+
+	result := results[0]
+	must.Eq(t, structs.EvalTriggerNodeDrain, result.Eval.TriggeredBy)
+	must.Len(t, 1, result.Plans)
+
+	plan := result.Plans[0]
+	must.Len(t, 2, plan.NodeUpdate[nodes[0].ID])
+	must.Len(t, 2, plan.NodeAllocation[nodes[1].ID])
+	must.MapNotContainsKey(t, plan.NodeAllocation, nodes[0].ID)
+
+	node, err := store.NodeByID(nil, nodes[0].ID)
+	must.NoError(t, err)
+	must.NotNil(t, node.DrainStrategy)
+	must.Eq(t, structs.NodeSchedulingIneligible, node.SchedulingEligibility)
+}
+
+func TestSimulator_PlacementFailure(t *testing.T) {
+	ci.Parallel(t)
+
+	store := state.TestStateStore(t)
+	must.NoError(t, store.UpsertNode(structs.MsgTypeTestSetup, 100, mock.Node()))
+
+	sim, err := NewSimulator(testlog.HCLogger(t), store)
+	must.NoError(t, err)
+
+	job := mock.Job()
+	job.TaskGroups[0].Tasks[0].Resources.CPU = 1 << 20
+	must.NoError(t, sim.RegisterJob(job))
+
+	results, err := sim.Run()
+	must.NoError(t, err)
+	must.Len(t, 1, results)
+
+	result := results[0]
+	must.MapContainsKey(t, result.Eval.FailedTGAllocs, job.TaskGroups[0].Name)
+	must.Len(t, 1, result.CreatedEvals)
+	must.Eq(t, structs.EvalStatusBlocked, result.CreatedEvals[0].Status)
+}
@@ -0,0 +1,273 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package scheduler
+
+import (
+	"fmt"
+	"strings"
+
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// topologyDomainSep separates the attribute values of nested topology
+// domains in a domain key.
+const topologyDomainSep = "\x1f"
+
+// TopologySpreadIterator is a FeasibleIterator which returns nodes that pass
+// the topology_spread blocks of the job and task group. A node is filtered if
+// placing an allocation on it would make its topology domain exceed the
+// maximum skew allowed against the sibling domains with the fewest
+// allocations of the task group.
+type TopologySpreadIterator struct {
+	ctx    Context
+	source FeasibleIterator
+	job    *structs.Job
+	tg     *structs.TaskGroup
+
+	// nodes are the nodes being considered for placement, used to discover
+	// the topology domains.
+	nodes []*structs.Node
+
+	// jobSpreads are the topology spreads set at the job level.
+	jobSpreads []*structs.TopologySpread
+
+	// spreads are the topology spreads of the current task group, starting
+	// with the ones set at the job level.
+	spreads []*structs.TopologySpread
+
+	// domains is a memoized map from task group to the topology domains of
+	// its feasible nodes, indexed by the key of their parent domain.
+	domains map[string]map[string]map[string]struct{}
+
+	// counts is the number of allocations of the task group in each domain.
+	// It is computed on the first call to Next after the plan may have
+	// changed.
+	counts map[string]int
+
+	// nodeKeys caches the domain keys of the nodes used by allocations.
+	nodeKeys map[string][]string
+}
+
+// NewTopologySpreadIterator creates a TopologySpreadIterator from a source.
+func NewTopologySpreadIterator(ctx Context, source FeasibleIterator) *TopologySpreadIterator {
+	return &TopologySpreadIterator{
+		ctx:      ctx,
+		source:   source,
+		domains:  make(map[string]map[string]map[string]struct{}),
+		nodeKeys: make(map[string][]string),
+	}
+}
+
+// SetNodes sets the nodes used to discover the topology domains.
+func (iter *TopologySpreadIterator) SetNodes(nodes []*structs.Node) {
+	iter.nodes = nodes
+	iter.domains = make(map[string]map[string]map[string]struct{})
+}
+
+func (iter *TopologySpreadIterator) SetJob(job *structs.Job) {
+	iter.job = job
+	iter.jobSpreads = job.TopologySpreads
+	iter.domains = make(map[string]map[string]map[string]struct{})
+	iter.nodeKeys = make(map[string][]string)
+}
+
+func (iter *TopologySpreadIterator) SetTaskGroup(tg *structs.TaskGroup) {
+	iter.tg = tg
+	iter.counts = nil
+
+	iter.spreads = make([]*structs.TopologySpread, 0, len(iter.jobSpreads)+len(tg.TopologySpreads))
+	iter.spreads = append(iter.spreads, iter.jobSpreads...)
+	iter.spreads = append(iter.spreads, tg.TopologySpreads...)
+	iter.nodeKeys = make(map[string][]string)
+
+	if len(iter.spreads) != 0 {
+		if _, ok := iter.domains[tg.Name]; !ok {
+			iter.domains[tg.Name] = iter.buildDomains(tg)
+		}
+	}
+}
+
+func (iter *TopologySpreadIterator) Next() *structs.Node {
+	for {
+		// Get the next option from the source
+		option := iter.source.Next()
+
+		// Hot path if there is nothing to check
+		if option == nil || len(iter.spreads) == 0 {
+			return option
+		}
+
+		if iter.counts == nil {
+			iter.counts = iter.countAllocs()
+		}
+
+		if ok, reason := iter.satisfiesTopologySpreads(option); !ok {
+			iter.ctx.Metrics().FilterNode(option, "topology-spread", reason)
+			continue
+		}
+
+		return option
+	}
+}
+
+func (iter *TopologySpreadIterator) Reset() {
+	iter.source.Reset()
+
+	// The plan may have changed since the last placement
+	iter.counts = nil
+}
+
+// satisfiesTopologySpreads returns whether placing an allocation on the node
+// keeps the skew of every level within its limit. If not, the reason is
+// returned.
+func (iter *TopologySpreadIterator) satisfiesTopologySpreads(option *structs.Node) (bool, string) {
+	values, missing := iter.nodeValues(option)
+	if missing != "" {
+		return false, fmt.Sprintf("missing property %q", missing)
+	}
+
+	domains := iter.domains[iter.tg.Name]
+	keys := topologyDomainKeys(values)
+
+	var parent string
+	for i, key := range keys {
+		spread := iter.spreads[i]
+
+		// The domain of the option is always considered, even if it wasn't
+		// found when building the domains
+		minCount := iter.counts[key]
+		for sibling := range domains[parent] {
+			minCount = min(minCount, iter.counts[sibling])
+		}
+
+		if skew := iter.counts[key] + 1 - minCount; skew > spread.MaxSkew {
+			return false, fmt.Sprintf("topology_spread: %s=%s skew %d exceeds %d",
+				spread.Attribute, values[i], skew, spread.MaxSkew)
+		}
+		parent = key
+	}
+
+	return true, ""
+}
+
+// buildDomains returns the topology domains of the nodes that satisfy the
+// constraints of the job and task group, indexed by the key of their parent
+// domain.
+func (iter *TopologySpreadIterator) buildDomains(tg *structs.TaskGroup) map[string]map[string]struct{} {
+	checker := NewConstraintChecker(iter.ctx, nil)
+	constraints := append(taskGroupConstraints(tg).constraints, iter.job.Constraints...)
+
+	domains := make(map[string]map[string]struct{})
+NODES:
+	for _, node := range iter.nodes {
+		for _, c := range constraints {
+			if !checker.meetsConstraint(c, node) {
+				continue NODES
+			}
+		}
+
+		values, missing := iter.nodeValues(node)
+		if missing != "" {
+			continue
+		}
+
+		var parent string
+		for _, key := range topologyDomainKeys(values) {
+			if domains[parent] == nil {
+				domains[parent] = make(map[string]struct{})
+			}
+			domains[parent][key] = struct{}{}
+			parent = key
+		}
+	}
+	return domains
+}
+
+// countAllocs returns the number of allocations of the task group in each
+// domain, taking into account the placements and stops in the plan so that
+// rolling updates and reschedules are honored.
+func (iter *TopologySpreadIterator) countAllocs() map[string]int {
+	counts := make(map[string]int)
+
+	ws := memdb.NewWatchSet()
+	existing, err := iter.ctx.State().AllocsByJob(ws, iter.job.Namespace, iter.job.ID, false)
+	if err != nil {
+		iter.ctx.Logger().Named("topology_spread").Error("failed to get job's allocations", "error", err)
+		return counts
+	}
+
+	// Track the node of each allocation by ID, so that allocations updated
+	// in the plan aren't counted twice
+	placed := make(map[string]string)
+	for _, alloc := range existing {
+		if alloc.TaskGroup == iter.tg.Name && !alloc.TerminalStatus() {
+			placed[alloc.ID] = alloc.NodeID
+		}
+	}
+
+	plan := iter.ctx.Plan()
+	for _, stops := range plan.NodeUpdate {
+		for _, alloc := range stops {
+			delete(placed, alloc.ID)
+		}
+	}
+	for nodeID, allocs := range plan.NodeAllocation {
+		for _, alloc := range allocs {
+			if alloc.TaskGroup == iter.tg.Name && alloc.JobID == iter.job.ID &&
+				alloc.Namespace == iter.job.Namespace && !alloc.TerminalStatus() {
+				placed[alloc.ID] = nodeID
+			}
+		}
+	}
+
+	for _, nodeID := range placed {
+		keys, ok := iter.nodeKeys[nodeID]
+		if !ok {
+			node, err := iter.ctx.State().NodeByID(ws, nodeID)
+			if err != nil {
+				iter.ctx.Logger().Named("topology_spread").Error("failed to lookup node", "node_id", nodeID, "error", err)
+				continue
+			}
+			if node != nil {
+				if values, missing := iter.nodeValues(node); missing == "" {
+					keys = topologyDomainKeys(values)
+				}
+			}
+			iter.nodeKeys[nodeID] = keys
+		}
+
+		for _, key := range keys {
+			counts[key]++
+		}
+	}
+
+	return counts
+}
+
+// nodeValues returns the value of the attribute of each topology spread for
+// the node. If the node is missing one of them, the attribute is returned
+// instead.
+func (iter *TopologySpreadIterator) nodeValues(node *structs.Node) ([]string, string) {
+	values := make([]string, 0, len(iter.spreads))
+	for _, spread := range iter.spreads {
+		value, ok := getProperty(node, spread.Attribute)
+		if !ok {
+			return nil, spread.Attribute
+		}
+		values = append(values, value)
+	}
+	return values, ""
+}
+
+// topologyDomainKeys returns the key of the domain of each level given the
+// attribute values of a node. Each key includes the values of the outer
+// levels so that domains are nested.
+func topologyDomainKeys(values []string) []string {
+	keys := make([]string, len(values))
+	for i := range values {
+		keys[i] = strings.Join(values[:i+1], topologyDomainSep)
+	}
+	return keys
+}
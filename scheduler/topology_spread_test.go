@@ -0,0 +1,170 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package scheduler
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+func TestTopologySpreadIterator(t *testing.T) {
+	ci.Parallel(t)
+
+	// Two datacenters with two racks each, and a node per rack
+	state, ctx := testContext(t)
+	var nodes []*structs.Node
+	for i := 0; i < 4; i++ {
+		node := mock.Node()
+		node.Datacenter = fmt.Sprintf("dc%d", i/2+1)
+		node.Meta["rack"] = fmt.Sprintf("r%d", i%2+1)
+		must.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, uint64(100+i), node))
+		nodes = append(nodes, node)
+	}
+
+	tg := &structs.TaskGroup{
+		Name: "web",
+		TopologySpreads: []*structs.TopologySpread{
+			{Attribute: "${meta.rack}", MaxSkew: 1},
+		},
+	}
+	job := &structs.Job{
+		ID:        "foo",
+		Namespace: structs.DefaultNamespace,
+		TopologySpreads: []*structs.TopologySpread{
+			{Attribute: "${node.datacenter}", MaxSkew: 1},
+		},
+		TaskGroups: []*structs.TaskGroup{tg},
+	}
+
+	alloc := func(node *structs.Node) *structs.Allocation {
+		return &structs.Allocation{
+			ID:        uuid.Generate(),
+			EvalID:    uuid.Generate(),
+			Namespace: job.Namespace,
+			JobID:     job.ID,
+			Job:       job,
+			TaskGroup: tg.Name,
+			NodeID:    node.ID,
+		}
+	}
+
+	static := NewStaticIterator(ctx, nodes)
+	iter := NewTopologySpreadIterator(ctx, static)
+	iter.SetNodes(nodes)
+	iter.SetJob(job)
+	iter.SetTaskGroup(tg)
+
+	// Without allocations every node is feasible
+	must.Len(t, 4, collectFeasible(iter))
+
+	// An existing allocation in dc1/r1 pushes the next one to dc2
+	existing := alloc(nodes[0])
+	must.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 200, []*structs.Allocation{existing}))
+	iter.Reset()
+	must.Eq(t, []*structs.Node{nodes[2], nodes[3]}, collectFeasible(iter))
+
+	// With dc2/r1 proposed in the plan, both datacenters are balanced and
+	// only the empty rack of each one is feasible
+	ctx.Plan().NodeAllocation[nodes[2].ID] = []*structs.Allocation{alloc(nodes[2])}
+	iter.Reset()
+	must.Eq(t, []*structs.Node{nodes[1], nodes[3]}, collectFeasible(iter))
+
+	// Stopping the existing allocation in the plan frees dc1/r1 again
+	ctx.Plan().NodeUpdate[nodes[0].ID] = []*structs.Allocation{existing}
+	iter.Reset()
+	must.Eq(t, []*structs.Node{nodes[0], nodes[1]}, collectFeasible(iter))
+}
+
+func TestTopologySpreadIterator_MissingAttribute(t *testing.T) {
+	ci.Parallel(t)
+
+	state, ctx := testContext(t)
+	nodes := []*structs.Node{mock.Node(), mock.Node()}
+	nodes[0].Meta["rack"] = "r1"
+	for i, node := range nodes {
+		must.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, uint64(100+i), node))
+	}
+
+	tg := &structs.TaskGroup{
+		Name: "web",
+		TopologySpreads: []*structs.TopologySpread{
+			{Attribute: "${meta.rack}", MaxSkew: 1},
+		},
+	}
+	job := &structs.Job{
+		ID:         "foo",
+		Namespace:  structs.DefaultNamespace,
+		TaskGroups: []*structs.TaskGroup{tg},
+	}
+
+	static := NewStaticIterator(ctx, nodes)
+	iter := NewTopologySpreadIterator(ctx, static)
+	iter.SetNodes(nodes)
+	iter.SetJob(job)
+	iter.SetTaskGroup(tg)
+
+	must.Eq(t, []*structs.Node{nodes[0]}, collectFeasible(iter))
+	must.MapContainsKey(t, ctx.Metrics().ConstraintFiltered, `missing property "${meta.rack}"`)
+}
+
+func TestServiceSched_TopologySpread(t *testing.T) {
+	ci.Parallel(t)
+
+	h := NewHarness(t)
+
+	// Three racks, with all but two nodes in rack r1
+	for i := 0; i < 6; i++ {
+		node := mock.Node()
+		node.Meta["rack"] = "r1"
+		if i == 4 {
+			node.Meta["rack"] = "r2"
+		} else if i == 5 {
+			node.Meta["rack"] = "r3"
+		}
+		must.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), node))
+	}
+
+	job := mock.Job()
+	job.TaskGroups[0].Count = 5
+	job.TaskGroups[0].Constraints = append(job.TaskGroups[0].Constraints,
+		&structs.Constraint{Operand: structs.ConstraintDistinctHosts})
+	job.TaskGroups[0].TopologySpreads = []*structs.TopologySpread{
+		{Attribute: "${meta.rack}", MaxSkew: 1},
+	}
+	must.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), nil, job))
+
+	eval := &structs.Evaluation{
+		Namespace:   structs.DefaultNamespace,
+		ID:          uuid.Generate(),
+		Priority:    job.Priority,
+		TriggeredBy: structs.EvalTriggerJobRegister,
+		JobID:       job.ID,
+		Status:      structs.EvalStatusPending,
+	}
+	must.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+	must.NoError(t, h.Process(NewServiceScheduler, eval))
+
+	// Racks r2 and r3 can only take one allocation each because of the
+	// distinct_hosts constraint, so r1 can't take more than two without
+	// exceeding the skew and the fifth allocation can't be placed
+	must.Len(t, 1, h.Plans)
+	racks := make(map[string]int)
+	for nodeID, allocs := range h.Plans[0].NodeAllocation {
+		node, err := h.State.NodeByID(nil, nodeID)
+		must.NoError(t, err)
+		racks[node.Meta["rack"]] += len(allocs)
+	}
+	must.Eq(t, map[string]int{"r1": 2, "r2": 1, "r3": 1}, racks)
+
+	must.Len(t, 1, h.Evals)
+	metrics := h.Evals[0].FailedTGAllocs[job.TaskGroups[0].Name]
+	must.NotNil(t, metrics)
+	must.Positive(t, metrics.ConstraintFiltered["topology_spread: ${meta.rack}=r1 skew 2 exceeds 1"])
+}
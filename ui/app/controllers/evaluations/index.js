@@ -134,6 +134,9 @@ export default class EvaluationsController extends Controller {
       { key: 'queued-allocs', label: 'Queued Allocations' },
       { key: 'preemption', label: 'Preemption' },
       { key: 'job-scaling', label: 'Job Scalling' },
+      { key: 'job-dependency', label: 'Job Dependency' },
+      { key: 'deschedule', label: 'Deschedule' },
+      { key: 'index-progress', label: 'Index Progress' },
     ];
   }
 